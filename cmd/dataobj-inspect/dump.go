@@ -12,7 +12,10 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 
 	"github.com/grafana/loki/v3/pkg/dataobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/indexpointers"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/logs"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/ngrams"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/pointers"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
 )
 
@@ -20,11 +23,16 @@ import (
 type dumpCommand struct {
 	files      *[]string
 	printLines *bool
+	format     *string
 }
 
 func (cmd *dumpCommand) run(c *kingpin.ParseContext) error {
 	for _, f := range *cmd.files {
-		cmd.dumpFile(f)
+		if *cmd.format == formatText {
+			cmd.dumpFile(f)
+		} else {
+			cmd.dumpFileRows(f)
+		}
 	}
 	return nil
 }
@@ -120,9 +128,96 @@ func (cmd *dumpCommand) dumpLogsSection(ctx context.Context, offset int, sec *da
 	}
 }
 
+// dumpFileRows writes the rows of all sections in the data object in a
+// machine-readable format.
+func (cmd *dumpCommand) dumpFileRows(name string) {
+	dataObj, f := openObject(name)
+	defer func() { _ = f.Close() }()
+
+	ctx := context.TODO()
+	w := newRowWriter(*cmd.format, os.Stdout)
+	for offset, sec := range dataObj.Sections() {
+		w.Reset()
+		switch {
+		case streams.CheckSection(sec):
+			streamsSec, err := streams.Open(ctx, sec)
+			if err != nil {
+				exitWithError(fmt.Errorf("failed to open streams section: %w", err))
+			}
+			for res := range streams.IterSection(ctx, streamsSec) {
+				stream, err := res.Value()
+				if err != nil {
+					exitWithError(err)
+				}
+				writeRow(w, streamRow(offset, stream))
+			}
+		case logs.CheckSection(sec):
+			logsSec, err := logs.Open(ctx, sec)
+			if err != nil {
+				exitWithError(fmt.Errorf("failed to open logs section: %w", err))
+			}
+			for res := range logs.IterSection(ctx, logsSec) {
+				record, err := res.Value()
+				if err != nil {
+					exitWithError(err)
+				}
+				writeRow(w, logRow(offset, record))
+			}
+		case pointers.CheckSection(sec):
+			pointersSec, err := pointers.Open(ctx, sec)
+			if err != nil {
+				exitWithError(fmt.Errorf("failed to open pointers section: %w", err))
+			}
+			for res := range pointers.IterSection(ctx, pointersSec) {
+				pointer, err := res.Value()
+				if err != nil {
+					exitWithError(err)
+				}
+				writeRow(w, pointerRow(offset, pointer))
+			}
+		case indexpointers.CheckSection(sec):
+			indexPointersSec, err := indexpointers.Open(ctx, sec)
+			if err != nil {
+				exitWithError(fmt.Errorf("failed to open index pointers section: %w", err))
+			}
+			for res := range indexpointers.IterSection(ctx, indexPointersSec) {
+				pointer, err := res.Value()
+				if err != nil {
+					exitWithError(err)
+				}
+				writeRow(w, indexPointerRow(offset, pointer))
+			}
+		case ngrams.CheckSection(sec):
+			ngramsSec, err := ngrams.Open(ctx, sec)
+			if err != nil {
+				exitWithError(fmt.Errorf("failed to open ngrams section: %w", err))
+			}
+			for res := range ngrams.IterSection(ctx, ngramsSec) {
+				stream, err := res.Value()
+				if err != nil {
+					exitWithError(err)
+				}
+				writeRow(w, ngramsRow(offset, stream))
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "skipping unknown section %d: %s\n", offset, sec.Type)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		exitWithError(err)
+	}
+}
+
+func writeRow(w rowWriter, row []field) {
+	if err := w.WriteRow(row); err != nil {
+		exitWithError(fmt.Errorf("failed to write row: %w", err))
+	}
+}
+
 func addDumpCommand(app *kingpin.Application) {
 	cmd := &dumpCommand{}
 	dump := app.Command("dump", "Dump the contents of the data object.").Action(cmd.run)
 	cmd.printLines = dump.Flag("print-lines", "Prints the lines of each column.").Bool()
+	cmd.format = dump.Flag("format", "Output format. The jsonl and csv formats dump the rows of all sections; csv writes a header row before the rows of each section.").Default(formatText).Enum(formatText, formatJSONL, formatCSV)
 	cmd.files = dump.Arg("file", "The file to dump.").ExistingFiles()
 }
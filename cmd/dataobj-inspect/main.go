@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/alecthomas/kingpin/v2"
+
+	"github.com/grafana/loki/v3/pkg/dataobj"
 )
 
 func exitWithError(err error) {
@@ -12,11 +14,31 @@ func exitWithError(err error) {
 	os.Exit(1)
 }
 
+// openObject opens the data object in the file name. The returned file must be
+// closed once the data object is no longer used.
+func openObject(name string) (*dataobj.Object, *os.File) {
+	f, err := os.Open(name)
+	if err != nil {
+		exitWithError(fmt.Errorf("failed to open file: %w", err))
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		exitWithError(fmt.Errorf("failed to read fileinfo: %w", err))
+	}
+	dataObj, err := dataobj.FromReaderAt(f, fi.Size())
+	if err != nil {
+		exitWithError(fmt.Errorf("failed to read dataobj: %w", err))
+	}
+	return dataObj, f
+}
+
 func main() {
 	app := kingpin.New("dataobj-inspect", "A command-line tool to inspect data objects.")
 	addDumpCommand(app)
 	addStatsCommand(app)
 	addListStreamsCommand(app)
 	addPrintStreamsCommand(app)
+	addQueryCommand(app)
+	addVerifyCommand(app)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 }
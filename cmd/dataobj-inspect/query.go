@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/fatih/color"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/loki/v3/pkg/dataobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/logs"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
+)
+
+// queryCommand prints the log records in the data object which match a
+// stream selector.
+type queryCommand struct {
+	files        *[]string
+	selector     *string
+	start, end   *string
+	lineContains *[]string
+	lineRegexps  *[]string
+	limit        *int
+	format       *string
+}
+
+// queryFilter holds the parsed arguments of a queryCommand.
+type queryFilter struct {
+	matchers     []*labels.Matcher
+	start, end   time.Time
+	lineContains [][]byte
+	lineRegexps  []*regexp.Regexp
+}
+
+func (cmd *queryCommand) run(c *kingpin.ParseContext) error {
+	filter, err := cmd.parse()
+	if err != nil {
+		return err
+	}
+
+	var (
+		printed int
+		w       rowWriter
+	)
+	if *cmd.format != formatText {
+		w = newRowWriter(*cmd.format, os.Stdout)
+	}
+	for _, f := range *cmd.files {
+		printed += cmd.queryFile(f, filter, w, printed)
+		if *cmd.limit > 0 && printed >= *cmd.limit {
+			break
+		}
+	}
+	if w != nil {
+		if err := w.Flush(); err != nil {
+			exitWithError(err)
+		}
+	}
+	return nil
+}
+
+func (cmd *queryCommand) parse() (*queryFilter, error) {
+	matchers, err := parser.ParseMetricSelector(*cmd.selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector: %w", err)
+	}
+	filter := &queryFilter{matchers: matchers}
+
+	if *cmd.start != "" {
+		if filter.start, err = time.Parse(time.RFC3339Nano, *cmd.start); err != nil {
+			return nil, fmt.Errorf("invalid start time: %w", err)
+		}
+	}
+	if *cmd.end != "" {
+		if filter.end, err = time.Parse(time.RFC3339Nano, *cmd.end); err != nil {
+			return nil, fmt.Errorf("invalid end time: %w", err)
+		}
+	}
+	for _, s := range *cmd.lineContains {
+		filter.lineContains = append(filter.lineContains, []byte(s))
+	}
+	for _, s := range *cmd.lineRegexps {
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid line regexp: %w", err)
+		}
+		filter.lineRegexps = append(filter.lineRegexps, re)
+	}
+	return filter, nil
+}
+
+// queryFile prints the matching log records of the data object in the file
+// name, given that printed records have already been printed. queryFile
+// returns the number of printed records.
+func (cmd *queryCommand) queryFile(name string, filter *queryFilter, w rowWriter, printed int) int {
+	dataObj, f := openObject(name)
+	defer func() { _ = f.Close() }()
+
+	ctx := context.TODO()
+	matching := matchingStreams(ctx, dataObj, filter.matchers)
+	if len(matching) == 0 {
+		return 0
+	}
+
+	var n int
+	bold := color.New(color.Bold)
+	for res := range logs.Iter(ctx, dataObj) {
+		record, err := res.Value()
+		if err != nil {
+			exitWithError(err)
+		}
+		lbls, ok := matching[record.StreamID]
+		if !ok || !filter.matches(record) {
+			continue
+		}
+
+		if w != nil {
+			writeRow(w, []field{
+				{"timestamp", record.Timestamp},
+				{"labels", lbls},
+				{"metadata", record.Metadata},
+				{"line", string(record.Line)},
+			})
+		} else {
+			bold.Printf("%s %s", record.Timestamp.UTC().Format(time.RFC3339Nano), lbls)
+			if !record.Metadata.IsEmpty() {
+				fmt.Printf(" %s", record.Metadata)
+			}
+			fmt.Printf(" %s\n", record.Line)
+		}
+
+		n++
+		if *cmd.limit > 0 && printed+n >= *cmd.limit {
+			break
+		}
+	}
+	return n
+}
+
+// matchingStreams returns the labels of the streams in dataObj which match
+// all matchers, by stream ID.
+func matchingStreams(ctx context.Context, dataObj *dataobj.Object, matchers []*labels.Matcher) map[int64]labels.Labels {
+	result := make(map[int64]labels.Labels)
+outer:
+	for res := range streams.Iter(ctx, dataObj) {
+		stream, err := res.Value()
+		if err != nil {
+			exitWithError(err)
+		}
+		for _, m := range matchers {
+			if !m.Matches(stream.Labels.Get(m.Name)) {
+				continue outer
+			}
+		}
+		result[stream.ID] = stream.Labels
+	}
+	return result
+}
+
+func (f *queryFilter) matches(record logs.Record) bool {
+	if !f.start.IsZero() && record.Timestamp.Before(f.start) {
+		return false
+	}
+	if !f.end.IsZero() && !record.Timestamp.Before(f.end) {
+		return false
+	}
+	for _, s := range f.lineContains {
+		if !bytes.Contains(record.Line, s) {
+			return false
+		}
+	}
+	for _, re := range f.lineRegexps {
+		if !re.Match(record.Line) {
+			return false
+		}
+	}
+	return true
+}
+
+func addQueryCommand(app *kingpin.Application) {
+	cmd := &queryCommand{}
+	query := app.Command("query", "Prints the log records in the data object matching a LogQL stream selector, in the order they are stored.").Action(cmd.run)
+	cmd.start = query.Flag("start", "Only print records at or after this time (RFC3339).").String()
+	cmd.end = query.Flag("end", "Only print records before this time (RFC3339).").String()
+	cmd.lineContains = query.Flag("line-contains", "Only print records whose line contains this string, like a |= line filter. May be repeated.").Strings()
+	cmd.lineRegexps = query.Flag("line-regexp", "Only print records whose line matches this regular expression, like a |~ line filter. May be repeated.").Strings()
+	cmd.limit = query.Flag("limit", "Maximum number of records to print. 0 prints all records.").Default("0").Int()
+	cmd.format = query.Flag("format", "Output format.").Default(formatText).Enum(formatText, formatJSONL, formatCSV)
+	cmd.selector = query.Arg("selector", `The LogQL stream selector, such as {app="foo"}.`).Required().String()
+	cmd.files = query.Arg("file", "The files to query.").ExistingFiles()
+}
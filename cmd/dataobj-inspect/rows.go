@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/indexpointers"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/logs"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/ngrams"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/pointers"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
+)
+
+// Output formats for rows.
+const (
+	formatText  = "text"
+	formatJSONL = "jsonl"
+	formatCSV   = "csv"
+)
+
+// field is a named value of a row. Values are strings, int64s, time.Times,
+// labels.Labels or byte slices.
+type field struct {
+	name  string
+	value any
+}
+
+// rowWriter writes rows in one of the machine-readable output formats.
+type rowWriter interface {
+	// WriteRow writes a single row. All rows passed to WriteRow between calls
+	// to Reset must have the same fields.
+	WriteRow(row []field) error
+
+	// Reset signals that the following rows may have different fields.
+	Reset()
+
+	// Flush flushes buffered rows to the underlying writer.
+	Flush() error
+}
+
+func newRowWriter(format string, w io.Writer) rowWriter {
+	switch format {
+	case formatCSV:
+		return &csvRowWriter{w: csv.NewWriter(w)}
+	default:
+		return &jsonRowWriter{w: w}
+	}
+}
+
+// jsonRowWriter writes each row as a JSON object on its own line. Fields are
+// written in the order of the row.
+type jsonRowWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+func (w *jsonRowWriter) WriteRow(row []field) error {
+	w.buf.Reset()
+	w.buf.WriteByte('{')
+	for i, f := range row {
+		if i > 0 {
+			w.buf.WriteByte(',')
+		}
+		name, err := json.Marshal(f.name)
+		if err != nil {
+			return err
+		}
+		value, err := json.Marshal(jsonValue(f.value))
+		if err != nil {
+			return fmt.Errorf("encoding field %s: %w", f.name, err)
+		}
+		w.buf.Write(name)
+		w.buf.WriteByte(':')
+		w.buf.Write(value)
+	}
+	w.buf.WriteString("}\n")
+	_, err := w.w.Write(w.buf.Bytes())
+	return err
+}
+
+func (w *jsonRowWriter) Reset() {}
+
+func (w *jsonRowWriter) Flush() error { return nil }
+
+func jsonValue(v any) any {
+	switch v := v.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case labels.Labels:
+		return v.Map()
+	}
+	// Byte slices are encoded as base64 by encoding/json.
+	return v
+}
+
+// csvRowWriter writes rows as CSV. A header row with the field names is
+// written before the first row and after each call to Reset.
+type csvRowWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+	record      []string
+}
+
+func (w *csvRowWriter) WriteRow(row []field) error {
+	if !w.wroteHeader {
+		w.record = w.record[:0]
+		for _, f := range row {
+			w.record = append(w.record, f.name)
+		}
+		if err := w.w.Write(w.record); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+	}
+
+	w.record = w.record[:0]
+	for _, f := range row {
+		w.record = append(w.record, csvValue(f.value))
+	}
+	return w.w.Write(w.record)
+}
+
+func (w *csvRowWriter) Reset() { w.wroteHeader = false }
+
+func (w *csvRowWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func csvValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case labels.Labels:
+		return v.String()
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	}
+	return fmt.Sprint(v)
+}
+
+func streamRow(section int, s streams.Stream) []field {
+	return []field{
+		{"section", int64(section)},
+		{"id", s.ID},
+		{"labels", s.Labels},
+		{"min_timestamp", s.MinTimestamp},
+		{"max_timestamp", s.MaxTimestamp},
+		{"uncompressed_size", s.UncompressedSize},
+		{"rows", int64(s.Rows)},
+	}
+}
+
+func logRow(section int, r logs.Record) []field {
+	return []field{
+		{"section", int64(section)},
+		{"stream_id", r.StreamID},
+		{"timestamp", r.Timestamp},
+		{"metadata", r.Metadata},
+		{"line", string(r.Line)},
+	}
+}
+
+func pointerRow(section int, p pointers.SectionPointer) []field {
+	return []field{
+		{"section", int64(section)},
+		{"path", p.Path},
+		{"pointer_section", p.Section},
+		{"kind", pointerKindName(p.PointerKind)},
+		{"stream_id", p.StreamID},
+		{"stream_id_ref", p.StreamIDRef},
+		{"start_timestamp", p.StartTs},
+		{"end_timestamp", p.EndTs},
+		{"line_count", p.LineCount},
+		{"uncompressed_size", p.UncompressedSize},
+		{"column_index", p.ColumnIndex},
+		{"column_name", p.ColumnName},
+		{"values_bloom_filter", p.ValuesBloomFilter},
+	}
+}
+
+func pointerKindName(kind pointers.PointerKind) string {
+	switch kind {
+	case pointers.PointerKindStreamIndex:
+		return "stream_index"
+	case pointers.PointerKindColumnIndex:
+		return "column_index"
+	}
+	return "invalid"
+}
+
+func indexPointerRow(section int, p indexpointers.IndexPointer) []field {
+	return []field{
+		{"section", int64(section)},
+		{"path", p.Path},
+		{"start_timestamp", p.StartTs},
+		{"end_timestamp", p.EndTs},
+	}
+}
+
+func ngramsRow(section int, s ngrams.StreamNgrams) []field {
+	return []field{
+		{"section", int64(section)},
+		{"path", s.Path},
+		{"logs_section", s.Section},
+		{"stream_id", s.StreamID},
+		{"ngram_bloom_filter", s.NgramBloomFilter},
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/fatih/color"
+
+	"github.com/grafana/loki/v3/pkg/dataobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/indexpointers"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/logs"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/ngrams"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/pointers"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
+)
+
+// verifyCommand decodes every page of the data object and reports pages
+// which are corrupt or don't match their metadata.
+type verifyCommand struct {
+	files *[]string
+}
+
+func (cmd *verifyCommand) run(c *kingpin.ParseContext) error {
+	var corrupt bool
+	for _, f := range *cmd.files {
+		if !cmd.verifyFile(f) {
+			corrupt = true
+		}
+	}
+	if corrupt {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// verifyFile verifies all sections of the data object in the file name and
+// returns whether all of them are valid.
+func (cmd *verifyCommand) verifyFile(name string) bool {
+	dataObj, f := openObject(name)
+	defer func() { _ = f.Close() }()
+
+	var (
+		ctx   = context.TODO()
+		valid = true
+
+		bold = color.New(color.Bold)
+		red  = color.New(color.FgRed)
+	)
+	bold.Println(name)
+	for offset, sec := range dataObj.Sections() {
+		err := verifySection(ctx, sec)
+		switch {
+		case errors.Is(err, errUnknownSection):
+			fmt.Printf("\tsection %d (%s): skipped, unknown section type\n", offset, sec.Type)
+		case err != nil:
+			valid = false
+			red.Printf("\tsection %d (%s): corrupt\n", offset, sec.Type)
+			fmt.Printf("\t\t%s\n", strings.ReplaceAll(err.Error(), "\n", "\n\t\t"))
+		default:
+			fmt.Printf("\tsection %d (%s): ok\n", offset, sec.Type)
+		}
+	}
+	return valid
+}
+
+var errUnknownSection = errors.New("unknown section")
+
+func verifySection(ctx context.Context, sec *dataobj.Section) error {
+	switch {
+	case streams.CheckSection(sec):
+		streamsSec, err := streams.Open(ctx, sec)
+		if err != nil {
+			return fmt.Errorf("opening streams section: %w", err)
+		}
+		return streams.Verify(ctx, streamsSec)
+	case logs.CheckSection(sec):
+		logsSec, err := logs.Open(ctx, sec)
+		if err != nil {
+			return fmt.Errorf("opening logs section: %w", err)
+		}
+		return logs.Verify(ctx, logsSec)
+	case pointers.CheckSection(sec):
+		pointersSec, err := pointers.Open(ctx, sec)
+		if err != nil {
+			return fmt.Errorf("opening pointers section: %w", err)
+		}
+		return pointers.Verify(ctx, pointersSec)
+	case indexpointers.CheckSection(sec):
+		indexPointersSec, err := indexpointers.Open(ctx, sec)
+		if err != nil {
+			return fmt.Errorf("opening index pointers section: %w", err)
+		}
+		return indexpointers.Verify(ctx, indexPointersSec)
+	case ngrams.CheckSection(sec):
+		ngramsSec, err := ngrams.Open(ctx, sec)
+		if err != nil {
+			return fmt.Errorf("opening ngrams section: %w", err)
+		}
+		return ngrams.Verify(ctx, ngramsSec)
+	}
+	return errUnknownSection
+}
+
+func addVerifyCommand(app *kingpin.Application) {
+	cmd := &verifyCommand{}
+	verify := app.Command("verify", "Decodes every page of the data object and checks checksums, row counts and statistics. Exits with a non-zero status if any section is corrupt.").Action(cmd.run)
+	cmd.files = verify.Arg("file", "The files to verify.").ExistingFiles()
+}
@@ -512,6 +512,12 @@ func (c *Compactor) TablesManager() TablesManager {
 	return c.tablesManager
 }
 
+// DeleteRequestsStore returns the store of delete requests, or nil if
+// retention is disabled.
+func (c *Compactor) DeleteRequestsStore() deletion.DeleteRequestsStore {
+	return c.deleteRequestsStore
+}
+
 type expirationChecker struct {
 	retentionExpiryChecker retention.ExpirationChecker
 	deletionExpiryChecker  retention.ExpirationChecker
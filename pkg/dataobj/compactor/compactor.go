@@ -0,0 +1,472 @@
+// Package compactor merges small data objects into larger ones.
+//
+// The dataobj consumer flushes data objects when they reach their target size
+// or when a partition has been idle for a while. Low-volume tenants and quiet
+// partitions therefore produce many small objects, and every query has to
+// open each of them. The compactor periodically looks for small objects in
+// the same metastore window and rewrites them into a single object. Logs are
+// merged and sorted by the logs section builder, just like when they are
+// consumed.
+//
+// If retention is enabled, the compactor also removes logs which are past
+// their retention period or requested for deletion, by rewriting the
+// affected objects or dropping them entirely.
+//
+// Compactors hold a lock in the bucket while compacting, so that only one of
+// them rewrites objects at a time.
+package compactor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/thanos-io/objstore"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/grafana/loki/v3/pkg/dataobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/consumer/logsobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/index"
+	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/logs"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
+	"github.com/grafana/loki/v3/pkg/dataobj/uploader"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/util/filter"
+)
+
+// maxEntriesPerAppend limits the number of entries of a stream appended to
+// the builder at once.
+const maxEntriesPerAppend = 1024
+
+type Compactor struct {
+	services.Service
+
+	cfg      Config
+	mCfg     metastore.Config
+	indexCfg index.Config
+
+	bucket         objstore.Bucket
+	events         *kgo.Client
+	lock           *lock
+	metastore      *metastore.ObjectMetastore
+	builder        *logsobj.Builder
+	buf            *bytes.Buffer
+	limits         Limits
+	deleteRequests DeleteRequestsStore
+
+	metrics *compactorMetrics
+	logger  log.Logger
+}
+
+// New creates a new Compactor which compacts the data objects in bucket.
+// Index objects referencing compacted data objects are rewritten using
+// indexCfg. Compacted data objects which aren't indexed yet are announced to
+// the index builder with events, which may be nil if there is no index
+// builder. If retention is enabled, limits provide the retention periods and
+// deletion modes of tenants and deleteRequests their delete requests; either
+// may be nil.
+func New(cfg Config, mCfg metastore.Config, indexCfg index.Config, bucket objstore.Bucket, events *kgo.Client, limits Limits, deleteRequests DeleteRequestsStore, reg prometheus.Registerer, logger log.Logger) (*Compactor, error) {
+	builder, err := logsobj.NewBuilder(cfg.BuilderConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create builder: %w", err)
+	}
+
+	metrics := newCompactorMetrics()
+	if err := metrics.register(reg); err != nil {
+		return nil, fmt.Errorf("failed to register metrics for compactor: %w", err)
+	}
+
+	logger = log.With(logger, "component", "dataobj-compactor")
+	c := &Compactor{
+		cfg:      cfg,
+		mCfg:     mCfg,
+		indexCfg: indexCfg,
+
+		bucket:         bucket,
+		events:         events,
+		lock:           newLock(bucket, cfg.LockLeaseDuration),
+		metastore:      metastore.NewObjectMetastore(mCfg, bucket, logger, nil),
+		builder:        builder,
+		buf:            bytes.NewBuffer(make([]byte, 0, cfg.TargetObjectSize)),
+		limits:         limits,
+		deleteRequests: deleteRequests,
+
+		metrics: metrics,
+		logger:  logger,
+	}
+	c.Service = services.NewTimerService(cfg.CompactionInterval, nil, c.iteration, c.stopping)
+	return c, nil
+}
+
+func (c *Compactor) stopping(_ error) error {
+	if err := c.lock.release(context.Background()); err != nil {
+		level.Warn(c.logger).Log("msg", "failed to release compactor lock", "err", err)
+	}
+	if c.events != nil {
+		c.events.Close()
+	}
+	return nil
+}
+
+func (c *Compactor) iteration(ctx context.Context) error {
+	if err := c.Compact(ctx); err != nil {
+		// Failures are retried on the next iteration, so we don't want to stop
+		// the service.
+		level.Error(c.logger).Log("msg", "failed to compact data objects", "err", err)
+	}
+	return nil
+}
+
+// Compact deletes previously compacted objects whose delete delay has passed,
+// completes replacements which failed in a previous iteration, applies
+// retention if enabled, and then compacts the small objects of every tenant.
+// Compact does nothing if another compactor holds the lock.
+func (c *Compactor) Compact(ctx context.Context) error {
+	if err := c.lock.acquire(ctx); errors.Is(err, errLocked) {
+		level.Info(c.logger).Log("msg", "skipping compaction, another compactor holds the lock")
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("acquiring lock: %w", err)
+	}
+
+	tenants, err := metastore.Tenants(ctx, c.bucket)
+	if err != nil {
+		return fmt.Errorf("listing tenants: %w", err)
+	}
+
+	var errs []error
+	for _, tenantID := range tenants {
+		if err := c.deleteMarkedObjects(ctx, tenantID); err != nil {
+			errs = append(errs, fmt.Errorf("deleting compacted objects of tenant %s: %w", tenantID, err))
+		}
+
+		// Objects of pending replacements are still listed in the
+		// metastore, so they must not be compacted again.
+		if err := c.completePendingReplacements(ctx, tenantID); err != nil {
+			errs = append(errs, fmt.Errorf("completing replacements of tenant %s: %w", tenantID, err))
+			continue
+		}
+
+		windows, err := metastore.Windows(ctx, c.bucket, tenantID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("listing metastore windows of tenant %s: %w", tenantID, err))
+			continue
+		}
+
+		if c.cfg.RetentionEnabled {
+			if err := c.applyRetention(ctx, tenantID, windows); err != nil {
+				errs = append(errs, fmt.Errorf("applying retention to tenant %s: %w", tenantID, err))
+			}
+		}
+
+		for _, window := range windows {
+			if err := c.compactWindow(ctx, tenantID, window); err != nil {
+				errs = append(errs, fmt.Errorf("compacting window %s of tenant %s: %w", window.Format(time.RFC3339), tenantID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// compactWindow compacts the small objects of a single metastore window.
+func (c *Compactor) compactWindow(ctx context.Context, tenantID string, window time.Time) error {
+	ctx = user.InjectOrgID(ctx, tenantID)
+
+	objects, err := c.metastore.WindowObjects(ctx, window)
+	if err != nil {
+		return err
+	}
+	candidates, err := c.smallObjects(ctx, window, objects)
+	if err != nil {
+		return err
+	}
+
+	for _, batch := range c.batches(candidates) {
+		if err := c.compactBatch(ctx, tenantID, window, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type candidate struct {
+	metastore.ObjectInfo
+	size int64
+}
+
+// smallObjects returns the objects smaller than the configured threshold
+// which are entirely within window. Objects spanning several windows are
+// never compacted, so that replacing them only updates a single metastore
+// object.
+func (c *Compactor) smallObjects(ctx context.Context, window time.Time, objects []metastore.ObjectInfo) ([]candidate, error) {
+	var candidates []candidate
+	for _, obj := range objects {
+		if !obj.MinTimestamp.Truncate(metastore.WindowSize).Equal(window) || !obj.MaxTimestamp.Truncate(metastore.WindowSize).Equal(window) {
+			continue
+		}
+
+		attrs, err := c.bucket.Attributes(ctx, obj.Path)
+		if c.bucket.IsObjNotFoundErr(err) {
+			level.Warn(c.logger).Log("msg", "object listed in metastore not found", "path", obj.Path)
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("getting attributes of object %s: %w", obj.Path, err)
+		}
+		if attrs.Size >= int64(c.cfg.SmallObjectSize) {
+			continue
+		}
+		candidates = append(candidates, candidate{ObjectInfo: obj, size: attrs.Size})
+	}
+	return candidates, nil
+}
+
+// batches groups candidates into batches of at least MinObjects objects whose
+// total size doesn't exceed the target object size.
+func (c *Compactor) batches(candidates []candidate) [][]metastore.ObjectInfo {
+	// Sorting by time keeps the time ranges of compacted objects narrow.
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return a.MinTimestamp.Compare(b.MinTimestamp)
+	})
+
+	var (
+		batches [][]metastore.ObjectInfo
+		batch   []metastore.ObjectInfo
+		size    int64
+	)
+	for _, obj := range candidates {
+		if size+obj.size > int64(c.cfg.TargetObjectSize) {
+			if len(batch) >= c.cfg.MinObjects {
+				batches = append(batches, batch)
+			}
+			batch, size = nil, 0
+		}
+		batch = append(batch, obj.ObjectInfo)
+		size += obj.size
+	}
+	if len(batch) >= c.cfg.MinObjects {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// compactBatch merges the objects of batch into a single object, and replaces
+// them with the new object in the metastore and in index objects. The objects
+// of batch are deleted once the delete delay has passed.
+//
+// The lock is renewed before rewriting, and compactBatch fails if another
+// compactor took it over.
+func (c *Compactor) compactBatch(ctx context.Context, tenantID string, window time.Time, batch []metastore.ObjectInfo) (err error) {
+	timer := prometheus.NewTimer(c.metrics.compactionSeconds)
+	defer func() {
+		timer.ObserveDuration()
+		if err != nil {
+			c.metrics.incCompactions(statusFailure)
+			return
+		}
+		c.metrics.incCompactions(statusSuccess)
+	}()
+
+	if err := c.lock.acquire(ctx); err != nil {
+		return fmt.Errorf("renewing lock: %w", err)
+	}
+
+	c.builder.Reset()
+	defer c.builder.Reset()
+
+	n, err := c.appendObjects(ctx, batch)
+	if errors.Is(err, logsobj.ErrBuilderFull) && n >= c.cfg.MinObjects {
+		// The objects are larger than estimated once decoded. Compact the
+		// objects which fit, the others are compacted on the next iteration.
+		batch = batch[:n]
+		c.builder.Reset()
+		_, err = c.appendObjects(ctx, batch)
+	} else if errors.Is(err, logsobj.ErrBuilderFull) {
+		level.Warn(c.logger).Log("msg", "not enough objects fit into a compacted object, skipping", "tenant", tenantID, "objects", len(batch))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	c.buf.Reset()
+	if _, err := c.builder.Flush(c.buf); err != nil {
+		return fmt.Errorf("flushing compacted object: %w", err)
+	}
+
+	objectPath, err := uploader.New(c.cfg.UploaderConfig, c.bucket, tenantID, c.logger).Upload(ctx, c.buf)
+	if err != nil {
+		return err
+	}
+
+	var (
+		oldPaths     = make([]string, 0, len(batch))
+		minTs, maxTs = batch[0].MinTimestamp, batch[0].MaxTimestamp
+	)
+	for _, obj := range batch {
+		oldPaths = append(oldPaths, obj.Path)
+		if obj.MinTimestamp.Before(minTs) {
+			minTs = obj.MinTimestamp
+		}
+		if obj.MaxTimestamp.After(maxTs) {
+			maxTs = obj.MaxTimestamp
+		}
+	}
+
+	if err := c.replace(ctx, tenantID, window, oldPaths, objectPath, minTs, maxTs); err != nil {
+		return err
+	}
+
+	c.metrics.compactedObjects.Add(float64(len(batch)))
+	level.Info(c.logger).Log("msg", "compacted data objects", "tenant", tenantID, "window", window.Format(time.RFC3339), "objects", len(batch), "path", objectPath, "size", c.buf.Len())
+	return nil
+}
+
+// appendObjects appends the logs of objects to the builder. It returns the
+// number of objects which were appended entirely.
+func (c *Compactor) appendObjects(ctx context.Context, objects []metastore.ObjectInfo) (int, error) {
+	for i, info := range objects {
+		obj, err := dataobj.FromBucket(ctx, c.bucket, info.Path)
+		if err != nil {
+			return i, fmt.Errorf("opening object %s: %w", info.Path, err)
+		}
+		if _, err := c.appendObject(ctx, obj, nil); err != nil {
+			return i, fmt.Errorf("appending object %s: %w", info.Path, err)
+		}
+	}
+	return len(objects), nil
+}
+
+// appendObject appends the logs of obj to the builder. Logs for which the
+// filter of their stream ID in filters returns true are skipped. It returns
+// the number of skipped logs.
+func (c *Compactor) appendObject(ctx context.Context, obj *dataobj.Object, filters map[int64]filter.Func) (int, error) {
+	streamLabels := make(map[int64]string)
+	for res := range streams.Iter(ctx, obj) {
+		stream, err := res.Value()
+		if err != nil {
+			return 0, fmt.Errorf("reading streams: %w", err)
+		}
+		streamLabels[stream.ID] = stream.Labels.String()
+	}
+
+	var (
+		stream   logproto.Stream
+		streamID int64
+		skipped  int
+	)
+	flush := func() error {
+		if len(stream.Entries) == 0 {
+			return nil
+		}
+		err := c.builder.Append(stream)
+		stream.Entries = stream.Entries[:0]
+		return err
+	}
+
+	for res := range logs.Iter(ctx, obj) {
+		record, err := res.Value()
+		if err != nil {
+			return skipped, fmt.Errorf("reading logs: %w", err)
+		}
+
+		line := string(record.Line)
+		if f, ok := filters[record.StreamID]; ok && f(record.Timestamp, line, record.Metadata) {
+			skipped++
+			continue
+		}
+
+		if stream.Labels == "" || record.StreamID != streamID || len(stream.Entries) >= maxEntriesPerAppend {
+			if err := flush(); err != nil {
+				return skipped, err
+			}
+
+			lbls, ok := streamLabels[record.StreamID]
+			if !ok {
+				return skipped, fmt.Errorf("unknown stream ID %d", record.StreamID)
+			}
+			stream.Labels, streamID = lbls, record.StreamID
+		}
+
+		stream.Entries = append(stream.Entries, logproto.Entry{
+			Timestamp:          record.Timestamp,
+			Line:               line,
+			StructuredMetadata: logproto.FromLabelsToLabelAdapters(record.Metadata),
+		})
+	}
+	return skipped, flush()
+}
+
+// markForDeletion records paths to be deleted once the delete delay has
+// passed. Deletion is delayed so that queries which already resolved the
+// paths from the metastore can still read them.
+func (c *Compactor) markForDeletion(ctx context.Context, tenantID string, paths []string) error {
+	markPath := deletionMarksDir(tenantID) + strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := c.bucket.Upload(ctx, markPath, strings.NewReader(strings.Join(paths, "\n"))); err != nil {
+		return fmt.Errorf("uploading deletion mark: %w", err)
+	}
+	return nil
+}
+
+// deleteMarkedObjects deletes the objects of deletion marks which are older
+// than the delete delay.
+func (c *Compactor) deleteMarkedObjects(ctx context.Context, tenantID string) error {
+	dir := deletionMarksDir(tenantID)
+
+	var marks []string
+	err := c.bucket.Iter(ctx, dir, func(name string) error {
+		ts, err := strconv.ParseInt(strings.TrimPrefix(name, dir), 10, 64)
+		if err != nil {
+			return nil
+		}
+		if time.Since(time.Unix(0, ts)) >= c.cfg.DeleteDelay {
+			marks = append(marks, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, mark := range marks {
+		var buf bytes.Buffer
+		rc, err := c.bucket.Get(ctx, mark)
+		if err != nil {
+			return fmt.Errorf("reading deletion mark %s: %w", mark, err)
+		}
+		_, err = buf.ReadFrom(rc)
+		_ = rc.Close()
+		if err != nil {
+			return fmt.Errorf("reading deletion mark %s: %w", mark, err)
+		}
+
+		for _, path := range strings.Split(buf.String(), "\n") {
+			if path == "" {
+				continue
+			}
+			if err := c.bucket.Delete(ctx, path); err != nil && !c.bucket.IsObjNotFoundErr(err) {
+				return fmt.Errorf("deleting object %s: %w", path, err)
+			}
+			c.metrics.deletedObjects.Inc()
+		}
+
+		if err := c.bucket.Delete(ctx, mark); err != nil {
+			return fmt.Errorf("deleting deletion mark %s: %w", mark, err)
+		}
+	}
+	return nil
+}
+
+func deletionMarksDir(tenantID string) string {
+	return fmt.Sprintf("tenant-%s/compactor/deletion-marks/", tenantID)
+}
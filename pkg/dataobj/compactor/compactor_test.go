@@ -0,0 +1,440 @@
+package compactor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/grafana/loki/v3/pkg/dataobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/consumer/logsobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/index"
+	"github.com/grafana/loki/v3/pkg/dataobj/index/indexobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/logs"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/ngrams"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/pointers"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
+	"github.com/grafana/loki/v3/pkg/dataobj/uploader"
+	"github.com/grafana/loki/v3/pkg/kafka/testkafka"
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+const tenantID = "test-tenant"
+
+var (
+	builderCfg = logsobj.BuilderConfig{
+		TargetPageSize:          2048,
+		TargetObjectSize:        1 << 22, // 4 MiB
+		TargetSectionSize:       1 << 21, // 2 MiB
+		BufferSize:              2048 * 8,
+		SectionStripeMergeLimit: 2,
+	}
+
+	indexCfg = index.Config{
+		BuilderConfig: indexobj.BuilderConfig{
+			TargetPageSize:          2048,
+			TargetObjectSize:        1 << 22, // 4 MiB
+			TargetSectionSize:       1 << 21, // 2 MiB
+			BufferSize:              2048 * 8,
+			SectionStripeMergeLimit: 2,
+		},
+		IndexStoragePrefix: "index/v0/",
+	}
+)
+
+func TestCompactor(t *testing.T) {
+	var (
+		ctx    = user.InjectOrgID(context.Background(), tenantID)
+		bucket = objstore.NewInMemBucket()
+		window = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	// Each object holds a single log line of both streams, so compacting them
+	// must interleave their logs.
+	var oldPaths []string
+	for i := range 4 {
+		ts := window.Add(time.Duration(i) * time.Minute)
+		oldPaths = append(oldPaths, writeObject(t, bucket,
+			logproto.Stream{Labels: `{app="foo"}`, Entries: []logproto.Entry{{Timestamp: ts, Line: fmt.Sprintf("foo %d", i)}}},
+			logproto.Stream{Labels: `{app="bar"}`, Entries: []logproto.Entry{{Timestamp: ts, Line: fmt.Sprintf("bar %d", i)}}},
+		))
+	}
+	// Objects spanning several windows are never compacted.
+	spanning := writeObject(t, bucket,
+		logproto.Stream{Labels: `{app="foo"}`, Entries: []logproto.Entry{{Timestamp: window.Add(11 * time.Hour)}, {Timestamp: window.Add(13 * time.Hour)}}},
+	)
+	indexPath := writeIndex(t, bucket, append(oldPaths, spanning))
+
+	c, err := New(Config{
+		BuilderConfig:      builderCfg,
+		UploaderConfig:     uploader.Config{SHAPrefixSize: 2},
+		CompactionInterval: time.Minute,
+		SmallObjectSize:    1 << 20,
+		MinObjects:         2,
+		DeleteDelay:        0,
+		LockLeaseDuration:  time.Minute,
+	}, metastore.Config{}, indexCfg, bucket, nil, nil, nil, prometheus.NewRegistry(), log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, c.Compact(context.Background()))
+
+	// The metastore must only list the compacted object and the object
+	// spanning several windows.
+	objects, err := metastore.NewObjectMetastore(metastore.Config{}, bucket, log.NewNopLogger(), nil).WindowObjects(ctx, window)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+
+	var newPath string
+	for _, obj := range objects {
+		if obj.Path == spanning {
+			continue
+		}
+		newPath = obj.Path
+		require.Equal(t, window, obj.MinTimestamp)
+		require.Equal(t, window.Add(3*time.Minute), obj.MaxTimestamp)
+	}
+	require.NotEmpty(t, newPath)
+	// Logs sections are sorted by timestamp (descending) and then stream ID.
+	require.Equal(t, []string{
+		`{app="foo"} foo 3`, `{app="bar"} bar 3`,
+		`{app="foo"} foo 2`, `{app="bar"} bar 2`,
+		`{app="foo"} foo 1`, `{app="bar"} bar 1`,
+		`{app="foo"} foo 0`, `{app="bar"} bar 0`,
+	}, readLines(t, bucket, newPath))
+
+	// The index object referencing the old objects must have been replaced.
+	indexBucket := objstore.NewPrefixedBucket(bucket, indexCfg.IndexStoragePrefix)
+	indexes, err := metastore.NewObjectMetastore(metastore.Config{}, indexBucket, log.NewNopLogger(), nil).WindowObjects(ctx, window)
+	require.NoError(t, err)
+	require.Len(t, indexes, 1)
+	require.NotEqual(t, indexPath, indexes[0].Path)
+
+	// The pointers of the spanning object are copied and the compacted object
+	// is indexed, which must be equivalent to indexing both from scratch.
+	newIndex, err := dataobj.FromBucket(ctx, indexBucket, indexes[0].Path)
+	require.NoError(t, err)
+	require.ElementsMatch(t, readIndex(t, buildIndex(t, bucket, []string{spanning, newPath})), readIndex(t, newIndex))
+
+	// Old objects are only deleted on the next compaction.
+	for _, path := range oldPaths {
+		exists, err := bucket.Exists(ctx, path)
+		require.NoError(t, err)
+		require.True(t, exists)
+	}
+
+	require.NoError(t, c.Compact(context.Background()))
+	for _, path := range append(oldPaths, indexCfg.IndexStoragePrefix+indexPath) {
+		exists, err := bucket.Exists(ctx, path)
+		require.NoError(t, err)
+		require.False(t, exists, "%s should have been deleted", path)
+	}
+	for _, path := range []string{newPath, spanning, indexCfg.IndexStoragePrefix + indexes[0].Path} {
+		exists, err := bucket.Exists(ctx, path)
+		require.NoError(t, err)
+		require.True(t, exists, "%s should not have been deleted", path)
+	}
+}
+
+func TestCompactor_PendingReplacement(t *testing.T) {
+	var (
+		ctx    = user.InjectOrgID(context.Background(), tenantID)
+		bucket = &failOnceBucket{Bucket: objstore.NewInMemBucket(), prefix: deletionMarksDir(tenantID)}
+		window = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	var oldPaths []string
+	for i := range 2 {
+		ts := window.Add(time.Duration(i) * time.Minute)
+		oldPaths = append(oldPaths, writeObject(t, bucket,
+			logproto.Stream{Labels: `{app="foo"}`, Entries: []logproto.Entry{{Timestamp: ts, Line: fmt.Sprintf("foo %d", i)}}},
+		))
+	}
+	writeIndex(t, bucket, oldPaths)
+
+	c, err := New(Config{
+		BuilderConfig:      builderCfg,
+		UploaderConfig:     uploader.Config{SHAPrefixSize: 2},
+		CompactionInterval: time.Minute,
+		SmallObjectSize:    1 << 20,
+		MinObjects:         2,
+		DeleteDelay:        time.Hour,
+		LockLeaseDuration:  time.Minute,
+	}, metastore.Config{}, indexCfg, bucket, nil, nil, nil, prometheus.NewRegistry(), log.NewNopLogger())
+	require.NoError(t, err)
+
+	// Marking the old objects for deletion fails, so the replacement is
+	// completed by the next compaction instead of compacting the old objects
+	// again.
+	require.Error(t, c.Compact(context.Background()))
+	require.True(t, bucket.failed)
+	require.NoError(t, c.Compact(context.Background()))
+
+	objects, err := metastore.NewObjectMetastore(metastore.Config{}, bucket, log.NewNopLogger(), nil).WindowObjects(ctx, window)
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	require.NotContains(t, oldPaths, objects[0].Path)
+
+	indexBucket := objstore.NewPrefixedBucket(bucket, indexCfg.IndexStoragePrefix)
+	indexes, err := metastore.NewObjectMetastore(metastore.Config{}, indexBucket, log.NewNopLogger(), nil).WindowObjects(ctx, window)
+	require.NoError(t, err)
+	require.Len(t, indexes, 1)
+
+	var records, marks int
+	require.NoError(t, bucket.Iter(ctx, replacementsDir(tenantID), func(string) error { records++; return nil }))
+	require.NoError(t, bucket.Iter(ctx, deletionMarksDir(tenantID), func(string) error { marks++; return nil }))
+	require.Zero(t, records)
+	require.Equal(t, 1, marks)
+}
+
+func TestCompactor_UnindexedObjects(t *testing.T) {
+	var (
+		ctx    = user.InjectOrgID(context.Background(), tenantID)
+		bucket = objstore.NewInMemBucket()
+		window = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	cluster, addr := testkafka.CreateClusterWithoutCustomConsumerGroupsSupport(t, 1, "loki.metastore-events")
+	defer cluster.Close()
+	producer, err := kgo.NewClient(kgo.SeedBrokers(addr), kgo.DefaultProduceTopic("loki.metastore-events"))
+	require.NoError(t, err)
+	defer producer.Close()
+
+	// The index builder didn't index the objects yet.
+	var oldPaths []string
+	for i := range 2 {
+		ts := window.Add(time.Duration(i) * time.Minute)
+		oldPaths = append(oldPaths, writeObject(t, bucket,
+			logproto.Stream{Labels: `{app="foo"}`, Entries: []logproto.Entry{{Timestamp: ts, Line: fmt.Sprintf("foo %d", i)}}},
+		))
+	}
+
+	c, err := New(Config{
+		BuilderConfig:      builderCfg,
+		UploaderConfig:     uploader.Config{SHAPrefixSize: 2},
+		CompactionInterval: time.Minute,
+		SmallObjectSize:    1 << 20,
+		MinObjects:         2,
+		DeleteDelay:        time.Hour,
+		LockLeaseDuration:  time.Minute,
+	}, metastore.Config{}, indexCfg, bucket, producer, nil, nil, prometheus.NewRegistry(), log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, c.Compact(context.Background()))
+
+	objects, err := metastore.NewObjectMetastore(metastore.Config{}, bucket, log.NewNopLogger(), nil).WindowObjects(ctx, window)
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	require.NotContains(t, oldPaths, objects[0].Path)
+
+	// No index object is written, the compacted object is announced to the
+	// index builder instead.
+	indexBucket := objstore.NewPrefixedBucket(bucket, indexCfg.IndexStoragePrefix)
+	indexes, err := metastore.NewObjectMetastore(metastore.Config{}, indexBucket, log.NewNopLogger(), nil).WindowObjects(ctx, window)
+	require.NoError(t, err)
+	require.Empty(t, indexes)
+
+	events := readEvents(t, addr, 1)
+	require.Equal(t, tenantID, events[0].Tenant)
+	require.Equal(t, objects[0].Path, events[0].ObjectPath)
+}
+
+// readEvents reads n object written events from the cluster at addr.
+func readEvents(t *testing.T, addr string, n int) []metastore.ObjectWrittenEvent {
+	t.Helper()
+
+	consumer, err := kgo.NewClient(kgo.SeedBrokers(addr), kgo.ConsumeTopics("loki.metastore-events"), kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()))
+	require.NoError(t, err)
+	defer consumer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var events []metastore.ObjectWrittenEvent
+	for len(events) < n {
+		fetches := consumer.PollFetches(ctx)
+		require.NoError(t, fetches.Err())
+		for _, record := range fetches.Records() {
+			var event metastore.ObjectWrittenEvent
+			require.NoError(t, event.Unmarshal(record.Value))
+			events = append(events, event)
+		}
+	}
+	require.Len(t, events, n)
+	return events
+}
+
+// failOnceBucket fails the first upload of an object with the given prefix.
+type failOnceBucket struct {
+	objstore.Bucket
+	prefix string
+	failed bool
+}
+
+func (b *failOnceBucket) Upload(ctx context.Context, name string, r io.Reader) error {
+	if !b.failed && strings.HasPrefix(name, b.prefix) {
+		b.failed = true
+		return errors.New("upload failed")
+	}
+	return b.Bucket.Upload(ctx, name, r)
+}
+
+func TestCompactor_Batches(t *testing.T) {
+	c := &Compactor{cfg: Config{
+		BuilderConfig: logsobj.BuilderConfig{TargetObjectSize: 100},
+		MinObjects:    2,
+	}}
+
+	start := time.Unix(0, 0)
+	candidates := []candidate{
+		{ObjectInfo: metastore.ObjectInfo{Path: "c", MinTimestamp: start.Add(3)}, size: 40},
+		{ObjectInfo: metastore.ObjectInfo{Path: "a", MinTimestamp: start.Add(1)}, size: 40},
+		{ObjectInfo: metastore.ObjectInfo{Path: "b", MinTimestamp: start.Add(2)}, size: 40},
+		{ObjectInfo: metastore.ObjectInfo{Path: "d", MinTimestamp: start.Add(4)}, size: 90},
+		{ObjectInfo: metastore.ObjectInfo{Path: "e", MinTimestamp: start.Add(5)}, size: 5},
+		{ObjectInfo: metastore.ObjectInfo{Path: "f", MinTimestamp: start.Add(6)}, size: 5},
+	}
+
+	var actual [][]string
+	for _, batch := range c.batches(candidates) {
+		var paths []string
+		for _, obj := range batch {
+			paths = append(paths, obj.Path)
+		}
+		actual = append(actual, paths)
+	}
+
+	// "c" and "d" are left out, since they don't fit with enough other objects.
+	require.Equal(t, [][]string{{"a", "b"}, {"d", "e", "f"}}, actual)
+}
+
+func writeObject(t *testing.T, bucket objstore.Bucket, streams ...logproto.Stream) string {
+	t.Helper()
+
+	builder, err := logsobj.NewBuilder(builderCfg)
+	require.NoError(t, err)
+	for _, stream := range streams {
+		require.NoError(t, builder.Append(stream))
+	}
+
+	var buf bytes.Buffer
+	stats, err := builder.Flush(&buf)
+	require.NoError(t, err)
+
+	path, err := uploader.New(uploader.Config{SHAPrefixSize: 2}, bucket, tenantID, log.NewNopLogger()).Upload(context.Background(), &buf)
+	require.NoError(t, err)
+
+	updater := metastore.NewUpdater(metastore.UpdaterConfig{}, bucket, tenantID, log.NewNopLogger())
+	require.NoError(t, updater.Update(context.Background(), path, stats.MinTimestamp, stats.MaxTimestamp))
+	return path
+}
+
+func writeIndex(t *testing.T, bucket objstore.Bucket, paths []string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	stats := calculateIndex(t, bucket, paths, &buf)
+
+	indexBucket := objstore.NewPrefixedBucket(bucket, indexCfg.IndexStoragePrefix)
+	key := index.ObjectKey(tenantID, &buf)
+	require.NoError(t, indexBucket.Upload(context.Background(), key, &buf))
+
+	updater := metastore.NewUpdater(metastore.UpdaterConfig{}, indexBucket, tenantID, log.NewNopLogger())
+	require.NoError(t, updater.Update(context.Background(), key, stats.MinTimestamp, stats.MaxTimestamp))
+	return key
+}
+
+// buildIndex builds an index object for the data objects at paths without
+// uploading it.
+func buildIndex(t *testing.T, bucket objstore.Bucket, paths []string) *dataobj.Object {
+	t.Helper()
+
+	var buf bytes.Buffer
+	calculateIndex(t, bucket, paths, &buf)
+	obj, err := dataobj.FromReaderAt(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	return obj
+}
+
+func calculateIndex(t *testing.T, bucket objstore.Bucket, paths []string, buf *bytes.Buffer) indexobj.FlushStats {
+	t.Helper()
+
+	builder, err := indexobj.NewBuilder(indexCfg.BuilderConfig)
+	require.NoError(t, err)
+	calculator := index.NewCalculator(builder)
+
+	for _, path := range paths {
+		obj, err := dataobj.FromBucket(context.Background(), bucket, path)
+		require.NoError(t, err)
+		require.NoError(t, calculator.Calculate(context.Background(), log.NewNopLogger(), obj, path))
+	}
+
+	stats, err := calculator.Flush(buf)
+	require.NoError(t, err)
+	return stats
+}
+
+// readIndex returns the pointers and n-gram bloom filters of the index object
+// obj. Stream IDs in the index object are replaced with the stream labels, so
+// that index objects built in a different order can be compared.
+func readIndex(t *testing.T, obj *dataobj.Object) []string {
+	t.Helper()
+
+	streamLabels := make(map[int64]string)
+	for res := range streams.Iter(context.Background(), obj) {
+		stream, err := res.Value()
+		require.NoError(t, err)
+		streamLabels[stream.ID] = stream.Labels.String()
+	}
+
+	var entries []string
+	for res := range pointers.Iter(context.Background(), obj) {
+		p, err := res.Value()
+		require.NoError(t, err)
+		switch p.PointerKind {
+		case pointers.PointerKindStreamIndex:
+			entries = append(entries, fmt.Sprintf("stream %s/%d %s ref=%d %d-%d lines=%d size=%d", p.Path, p.Section, streamLabels[p.StreamID], p.StreamIDRef, p.StartTs.UnixNano(), p.EndTs.UnixNano(), p.LineCount, p.UncompressedSize))
+		default:
+			entries = append(entries, fmt.Sprintf("column %s/%d %s %d %x", p.Path, p.Section, p.ColumnName, p.ColumnIndex, p.ValuesBloomFilter))
+		}
+	}
+	for res := range ngrams.Iter(context.Background(), obj) {
+		n, err := res.Value()
+		require.NoError(t, err)
+		entries = append(entries, fmt.Sprintf("ngrams %s/%d %d %x", n.Path, n.Section, n.StreamID, n.NgramBloomFilter))
+	}
+	return entries
+}
+
+// readLines returns the log lines of the object at path, prefixed with the
+// labels of their stream, in the order they are stored.
+func readLines(t *testing.T, bucket objstore.Bucket, path string) []string {
+	t.Helper()
+
+	obj, err := dataobj.FromBucket(context.Background(), bucket, path)
+	require.NoError(t, err)
+
+	streamLabels := make(map[int64]string)
+	for res := range streams.Iter(context.Background(), obj) {
+		stream, err := res.Value()
+		require.NoError(t, err)
+		streamLabels[stream.ID] = stream.Labels.String()
+	}
+
+	var lines []string
+	for res := range logs.Iter(context.Background(), obj) {
+		record, err := res.Value()
+		require.NoError(t, err)
+		lines = append(lines, streamLabels[record.StreamID]+" "+string(record.Line))
+	}
+	return lines
+}
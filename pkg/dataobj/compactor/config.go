@@ -0,0 +1,75 @@
+package compactor
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/consumer/logsobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/uploader"
+)
+
+type Config struct {
+	logsobj.BuilderConfig `yaml:",inline"`
+	UploaderConfig        uploader.Config `yaml:"uploader"`
+
+	CompactionInterval time.Duration `yaml:"compaction_interval" experimental:"true"`
+	SmallObjectSize    flagext.Bytes `yaml:"small_object_size" experimental:"true"`
+	MinObjects         int           `yaml:"min_objects" experimental:"true"`
+	DeleteDelay        time.Duration `yaml:"delete_delay" experimental:"true"`
+	RetentionEnabled   bool          `yaml:"retention_enabled" experimental:"true"`
+	LockLeaseDuration  time.Duration `yaml:"lock_lease_duration" experimental:"true"`
+
+	// DeleteRequestCancelPeriod is the cancel period of delete requests,
+	// which is set from the compactor config.
+	DeleteRequestCancelPeriod time.Duration `yaml:"-"`
+}
+
+func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	cfg.RegisterFlagsWithPrefix("dataobj-compactor.", f)
+}
+
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	cfg.BuilderConfig.RegisterFlagsWithPrefix(prefix, f)
+	cfg.UploaderConfig.RegisterFlagsWithPrefix(prefix, f)
+
+	_ = cfg.SmallObjectSize.Set("128MB")
+
+	f.DurationVar(&cfg.CompactionInterval, prefix+"compaction-interval", 10*time.Minute, "Experimental: How often to look for small data objects to compact.")
+	f.Var(&cfg.SmallObjectSize, prefix+"small-object-size", "Experimental: Data objects smaller than this size are merged with other small data objects of the same metastore window.")
+	f.IntVar(&cfg.MinObjects, prefix+"min-objects", 4, "Experimental: The minimum number of small data objects in a metastore window required to compact them.")
+	f.DurationVar(&cfg.DeleteDelay, prefix+"delete-delay", 2*time.Hour, "Experimental: How long to wait before deleting data objects which have been compacted. Must be longer than the longest running query.")
+	f.DurationVar(&cfg.LockLeaseDuration, prefix+"lock-lease-duration", 15*time.Minute, "Experimental: How long the compactor holds the lock which prevents other compactors from running after its last renewal. The lock is renewed before rewriting each data object, so this must be longer than it takes to compact a single batch of objects.")
+	f.BoolVar(&cfg.RetentionEnabled, prefix+"retention-enabled", false, "Experimental: Remove logs past their retention period and logs requested for deletion from data objects. Delete requests are read from the compactor, which must run in the same process with retention enabled, and are applied once their cancel period has passed.")
+}
+
+func (cfg *Config) Validate() error {
+	var errs []error
+
+	if err := cfg.BuilderConfig.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := cfg.UploaderConfig.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if cfg.CompactionInterval <= 0 {
+		errs = append(errs, errors.New("CompactionInterval must be greater than 0"))
+	}
+	if cfg.SmallObjectSize <= 0 || cfg.SmallObjectSize >= cfg.TargetObjectSize {
+		errs = append(errs, errors.New("SmallObjectSize must be greater than 0 and less than TargetObjectSize"))
+	}
+	if cfg.MinObjects < 2 {
+		errs = append(errs, errors.New("MinObjects must be greater than 1"))
+	}
+	if cfg.DeleteDelay < 0 {
+		errs = append(errs, errors.New("DeleteDelay must not be negative"))
+	}
+	if cfg.LockLeaseDuration <= 0 {
+		errs = append(errs, errors.New("LockLeaseDuration must be greater than 0"))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,215 @@
+package compactor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/loki/v3/pkg/dataobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/index"
+	"github.com/grafana/loki/v3/pkg/dataobj/index/indexobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/ngrams"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/pointers"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
+)
+
+// prepareIndexes builds the index object which replaces the index objects of
+// window referencing one of oldPaths. The new index object references newPath
+// instead of oldPaths, or neither if newPath is empty. It is uploaded, but
+// not registered in the index metastore yet.
+//
+// Index objects reference many data objects, so the pointers of the other
+// data objects are copied from the affected index objects, and only newPath
+// is indexed from scratch. The affected index objects are merged into a
+// single new index object.
+//
+// The returned replacement is empty if no index object references oldPaths.
+// Its paths are relative to the index bucket.
+func (c *Compactor) prepareIndexes(ctx context.Context, tenantID string, window time.Time, oldPaths []string, newPath string) (metastoreReplacement, error) {
+	var (
+		indexBucket    = objstore.NewPrefixedBucket(c.bucket, c.indexCfg.IndexStoragePrefix)
+		indexMetastore = metastore.NewObjectMetastore(c.mCfg, indexBucket, c.logger, nil)
+	)
+
+	indexObjects, err := indexMetastore.WindowObjects(ctx, window)
+	if err != nil {
+		return metastoreReplacement{}, fmt.Errorf("listing index objects: %w", err)
+	}
+
+	removed := make(map[string]struct{}, len(oldPaths))
+	for _, path := range oldPaths {
+		removed[path] = struct{}{}
+	}
+
+	var (
+		r        metastoreReplacement
+		affected []*dataobj.Object
+	)
+	for _, info := range indexObjects {
+		obj, err := dataobj.FromBucket(ctx, indexBucket, info.Path)
+		if err != nil {
+			return metastoreReplacement{}, fmt.Errorf("opening index object %s: %w", info.Path, err)
+		}
+		ok, err := referencesAny(ctx, obj, removed)
+		if err != nil {
+			return metastoreReplacement{}, fmt.Errorf("reading pointers of index object %s: %w", info.Path, err)
+		} else if !ok {
+			continue
+		}
+
+		affected = append(affected, obj)
+		r.OldPaths = append(r.OldPaths, info.Path)
+		if r.MinTimestamp.IsZero() || info.MinTimestamp.Before(r.MinTimestamp) {
+			r.MinTimestamp = info.MinTimestamp
+		}
+		if info.MaxTimestamp.After(r.MaxTimestamp) {
+			r.MaxTimestamp = info.MaxTimestamp
+		}
+	}
+	if len(affected) == 0 {
+		return metastoreReplacement{}, nil
+	}
+
+	key, minTs, maxTs, err := c.buildIndex(ctx, tenantID, affected, removed, newPath)
+	if err != nil {
+		return metastoreReplacement{}, err
+	}
+	r.NewPath = key
+	if key != "" && minTs.Before(r.MinTimestamp) {
+		r.MinTimestamp = minTs
+	}
+	if key != "" && maxTs.After(r.MaxTimestamp) {
+		r.MaxTimestamp = maxTs
+	}
+	return r, nil
+}
+
+// buildIndex builds and uploads a single index object holding the pointers
+// of indexObjects which don't reference removed, and the index of the data
+// object at newPath, if any. It returns the path of the index object relative
+// to the index bucket and its time range, or an empty path if the index
+// object would be empty.
+func (c *Compactor) buildIndex(ctx context.Context, tenantID string, indexObjects []*dataobj.Object, removed map[string]struct{}, newPath string) (string, time.Time, time.Time, error) {
+	builder, err := indexobj.NewBuilder(c.indexCfg.BuilderConfig)
+	if err != nil {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("creating index builder: %w", err)
+	}
+
+	var copied int
+	for _, obj := range indexObjects {
+		n, err := copyIndex(ctx, builder, obj, removed)
+		if err != nil {
+			return "", time.Time{}, time.Time{}, err
+		}
+		copied += n
+	}
+
+	if newPath != "" {
+		obj, err := dataobj.FromBucket(ctx, c.bucket, newPath)
+		if err != nil {
+			return "", time.Time{}, time.Time{}, fmt.Errorf("opening object %s: %w", newPath, err)
+		}
+		if err := index.NewCalculator(builder).Calculate(ctx, c.logger, obj, newPath); err != nil {
+			return "", time.Time{}, time.Time{}, fmt.Errorf("calculating index for object %s: %w", newPath, err)
+		}
+	} else if copied == 0 {
+		return "", time.Time{}, time.Time{}, nil
+	}
+
+	var buf bytes.Buffer
+	stats, err := builder.Flush(&buf)
+	if err != nil {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("flushing index builder: %w", err)
+	}
+
+	indexBucket := objstore.NewPrefixedBucket(c.bucket, c.indexCfg.IndexStoragePrefix)
+	key := index.ObjectKey(tenantID, &buf)
+	if err := indexBucket.Upload(ctx, key, &buf); err != nil {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("uploading index: %w", err)
+	}
+	return key, stats.MinTimestamp, stats.MaxTimestamp, nil
+}
+
+// copyIndex appends the pointers and n-gram bloom filters of the index
+// object obj which don't reference one of removed to builder. It returns the
+// number of copied pointers.
+//
+// Stream IDs are only unique within an index object, so the stream of each
+// copied stream pointer is appended to builder again. Its time range and
+// size are taken from the copied pointers, so that removed data objects no
+// longer widen them.
+func copyIndex(ctx context.Context, builder *indexobj.Builder, obj *dataobj.Object, removed map[string]struct{}) (int, error) {
+	streamLabels := make(map[int64]labels.Labels)
+	for res := range streams.Iter(ctx, obj) {
+		stream, err := res.Value()
+		if err != nil {
+			return 0, fmt.Errorf("reading streams of index object: %w", err)
+		}
+		streamLabels[stream.ID] = stream.Labels
+	}
+
+	var copied int
+	for res := range pointers.Iter(ctx, obj) {
+		pointer, err := res.Value()
+		if err != nil {
+			return copied, fmt.Errorf("reading pointers of index object: %w", err)
+		}
+		if _, ok := removed[pointer.Path]; ok {
+			continue
+		}
+
+		if pointer.PointerKind == pointers.PointerKindStreamIndex {
+			lbls, ok := streamLabels[pointer.StreamID]
+			if !ok {
+				return copied, fmt.Errorf("unknown stream ID %d in index object", pointer.StreamID)
+			}
+			pointer.StreamID, err = builder.AppendStream(streams.Stream{
+				Labels:           lbls,
+				MinTimestamp:     pointer.StartTs,
+				MaxTimestamp:     pointer.EndTs,
+				UncompressedSize: pointer.UncompressedSize,
+			})
+			if err != nil {
+				return copied, fmt.Errorf("appending stream: %w", err)
+			}
+		}
+		if err := builder.AppendPointer(pointer); err != nil {
+			return copied, fmt.Errorf("appending pointer: %w", err)
+		}
+		copied++
+	}
+
+	for res := range ngrams.Iter(ctx, obj) {
+		stream, err := res.Value()
+		if err != nil {
+			return copied, fmt.Errorf("reading n-grams of index object: %w", err)
+		}
+		if _, ok := removed[stream.Path]; ok {
+			continue
+		}
+		if err := builder.AppendNgrams(stream.Path, stream.Section, stream.StreamID, stream.NgramBloomFilter); err != nil {
+			return copied, fmt.Errorf("appending n-grams: %w", err)
+		}
+	}
+	return copied, nil
+}
+
+// referencesAny returns whether the index object obj references one of
+// paths.
+func referencesAny(ctx context.Context, obj *dataobj.Object, paths map[string]struct{}) (bool, error) {
+	for res := range pointers.Iter(ctx, obj) {
+		pointer, err := res.Value()
+		if err != nil {
+			return false, err
+		}
+		if _, ok := paths[pointer.Path]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,80 @@
+package compactor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/thanos-io/objstore"
+)
+
+// lockPath is the path of the compactor lock in the data object bucket.
+const lockPath = "compactor/lock"
+
+// errLocked is returned when the lock is held by another compactor.
+var errLocked = errors.New("lock is held by another compactor")
+
+// A lock is a lease on the data object bucket. Compactors rewrite objects
+// and metastores without coordinating with each other, so only the holder
+// of the lock may compact.
+//
+// The lease is renewed before each rewrite. It expires if its holder stops
+// renewing it, so that another compactor can take over when the holder
+// crashes.
+type lock struct {
+	bucket objstore.Bucket
+	owner  string
+	lease  time.Duration
+}
+
+func newLock(bucket objstore.Bucket, lease time.Duration) *lock {
+	return &lock{bucket: bucket, owner: uuid.NewString(), lease: lease}
+}
+
+type lockRecord struct {
+	Owner   string    `json:"owner"`
+	Expires time.Time `json:"expires"`
+}
+
+// acquire acquires or renews the lock. It returns errLocked if another
+// compactor holds an unexpired lease.
+func (l *lock) acquire(ctx context.Context) error {
+	return l.update(ctx, time.Now().Add(l.lease))
+}
+
+// release releases the lock if it is held, so that another compactor can
+// take over without waiting for the lease to expire.
+func (l *lock) release(ctx context.Context) error {
+	err := l.update(ctx, time.Time{})
+	if errors.Is(err, errLocked) {
+		return nil
+	}
+	return err
+}
+
+func (l *lock) update(ctx context.Context, expires time.Time) error {
+	return l.bucket.GetAndReplace(ctx, lockPath, func(existing io.ReadCloser) (io.ReadCloser, error) {
+		if existing != nil {
+			defer existing.Close()
+
+			var current lockRecord
+			if err := json.NewDecoder(existing).Decode(&current); err != nil {
+				return nil, fmt.Errorf("decoding lock: %w", err)
+			}
+			if current.Owner != l.owner && time.Now().Before(current.Expires) {
+				return nil, errLocked
+			}
+		}
+
+		data, err := json.Marshal(lockRecord{Owner: l.owner, Expires: expires})
+		if err != nil {
+			return nil, fmt.Errorf("encoding lock: %w", err)
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	})
+}
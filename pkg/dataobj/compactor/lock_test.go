@@ -0,0 +1,35 @@
+package compactor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+)
+
+func TestLock(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		bucket = objstore.NewInMemBucket()
+		a      = newLock(bucket, time.Hour)
+		b      = newLock(bucket, time.Hour)
+	)
+
+	require.NoError(t, a.acquire(ctx))
+	require.NoError(t, a.acquire(ctx), "holder must be able to renew the lock")
+	require.ErrorIs(t, b.acquire(ctx), errLocked)
+	require.NoError(t, b.release(ctx), "releasing a lock held by another compactor is a no-op")
+	require.ErrorIs(t, b.acquire(ctx), errLocked)
+
+	require.NoError(t, a.release(ctx))
+	require.NoError(t, b.acquire(ctx))
+	require.ErrorIs(t, a.acquire(ctx), errLocked)
+
+	// An expired lease can be taken over.
+	expiring := newLock(bucket, -time.Second)
+	require.NoError(t, b.release(ctx))
+	require.NoError(t, expiring.acquire(ctx))
+	require.NoError(t, a.acquire(ctx))
+}
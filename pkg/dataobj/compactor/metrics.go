@@ -0,0 +1,79 @@
+package compactor
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type status string
+
+const (
+	statusSuccess status = "success"
+	statusFailure status = "failure"
+)
+
+type compactorMetrics struct {
+	compactionsTotal  *prometheus.CounterVec
+	compactedObjects  prometheus.Counter
+	deletedObjects    prometheus.Counter
+	compactionSeconds prometheus.Histogram
+
+	retentionObjects *prometheus.CounterVec
+	deletedLines     *prometheus.CounterVec
+}
+
+func newCompactorMetrics() *compactorMetrics {
+	return &compactorMetrics{
+		compactionsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_dataobj_compactor_compactions_total",
+			Help: "Total number of compactions of small data objects, by status",
+		}, []string{"status"}),
+		compactedObjects: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "loki_dataobj_compactor_compacted_objects_total",
+			Help: "Total number of small data objects merged into larger data objects",
+		}),
+		deletedObjects: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "loki_dataobj_compactor_deleted_objects_total",
+			Help: "Total number of compacted objects deleted from object storage",
+		}),
+		compactionSeconds: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:                            "loki_dataobj_compactor_compaction_duration_seconds",
+			Help:                            "Time taken to compact a batch of small data objects",
+			Buckets:                         prometheus.DefBuckets,
+			NativeHistogramBucketFactor:     1.1,
+			NativeHistogramMaxBucketNumber:  100,
+			NativeHistogramMinResetDuration: 0,
+		}),
+		retentionObjects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_dataobj_compactor_retention_objects_total",
+			Help: "Total number of data objects rewritten or dropped to remove logs past retention or requested for deletion, by action",
+		}, []string{"action"}),
+		deletedLines: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_dataobj_compactor_deleted_lines_total",
+			Help: "Total number of log lines removed by delete requests with line filters, by tenant",
+		}, []string{"user"}),
+	}
+}
+
+func (m *compactorMetrics) register(reg prometheus.Registerer) error {
+	collectors := []prometheus.Collector{
+		m.compactionsTotal,
+		m.compactedObjects,
+		m.deletedObjects,
+		m.compactionSeconds,
+		m.retentionObjects,
+		m.deletedLines,
+	}
+
+	for _, collector := range collectors {
+		if err := reg.Register(collector); err != nil {
+			if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *compactorMetrics) incCompactions(status status) {
+	m.compactionsTotal.WithLabelValues(string(status)).Inc()
+}
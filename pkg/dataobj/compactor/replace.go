@@ -0,0 +1,172 @@
+package compactor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/thanos-io/objstore"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
+)
+
+// A replacement records the metastore updates which replace data objects
+// with a rewritten data object. It is stored in the bucket until all updates
+// have been applied, so that a replacement which failed halfway is completed
+// by the next iteration.
+type replacement struct {
+	// Data replaces the data objects in the metastore.
+	Data metastoreReplacement `json:"data"`
+	// Index replaces the index objects referencing the data objects in the
+	// index metastore. Its paths are relative to the index bucket.
+	Index metastoreReplacement `json:"index"`
+}
+
+// metastoreReplacement describes a call to [metastore.Updater.Replace].
+type metastoreReplacement struct {
+	OldPaths     []string  `json:"old_paths"`
+	NewPath      string    `json:"new_path"`
+	MinTimestamp time.Time `json:"min_timestamp"`
+	MaxTimestamp time.Time `json:"max_timestamp"`
+}
+
+// replace replaces the data objects at oldPaths, which are within window, with
+// the data object at newPath in the metastore and in index objects. If newPath
+// is empty, the data objects are removed. The replaced objects are deleted
+// once the delete delay has passed.
+//
+// The index objects are replaced before the metastore, so that queries never
+// see both the old and the new data objects. The replacement is recorded
+// before updating either of them, and completed by the next iteration if it
+// fails.
+//
+// If no index object references the data objects yet, their events are still
+// buffered by the index builder, which skips them once they are no longer
+// listed in the metastore. An event for newPath is emitted instead, so that
+// it is indexed in their place.
+func (c *Compactor) replace(ctx context.Context, tenantID string, window time.Time, oldPaths []string, newPath string, minTs, maxTs time.Time) error {
+	indexReplacement, err := c.prepareIndexes(ctx, tenantID, window, oldPaths, newPath)
+	if err != nil {
+		return fmt.Errorf("rewriting index objects: %w", err)
+	}
+
+	r := replacement{
+		Data: metastoreReplacement{
+			OldPaths:     oldPaths,
+			NewPath:      newPath,
+			MinTimestamp: minTs,
+			MaxTimestamp: maxTs,
+		},
+		Index: indexReplacement,
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("encoding replacement: %w", err)
+	}
+	recordPath := replacementsDir(tenantID) + strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := c.bucket.Upload(ctx, recordPath, bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("uploading replacement: %w", err)
+	}
+
+	return c.completeReplacement(ctx, tenantID, recordPath, r)
+}
+
+// completeReplacement applies r, which is recorded at recordPath. Applying a
+// replacement more than once has no further effect.
+func (c *Compactor) completeReplacement(ctx context.Context, tenantID string, recordPath string, r replacement) error {
+	var toDelete []string
+
+	if len(r.Index.OldPaths) > 0 {
+		indexBucket := objstore.NewPrefixedBucket(c.bucket, c.indexCfg.IndexStoragePrefix)
+		updater := metastore.NewUpdater(c.mCfg.Updater, indexBucket, tenantID, c.logger)
+		if err := updater.Replace(ctx, r.Index.OldPaths, r.Index.NewPath, r.Index.MinTimestamp, r.Index.MaxTimestamp); err != nil {
+			return fmt.Errorf("replacing index objects in metastore: %w", err)
+		}
+		for _, path := range r.Index.OldPaths {
+			toDelete = append(toDelete, c.indexCfg.IndexStoragePrefix+path)
+		}
+	}
+
+	updater := metastore.NewUpdater(c.mCfg.Updater, c.bucket, tenantID, c.logger)
+	if err := updater.Replace(ctx, r.Data.OldPaths, r.Data.NewPath, r.Data.MinTimestamp, r.Data.MaxTimestamp); err != nil {
+		return fmt.Errorf("replacing objects in metastore: %w", err)
+	}
+	toDelete = append(toDelete, r.Data.OldPaths...)
+
+	if r.Data.NewPath != "" && len(r.Index.OldPaths) == 0 {
+		if err := c.emitObjectWrittenEvent(ctx, tenantID, r.Data.NewPath); err != nil {
+			return err
+		}
+	}
+
+	if err := c.markForDeletion(ctx, tenantID, toDelete); err != nil {
+		return err
+	}
+	if err := c.bucket.Delete(ctx, recordPath); err != nil {
+		return fmt.Errorf("deleting replacement %s: %w", recordPath, err)
+	}
+	return nil
+}
+
+// emitObjectWrittenEvent announces the data object at path to the index
+// builder. Unlike the consumer, it waits for the event to be produced, so
+// that a replacement isn't completed before its object is going to be
+// indexed.
+func (c *Compactor) emitObjectWrittenEvent(ctx context.Context, tenantID, path string) error {
+	if c.events == nil {
+		return nil
+	}
+
+	event := &metastore.ObjectWrittenEvent{
+		Tenant:     tenantID,
+		ObjectPath: path,
+		WriteTime:  time.Now().Format(time.RFC3339),
+	}
+	eventBytes, err := event.Marshal()
+	if err != nil {
+		return fmt.Errorf("encoding event for object %s: %w", path, err)
+	}
+	if err := c.events.ProduceSync(ctx, &kgo.Record{Value: eventBytes}).FirstErr(); err != nil {
+		return fmt.Errorf("producing event for object %s: %w", path, err)
+	}
+	return nil
+}
+
+// completePendingReplacements completes the replacements of tenantID which
+// failed in a previous iteration.
+func (c *Compactor) completePendingReplacements(ctx context.Context, tenantID string) error {
+	var records []string
+	if err := c.bucket.Iter(ctx, replacementsDir(tenantID), func(name string) error {
+		records = append(records, name)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, recordPath := range records {
+		rc, err := c.bucket.Get(ctx, recordPath)
+		if err != nil {
+			return fmt.Errorf("reading replacement %s: %w", recordPath, err)
+		}
+		var r replacement
+		err = json.NewDecoder(rc).Decode(&r)
+		_ = rc.Close()
+		if err != nil {
+			return fmt.Errorf("decoding replacement %s: %w", recordPath, err)
+		}
+
+		if err := c.completeReplacement(ctx, tenantID, recordPath, r); err != nil {
+			return fmt.Errorf("completing replacement %s: %w", recordPath, err)
+		}
+	}
+	return nil
+}
+
+func replacementsDir(tenantID string) string {
+	return fmt.Sprintf("tenant-%s/compactor/replacements/", tenantID)
+}
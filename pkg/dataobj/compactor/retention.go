@@ -0,0 +1,443 @@
+package compactor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/compactor/deletion"
+	"github.com/grafana/loki/v3/pkg/compactor/deletionmode"
+	"github.com/grafana/loki/v3/pkg/compactor/retention"
+	"github.com/grafana/loki/v3/pkg/dataobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/consumer/logsobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
+	"github.com/grafana/loki/v3/pkg/dataobj/uploader"
+	"github.com/grafana/loki/v3/pkg/util/filter"
+)
+
+const (
+	retentionActionRewritten = "rewritten"
+	retentionActionDropped   = "dropped"
+)
+
+// removeAll is the filter for streams whose logs must all be removed.
+func removeAll(time.Time, string, labels.Labels) bool { return true }
+
+// retentionFilter decides which logs of a tenant must be removed, because
+// they are past their retention period or requested for deletion.
+type retentionFilter struct {
+	tenantID  string
+	retention *retention.TenantRetentionSnapshot
+	requests  []deletion.DeleteRequest
+	now       time.Time
+}
+
+// forStream returns a filter for the logs of stream which must be removed,
+// or nil if no logs of stream must be removed. all is true if all logs of
+// stream must be removed.
+func (f *retentionFilter) forStream(stream streams.Stream) (fn filter.Func, all bool) {
+	var filters []filter.Func
+
+	if f.retention != nil {
+		// A period of 0 disables retention.
+		if period := f.retention.RetentionPeriodFor(stream.Labels); period > 0 {
+			cutoff := f.now.Add(-period)
+			if stream.MaxTimestamp.Before(cutoff) {
+				return removeAll, true
+			}
+			if stream.MinTimestamp.Before(cutoff) {
+				filters = append(filters, func(ts time.Time, _ string, _ labels.Labels) bool {
+					return ts.Before(cutoff)
+				})
+			}
+		}
+	}
+
+	chunk := retention.Chunk{
+		From:    model.TimeFromUnixNano(stream.MinTimestamp.UnixNano()),
+		Through: model.TimeFromUnixNano(stream.MaxTimestamp.UnixNano()),
+	}
+	for i := range f.requests {
+		deleted, fn := f.requests[i].GetChunkFilter([]byte(f.tenantID), stream.Labels, chunk)
+		if !deleted {
+			continue
+		}
+		if fn == nil {
+			// The request covers all logs of the stream.
+			return removeAll, true
+		}
+		filters = append(filters, fn)
+	}
+
+	switch len(filters) {
+	case 0:
+		return nil, false
+	case 1:
+		return filters[0], false
+	}
+	return func(ts time.Time, line string, metadata labels.Labels) bool {
+		for _, fn := range filters {
+			if fn(ts, line, metadata) {
+				return true
+			}
+		}
+		return false
+	}, false
+}
+
+// Limits provides the retention periods and deletion modes of tenants.
+type Limits interface {
+	retention.Limits
+	DeletionMode(userID string) string
+}
+
+// DeleteRequestsStore provides the delete requests of tenants. It is
+// implemented by the delete requests store of the compactor.
+type DeleteRequestsStore interface {
+	GetAllDeleteRequestsForUser(ctx context.Context, userID string, forQuerytimeFiltering bool) ([]deletion.DeleteRequest, error)
+}
+
+// retentionState tracks the progress of applying retention to the objects
+// of a tenant, so that objects are only opened if some of their logs may
+// have to be removed.
+type retentionState struct {
+	// Periods are the retention periods the objects were checked with.
+	Periods []time.Duration `json:"periods"`
+	// Processed are the IDs of the delete requests which have been applied
+	// to all objects.
+	Processed []string `json:"processed"`
+	// Objects holds the progress of each object.
+	Objects map[string]objectProgress `json:"objects"`
+}
+
+type objectProgress struct {
+	// Checked is when the retention periods were last applied to the object.
+	Checked time.Time `json:"checked"`
+	// Requests are the IDs of the pending delete requests which have been
+	// applied to the object.
+	Requests []string `json:"requests,omitempty"`
+}
+
+// applyRetention removes the logs of tenantID in windows which are past
+// their retention period or requested for deletion.
+//
+// Only objects with logs which expired since they were last checked, or
+// which overlap a delete request that hasn't been applied to them yet, are
+// opened. Delete requests are processed once they have been applied to all
+// objects. The progress is kept in the bucket, so that a failed pass resumes
+// where it stopped.
+func (c *Compactor) applyRetention(ctx context.Context, tenantID string, windows []time.Time) (err error) {
+	state, err := c.loadRetentionState(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if saveErr := c.saveRetentionState(ctx, tenantID, state); saveErr != nil {
+			err = errors.Join(err, saveErr)
+		}
+	}()
+
+	f := &retentionFilter{tenantID: tenantID, now: time.Now()}
+	var periods []time.Duration
+	if c.limits != nil {
+		f.retention = retention.NewTenantRetentionSnapshot(c.limits, tenantID)
+		periods = retentionPeriods(c.limits, tenantID)
+	}
+	if !slices.Equal(periods, state.Periods) {
+		// Logs of objects checked with other periods may have expired.
+		for path, progress := range state.Objects {
+			progress.Checked = time.Time{}
+			state.Objects[path] = progress
+		}
+		state.Periods = periods
+	}
+
+	f.requests, state.Processed, err = c.pendingDeleteRequests(ctx, tenantID, state.Processed, f.now)
+	if err != nil {
+		return err
+	}
+	applied := make([]string, 0, len(f.requests))
+	for _, req := range f.requests {
+		if !slices.Contains(applied, req.RequestID) {
+			applied = append(applied, req.RequestID)
+		}
+	}
+
+	ctx = user.InjectOrgID(ctx, tenantID)
+
+	// Objects spanning several windows are listed in each of them, but only
+	// need to be processed once.
+	seen := make(map[string]struct{})
+	for _, window := range windows {
+		objects, err := c.metastore.WindowObjects(ctx, window)
+		if err != nil {
+			return err
+		}
+
+		for _, info := range objects {
+			if _, ok := seen[info.Path]; ok {
+				continue
+			}
+			seen[info.Path] = struct{}{}
+
+			if !needsRetention(info, state.Objects[info.Path], f.now, periods, f.requests) {
+				continue
+			}
+
+			path, err := c.applyObjectRetention(ctx, tenantID, info, f)
+			if err != nil {
+				return fmt.Errorf("applying retention to object %s: %w", info.Path, err)
+			}
+			delete(state.Objects, info.Path)
+			if path != "" {
+				seen[path] = struct{}{}
+				state.Objects[path] = objectProgress{Checked: f.now, Requests: applied}
+			}
+		}
+	}
+
+	// All objects have been checked, so the pending delete requests have
+	// been processed.
+	for path, progress := range state.Objects {
+		if _, ok := seen[path]; !ok {
+			delete(state.Objects, path)
+			continue
+		}
+		progress.Requests = nil
+		state.Objects[path] = progress
+	}
+	for _, id := range applied {
+		state.Processed = append(state.Processed, id)
+		level.Info(c.logger).Log("msg", "processed delete request", "tenant", tenantID, "delete_request_id", id)
+	}
+	return nil
+}
+
+// pendingDeleteRequests returns the delete requests of tenantID which haven't
+// been processed yet and can no longer be cancelled. It also returns the IDs
+// in processed of the requests which still exist.
+func (c *Compactor) pendingDeleteRequests(ctx context.Context, tenantID string, processed []string, now time.Time) ([]deletion.DeleteRequest, []string, error) {
+	if c.deleteRequests == nil {
+		return nil, processed, nil
+	}
+	if c.limits != nil {
+		mode, err := deletionmode.ParseMode(c.limits.DeletionMode(tenantID))
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting deletion mode: %w", err)
+		}
+		if mode != deletionmode.FilterAndDelete {
+			return nil, processed, nil
+		}
+	}
+
+	requests, err := c.deleteRequests.GetAllDeleteRequestsForUser(ctx, tenantID, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting delete requests: %w", err)
+	}
+
+	var (
+		pending        []deletion.DeleteRequest
+		stillProcessed []string
+		cutoff         = model.TimeFromUnixNano(now.Add(-c.cfg.DeleteRequestCancelPeriod).UnixNano())
+	)
+	for _, req := range requests {
+		if slices.Contains(processed, req.RequestID) {
+			if !slices.Contains(stillProcessed, req.RequestID) {
+				stillProcessed = append(stillProcessed, req.RequestID)
+			}
+			continue
+		}
+		// Requests can be cancelled during the cancel period. The extra
+		// minute avoids racing with a cancellation, like the compactor does.
+		if req.CreatedAt.Add(time.Minute).After(cutoff) {
+			continue
+		}
+		// Filters of requests with line filters count the deleted lines.
+		req.TotalLinesDeletedMetric = c.metrics.deletedLines
+		pending = append(pending, req)
+	}
+	return pending, stillProcessed, nil
+}
+
+// needsRetention returns whether some logs of the object described by info
+// may have to be removed, given its progress.
+func needsRetention(info metastore.ObjectInfo, progress objectProgress, now time.Time, periods []time.Duration, requests []deletion.DeleteRequest) bool {
+	for _, period := range periods {
+		// Logs which expired since the last check are between the cutoffs of
+		// the last and this check.
+		if info.MinTimestamp.Before(now.Add(-period)) && !info.MaxTimestamp.Before(progress.Checked.Add(-period)) {
+			return true
+		}
+	}
+
+	var (
+		from    = model.TimeFromUnixNano(info.MinTimestamp.UnixNano())
+		through = model.TimeFromUnixNano(info.MaxTimestamp.UnixNano())
+	)
+	for _, req := range requests {
+		if req.StartTime <= through && req.EndTime >= from && !slices.Contains(progress.Requests, req.RequestID) {
+			return true
+		}
+	}
+	return false
+}
+
+// retentionPeriods returns the distinct retention periods of tenantID in
+// ascending order. A period of 0 disables retention and is omitted.
+func retentionPeriods(limits retention.Limits, tenantID string) []time.Duration {
+	var periods []time.Duration
+	if period := limits.RetentionPeriod(tenantID); period > 0 {
+		periods = append(periods, period)
+	}
+	for _, streamRetention := range limits.StreamRetention(tenantID) {
+		if period := time.Duration(streamRetention.Period); period > 0 && !slices.Contains(periods, period) {
+			periods = append(periods, period)
+		}
+	}
+	slices.Sort(periods)
+	return periods
+}
+
+func (c *Compactor) loadRetentionState(ctx context.Context, tenantID string) (*retentionState, error) {
+	state := &retentionState{Objects: make(map[string]objectProgress)}
+
+	rc, err := c.bucket.Get(ctx, retentionStatePath(tenantID))
+	if c.bucket.IsObjNotFoundErr(err) {
+		return state, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading retention state: %w", err)
+	}
+	defer rc.Close()
+
+	if err := json.NewDecoder(rc).Decode(state); err != nil {
+		return nil, fmt.Errorf("decoding retention state: %w", err)
+	}
+	if state.Objects == nil {
+		state.Objects = make(map[string]objectProgress)
+	}
+	return state, nil
+}
+
+func (c *Compactor) saveRetentionState(ctx context.Context, tenantID string, state *retentionState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encoding retention state: %w", err)
+	}
+	if err := c.bucket.Upload(ctx, retentionStatePath(tenantID), bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("uploading retention state: %w", err)
+	}
+	return nil
+}
+
+func retentionStatePath(tenantID string) string {
+	return fmt.Sprintf("tenant-%s/compactor/retention.json", tenantID)
+}
+
+// applyObjectRetention removes the logs of the object described by info for
+// which f returns a filter. The object is rewritten without these logs, or
+// dropped if none of its logs remain, and replaced in the metastore and in
+// index objects. The old object is deleted once the delete delay has passed.
+//
+// applyObjectRetention returns the path of the object holding the remaining
+// logs: the path of the rewritten object, info.Path if the object was left
+// untouched, or an empty path if it was dropped.
+func (c *Compactor) applyObjectRetention(ctx context.Context, tenantID string, info metastore.ObjectInfo, f *retentionFilter) (string, error) {
+	obj, err := dataobj.FromBucket(ctx, c.bucket, info.Path)
+	if err != nil {
+		return "", fmt.Errorf("opening object: %w", err)
+	}
+
+	// A stream may be stored in several sections, so its time range is only
+	// known once all sections have been read.
+	objStreams := make(map[int64]streams.Stream)
+	for res := range streams.Iter(ctx, obj) {
+		stream, err := res.Value()
+		if err != nil {
+			return "", fmt.Errorf("reading streams: %w", err)
+		}
+		if prev, ok := objStreams[stream.ID]; ok {
+			if prev.MinTimestamp.Before(stream.MinTimestamp) {
+				stream.MinTimestamp = prev.MinTimestamp
+			}
+			if prev.MaxTimestamp.After(stream.MaxTimestamp) {
+				stream.MaxTimestamp = prev.MaxTimestamp
+			}
+		}
+		objStreams[stream.ID] = stream
+	}
+
+	var (
+		filters     = make(map[int64]filter.Func)
+		removeWhole = true
+	)
+	for id, stream := range objStreams {
+		fn, all := f.forStream(stream)
+		if fn != nil {
+			filters[id] = fn
+		}
+		removeWhole = removeWhole && all
+	}
+	if len(filters) == 0 {
+		return info.Path, nil
+	}
+
+	if err := c.lock.acquire(ctx); err != nil {
+		return "", fmt.Errorf("renewing lock: %w", err)
+	}
+
+	var newPath string
+	if !removeWhole {
+		c.builder.Reset()
+		defer c.builder.Reset()
+
+		removed, err := c.appendObject(ctx, obj, filters)
+		if err != nil {
+			return "", err
+		}
+		if removed == 0 {
+			// Line filters of delete requests didn't match any logs.
+			return info.Path, nil
+		}
+
+		c.buf.Reset()
+		_, err = c.builder.Flush(c.buf)
+		switch {
+		case errors.Is(err, logsobj.ErrBuilderEmpty):
+			// The filters removed all remaining logs, so the object is dropped.
+		case err != nil:
+			return "", fmt.Errorf("flushing rewritten object: %w", err)
+		default:
+			newPath, err = uploader.New(c.cfg.UploaderConfig, c.bucket, tenantID, c.logger).Upload(ctx, c.buf)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// The rewritten object is registered for the whole time range of the old
+	// object, which always covers its own time range. Index objects
+	// referencing the object cover its whole time range, so they are all
+	// listed in the window of its first log.
+	window := info.MinTimestamp.Truncate(metastore.WindowSize)
+	if err := c.replace(ctx, tenantID, window, []string{info.Path}, newPath, info.MinTimestamp, info.MaxTimestamp); err != nil {
+		return "", err
+	}
+
+	action := retentionActionRewritten
+	if newPath == "" {
+		action = retentionActionDropped
+	}
+	c.metrics.retentionObjects.WithLabelValues(action).Inc()
+	level.Info(c.logger).Log("msg", "applied retention to data object", "tenant", tenantID, "path", info.Path, "action", action, "new_path", newPath)
+	return newPath, nil
+}
@@ -0,0 +1,324 @@
+package compactor
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/grafana/loki/v3/pkg/compactor/deletion"
+	"github.com/grafana/loki/v3/pkg/compactor/deletionmode"
+	"github.com/grafana/loki/v3/pkg/dataobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
+	"github.com/grafana/loki/v3/pkg/dataobj/uploader"
+	"github.com/grafana/loki/v3/pkg/kafka/testkafka"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+func TestCompactor_Retention(t *testing.T) {
+	var (
+		ctx    = user.InjectOrgID(context.Background(), tenantID)
+		bucket = &countingBucket{Bucket: objstore.NewInMemBucket()}
+		now    = time.Now().Truncate(time.Second)
+	)
+
+	// Logs of {app="foo"} are retained for a day, logs of {app="bar"} forever.
+	limits := fakeLimits{streamRetention: []validation.StreamRetention{{
+		Period:   model.Duration(24 * time.Hour),
+		Matchers: []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "app", "foo")},
+	}}}
+	requests := fakeDeleteRequests{{
+		RequestID: "1",
+		UserID:    tenantID,
+		StartTime: 0,
+		EndTime:   model.Now(),
+		Query:     `{app="bar"} |= "secret"`,
+		CreatedAt: model.Now().Add(-2 * time.Hour),
+	}, {
+		// Requests are only applied once their cancel period has passed.
+		RequestID: "2",
+		UserID:    tenantID,
+		StartTime: 0,
+		EndTime:   model.Now(),
+		Query:     `{app="foo"} |= "secret"`,
+		CreatedAt: model.Now(),
+	}}
+
+	expired := writeObject(t, bucket,
+		logproto.Stream{Labels: `{app="foo"}`, Entries: []logproto.Entry{{Timestamp: now.Add(-48 * time.Hour), Line: "expired"}}},
+	)
+	partiallyExpired := writeObject(t, bucket,
+		logproto.Stream{Labels: `{app="foo"}`, Entries: []logproto.Entry{{Timestamp: now.Add(-30 * time.Hour), Line: "expired"}}},
+		logproto.Stream{Labels: `{app="bar"}`, Entries: []logproto.Entry{{Timestamp: now.Add(-30 * time.Hour), Line: "retained"}}},
+	)
+	deleted := writeObject(t, bucket,
+		logproto.Stream{Labels: `{app="bar"}`, Entries: []logproto.Entry{
+			{Timestamp: now.Add(-2 * time.Hour), Line: "secret"},
+			{Timestamp: now.Add(-time.Hour), Line: "retained"},
+		}},
+	)
+	untouched := writeObject(t, bucket,
+		logproto.Stream{Labels: `{app="foo"}`, Entries: []logproto.Entry{{Timestamp: now.Add(-time.Hour), Line: "secret"}}},
+	)
+	writeIndex(t, bucket, []string{expired, partiallyExpired, deleted, untouched})
+
+	c, err := New(Config{
+		BuilderConfig:      builderCfg,
+		UploaderConfig:     uploader.Config{SHAPrefixSize: 2},
+		CompactionInterval: time.Minute,
+		SmallObjectSize:    1 << 20,
+		MinObjects:         100,
+		DeleteDelay:        0,
+		RetentionEnabled:   true,
+		LockLeaseDuration:  time.Minute,
+
+		DeleteRequestCancelPeriod: time.Hour,
+	}, metastore.Config{}, indexCfg, bucket, nil, limits, requests, prometheus.NewRegistry(), log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, c.Compact(context.Background()))
+
+	// The metastore window of the expired object only listed that object, so
+	// it must be empty.
+	expiredObjects, err := metastore.NewObjectMetastore(metastore.Config{}, bucket, log.NewNopLogger(), nil).WindowObjects(ctx, now.Add(-48*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, expiredObjects)
+
+	windows, err := metastore.Windows(ctx, bucket, tenantID)
+	require.NoError(t, err)
+
+	objects := allObjects(ctx, t, bucket, windows)
+	require.Len(t, objects, 3)
+	require.Contains(t, objects, untouched)
+
+	var lines []string
+	for _, path := range objects {
+		if path == untouched {
+			continue
+		}
+		require.NotContains(t, []string{expired, partiallyExpired, deleted}, path)
+		lines = append(lines, readLines(t, bucket, path)...)
+	}
+	require.ElementsMatch(t, []string{`{app="bar"} retained`, `{app="bar"} retained`}, lines)
+
+	// The index must only reference the remaining objects.
+	indexBucket := objstore.NewPrefixedBucket(bucket, indexCfg.IndexStoragePrefix)
+	indexWindows, err := metastore.Windows(ctx, indexBucket, tenantID)
+	require.NoError(t, err)
+	indexes := allObjects(ctx, t, indexBucket, indexWindows)
+	require.Len(t, indexes, 1)
+
+	indexObj, err := dataobj.FromBucket(ctx, indexBucket, indexes[0])
+	require.NoError(t, err)
+	require.ElementsMatch(t, readIndex(t, buildIndex(t, bucket, objects)), readIndex(t, indexObj))
+
+	// Applying retention again must not change anything, and must not open
+	// any object, since their logs haven't expired since and the delete
+	// request has been processed.
+	require.NotZero(t, bucket.reads)
+	bucket.reads = 0
+	require.NoError(t, c.Compact(context.Background()))
+	require.Zero(t, bucket.reads)
+	windows, err = metastore.Windows(ctx, bucket, tenantID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, objects, allObjects(ctx, t, bucket, windows))
+
+	state, err := c.loadRetentionState(ctx, tenantID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, state.Processed)
+	require.Len(t, state.Objects, len(objects))
+
+	for _, path := range []string{expired, partiallyExpired, deleted} {
+		exists, err := bucket.Exists(ctx, path)
+		require.NoError(t, err)
+		require.False(t, exists, "%s should have been deleted", path)
+	}
+}
+
+func TestCompactor_RetentionUnindexedObjects(t *testing.T) {
+	var (
+		ctx    = user.InjectOrgID(context.Background(), tenantID)
+		bucket = objstore.NewInMemBucket()
+		now    = time.Now().Truncate(time.Second)
+	)
+
+	cluster, addr := testkafka.CreateClusterWithoutCustomConsumerGroupsSupport(t, 1, "loki.metastore-events")
+	defer cluster.Close()
+	producer, err := kgo.NewClient(kgo.SeedBrokers(addr), kgo.DefaultProduceTopic("loki.metastore-events"))
+	require.NoError(t, err)
+	defer producer.Close()
+
+	// Logs of {app="foo"} are retained for a day, logs of {app="bar"} forever.
+	limits := fakeLimits{streamRetention: []validation.StreamRetention{{
+		Period:   model.Duration(24 * time.Hour),
+		Matchers: []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "app", "foo")},
+	}}}
+
+	// The index builder didn't index the objects yet.
+	expired := writeObject(t, bucket,
+		logproto.Stream{Labels: `{app="foo"}`, Entries: []logproto.Entry{{Timestamp: now.Add(-48 * time.Hour), Line: "expired"}}},
+	)
+	partiallyExpired := writeObject(t, bucket,
+		logproto.Stream{Labels: `{app="foo"}`, Entries: []logproto.Entry{{Timestamp: now.Add(-30 * time.Hour), Line: "expired"}}},
+		logproto.Stream{Labels: `{app="bar"}`, Entries: []logproto.Entry{{Timestamp: now.Add(-30 * time.Hour), Line: "retained"}}},
+	)
+
+	c, err := New(Config{
+		BuilderConfig:      builderCfg,
+		UploaderConfig:     uploader.Config{SHAPrefixSize: 2},
+		CompactionInterval: time.Minute,
+		SmallObjectSize:    1 << 20,
+		MinObjects:         100,
+		DeleteDelay:        time.Hour,
+		RetentionEnabled:   true,
+		LockLeaseDuration:  time.Minute,
+	}, metastore.Config{}, indexCfg, bucket, producer, limits, nil, prometheus.NewRegistry(), log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, c.Compact(context.Background()))
+
+	windows, err := metastore.Windows(ctx, bucket, tenantID)
+	require.NoError(t, err)
+	objects := allObjects(ctx, t, bucket, windows)
+	require.Len(t, objects, 1)
+	require.NotContains(t, []string{expired, partiallyExpired}, objects[0])
+
+	// Only the rewritten object is announced to the index builder, which
+	// skips the events of the dropped and replaced objects.
+	events := readEvents(t, addr, 1)
+	require.Equal(t, objects[0], events[0].ObjectPath)
+}
+
+// allObjects returns the paths of the objects listed in the metastore windows
+// of bucket.
+func allObjects(ctx context.Context, t *testing.T, bucket objstore.Bucket, windows []time.Time) []string {
+	t.Helper()
+
+	var paths []string
+	for _, window := range windows {
+		objects, err := metastore.NewObjectMetastore(metastore.Config{}, bucket, log.NewNopLogger(), nil).WindowObjects(ctx, window)
+		require.NoError(t, err)
+		for _, obj := range objects {
+			if !slices.Contains(paths, obj.Path) {
+				paths = append(paths, obj.Path)
+			}
+		}
+	}
+	return paths
+}
+
+func TestNeedsRetention(t *testing.T) {
+	var (
+		now  = time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+		day  = 24 * time.Hour
+		info = metastore.ObjectInfo{MinTimestamp: now.Add(-3 * day), MaxTimestamp: now.Add(-2 * day)}
+		req  = deletion.DeleteRequest{
+			RequestID: "1",
+			StartTime: model.TimeFromUnixNano(now.Add(-5 * day).UnixNano()),
+			EndTime:   model.TimeFromUnixNano(now.Add(-4 * day).UnixNano()),
+		}
+	)
+
+	for _, tc := range []struct {
+		name     string
+		progress objectProgress
+		periods  []time.Duration
+		requests []deletion.DeleteRequest
+		expected bool
+	}{
+		{name: "no retention", expected: false},
+		{name: "not expired", periods: []time.Duration{4 * day}, expected: false},
+		{name: "expired", periods: []time.Duration{2*day + time.Hour}, expected: true},
+		{
+			name:     "checked after expiry",
+			progress: objectProgress{Checked: now.Add(-time.Hour)},
+			periods:  []time.Duration{day},
+			expected: false,
+		},
+		{
+			name:     "expired since last check",
+			progress: objectProgress{Checked: now.Add(-2 * day)},
+			periods:  []time.Duration{day},
+			expected: true,
+		},
+		{name: "request before object", requests: []deletion.DeleteRequest{req}, expected: false},
+		{
+			name: "request overlapping object",
+			requests: []deletion.DeleteRequest{{
+				RequestID: "2",
+				StartTime: model.TimeFromUnixNano(now.Add(-5 * day).UnixNano()),
+				EndTime:   model.TimeFromUnixNano(now.UnixNano()),
+			}},
+			expected: true,
+		},
+		{
+			name:     "request already applied",
+			progress: objectProgress{Requests: []string{"2"}},
+			requests: []deletion.DeleteRequest{{
+				RequestID: "2",
+				StartTime: model.TimeFromUnixNano(now.Add(-5 * day).UnixNano()),
+				EndTime:   model.TimeFromUnixNano(now.UnixNano()),
+			}},
+			expected: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, needsRetention(info, tc.progress, now, tc.periods, tc.requests))
+		})
+	}
+}
+
+// countingBucket counts the reads of data objects.
+type countingBucket struct {
+	objstore.Bucket
+	reads int
+}
+
+func (b *countingBucket) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	if strings.HasPrefix(name, "tenant-"+tenantID+"/objects/") {
+		b.reads++
+	}
+	return b.Bucket.Get(ctx, name)
+}
+
+func (b *countingBucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	if strings.HasPrefix(name, "tenant-"+tenantID+"/objects/") {
+		b.reads++
+	}
+	return b.Bucket.GetRange(ctx, name, off, length)
+}
+
+type fakeLimits struct {
+	retentionPeriod time.Duration
+	streamRetention []validation.StreamRetention
+}
+
+func (f fakeLimits) RetentionPeriod(_ string) time.Duration { return f.retentionPeriod }
+
+func (f fakeLimits) StreamRetention(_ string) []validation.StreamRetention {
+	return f.streamRetention
+}
+
+func (f fakeLimits) AllByUserID() map[string]*validation.Limits { return nil }
+
+func (f fakeLimits) DefaultLimits() *validation.Limits { return &validation.Limits{} }
+
+func (f fakeLimits) PoliciesStreamMapping(_ string) validation.PolicyStreamMapping { return nil }
+
+func (f fakeLimits) DeletionMode(_ string) string { return deletionmode.FilterAndDelete.String() }
+
+type fakeDeleteRequests []deletion.DeleteRequest
+
+func (f fakeDeleteRequests) GetAllDeleteRequestsForUser(_ context.Context, _ string, _ bool) ([]deletion.DeleteRequest, error) {
+	return slices.Clone(f), nil
+}
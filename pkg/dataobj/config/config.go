@@ -3,6 +3,7 @@ package config
 import (
 	"flag"
 
+	"github.com/grafana/loki/v3/pkg/dataobj/compactor"
 	"github.com/grafana/loki/v3/pkg/dataobj/consumer"
 	"github.com/grafana/loki/v3/pkg/dataobj/index"
 	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
@@ -12,6 +13,7 @@ import (
 type Config struct {
 	Consumer  consumer.Config  `yaml:"consumer"`
 	Index     index.Config     `yaml:"index"`
+	Compactor compactor.Config `yaml:"compactor"`
 	Metastore metastore.Config `yaml:"metastore"`
 	Querier   querier.Config   `yaml:"querier"`
 	// StorageBucketPrefix is the prefix to use for the storage bucket.
@@ -21,6 +23,7 @@ type Config struct {
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.Consumer.RegisterFlags(f)
 	cfg.Index.RegisterFlags(f)
+	cfg.Compactor.RegisterFlags(f)
 	cfg.Metastore.RegisterFlags(f)
 	cfg.Querier.RegisterFlags(f)
 	f.StringVar(&cfg.StorageBucketPrefix, "dataobj-storage-bucket-prefix", "dataobj/", "The prefix to use for the storage bucket.")
@@ -30,6 +33,9 @@ func (cfg *Config) Validate() error {
 	if err := cfg.Consumer.Validate(); err != nil {
 		return err
 	}
+	if err := cfg.Compactor.Validate(); err != nil {
+		return err
+	}
 	if err := cfg.Querier.Validate(); err != nil {
 		return err
 	}
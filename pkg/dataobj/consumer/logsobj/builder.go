@@ -61,6 +61,10 @@ type BuilderConfig struct {
 	// values of MergeSize trade off lower memory overhead for higher time spent
 	// merging.
 	SectionStripeMergeLimit int `yaml:"section_stripe_merge_limit"`
+
+	// DictionaryEncoding enables dictionary encoding for structured metadata
+	// values in logs sections.
+	DictionaryEncoding bool `yaml:"dictionary_encoding"`
 }
 
 // RegisterFlagsWithPrefix registers flags with the given prefix.
@@ -75,6 +79,7 @@ func (cfg *BuilderConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet
 	f.Var(&cfg.TargetSectionSize, prefix+"target-section-size", "The target maximum amount of uncompressed data to hold in sections, for sections that support being limited by size. Uncompressed size is used for consistent I/O and planning.")
 	f.Var(&cfg.BufferSize, prefix+"buffer-size", "The size of logs to buffer in memory before adding into columnar builders, used to reduce CPU load of sorting.")
 	f.IntVar(&cfg.SectionStripeMergeLimit, prefix+"section-stripe-merge-limit", 2, "The maximum number of log section stripes to merge into a section at once. Must be greater than 1.")
+	f.BoolVar(&cfg.DictionaryEncoding, prefix+"dictionary-encoding", false, "Experimental: Store structured metadata values of logs sections with per-page dictionaries, which shrinks repetitive values and lets queries skip pages without matching values. Pages with many distinct values keep plain encoding. Queriers must support dictionary encoding before it is enabled.")
 }
 
 // Validate validates the BuilderConfig.
@@ -162,9 +167,10 @@ func NewBuilder(cfg BuilderConfig) (*Builder, error) {
 		builder: dataobj.NewBuilder(),
 		streams: streams.NewBuilder(metrics.streams, int(cfg.TargetPageSize)),
 		logs: logs.NewBuilder(metrics.logs, logs.BuilderOptions{
-			PageSizeHint:     int(cfg.TargetPageSize),
-			BufferSize:       int(cfg.BufferSize),
-			StripeMergeLimit: cfg.SectionStripeMergeLimit,
+			PageSizeHint:       int(cfg.TargetPageSize),
+			BufferSize:         int(cfg.BufferSize),
+			StripeMergeLimit:   cfg.SectionStripeMergeLimit,
+			DictionaryEncoding: cfg.DictionaryEncoding,
 		}),
 	}, nil
 }
@@ -142,3 +142,7 @@ func (o *Object) init(ctx context.Context) error {
 // Sections returns the list of sections available in the Object. The slice of
 // returned sections must not be mutated.
 func (o *Object) Sections() Sections { return o.sections }
+
+// MetadataSize returns the encoded size in bytes of the metadata of the
+// Object, which is kept in memory while the Object is open.
+func (o *Object) MetadataSize() int { return o.metadata.Size() }
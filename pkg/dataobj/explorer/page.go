@@ -0,0 +1,114 @@
+package explorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// PageResponse holds the decoded rows of a single page of a column, along
+// with the encoding and compression statistics of the page.
+type PageResponse struct {
+	Column           ColumnWithPages `json:"column"`
+	Page             PageInfo        `json:"page"`
+	CompressionRatio float64         `json:"compression_ratio"`
+	NullsCount       uint64          `json:"nulls_count"`
+	Values           []any           `json:"values"`
+	Offset           int             `json:"offset"`
+	HasMore          bool            `json:"hasMore"`
+}
+
+// handlePage decodes a single page of a column in a data object.
+//
+// The page is selected by the index of its section in the data object, the
+// index of its column in the section, and its index in the column, in the
+// order returned by the inspect endpoint. The offset and limit parameters
+// page through the rows of the page.
+func (s *Service) handlePage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filename := r.URL.Query().Get("file")
+	if filename == "" {
+		http.Error(w, "file parameter is required", http.StatusBadRequest)
+		return
+	}
+	var indexes [3]int
+	for i, name := range []string{"section", "column", "page"} {
+		v, err := intParam(r, name, -1)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if v < 0 {
+			http.Error(w, fmt.Sprintf("%s parameter is required", name), http.StatusBadRequest)
+			return
+		}
+		indexes[i] = v
+	}
+	sectionIndex, columnIndex, pageIndex := indexes[0], indexes[1], indexes[2]
+
+	offset, limit, err := pagingParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sec, err := openBrowseSection(r.Context(), s.bucket, filename, sectionIndex)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	meta, err := sec.inspect(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to inspect section: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if len(meta.Columns) != sec.decodableColumns {
+		http.Error(w, "section contains columns of unknown types and can't be decoded", http.StatusBadRequest)
+		return
+	}
+	if columnIndex >= len(meta.Columns) {
+		http.Error(w, fmt.Sprintf("column %d out of range: section has %d columns", columnIndex, len(meta.Columns)), http.StatusBadRequest)
+		return
+	}
+	column := meta.Columns[columnIndex]
+	if pageIndex >= len(column.Pages) {
+		http.Error(w, fmt.Sprintf("page %d out of range: column has %d pages", pageIndex, len(column.Pages)), http.StatusBadRequest)
+		return
+	}
+
+	values, err := sec.decodePage(r.Context(), columnIndex, pageIndex)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode page: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	page := column.Pages[pageIndex]
+	resp := PageResponse{
+		Column:     column,
+		Page:       page,
+		NullsCount: page.RowsCount - page.ValuesCount,
+		Values:     []any{},
+		Offset:     offset,
+	}
+	resp.Column.Pages = nil
+	if page.CompressedSize > 0 {
+		resp.CompressionRatio = float64(page.UncompressedSize) / float64(page.CompressedSize)
+	}
+
+	if offset < len(values) {
+		end := min(offset+limit, len(values))
+		for _, v := range values[offset:end] {
+			resp.Values = append(resp.Values, displayValue(v))
+		}
+		resp.HasMore = end < len(values)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
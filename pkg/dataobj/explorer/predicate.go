@@ -0,0 +1,144 @@
+package explorer
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Operators supported by column predicates.
+const (
+	opEqual        = "="
+	opNotEqual     = "!="
+	opRegexp       = "=~"
+	opNotRegexp    = "!~"
+	opGreater      = ">"
+	opGreaterEqual = ">="
+	opLess         = "<"
+	opLessEqual    = "<="
+)
+
+var predicateRegexp = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_.]*)\s*(=~|!~|!=|>=|<=|=|>|<)\s*(.*?)\s*$`)
+
+// columnPredicate filters decoded rows by the value of a single column, such
+// as stream_id=5, label.app=~"api|web" or timestamp>=2025-01-01T00:00:00Z.
+//
+// Integer and timestamp columns are compared by value; timestamps may be
+// given in RFC3339 or as Unix nanoseconds. Regular expressions are fully
+// anchored and match against the string form of a value. Like label
+// matchers, missing values are treated as empty strings by the equality and
+// regular expression operators, but never match the ordering operators.
+type columnPredicate struct {
+	column string
+	op     string
+	value  string
+	re     *regexp.Regexp
+}
+
+// parsePredicate parses a predicate of the form <column><op><value>. The
+// value may be a double-quoted Go string.
+func parsePredicate(s string) (*columnPredicate, error) {
+	m := predicateRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("invalid predicate %q: expected <column><op><value>", s)
+	}
+
+	p := &columnPredicate{column: m[1], op: m[2], value: m[3]}
+	if strings.HasPrefix(p.value, `"`) {
+		value, err := strconv.Unquote(p.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid predicate %q: %w", s, err)
+		}
+		p.value = value
+	}
+
+	if p.op == opRegexp || p.op == opNotRegexp {
+		re, err := regexp.Compile("^(?:" + p.value + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid predicate %q: %w", s, err)
+		}
+		p.re = re
+	}
+	return p, nil
+}
+
+// matches returns true if r passes the predicate.
+func (p *columnPredicate) matches(r row) bool {
+	v := r[p.column]
+
+	switch p.op {
+	case opRegexp:
+		return p.re.MatchString(stringValue(v))
+	case opNotRegexp:
+		return !p.re.MatchString(stringValue(v))
+	case opEqual, opNotEqual:
+		c, ok := p.compare(v)
+		if !ok {
+			c = strings.Compare(stringValue(v), p.value)
+		}
+		return (c == 0) == (p.op == opEqual)
+	}
+
+	c, ok := p.compare(v)
+	if !ok {
+		return false
+	}
+	switch p.op {
+	case opGreater:
+		return c > 0
+	case opGreaterEqual:
+		return c >= 0
+	case opLess:
+		return c < 0
+	case opLessEqual:
+		return c <= 0
+	}
+	return false
+}
+
+// compare compares v with the value of the predicate. compare returns false
+// if v is missing or the value of the predicate can't be converted to the type
+// of v.
+func (p *columnPredicate) compare(v any) (int, bool) {
+	switch v := v.(type) {
+	case string:
+		return strings.Compare(v, p.value), true
+	case int64:
+		other, err := strconv.ParseInt(p.value, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		return cmp.Compare(v, other), true
+	case time.Time:
+		other, err := parseTimestamp(p.value)
+		if err != nil {
+			return 0, false
+		}
+		return v.Compare(other), true
+	}
+	return 0, false
+}
+
+func parseTimestamp(s string) (time.Time, error) {
+	if ns, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(0, ns), nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// stringValue returns the string form of a decoded value, or an empty string
+// if v is missing.
+func stringValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return fmt.Sprint(displayValue(v))
+}
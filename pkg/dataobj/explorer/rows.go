@@ -0,0 +1,146 @@
+package explorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+const (
+	defaultRowsLimit = 100
+	maxRowsLimit     = 1000
+)
+
+// RowsResponse holds a page of decoded rows of a section.
+type RowsResponse struct {
+	Columns []string         `json:"columns"`
+	Rows    []map[string]any `json:"rows"`
+	Offset  int              `json:"offset"`
+	HasMore bool             `json:"hasMore"`
+}
+
+// handleRows returns the decoded rows of a single section of a data object.
+//
+// The section is selected by its index in the data object. Rows are filtered
+// by any number of predicate parameters, which must all match; see
+// [columnPredicate] for the supported syntax. The offset and limit parameters
+// page through the matching rows.
+func (s *Service) handleRows(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	filename := query.Get("file")
+	if filename == "" {
+		http.Error(w, "file parameter is required", http.StatusBadRequest)
+		return
+	}
+	sectionIndex, err := intParam(r, "section", -1)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	} else if sectionIndex < 0 {
+		http.Error(w, "section parameter is required", http.StatusBadRequest)
+		return
+	}
+	offset, limit, err := pagingParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sec, err := openBrowseSection(r.Context(), s.bucket, filename, sectionIndex)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var predicates []*columnPredicate
+	for _, param := range query["predicate"] {
+		p, err := parsePredicate(param)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !slices.Contains(sec.columns, p.column) {
+			http.Error(w, fmt.Sprintf("invalid predicate %q: unknown column %s", param, p.column), http.StatusBadRequest)
+			return
+		}
+		predicates = append(predicates, p)
+	}
+
+	resp := RowsResponse{
+		Columns: sec.columns,
+		Rows:    []map[string]any{},
+		Offset:  offset,
+	}
+
+	var skipped int
+	err = sec.rows(r.Context(), func(row row) bool {
+		for _, p := range predicates {
+			if !p.matches(row) {
+				return true
+			}
+		}
+
+		switch {
+		case skipped < offset:
+			skipped++
+			return true
+		case len(resp.Rows) == limit:
+			resp.HasMore = true
+			return false
+		}
+
+		out := make(map[string]any, len(row))
+		for column, value := range row {
+			out[column] = displayValue(value)
+		}
+		resp.Rows = append(resp.Rows, out)
+		return true
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read rows: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// intParam returns the value of the integer query parameter name, or def if
+// the parameter is not set.
+func intParam(r *http.Request, name string, def int) (int, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s parameter: %w", name, err)
+	}
+	return v, nil
+}
+
+// pagingParams returns the offset and limit query parameters.
+func pagingParams(r *http.Request) (offset, limit int, err error) {
+	if offset, err = intParam(r, "offset", 0); err != nil {
+		return 0, 0, err
+	} else if offset < 0 {
+		return 0, 0, fmt.Errorf("offset parameter must not be negative")
+	}
+
+	if limit, err = intParam(r, "limit", defaultRowsLimit); err != nil {
+		return 0, 0, err
+	} else if limit <= 0 || limit > maxRowsLimit {
+		return 0, 0, fmt.Errorf("limit parameter must be between 1 and %d", maxRowsLimit)
+	}
+	return offset, limit, nil
+}
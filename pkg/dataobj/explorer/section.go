@@ -0,0 +1,320 @@
+package explorer
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/loki/v3/pkg/dataobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/indexpointers"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/logs"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/ngrams"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/pointers"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
+)
+
+// Prefixes of the row columns which hold the labels of a stream and the
+// structured metadata of a log record.
+const (
+	labelColumnPrefix    = "label."
+	metadataColumnPrefix = "metadata."
+)
+
+// row is a decoded row of a section, keyed by column name. Values are
+// strings, int64s, time.Times or byte slices; missing values are nil.
+type row map[string]any
+
+// browseSection provides access to the decoded rows and pages of a section.
+type browseSection struct {
+	// columns holds the names of the columns of rows, in display order.
+	columns []string
+
+	// rows calls yield for each row of the section, in the order the rows are
+	// stored, until yield returns false.
+	rows func(ctx context.Context, yield func(row) bool) error
+
+	// inspect returns the metadata of the section. Unless the section has
+	// columns of unknown types, the columns of the metadata are indexed the
+	// same way as for decodePage.
+	inspect func(ctx context.Context) (SectionMetadata, error)
+
+	// decodePage decodes the rows of a single page of a column.
+	decodePage func(ctx context.Context, column, page int) ([]any, error)
+
+	// decodableColumns is the number of columns of a known type. Columns of
+	// unknown types can't be decoded and shift the column indexes of
+	// decodePage.
+	decodableColumns int
+}
+
+// openBrowseSection opens the section at index in the data object at path.
+func openBrowseSection(ctx context.Context, bucket objstore.BucketReader, path string, index int) (*browseSection, error) {
+	obj, err := dataobj.FromBucket(ctx, bucket, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sections: %w", err)
+	}
+
+	sections := obj.Sections()
+	if index < 0 || index >= len(sections) {
+		return nil, fmt.Errorf("section %d out of range: object has %d sections", index, len(sections))
+	}
+	section := sections[index]
+
+	switch {
+	case streams.CheckSection(section):
+		sec, err := streams.Open(ctx, section)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open streams section: %w", err)
+		}
+		return browseStreamsSection(section.Type, sec), nil
+	case logs.CheckSection(section):
+		sec, err := logs.Open(ctx, section)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open logs section: %w", err)
+		}
+		return browseLogsSection(section.Type, sec), nil
+	case pointers.CheckSection(section):
+		sec, err := pointers.Open(ctx, section)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open pointers section: %w", err)
+		}
+		return browsePointersSection(section.Type, sec), nil
+	case indexpointers.CheckSection(section):
+		sec, err := indexpointers.Open(ctx, section)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open index pointers section: %w", err)
+		}
+		return browseIndexPointersSection(section.Type, sec), nil
+	case ngrams.CheckSection(section):
+		sec, err := ngrams.Open(ctx, section)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open ngrams section: %w", err)
+		}
+		return browseNgramsSection(section.Type, sec), nil
+	}
+	return nil, fmt.Errorf("unsupported section type %s", section.Type)
+}
+
+func browseStreamsSection(ty dataobj.SectionType, sec *streams.Section) *browseSection {
+	columns := []string{"id", "min_timestamp", "max_timestamp", "uncompressed_size", "rows"}
+	for _, col := range sec.Columns() {
+		if col.Type == streams.ColumnTypeLabel {
+			columns = append(columns, labelColumnPrefix+col.Name)
+		}
+	}
+
+	return &browseSection{
+		columns: columns,
+		rows: func(ctx context.Context, yield func(row) bool) error {
+			for res := range streams.IterSection(ctx, sec) {
+				stream, err := res.Value()
+				if err != nil {
+					return err
+				}
+				r := row{
+					"id":                stream.ID,
+					"min_timestamp":     stream.MinTimestamp.UTC(),
+					"max_timestamp":     stream.MaxTimestamp.UTC(),
+					"uncompressed_size": stream.UncompressedSize,
+					"rows":              int64(stream.Rows),
+				}
+				addLabels(r, labelColumnPrefix, stream.Labels)
+				if !yield(r) {
+					return nil
+				}
+			}
+			return nil
+		},
+		inspect: func(ctx context.Context) (SectionMetadata, error) {
+			return inspectStreamsSection(ctx, ty, sec)
+		},
+		decodePage: func(ctx context.Context, column, page int) ([]any, error) {
+			return streams.DecodePage(ctx, sec, column, page)
+		},
+		decodableColumns: len(sec.Columns()),
+	}
+}
+
+func browseLogsSection(ty dataobj.SectionType, sec *logs.Section) *browseSection {
+	columns := []string{"stream_id", "timestamp"}
+	for _, col := range sec.Columns() {
+		if col.Type == logs.ColumnTypeMetadata {
+			columns = append(columns, metadataColumnPrefix+col.Name)
+		}
+	}
+	columns = append(columns, "message")
+
+	return &browseSection{
+		columns: columns,
+		rows: func(ctx context.Context, yield func(row) bool) error {
+			for res := range logs.IterSection(ctx, sec) {
+				record, err := res.Value()
+				if err != nil {
+					return err
+				}
+				r := row{
+					"stream_id": record.StreamID,
+					"timestamp": record.Timestamp.UTC(),
+					"message":   string(record.Line),
+				}
+				addLabels(r, metadataColumnPrefix, record.Metadata)
+				if !yield(r) {
+					return nil
+				}
+			}
+			return nil
+		},
+		inspect: func(ctx context.Context) (SectionMetadata, error) {
+			return inspectLogsSection(ctx, ty, sec)
+		},
+		decodePage: func(ctx context.Context, column, page int) ([]any, error) {
+			return logs.DecodePage(ctx, sec, column, page)
+		},
+		decodableColumns: len(sec.Columns()),
+	}
+}
+
+func browsePointersSection(ty dataobj.SectionType, sec *pointers.Section) *browseSection {
+	return &browseSection{
+		columns: []string{
+			"path", "section", "pointer_kind", "stream_id", "stream_id_ref",
+			"min_timestamp", "max_timestamp", "row_count", "uncompressed_size",
+			"column_index", "column_name", "values_bloom_filter",
+		},
+		rows: func(ctx context.Context, yield func(row) bool) error {
+			for res := range pointers.IterSection(ctx, sec) {
+				pointer, err := res.Value()
+				if err != nil {
+					return err
+				}
+				r := row{
+					"path":                pointer.Path,
+					"section":             pointer.Section,
+					"pointer_kind":        pointerKindName(pointer.PointerKind),
+					"stream_id":           pointer.StreamID,
+					"stream_id_ref":       pointer.StreamIDRef,
+					"min_timestamp":       pointer.StartTs.UTC(),
+					"max_timestamp":       pointer.EndTs.UTC(),
+					"row_count":           pointer.LineCount,
+					"uncompressed_size":   pointer.UncompressedSize,
+					"column_index":        pointer.ColumnIndex,
+					"column_name":         pointer.ColumnName,
+					"values_bloom_filter": pointer.ValuesBloomFilter,
+				}
+				if !yield(r) {
+					return nil
+				}
+			}
+			return nil
+		},
+		inspect: func(ctx context.Context) (SectionMetadata, error) {
+			return inspectPointersSection(ctx, ty, sec)
+		},
+		decodePage: func(ctx context.Context, column, page int) ([]any, error) {
+			return pointers.DecodePage(ctx, sec, column, page)
+		},
+		decodableColumns: len(sec.Columns()),
+	}
+}
+
+func pointerKindName(kind pointers.PointerKind) string {
+	switch kind {
+	case pointers.PointerKindStreamIndex:
+		return "stream_index"
+	case pointers.PointerKindColumnIndex:
+		return "column_index"
+	}
+	return "invalid"
+}
+
+func browseIndexPointersSection(ty dataobj.SectionType, sec *indexpointers.Section) *browseSection {
+	return &browseSection{
+		columns: []string{"path", "min_timestamp", "max_timestamp"},
+		rows: func(ctx context.Context, yield func(row) bool) error {
+			for res := range indexpointers.IterSection(ctx, sec) {
+				pointer, err := res.Value()
+				if err != nil {
+					return err
+				}
+				r := row{
+					"path":          pointer.Path,
+					"min_timestamp": pointer.StartTs.UTC(),
+					"max_timestamp": pointer.EndTs.UTC(),
+				}
+				if !yield(r) {
+					return nil
+				}
+			}
+			return nil
+		},
+		inspect: func(ctx context.Context) (SectionMetadata, error) {
+			return inspectIndexPointersSection(ctx, ty, sec)
+		},
+		decodePage: func(ctx context.Context, column, page int) ([]any, error) {
+			return indexpointers.DecodePage(ctx, sec, column, page)
+		},
+		decodableColumns: len(sec.Columns()),
+	}
+}
+
+func browseNgramsSection(ty dataobj.SectionType, sec *ngrams.Section) *browseSection {
+	return &browseSection{
+		columns: []string{"path", "section", "stream_id", "ngram_bloom_filter"},
+		rows: func(ctx context.Context, yield func(row) bool) error {
+			for res := range ngrams.IterSection(ctx, sec) {
+				stream, err := res.Value()
+				if err != nil {
+					return err
+				}
+				r := row{
+					"path":               stream.Path,
+					"section":            stream.Section,
+					"stream_id":          stream.StreamID,
+					"ngram_bloom_filter": stream.NgramBloomFilter,
+				}
+				if !yield(r) {
+					return nil
+				}
+			}
+			return nil
+		},
+		inspect: func(ctx context.Context) (SectionMetadata, error) {
+			return inspectNgramsSection(ctx, ty, sec)
+		},
+		decodePage: func(ctx context.Context, column, page int) ([]any, error) {
+			return ngrams.DecodePage(ctx, sec, column, page)
+		},
+		decodableColumns: len(sec.Columns()),
+	}
+}
+
+func addLabels(r row, prefix string, lbls labels.Labels) {
+	lbls.Range(func(l labels.Label) {
+		r[prefix+l.Name] = l.Value
+	})
+}
+
+// displayValue converts a decoded value into a value for a JSON response.
+// Timestamps are formatted as RFC3339, and integers are formatted as strings
+// to retain their precision in JavaScript. Byte slices which aren't valid
+// UTF-8, such as bloom filters, are replaced by a description of their size.
+func displayValue(v any) any {
+	switch v := v.(type) {
+	case int64:
+		return fmt.Sprint(v)
+	case uint64:
+		return fmt.Sprint(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case []byte:
+		if !utf8.Valid(v) {
+			return fmt.Sprintf("<%d bytes>", len(v))
+		}
+		return string(v)
+	}
+	return v
+}
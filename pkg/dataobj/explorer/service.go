@@ -41,6 +41,8 @@ func (s *Service) Handler() (string, http.Handler) {
 	mux.HandleFunc("/dataobj/api/v1/list", s.handleList)
 	mux.HandleFunc("/dataobj/api/v1/inspect", s.handleInspect)
 	mux.HandleFunc("/dataobj/api/v1/download", s.handleDownload)
+	mux.HandleFunc("/dataobj/api/v1/rows", s.handleRows)
+	mux.HandleFunc("/dataobj/api/v1/page", s.handlePage)
 	mux.HandleFunc("/dataobj/api/v1/provider", s.handleProvider)
 
 	return "/dataobj", mux
@@ -19,6 +19,7 @@ import (
 	"github.com/grafana/dskit/flagext"
 	"github.com/grafana/dskit/multierror"
 	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/thanos-io/objstore"
 	"github.com/twmb/franz-go/pkg/kgo"
@@ -26,6 +27,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/dataobj"
 	"github.com/grafana/loki/v3/pkg/dataobj/index/indexobj"
 	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
 	"github.com/grafana/loki/v3/pkg/kafka"
 	"github.com/grafana/loki/v3/pkg/kafka/client"
 )
@@ -51,7 +53,10 @@ func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
 type downloadedObject struct {
 	event       metastore.ObjectWrittenEvent
 	objectBytes *[]byte
-	err         error
+	// deleted is set if the object no longer exists, e.g. because it was
+	// compacted.
+	deleted bool
+	err     error
 }
 
 const (
@@ -78,6 +83,7 @@ type Builder struct {
 	// Builder initialization
 	builderCfg  indexobj.BuilderConfig
 	bucket      objstore.Bucket
+	metastore   *metastore.ObjectMetastore
 	flushBuffer *bytes.Buffer
 
 	// Metrics
@@ -151,6 +157,7 @@ func NewIndexBuilder(
 		client:            eventConsumerClient,
 		logger:            logger,
 		bucket:            bucket,
+		metastore:         metastore.NewObjectMetastore(mCfg, bucket, logger, nil),
 		flushBuffer:       flushBuffer,
 		downloadedObjects: downloadedObjects,
 		downloadQueue:     downloadQueue,
@@ -176,7 +183,13 @@ func (p *Builder) run(ctx context.Context) error {
 			downloadStart := time.Now()
 
 			objectReader, err := p.bucket.Get(p.ctx, event.ObjectPath)
-			if err != nil {
+			if p.bucket.IsObjNotFoundErr(err) {
+				p.downloadedObjects <- downloadedObject{
+					event:   event,
+					deleted: true,
+				}
+				continue
+			} else if err != nil {
 				p.downloadedObjects <- downloadedObject{
 					event: event,
 					err:   fmt.Errorf("failed to fetch object from storage: %w", err),
@@ -281,12 +294,19 @@ func (p *Builder) buildIndex(events []metastore.ObjectWrittenEvent) error {
 	}
 
 	// Process the results as they are downloaded
-	processingErrors := multierror.New()
+	var (
+		processingErrors = multierror.New()
+		indexed          int
+	)
 	for i := 0; i < len(events); i++ {
 		obj := <-p.downloadedObjects
 		objLogger := log.With(p.logger, "object_path", obj.event.ObjectPath)
 		level.Info(objLogger).Log("msg", "processing object")
 
+		if obj.deleted {
+			level.Info(objLogger).Log("msg", "skipping deleted object")
+			continue
+		}
 		if obj.err != nil {
 			processingErrors.Add(fmt.Errorf("failed to download object: %w", obj.err))
 			continue
@@ -298,15 +318,32 @@ func (p *Builder) buildIndex(events []metastore.ObjectWrittenEvent) error {
 			continue
 		}
 
+		// Objects which were replaced by the compactor are still around
+		// until their delete delay has passed. They must not be indexed, as
+		// the index objects of the replacing object already cover their logs.
+		listed, err := p.listedInMetastore(p.ctx, reader, obj.event)
+		if err != nil {
+			processingErrors.Add(fmt.Errorf("failed to look up object in metastore: %w", err))
+			continue
+		} else if !listed {
+			level.Info(objLogger).Log("msg", "skipping object which is no longer listed in the metastore")
+			continue
+		}
+
 		if err := p.calculator.Calculate(p.ctx, objLogger, reader, obj.event.ObjectPath); err != nil {
 			processingErrors.Add(fmt.Errorf("failed to calculate index: %w", err))
 			continue
 		}
+		indexed++
 	}
 
 	if processingErrors.Err() != nil {
 		return processingErrors.Err()
 	}
+	if indexed == 0 {
+		level.Info(p.logger).Log("msg", "no objects left to index", "tenant", events[0].Tenant, "events", len(events))
+		return nil
+	}
 
 	p.flushBuffer.Reset()
 	stats, err := p.calculator.Flush(p.flushBuffer)
@@ -333,6 +370,33 @@ func (p *Builder) buildIndex(events []metastore.ObjectWrittenEvent) error {
 	return nil
 }
 
+// listedInMetastore reports whether the data object of event is still listed
+// in the metastore. Objects are listed in every window they span, so only the
+// window of the first log of obj is checked.
+func (p *Builder) listedInMetastore(ctx context.Context, obj *dataobj.Object, event metastore.ObjectWrittenEvent) (bool, error) {
+	var minTimestamp time.Time
+	for res := range streams.Iter(ctx, obj) {
+		stream, err := res.Value()
+		if err != nil {
+			return false, fmt.Errorf("reading streams: %w", err)
+		}
+		if minTimestamp.IsZero() || stream.MinTimestamp.Before(minTimestamp) {
+			minTimestamp = stream.MinTimestamp
+		}
+	}
+	if minTimestamp.IsZero() {
+		return false, nil
+	}
+
+	objects, err := p.metastore.WindowObjects(user.InjectOrgID(ctx, event.Tenant), minTimestamp.Truncate(metastore.WindowSize))
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(objects, func(info metastore.ObjectInfo) bool {
+		return info.Path == event.ObjectPath
+	}), nil
+}
+
 // ObjectKey determines the key in object storage to upload the object to, based on our path scheme.
 func ObjectKey(tenantID string, object *bytes.Buffer) string {
 	sum := sha256.Sum224(object.Bytes())
@@ -19,6 +19,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/dataobj/index/indexobj"
 	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/pointers"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
 	"github.com/grafana/loki/v3/pkg/kafka"
 	"github.com/grafana/loki/v3/pkg/kafka/testkafka"
 	"github.com/grafana/loki/v3/pkg/logproto"
@@ -34,6 +35,8 @@ var testBuilderConfig = indexobj.BuilderConfig{
 	SectionStripeMergeLimit: 2,
 }
 
+// buildLogObject uploads a logs object to path and registers it in the
+// metastore of the tenant test-tenant, like the consumer does.
 func buildLogObject(t *testing.T, app string, path string, bucket objstore.Bucket) {
 	candidate, err := logsobj.NewBuilder(logsobj.BuilderConfig{
 		TargetPageSize:    128 * 1024,
@@ -55,11 +58,14 @@ func buildLogObject(t *testing.T, app string, path string, bucket objstore.Bucke
 	}
 
 	buf := bytes.NewBuffer(nil)
-	_, err = candidate.Flush(buf)
+	stats, err := candidate.Flush(buf)
 	require.NoError(t, err)
 
 	err = bucket.Upload(context.Background(), path, buf)
 	require.NoError(t, err)
+
+	err = metastore.NewUpdater(metastore.UpdaterConfig{}, bucket, "test-tenant", log.NewNopLogger()).Update(context.Background(), path, stats.MinTimestamp, stats.MaxTimestamp)
+	require.NoError(t, err)
 }
 
 func TestIndexBuilder(t *testing.T) {
@@ -125,6 +131,83 @@ func TestIndexBuilder(t *testing.T) {
 	require.Equal(t, 30, len(indexes))
 }
 
+func TestIndexBuilder_SkipsRemovedObjects(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	bucket := objstore.NewInMemBucket()
+
+	cluster, configString := testkafka.CreateClusterWithoutCustomConsumerGroupsSupport(t, 1, "loki.metastore-events")
+	defer cluster.Close()
+
+	client, err := kgo.NewClient(kgo.ConsumerGroup("test-consumer-group"), kgo.ConsumeTopics("loki.metastore-events"), kgo.SeedBrokers(configString))
+	require.NoError(t, err)
+
+	indexPrefix := "test-prefix"
+	tenant := "test-tenant"
+	p, err := NewIndexBuilder(
+		Config{
+			BuilderConfig:      testBuilderConfig,
+			EventsPerIndex:     3,
+			IndexStoragePrefix: indexPrefix,
+			EnabledTenantIDs:   []string{tenant},
+		},
+		metastore.Config{},
+		kafka.Config{},
+		log.NewNopLogger(),
+		"instance-id",
+		bucket,
+		prometheus.NewRegistry(),
+	)
+	require.NoError(t, err)
+	p.client = client
+	require.NoError(t, p.StartAsync(ctx))
+
+	buildLogObject(t, "loki", "test-path-0", bucket)
+	buildLogObject(t, "testing", "test-path-1", bucket)
+	buildLogObject(t, "three", "test-path-2", bucket)
+
+	// test-path-1 was compacted and deleted, test-path-2 was replaced but
+	// not deleted yet.
+	require.NoError(t, bucket.Delete(ctx, "test-path-1"))
+	obj, err := dataobj.FromBucket(ctx, bucket, "test-path-2")
+	require.NoError(t, err)
+	var minTs, maxTs time.Time
+	for res := range streams.Iter(ctx, obj) {
+		stream, err := res.Value()
+		require.NoError(t, err)
+		if minTs.IsZero() || stream.MinTimestamp.Before(minTs) {
+			minTs = stream.MinTimestamp
+		}
+		if stream.MaxTimestamp.After(maxTs) {
+			maxTs = stream.MaxTimestamp
+		}
+	}
+	require.NoError(t, metastore.NewUpdater(metastore.UpdaterConfig{}, bucket, tenant, log.NewNopLogger()).Replace(ctx, []string{"test-path-2"}, "", minTs, maxTs))
+
+	for i := 0; i < 3; i++ {
+		event := metastore.ObjectWrittenEvent{
+			ObjectPath: fmt.Sprintf("test-path-%d", i),
+			Tenant:     tenant,
+			WriteTime:  time.Now().Format(time.RFC3339),
+		}
+		eventBytes, err := event.Marshal()
+		require.NoError(t, err)
+
+		p.processRecord(&kgo.Record{
+			Key:   []byte(tenant),
+			Value: eventBytes,
+		})
+	}
+
+	// Only the streams of test-path-0 are indexed.
+	indexes := readAllSectionPointers(t, bucket, indexPrefix)
+	require.Equal(t, 10, len(indexes))
+	for _, pointer := range indexes {
+		require.Equal(t, "test-path-0", pointer.Path)
+	}
+}
+
 func readAllSectionPointers(t *testing.T, bucket objstore.Bucket, indexPrefix string) []pointers.SectionPointer {
 	var out []pointers.SectionPointer
 
@@ -19,6 +19,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/dataobj"
 	"github.com/grafana/loki/v3/pkg/dataobj/index/indexobj"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/logs"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/ngrams"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
 )
 
@@ -66,6 +67,7 @@ func (c *Calculator) Calculate(ctx context.Context, logger log.Logger, reader *d
 			sectionLogger := log.With(logger, "section", i)
 			// 1. A bloom filter for each column in the logs section.
 			// 2. A per-section stream time-range index using min/max of each stream in the logs section. StreamIDs will reference the aggregate stream section.
+			// 3. A bloom filter of the n-grams of the log lines of each stream in the logs section.
 			if err := c.processLogsSection(ctx, sectionLogger, objectPath, section, int64(i)); err != nil {
 				return fmt.Errorf("failed to process logs section path=%s section=%d: %w", objectPath, i, err)
 			}
@@ -138,6 +140,9 @@ func (c *Calculator) processLogsSection(ctx context.Context, sectionLogger log.L
 		columnIndexes[column.Name] = column.ColumnIndex
 	}
 
+	// N-gram sets are keyed by the stream ID in the logs object.
+	streamNgrams := make(map[int64]*ngrams.NgramSet)
+
 	// Read the whole logs section to extract all the column values.
 	cnt := 0
 	// TODO(benclive): Switch to a columnar reader instead of row based
@@ -158,6 +163,12 @@ func (c *Calculator) processLogsSection(ctx context.Context, sectionLogger log.L
 			log.Metadata.Range(func(md labels.Label) {
 				columnBloomBuilders[md.Name].Add([]byte(md.Value))
 			})
+			ngramSet, ok := streamNgrams[log.StreamID]
+			if !ok {
+				ngramSet = &ngrams.NgramSet{}
+				streamNgrams[log.StreamID] = ngramSet
+			}
+			ngramSet.AddLine(log.Line)
 			logsInfo[i].objectPath = objectPath
 			logsInfo[i].sectionIdx = sectionIdx
 			logsInfo[i].streamID = log.StreamID
@@ -191,6 +202,20 @@ func (c *Calculator) processLogsSection(ctx context.Context, sectionLogger log.L
 		}
 	}
 
+	// Write the n-gram bloom filters of each stream to the new index object.
+	for streamID, ngramSet := range streamNgrams {
+		bloomBytes, err := ngramSet.BloomFilter()
+		if err != nil {
+			return fmt.Errorf("failed to marshal n-gram bloom filter: %w", err)
+		}
+		c.builderMtx.Lock()
+		err = c.indexobjBuilder.AppendNgrams(objectPath, sectionIdx, streamID, bloomBytes)
+		c.builderMtx.Unlock()
+		if err != nil {
+			return fmt.Errorf("failed to append n-gram index: %w", err)
+		}
+	}
+
 	level.Info(sectionLogger).Log("msg", "finished processing logs section", "rowsProcessed", cnt)
 	return nil
 }
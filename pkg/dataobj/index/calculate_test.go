@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/dataobj"
 	"github.com/grafana/loki/v3/pkg/dataobj/consumer/logsobj"
 	"github.com/grafana/loki/v3/pkg/dataobj/index/indexobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/ngrams"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/pointers"
 	"github.com/grafana/loki/v3/pkg/logproto"
 
@@ -134,6 +135,7 @@ func TestCalculator_Calculate(t *testing.T) {
 		require.Greater(t, count, 1)
 
 		requireValidPointers(t, obj)
+		requireValidNgrams(t, obj)
 	})
 }
 
@@ -173,3 +175,25 @@ func requireValidPointers(t *testing.T, obj *dataobj.Object) {
 		require.Greater(t, totalPointers, 0)
 	}
 }
+
+// requireValidNgrams checks the n-gram bloom filters of the objects created
+// by createTestLogObject: one per stream for each of the 10 objects.
+func requireValidNgrams(t *testing.T, obj *dataobj.Object) {
+	var rows, errorRows int
+	for result := range ngrams.Iter(context.Background(), obj) {
+		row, err := result.Value()
+		require.NoError(t, err)
+		require.NotEqual(t, "", row.Path)
+		require.Greater(t, row.StreamID, int64(0))
+
+		filter, err := ngrams.NewNgramFilter(row.NgramBloomFilter)
+		require.NoError(t, err)
+		require.True(t, filter.MayContain("hello from"))
+		if filter.MayContain("error message") {
+			errorRows++
+		}
+		rows++
+	}
+	require.Equal(t, 20, rows)
+	require.Equal(t, 10, errorRows, "only the bar streams contain the error message")
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/dataobj"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/indexpointers"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/ngrams"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/pointers"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
 )
@@ -121,6 +122,7 @@ type Builder struct {
 	streams       *streams.Builder
 	pointers      *pointers.Builder
 	indexPointers *indexpointers.Builder
+	ngrams        *ngrams.Builder
 
 	state builderState
 }
@@ -161,6 +163,7 @@ func NewBuilder(cfg BuilderConfig) (*Builder, error) {
 		streams:       streams.NewBuilder(metrics.streams, int(cfg.TargetPageSize)),
 		pointers:      pointers.NewBuilder(metrics.pointers, int(cfg.TargetPageSize)),
 		indexPointers: indexpointers.NewBuilder(metrics.indexPointers, int(cfg.TargetPageSize)),
+		ngrams:        ngrams.NewBuilder(metrics.ngrams, int(cfg.TargetPageSize)),
 	}, nil
 }
 
@@ -319,11 +322,72 @@ func (b *Builder) AppendColumnIndex(path string, section int64, columnName strin
 	return nil
 }
 
+// AppendPointer appends a copy of a pointer read from another index object
+// to the object's pointers section. The StreamID of stream pointers must
+// reference a stream appended with [Builder.AppendStream]. AppendPointer
+// returns [ErrBuilderFull] if the builder is full.
+func (b *Builder) AppendPointer(pointer pointers.SectionPointer) error {
+	b.metrics.appendsTotal.Inc()
+	newEntrySize := 4 + len(pointer.ColumnName) + len(pointer.ValuesBloomFilter)
+
+	if b.state != builderStateEmpty && b.currentSizeEstimate+newEntrySize > int(b.cfg.TargetObjectSize) {
+		return ErrBuilderFull
+	}
+
+	timer := prometheus.NewTimer(b.metrics.appendTime)
+	defer timer.ObserveDuration()
+
+	b.pointers.Append(pointer)
+
+	if b.pointers.EstimatedSize() > int(b.cfg.TargetSectionSize) {
+		if err := b.builder.Append(b.pointers); err != nil {
+			b.metrics.appendFailures.Inc()
+			return err
+		}
+	}
+
+	b.currentSizeEstimate = b.estimatedSize()
+	b.state = builderStateDirty
+	return nil
+}
+
+// AppendNgrams appends the n-gram bloom filter of the log lines of a stream in
+// a logs section to the object's ngrams section. AppendNgrams returns
+// [ErrBuilderFull] if the builder is full.
+//
+// Once a Builder is full, call [Builder.Flush] to flush the buffered data,
+// then call AppendNgrams again with the same entry.
+func (b *Builder) AppendNgrams(path string, section int64, streamIDInObject int64, ngramBloomFilter []byte) error {
+	b.metrics.appendsTotal.Inc()
+	newEntrySize := 1 + 1 + len(ngramBloomFilter) // section, streamID, bloom filter; paths compress well.
+
+	if b.state != builderStateEmpty && b.currentSizeEstimate+newEntrySize > int(b.cfg.TargetObjectSize) {
+		return ErrBuilderFull
+	}
+
+	timer := prometheus.NewTimer(b.metrics.appendTime)
+	defer timer.ObserveDuration()
+
+	b.ngrams.Append(path, section, streamIDInObject, ngramBloomFilter)
+
+	if b.ngrams.EstimatedSize() > int(b.cfg.TargetSectionSize) {
+		if err := b.builder.Append(b.ngrams); err != nil {
+			b.metrics.appendFailures.Inc()
+			return err
+		}
+	}
+
+	b.currentSizeEstimate = b.estimatedSize()
+	b.state = builderStateDirty
+	return nil
+}
+
 func (b *Builder) estimatedSize() int {
 	var size int
 	size += b.streams.EstimatedSize()
 	size += b.pointers.EstimatedSize()
 	size += b.indexPointers.EstimatedSize()
+	size += b.ngrams.EstimatedSize()
 	size += b.builder.Bytes()
 	b.metrics.sizeEstimate.Set(float64(size))
 	return size
@@ -357,6 +421,7 @@ func (b *Builder) Flush(output *bytes.Buffer) (FlushStats, error) {
 	flushErrors = append(flushErrors, b.builder.Append(b.streams))
 	flushErrors = append(flushErrors, b.builder.Append(b.pointers))
 	flushErrors = append(flushErrors, b.builder.Append(b.indexPointers))
+	flushErrors = append(flushErrors, b.builder.Append(b.ngrams))
 
 	if err := errors.Join(flushErrors...); err != nil {
 		b.metrics.flushFailures.Inc()
@@ -411,6 +476,13 @@ func (b *Builder) observeObject(ctx context.Context, obj *dataobj.Object) error
 				continue
 			}
 			errs = append(errs, b.metrics.pointers.Observe(ctx, pointerSection))
+		case ngrams.CheckSection(sec):
+			ngramsSection, err := ngrams.Open(ctx, sec)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			errs = append(errs, b.metrics.ngrams.Observe(ctx, ngramsSection))
 		case streams.CheckSection(sec):
 			streamSection, err := streams.Open(context.Background(), sec)
 			if err != nil {
@@ -430,6 +502,7 @@ func (b *Builder) Reset() {
 	b.streams.Reset()
 	b.pointers.Reset()
 	b.indexPointers.Reset()
+	b.ngrams.Reset()
 
 	b.metrics.sizeEstimate.Set(0)
 	b.currentSizeEstimate = 0
@@ -7,6 +7,7 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/dataobj"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/indexpointers"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/ngrams"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/pointers"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
 )
@@ -15,6 +16,7 @@ import (
 type builderMetrics struct {
 	pointers      *pointers.Metrics
 	indexPointers *indexpointers.Metrics
+	ngrams        *ngrams.Metrics
 	streams       *streams.Metrics
 	dataobj       *dataobj.Metrics
 
@@ -38,6 +40,7 @@ type builderMetrics struct {
 func newBuilderMetrics() *builderMetrics {
 	return &builderMetrics{
 		indexPointers: indexpointers.NewMetrics(),
+		ngrams:        ngrams.NewMetrics(),
 		pointers:      pointers.NewMetrics(),
 		streams:       streams.NewMetrics(),
 		dataobj:       dataobj.NewMetrics(),
@@ -126,6 +129,7 @@ func (m *builderMetrics) Register(reg prometheus.Registerer) error {
 	var errs []error
 
 	errs = append(errs, m.indexPointers.Register(reg))
+	errs = append(errs, m.ngrams.Register(reg))
 	errs = append(errs, m.pointers.Register(reg))
 	errs = append(errs, m.streams.Register(reg))
 	errs = append(errs, m.dataobj.Register(reg))
@@ -150,6 +154,7 @@ func (m *builderMetrics) Register(reg prometheus.Registerer) error {
 // Unregister unregisters metrics from the provided Registerer.
 func (m *builderMetrics) Unregister(reg prometheus.Registerer) {
 	m.indexPointers.Unregister(reg)
+	m.ngrams.Unregister(reg)
 	m.pointers.Unregister(reg)
 	m.streams.Unregister(reg)
 	m.dataobj.Unregister(reg)
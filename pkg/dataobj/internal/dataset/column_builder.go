@@ -40,6 +40,11 @@ type StatisticsOptions struct {
 	// StoreCardinalityStats indicates whether to store cardinality estimations,
 	// facilitated by hyperloglog
 	StoreCardinalityStats bool
+
+	// StoreBloomFilters indicates whether to store a bloom filter of the values
+	// of each page. Bloom filters permit skipping pages for equality predicates
+	// on columns with too many distinct values for range stats to be useful.
+	StoreBloomFilters bool
 }
 
 // CompressionOptions customizes the compressor used when building pages.
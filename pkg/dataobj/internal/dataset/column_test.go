@@ -209,3 +209,65 @@ func getMinMax(t *testing.T, stats *datasetmd.Statistics) (minVal, maxVal Value)
 
 	return minValue, maxValue
 }
+
+func TestColumnBuilder_BloomFilters(t *testing.T) {
+	var (
+		aString = strings.Repeat("a", 100)
+		bString = strings.Repeat("b", 100)
+		cString = strings.Repeat("c", 100)
+		dString = strings.Repeat("d", 100)
+	)
+
+	in := []string{
+		// Null values aren't added to bloom filters.
+		"",
+
+		// Strings are grouped by which page they'll be appended to.
+		aString,
+		dString,
+		aString,
+
+		bString,
+		cString,
+		cString,
+	}
+
+	opts := BuilderOptions{
+		PageSizeHint: 301, // Slightly larger than the string length of 3 strings per page.
+		Value:        datasetmd.VALUE_TYPE_BYTE_ARRAY,
+		Compression:  datasetmd.COMPRESSION_TYPE_NONE,
+		Encoding:     datasetmd.ENCODING_TYPE_PLAIN,
+
+		Statistics: StatisticsOptions{
+			StoreBloomFilters: true,
+		},
+	}
+	b, err := NewColumnBuilder("", opts)
+	require.NoError(t, err)
+
+	for i, s := range in {
+		require.NoError(t, b.Append(i, ByteArrayValue([]byte(s))))
+	}
+
+	col, err := b.Flush()
+	require.NoError(t, err)
+	require.Len(t, col.Pages, 2)
+
+	mayMatch := func(page *MemPage, s string) bool {
+		ok, err := bloomMayMatch(&page.Info, EqualPredicate{Value: ByteArrayValue([]byte(s))})
+		require.NoError(t, err)
+		return ok
+	}
+
+	require.NotEmpty(t, col.Pages[0].Info.BloomFilter)
+	require.True(t, mayMatch(col.Pages[0], aString))
+	require.True(t, mayMatch(col.Pages[0], dString))
+	require.False(t, mayMatch(col.Pages[0], bString))
+	require.False(t, mayMatch(col.Pages[0], cString))
+
+	require.NotEmpty(t, col.Pages[1].Info.BloomFilter)
+	require.False(t, mayMatch(col.Pages[1], aString))
+	require.True(t, mayMatch(col.Pages[1], bString))
+	require.True(t, mayMatch(col.Pages[1], cString))
+	require.False(t, mayMatch(col.Pages[1], dString))
+}
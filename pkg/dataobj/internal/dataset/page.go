@@ -41,6 +41,15 @@ type (
 
 		Encoding datasetmd.EncodingType // Encoding used for values in the page.
 		Stats    *datasetmd.Statistics  // Optional statistics for the page.
+
+		// BloomFilter is an optional encoded bloom filter of the non-NULL values
+		// in the page. See [datasetmd.PageInfo] for details on the encoding.
+		BloomFilter []byte
+
+		// Dictionary is the encoded dictionary of pages using
+		// [datasetmd.ENCODING_TYPE_DICTIONARY]. See [datasetmd.PageInfo] for
+		// details on the encoding.
+		Dictionary []byte
 	}
 
 	// Pages is a set of [Page]s.
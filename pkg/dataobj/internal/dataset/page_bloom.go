@@ -0,0 +1,92 @@
+package dataset
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"github.com/bits-and-blooms/bloom/v3"
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/metadata/datasetmd"
+)
+
+// bloomFalsePositiveRate is the target false positive rate of page bloom
+// filters.
+const bloomFalsePositiveRate = 1.0 / 128.0
+
+// bloomHash returns the hash of v which is added to page bloom filters. See
+// [datasetmd.PageInfo] for details on the encoding. bloomHash returns false if
+// v can't be added to a bloom filter.
+func bloomHash(v Value) (uint64, bool) {
+	var buf [8]byte
+
+	switch v.Type() {
+	case datasetmd.VALUE_TYPE_INT64:
+		binary.LittleEndian.PutUint64(buf[:], uint64(v.Int64()))
+		return xxhash.Sum64(buf[:]), true
+	case datasetmd.VALUE_TYPE_UINT64:
+		binary.LittleEndian.PutUint64(buf[:], v.Uint64())
+		return xxhash.Sum64(buf[:]), true
+	case datasetmd.VALUE_TYPE_BYTE_ARRAY:
+		return xxhash.Sum64(v.ByteArray()), true
+	default:
+		return 0, false
+	}
+}
+
+// buildBloomFilter returns an encoded bloom filter holding the provided hashes,
+// as returned by [bloomHash]. hashes is sorted in place.
+func buildBloomFilter(hashes []uint64) ([]byte, error) {
+	slices.Sort(hashes)
+	hashes = slices.Compact(hashes)
+
+	var (
+		filter = bloom.NewWithEstimates(uint(max(len(hashes), 1)), bloomFalsePositiveRate)
+		key    [8]byte
+	)
+	for _, hash := range hashes {
+		binary.LittleEndian.PutUint64(key[:], hash)
+		filter.Add(key[:])
+	}
+	return filter.MarshalBinary()
+}
+
+// bloomMayMatch returns false if the bloom filter of a page proves that p is
+// false for every row in the page. bloomMayMatch returns true if the page has
+// no bloom filter or the predicate can't be checked against it.
+func bloomMayMatch(info *PageInfo, p Predicate) (bool, error) {
+	if len(info.BloomFilter) == 0 {
+		return true, nil
+	}
+
+	var values []Value
+	switch p := p.(type) {
+	case EqualPredicate:
+		values = []Value{p.Value}
+	case InPredicate:
+		values = slices.Collect(p.Values.Iter())
+	default:
+		return true, nil
+	}
+
+	var filter bloom.BloomFilter
+	if err := filter.UnmarshalBinary(info.BloomFilter); err != nil {
+		return false, fmt.Errorf("decoding bloom filter: %w", err)
+	}
+
+	var key [8]byte
+	for _, v := range values {
+		hash, ok := bloomHash(v)
+		if !ok {
+			// NULL values aren't added to bloom filters, so we can't rule out
+			// the page.
+			return true, nil
+		}
+		binary.LittleEndian.PutUint64(key[:], hash)
+		if filter.Test(key[:]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -50,6 +50,11 @@ type pageBuilder struct {
 	// minValue and maxValue track the minimum and maximum values appended to the
 	// page. These are used to compute statistics for the page if requested.
 	minValue, maxValue Value
+
+	// bloomHashes holds the hashes of values appended to the page, used to
+	// build the page bloom filter if requested. The bloom filter can only be
+	// sized once all values are known.
+	bloomHashes []uint64
 }
 
 // newPageBuilder creates a new pageBuilder that stores a sequence of [Value]s.
@@ -169,6 +174,11 @@ func (b *pageBuilder) accumulateStatistics(value Value) {
 	if b.opts.Statistics.StoreRangeStats {
 		b.updateMinMax(value)
 	}
+	if b.opts.Statistics.StoreBloomFilters {
+		if hash, ok := bloomHash(value); ok {
+			b.bloomHashes = append(b.bloomHashes, hash)
+		}
+	}
 }
 
 func (b *pageBuilder) updateMinMax(value Value) {
@@ -207,7 +217,14 @@ func (b *pageBuilder) EstimatedSize() int {
 	// This estimate doesn't account for any values in encoders which haven't
 	// been flushed yet. However, encoder buffers are usually small enough that
 	// we wouldn't massively overshoot our estimate.
-	return b.presenceBuffer.Len() + b.valuesWriter.BytesWritten()
+	//
+	// The exception are encoders which buffer all values until they're flushed
+	// (like dictionary encoding), which report their own estimate.
+	size := b.presenceBuffer.Len() + b.valuesWriter.BytesWritten()
+	if enc, ok := b.valuesEnc.(bufferedValueEncoder); ok {
+		size += enc.EstimatedSize()
+	}
+	return size
 }
 
 // Rows returns the number of rows appended to the pageBuilder.
@@ -240,6 +257,13 @@ func (b *pageBuilder) Flush() (*MemPage, error) {
 		return nil, fmt.Errorf("flushing values writer: %w", err)
 	}
 
+	// Dictionary encoders pick the encoding of each page when it's flushed, and
+	// their dictionary is stored in the page metadata.
+	encoding, dictionary := b.opts.Encoding, []byte(nil)
+	if enc, ok := b.valuesEnc.(*dictionaryEncoder); ok {
+		encoding, dictionary = enc.Flushed()
+	}
+
 	// The final data of our page is the combination of the presence bitmap and
 	// the values. To denote when one ends and the other begins, we prepend the
 	// data with the size of the presence bitmap as a uvarint. See the doc
@@ -262,6 +286,14 @@ func (b *pageBuilder) Flush() (*MemPage, error) {
 
 	checksum := crc32.Checksum(finalData.Bytes(), checksumTable)
 
+	var bloomFilter []byte
+	if b.opts.Statistics.StoreBloomFilters && b.values > 0 {
+		var err error
+		if bloomFilter, err = buildBloomFilter(b.bloomHashes); err != nil {
+			return nil, fmt.Errorf("building bloom filter: %w", err)
+		}
+	}
+
 	page := MemPage{
 		Info: PageInfo{
 			UncompressedSize: headerSize + presenceSize + b.valuesWriter.BytesWritten(),
@@ -270,8 +302,10 @@ func (b *pageBuilder) Flush() (*MemPage, error) {
 			RowCount:         b.rows,
 			ValuesCount:      b.values,
 
-			Encoding: b.opts.Encoding,
-			Stats:    b.buildStats(),
+			Encoding:    encoding,
+			Stats:       b.buildStats(),
+			BloomFilter: bloomFilter,
+			Dictionary:  dictionary,
 		},
 
 		Data: finalData.Bytes(),
@@ -315,4 +349,5 @@ func (b *pageBuilder) Reset() {
 	b.values = 0
 	b.minValue = Value{}
 	b.maxValue = Value{}
+	b.bloomHashes = b.bloomHashes[:0]
 }
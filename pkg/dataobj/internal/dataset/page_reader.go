@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 
 	"github.com/grafana/loki/v3/pkg/dataobj/internal/metadata/datasetmd"
 	"github.com/grafana/loki/v3/pkg/dataobj/internal/util/slicegrow"
@@ -209,6 +210,11 @@ func (pr *pageReader) init(ctx context.Context) error {
 	} else {
 		pr.valuesDec.Reset(pr.valuesReader)
 	}
+	if dec, ok := pr.valuesDec.(*dictionaryDecoder); ok {
+		if err := dec.SetDictionary(memPage.Info.Dictionary); err != nil {
+			return fmt.Errorf("reading page dictionary: %w", err)
+		}
+	}
 
 	pr.ready = true
 	pr.closer = valuesReader
@@ -326,3 +332,68 @@ func (pr *pageReader) getValuesReader() *bufio.Reader {
 	}
 	return pr.valuesReader
 }
+
+// DecodePage decodes all rows of the page at index in column. Rows are
+// returned in order as int64, uint64 or []byte values depending on the value
+// type of column; NULL rows are returned as nil.
+//
+// DecodePage is intended for inspecting individual pages. Use a [Reader] to
+// read values across pages.
+func DecodePage(ctx context.Context, column Column, index int) ([]any, error) {
+	if index < 0 {
+		return nil, fmt.Errorf("invalid page index %d", index)
+	}
+
+	var (
+		page  Page
+		pages int
+	)
+	for result := range column.ListPages(ctx) {
+		p, err := result.Value()
+		if err != nil {
+			return nil, fmt.Errorf("listing pages: %w", err)
+		}
+		if pages == index {
+			page = p
+			break
+		}
+		pages++
+	}
+	if page == nil {
+		return nil, fmt.Errorf("page %d out of range: column has %d pages", index, pages)
+	}
+
+	info := column.ColumnInfo()
+	r := newPageReader(page, info.Type, info.Compression)
+	defer func() { _ = r.Close() }()
+
+	var (
+		rows = make([]any, 0, page.PageInfo().RowCount)
+		buf  = make([]Value, 1024)
+	)
+	for {
+		n, err := r.Read(ctx, buf)
+		for _, v := range buf[:n] {
+			switch {
+			case v.IsNil():
+				rows = append(rows, nil)
+			case v.Type() == datasetmd.VALUE_TYPE_INT64:
+				rows = append(rows, v.Int64())
+			case v.Type() == datasetmd.VALUE_TYPE_UINT64:
+				rows = append(rows, v.Uint64())
+			case v.Type() == datasetmd.VALUE_TYPE_BYTE_ARRAY:
+				// The page reader reuses the memory of byte arrays across calls to
+				// Read, so they need to be copied.
+				rows = append(rows, slices.Clone(v.ByteArray()))
+			default:
+				return nil, fmt.Errorf("unsupported value type %s", v.Type())
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+}
@@ -133,6 +133,45 @@ func Test_pageReader_SkipRows(t *testing.T) {
 	require.Equal(t, pageReaderTestStrings[4:], actual)
 }
 
+func TestDecodePage(t *testing.T) {
+	b, err := NewColumnBuilder("", BuilderOptions{
+		PageSizeHint: 64,
+		Value:        datasetmd.VALUE_TYPE_INT64,
+		Compression:  datasetmd.COMPRESSION_TYPE_ZSTD,
+		Encoding:     datasetmd.ENCODING_TYPE_DELTA,
+	})
+	require.NoError(t, err)
+	for i := range 100 {
+		if i%10 == 0 {
+			continue // Leave some rows NULL.
+		}
+		require.NoError(t, b.Append(i, Int64Value(int64(i))))
+	}
+	col, err := b.Flush()
+	require.NoError(t, err)
+	require.Greater(t, len(col.Pages), 1, "test requires multiple pages")
+
+	var decoded []any
+	for i := range col.Pages {
+		rows, err := DecodePage(context.Background(), col, i)
+		require.NoError(t, err)
+		require.Len(t, rows, col.Pages[i].Info.RowCount)
+		decoded = append(decoded, rows...)
+	}
+
+	require.Len(t, decoded, 100)
+	for i, v := range decoded {
+		if i%10 == 0 {
+			require.Nil(t, v, "row %d", i)
+		} else {
+			require.Equal(t, int64(i), v, "row %d", i)
+		}
+	}
+
+	_, err = DecodePage(context.Background(), col, len(col.Pages))
+	require.ErrorContains(t, err, "out of range")
+}
+
 func buildPage(t *testing.T, opts BuilderOptions, in []string) *MemPage {
 	t.Helper()
 
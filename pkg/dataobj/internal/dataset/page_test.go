@@ -185,6 +185,80 @@ func Test_pageBuilder_WriteRead(t *testing.T) {
 	require.Equal(t, in, actual)
 }
 
+func Test_pageBuilder_DictionaryEncoding(t *testing.T) {
+	tt := []struct {
+		name         string
+		in           []string
+		wantEncoding datasetmd.EncodingType
+	}{
+		{
+			name:         "low cardinality",
+			in:           []string{"info", "", "debug", "info", "info", "debug"},
+			wantEncoding: datasetmd.ENCODING_TYPE_DICTIONARY,
+		},
+		{
+			name:         "high cardinality",
+			in:           []string{"trace-1", "", "trace-2", "trace-3", "trace-1"},
+			wantEncoding: datasetmd.ENCODING_TYPE_PLAIN,
+		},
+		{
+			name:         "only NULLs",
+			in:           []string{"", ""},
+			wantEncoding: datasetmd.ENCODING_TYPE_DICTIONARY,
+		},
+	}
+
+	opts := BuilderOptions{
+		PageSizeHint: 1024,
+		Value:        datasetmd.VALUE_TYPE_BYTE_ARRAY,
+		Compression:  datasetmd.COMPRESSION_TYPE_ZSTD,
+		Encoding:     datasetmd.ENCODING_TYPE_DICTIONARY,
+	}
+	b, err := newPageBuilder(opts)
+	require.NoError(t, err)
+
+	// All pages are read with the same reader, which must switch between
+	// encodings and dictionaries.
+	var r *pageReader
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, s := range tc.in {
+				require.True(t, b.Append(ByteArrayValue([]byte(s))))
+			}
+
+			page, err := b.Flush()
+			require.NoError(t, err)
+			require.Equal(t, tc.wantEncoding, page.Info.Encoding)
+			if tc.wantEncoding != datasetmd.ENCODING_TYPE_DICTIONARY {
+				require.Nil(t, page.Info.Dictionary)
+			}
+
+			if r == nil {
+				r = newPageReader(page, opts.Value, opts.Compression)
+			} else {
+				r.Reset(page, opts.Value, opts.Compression)
+			}
+
+			values := make([]Value, len(tc.in)+1)
+			n, err := r.Read(context.Background(), values)
+			if !errors.Is(err, io.EOF) {
+				require.NoError(t, err)
+			}
+
+			var actual []string
+			for _, val := range values[:n] {
+				if val.IsNil() {
+					actual = append(actual, "")
+				} else {
+					actual = append(actual, string(val.ByteArray()))
+				}
+			}
+			require.Equal(t, tc.in, actual)
+		})
+	}
+}
+
 func Test_pageBuilder_Fill(t *testing.T) {
 	opts := BuilderOptions{
 		PageSizeHint: 1_500_000,
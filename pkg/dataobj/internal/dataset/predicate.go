@@ -60,8 +60,10 @@ type (
 	// FuncPredicate is a [Predicate] which asserts that a row may only be
 	// included if the Value of the Column passes the Keep function.
 	//
-	// Instances of FuncPredicate are ineligible for page filtering and should
-	// only be used when there isn't a more explicit Predicate implementation.
+	// Instances of FuncPredicate are ineligible for page filtering based on
+	// statistics and should only be used when there isn't a more explicit
+	// Predicate implementation. Pages using dictionary encoding can still be
+	// filtered by calling Keep for each value in the page dictionary.
 	FuncPredicate struct {
 		Column Column // Column to check.
 
@@ -69,7 +71,9 @@ type (
 		// the Column instance to allow for reusing the same function across
 		// multiple columns, if necessary.
 		//
-		// If Keep returns true, the row is kept.
+		// If Keep returns true, the row is kept. Keep must only depend on the
+		// value it is given, as it may be called for values of pages rather
+		// than rows.
 		Keep func(column Column, value Value) bool
 	}
 )
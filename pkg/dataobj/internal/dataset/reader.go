@@ -547,6 +547,15 @@ func (r *Reader) initDownloader(ctx context.Context) error {
 		}
 	}
 
+	// Predicates can additionally be checked against the dictionaries of
+	// dictionary-encoded pages, which are stored in page metadata.
+	for _, p := range r.opts.Predicates {
+		ranges, err = r.pruneDictionaryRanges(ctx, p, ranges)
+		if err != nil {
+			return err
+		}
+	}
+
 	r.dl.SetDatasetRanges(ranges)
 	r.ranges = ranges
 
@@ -751,6 +760,9 @@ func simplifyNotPredicate(p NotPredicate) (Predicate, error) {
 // buildColumnPredicateRanges returns a set of rowRanges that are valid based
 // on whether EqualPredicate, InPredicate, GreaterThanPredicate, or LessThanPredicate may be
 // true for each page in a column.
+//
+// Pages are checked against their range statistics and, for EqualPredicate and
+// InPredicate, against their bloom filters.
 func (r *Reader) buildColumnPredicateRanges(ctx context.Context, c Column, p Predicate) (rowRanges, error) {
 	// Get the wrapped column so that the result of c.ListPages can be cached.
 	if idx, ok := r.origColumnLookup[c]; ok {
@@ -781,6 +793,12 @@ func (r *Reader) buildColumnPredicateRanges(ctx context.Context, c Column, p Pre
 			End:   uint64(pageStart + pageInfo.RowCount - 1),
 		}
 
+		if mayMatch, err := bloomMayMatch(pageInfo, p); err != nil {
+			return nil, fmt.Errorf("failed to read page bloom filter: %w", err)
+		} else if !mayMatch {
+			continue
+		}
+
 		minValue, maxValue, err := readMinMax(pageInfo.Stats)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read page stats: %w", err)
@@ -819,6 +837,108 @@ func (r *Reader) buildColumnPredicateRanges(ctx context.Context, c Column, p Pre
 	return ranges, nil
 }
 
+// pruneDictionaryRanges returns the subset of ranges for which p may be true,
+// based on the dictionaries of dictionary-encoded pages in ranges.
+//
+// Only predicates which can be fully evaluated against the values of a single
+// column (EqualPredicate, InPredicate, and FuncPredicate), and combinations of
+// them with AndPredicate and OrPredicate, are checked. Other predicates keep
+// ranges as-is.
+func (r *Reader) pruneDictionaryRanges(ctx context.Context, p Predicate, ranges rowRanges) (rowRanges, error) {
+	switch p := p.(type) {
+	case AndPredicate:
+		left, err := r.pruneDictionaryRanges(ctx, p.Left, ranges)
+		if err != nil {
+			return nil, err
+		}
+		return r.pruneDictionaryRanges(ctx, p.Right, left)
+
+	case OrPredicate:
+		left, err := r.pruneDictionaryRanges(ctx, p.Left, ranges)
+		if err != nil {
+			return nil, err
+		}
+		right, err := r.pruneDictionaryRanges(ctx, p.Right, ranges)
+		if err != nil {
+			return nil, err
+		}
+		return unionRanges(nil, left, right), nil
+
+	case EqualPredicate:
+		return r.pruneColumnDictionaryRanges(ctx, p.Column, p, ranges)
+
+	case InPredicate:
+		return r.pruneColumnDictionaryRanges(ctx, p.Column, p, ranges)
+
+	case FuncPredicate:
+		return r.pruneColumnDictionaryRanges(ctx, p.Column, p, ranges)
+
+	default:
+		return ranges, nil
+	}
+}
+
+// pruneColumnDictionaryRanges removes the rows of dictionary-encoded pages of
+// c from ranges if none of the values in the dictionary of the page pass p.
+func (r *Reader) pruneColumnDictionaryRanges(ctx context.Context, c Column, p Predicate, ranges rowRanges) (rowRanges, error) {
+	// p refers to the original column, while pages are listed from the wrapped
+	// column so that the result of c.ListPages is cached.
+	lookup := map[Column]int{c: 0}
+
+	if idx, ok := r.origColumnLookup[c]; ok {
+		c = r.dl.AllColumns()[idx]
+	} else {
+		return nil, fmt.Errorf("column %v not found in Reader columns", c)
+	}
+
+	var (
+		keep rowRanges
+		row  = Row{Values: make([]Value, 1)}
+
+		pageStart    int
+		lastPageSize int
+	)
+
+	for result := range c.ListPages(ctx) {
+		pageStart += lastPageSize
+
+		page, err := result.Value()
+		if err != nil {
+			return nil, err
+		}
+		pageInfo := page.PageInfo()
+		lastPageSize = pageInfo.RowCount
+
+		pageRange := rowRange{
+			Start: uint64(pageStart),
+			End:   uint64(pageStart + pageInfo.RowCount - 1),
+		}
+		if pageInfo.Encoding != datasetmd.ENCODING_TYPE_DICTIONARY || !ranges.Overlaps(pageRange) {
+			keep.Add(pageRange)
+			continue
+		}
+
+		dictionary, err := decodeDictionary(pageInfo.Dictionary, nil)
+		if err != nil {
+			return nil, fmt.Errorf("reading page dictionary: %w", err)
+		}
+
+		// Pages with NULLs must be kept if p may be true for NULL.
+		include := pageInfo.ValuesCount < pageInfo.RowCount && checkPredicate(p, lookup, row)
+		for i := 0; i < len(dictionary) && !include; i++ {
+			row.Values[0] = dictionary[i]
+			include = checkPredicate(p, lookup, row)
+		}
+		row.Values[0] = Value{}
+
+		if include {
+			keep.Add(pageRange)
+		}
+	}
+
+	return intersectRanges(nil, ranges, keep), nil
+}
+
 // readMinMax reads the minimum and maximum values from the provided
 // statistics. If either minValue or maxValue is NULL, the value is not present
 // in the statistics.
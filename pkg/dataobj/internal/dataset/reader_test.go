@@ -1,6 +1,7 @@
 package dataset
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -864,3 +865,261 @@ func Test_DatasetGenerator(t *testing.T) {
 	t.Logf("timestamp column size: %s", humanize.Bytes(uint64(cols[0].ColumnInfo().UncompressedSize)))
 	t.Logf("label column size: %s", humanize.Bytes(uint64(cols[1].ColumnInfo().UncompressedSize)))
 }
+
+// Test_Reader_DictionaryPageFiltering tests that a Reader filters out
+// dictionary-encoded pages whose dictionary has no values passing a predicate,
+// even if page statistics can't rule them out.
+func Test_Reader_DictionaryPageFiltering(t *testing.T) {
+	pages := [][]string{
+		{"debug", "info", "debug", "info"}, // Rows 0 - 3
+		{"error", "warn", "error", "warn"}, // Rows 4 - 7
+		{"", "warn"},                       // Rows 8 - 9
+	}
+
+	column := &MemColumn{
+		Info: ColumnInfo{
+			Name:        "level",
+			Type:        datasetmd.VALUE_TYPE_BYTE_ARRAY,
+			Compression: datasetmd.COMPRESSION_TYPE_SNAPPY,
+			RowsCount:   10,
+		},
+	}
+	for _, values := range pages {
+		builder, err := newPageBuilder(BuilderOptions{
+			PageSizeHint: 1024,
+			Value:        datasetmd.VALUE_TYPE_BYTE_ARRAY,
+			Encoding:     datasetmd.ENCODING_TYPE_DICTIONARY,
+			Compression:  datasetmd.COMPRESSION_TYPE_SNAPPY,
+			Statistics:   StatisticsOptions{StoreRangeStats: true},
+		})
+		require.NoError(t, err)
+		for _, v := range values {
+			require.True(t, builder.Append(ByteArrayValue([]byte(v))))
+		}
+		page, err := builder.Flush()
+		require.NoError(t, err)
+		column.Pages = append(column.Pages, page)
+	}
+
+	dset := FromMemory([]*MemColumn{column})
+	cols, err := result.Collect(dset.ListColumns(context.Background()))
+	require.NoError(t, err)
+
+	tt := []struct {
+		name       string
+		predicate  Predicate
+		wantRanges rowRanges
+		wantRows   []string
+	}{
+		{
+			name:       "equal predicate",
+			predicate:  EqualPredicate{Column: cols[0], Value: ByteArrayValue([]byte("info"))},
+			wantRanges: rowRanges{{Start: 0, End: 3}},
+			wantRows:   []string{"info", "info"},
+		},
+		{
+			name:       "in predicate",
+			predicate:  InPredicate{Column: cols[0], Values: NewByteArrayValueSet([]Value{ByteArrayValue([]byte("error")), ByteArrayValue([]byte("fatal"))})},
+			wantRanges: rowRanges{{Start: 4, End: 7}},
+			wantRows:   []string{"error", "error"},
+		},
+		{
+			name: "func predicate",
+			predicate: FuncPredicate{Column: cols[0], Keep: func(_ Column, value Value) bool {
+				return !value.IsNil() && bytes.HasPrefix(value.ByteArray(), []byte("w"))
+			}},
+			wantRanges: rowRanges{{Start: 4, End: 7}, {Start: 8, End: 9}},
+			wantRows:   []string{"warn", "warn", "warn"},
+		},
+		{
+			name: "func predicate matching NULL",
+			predicate: FuncPredicate{Column: cols[0], Keep: func(_ Column, value Value) bool {
+				return value.IsNil()
+			}},
+			wantRanges: rowRanges{{Start: 8, End: 9}},
+			wantRows:   []string{""},
+		},
+		{
+			name: "or predicate",
+			predicate: OrPredicate{
+				Left:  EqualPredicate{Column: cols[0], Value: ByteArrayValue([]byte("info"))},
+				Right: EqualPredicate{Column: cols[0], Value: ByteArrayValue([]byte("error"))},
+			},
+			wantRanges: rowRanges{{Start: 0, End: 7}},
+			wantRows:   []string{"info", "info", "error", "error"},
+		},
+		{
+			name:       "not predicate",
+			predicate:  NotPredicate{Inner: EqualPredicate{Column: cols[0], Value: ByteArrayValue([]byte("info"))}},
+			wantRanges: rowRanges{{Start: 0, End: 9}},
+			wantRows:   []string{"debug", "debug", "error", "warn", "error", "warn", "", "warn"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewReader(ReaderOptions{
+				Dataset:    dset,
+				Columns:    cols,
+				Predicates: []Predicate{tc.predicate},
+			})
+			defer r.Close()
+
+			rows, err := readDataset(r, 3)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantRanges, r.ranges)
+
+			var actual []string
+			for _, row := range rows {
+				if row.Values[0].IsNil() {
+					actual = append(actual, "")
+					continue
+				}
+				actual = append(actual, string(row.Values[0].ByteArray()))
+			}
+			require.Equal(t, tc.wantRows, actual)
+		})
+	}
+}
+
+// Test_Reader_DictionaryPageFilteringWithoutDownload tests that dictionaries
+// are checked without reading the data of pages.
+func Test_Reader_DictionaryPageFilteringWithoutDownload(t *testing.T) {
+	column := &MemColumn{
+		Info: ColumnInfo{
+			Name:        "level",
+			Type:        datasetmd.VALUE_TYPE_BYTE_ARRAY,
+			Compression: datasetmd.COMPRESSION_TYPE_SNAPPY,
+			RowsCount:   8,
+		},
+	}
+	for range 2 {
+		builder, err := newPageBuilder(BuilderOptions{
+			PageSizeHint: 1024,
+			Value:        datasetmd.VALUE_TYPE_BYTE_ARRAY,
+			Encoding:     datasetmd.ENCODING_TYPE_DICTIONARY,
+			Compression:  datasetmd.COMPRESSION_TYPE_SNAPPY,
+			Statistics:   StatisticsOptions{StoreRangeStats: true},
+		})
+		require.NoError(t, err)
+		for _, v := range []string{"debug", "warn", "debug", "warn"} {
+			require.True(t, builder.Append(ByteArrayValue([]byte(v))))
+		}
+		page, err := builder.Flush()
+		require.NoError(t, err)
+		require.Equal(t, datasetmd.ENCODING_TYPE_DICTIONARY, page.Info.Encoding)
+
+		// Page statistics can't rule out "info", so the reader must use the
+		// dictionary. Reading the corrupted page would fail.
+		page.Data = nil
+		column.Pages = append(column.Pages, page)
+	}
+
+	dset := FromMemory([]*MemColumn{column})
+	cols, err := result.Collect(dset.ListColumns(context.Background()))
+	require.NoError(t, err)
+
+	r := NewReader(ReaderOptions{
+		Dataset:    dset,
+		Columns:    cols,
+		Predicates: []Predicate{EqualPredicate{Column: cols[0], Value: ByteArrayValue([]byte("info"))}},
+	})
+	defer r.Close()
+
+	rows, err := readDataset(r, 3)
+	require.NoError(t, err)
+	require.Empty(t, rows)
+	require.Empty(t, r.ranges)
+}
+
+// Test_Reader_BloomFilterPageFiltering tests that a Reader filters out pages
+// whose bloom filter doesn't contain the values of a predicate, even if page
+// statistics can't rule them out.
+func Test_Reader_BloomFilterPageFiltering(t *testing.T) {
+	builder, err := NewColumnBuilder("trace_id", BuilderOptions{
+		PageSizeHint: 40, // Four values per page.
+		Value:        datasetmd.VALUE_TYPE_BYTE_ARRAY,
+		Compression:  datasetmd.COMPRESSION_TYPE_SNAPPY,
+		Encoding:     datasetmd.ENCODING_TYPE_PLAIN,
+
+		Statistics: StatisticsOptions{
+			StoreRangeStats:   true,
+			StoreBloomFilters: true,
+		},
+	})
+	require.NoError(t, err)
+
+	// Every page holds the range "trace-000" to "trace-999", so only bloom
+	// filters can rule out pages.
+	var traceIDs []string
+	for page := range 4 {
+		traceIDs = append(traceIDs, "trace-000", fmt.Sprintf("trace-%03d", 100+page), fmt.Sprintf("trace-%03d", 200+page), "trace-999")
+	}
+	for i, traceID := range traceIDs {
+		require.NoError(t, builder.Append(i, ByteArrayValue([]byte(traceID))))
+	}
+
+	column, err := builder.Flush()
+	require.NoError(t, err)
+	require.Len(t, column.Pages, 4)
+
+	dset := FromMemory([]*MemColumn{column})
+	cols, err := result.Collect(dset.ListColumns(context.Background()))
+	require.NoError(t, err)
+
+	tt := []struct {
+		name       string
+		predicate  Predicate
+		wantRanges rowRanges
+		wantRows   []string
+	}{
+		{
+			name:       "equal predicate",
+			predicate:  EqualPredicate{Column: cols[0], Value: ByteArrayValue([]byte("trace-102"))},
+			wantRanges: rowRanges{{Start: 8, End: 11}},
+			wantRows:   []string{"trace-102"},
+		},
+		{
+			name: "in predicate",
+			predicate: InPredicate{Column: cols[0], Values: NewByteArrayValueSet([]Value{
+				ByteArrayValue([]byte("trace-100")),
+				ByteArrayValue([]byte("trace-203")),
+			})},
+			wantRanges: rowRanges{{Start: 0, End: 3}, {Start: 12, End: 15}},
+			wantRows:   []string{"trace-100", "trace-203"},
+		},
+		{
+			name:       "value in every page",
+			predicate:  EqualPredicate{Column: cols[0], Value: ByteArrayValue([]byte("trace-999"))},
+			wantRanges: rowRanges{{Start: 0, End: 3}, {Start: 4, End: 7}, {Start: 8, End: 11}, {Start: 12, End: 15}},
+			wantRows:   []string{"trace-999", "trace-999", "trace-999", "trace-999"},
+		},
+		{
+			name:       "missing value",
+			predicate:  EqualPredicate{Column: cols[0], Value: ByteArrayValue([]byte("trace-500"))},
+			wantRanges: nil,
+			wantRows:   nil,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewReader(ReaderOptions{
+				Dataset:    dset,
+				Columns:    cols,
+				Predicates: []Predicate{tc.predicate},
+			})
+			defer r.Close()
+
+			rows, err := readDataset(r, 3)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantRanges, r.ranges)
+
+			var actual []string
+			for _, row := range rows {
+				actual = append(actual, string(row.Values[0].ByteArray()))
+			}
+			require.Equal(t, tc.wantRows, actual)
+		})
+	}
+}
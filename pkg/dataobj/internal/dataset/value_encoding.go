@@ -30,6 +30,17 @@ type valueEncoder interface {
 	Reset(w streamio.Writer)
 }
 
+// A bufferedValueEncoder is a [valueEncoder] which buffers encoded values in
+// memory until it is flushed, such as encoders which can only write their
+// output once all values are known.
+type bufferedValueEncoder interface {
+	valueEncoder
+
+	// EstimatedSize returns the estimated number of bytes that will be written
+	// to the underlying [streamio.Writer] on the next call to Flush.
+	EstimatedSize() int
+}
+
 // A valueDecoder decodes sequences of [Value] from an underlying
 // [streamio.Reader]. Implementations of encoding types must call
 // registerValueEncoding to register themselves.
@@ -0,0 +1,344 @@
+package dataset
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/metadata/datasetmd"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/streamio"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/util/slicegrow"
+)
+
+func init() {
+	// Register the encoding so instances of it can be dynamically created.
+	registerValueEncoding(
+		datasetmd.VALUE_TYPE_BYTE_ARRAY,
+		datasetmd.ENCODING_TYPE_DICTIONARY,
+		func(w streamio.Writer) valueEncoder { return newDictionaryEncoder(w) },
+		func(r streamio.Reader) valueDecoder { return newDictionaryDecoder(r) },
+	)
+}
+
+// maxDictionarySize is the largest encoded size of a page dictionary.
+// Dictionaries are stored in page metadata, which is downloaded with the
+// metadata of the column, so pages with larger dictionaries fall back to plain
+// encoding.
+const maxDictionarySize = 16 << 10
+
+// dictionaryEncoder encodes byte array values by storing each distinct value
+// once in a dictionary, and encoding the sequence of values as indexes into
+// that dictionary. This is effective for columns with few distinct values,
+// such as log levels or pod names.
+//
+// The dictionary can only be written once all values are known, so values are
+// buffered until [dictionaryEncoder.Flush] is called. Each call to Flush
+// writes a new, self-contained page; callers are expected to flush once per
+// page.
+//
+// Dictionary encoding isn't worth it for pages with many distinct values, so
+// Flush falls back to plain encoding if fewer than half of the values of the
+// page repeat an earlier value, or if the dictionary would exceed
+// [maxDictionarySize]. Callers must use [dictionaryEncoder.Flushed] to learn
+// which encoding was written.
+//
+// # Format
+//
+// The dictionary isn't part of the encoded values. It is returned by
+// [dictionaryEncoder.Flushed] so that it can be stored in the page metadata,
+// which allows readers to check it without downloading the page. The EBNF
+// grammar is as follows:
+//
+//	dictionary         = dictionary_size dictionary_entry*;
+//	dictionary_size    = (* uvarint(number of entries) *)
+//	dictionary_entry   = entry_size entry_data;
+//	entry_size         = (* uvarint(len(entry_data)) *)
+//	entry_data         = (* raw bytes of the value *)
+//	dictionary_data    = (* bitmap-encoded indexes into dictionary, one per value *)
+//
+// Entries are stored in the order they were first encoded.
+type dictionaryEncoder struct {
+	w streamio.Writer
+
+	entries [][]byte          // Dictionary entries in order of their index.
+	lookup  map[string]uint64 // Index of each entry in entries.
+	size    int               // Encoded size of the dictionary in bytes.
+
+	indexes       []uint64 // Index of each encoded value, for falling back to plain encoding.
+	indexesBuffer *bytes.Buffer
+	indexesEnc    *bitmapEncoder
+	plainSize     int // Size of the encoded values with plain encoding.
+
+	flushedEncoding   datasetmd.EncodingType
+	flushedDictionary []byte
+}
+
+var _ bufferedValueEncoder = (*dictionaryEncoder)(nil)
+
+// newDictionaryEncoder creates a dictionaryEncoder that writes encoded values
+// to w.
+func newDictionaryEncoder(w streamio.Writer) *dictionaryEncoder {
+	indexesBuffer := bytes.NewBuffer(nil)
+
+	return &dictionaryEncoder{
+		w: w,
+
+		lookup: make(map[string]uint64),
+
+		indexesBuffer: indexesBuffer,
+		indexesEnc:    newBitmapEncoder(indexesBuffer),
+
+		flushedEncoding: datasetmd.ENCODING_TYPE_DICTIONARY,
+	}
+}
+
+// ValueType returns [datasetmd.VALUE_TYPE_BYTE_ARRAY].
+func (enc *dictionaryEncoder) ValueType() datasetmd.ValueType {
+	return datasetmd.VALUE_TYPE_BYTE_ARRAY
+}
+
+// EncodingType returns [datasetmd.ENCODING_TYPE_DICTIONARY]. Use
+// [dictionaryEncoder.Flushed] to get the encoding of flushed values.
+func (enc *dictionaryEncoder) EncodingType() datasetmd.EncodingType {
+	return datasetmd.ENCODING_TYPE_DICTIONARY
+}
+
+// Encode encodes an individual byte array value. Values are buffered in memory
+// until [dictionaryEncoder.Flush] is called.
+func (enc *dictionaryEncoder) Encode(v Value) error {
+	if v.Type() != datasetmd.VALUE_TYPE_BYTE_ARRAY {
+		return fmt.Errorf("dictionary: invalid value type %v", v.Type())
+	}
+	arr := v.ByteArray()
+
+	index, ok := enc.lookup[unsafeString(arr)]
+	if !ok {
+		// The memory of arr is owned by the caller, so we need to copy it before
+		// retaining it.
+		entry := bytes.Clone(arr)
+
+		index = uint64(len(enc.entries))
+		enc.entries = append(enc.entries, entry)
+		enc.lookup[unsafeString(entry)] = index
+		enc.size += streamio.UvarintSize(uint64(len(entry))) + len(entry)
+	}
+
+	if err := enc.indexesEnc.Encode(Uint64Value(index)); err != nil {
+		return err
+	}
+	enc.indexes = append(enc.indexes, index)
+	enc.plainSize += streamio.UvarintSize(uint64(len(arr))) + len(arr)
+	return nil
+}
+
+// useDictionary reports whether the buffered values are written with
+// dictionary encoding.
+func (enc *dictionaryEncoder) useDictionary() bool {
+	return len(enc.entries)*2 <= len(enc.indexes) && enc.dictionarySize() <= maxDictionarySize
+}
+
+func (enc *dictionaryEncoder) dictionarySize() int {
+	return streamio.UvarintSize(uint64(len(enc.entries))) + enc.size
+}
+
+// EstimatedSize returns the estimated number of bytes buffered by the encoder
+// that will be written on the next call to [dictionaryEncoder.Flush]. The
+// estimate includes the size of the dictionary, even though it isn't written
+// to the underlying [streamio.Writer].
+func (enc *dictionaryEncoder) EstimatedSize() int {
+	if len(enc.indexes) == 0 {
+		return 0
+	} else if !enc.useDictionary() {
+		return enc.plainSize
+	}
+	return enc.dictionarySize() + enc.indexesBuffer.Len()
+}
+
+// Flush writes all encoded values to the underlying [streamio.Writer], and
+// then resets the dictionary. Values are written as the indexes into the
+// dictionary, or with plain encoding if the dictionary isn't worth it. Flush
+// is a no-op if no values were encoded since the last flush.
+func (enc *dictionaryEncoder) Flush() error {
+	if len(enc.indexes) == 0 {
+		return nil
+	}
+
+	if !enc.useDictionary() {
+		plainEnc := newPlainBytesEncoder(enc.w)
+		for _, index := range enc.indexes {
+			if err := plainEnc.Encode(ByteArrayValue(enc.entries[index])); err != nil {
+				return err
+			}
+		}
+		enc.flushedEncoding = datasetmd.ENCODING_TYPE_PLAIN
+		enc.flushedDictionary = nil
+
+		enc.reset()
+		return nil
+	}
+
+	if err := enc.indexesEnc.Flush(); err != nil {
+		return err
+	}
+	if _, err := enc.indexesBuffer.WriteTo(enc.w); err != nil {
+		return err
+	}
+
+	dictionary := make([]byte, 0, enc.dictionarySize())
+	dictionary = binary.AppendUvarint(dictionary, uint64(len(enc.entries)))
+	for _, entry := range enc.entries {
+		dictionary = binary.AppendUvarint(dictionary, uint64(len(entry)))
+		dictionary = append(dictionary, entry...)
+	}
+	enc.flushedEncoding = datasetmd.ENCODING_TYPE_DICTIONARY
+	enc.flushedDictionary = dictionary
+
+	enc.reset()
+	return nil
+}
+
+// Flushed returns the encoding of the values written by the last call to
+// [dictionaryEncoder.Flush], and the encoded dictionary for
+// [datasetmd.ENCODING_TYPE_DICTIONARY]. If no values were flushed since the
+// encoder was last reset, Flushed returns
+// [datasetmd.ENCODING_TYPE_DICTIONARY] with an empty dictionary.
+func (enc *dictionaryEncoder) Flushed() (datasetmd.EncodingType, []byte) {
+	return enc.flushedEncoding, enc.flushedDictionary
+}
+
+// Reset implements [valueEncoder]. It discards any buffered values and resets
+// the encoder to write to w.
+func (enc *dictionaryEncoder) Reset(w streamio.Writer) {
+	enc.w = w
+	enc.reset()
+	enc.flushedEncoding = datasetmd.ENCODING_TYPE_DICTIONARY
+	enc.flushedDictionary = nil
+}
+
+func (enc *dictionaryEncoder) reset() {
+	enc.entries = enc.entries[:0]
+	clear(enc.lookup)
+	enc.size = 0
+
+	enc.indexes = enc.indexes[:0]
+	enc.indexesBuffer.Reset()
+	enc.indexesEnc.Reset(enc.indexesBuffer)
+	enc.plainSize = 0
+}
+
+// dictionaryDecoder decodes byte arrays from an [streamio.Reader] written by
+// a [dictionaryEncoder]. The dictionary of the page must be provided with
+// [dictionaryDecoder.SetDictionary] before decoding.
+type dictionaryDecoder struct {
+	dictionary []Value // Dictionary entries.
+
+	indexesDec *bitmapDecoder
+	indexes    []Value
+}
+
+var _ valueDecoder = (*dictionaryDecoder)(nil)
+
+// newDictionaryDecoder creates a dictionaryDecoder that reads encoded values
+// from r.
+func newDictionaryDecoder(r streamio.Reader) *dictionaryDecoder {
+	return &dictionaryDecoder{
+		indexesDec: newBitmapDecoder(r),
+	}
+}
+
+// ValueType returns [datasetmd.VALUE_TYPE_BYTE_ARRAY].
+func (dec *dictionaryDecoder) ValueType() datasetmd.ValueType {
+	return datasetmd.VALUE_TYPE_BYTE_ARRAY
+}
+
+// EncodingType returns [datasetmd.ENCODING_TYPE_DICTIONARY].
+func (dec *dictionaryDecoder) EncodingType() datasetmd.EncodingType {
+	return datasetmd.ENCODING_TYPE_DICTIONARY
+}
+
+// SetDictionary sets the dictionary of the values to decode, in the form
+// returned by [dictionaryEncoder.Flushed].
+func (dec *dictionaryDecoder) SetDictionary(data []byte) error {
+	dictionary, err := decodeDictionary(data, dec.dictionary[:0])
+	if err != nil {
+		return err
+	}
+	dec.dictionary = dictionary
+	return nil
+}
+
+// Decode decodes up to len(s) values, storing the results into s. The
+// number of decoded values is returned, followed by an error (if any).
+// At the end of the stream, Decode returns 0, [io.EOF].
+func (dec *dictionaryDecoder) Decode(s []Value) (int, error) {
+	if len(s) == 0 {
+		return 0, nil
+	}
+
+	dec.indexes = slicegrow.GrowToCap(dec.indexes, len(s))
+	dec.indexes = dec.indexes[:len(s)]
+
+	n, err := dec.indexesDec.Decode(dec.indexes)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, err
+	}
+
+	for i, index := range dec.indexes[:n] {
+		if index.Uint64() >= uint64(len(dec.dictionary)) {
+			return i, fmt.Errorf("dictionary index %d out of range [0, %d)", index.Uint64(), len(dec.dictionary))
+		}
+
+		// Values are copied out of the dictionary, since callers are permitted
+		// to reuse the memory of values in s.
+		entry := dec.dictionary[index.Uint64()].ByteArray()
+		dst := slicegrow.GrowToCap(s[i].Buffer(), len(entry))
+		dst = dst[:len(entry)]
+		copy(dst, entry)
+		s[i] = ByteArrayValue(dst)
+	}
+	return n, err
+}
+
+// Reset implements [valueDecoder]. It resets the decoder to read from r and
+// discards the dictionary.
+func (dec *dictionaryDecoder) Reset(r streamio.Reader) {
+	clear(dec.dictionary)
+	dec.dictionary = dec.dictionary[:0]
+	dec.indexesDec.Reset(r)
+}
+
+// decodeDictionary decodes the dictionary returned by
+// [dictionaryEncoder.Flushed], appending its entries to dst. The entries
+// reference the memory of data.
+func decodeDictionary(data []byte, dst []Value) ([]Value, error) {
+	if len(data) == 0 {
+		return dst, nil
+	}
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return dst, errors.New("reading dictionary size")
+	}
+	data = data[n:]
+
+	for range count {
+		size, n := binary.Uvarint(data)
+		if n <= 0 {
+			return dst, errors.New("reading dictionary entry size")
+		}
+		data = data[n:]
+
+		if uint64(len(data)) < size {
+			return dst, fmt.Errorf("reading dictionary entry: %w", io.ErrUnexpectedEOF)
+		}
+		dst = append(dst, ByteArrayValue(data[:size:size]))
+		data = data[size:]
+	}
+
+	if len(data) > 0 {
+		return dst, fmt.Errorf("%d trailing bytes after dictionary", len(data))
+	}
+	return dst, nil
+}
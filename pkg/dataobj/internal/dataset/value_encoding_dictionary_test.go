@@ -0,0 +1,271 @@
+package dataset
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/metadata/datasetmd"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/streamio"
+)
+
+var testDictionaryStrings = []string{
+	"info",
+	"info",
+	"debug",
+	"info",
+	"error",
+	"debug",
+	"info",
+	"warn",
+}
+
+func Test_dictionaryEncoder(t *testing.T) {
+	var buf bytes.Buffer
+
+	var (
+		enc    = newDictionaryEncoder(&buf)
+		dec    = newDictionaryDecoder(&buf)
+		decBuf = make([]Value, batchSize)
+	)
+
+	for _, v := range testDictionaryStrings {
+		require.NoError(t, enc.Encode(ByteArrayValue([]byte(v))))
+	}
+	require.Zero(t, buf.Len(), "values must be buffered until flushed")
+	require.NoError(t, enc.Flush())
+
+	encoding, dictionary := enc.Flushed()
+	require.Equal(t, datasetmd.ENCODING_TYPE_DICTIONARY, encoding)
+	require.NoError(t, dec.SetDictionary(dictionary))
+
+	var out []string
+
+	for {
+		n, err := dec.Decode(decBuf[:batchSize])
+		for _, v := range decBuf[:n] {
+			out = append(out, string(v.ByteArray()))
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	require.Equal(t, testDictionaryStrings, out)
+}
+
+func Test_dictionaryEncoder_partialRead(t *testing.T) {
+	var buf bytes.Buffer
+
+	var (
+		enc    = newDictionaryEncoder(&buf)
+		dec    = newDictionaryDecoder(&oneByteReader{&buf})
+		decBuf = make([]Value, 3)
+	)
+
+	for _, v := range testDictionaryStrings {
+		require.NoError(t, enc.Encode(ByteArrayValue([]byte(v))))
+	}
+	require.NoError(t, enc.Flush())
+
+	_, dictionary := enc.Flushed()
+	require.NoError(t, dec.SetDictionary(dictionary))
+
+	var out []string
+
+	for {
+		n, err := dec.Decode(decBuf)
+		for _, v := range decBuf[:n] {
+			out = append(out, string(v.ByteArray()))
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	require.Equal(t, testDictionaryStrings, out)
+}
+
+func Test_dictionaryEncoder_reusingValues(t *testing.T) {
+	var buf bytes.Buffer
+
+	var (
+		enc    = newDictionaryEncoder(&buf)
+		dec    = newDictionaryDecoder(&buf)
+		decBuf = make([]Value, batchSize)
+	)
+
+	for _, v := range testDictionaryStrings {
+		require.NoError(t, enc.Encode(ByteArrayValue([]byte(v))))
+	}
+	require.NoError(t, enc.Flush())
+
+	_, dictionary := enc.Flushed()
+	require.NoError(t, dec.SetDictionary(dictionary))
+
+	for i := range decBuf {
+		decBuf[i] = ByteArrayValue(make([]byte, 64))
+	}
+
+	n, err := dec.Decode(decBuf)
+	require.NoError(t, err)
+	require.Equal(t, len(testDictionaryStrings), n)
+
+	// Modifying decoded values must not modify the dictionary.
+	copy(decBuf[0].ByteArray(), "oops")
+	copy(decBuf[1].ByteArray(), "oops")
+
+	var out []string
+	for _, v := range dec.dictionary {
+		out = append(out, string(v.ByteArray()))
+	}
+	require.Equal(t, []string{"info", "debug", "error", "warn"}, out)
+}
+
+func Test_dictionaryEncoder_reset(t *testing.T) {
+	var first, second bytes.Buffer
+
+	enc := newDictionaryEncoder(&first)
+	require.NoError(t, enc.Encode(ByteArrayValue([]byte("foo"))))
+	require.NoError(t, enc.Encode(ByteArrayValue([]byte("foo"))))
+	require.NoError(t, enc.Flush())
+
+	// Values encoded after a reset must not use the previous dictionary.
+	enc.Reset(&second)
+	encoding, dictionary := enc.Flushed()
+	require.Equal(t, datasetmd.ENCODING_TYPE_DICTIONARY, encoding)
+	require.Empty(t, dictionary)
+
+	require.NoError(t, enc.Encode(ByteArrayValue([]byte("bar"))))
+	require.NoError(t, enc.Encode(ByteArrayValue([]byte("bar"))))
+	require.NoError(t, enc.Flush())
+
+	_, dictionary = enc.Flushed()
+	entries, err := decodeDictionary(dictionary, nil)
+	require.NoError(t, err)
+	require.Equal(t, []Value{ByteArrayValue([]byte("bar"))}, entries)
+}
+
+func Test_dictionaryEncoder_EstimatedSize(t *testing.T) {
+	var buf bytes.Buffer
+	enc := newDictionaryEncoder(&buf)
+	require.Zero(t, enc.EstimatedSize())
+
+	for _, v := range testDictionaryStrings {
+		require.NoError(t, enc.Encode(ByteArrayValue([]byte(v))))
+	}
+	estimate := enc.EstimatedSize()
+
+	require.NoError(t, enc.Flush())
+	_, dictionary := enc.Flushed()
+	require.GreaterOrEqual(t, estimate, len("info")+len("debug")+len("error")+len("warn"))
+	require.LessOrEqual(t, estimate, buf.Len()+len(dictionary))
+	require.Zero(t, enc.EstimatedSize())
+}
+
+func Test_dictionaryEncoder_smallerThanPlain(t *testing.T) {
+	var plainBuf, dictBuf bytes.Buffer
+
+	var (
+		plainEnc = newPlainBytesEncoder(&plainBuf)
+		dictEnc  = newDictionaryEncoder(&dictBuf)
+	)
+
+	for i := range 1000 {
+		v := ByteArrayValue([]byte(testDictionaryStrings[i%len(testDictionaryStrings)]))
+		require.NoError(t, plainEnc.Encode(v))
+		require.NoError(t, dictEnc.Encode(v))
+	}
+	require.NoError(t, plainEnc.Flush())
+	require.NoError(t, dictEnc.Flush())
+
+	_, dictionary := dictEnc.Flushed()
+	require.Less(t, dictBuf.Len()+len(dictionary), plainBuf.Len()/4)
+}
+
+func Test_dictionaryEncoder_plainFallback(t *testing.T) {
+	tt := []struct {
+		name   string
+		values []string
+	}{
+		{
+			name:   "high cardinality",
+			values: []string{"a", "b", "c", "a", "d"},
+		},
+		{
+			name: "large dictionary",
+			values: func() []string {
+				var values []string
+				for i := range 3 * maxDictionarySize / 100 {
+					values = append(values, fmt.Sprintf("%0100d", i/3))
+				}
+				return values
+			}(),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			enc := newDictionaryEncoder(&buf)
+
+			var plainSize int
+			for _, v := range tc.values {
+				require.NoError(t, enc.Encode(ByteArrayValue([]byte(v))))
+				plainSize += streamio.UvarintSize(uint64(len(v))) + len(v)
+			}
+			require.Equal(t, plainSize, enc.EstimatedSize())
+			require.NoError(t, enc.Flush())
+
+			encoding, dictionary := enc.Flushed()
+			require.Equal(t, datasetmd.ENCODING_TYPE_PLAIN, encoding)
+			require.Nil(t, dictionary)
+			require.Equal(t, plainSize, buf.Len())
+
+			var (
+				dec    = newPlainBytesDecoder(&buf)
+				decBuf = make([]Value, len(tc.values))
+			)
+			n, err := dec.Decode(decBuf)
+			require.NoError(t, err)
+
+			var out []string
+			for _, v := range decBuf[:n] {
+				out = append(out, string(v.ByteArray()))
+			}
+			require.Equal(t, tc.values, out)
+		})
+	}
+}
+
+func Test_decodeDictionary_invalid(t *testing.T) {
+	dictionary := binary.AppendUvarint(nil, 2)
+	dictionary = binary.AppendUvarint(dictionary, 3)
+	dictionary = append(dictionary, "foo"...)
+
+	_, err := decodeDictionary(dictionary, nil)
+	require.Error(t, err, "dictionary with missing entries must be rejected")
+
+	_, err = decodeDictionary(append(dictionary, 10), nil)
+	require.Error(t, err, "dictionary with truncated entry must be rejected")
+}
+
+func Benchmark_dictionaryEncoder_Append(b *testing.B) {
+	enc := newDictionaryEncoder(streamio.Discard)
+
+	for i := 0; i < b.N; i++ {
+		for _, v := range testDictionaryStrings {
+			_ = enc.Encode(ByteArrayValue([]byte(v)))
+		}
+		_ = enc.Flush()
+	}
+}
@@ -0,0 +1,184 @@
+package dataset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/metadata/datasetmd"
+)
+
+// VerifyColumn decodes every page of column and checks the decoded values
+// against the metadata of the page and the column. Decoding a page also
+// validates its CRC32 checksum.
+//
+// VerifyColumn returns an error describing every problem found, or nil if
+// column is valid. Pages which fail to decode are reported and skipped, so
+// that all corrupt pages of a column are reported at once.
+func VerifyColumn(ctx context.Context, column Column) error {
+	var (
+		errs []error
+		info = column.ColumnInfo()
+
+		rows, values int
+	)
+
+	columnRange, err := newRangeCheck(info.Statistics)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("column statistics: %w", err))
+	}
+
+	var index int
+	for result := range column.ListPages(ctx) {
+		page, err := result.Value()
+		if err != nil {
+			return errors.Join(append(errs, fmt.Errorf("listing pages: %w", err))...)
+		}
+
+		pageRows, pageValues, err := verifyPage(ctx, page, info, columnRange)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("page %d: %w", index, err))
+		}
+		rows += pageRows
+		values += pageValues
+		index++
+	}
+
+	if rows != info.RowsCount {
+		errs = append(errs, fmt.Errorf("decoded %d rows, column metadata reports %d", rows, info.RowsCount))
+	}
+	if values != info.ValuesCount {
+		errs = append(errs, fmt.Errorf("decoded %d values, column metadata reports %d", values, info.ValuesCount))
+	}
+	if err := columnRange.Err(); err != nil {
+		errs = append(errs, fmt.Errorf("column statistics: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// verifyPage decodes page and checks it against its metadata. Non-NULL values
+// of the page are also checked against columnRange. verifyPage returns the
+// number of rows and values which were decoded.
+func verifyPage(ctx context.Context, page Page, column *ColumnInfo, columnRange *rangeCheck) (rows, values int, err error) {
+	info := page.PageInfo()
+
+	pageRange, err := newRangeCheck(info.Stats)
+	if err != nil {
+		return 0, 0, fmt.Errorf("page statistics: %w", err)
+	}
+
+	r := newPageReader(page, column.Type, column.Compression)
+	defer func() { _ = r.Close() }()
+
+	buf := make([]Value, 1024)
+	for {
+		n, err := r.Read(ctx, buf)
+		for _, v := range buf[:n] {
+			if v.IsNil() {
+				continue
+			}
+			if v.Type() != column.Type {
+				return rows, values, fmt.Errorf("decoded value of type %s in column of type %s", v.Type(), column.Type)
+			}
+			pageRange.Observe(v)
+			columnRange.Observe(v)
+			values++
+		}
+		rows += n
+
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return rows, values, err
+		}
+	}
+
+	var errs []error
+	if rows != info.RowCount {
+		errs = append(errs, fmt.Errorf("decoded %d rows, page metadata reports %d", rows, info.RowCount))
+	}
+	if values != info.ValuesCount {
+		errs = append(errs, fmt.Errorf("decoded %d values, page metadata reports %d", values, info.ValuesCount))
+	}
+	if err := pageRange.Err(); err != nil {
+		errs = append(errs, fmt.Errorf("page statistics: %w", err))
+	}
+	return rows, values, errors.Join(errs...)
+}
+
+// rangeCheck checks a sequence of values against the minimum and maximum
+// value of range statistics. A nil rangeCheck accepts all values.
+type rangeCheck struct {
+	min, max       Value
+	sawMin, sawMax bool
+
+	outOfRange int
+}
+
+// newRangeCheck returns a rangeCheck for the range statistics in stats, or nil
+// if stats doesn't hold range statistics.
+func newRangeCheck(stats *datasetmd.Statistics) (*rangeCheck, error) {
+	if stats == nil || (len(stats.MinValue) == 0 && len(stats.MaxValue) == 0) {
+		return nil, nil
+	}
+
+	var check rangeCheck
+	if err := check.min.UnmarshalBinary(stats.MinValue); err != nil {
+		return nil, fmt.Errorf("decoding min value: %w", err)
+	} else if err := check.max.UnmarshalBinary(stats.MaxValue); err != nil {
+		return nil, fmt.Errorf("decoding max value: %w", err)
+	}
+	if check.min.Type() != check.max.Type() {
+		return nil, fmt.Errorf("min value of type %s and max value of type %s", check.min.Type(), check.max.Type())
+	}
+	return &check, nil
+}
+
+// Observe checks the non-NULL value v against the range.
+func (c *rangeCheck) Observe(v Value) {
+	if c == nil || v.Type() != c.min.Type() {
+		return
+	}
+
+	lower, upper := CompareValues(&v, &c.min), CompareValues(&v, &c.max)
+	if lower < 0 || upper > 0 {
+		c.outOfRange++
+	}
+	c.sawMin = c.sawMin || lower == 0
+	c.sawMax = c.sawMax || upper == 0
+}
+
+// Err returns an error if any of the observed values was outside of the range
+// or if the minimum or maximum value of the range was never observed.
+func (c *rangeCheck) Err() error {
+	if c == nil {
+		return nil
+	}
+
+	var errs []error
+	if c.outOfRange > 0 {
+		errs = append(errs, fmt.Errorf("%d values outside of range [%s, %s]", c.outOfRange, formatValue(c.min), formatValue(c.max)))
+	}
+	if !c.sawMin {
+		errs = append(errs, fmt.Errorf("min value %s not found", formatValue(c.min)))
+	}
+	if !c.sawMax {
+		errs = append(errs, fmt.Errorf("max value %s not found", formatValue(c.max)))
+	}
+	return errors.Join(errs...)
+}
+
+// formatValue formats v for error messages.
+func formatValue(v Value) string {
+	switch v.Type() {
+	case datasetmd.VALUE_TYPE_INT64:
+		return strconv.FormatInt(v.Int64(), 10)
+	case datasetmd.VALUE_TYPE_UINT64:
+		return strconv.FormatUint(v.Uint64(), 10)
+	case datasetmd.VALUE_TYPE_BYTE_ARRAY:
+		return strconv.Quote(string(v.ByteArray()))
+	}
+	return "NULL"
+}
@@ -0,0 +1,69 @@
+package dataset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/metadata/datasetmd"
+)
+
+func TestVerifyColumn(t *testing.T) {
+	buildColumn := func(t *testing.T) *MemColumn {
+		t.Helper()
+
+		b, err := NewColumnBuilder("", BuilderOptions{
+			PageSizeHint: 64,
+			Value:        datasetmd.VALUE_TYPE_INT64,
+			Compression:  datasetmd.COMPRESSION_TYPE_ZSTD,
+			Encoding:     datasetmd.ENCODING_TYPE_DELTA,
+			Statistics:   StatisticsOptions{StoreRangeStats: true},
+		})
+		require.NoError(t, err)
+
+		for i := range 100 {
+			if i%10 == 0 {
+				continue // Leave some rows NULL.
+			}
+			require.NoError(t, b.Append(i, Int64Value(int64(i*3))))
+		}
+
+		col, err := b.Flush()
+		require.NoError(t, err)
+		require.Greater(t, len(col.Pages), 1, "test requires multiple pages")
+		return col
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		col := buildColumn(t)
+		require.NoError(t, VerifyColumn(context.Background(), col))
+	})
+
+	t.Run("corrupt page", func(t *testing.T) {
+		col := buildColumn(t)
+		data := append(PageData(nil), col.Pages[1].Data...)
+		data[len(data)-1] ^= 0xff
+		col.Pages[1].Data = data
+
+		err := VerifyColumn(context.Background(), col)
+		require.ErrorContains(t, err, "page 1: opening page for reading: invalid CRC32 checksum")
+		require.ErrorContains(t, err, "column metadata reports 100")
+	})
+
+	t.Run("mismatched metadata", func(t *testing.T) {
+		col := buildColumn(t)
+		col.Pages[0].Info.ValuesCount++
+		col.Info.RowsCount++
+
+		stats := *col.Pages[0].Info.Stats
+		stats.MaxValue, _ = Int64Value(1000).MarshalBinary()
+		col.Pages[0].Info.Stats = &stats
+
+		err := VerifyColumn(context.Background(), col)
+		require.ErrorContains(t, err, "page 0: decoded")
+		require.ErrorContains(t, err, "page statistics: max value 1000 not found")
+		require.ErrorContains(t, err, "decoded 100 rows, column metadata reports 101")
+		require.NotContains(t, err.Error(), "page 1:")
+	})
+}
@@ -106,6 +106,10 @@ const (
 	// Bitmap encoding. Bitmaps effiently store repeating sequences of unsigned
 	// integers using a combination of run-length encoding and bitpacking.
 	ENCODING_TYPE_BITMAP EncodingType = 3
+	// Dictionary encoding. The distinct values of the page are stored once in
+	// the dictionary of the page metadata, and the page data holds a
+	// bitmap-encoded sequence of indexes into that dictionary.
+	ENCODING_TYPE_DICTIONARY EncodingType = 4
 )
 
 var EncodingType_name = map[int32]string{
@@ -113,6 +117,7 @@ var EncodingType_name = map[int32]string{
 	1: "ENCODING_TYPE_PLAIN",
 	2: "ENCODING_TYPE_DELTA",
 	3: "ENCODING_TYPE_BITMAP",
+	4: "ENCODING_TYPE_DICTIONARY",
 }
 
 var EncodingType_value = map[string]int32{
@@ -120,6 +125,7 @@ var EncodingType_value = map[string]int32{
 	"ENCODING_TYPE_PLAIN":       1,
 	"ENCODING_TYPE_DELTA":       2,
 	"ENCODING_TYPE_BITMAP":      3,
+	"ENCODING_TYPE_DICTIONARY":  4,
 }
 
 func (EncodingType) EnumDescriptor() ([]byte, []int) {
@@ -385,6 +391,21 @@ type PageInfo struct {
 	Statistics *Statistics `protobuf:"bytes,8,opt,name=statistics,proto3" json:"statistics,omitempty"`
 	// Total number of non-NULL values in the page.
 	ValuesCount uint64 `protobuf:"varint,9,opt,name=values_count,json=valuesCount,proto3" json:"values_count,omitempty"`
+	// Optional bloom filter of the non-NULL values in the page, encoded with
+	// the binary format of github.com/bits-and-blooms/bloom/v3.
+	//
+	// Values are added to the filter as the little-endian 64-bit xxhash of their
+	// bytes. Byte array values are hashed as-is, while integer values are hashed
+	// as their 8-byte little-endian representation.
+	BloomFilter []byte `protobuf:"bytes,10,opt,name=bloom_filter,json=bloomFilter,proto3" json:"bloom_filter,omitempty"`
+	// Dictionary of pages using ENCODING_TYPE_DICTIONARY. The dictionary is
+	// stored in the page metadata rather than in the page data, so that readers
+	// can check predicates against it without downloading the page.
+	//
+	// The dictionary is encoded as a uvarint count of entries, followed by each
+	// entry as a uvarint length and the raw bytes of the entry. The page data
+	// holds the bitmap-encoded indexes into the dictionary.
+	Dictionary []byte `protobuf:"bytes,11,opt,name=dictionary,proto3" json:"dictionary,omitempty"`
 }
 
 func (m *PageInfo) Reset()      { *m = PageInfo{} }
@@ -482,6 +503,20 @@ func (m *PageInfo) GetValuesCount() uint64 {
 	return 0
 }
 
+func (m *PageInfo) GetBloomFilter() []byte {
+	if m != nil {
+		return m.BloomFilter
+	}
+	return nil
+}
+
+func (m *PageInfo) GetDictionary() []byte {
+	if m != nil {
+		return m.Dictionary
+	}
+	return nil
+}
+
 // SectionSortInfo represents the sort order information for the records
 // in a section.
 //
@@ -601,61 +636,64 @@ func init() {
 }
 
 var fileDescriptor_7ab9d5b21b743868 = []byte{
-	// 860 bytes of a gzipped FileDescriptorProto
+	// 904 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0x4d, 0x73, 0xda, 0x46,
-	0x18, 0x66, 0x81, 0xa4, 0xe8, 0x05, 0xdb, 0xf2, 0xd6, 0x6e, 0x64, 0x3b, 0x51, 0xa9, 0x3b, 0xd3,
-	0x50, 0xd2, 0x81, 0x29, 0xee, 0x34, 0x67, 0x0c, 0x6a, 0xaa, 0x19, 0x47, 0x30, 0x92, 0x92, 0x19,
-	0xfb, 0xa2, 0x91, 0x85, 0x4c, 0xd5, 0xa0, 0x5d, 0x46, 0x5a, 0x5c, 0x93, 0x53, 0x4f, 0x39, 0xf7,
-	0x67, 0xf4, 0xa7, 0xf4, 0xe8, 0x63, 0x8e, 0x35, 0x9e, 0xe9, 0xe4, 0x98, 0x9f, 0xd0, 0xd1, 0x0a,
-	0x81, 0xf8, 0x28, 0x93, 0x43, 0x6f, 0xbb, 0xef, 0xf3, 0xbc, 0x1f, 0xec, 0xf3, 0xf0, 0x0a, 0x9e,
-	0x0f, 0xdf, 0xf4, 0xeb, 0x3d, 0x9b, 0xd9, 0xf4, 0xf2, 0xd7, 0xba, 0x47, 0x98, 0x1b, 0x10, 0x7b,
-	0x50, 0xf7, 0x5d, 0x66, 0x47, 0x41, 0x8e, 0x84, 0x2e, 0xf3, 0x7b, 0xf3, 0x53, 0x6d, 0x18, 0x50,
-	0x46, 0xf1, 0xd1, 0x34, 0xa9, 0x96, 0x70, 0x6b, 0x53, 0x46, 0xed, 0xfa, 0xfb, 0xe3, 0x7f, 0x72,
-	0x00, 0x2d, 0x3a, 0x18, 0xf9, 0x44, 0x25, 0x57, 0x14, 0x63, 0xc8, 0x13, 0xdb, 0x77, 0x25, 0x54,
-	0x46, 0x15, 0x41, 0xe7, 0x67, 0xac, 0x00, 0x5c, 0xdb, 0x83, 0x91, 0x6b, 0xb1, 0xf1, 0xd0, 0x95,
-	0xb2, 0x65, 0x54, 0xd9, 0x6e, 0x7c, 0x53, 0xdb, 0x50, 0xb4, 0xf6, 0x3a, 0xa2, 0x9b, 0xe3, 0xa1,
-	0xab, 0x0b, 0xd7, 0xc9, 0x11, 0x3f, 0x01, 0x08, 0xe8, 0x6f, 0xa1, 0xe5, 0xd0, 0x11, 0x61, 0x52,
-	0xae, 0x8c, 0x2a, 0x79, 0x5d, 0x88, 0x22, 0xad, 0x28, 0x80, 0x35, 0x28, 0x3a, 0xd4, 0x1f, 0x06,
-	0x6e, 0x18, 0x7a, 0x94, 0x48, 0x79, 0xde, 0xe6, 0xbb, 0x8d, 0x6d, 0x5a, 0x73, 0x3e, 0x6f, 0x96,
-	0x2e, 0x80, 0x9f, 0xc1, 0xee, 0x88, 0x24, 0x01, 0xb7, 0x67, 0x85, 0xde, 0x5b, 0x57, 0x7a, 0xc0,
-	0xbb, 0x8a, 0x69, 0xc0, 0xf0, 0xde, 0xba, 0xf8, 0x29, 0xec, 0x2c, 0x53, 0x1f, 0x72, 0xea, 0xf6,
-	0x2a, 0x31, 0x99, 0xc4, 0xa2, 0x57, 0x57, 0xa1, 0xcb, 0xa4, 0xcf, 0x62, 0x62, 0x12, 0xee, 0xf0,
-	0x28, 0xfe, 0x1a, 0xb6, 0x66, 0x44, 0x5e, 0xaf, 0xc0, 0x69, 0xa5, 0x24, 0xc8, 0xab, 0xbd, 0x00,
-	0x08, 0x99, 0xcd, 0xbc, 0x90, 0x79, 0x4e, 0x28, 0x09, 0x65, 0x54, 0x29, 0x36, 0x9e, 0x6e, 0xfc,
-	0xc9, 0xc6, 0x8c, 0xae, 0xa7, 0x52, 0xf1, 0x57, 0x50, 0xe2, 0x0f, 0x9d, 0xbc, 0x2e, 0xf0, 0x66,
-	0xc5, 0x38, 0xc6, 0xdf, 0xf7, 0x38, 0x04, 0x98, 0x27, 0xe3, 0x23, 0x10, 0x7c, 0x8f, 0x58, 0x9c,
-	0xc0, 0xc5, 0x2e, 0xe9, 0x05, 0xdf, 0x23, 0x5c, 0x38, 0x0e, 0xda, 0x37, 0x53, 0x30, 0x3b, 0x05,
-	0xed, 0x9b, 0x18, 0x7c, 0x06, 0xbb, 0x8e, 0x1d, 0xf4, 0x3c, 0x62, 0x0f, 0x3c, 0x36, 0x5e, 0x50,
-	0x53, 0x4c, 0x01, 0x71, 0xd3, 0x77, 0x39, 0x28, 0x74, 0xed, 0xbe, 0xcb, 0xbd, 0xb5, 0x56, 0x11,
-	0xf4, 0xe9, 0x8a, 0x64, 0xd7, 0x2a, 0xb2, 0x07, 0x0f, 0x9c, 0xc0, 0x39, 0x69, 0xf0, 0x19, 0xb6,
-	0xf4, 0xf8, 0xb2, 0x64, 0xb6, 0xfc, 0xb2, 0xd9, 0x14, 0x28, 0xb8, 0xc4, 0xa1, 0x3d, 0x8f, 0xf4,
-	0xb9, 0x27, 0xb6, 0x1b, 0xdf, 0x6e, 0x7c, 0x76, 0x65, 0x4a, 0xe6, 0x36, 0x9b, 0xa5, 0xe2, 0x2f,
-	0xa1, 0x98, 0x76, 0x42, 0x6c, 0x19, 0x48, 0xb9, 0xe0, 0x08, 0x84, 0xb9, 0x03, 0x62, 0xa3, 0x14,
-	0xfe, 0x43, 0xfd, 0xc2, 0xff, 0xa7, 0xbe, 0xb0, 0xaa, 0xfe, 0x07, 0x04, 0x3b, 0x86, 0xeb, 0x30,
-	0x8f, 0x12, 0x83, 0x06, 0x8c, 0xeb, 0x71, 0x01, 0x25, 0x87, 0xff, 0xf3, 0xad, 0x90, 0x06, 0x2c,
-	0x94, 0x50, 0x39, 0x57, 0x29, 0x36, 0x9e, 0x6f, 0x9e, 0x60, 0xb1, 0x46, 0x2d, 0x5e, 0x1d, 0xd1,
-	0x35, 0xfa, 0xf7, 0x25, 0xe7, 0xf0, 0x70, 0x9c, 0x6c, 0x95, 0xe8, 0x1a, 0x0d, 0x38, 0xed, 0xe4,
-	0x91, 0x9e, 0x7b, 0xc3, 0x45, 0xdf, 0x4a, 0x12, 0xd4, 0x28, 0x84, 0x7f, 0x06, 0xa1, 0xe7, 0x05,
-	0x71, 0xf5, 0xe9, 0x8e, 0xa9, 0x6e, 0x9e, 0x84, 0x06, 0xac, 0x9d, 0x64, 0xe8, 0xf3, 0xe4, 0xea,
-	0x08, 0x84, 0xd9, 0xfe, 0xc1, 0x87, 0xf0, 0xc5, 0xeb, 0xe6, 0xd9, 0x2b, 0xc5, 0x32, 0xcf, 0xbb,
-	0x8a, 0xf5, 0x4a, 0x33, 0xba, 0x4a, 0x4b, 0xfd, 0x49, 0x55, 0xda, 0x62, 0x06, 0xef, 0x81, 0x98,
-	0xc2, 0x54, 0xcd, 0xfc, 0xf1, 0x07, 0x11, 0xe1, 0x7d, 0xd8, 0x4d, 0x67, 0xc4, 0xe1, 0x2c, 0x3e,
-	0x80, 0xfd, 0x54, 0xf8, 0xf4, 0xdc, 0x54, 0xac, 0xa6, 0xae, 0x37, 0xcf, 0xc5, 0xfc, 0x71, 0xbe,
-	0x90, 0x13, 0x73, 0xd5, 0x77, 0x08, 0x76, 0x96, 0x16, 0x12, 0x2e, 0xc3, 0xe3, 0x56, 0xe7, 0x65,
-	0x57, 0x57, 0x0c, 0x43, 0xed, 0x68, 0xeb, 0x66, 0x38, 0x80, 0xfd, 0x15, 0x86, 0xd6, 0xd1, 0x14,
-	0x11, 0xe1, 0x23, 0x78, 0xb4, 0x02, 0x19, 0x5a, 0xb3, 0xdb, 0x3d, 0x8f, 0xc7, 0x59, 0x01, 0x2f,
-	0x0c, 0xb3, 0x2d, 0xe6, 0xaa, 0x63, 0x28, 0xa5, 0xed, 0x8a, 0x9f, 0xc0, 0x81, 0xa2, 0xb5, 0x3a,
-	0x6d, 0x55, 0x7b, 0xb1, 0x6e, 0x82, 0x47, 0xf0, 0xf9, 0x22, 0xdc, 0x3d, 0x6b, 0xaa, 0x9a, 0x88,
-	0x56, 0x81, 0xb6, 0x72, 0x66, 0x36, 0xc5, 0x2c, 0x96, 0x60, 0x6f, 0x11, 0x38, 0x55, 0xcd, 0x97,
-	0xcd, 0xae, 0x98, 0xab, 0x0e, 0x60, 0x6b, 0x41, 0x16, 0x2c, 0xc3, 0xa1, 0xd1, 0xd1, 0x4d, 0xab,
-	0xad, 0xea, 0x4a, 0xcb, 0x8c, 0x26, 0x5d, 0x6c, 0xfe, 0x18, 0xa4, 0x25, 0xbc, 0x69, 0xb4, 0x14,
-	0x2d, 0x2a, 0x2d, 0xa2, 0x68, 0xf2, 0x25, 0xb4, 0xad, 0xcc, 0xe0, 0xec, 0xe9, 0xcd, 0xed, 0x9d,
-	0x9c, 0x79, 0x7f, 0x27, 0x67, 0x3e, 0xde, 0xc9, 0xe8, 0xf7, 0x89, 0x8c, 0xfe, 0x9c, 0xc8, 0xe8,
-	0xaf, 0x89, 0x8c, 0x6e, 0x27, 0x32, 0xfa, 0x7b, 0x22, 0xa3, 0x0f, 0x13, 0x39, 0xf3, 0x71, 0x22,
-	0xa3, 0x3f, 0xee, 0xe5, 0xcc, 0xed, 0xbd, 0x9c, 0x79, 0x7f, 0x2f, 0x67, 0x2e, 0x4e, 0xfb, 0x1e,
-	0xfb, 0x65, 0x74, 0x59, 0x73, 0xa8, 0x5f, 0xef, 0x07, 0xf6, 0x95, 0x4d, 0xec, 0xfa, 0x80, 0xbe,
-	0xf1, 0xea, 0xd7, 0x27, 0xf5, 0x4f, 0xfc, 0xc2, 0x5e, 0x3e, 0xe4, 0x1f, 0xd6, 0x93, 0x7f, 0x03,
-	0x00, 0x00, 0xff, 0xff, 0x2a, 0x2b, 0x5b, 0xb1, 0x93, 0x07, 0x00, 0x00,
+	0x18, 0x66, 0x81, 0xa4, 0xf0, 0x82, 0x6d, 0x79, 0x6b, 0x37, 0xf2, 0x47, 0x54, 0xea, 0xce, 0x34,
+	0x94, 0x74, 0x60, 0x8a, 0x3b, 0xcd, 0x19, 0x83, 0x92, 0x6a, 0xc6, 0x11, 0x8c, 0xa4, 0x64, 0x06,
+	0x5f, 0x34, 0xb2, 0x10, 0x74, 0x1b, 0xa4, 0x65, 0xa4, 0xc5, 0x35, 0x39, 0xf5, 0xd4, 0x73, 0x6f,
+	0xfd, 0x0b, 0xfd, 0x29, 0xed, 0xcd, 0xc7, 0x1c, 0x6b, 0x3c, 0xd3, 0xc9, 0x31, 0x3f, 0xa1, 0xa3,
+	0x15, 0x02, 0x01, 0x2e, 0x93, 0x43, 0x6f, 0xbb, 0xcf, 0xf3, 0xbc, 0x1f, 0xbb, 0xef, 0xa3, 0x15,
+	0x3c, 0x1b, 0xbd, 0x19, 0xd4, 0x7a, 0x16, 0xb3, 0xe8, 0xe5, 0x4f, 0x35, 0xe2, 0x31, 0xc7, 0xf7,
+	0xac, 0x61, 0xcd, 0x75, 0x98, 0x15, 0x82, 0x9c, 0x09, 0x1c, 0xe6, 0xf6, 0x16, 0xab, 0xea, 0xc8,
+	0xa7, 0x8c, 0xe2, 0xa3, 0x59, 0x50, 0x35, 0xd6, 0x56, 0x67, 0x8a, 0xea, 0xd5, 0xb7, 0x27, 0xff,
+	0x64, 0x00, 0x9a, 0x74, 0x38, 0x76, 0x3d, 0xc5, 0xeb, 0x53, 0x8c, 0x21, 0xeb, 0x59, 0xae, 0x23,
+	0xa2, 0x12, 0x2a, 0xe7, 0x35, 0xbe, 0xc6, 0x32, 0xc0, 0x95, 0x35, 0x1c, 0x3b, 0x26, 0x9b, 0x8c,
+	0x1c, 0x31, 0x5d, 0x42, 0xe5, 0xed, 0xfa, 0x57, 0xd5, 0x0d, 0x49, 0xab, 0xaf, 0x43, 0xb9, 0x31,
+	0x19, 0x39, 0x5a, 0xfe, 0x2a, 0x5e, 0xe2, 0xc7, 0x00, 0x3e, 0xfd, 0x39, 0x30, 0x6d, 0x3a, 0xf6,
+	0x98, 0x98, 0x29, 0xa1, 0x72, 0x56, 0xcb, 0x87, 0x48, 0x33, 0x04, 0xb0, 0x0a, 0x05, 0x9b, 0xba,
+	0x23, 0xdf, 0x09, 0x02, 0x42, 0x3d, 0x31, 0xcb, 0xcb, 0x7c, 0xb3, 0xb1, 0x4c, 0x73, 0xa1, 0xe7,
+	0xc5, 0x92, 0x09, 0xf0, 0x53, 0xd8, 0x1d, 0x7b, 0x31, 0xe0, 0xf4, 0xcc, 0x80, 0xbc, 0x75, 0xc4,
+	0x07, 0xbc, 0xaa, 0x90, 0x24, 0x74, 0xf2, 0xd6, 0xc1, 0x4f, 0x60, 0x67, 0x55, 0xfa, 0x90, 0x4b,
+	0xb7, 0xd7, 0x85, 0x71, 0x27, 0x26, 0xed, 0xf7, 0x03, 0x87, 0x89, 0x9f, 0x44, 0xc2, 0x18, 0x6e,
+	0x73, 0x14, 0x7f, 0x09, 0x5b, 0x73, 0x21, 0xcf, 0x97, 0xe3, 0xb2, 0x62, 0x0c, 0xf2, 0x6c, 0x2f,
+	0x00, 0x02, 0x66, 0x31, 0x12, 0x30, 0x62, 0x07, 0x62, 0xbe, 0x84, 0xca, 0x85, 0xfa, 0x93, 0x8d,
+	0x47, 0xd6, 0xe7, 0x72, 0x2d, 0x11, 0x8a, 0xbf, 0x80, 0x22, 0xbf, 0xe8, 0xf8, 0x76, 0x81, 0x17,
+	0x2b, 0x44, 0x18, 0xbf, 0xdf, 0x93, 0x00, 0x60, 0x11, 0x8c, 0x8f, 0x20, 0xef, 0x12, 0xcf, 0xe4,
+	0x02, 0x3e, 0xec, 0xa2, 0x96, 0x73, 0x89, 0xc7, 0x07, 0xc7, 0x49, 0xeb, 0x7a, 0x46, 0xa6, 0x67,
+	0xa4, 0x75, 0x1d, 0x91, 0x4f, 0x61, 0xd7, 0xb6, 0xfc, 0x1e, 0xf1, 0xac, 0x21, 0x61, 0x93, 0xa5,
+	0x69, 0x0a, 0x09, 0x22, 0x2a, 0xfa, 0x57, 0x06, 0x72, 0x1d, 0x6b, 0xe0, 0x70, 0x6f, 0xdd, 0x3b,
+	0x11, 0xf4, 0xf1, 0x13, 0x49, 0xdf, 0x3b, 0x91, 0x3d, 0x78, 0x60, 0xfb, 0xf6, 0x69, 0x9d, 0xf7,
+	0xb0, 0xa5, 0x45, 0x9b, 0x15, 0xb3, 0x65, 0x57, 0xcd, 0x26, 0x43, 0xce, 0xf1, 0x6c, 0xda, 0x23,
+	0xde, 0x80, 0x7b, 0x62, 0xbb, 0xfe, 0xf5, 0xc6, 0x6b, 0x97, 0x67, 0x62, 0x6e, 0xb3, 0x79, 0x28,
+	0xfe, 0x1c, 0x0a, 0x49, 0x27, 0x44, 0x96, 0x81, 0x84, 0x0b, 0x8e, 0x20, 0xbf, 0x70, 0x40, 0x64,
+	0x94, 0xdc, 0x7f, 0x4c, 0x3f, 0xf7, 0xff, 0x4d, 0x3f, 0xbf, 0x36, 0xfd, 0x50, 0x72, 0x39, 0xa4,
+	0xd4, 0x35, 0xfb, 0x64, 0xc8, 0x1c, 0x9f, 0x1b, 0xa4, 0xa8, 0x15, 0x38, 0xf6, 0x9c, 0x43, 0x58,
+	0x02, 0xe8, 0x11, 0x9b, 0x11, 0xea, 0x59, 0xfe, 0x44, 0x2c, 0x70, 0x41, 0x02, 0x39, 0x79, 0x8f,
+	0x60, 0x47, 0x77, 0xf8, 0x56, 0xa7, 0x3e, 0xe3, 0x23, 0xbd, 0x80, 0xa2, 0xcd, 0x1f, 0x0f, 0x33,
+	0xa0, 0x3e, 0x0b, 0x44, 0x54, 0xca, 0x94, 0x0b, 0xf5, 0x67, 0x9b, 0x0f, 0xb1, 0x9c, 0xa3, 0x1a,
+	0xbd, 0x3e, 0xe1, 0x36, 0xfc, 0x80, 0xe3, 0x75, 0x70, 0x38, 0x89, 0x1f, 0xa6, 0x70, 0x1b, 0x1e,
+	0x60, 0x56, 0x89, 0x78, 0x3d, 0xe7, 0x9a, 0xfb, 0x66, 0x2b, 0x0e, 0x50, 0x42, 0x08, 0xff, 0x00,
+	0xf9, 0x1e, 0xf1, 0xa3, 0xec, 0xb3, 0x67, 0xaa, 0xb2, 0xb9, 0x13, 0xea, 0xb3, 0x56, 0x1c, 0xa1,
+	0x2d, 0x82, 0x2b, 0x63, 0xc8, 0xcf, 0x9f, 0x30, 0x7c, 0x08, 0x9f, 0xbd, 0x6e, 0x9c, 0xbf, 0x92,
+	0x4d, 0xa3, 0xdb, 0x91, 0xcd, 0x57, 0xaa, 0xde, 0x91, 0x9b, 0xca, 0x73, 0x45, 0x6e, 0x09, 0x29,
+	0xbc, 0x07, 0x42, 0x82, 0x53, 0x54, 0xe3, 0xfb, 0xef, 0x04, 0x84, 0xf7, 0x61, 0x37, 0x19, 0x11,
+	0xc1, 0x69, 0x7c, 0x00, 0xfb, 0x09, 0xf8, 0xac, 0x6b, 0xc8, 0x66, 0x43, 0xd3, 0x1a, 0x5d, 0x21,
+	0x7b, 0x92, 0xcd, 0x65, 0x84, 0x4c, 0xe5, 0x57, 0x04, 0x3b, 0x2b, 0x6f, 0x1a, 0x2e, 0xc1, 0x71,
+	0xb3, 0xfd, 0xb2, 0xa3, 0xc9, 0xba, 0xae, 0xb4, 0xd5, 0xfb, 0x7a, 0x38, 0x80, 0xfd, 0x35, 0x85,
+	0xda, 0x56, 0x65, 0x01, 0xe1, 0x23, 0x78, 0xb4, 0x46, 0xe9, 0x6a, 0xa3, 0xd3, 0xe9, 0x46, 0xed,
+	0xac, 0x91, 0x17, 0xba, 0xd1, 0x12, 0x32, 0x95, 0xdf, 0x11, 0x14, 0x93, 0x96, 0xc7, 0x8f, 0xe1,
+	0x40, 0x56, 0x9b, 0xed, 0x96, 0xa2, 0xbe, 0xb8, 0xaf, 0x85, 0x47, 0xf0, 0xe9, 0x32, 0xdd, 0x39,
+	0x6f, 0x28, 0xaa, 0x80, 0xd6, 0x89, 0x96, 0x7c, 0x6e, 0x34, 0x84, 0x34, 0x16, 0x61, 0x6f, 0x99,
+	0x38, 0x53, 0x8c, 0x97, 0x8d, 0x8e, 0x90, 0xc1, 0xc7, 0x20, 0xae, 0x84, 0x28, 0x4d, 0x43, 0x69,
+	0xab, 0x0d, 0xad, 0x2b, 0x64, 0x2b, 0x43, 0xd8, 0x5a, 0x9a, 0x1a, 0x96, 0xe0, 0x50, 0x6f, 0x6b,
+	0x86, 0xd9, 0x52, 0x34, 0x99, 0xeb, 0x56, 0x5a, 0x3b, 0x06, 0x71, 0x85, 0x6f, 0xe8, 0x4d, 0x59,
+	0x0d, 0xd3, 0x0b, 0x28, 0x3c, 0xd7, 0x0a, 0xdb, 0x92, 0xe7, 0x74, 0xfa, 0xec, 0xfa, 0xe6, 0x56,
+	0x4a, 0xbd, 0xbb, 0x95, 0x52, 0x1f, 0x6e, 0x25, 0xf4, 0xcb, 0x54, 0x42, 0x7f, 0x4c, 0x25, 0xf4,
+	0xe7, 0x54, 0x42, 0x37, 0x53, 0x09, 0xfd, 0x3d, 0x95, 0xd0, 0xfb, 0xa9, 0x94, 0xfa, 0x30, 0x95,
+	0xd0, 0x6f, 0x77, 0x52, 0xea, 0xe6, 0x4e, 0x4a, 0xbd, 0xbb, 0x93, 0x52, 0x17, 0x67, 0x03, 0xc2,
+	0x7e, 0x1c, 0x5f, 0x56, 0x6d, 0xea, 0xd6, 0x06, 0xbe, 0xd5, 0xb7, 0x3c, 0xab, 0x36, 0xa4, 0x6f,
+	0x48, 0xed, 0xea, 0xb4, 0xf6, 0x91, 0xff, 0xf0, 0xcb, 0x87, 0xfc, 0xd7, 0x7d, 0xfa, 0xef, 0x00,
+	0x90, 0xbb, 0x7a, 0xee, 0xf5, 0x07, 0x00, 0x00,
 }
 
 func (x ValueType) String() string {
@@ -813,6 +851,12 @@ func (this *PageInfo) Equal(that interface{}) bool {
 	if this.ValuesCount != that1.ValuesCount {
 		return false
 	}
+	if !bytes.Equal(this.BloomFilter, that1.BloomFilter) {
+		return false
+	}
+	if !bytes.Equal(this.Dictionary, that1.Dictionary) {
+		return false
+	}
 	return true
 }
 func (this *SectionSortInfo) Equal(that interface{}) bool {
@@ -908,7 +952,7 @@ func (this *PageInfo) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 13)
+	s := make([]string, 0, 15)
 	s = append(s, "&datasetmd.PageInfo{")
 	s = append(s, "UncompressedSize: "+fmt.Sprintf("%#v", this.UncompressedSize)+",\n")
 	s = append(s, "CompressedSize: "+fmt.Sprintf("%#v", this.CompressedSize)+",\n")
@@ -921,6 +965,8 @@ func (this *PageInfo) GoString() string {
 		s = append(s, "Statistics: "+fmt.Sprintf("%#v", this.Statistics)+",\n")
 	}
 	s = append(s, "ValuesCount: "+fmt.Sprintf("%#v", this.ValuesCount)+",\n")
+	s = append(s, "BloomFilter: "+fmt.Sprintf("%#v", this.BloomFilter)+",\n")
+	s = append(s, "Dictionary: "+fmt.Sprintf("%#v", this.Dictionary)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1099,6 +1145,20 @@ func (m *PageInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Dictionary) > 0 {
+		i -= len(m.Dictionary)
+		copy(dAtA[i:], m.Dictionary)
+		i = encodeVarintDatasetmd(dAtA, i, uint64(len(m.Dictionary)))
+		i--
+		dAtA[i] = 0x5a
+	}
+	if len(m.BloomFilter) > 0 {
+		i -= len(m.BloomFilter)
+		copy(dAtA[i:], m.BloomFilter)
+		i = encodeVarintDatasetmd(dAtA, i, uint64(len(m.BloomFilter)))
+		i--
+		dAtA[i] = 0x52
+	}
 	if m.ValuesCount != 0 {
 		i = encodeVarintDatasetmd(dAtA, i, uint64(m.ValuesCount))
 		i--
@@ -1330,6 +1390,14 @@ func (m *PageInfo) Size() (n int) {
 	if m.ValuesCount != 0 {
 		n += 1 + sovDatasetmd(uint64(m.ValuesCount))
 	}
+	l = len(m.BloomFilter)
+	if l > 0 {
+		n += 1 + l + sovDatasetmd(uint64(l))
+	}
+	l = len(m.Dictionary)
+	if l > 0 {
+		n += 1 + l + sovDatasetmd(uint64(l))
+	}
 	return n
 }
 
@@ -1414,6 +1482,8 @@ func (this *PageInfo) String() string {
 		`DataSize:` + fmt.Sprintf("%v", this.DataSize) + `,`,
 		`Statistics:` + strings.Replace(this.Statistics.String(), "Statistics", "Statistics", 1) + `,`,
 		`ValuesCount:` + fmt.Sprintf("%v", this.ValuesCount) + `,`,
+		`BloomFilter:` + fmt.Sprintf("%v", this.BloomFilter) + `,`,
+		`Dictionary:` + fmt.Sprintf("%v", this.Dictionary) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2082,6 +2152,74 @@ func (m *PageInfo) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BloomFilter", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDatasetmd
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDatasetmd
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDatasetmd
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BloomFilter = append(m.BloomFilter[:0], dAtA[iNdEx:postIndex]...)
+			if m.BloomFilter == nil {
+				m.BloomFilter = []byte{}
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Dictionary", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDatasetmd
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDatasetmd
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDatasetmd
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Dictionary = append(m.Dictionary[:0], dAtA[iNdEx:postIndex]...)
+			if m.Dictionary == nil {
+				m.Dictionary = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDatasetmd(dAtA[iNdEx:])
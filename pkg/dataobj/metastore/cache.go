@@ -0,0 +1,156 @@
+package metastore
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/loki/v3/pkg/dataobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/pointers"
+)
+
+// metastoreObjectCacheSize is the number of metastore objects kept in
+// memory. Each metastore object covers a single window, so queries only read
+// a few of them.
+const metastoreObjectCacheSize = 256
+
+const (
+	cacheObjectTypeMetastore = "metastore"
+	cacheObjectTypeIndex     = "index"
+
+	cacheResultHit  = "hit"
+	cacheResultMiss = "miss"
+)
+
+// objectCache caches decoded metastore and index objects, so that they
+// don't need to be fetched from object storage again for every query.
+//
+// Index objects are immutable and therefore cached by path. Metastore
+// objects are replaced whenever objects are added to or removed from their
+// window, so a cached metastore object is only used as long as its size and
+// modification time in object storage are unchanged.
+type objectCache struct {
+	metastoreObjects *lru.Cache[string, cachedMetastoreObject]
+	indexObjects     *indexObjectCache
+	metrics          *objectMetastoreMetrics
+}
+
+type cachedMetastoreObject struct {
+	attrs  objstore.ObjectAttributes
+	object *dataobj.Object
+}
+
+// newObjectCache returns an objectCache which keeps opened index objects up
+// to indexObjectsSize bytes of metadata. Index objects are not cached if
+// indexObjectsSize is zero.
+func newObjectCache(indexObjectsSize int, metrics *objectMetastoreMetrics) *objectCache {
+	metastoreObjects, err := lru.New[string, cachedMetastoreObject](metastoreObjectCacheSize)
+	if err != nil {
+		panic(err) // Only fails for a non-positive size.
+	}
+	return &objectCache{
+		metastoreObjects: metastoreObjects,
+		indexObjects:     newIndexObjectCache(indexObjectsSize),
+		metrics:          metrics,
+	}
+}
+
+// metastoreObject returns the metastore object at path, fetching it with
+// fetch if it isn't cached or has changed since it was cached.
+func (c *objectCache) metastoreObject(ctx context.Context, bucket objstore.Bucket, path string, fetch func(context.Context, string) (*dataobj.Object, error)) (*dataobj.Object, error) {
+	attrs, err := bucket.Attributes(ctx, path)
+	if err != nil {
+		// Without attributes, a cached object can't be validated, so the
+		// object is fetched without caching it. This also reports missing
+		// objects to the caller.
+		return fetch(ctx, path)
+	}
+
+	if cached, ok := c.metastoreObjects.Get(path); ok && cached.attrs.Size == attrs.Size && cached.attrs.LastModified.Equal(attrs.LastModified) {
+		c.metrics.incObjectCacheRequests(cacheObjectTypeMetastore, cacheResultHit)
+		return cached.object, nil
+	}
+	c.metrics.incObjectCacheRequests(cacheObjectTypeMetastore, cacheResultMiss)
+
+	object, err := fetch(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	c.metastoreObjects.Add(path, cachedMetastoreObject{attrs: attrs, object: object})
+	return object, nil
+}
+
+// indexObject returns the opened object at path. Only index objects are
+// cached: metastores may also list data objects, which are only opened to
+// read their streams.
+func (c *objectCache) indexObject(ctx context.Context, bucket objstore.Bucket, path string) (*dataobj.Object, error) {
+	if object, ok := c.indexObjects.get(path); ok {
+		c.metrics.incObjectCacheRequests(cacheObjectTypeIndex, cacheResultHit)
+		return object, nil
+	}
+	c.metrics.incObjectCacheRequests(cacheObjectTypeIndex, cacheResultMiss)
+
+	object, err := dataobj.FromBucket(ctx, bucket, path)
+	if err != nil {
+		return nil, err
+	}
+	if object.Sections().Count(pointers.CheckSection) > 0 {
+		c.indexObjects.add(path, object)
+	}
+	return object, nil
+}
+
+// indexObjectCache is an LRU cache of opened index objects which is bounded
+// by the size of their metadata, since opened objects keep their metadata in
+// memory.
+type indexObjectCache struct {
+	mtx     sync.Mutex
+	objects *simplelru.LRU[string, *dataobj.Object]
+	size    int
+	maxSize int
+}
+
+func newIndexObjectCache(maxSize int) *indexObjectCache {
+	c := &indexObjectCache{maxSize: maxSize}
+
+	// The number of objects is bounded by their size instead.
+	objects, err := simplelru.NewLRU(math.MaxInt, func(_ string, object *dataobj.Object) {
+		c.size -= object.MetadataSize()
+	})
+	if err != nil {
+		panic(err) // Only fails for a non-positive size.
+	}
+	c.objects = objects
+	return c
+}
+
+func (c *indexObjectCache) get(path string) (*dataobj.Object, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.objects.Get(path)
+}
+
+// add adds object to the cache, evicting the least recently used objects
+// until the cache fits. Objects larger than the cache are not added.
+func (c *indexObjectCache) add(path string, object *dataobj.Object) {
+	size := object.MetadataSize()
+	if c.maxSize <= 0 || size > c.maxSize {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.objects.Contains(path) {
+		return
+	}
+	c.objects.Add(path, object)
+	c.size += size
+	for c.size > c.maxSize {
+		c.objects.RemoveOldest()
+	}
+}
@@ -0,0 +1,116 @@
+package metastore
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/loki/v3/pkg/dataobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/index/indexobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
+)
+
+func TestObjectCache_MetastoreObject(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		bucket = objstore.NewInMemBucket()
+		cache  = newObjectCache(0, newObjectMetastoreMetrics())
+		path   = metastorePath(tenantID, now.Truncate(WindowSize))
+	)
+
+	var fetches int
+	fetch := func(context.Context, string) (*dataobj.Object, error) {
+		fetches++
+		return &dataobj.Object{}, nil
+	}
+
+	require.NoError(t, bucket.Upload(ctx, path, bytes.NewReader([]byte("v1"))))
+	first, err := cache.metastoreObject(ctx, bucket, path, fetch)
+	require.NoError(t, err)
+	second, err := cache.metastoreObject(ctx, bucket, path, fetch)
+	require.NoError(t, err)
+	require.Same(t, first, second)
+	require.Equal(t, 1, fetches)
+
+	// Replacing the metastore object must invalidate the cached object.
+	require.NoError(t, bucket.Upload(ctx, path, bytes.NewReader([]byte("v2 with more data"))))
+	third, err := cache.metastoreObject(ctx, bucket, path, fetch)
+	require.NoError(t, err)
+	require.NotSame(t, first, third)
+	require.Equal(t, 2, fetches)
+
+	// Objects without attributes are never cached.
+	_, err = cache.metastoreObject(ctx, bucket, "missing", fetch)
+	require.NoError(t, err)
+	_, err = cache.metastoreObject(ctx, bucket, "missing", fetch)
+	require.NoError(t, err)
+	require.Equal(t, 4, fetches)
+}
+
+func TestObjectCache_IndexObject(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		bucket = objstore.NewInMemBucket()
+	)
+
+	// Upload the same index object at three paths, so that they have the
+	// same size.
+	index := buildIndexObject(t)
+	for _, path := range []string{"a", "b", "c"} {
+		require.NoError(t, bucket.Upload(ctx, path, bytes.NewReader(index)))
+	}
+	object, err := dataobj.FromReaderAt(bytes.NewReader(index), int64(len(index)))
+	require.NoError(t, err)
+	cache := newObjectCache(2*object.MetadataSize(), newObjectMetastoreMetrics())
+
+	fetch := func(path string) *dataobj.Object {
+		object, err := cache.indexObject(ctx, bucket, path)
+		require.NoError(t, err)
+		return object
+	}
+
+	a, b := fetch("a"), fetch("b")
+	require.Same(t, a, fetch("a"))
+	require.Same(t, b, fetch("b"))
+
+	// Adding c must evict the least recently used object a.
+	c := fetch("c")
+	require.Same(t, c, fetch("c"))
+	require.NotSame(t, a, fetch("a"))
+
+	// Data objects are never cached.
+	data := newTestDataBuilder(t, tenantID)
+	require.NoError(t, data.builder.Append(testStreams[0]))
+	var buf bytes.Buffer
+	_, err = data.builder.Flush(&buf)
+	require.NoError(t, err)
+	require.NoError(t, bucket.Upload(ctx, "data", &buf))
+	require.NotSame(t, fetch("data"), fetch("data"))
+}
+
+func buildIndexObject(t *testing.T) []byte {
+	t.Helper()
+
+	builder, err := indexobj.NewBuilder(indexobj.BuilderConfig{
+		TargetPageSize:          1024 * 1024,
+		TargetObjectSize:        10 * 1024 * 1024,
+		TargetSectionSize:       1024 * 1024,
+		BufferSize:              1024 * 1024,
+		SectionStripeMergeLimit: 2,
+	})
+	require.NoError(t, err)
+
+	lbls := labels.FromStrings("app", "foo")
+	id, err := builder.AppendStream(streams.Stream{Labels: lbls, MinTimestamp: now, MaxTimestamp: now})
+	require.NoError(t, err)
+	require.NoError(t, builder.ObserveLogLine("data", 0, 0, id, now, 10))
+
+	var buf bytes.Buffer
+	_, err = builder.Flush(&buf)
+	require.NoError(t, err)
+	return buf.Bytes()
+}
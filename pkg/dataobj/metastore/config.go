@@ -4,21 +4,28 @@ import (
 	"flag"
 
 	"github.com/pkg/errors"
+
+	"github.com/grafana/loki/v3/pkg/util/flagext"
 )
 
 // Config is the configuration block for the metastore settings.
 type Config struct {
 	Updater UpdaterConfig `yaml:"updater" experimental:"true"`
+
+	IndexObjectCacheSize flagext.ByteSize `yaml:"index_object_cache_size" experimental:"true"`
 }
 
 // RegisterFlags registers the flags for the metastore settings.
 func (c *Config) RegisterFlags(f *flag.FlagSet) {
-	c.Updater.RegisterFlagsWithPrefix("dataobj-metastore.", f)
+	c.RegisterFlagsWithPrefix("dataobj-metastore.", f)
 }
 
 // RegisterFlagsWithPrefix registers the flags for the metastore settings with a prefix.
 func (c *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
 	c.Updater.RegisterFlagsWithPrefix(prefix, f)
+
+	_ = c.IndexObjectCacheSize.Set("256MB")
+	f.Var(&c.IndexObjectCacheSize, prefix+"index-object-cache-size", "Maximum size of the metadata of opened index objects cached in memory by queriers. 0 disables the cache.")
 }
 
 // Validate validates the metastore settings.
@@ -11,15 +11,15 @@ type Metastore interface {
 	// Streams returns all streams corresponding to the given matchers between [start,end]
 	Streams(ctx context.Context, start, end time.Time, matchers ...*labels.Matcher) ([]*labels.Labels, error)
 
-	// DataObjects returns paths to all matching the given matchers between [start,end]
-	// TODO(chaudum); The comment is not correct, because the implementation does not filter by matchers, only by [start, end].
+	// DataObjects returns paths to all data objects with streams matching the given matchers between [start,end]
 	DataObjects(ctx context.Context, start, end time.Time, matchers ...*labels.Matcher) ([]string, error)
 
 	// StreamsIDs returns object store paths and stream IDs for all matching objects for the given matchers between [start,end]
 	StreamIDs(ctx context.Context, start, end time.Time, matchers ...*labels.Matcher) ([]string, [][]int64, []int, error)
 
 	// Sections returns a list of SectionDescriptors, including metadata (stream IDs, start & end times, bytes), for the given matchers & predicates between [start,end]
-	Sections(ctx context.Context, start, end time.Time, matchers []*labels.Matcher, predicates []*labels.Matcher) ([]*DataobjSectionDescriptor, error)
+	// Streams are left out of the descriptors if their log lines can't contain all lineFilters, the strings matched by |= line filters.
+	Sections(ctx context.Context, start, end time.Time, matchers []*labels.Matcher, predicates []*labels.Matcher, lineFilters []string) ([]*DataobjSectionDescriptor, error)
 
 	// Labels returns all possible labels from matching streams between [start,end]
 	Labels(ctx context.Context, start, end time.Time, matchers ...*labels.Matcher) ([]string, error) // Used to get possible labels for a given stream
@@ -121,6 +121,7 @@ type objectMetastoreMetrics struct {
 	resolvedSectionsTotalDuration       prometheus.Histogram
 	resolvedSectionsTotal               prometheus.Histogram
 	resolvedSectionsRatio               prometheus.Histogram
+	objectCacheRequests                 *prometheus.CounterVec
 }
 
 func newObjectMetastoreMetrics() *objectMetastoreMetrics {
@@ -221,6 +222,10 @@ func newObjectMetastoreMetrics() *objectMetastoreMetrics {
 			NativeHistogramMaxBucketNumber:  100,
 			NativeHistogramMinResetDuration: 0,
 		}),
+		objectCacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_metastore_object_cache_requests_total",
+			Help: "Total number of lookups of metastore and index objects in the object cache, by object type and result",
+		}, []string{"type", "result"}),
 	}
 
 	return metrics
@@ -237,4 +242,9 @@ func (p *objectMetastoreMetrics) register(reg prometheus.Registerer) {
 	reg.MustRegister(p.estimateSectionsSections)
 	reg.MustRegister(p.resolvedSectionsTotal)
 	reg.MustRegister(p.resolvedSectionsRatio)
+	reg.MustRegister(p.objectCacheRequests)
+}
+
+func (p *objectMetastoreMetrics) incObjectCacheRequests(objectType, result string) {
+	p.objectCacheRequests.WithLabelValues(objectType, result).Inc()
 }
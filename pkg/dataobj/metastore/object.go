@@ -26,12 +26,14 @@ import (
 	"github.com/grafana/loki/v3/pkg/dataobj"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/indexpointers"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/logs"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/ngrams"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/pointers"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
 )
 
 const (
-	metastoreWindowSize = 12 * time.Hour
+	// WindowSize is the time range covered by each metastore object.
+	WindowSize = 12 * time.Hour
 )
 
 type ObjectMetastore struct {
@@ -39,6 +41,7 @@ type ObjectMetastore struct {
 	parallelism int
 	logger      log.Logger
 	metrics     *objectMetastoreMetrics
+	cache       *objectCache
 }
 
 type SectionKey struct {
@@ -86,12 +89,52 @@ func metastorePath(tenantID string, window time.Time) string {
 	return fmt.Sprintf("tenant-%s/metastore/%s.store", tenantID, window.Format(time.RFC3339))
 }
 
+// Tenants returns the IDs of all tenants which have objects in bucket.
+func Tenants(ctx context.Context, bucket objstore.Bucket) ([]string, error) {
+	var tenants []string
+	err := bucket.Iter(ctx, "", func(name string) error {
+		if !strings.HasPrefix(name, "tenant-") || !strings.HasSuffix(name, objstore.DirDelim) {
+			return nil
+		}
+		tenants = append(tenants, strings.TrimSuffix(strings.TrimPrefix(name, "tenant-"), objstore.DirDelim))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(tenants)
+	return tenants, nil
+}
+
+// Windows returns the start times of all metastore windows of tenantID which
+// exist in bucket, in ascending order.
+func Windows(ctx context.Context, bucket objstore.Bucket, tenantID string) ([]time.Time, error) {
+	var (
+		windows []time.Time
+		dir     = fmt.Sprintf("tenant-%s/metastore/", tenantID)
+	)
+	err := bucket.Iter(ctx, dir, func(name string) error {
+		window, err := time.Parse(time.RFC3339, strings.TrimSuffix(strings.TrimPrefix(name, dir), ".store"))
+		if err != nil {
+			// Ignore unrelated objects.
+			return nil
+		}
+		windows = append(windows, window.UTC())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(windows, func(a, b time.Time) int { return a.Compare(b) })
+	return windows, nil
+}
+
 func iterStorePaths(tenantID string, start, end time.Time) iter.Seq[string] {
-	minMetastoreWindow := start.Truncate(metastoreWindowSize).UTC()
-	maxMetastoreWindow := end.Truncate(metastoreWindowSize).UTC()
+	minMetastoreWindow := start.Truncate(WindowSize).UTC()
+	maxMetastoreWindow := end.Truncate(WindowSize).UTC()
 
 	return func(yield func(t string) bool) {
-		for metastoreWindow := minMetastoreWindow; !metastoreWindow.After(maxMetastoreWindow); metastoreWindow = metastoreWindow.Add(metastoreWindowSize) {
+		for metastoreWindow := minMetastoreWindow; !metastoreWindow.After(maxMetastoreWindow); metastoreWindow = metastoreWindow.Add(WindowSize) {
 			if !yield(metastorePath(tenantID, metastoreWindow)) {
 				return
 			}
@@ -99,12 +142,14 @@ func iterStorePaths(tenantID string, start, end time.Time) iter.Seq[string] {
 	}
 }
 
-func NewObjectMetastore(bucket objstore.Bucket, logger log.Logger, reg prometheus.Registerer) *ObjectMetastore {
+func NewObjectMetastore(cfg Config, bucket objstore.Bucket, logger log.Logger, reg prometheus.Registerer) *ObjectMetastore {
+	metrics := newObjectMetastoreMetrics()
 	store := &ObjectMetastore{
 		bucket:      bucket,
 		parallelism: 64,
 		logger:      logger,
-		metrics:     newObjectMetastoreMetrics(),
+		metrics:     metrics,
+		cache:       newObjectCache(cfg.IndexObjectCacheSize.Val(), metrics),
 	}
 	if reg != nil {
 		store.metrics.register(reg)
@@ -197,7 +242,7 @@ func (m *ObjectMetastore) StreamIDs(ctx context.Context, start, end time.Time, m
 	return paths, streamIDs, sections, nil
 }
 
-func (m *ObjectMetastore) Sections(ctx context.Context, start, end time.Time, matchers []*labels.Matcher, predicates []*labels.Matcher) ([]*DataobjSectionDescriptor, error) {
+func (m *ObjectMetastore) Sections(ctx context.Context, start, end time.Time, matchers []*labels.Matcher, predicates []*labels.Matcher, lineFilters []string) ([]*DataobjSectionDescriptor, error) {
 	sectionsTimer := prometheus.NewTimer(m.metrics.resolvedSectionsTotalDuration)
 
 	tenantID, err := tenant.TenantID(ctx)
@@ -223,16 +268,15 @@ func (m *ObjectMetastore) Sections(ctx context.Context, start, end time.Time, ma
 		Start: start,
 		End:   end,
 	}
-	streamSectionPointers, err := m.getSectionsForStreams(ctx, paths, streamMatchers, pointerPredicate)
+	streamSectionPointers, err := m.getSectionsForStreams(ctx, paths, streamMatchers, pointerPredicate, lineFilters)
 	if err != nil {
 		return nil, err
 	}
 	initialSectionPointersCount := len(streamSectionPointers)
 
-	if len(predicates) > 0 {
+	if pointerMatchers := pointerPredicateFromMatchers(predicates...); pointerMatchers != nil {
 		// Search the section AMQs to estimate sections that might match the predicates
 		// AMQs may return false positives so this is an over-estimate.
-		pointerMatchers := pointerPredicateFromMatchers(predicates...)
 		sectionMembershipEstimates, err := m.estimateSectionsForPredicates(ctx, paths, pointerMatchers)
 		if err != nil {
 			return nil, err
@@ -274,12 +318,19 @@ func intersectSections(sectionPointers []*DataobjSectionDescriptor, sectionMembe
 	return sectionPointers[:nextEmptyIdx]
 }
 
-func (m *ObjectMetastore) DataObjects(ctx context.Context, start, end time.Time, _ ...*labels.Matcher) ([]string, error) {
+// DataObjects returns the paths of the data objects containing streams which
+// match matchers between [start,end]. Objects listed in the metastore may
+// either be data objects or index objects, which are resolved to the data
+// objects they point to.
+//
+// If no matchers are given, the objects listed in the metastore between
+// [start,end] are returned without opening them.
+func (m *ObjectMetastore) DataObjects(ctx context.Context, start, end time.Time, matchers ...*labels.Matcher) ([]string, error) {
 	tenantID, err := tenant.TenantID(ctx)
 	if err != nil {
 		return nil, err
 	}
-	level.Debug(m.logger).Log("msg", "ObjectMetastore.DataObjects", "tenant", tenantID, "start", start, "end", end)
+	level.Debug(m.logger).Log("msg", "ObjectMetastore.DataObjects", "tenant", tenantID, "start", start, "end", end, "matchers", matchersToString(matchers))
 
 	// Get all metastore paths for the time range
 	var storePaths []string
@@ -288,7 +339,106 @@ func (m *ObjectMetastore) DataObjects(ctx context.Context, start, end time.Time,
 	}
 
 	// List objects from all stores concurrently
-	return m.listObjectsFromStores(ctx, storePaths, start, end)
+	paths, err := m.listObjectsFromStores(ctx, storePaths, start, end)
+	if err != nil || len(matchers) == 0 {
+		return paths, err
+	}
+
+	predicate := streamPredicateFromMatchers(start, end, matchers...)
+	return m.listDataObjects(ctx, paths, start, end, predicate)
+}
+
+// listDataObjects returns the paths of the data objects with streams matching
+// predicate, given the paths of the objects listed in the metastore.
+func (m *ObjectMetastore) listDataObjects(ctx context.Context, paths []string, start, end time.Time, predicate streams.RowPredicate) ([]string, error) {
+	dataPaths := make([][]string, len(paths))
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(m.parallelism)
+
+	for idx, path := range paths {
+		g.Go(func() error {
+			object, err := m.fetchObject(ctx, path)
+			if err != nil {
+				return fmt.Errorf("fetching object '%s' from bucket: %w", path, err)
+			}
+
+			var matchingStreamIDs []int64
+			err = forEachStream(ctx, object, predicate, func(stream streams.Stream) {
+				matchingStreamIDs = append(matchingStreamIDs, stream.ID)
+			})
+			if err != nil {
+				return fmt.Errorf("reading streams of object '%s': %w", path, err)
+			}
+			if len(matchingStreamIDs) == 0 {
+				return nil
+			}
+
+			if object.Sections().Count(pointers.CheckSection) == 0 {
+				// Not an index object, so path is the data object itself.
+				dataPaths[idx] = []string{path}
+				return nil
+			}
+
+			timeRangePredicate := pointers.TimeRangeRowPredicate{Start: start, End: end}
+			return forEachObjPointer(ctx, object, timeRangePredicate, matchingStreamIDs, func(pointer pointers.SectionPointer) {
+				dataPaths[idx] = append(dataPaths[idx], pointer.Path)
+			})
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return dedupeAndSort(dataPaths), nil
+}
+
+// ObjectInfo describes an object listed in a metastore object.
+type ObjectInfo struct {
+	Path         string
+	MinTimestamp time.Time
+	MaxTimestamp time.Time
+}
+
+// WindowObjects returns all objects listed in the metastore object of the
+// window starting at window, regardless of their time range. WindowObjects
+// returns no objects if the metastore object does not exist.
+func (m *ObjectMetastore) WindowObjects(ctx context.Context, window time.Time) ([]ObjectInfo, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path := metastorePath(tenantID, window.Truncate(WindowSize).UTC())
+	object, err := m.readMetastoreObject(ctx, path)
+	if m.bucket.IsObjNotFoundErr(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading metastore %s: %w", path, err)
+	}
+
+	var objects []ObjectInfo
+	err = forEachStream(ctx, object, nil, func(stream streams.Stream) {
+		if info, ok := objectInfoFromLabels(stream.Labels); ok {
+			objects = append(objects, info)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = forEachIndexPointer(ctx, object, nil, func(indexPointer indexpointers.IndexPointer) {
+		objects = append(objects, ObjectInfo{
+			Path:         indexPointer.Path,
+			MinTimestamp: indexPointer.StartTs.UTC(),
+			MaxTimestamp: indexPointer.EndTs.UTC(),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
 }
 
 func (m *ObjectMetastore) Labels(ctx context.Context, start, end time.Time, matchers ...*labels.Matcher) ([]string, error) {
@@ -347,36 +497,23 @@ func streamPredicateFromMatchers(start, end time.Time, matchers ...*labels.Match
 		IncludeEnd:   true,
 	})
 	for _, matcher := range matchers {
-		switch matcher.Type {
-		case labels.MatchEqual:
+		if matcher.Type == labels.MatchEqual && matcher.Value != "" {
 			predicates = append(predicates, streams.LabelMatcherRowPredicate{
 				Name:  matcher.Name,
 				Value: matcher.Value,
 			})
-		case labels.MatchNotEqual:
-			predicates = append(predicates, streams.NotRowPredicate{
-				Inner: streams.LabelMatcherRowPredicate{
-					Name:  matcher.Name,
-					Value: matcher.Value,
-				},
-			})
-		case labels.MatchRegexp:
-			predicates = append(predicates, streams.LabelFilterRowPredicate{
-				Name: matcher.Name,
-				Keep: func(_, value string) bool {
-					return matcher.Matches(value)
-				},
-			})
-		case labels.MatchNotRegexp:
-			predicates = append(predicates, streams.NotRowPredicate{
-				Inner: streams.LabelFilterRowPredicate{
-					Name: matcher.Name,
-					Keep: func(_, value string) bool {
-						return !matcher.Matches(value)
-					},
-				},
-			})
+			continue
 		}
+
+		// Streams without the label have an empty value for it, so matchers
+		// which match the empty value, such as {env=""} or {env!="prod"},
+		// also keep streams and objects which don't have the label at all.
+		predicates = append(predicates, streams.LabelFilterRowPredicate{
+			Name: matcher.Name,
+			Keep: func(_, value string) bool {
+				return matcher.Matches(value)
+			},
+		})
 	}
 
 	if len(predicates) == 1 {
@@ -402,14 +539,18 @@ func pointerPredicateFromMatchers(matchers ...*labels.Matcher) pointers.RowPredi
 
 	predicates := make([]pointers.RowPredicate, 0, len(matchers)+1)
 	for _, matcher := range matchers {
-		switch matcher.Type {
-		case labels.MatchEqual:
+		// Empty values also match sections without the key, which can't be
+		// ruled out with the blooms.
+		if matcher.Type == labels.MatchEqual && matcher.Value != "" {
 			predicates = append(predicates, pointers.BloomExistenceRowPredicate{
 				Name:  matcher.Name,
 				Value: matcher.Value,
 			})
 		}
 	}
+	if len(predicates) == 0 {
+		return nil
+	}
 
 	current := predicates[0]
 
@@ -457,7 +598,7 @@ func (m *ObjectMetastore) listStreamsFromObjects(ctx context.Context, paths []st
 
 	for _, path := range paths {
 		g.Go(func() error {
-			object, err := dataobj.FromBucket(ctx, m.bucket, path)
+			object, err := m.fetchObject(ctx, path)
 			if err != nil {
 				return fmt.Errorf("getting object from bucket: %w", err)
 			}
@@ -489,7 +630,7 @@ func (m *ObjectMetastore) listStreamIDsFromObjects(ctx context.Context, paths []
 
 	for idx, path := range paths {
 		g.Go(func() error {
-			object, err := dataobj.FromBucket(ctx, m.bucket, path)
+			object, err := m.fetchObject(ctx, path)
 			if err != nil {
 				return fmt.Errorf("getting object from bucket: %w", err)
 			}
@@ -512,7 +653,7 @@ func (m *ObjectMetastore) listStreamIDsFromObjects(ctx context.Context, paths []
 
 // getSectionsForStreams reads the section data from matching streams and aggregates them into section descriptors.
 // This is an exact lookup and includes metadata from the streams in each section: the stream IDs, the min-max timestamps, the number of bytes & number of lines.
-func (m *ObjectMetastore) getSectionsForStreams(ctx context.Context, paths []string, streamPredicate streams.RowPredicate, timeRangePredicate pointers.TimeRangeRowPredicate) ([]*DataobjSectionDescriptor, error) {
+func (m *ObjectMetastore) getSectionsForStreams(ctx context.Context, paths []string, streamPredicate streams.RowPredicate, timeRangePredicate pointers.TimeRangeRowPredicate, lineFilters []string) ([]*DataobjSectionDescriptor, error) {
 	if streamPredicate == nil {
 		// At least one stream matcher is required, currently.
 		return nil, nil
@@ -532,7 +673,7 @@ func (m *ObjectMetastore) getSectionsForStreams(ctx context.Context, paths []str
 			var key SectionKey
 			var matchingStreamIDs []int64
 
-			idxObject, err := fetchObject(ctx, m.bucket, path)
+			idxObject, err := m.fetchObject(ctx, path)
 			if err != nil {
 				return fmt.Errorf("fetching object '%s' from bucket: %w", path, err)
 			}
@@ -551,12 +692,26 @@ func (m *ObjectMetastore) getSectionsForStreams(ctx context.Context, paths []str
 				return nil
 			}
 
+			// Streams whose log lines can't match the line filters are skipped,
+			// so they don't count towards the section descriptors.
+			var excludedStreams map[sectionStreamKey]struct{}
+			if len(lineFilters) > 0 {
+				excludedStreams, err = streamsNotMatchingLineFilters(ctx, idxObject, lineFilters)
+				if err != nil {
+					return fmt.Errorf("reading n-gram index: %w", err)
+				}
+			}
+
 			objectSectionDescriptors := make(map[SectionKey]*DataobjSectionDescriptor)
 			sectionPointerReadTimer := prometheus.NewTimer(m.metrics.streamFilterPointersReadDuration)
 			err = forEachObjPointer(ctx, idxObject, timeRangePredicate, matchingStreamIDs, func(pointer pointers.SectionPointer) {
 				key.ObjectPath = pointer.Path
 				key.SectionIdx = pointer.Section
 
+				if _, excluded := excludedStreams[sectionStreamKey{SectionKey: key, StreamID: pointer.StreamIDRef}]; excluded {
+					return
+				}
+
 				sectionDescriptor, ok := objectSectionDescriptors[key]
 				if !ok {
 					objectSectionDescriptors[key] = NewSectionDescriptor(pointer)
@@ -589,6 +744,59 @@ func (m *ObjectMetastore) getSectionsForStreams(ctx context.Context, paths []str
 	return sectionDescriptors, nil
 }
 
+// sectionStreamKey identifies a stream within a section of a data object.
+type sectionStreamKey struct {
+	SectionKey
+	StreamID int64
+}
+
+// streamsNotMatchingLineFilters uses the n-gram bloom filters of the index
+// object to find the streams of each section for which none of the log lines
+// contain all lineFilters. Streams without n-gram bloom filters, such as those
+// of objects indexed before they were introduced, are never returned.
+func streamsNotMatchingLineFilters(ctx context.Context, object *dataobj.Object, lineFilters []string) (map[sectionStreamKey]struct{}, error) {
+	excluded := make(map[sectionStreamKey]struct{})
+
+	var reader ngrams.RowReader
+	defer reader.Close()
+
+	buf := make([]ngrams.StreamNgrams, 128)
+
+	for _, section := range object.Sections().Filter(ngrams.CheckSection) {
+		sec, err := ngrams.Open(ctx, section)
+		if err != nil {
+			return nil, fmt.Errorf("opening section: %w", err)
+		}
+
+		reader.Reset(sec)
+		for {
+			num, err := reader.Read(ctx, buf)
+			if err != nil && !errors.Is(err, io.EOF) {
+				return nil, err
+			}
+			if num == 0 && errors.Is(err, io.EOF) {
+				break
+			}
+			for _, row := range buf[:num] {
+				filter, err := ngrams.NewNgramFilter(row.NgramBloomFilter)
+				if err != nil {
+					return nil, err
+				}
+				for _, lineFilter := range lineFilters {
+					if !filter.MayContain(lineFilter) {
+						excluded[sectionStreamKey{
+							SectionKey: SectionKey{ObjectPath: row.Path, SectionIdx: row.Section},
+							StreamID:   row.StreamID,
+						}] = struct{}{}
+						break
+					}
+				}
+			}
+		}
+	}
+	return excluded, nil
+}
+
 // estimateSectionsForPredicates checks the predicates against the section AMQs to determine approximate section membership.
 // This is an inexact lookup and only returns probable sections: there may be false positives, but no true negatives. There is no additional metadata returned beyond the section info.
 func (m *ObjectMetastore) estimateSectionsForPredicates(ctx context.Context, paths []string, predicate pointers.RowPredicate) ([]*DataobjSectionDescriptor, error) {
@@ -602,7 +810,7 @@ func (m *ObjectMetastore) estimateSectionsForPredicates(ctx context.Context, pat
 	var sectionDescriptorsMutex sync.Mutex
 	for _, path := range paths {
 		g.Go(func() error {
-			idxObject, err := fetchObject(ctx, m.bucket, path)
+			idxObject, err := m.fetchObject(ctx, path)
 			if err != nil {
 				return fmt.Errorf("fetching object from bucket: %w", err)
 			}
@@ -639,8 +847,10 @@ func (m *ObjectMetastore) estimateSectionsForPredicates(ctx context.Context, pat
 	return sectionDescriptors, nil
 }
 
-func fetchObject(ctx context.Context, bucket objstore.Bucket, path string) (*dataobj.Object, error) {
-	return dataobj.FromBucket(ctx, bucket, path)
+// fetchObject opens the object at path, which is listed in a metastore
+// object. Opened objects are cached.
+func (m *ObjectMetastore) fetchObject(ctx context.Context, path string) (*dataobj.Object, error) {
+	return m.cache.indexObject(ctx, m.bucket, path)
 }
 
 func addLabels(mtx *sync.Mutex, streams map[uint64][]*labels.Labels, newLabels *labels.Labels) {
@@ -663,19 +873,10 @@ func addLabels(mtx *sync.Mutex, streams map[uint64][]*labels.Labels, newLabels *
 }
 
 func (m *ObjectMetastore) listObjects(ctx context.Context, path string, start, end time.Time) ([]string, error) {
-	var buf bytes.Buffer
-	objectReader, err := m.bucket.Get(ctx, path)
+	object, err := m.readMetastoreObject(ctx, path)
 	if err != nil {
 		return nil, err
 	}
-	n, err := buf.ReadFrom(objectReader)
-	if err != nil {
-		return nil, fmt.Errorf("reading metastore object: %w", err)
-	}
-	object, err := dataobj.FromReaderAt(bytes.NewReader(buf.Bytes()), n)
-	if err != nil {
-		return nil, fmt.Errorf("getting object from reader: %w", err)
-	}
 	var objectPaths []string
 
 	// First we iterate over index objects based on the old format.
@@ -704,6 +905,36 @@ func (m *ObjectMetastore) listObjects(ctx context.Context, path string, start, e
 	return objectPaths, nil
 }
 
+// readMetastoreObject returns the metastore object at path. Metastore objects
+// are cached as long as they are unchanged.
+func (m *ObjectMetastore) readMetastoreObject(ctx context.Context, path string) (*dataobj.Object, error) {
+	return m.cache.metastoreObject(ctx, m.bucket, path, m.downloadMetastoreObject)
+}
+
+// downloadMetastoreObject downloads the metastore object at path into memory.
+func (m *ObjectMetastore) downloadMetastoreObject(ctx context.Context, path string) (*dataobj.Object, error) {
+	var buf bytes.Buffer
+	objectReader, err := m.bucket.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer objectReader.Close()
+
+	n, err := buf.ReadFrom(objectReader)
+	if err != nil {
+		return nil, fmt.Errorf("reading metastore object: %w", err)
+	}
+	if n == 0 {
+		// All objects of the window were removed, see [Updater.Replace].
+		return &dataobj.Object{}, nil
+	}
+	object, err := dataobj.FromReaderAt(bytes.NewReader(buf.Bytes()), n)
+	if err != nil {
+		return nil, fmt.Errorf("getting object from reader: %w", err)
+	}
+	return object, nil
+}
+
 func forEachIndexPointer(ctx context.Context, object *dataobj.Object, predicate indexpointers.RowPredicate, f func(indexpointers.IndexPointer)) error {
 	var reader indexpointers.RowReader
 	defer reader.Close()
@@ -838,6 +1069,19 @@ func dedupeAndSort(objects [][]string) []string {
 
 // objectOverlapsRange checks if an object's time range overlaps with the query range
 func objectOverlapsRange(lbs labels.Labels, start, end time.Time) (bool, string) {
+	info, ok := objectInfoFromLabels(lbs)
+	if !ok {
+		return false, ""
+	}
+	if info.MaxTimestamp.Before(start) || info.MinTimestamp.After(end) {
+		return false, ""
+	}
+	return true, info.Path
+}
+
+// objectInfoFromLabels returns the object described by the labels of a stream
+// in a metastore object of the old format.
+func objectInfoFromLabels(lbs labels.Labels) (ObjectInfo, bool) {
 	var (
 		objStart, objEnd time.Time
 		objPath          string
@@ -864,10 +1108,7 @@ func objectOverlapsRange(lbs labels.Labels, start, end time.Time) (bool, string)
 	})
 
 	if objStart.IsZero() || objEnd.IsZero() {
-		return false, ""
-	}
-	if objEnd.Before(start) || objStart.After(end) {
-		return false, ""
+		return ObjectInfo{}, false
 	}
-	return true, objPath
+	return ObjectInfo{Path: objPath, MinTimestamp: objStart, MaxTimestamp: objEnd}, true
 }
@@ -17,6 +17,7 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/dataobj/consumer/logsobj"
 	"github.com/grafana/loki/v3/pkg/dataobj/index/indexobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/ngrams"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
 	"github.com/grafana/loki/v3/pkg/dataobj/uploader"
 	"github.com/grafana/loki/v3/pkg/logproto"
@@ -291,7 +292,7 @@ func TestSectionsForStreamMatchers(t *testing.T) {
 	err = metastoreUpdater.Update(context.Background(), path, stats.MinTimestamp, stats.MaxTimestamp)
 	require.NoError(t, err)
 
-	mstore := NewObjectMetastore(bucket, log.NewNopLogger(), prometheus.NewPedanticRegistry())
+	mstore := NewObjectMetastore(Config{}, bucket, log.NewNopLogger(), prometheus.NewPedanticRegistry())
 
 	tests := []struct {
 		name       string
@@ -325,13 +326,337 @@ func TestSectionsForStreamMatchers(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			sections, err := mstore.Sections(ctx, now.Add(-time.Hour), now.Add(time.Hour), tt.matchers, tt.predicates)
+			sections, err := mstore.Sections(ctx, now.Add(-time.Hour), now.Add(time.Hour), tt.matchers, tt.predicates, nil)
 			require.NoError(t, err)
 			require.Len(t, sections, tt.wantCount)
 		})
 	}
 }
 
+func TestSectionsForLineFilters(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), tenantID)
+
+	builder, err := indexobj.NewBuilder(indexobj.BuilderConfig{
+		TargetPageSize:          1024 * 1024,
+		TargetObjectSize:        10 * 1024 * 1024,
+		TargetSectionSize:       1024 * 1024,
+		BufferSize:              1024 * 1024,
+		SectionStripeMergeLimit: 2,
+	})
+	require.NoError(t, err)
+
+	// Each stream is stored in its own section of the data object. The last
+	// stream has no n-gram bloom filter, like the streams of objects indexed
+	// before they were introduced.
+	sectionStreams := []struct {
+		labels string
+		lines  []string
+	}{
+		{labels: `{app="foo", env="prod"}`, lines: []string{"connection refused", "retrying"}},
+		{labels: `{app="foo", env="dev"}`, lines: []string{"request completed"}},
+		{labels: `{app="foo", env="test"}`},
+	}
+	for i, s := range sectionStreams {
+		lbls, err := syntax.ParseLabels(s.labels)
+		require.NoError(t, err)
+
+		streamID := int64(i + 1)
+		newIdx, err := builder.AppendStream(streams.Stream{
+			ID:           streamID,
+			Labels:       lbls,
+			MinTimestamp: now,
+			MaxTimestamp: now,
+		})
+		require.NoError(t, err)
+		err = builder.ObserveLogLine("test-path", int64(i), streamID, newIdx, now, 1)
+		require.NoError(t, err)
+
+		if s.lines == nil {
+			continue
+		}
+		var set ngrams.NgramSet
+		for _, line := range s.lines {
+			set.AddLine([]byte(line))
+		}
+		bloomFilter, err := set.BloomFilter()
+		require.NoError(t, err)
+		require.NoError(t, builder.AppendNgrams("test-path", int64(i), streamID, bloomFilter))
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, 1024*1024))
+	stats, err := builder.Flush(buf)
+	require.NoError(t, err)
+
+	bucket := objstore.NewInMemBucket()
+
+	uploader := uploader.New(uploader.Config{SHAPrefixSize: 2}, bucket, tenantID, log.NewNopLogger())
+	require.NoError(t, uploader.RegisterMetrics(prometheus.NewPedanticRegistry()))
+
+	path, err := uploader.Upload(context.Background(), buf)
+	require.NoError(t, err)
+
+	metastoreUpdater := NewUpdater(UpdaterConfig{}, bucket, tenantID, log.NewNopLogger())
+	err = metastoreUpdater.Update(context.Background(), path, stats.MinTimestamp, stats.MaxTimestamp)
+	require.NoError(t, err)
+
+	mstore := NewObjectMetastore(Config{}, bucket, log.NewNopLogger(), prometheus.NewPedanticRegistry())
+	matchers := []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "app", "foo")}
+
+	tests := []struct {
+		name         string
+		lineFilters  []string
+		wantSections []int64
+	}{
+		{
+			name:         "no line filters",
+			lineFilters:  nil,
+			wantSections: []int64{0, 1, 2},
+		},
+		{
+			name:         "line filter matching a single stream",
+			lineFilters:  []string{"refused"},
+			wantSections: []int64{0, 2},
+		},
+		{
+			name:         "line filters matching different streams",
+			lineFilters:  []string{"refused", "completed"},
+			wantSections: []int64{2},
+		},
+		{
+			name:         "line filter matching no stream",
+			lineFilters:  []string{"timeout"},
+			wantSections: []int64{2},
+		},
+		{
+			name:         "line filter too short to look up",
+			lineFilters:  []string{"re"},
+			wantSections: []int64{0, 1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sections, err := mstore.Sections(ctx, now.Add(-time.Hour), now.Add(time.Hour), matchers, nil, tt.lineFilters)
+			require.NoError(t, err)
+
+			var actual []int64
+			for _, section := range sections {
+				require.Equal(t, []int64{section.SectionIdx + 1}, section.StreamIDs)
+				actual = append(actual, section.SectionIdx)
+			}
+			slices.Sort(actual)
+			require.Equal(t, tt.wantSections, actual)
+		})
+	}
+}
+
+func TestDataObjects(t *testing.T) {
+	tests := []struct {
+		name     string
+		matchers []*labels.Matcher
+		want     int
+	}{
+		{
+			name: "no matchers returns all objects",
+			want: 5,
+		},
+		{
+			name: "matching streams",
+			matchers: []*labels.Matcher{
+				labels.MustNewMatcher(labels.MatchEqual, "app", "foo"),
+			},
+			want: 2,
+		},
+		{
+			name: "matching streams with regexp",
+			matchers: []*labels.Matcher{
+				labels.MustNewMatcher(labels.MatchRegexp, "app", "ba.+"),
+				labels.MustNewMatcher(labels.MatchEqual, "env", "prod"),
+			},
+			want: 2,
+		},
+		{
+			name: "not matching streams",
+			matchers: []*labels.Matcher{
+				labels.MustNewMatcher(labels.MatchEqual, "app", "doesnotexist"),
+			},
+			want: 0,
+		},
+		{
+			name: "empty value matches streams without the label",
+			matchers: []*labels.Matcher{
+				labels.MustNewMatcher(labels.MatchEqual, "team", ""),
+			},
+			want: 4,
+		},
+		{
+			name: "regexp matching empty value matches streams without the label",
+			matchers: []*labels.Matcher{
+				labels.MustNewMatcher(labels.MatchRegexp, "team", ".*"),
+			},
+			want: 5,
+		},
+		{
+			name: "not equal matches streams without the label",
+			matchers: []*labels.Matcher{
+				labels.MustNewMatcher(labels.MatchNotEqual, "team", "a"),
+			},
+			want: 4,
+		},
+		{
+			name: "not equal empty value doesn't match streams without the label",
+			matchers: []*labels.Matcher{
+				labels.MustNewMatcher(labels.MatchNotEqual, "team", ""),
+			},
+			want: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queryMetastore(t, tenantID, func(ctx context.Context, start, end time.Time, mstore Metastore) {
+				paths, err := mstore.DataObjects(ctx, start, end, tt.matchers...)
+				require.NoError(t, err)
+				require.Len(t, paths, tt.want)
+			})
+		})
+	}
+}
+
+func TestDataObjectsFromIndex(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), tenantID)
+
+	builder, err := indexobj.NewBuilder(indexobj.BuilderConfig{
+		TargetPageSize:          1024 * 1024,
+		TargetObjectSize:        10 * 1024 * 1024,
+		TargetSectionSize:       128,
+		BufferSize:              1024 * 1024,
+		SectionStripeMergeLimit: 2,
+	})
+	require.NoError(t, err)
+
+	// Every app is stored in its own data object.
+	for i, ts := range testStreams {
+		lbls, err := syntax.ParseLabels(ts.Labels)
+		require.NoError(t, err)
+
+		newIdx, err := builder.AppendStream(streams.Stream{
+			ID:           int64(i),
+			Labels:       lbls,
+			MinTimestamp: ts.Entries[0].Timestamp,
+			MaxTimestamp: ts.Entries[0].Timestamp,
+		})
+		require.NoError(t, err)
+		err = builder.ObserveLogLine(lbls.Get("app")+".obj", 0, int64(i), newIdx, ts.Entries[0].Timestamp, int64(len(ts.Entries[0].Line)))
+		require.NoError(t, err)
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, 1024*1024))
+	stats, err := builder.Flush(buf)
+	require.NoError(t, err)
+
+	bucket := objstore.NewInMemBucket()
+	path, err := uploader.New(uploader.Config{SHAPrefixSize: 2}, bucket, tenantID, log.NewNopLogger()).Upload(context.Background(), buf)
+	require.NoError(t, err)
+	err = NewUpdater(UpdaterConfig{}, bucket, tenantID, log.NewNopLogger()).Update(context.Background(), path, stats.MinTimestamp, stats.MaxTimestamp)
+	require.NoError(t, err)
+
+	mstore := NewObjectMetastore(Config{}, bucket, log.NewNopLogger(), prometheus.NewPedanticRegistry())
+
+	tests := []struct {
+		name     string
+		matchers []*labels.Matcher
+		want     []string
+	}{
+		{
+			name: "matching streams",
+			matchers: []*labels.Matcher{
+				labels.MustNewMatcher(labels.MatchEqual, "app", "foo"),
+			},
+			want: []string{"foo.obj"},
+		},
+		{
+			name: "matching streams with regexp",
+			matchers: []*labels.Matcher{
+				labels.MustNewMatcher(labels.MatchRegexp, "app", "ba.+"),
+			},
+			want: []string{"bar.obj", "baz.obj"},
+		},
+		{
+			name: "not matching streams",
+			matchers: []*labels.Matcher{
+				labels.MustNewMatcher(labels.MatchEqual, "app", "doesnotexist"),
+			},
+			want: []string{},
+		},
+		{
+			name: "empty value matches streams without the label",
+			matchers: []*labels.Matcher{
+				labels.MustNewMatcher(labels.MatchEqual, "team", ""),
+			},
+			want: []string{"bar.obj", "foo.obj"},
+		},
+		{
+			name: "regexp matching empty value matches streams without the label",
+			matchers: []*labels.Matcher{
+				labels.MustNewMatcher(labels.MatchRegexp, "team", ".*"),
+			},
+			want: []string{"bar.obj", "baz.obj", "foo.obj"},
+		},
+		{
+			name: "not equal matches streams without the label",
+			matchers: []*labels.Matcher{
+				labels.MustNewMatcher(labels.MatchNotEqual, "team", "a"),
+			},
+			want: []string{"bar.obj", "foo.obj"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths, err := mstore.DataObjects(ctx, now.Add(-time.Hour), now.Add(time.Hour), tt.matchers...)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, paths)
+		})
+	}
+}
+
+func TestWindows(t *testing.T) {
+	builder := newTestDataBuilder(t, tenantID)
+	for _, stream := range testStreams {
+		builder.addStreamAndFlush(stream)
+	}
+	ctx := user.InjectOrgID(context.Background(), tenantID)
+
+	tenants, err := Tenants(ctx, builder.bucket)
+	require.NoError(t, err)
+	require.Equal(t, []string{tenantID}, tenants)
+
+	windows, err := Windows(ctx, builder.bucket, tenantID)
+	require.NoError(t, err)
+	require.NotEmpty(t, windows)
+	require.True(t, slices.IsSortedFunc(windows, func(a, b time.Time) int { return a.Compare(b) }))
+
+	mstore := NewObjectMetastore(Config{}, builder.bucket, log.NewNopLogger(), nil)
+
+	// Every object must be listed in the windows it overlaps with.
+	var total int
+	for _, window := range windows {
+		objects, err := mstore.WindowObjects(ctx, window)
+		require.NoError(t, err)
+		for _, obj := range objects {
+			require.False(t, obj.MaxTimestamp.Before(window))
+			require.True(t, obj.MinTimestamp.Before(window.Add(WindowSize)))
+		}
+		total += len(objects)
+	}
+	require.GreaterOrEqual(t, total, len(testStreams))
+
+	objects, err := mstore.WindowObjects(ctx, windows[0].Add(-WindowSize))
+	require.NoError(t, err)
+	require.Empty(t, objects)
+}
+
 func queryMetastore(t *testing.T, tenantID string, mfunc func(context.Context, time.Time, time.Time, Metastore)) {
 	now := time.Now().UTC()
 	start := now.Add(-time.Hour * 5)
@@ -343,7 +668,7 @@ func queryMetastore(t *testing.T, tenantID string, mfunc func(context.Context, t
 		builder.addStreamAndFlush(stream)
 	}
 
-	mstore := NewObjectMetastore(builder.bucket, log.NewNopLogger(), nil)
+	mstore := NewObjectMetastore(Config{}, builder.bucket, log.NewNopLogger(), nil)
 	defer func() {
 		require.NoError(t, mstore.bucket.Close())
 	}()
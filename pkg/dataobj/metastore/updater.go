@@ -118,6 +118,36 @@ func (m *Updater) initBuilder() error {
 
 // Update adds provided dataobj path to the metastore. Flush stats are used to determine the stored metadata about this dataobj.
 func (m *Updater) Update(ctx context.Context, dataobjPath string, minTimestamp, maxTimestamp time.Time) error {
+	return m.update(ctx, nil, dataobjPath, minTimestamp, maxTimestamp)
+}
+
+// Replace replaces the objects at oldPaths with the object at dataobjPath in
+// the metastore objects between minTimestamp and maxTimestamp. Each metastore
+// object is rewritten in a single write, so readers never observe both the old
+// and new objects for a window, nor neither of them.
+//
+// Old objects are only removed from the metastore objects between
+// minTimestamp and maxTimestamp, so the time range must cover the time ranges
+// of all old objects. If dataobjPath is empty, the old objects are removed
+// without replacement, and metastore objects which become empty are replaced
+// with empty objects.
+//
+// Existing entries for dataobjPath are replaced too, so calling Replace again
+// with the same arguments has no further effect.
+func (m *Updater) Replace(ctx context.Context, oldPaths []string, dataobjPath string, minTimestamp, maxTimestamp time.Time) error {
+	removed := make(map[string]struct{}, len(oldPaths)+1)
+	for _, path := range oldPaths {
+		removed[path] = struct{}{}
+	}
+	if dataobjPath != "" {
+		removed[dataobjPath] = struct{}{}
+	}
+	return m.update(ctx, removed, dataobjPath, minTimestamp, maxTimestamp)
+}
+
+// update adds dataobjPath to the metastore objects between minTimestamp and
+// maxTimestamp, dropping any existing entries for the paths in removed.
+func (m *Updater) update(ctx context.Context, removed map[string]struct{}, dataobjPath string, minTimestamp, maxTimestamp time.Time) error {
 	var err error
 	processingTime := prometheus.NewTimer(m.metrics.metastoreProcessingTime)
 	defer processingTime.ObserveDuration()
@@ -151,6 +181,7 @@ func (m *Updater) Update(ctx context.Context, dataobjPath string, minTimestamp,
 				m.metastoreBuilder.Reset()
 				m.builder.Reset()
 				ty := m.cfg.StorageFormat
+				retained := 0
 
 				if m.buf.Len() > 0 {
 					replayDuration := prometheus.NewTimer(m.metrics.metastoreReplayTime)
@@ -158,19 +189,27 @@ func (m *Updater) Update(ctx context.Context, dataobjPath string, minTimestamp,
 					if err != nil {
 						return nil, errors.Wrap(err, "creating object from buffer")
 					}
-					ty, err = m.readFromExisting(ctx, object)
+					ty, retained, err = m.readFromExisting(ctx, object, removed)
 					if err != nil {
 						return nil, errors.Wrap(err, "reading existing metastore version")
 					}
 					replayDuration.ObserveDuration()
 				}
 
-				encodingDuration := prometheus.NewTimer(m.metrics.metastoreEncodingTime)
-				err = m.append(ty, dataobjPath, minTimestamp, maxTimestamp)
-				if err != nil {
+				if dataobjPath == "" {
+					if retained == 0 {
+						// Data objects can't be empty, so an empty object is
+						// written instead. Unlike deleting the metastore
+						// object, this doesn't race with concurrent updates.
+						m.buf.Reset()
+						return io.NopCloser(m.buf), nil
+					}
+				} else if err := m.append(ty, dataobjPath, minTimestamp, maxTimestamp); err != nil {
 					return nil, errors.Wrap(err, "appending to metastore builder")
 				}
 
+				encodingDuration := prometheus.NewTimer(m.metrics.metastoreEncodingTime)
+
 				m.buf.Reset()
 
 				switch ty {
@@ -237,7 +276,10 @@ func (m *Updater) append(ty StorageFormatType, dataobjPath string, minTimestamp,
 }
 
 // readFromExisting reads the provided metastore object and appends the streams to the builder so it can be later modified.
-func (m *Updater) readFromExisting(ctx context.Context, object *dataobj.Object) (StorageFormatType, error) {
+// Entries for the object paths in removed are not appended. The number of appended entries is returned.
+func (m *Updater) readFromExisting(ctx context.Context, object *dataobj.Object, removed map[string]struct{}) (StorageFormatType, int, error) {
+	var retained int
+
 	var streamsReader streams.RowReader
 	defer streamsReader.Close()
 
@@ -258,48 +300,56 @@ func (m *Updater) readFromExisting(ctx context.Context, object *dataobj.Object)
 		case streams.CheckSection(section):
 			sec, err := streams.Open(ctx, section)
 			if err != nil {
-				return StorageFormatTypeV1, errors.Wrap(err, "opening section")
+				return StorageFormatTypeV1, retained, errors.Wrap(err, "opening section")
 			}
 
 			streamsReader.Reset(sec)
 			for n, err := streamsReader.Read(ctx, buf); n > 0; n, err = streamsReader.Read(ctx, buf) {
 				if err != nil && err != io.EOF {
-					return StorageFormatTypeV1, errors.Wrap(err, "reading streams")
+					return StorageFormatTypeV1, retained, errors.Wrap(err, "reading streams")
 				}
 				for _, stream := range buf[:n] {
+					if _, ok := removed[stream.Labels.Get(labelNamePath)]; ok {
+						continue
+					}
 					err = m.metastoreBuilder.Append(logproto.Stream{
 						Labels:  stream.Labels.String(),
 						Entries: []logproto.Entry{{Line: ""}},
 					})
 					if err != nil {
-						return StorageFormatTypeV1, errors.Wrap(err, "appending streams")
+						return StorageFormatTypeV1, retained, errors.Wrap(err, "appending streams")
 					}
+					retained++
 				}
 			}
 
-			return StorageFormatTypeV1, nil
+			return StorageFormatTypeV1, retained, nil
 		// New standard approach for metastore top-level objects.
 		case indexpointers.CheckSection(section):
 			sec, err := indexpointers.Open(ctx, section)
 			if err != nil {
-				return StorageFormatTypeV2, errors.Wrap(err, "opening section")
+				return StorageFormatTypeV2, retained, errors.Wrap(err, "opening section")
 			}
 			indexPointersReader.Reset(sec)
 			for n, err := indexPointersReader.Read(ctx, pbuf); n > 0; n, err = indexPointersReader.Read(ctx, pbuf) {
 				if err != nil && err != io.EOF {
-					return StorageFormatTypeV2, errors.Wrap(err, "reading index pointers")
+					return StorageFormatTypeV2, retained, errors.Wrap(err, "reading index pointers")
 				}
 				for _, indexPointer := range pbuf[:n] {
+					if _, ok := removed[indexPointer.Path]; ok {
+						continue
+					}
 					err = m.builder.AppendIndexPointer(indexPointer.Path, indexPointer.StartTs, indexPointer.EndTs)
 					if err != nil {
-						return StorageFormatTypeV2, errors.Wrap(err, "appending index pointers")
+						return StorageFormatTypeV2, retained, errors.Wrap(err, "appending index pointers")
 					}
+					retained++
 				}
 			}
 
-			return StorageFormatTypeV2, nil
+			return StorageFormatTypeV2, retained, nil
 		}
 	}
 
-	return m.cfg.StorageFormat, nil
+	return m.cfg.StorageFormat, retained, nil
 }
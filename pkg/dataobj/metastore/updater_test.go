@@ -11,6 +11,7 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/dskit/user"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/require"
@@ -104,12 +105,74 @@ func TestUpdater(t *testing.T) {
 		dobj, err := dataobj.FromReaderAt(bytes.NewReader(object), int64(len(object)))
 		require.NoError(t, err)
 
-		ty, err := updater.readFromExisting(context.Background(), dobj)
+		ty, _, err := updater.readFromExisting(context.Background(), dobj, nil)
 		require.NoError(t, err)
 		require.Equal(t, StorageFormatTypeV1, ty)
 	})
 }
 
+func TestUpdater_Replace(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		format StorageFormatType
+	}{
+		{name: "metastore v1", format: StorageFormatTypeV1},
+		{name: "metastore v2", format: StorageFormatTypeV2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tenantID := "test"
+			bucket := objstore.NewInMemBucket()
+
+			updater := NewUpdater(UpdaterConfig{StorageFormat: tc.format}, bucket, tenantID, log.NewNopLogger())
+			for _, path := range []string{"testdata/a.obj", "testdata/b.obj", "testdata/c.obj"} {
+				err := updater.Update(context.Background(), path, unixTime(10), unixTime(20))
+				require.NoError(t, err)
+			}
+
+			err := updater.Replace(context.Background(), []string{"testdata/a.obj", "testdata/b.obj"}, "testdata/ab.obj", unixTime(10), unixTime(20))
+			require.NoError(t, err)
+			// Replacing again has no further effect.
+			err = updater.Replace(context.Background(), []string{"testdata/a.obj", "testdata/b.obj"}, "testdata/ab.obj", unixTime(10), unixTime(20))
+			require.NoError(t, err)
+
+			ctx := user.InjectOrgID(context.Background(), tenantID)
+			objects, err := NewObjectMetastore(Config{}, bucket, log.NewNopLogger(), nil).WindowObjects(ctx, unixTime(0))
+			require.NoError(t, err)
+			require.ElementsMatch(t, []ObjectInfo{
+				{Path: "testdata/c.obj", MinTimestamp: unixTime(10), MaxTimestamp: unixTime(20)},
+				{Path: "testdata/ab.obj", MinTimestamp: unixTime(10), MaxTimestamp: unixTime(20)},
+			}, objects)
+
+			// Removing objects without replacement empties metastore objects
+			// which have no entries left.
+			err = updater.Replace(context.Background(), []string{"testdata/ab.obj"}, "", unixTime(10), unixTime(20))
+			require.NoError(t, err)
+			err = updater.Replace(context.Background(), []string{"testdata/c.obj"}, "", unixTime(10), unixTime(20))
+			require.NoError(t, err)
+			reader, err := bucket.Get(context.Background(), metastorePath(tenantID, unixTime(0)))
+			require.NoError(t, err)
+			data, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			require.Empty(t, data)
+
+			mstore := NewObjectMetastore(Config{}, bucket, log.NewNopLogger(), nil)
+			objects, err = mstore.WindowObjects(ctx, unixTime(0))
+			require.NoError(t, err)
+			require.Empty(t, objects)
+			paths, err := mstore.DataObjects(ctx, unixTime(0), unixTime(30))
+			require.NoError(t, err)
+			require.Empty(t, paths)
+
+			// Empty metastore objects can be updated again.
+			err = updater.Update(context.Background(), "testdata/d.obj", unixTime(10), unixTime(20))
+			require.NoError(t, err)
+			objects, err = mstore.WindowObjects(ctx, unixTime(0))
+			require.NoError(t, err)
+			require.Equal(t, []ObjectInfo{{Path: "testdata/d.obj", MinTimestamp: unixTime(10), MaxTimestamp: unixTime(20)}}, objects)
+		})
+	}
+}
+
 func newUpdater(t *testing.T, tenantID string, bucket objstore.Bucket, v1 *logsobj.Builder, v2 *indexobj.Builder) *Updater {
 	t.Helper()
 
@@ -35,7 +35,16 @@ var streamsPool = sync.Pool{
 func (s *Store) SelectSeries(ctx context.Context, req logql.SelectLogParams) ([]logproto.SeriesIdentifier, error) {
 	logger := util_log.WithContext(ctx, s.logger)
 
-	objects, err := s.objectsForTimeRange(ctx, req.Start, req.End, logger)
+	var matchers []*labels.Matcher
+	if req.Selector != "" {
+		expr, err := req.LogSelector()
+		if err != nil {
+			return nil, err
+		}
+		matchers = expr.Matchers()
+	}
+
+	objects, err := s.objectsForTimeRange(ctx, req.Start, req.End, matchers, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -49,15 +58,6 @@ func (s *Store) SelectSeries(ctx context.Context, req logql.SelectLogParams) ([]
 		return nil, err
 	}
 
-	var matchers []*labels.Matcher
-	if req.Selector != "" {
-		expr, err := req.LogSelector()
-		if err != nil {
-			return nil, err
-		}
-		matchers = expr.Matchers()
-	}
-
 	uniqueSeries := &sync.Map{}
 
 	processor := newStreamProcessor(req.Start, req.End, matchers, objects, shard, logger)
@@ -85,7 +85,7 @@ func (s *Store) SelectSeries(ctx context.Context, req logql.SelectLogParams) ([]
 func (s *Store) LabelNamesForMetricName(ctx context.Context, _ string, from, through model.Time, _ string, matchers ...*labels.Matcher) ([]string, error) {
 	logger := util_log.WithContext(ctx, s.logger)
 	start, end := from.Time(), through.Time()
-	objects, err := s.objectsForTimeRange(ctx, start, end, logger)
+	objects, err := s.objectsForTimeRange(ctx, start, end, matchers, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -129,7 +129,7 @@ func (s *Store) LabelValuesForMetricName(ctx context.Context, _ string, from, th
 
 	matchers = append(matchers, requireLabel)
 
-	objects, err := s.objectsForTimeRange(ctx, start, end, logger)
+	objects, err := s.objectsForTimeRange(ctx, start, end, matchers, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -259,6 +259,9 @@ func (sp *streamProcessor) processSingleReader(ctx context.Context, reader *stre
 			break
 		}
 		for _, stream := range streams[:n] {
+			if !matchBoundedShard(sp.shard, stream.Labels) {
+				continue
+			}
 			h, buf = stream.Labels.HashWithoutLabels(buf, []string(nil)...)
 			// Try to claim this hash first
 			if _, seen := sp.seenSeries.LoadOrStore(h, nil); seen {
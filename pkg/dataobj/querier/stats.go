@@ -0,0 +1,238 @@
+package querier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/storage/chunk"
+	"github.com/grafana/loki/v3/pkg/storage/stores/index/seriesvolume"
+	"github.com/grafana/loki/v3/pkg/storage/stores/index/stats"
+	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper/tsdb/sharding"
+	"github.com/grafana/loki/v3/pkg/util"
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+)
+
+// Stats implements querier.Store
+//
+// Stats are computed from the streams sections of data objects, which record
+// the number of rows and the uncompressed size of each stream. A stream in a
+// data object is counted as one chunk. Streams which partially overlap
+// [from, through] are accounted proportionally to the overlap.
+func (s *Store) Stats(ctx context.Context, _ string, from, through model.Time, matchers ...*labels.Matcher) (*stats.Stats, error) {
+	logger := util_log.WithContext(ctx, s.logger)
+	start, end := from.Time(), through.Time()
+
+	objects, err := s.objectsForTimeRange(ctx, start, end, matchers, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		res  = &stats.Stats{}
+		seen = make(map[uint64]struct{})
+	)
+	err = forEachMatchingStream(ctx, objects, start, end, matchers, func(stream streams.Stream) {
+		if _, ok := seen[labels.StableHash(stream.Labels)]; !ok {
+			seen[labels.StableHash(stream.Labels)] = struct{}{}
+			res.Streams++
+		}
+
+		entries, bytes := streamStatsInRange(stream, start, end)
+		res.Chunks++
+		res.Entries += entries
+		res.Bytes += bytes
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Volume implements querier.Store
+//
+// Like Stats, volumes are computed from the uncompressed size of streams
+// recorded in the streams sections of data objects.
+func (s *Store) Volume(ctx context.Context, _ string, from, through model.Time, limit int32, targetLabels []string, aggregateBy string, matchers ...*labels.Matcher) (*logproto.VolumeResponse, error) {
+	logger := util_log.WithContext(ctx, s.logger)
+	start, end := from.Time(), through.Time()
+
+	labelsToMatch, matchers, includeAll := util.PrepareLabelsAndMatchers(targetLabels, matchers)
+	aggregateBySeries := seriesvolume.AggregateBySeries(aggregateBy) || aggregateBy == ""
+
+	objects, err := s.objectsForTimeRange(ctx, start, end, matchers, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		volumes = make(map[string]uint64)
+		builder = labels.NewScratchBuilder(len(labelsToMatch))
+	)
+	err = forEachMatchingStream(ctx, objects, start, end, matchers, func(stream streams.Stream) {
+		entries, bytes := streamStatsInRange(stream, start, end)
+		if entries == 0 {
+			return
+		}
+
+		if aggregateBySeries {
+			builder.Reset()
+			stream.Labels.Range(func(l labels.Label) {
+				if _, ok := labelsToMatch[l.Name]; includeAll || ok {
+					builder.Add(l.Name, l.Value)
+				}
+			})
+			builder.Sort()
+			volumes[builder.Labels().String()] += bytes
+			return
+		}
+
+		// When aggregating by labels, capture sizes for target labels if
+		// provided, otherwise for all labels.
+		stream.Labels.Range(func(l labels.Label) {
+			if _, ok := labelsToMatch[l.Name]; len(targetLabels) == 0 || includeAll || ok {
+				volumes[l.Name] += bytes
+			}
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return seriesvolume.MapToVolumeResponse(volumes, int(limit)), nil
+}
+
+// GetShards implements querier.Store
+//
+// GetShards returns bounded shards over the fingerprints of streams, so that
+// each shard holds roughly targetBytesPerShard uncompressed bytes.
+func (s *Store) GetShards(ctx context.Context, _ string, from, through model.Time, targetBytesPerShard uint64, predicate chunk.Predicate) (*logproto.ShardsResponse, error) {
+	logger := util_log.WithContext(ctx, s.logger)
+	start, end := from.Time(), through.Time()
+
+	objects, err := s.objectsForTimeRange(ctx, start, end, predicate.Matchers, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		resp = &logproto.ShardsResponse{}
+		fps  = make(map[model.Fingerprint]*sharding.SizedFP)
+	)
+	err = forEachMatchingStream(ctx, objects, start, end, predicate.Matchers, func(stream streams.Stream) {
+		fp := model.Fingerprint(labels.StableHash(stream.Labels))
+		x, ok := fps[fp]
+		if !ok {
+			x = &sharding.SizedFP{Fp: fp}
+			fps[fp] = x
+		}
+
+		entries, bytes := streamStatsInRange(stream, start, end)
+		x.Stats.Chunks++
+		x.Stats.Entries += entries
+		x.Stats.Bytes += bytes
+		resp.Statistics.Index.TotalChunks++
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	series := sharding.SizedFPs(sharding.SizedFPsPool.Get(len(fps)))
+	defer sharding.SizedFPsPool.Put(series)
+
+	for _, x := range fps {
+		series = append(series, *x)
+	}
+	sort.Sort(series)
+	resp.Shards = series.ShardsFor(targetBytesPerShard)
+
+	return resp, nil
+}
+
+// forEachMatchingStream calls f for every stream in the streams sections of
+// objects which matches matchers and overlaps [start, end].
+//
+// Unlike [streamProcessor], streams are not deduplicated: f is called once
+// for every object which contains a stream. Calls to f are serialized.
+func forEachMatchingStream(ctx context.Context, objects []object, start, end time.Time, matchers []*labels.Matcher, f func(streams.Stream)) error {
+	if len(objects) == 0 {
+		return nil
+	}
+
+	readers, err := shardStreamReaders(ctx, objects, noShard)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, reader := range readers {
+			_ = reader.Close()
+			streamReaderPool.Put(reader)
+		}
+	}()
+
+	predicate := streamPredicate(withoutMatchAll(matchers), start, end)
+	for _, reader := range readers {
+		if err := reader.SetPredicate(predicate); err != nil {
+			return err
+		}
+	}
+
+	var mtx sync.Mutex
+	g, ctx := errgroup.WithContext(ctx)
+	for _, reader := range readers {
+		g.Go(func() error {
+			streamsPtr := streamsPool.Get().(*[]streams.Stream)
+			defer streamsPool.Put(streamsPtr)
+			buf := *streamsPtr
+
+			for {
+				n, err := reader.Read(ctx, buf)
+				if err != nil && err != io.EOF {
+					return fmt.Errorf("failed to read streams: %w", err)
+				}
+				if n == 0 && err == io.EOF {
+					return nil
+				}
+
+				mtx.Lock()
+				for _, stream := range buf[:n] {
+					f(stream)
+				}
+				mtx.Unlock()
+			}
+		})
+	}
+	return g.Wait()
+}
+
+// withoutMatchAll returns matchers without the empty matchers used to select
+// all streams, such as the ones of the volume query "{}".
+func withoutMatchAll(matchers []*labels.Matcher) []*labels.Matcher {
+	res := make([]*labels.Matcher, 0, len(matchers))
+	for _, m := range matchers {
+		if m.Name == "" {
+			continue
+		}
+		res = append(res, m)
+	}
+	return res
+}
+
+// streamStatsInRange returns the number of entries and uncompressed bytes of
+// stream within [start, end]. Entries are assumed to be evenly distributed
+// over the time range of the stream, like chunks in TSDB index stats.
+func streamStatsInRange(stream streams.Stream, start, end time.Time) (entries, bytes uint64) {
+	factor := util.GetFactorOfTime(
+		start.UnixNano(), end.UnixNano(),
+		stream.MinTimestamp.UnixNano(), stream.MaxTimestamp.UnixNano(),
+	)
+	return uint64(float64(stream.Rows) * factor), uint64(float64(stream.UncompressedSize) * factor)
+}
@@ -0,0 +1,242 @@
+package querier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/storage/chunk"
+	"github.com/grafana/loki/v3/pkg/storage/stores/index/stats"
+)
+
+func TestStore_Stats(t *testing.T) {
+	const testTenant = "test-tenant"
+	builder := newTestDataBuilder(t, testTenant)
+	defer builder.close()
+
+	// Setup test data
+	now := setupTestData(t, builder)
+	meta := metastore.NewObjectMetastore(metastore.Config{}, builder.bucket, log.NewNopLogger(), nil)
+	store := NewStore(builder.bucket, log.NewNopLogger(), meta)
+	ctx := user.InjectOrgID(context.Background(), testTenant)
+
+	tests := []struct {
+		name     string
+		matchers string
+		start    time.Time
+		end      time.Time
+		want     stats.Stats
+	}{
+		{
+			name:     "all streams in range",
+			matchers: `{app=~".+"}`,
+			start:    now,
+			end:      now.Add(time.Hour),
+			want:     stats.Stats{Streams: 5, Chunks: 5, Entries: 18, Bytes: 72},
+		},
+		{
+			name:     "stream in multiple objects",
+			matchers: `{app="foo", env="prod"}`,
+			start:    now.Add(-3 * time.Hour),
+			end:      now.Add(3 * time.Hour),
+			want:     stats.Stats{Streams: 1, Chunks: 3, Entries: 10, Bytes: 79},
+		},
+		{
+			name:     "stream partially in range",
+			matchers: `{app="baz"}`,
+			start:    now.Add(20 * time.Second),
+			end:      now.Add(40 * time.Second),
+			// 20s of the 30s of the stream are in range.
+			want: stats.Stats{Streams: 1, Chunks: 1, Entries: 2, Bytes: 10},
+		},
+		{
+			name:     "no matching streams",
+			matchers: `{app="qux"}`,
+			start:    now,
+			end:      now.Add(time.Hour),
+			want:     stats.Stats{},
+		},
+		{
+			name:     "streams without the label",
+			matchers: `{app=~".+", team=""}`,
+			start:    now,
+			end:      now.Add(time.Hour),
+			want:     stats.Stats{Streams: 4, Chunks: 4, Entries: 14, Bytes: 56},
+		},
+		{
+			name:     "streams without the label with not equal",
+			matchers: `{app=~".+", team!="a"}`,
+			start:    now,
+			end:      now.Add(time.Hour),
+			want:     stats.Stats{Streams: 4, Chunks: 4, Entries: 14, Bytes: 56},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchers, err := syntax.ParseMatchers(tt.matchers, true)
+			require.NoError(t, err)
+
+			res, err := store.Stats(ctx, testTenant, model.TimeFromUnixNano(tt.start.UnixNano()), model.TimeFromUnixNano(tt.end.UnixNano()), matchers...)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, *res)
+		})
+	}
+}
+
+func TestStore_Volume(t *testing.T) {
+	const testTenant = "test-tenant"
+	builder := newTestDataBuilder(t, testTenant)
+	defer builder.close()
+
+	// Setup test data
+	now := setupTestData(t, builder)
+	meta := metastore.NewObjectMetastore(metastore.Config{}, builder.bucket, log.NewNopLogger(), nil)
+	store := NewStore(builder.bucket, log.NewNopLogger(), meta)
+	ctx := user.InjectOrgID(context.Background(), testTenant)
+
+	tests := []struct {
+		name         string
+		matchers     string
+		targetLabels []string
+		aggregateBy  string
+		limit        int32
+		want         []logproto.Volume
+	}{
+		{
+			name:        "aggregate by series",
+			matchers:    `{app=~".+"}`,
+			aggregateBy: "series",
+			limit:       100,
+			want: []logproto.Volume{
+				{Name: `{app="bar"}`, Volume: 28},
+				{Name: `{app="foo"}`, Volume: 28},
+				{Name: `{app="baz"}`, Volume: 16},
+			},
+		},
+		{
+			name:         "aggregate by series with target labels",
+			matchers:     `{app="foo"}`,
+			targetLabels: []string{"env"},
+			aggregateBy:  "series",
+			limit:        100,
+			want: []logproto.Volume{
+				{Name: `{env="prod"}`, Volume: 16},
+				{Name: `{env="dev"}`, Volume: 12},
+			},
+		},
+		{
+			name:        "aggregate by labels",
+			matchers:    `{app=~".+"}`,
+			aggregateBy: "labels",
+			limit:       100,
+			want: []logproto.Volume{
+				{Name: "app", Volume: 72},
+				{Name: "env", Volume: 72},
+				{Name: "team", Volume: 16},
+			},
+		},
+		{
+			name:        "all streams",
+			aggregateBy: "labels",
+			limit:       100,
+			want: []logproto.Volume{
+				{Name: "app", Volume: 72},
+				{Name: "env", Volume: 72},
+				{Name: "team", Volume: 16},
+			},
+		},
+		{
+			name:        "limit",
+			matchers:    `{app=~".+"}`,
+			aggregateBy: "series",
+			limit:       1,
+			want: []logproto.Volume{
+				{Name: `{app="bar"}`, Volume: 28},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Without a selector, all streams are selected like in the
+			// volume query {}.
+			matchers := []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "", "")}
+			if tt.matchers != "" {
+				var err error
+				matchers, err = syntax.ParseMatchers(tt.matchers, true)
+				require.NoError(t, err)
+			}
+
+			res, err := store.Volume(ctx, testTenant, model.TimeFromUnixNano(now.UnixNano()), model.TimeFromUnixNano(now.Add(time.Hour).UnixNano()), tt.limit, tt.targetLabels, tt.aggregateBy, matchers...)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, res.Volumes)
+		})
+	}
+}
+
+func TestStore_GetShards(t *testing.T) {
+	const testTenant = "test-tenant"
+	builder := newTestDataBuilder(t, testTenant)
+	defer builder.close()
+
+	// Setup test data
+	now := setupTestData(t, builder)
+	meta := metastore.NewObjectMetastore(metastore.Config{}, builder.bucket, log.NewNopLogger(), nil)
+	store := NewStore(builder.bucket, log.NewNopLogger(), meta)
+	ctx := user.InjectOrgID(context.Background(), testTenant)
+
+	matchers, err := syntax.ParseMatchers(`{app=~".+"}`, true)
+	require.NoError(t, err)
+
+	from, through := model.TimeFromUnixNano(now.UnixNano()), model.TimeFromUnixNano(now.Add(time.Hour).UnixNano())
+	res, err := store.GetShards(ctx, testTenant, from, through, 30, chunk.NewPredicate(matchers, nil))
+	require.NoError(t, err)
+	require.Greater(t, len(res.Shards), 1)
+	require.EqualValues(t, 5, res.Statistics.Index.TotalChunks)
+
+	var totalBytes, totalStreams uint64
+	for _, shard := range res.Shards {
+		totalBytes += shard.Stats.Bytes
+		totalStreams += shard.Stats.Streams
+	}
+	require.EqualValues(t, 72, totalBytes)
+	require.EqualValues(t, 5, totalStreams)
+
+	// Each series must be returned by exactly one of the shards.
+	var allSeries []string
+	for _, shard := range res.Shards {
+		series, err := store.SelectSeries(ctx, logql.SelectLogParams{
+			QueryRequest: &logproto.QueryRequest{
+				Start:    now,
+				End:      now.Add(time.Hour),
+				Plan:     planFromString(`{app=~".+"}`),
+				Selector: `{app=~".+"}`,
+				Shards:   logql.Shards{logql.NewBoundedShard(shard)}.Encode(),
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, series, int(shard.Stats.Streams))
+
+		for _, s := range series {
+			allSeries = append(allSeries, labelsFromSeriesID(s))
+		}
+	}
+	require.ElementsMatch(t, []string{
+		`{app="foo", env="prod"}`,
+		`{app="foo", env="dev"}`,
+		`{app="bar", env="prod"}`,
+		`{app="bar", env="dev"}`,
+		`{app="baz", env="prod", team="a"}`,
+	}, allSeries)
+}
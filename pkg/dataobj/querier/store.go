@@ -25,14 +25,11 @@ import (
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/logs"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
 	"github.com/grafana/loki/v3/pkg/iter"
-	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/querier"
-	"github.com/grafana/loki/v3/pkg/storage/chunk"
 	"github.com/grafana/loki/v3/pkg/storage/config"
 	storageconfig "github.com/grafana/loki/v3/pkg/storage/config"
-	"github.com/grafana/loki/v3/pkg/storage/stores/index/stats"
 	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper/tsdb/index"
 	"github.com/grafana/loki/v3/pkg/tracing"
 	util_log "github.com/grafana/loki/v3/pkg/util/log"
@@ -122,7 +119,11 @@ func (s *Store) String() string {
 func (s *Store) SelectLogs(ctx context.Context, req logql.SelectLogParams) (iter.EntryIterator, error) {
 	logger := util_log.WithContext(ctx, s.logger)
 
-	objects, err := s.objectsForTimeRange(ctx, req.Start, req.End, logger)
+	selector, err := req.LogSelector()
+	if err != nil {
+		return nil, err
+	}
+	objects, err := s.objectsForTimeRange(ctx, req.Start, req.End, selector.Matchers(), logger)
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +143,15 @@ func (s *Store) SelectLogs(ctx context.Context, req logql.SelectLogParams) (iter
 func (s *Store) SelectSamples(ctx context.Context, req logql.SelectSampleParams) (iter.SampleIterator, error) {
 	logger := util_log.WithContext(ctx, s.logger)
 
-	objects, err := s.objectsForTimeRange(ctx, req.Start, req.End, logger)
+	expr, err := req.Expr()
+	if err != nil {
+		return nil, err
+	}
+	selector, err := expr.Selector()
+	if err != nil {
+		return nil, err
+	}
+	objects, err := s.objectsForTimeRange(ctx, req.Start, req.End, selector.Matchers(), logger)
 	if err != nil {
 		return nil, err
 	}
@@ -154,39 +163,18 @@ func (s *Store) SelectSamples(ctx context.Context, req logql.SelectSampleParams)
 	if err != nil {
 		return nil, err
 	}
-	expr, err := req.Expr()
-	if err != nil {
-		return nil, err
-	}
 
 	return selectSamples(ctx, objects, shard, expr, req.Start, req.End, logger)
 }
 
-// Stats implements querier.Store
-func (s *Store) Stats(_ context.Context, _ string, _ model.Time, _ model.Time, _ ...*labels.Matcher) (*stats.Stats, error) {
-	// TODO: Implement
-	return &stats.Stats{}, nil
-}
-
-// Volume implements querier.Store
-func (s *Store) Volume(_ context.Context, _ string, _ model.Time, _ model.Time, _ int32, _ []string, _ string, _ ...*labels.Matcher) (*logproto.VolumeResponse, error) {
-	// TODO: Implement
-	return &logproto.VolumeResponse{}, nil
-}
-
-// GetShards implements querier.Store
-func (s *Store) GetShards(_ context.Context, _ string, _ model.Time, _ model.Time, _ uint64, _ chunk.Predicate) (*logproto.ShardsResponse, error) {
-	// TODO: Implement
-	return &logproto.ShardsResponse{}, nil
-}
-
 type object struct {
 	*dataobj.Object
 	path string
 }
 
-// objectsForTimeRange returns data objects for the given time range.
-func (s *Store) objectsForTimeRange(ctx context.Context, from, through time.Time, logger log.Logger) ([]object, error) {
+// objectsForTimeRange returns data objects for the given time range which
+// contain streams matching matchers.
+func (s *Store) objectsForTimeRange(ctx context.Context, from, through time.Time, matchers []*labels.Matcher, logger log.Logger) ([]object, error) {
 	ctx, span := tracer.Start(ctx, "objectsForTimeRange")
 	defer span.End()
 
@@ -195,7 +183,7 @@ func (s *Store) objectsForTimeRange(ctx context.Context, from, through time.Time
 		attribute.String("through", through.String()),
 	)
 
-	files, err := s.metastore.DataObjects(ctx, from, through)
+	files, err := s.metastore.DataObjects(ctx, from, through, withoutMatchAll(matchers)...)
 	if err != nil {
 		return nil, err
 	}
@@ -339,6 +327,7 @@ func selectSamples(ctx context.Context, objects []object, shard logql.Shard, exp
 
 type shardedObject struct {
 	object       object
+	shard        logql.Shard
 	streamReader *streams.RowReader
 	logReaders   []*logs.RowReader
 
@@ -418,6 +407,7 @@ func shardObjects(
 		reader := shardedObjectsPool.Get().(*shardedObject)
 		reader.streamReader = streamReaderPool.Get().(*streams.RowReader)
 		reader.object = objects[i]
+		reader.shard = shard
 
 		sec, err := findStreamsSection(ctx, objects[i].Object)
 		if err != nil {
@@ -498,6 +488,7 @@ func (s *shardedObject) reset() {
 	s.logReaders = s.logReaders[:0]
 	s.streamsIDs = s.streamsIDs[:0]
 	s.object = object{}
+	s.shard = logql.Shard{}
 	clear(s.streams)
 }
 
@@ -615,6 +606,9 @@ func (s *shardedObject) matchStreams(ctx context.Context) error {
 		}
 
 		for _, stream := range streams[:n] {
+			if !matchBoundedShard(s.shard, stream.Labels) {
+				continue
+			}
 			s.streams[stream.ID] = stream
 			s.streamsIDs = append(s.streamsIDs, stream.ID)
 		}
@@ -685,8 +679,8 @@ func matchersToPredicate(matchers []*labels.Matcher) streams.RowPredicate {
 	var left streams.RowPredicate
 	for _, matcher := range matchers {
 		var right streams.RowPredicate
-		switch matcher.Type {
-		case labels.MatchEqual:
+		switch {
+		case matcher.Type == labels.MatchEqual && matcher.Value != "":
 			right = streams.LabelMatcherRowPredicate{Name: matcher.Name, Value: matcher.Value}
 		default:
 			right = streams.LabelFilterRowPredicate{Name: matcher.Name, Keep: func(_, value string) bool {
@@ -716,12 +710,21 @@ func parseShards(shards []string) (logql.Shard, error) {
 	if len(parsed) == 0 {
 		return noShard, nil
 	}
-	if parsed[0].Variant() != logql.PowerOfTwoVersion {
-		return noShard, fmt.Errorf("unsupported shard variant: %s", parsed[0].Variant())
-	}
 	return parsed[0], nil
 }
 
+// matchBoundedShard returns false if shard is a bounded shard which doesn't
+// include the fingerprint of a stream with the given labels.
+//
+// Power of two shards are applied by reading a subset of sections instead
+// (see [shardSections]), so matchBoundedShard always returns true for them.
+func matchBoundedShard(shard logql.Shard, lbls labels.Labels) bool {
+	if shard.Bounded == nil {
+		return true
+	}
+	return shard.Match(model.Fingerprint(labels.StableHash(lbls)))
+}
+
 func buildLogsPredicateFromSampleExpr(expr syntax.SampleExpr) (logs.RowPredicate, syntax.SampleExpr) {
 	var (
 		predicate logs.RowPredicate
@@ -207,7 +207,9 @@ func (enc *columnEncoder) AppendPage(page *dataset.MemPage) error {
 			DataOffset: uint64(enc.startOffset + enc.totalPageSize),
 			DataSize:   uint64(len(page.Data)),
 
-			Statistics: page.Info.Stats,
+			Statistics:  page.Info.Stats,
+			BloomFilter: page.Info.BloomFilter,
+			Dictionary:  page.Info.Dictionary,
 		},
 	})
 
@@ -40,6 +40,14 @@ type BuilderOptions struct {
 	// increase time spent merging. Higher values of StripeMergeLimit increase
 	// memory overhead but reduce time spent merging.
 	StripeMergeLimit int
+
+	// DictionaryEncoding enables dictionary encoding for the values of
+	// structured metadata columns. Pages with many distinct values fall back to
+	// plain encoding.
+	//
+	// Readers that don't support dictionary encoding can't read sections
+	// written with it.
+	DictionaryEncoding bool
 }
 
 // Builder accumulate a set of [Record]s within a data object.
@@ -80,10 +88,16 @@ func NewBuilder(metrics *Metrics, opts BuilderOptions) *Builder {
 		metrics = NewMetrics()
 	}
 
-	return &Builder{
+	b := &Builder{
 		metrics: metrics,
 		opts:    opts,
 	}
+	if opts.DictionaryEncoding {
+		// Stripes are intermediate tables, so only the merged section is
+		// dictionary encoded.
+		b.sectionBuffer.metadataEncoding = datasetmd.ENCODING_TYPE_DICTIONARY
+	}
+	return b
 }
 
 // Type returns the [dataobj.SectionType] of the logs builder.
@@ -3,6 +3,7 @@ package logs_test
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -84,6 +85,86 @@ func Test(t *testing.T) {
 	}
 }
 
+func TestBuilder_DictionaryEncoding(t *testing.T) {
+	var records []logs.Record
+	for i := range 100 {
+		records = append(records, logs.Record{
+			StreamID:  1,
+			Timestamp: time.Unix(int64(i+1), 0),
+			Metadata: labels.New(
+				labels.Label{Name: "level", Value: []string{"info", "debug", "error"}[i%3]},
+				labels.Label{Name: "trace_id", Value: fmt.Sprintf("trace-%d", i)},
+			),
+			Line: []byte("hello world"),
+		})
+	}
+
+	tt := []struct {
+		name               string
+		dictionaryEncoding bool
+		wantEncodings      map[string]string
+	}{
+		{
+			name:               "disabled",
+			dictionaryEncoding: false,
+			wantEncodings:      map[string]string{"level": "ENCODING_TYPE_PLAIN", "trace_id": "ENCODING_TYPE_PLAIN"},
+		},
+		{
+			name:               "enabled",
+			dictionaryEncoding: true,
+			// Trace IDs are all distinct, so they fall back to plain encoding.
+			wantEncodings: map[string]string{"level": "ENCODING_TYPE_DICTIONARY", "trace_id": "ENCODING_TYPE_PLAIN"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			builder := logs.NewBuilder(nil, logs.BuilderOptions{
+				PageSizeHint:       8192,
+				BufferSize:         4192,
+				StripeMergeLimit:   2,
+				DictionaryEncoding: tc.dictionaryEncoding,
+			})
+			for _, record := range records {
+				builder.Append(record)
+			}
+
+			buf, err := buildObject(builder)
+			require.NoError(t, err)
+
+			obj, err := dataobj.FromReaderAt(bytes.NewReader(buf), int64(len(buf)))
+			require.NoError(t, err)
+			sec, err := logs.Open(t.Context(), obj.Sections()[0])
+			require.NoError(t, err)
+
+			stats, err := logs.ReadStats(t.Context(), sec)
+			require.NoError(t, err)
+
+			encodings := make(map[string]string)
+			for _, col := range stats.Columns {
+				if _, ok := tc.wantEncodings[col.Name]; !ok {
+					continue
+				}
+				for _, page := range col.Pages {
+					encodings[col.Name] = page.Encoding
+				}
+			}
+			require.Equal(t, tc.wantEncodings, encodings)
+
+			var count int
+			for result := range logs.Iter(t.Context(), obj) {
+				record, err := result.Value()
+				require.NoError(t, err)
+				i := record.Timestamp.Unix() - 1
+				require.Equal(t, []string{"info", "debug", "error"}[i%3], record.Metadata.Get("level"))
+				require.Equal(t, fmt.Sprintf("trace-%d", i), record.Metadata.Get("trace_id"))
+				count++
+			}
+			require.Equal(t, len(records), count)
+		})
+	}
+}
+
 func buildObject(lt *logs.Builder) ([]byte, error) {
 	var buf bytes.Buffer
 
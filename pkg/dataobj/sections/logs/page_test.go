@@ -0,0 +1,48 @@
+package logs_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/logs"
+)
+
+func TestDecodePage(t *testing.T) {
+	var (
+		recs     []logs.Record
+		expected []string
+	)
+	for i := range 100 {
+		recs = append(recs, logs.Record{
+			StreamID:  1,
+			Timestamp: unixTime(int64(i + 1)),
+			Line:      []byte(fmt.Sprintf("line %d", i)),
+		})
+		expected = append(expected, fmt.Sprintf("line %d", i))
+	}
+	sec := buildSection(t, recs)
+
+	stats, err := logs.ReadStats(t.Context(), sec)
+	require.NoError(t, err)
+
+	for i, col := range sec.Columns() {
+		if col.Type != logs.ColumnTypeMessage {
+			continue
+		}
+
+		var lines []string
+		for page := range stats.Columns[i].Pages {
+			rows, err := logs.DecodePage(t.Context(), sec, i, page)
+			require.NoError(t, err)
+			for _, row := range rows {
+				lines = append(lines, string(row.([]byte)))
+			}
+		}
+		require.ElementsMatch(t, expected, lines)
+	}
+
+	_, err = logs.DecodePage(t.Context(), sec, len(sec.Columns()), 0)
+	require.ErrorContains(t, err, "out of range")
+}
@@ -116,9 +116,10 @@ type tableBuffer struct {
 	streamID  *dataset.ColumnBuilder
 	timestamp *dataset.ColumnBuilder
 
-	metadatas      []*dataset.ColumnBuilder
-	metadataLookup map[string]int                    // map of metadata key to index in metadatas
-	usedMetadatas  map[*dataset.ColumnBuilder]string // metadata with its name.
+	metadatas        []*dataset.ColumnBuilder
+	metadataLookup   map[string]int                    // map of metadata key to index in metadatas
+	usedMetadatas    map[*dataset.ColumnBuilder]string // metadata with its name.
+	metadataEncoding datasetmd.EncodingType            // Encoding of metadata values; plain encoding if unset.
 
 	message *dataset.ColumnBuilder
 }
@@ -190,15 +191,25 @@ func (b *tableBuffer) Metadata(key string, pageSize int, compressionOpts dataset
 		return builder
 	}
 
+	encoding := b.metadataEncoding
+	if encoding == datasetmd.ENCODING_TYPE_UNSPECIFIED {
+		encoding = datasetmd.ENCODING_TYPE_PLAIN
+	}
+
 	col, err := dataset.NewColumnBuilder(key, dataset.BuilderOptions{
 		PageSizeHint:       pageSize,
 		Value:              datasetmd.VALUE_TYPE_BYTE_ARRAY,
-		Encoding:           datasetmd.ENCODING_TYPE_PLAIN,
+		Encoding:           encoding,
 		Compression:        datasetmd.COMPRESSION_TYPE_ZSTD,
 		CompressionOptions: compressionOpts,
 		Statistics: dataset.StatisticsOptions{
 			StoreRangeStats:       true,
 			StoreCardinalityStats: true,
+
+			// Range stats are rarely useful for high-cardinality metadata such as
+			// trace IDs, so we additionally store bloom filters to permit skipping
+			// pages when looking for specific values.
+			StoreBloomFilters: true,
 		},
 	})
 	if err != nil {
@@ -0,0 +1,26 @@
+package logs_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/sections/logs"
+)
+
+func TestVerify(t *testing.T) {
+	var recs []logs.Record
+	for i := range 1000 {
+		recs = append(recs, logs.Record{
+			StreamID:  int64(i%3 + 1),
+			Timestamp: unixTime(int64(i + 1)),
+			Metadata:  labels.FromStrings("trace_id", fmt.Sprintf("%d", i%7)),
+			Line:      []byte(fmt.Sprintf("line %d", i)),
+		})
+	}
+
+	sec := buildSection(t, recs)
+	require.NoError(t, logs.Verify(t.Context(), sec))
+}
@@ -0,0 +1,234 @@
+package ngrams
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/loki/v3/pkg/dataobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/dataset"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/metadata/datasetmd"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/metadata/ngramsmd"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/util/sliceclear"
+)
+
+// StreamNgrams holds the n-gram bloom filter of the log lines of a single
+// stream in a logs section of a data object.
+//
+// The path is the data object path, the section is the index of the logs
+// section in the data object and the stream ID is the ID of the stream in the
+// data object.
+type StreamNgrams struct {
+	Path             string
+	Section          int64
+	StreamID         int64
+	NgramBloomFilter []byte
+}
+
+// Builder builds an ngrams section.
+type Builder struct {
+	metrics  *Metrics
+	pageSize int
+
+	streams []*StreamNgrams
+}
+
+// NewBuilder creates a new ngrams section builder. If metrics is nil, a new
+// set of metrics is created.
+func NewBuilder(metrics *Metrics, pageSize int) *Builder {
+	if metrics == nil {
+		metrics = NewMetrics()
+	}
+	return &Builder{
+		metrics:  metrics,
+		pageSize: pageSize,
+		streams:  make([]*StreamNgrams, 0, 1024),
+	}
+}
+
+func (b *Builder) Type() dataobj.SectionType { return sectionType }
+
+// Append adds the n-gram bloom filter of a stream in a logs section to the
+// builder. The bloom filter is typically built with [NgramSet.BloomFilter].
+func (b *Builder) Append(path string, section int64, streamID int64, ngramBloomFilter []byte) {
+	b.metrics.recordsTotal.Inc()
+	b.streams = append(b.streams, &StreamNgrams{
+		Path:             path,
+		Section:          section,
+		StreamID:         streamID,
+		NgramBloomFilter: ngramBloomFilter,
+	})
+}
+
+// EstimatedSize returns the estimated size of the ngrams section in bytes.
+func (b *Builder) EstimatedSize() int {
+	// Since columns are only built when encoding, we can't use
+	// [dataset.ColumnBuilder.EstimatedSize] here.
+	//
+	// Bloom filters are stored uncompressed and make up most of the section,
+	// so we count them in full. Paths repeat for every stream of an object and
+	// compress well; section indexes and stream IDs are small deltas.
+
+	if len(b.streams) == 0 {
+		return 0
+	}
+
+	var (
+		avgPathLength        = 85  // Average path length in bytes (based on real data)
+		pathCompressionRatio = 10  // ZSTD compression ratio of repeated paths
+		deltaSize            = 1   // Section and stream ID delta
+		metadataOverhead     = 100 // Estimated metadata overhead per column
+	)
+
+	var sizeEstimate int
+	for _, stream := range b.streams {
+		sizeEstimate += len(stream.NgramBloomFilter)
+	}
+	sizeEstimate += (len(b.streams) * avgPathLength) / pathCompressionRatio
+	sizeEstimate += len(b.streams) * 2 * deltaSize
+	sizeEstimate += 4 * metadataOverhead
+
+	return sizeEstimate
+}
+
+// Flush flushes the ngrams section to the provided writer.
+//
+// After successful encoding, b is reset to a fresh state and can be reused.
+func (b *Builder) Flush(w dataobj.SectionWriter) (n int64, err error) {
+	timer := prometheus.NewTimer(b.metrics.encodeSeconds)
+	defer timer.ObserveDuration()
+
+	b.sortStreams()
+
+	var enc encoder
+	defer enc.Reset()
+	if err := b.encodeTo(&enc); err != nil {
+		return 0, fmt.Errorf("building encoder: %w", err)
+	}
+
+	n, err = enc.Flush(w)
+	if err == nil {
+		b.Reset()
+	}
+	return n, err
+}
+
+// sortStreams sorts the streams by path, section and stream ID, so rows of
+// the same logs section are stored next to each other.
+func (b *Builder) sortStreams() {
+	slices.SortFunc(b.streams, func(a, b *StreamNgrams) int {
+		if res := cmp.Compare(a.Path, b.Path); res != 0 {
+			return res
+		}
+		if res := cmp.Compare(a.Section, b.Section); res != 0 {
+			return res
+		}
+		return cmp.Compare(a.StreamID, b.StreamID)
+	})
+}
+
+// Reset resets all state, allowing the Builder to be reused.
+func (b *Builder) Reset() {
+	b.streams = sliceclear.Clear(b.streams)
+}
+
+func (b *Builder) encodeTo(enc *encoder) error {
+	pathBuilder, err := dataset.NewColumnBuilder("path", dataset.BuilderOptions{
+		PageSizeHint: b.pageSize,
+		Value:        datasetmd.VALUE_TYPE_BYTE_ARRAY,
+		Encoding:     datasetmd.ENCODING_TYPE_PLAIN,
+		Compression:  datasetmd.COMPRESSION_TYPE_ZSTD,
+		Statistics: dataset.StatisticsOptions{
+			StoreRangeStats: true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("creating path column: %w", err)
+	}
+
+	sectionBuilder, err := numberColumnBuilder("section", b.pageSize)
+	if err != nil {
+		return fmt.Errorf("creating section column: %w", err)
+	}
+
+	streamIDBuilder, err := numberColumnBuilder("stream_id", b.pageSize)
+	if err != nil {
+		return fmt.Errorf("creating stream ID column: %w", err)
+	}
+
+	bloomFilterBuilder, err := dataset.NewColumnBuilder("ngram_bloom_filter", dataset.BuilderOptions{
+		PageSizeHint: b.pageSize,
+		Value:        datasetmd.VALUE_TYPE_BYTE_ARRAY,
+		Encoding:     datasetmd.ENCODING_TYPE_PLAIN,
+		Compression:  datasetmd.COMPRESSION_TYPE_NONE, // Bloom filters don't compress well.
+	})
+	if err != nil {
+		return fmt.Errorf("creating n-gram bloom filter column: %w", err)
+	}
+
+	for i, stream := range b.streams {
+		// Append only fails if the rows are out-of-order, which can't happen here.
+		_ = pathBuilder.Append(i, dataset.ByteArrayValue([]byte(stream.Path)))
+		_ = sectionBuilder.Append(i, dataset.Int64Value(stream.Section))
+		_ = streamIDBuilder.Append(i, dataset.Int64Value(stream.StreamID))
+		_ = bloomFilterBuilder.Append(i, dataset.ByteArrayValue(stream.NgramBloomFilter))
+	}
+
+	// Encode our builders to sections. We ignore errors after enc.OpenStreams
+	// (which may fail due to a caller) since we guarantee correct usage of the
+	// encoding API.
+	{
+		var errs []error
+		errs = append(errs, encodeColumn(enc, ngramsmd.COLUMN_TYPE_PATH, pathBuilder))
+		errs = append(errs, encodeColumn(enc, ngramsmd.COLUMN_TYPE_SECTION, sectionBuilder))
+		errs = append(errs, encodeColumn(enc, ngramsmd.COLUMN_TYPE_STREAM_ID, streamIDBuilder))
+		errs = append(errs, encodeColumn(enc, ngramsmd.COLUMN_TYPE_NGRAM_BLOOM_FILTER, bloomFilterBuilder))
+
+		if err := errors.Join(errs...); err != nil {
+			return fmt.Errorf("encoding columns: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func numberColumnBuilder(name string, pageSize int) (*dataset.ColumnBuilder, error) {
+	return dataset.NewColumnBuilder(name, dataset.BuilderOptions{
+		PageSizeHint: pageSize,
+		Value:        datasetmd.VALUE_TYPE_INT64,
+		Encoding:     datasetmd.ENCODING_TYPE_DELTA,
+		Compression:  datasetmd.COMPRESSION_TYPE_NONE,
+		Statistics: dataset.StatisticsOptions{
+			StoreRangeStats: true,
+		},
+	})
+}
+
+func encodeColumn(enc *encoder, columnType ngramsmd.ColumnType, builder *dataset.ColumnBuilder) error {
+	column, err := builder.Flush()
+	if err != nil {
+		return fmt.Errorf("flushing %s column: %w", columnType, err)
+	}
+
+	columnEnc, err := enc.OpenColumn(columnType, &column.Info)
+	if err != nil {
+		return fmt.Errorf("opening %s column encoder: %w", columnType, err)
+	}
+	defer func() {
+		// Discard on defer for safety. This will return an error if we
+		// successfully committed.
+		_ = columnEnc.Discard()
+	}()
+
+	for _, page := range column.Pages {
+		err := columnEnc.AppendPage(page)
+		if err != nil {
+			return fmt.Errorf("appending %s page: %w", columnType, err)
+		}
+	}
+
+	return columnEnc.Commit()
+}
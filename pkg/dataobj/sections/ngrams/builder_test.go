@@ -0,0 +1,104 @@
+package ngrams
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/dataobj"
+)
+
+func TestBuilder(t *testing.T) {
+	bloomFilter := func(lines ...string) []byte {
+		var set NgramSet
+		for _, line := range lines {
+			set.AddLine([]byte(line))
+		}
+		bf, err := set.BloomFilter()
+		require.NoError(t, err)
+		return bf
+	}
+
+	input := []StreamNgrams{
+		{Path: "obj-b", Section: 1, StreamID: 2, NgramBloomFilter: bloomFilter("level=warn msg=timeout")},
+		{Path: "obj-a", Section: 1, StreamID: 1, NgramBloomFilter: bloomFilter("GET /api/v1/push 200")},
+		{Path: "obj-a", Section: 0, StreamID: 3, NgramBloomFilter: bloomFilter("level=error msg=failed", "level=info")},
+		{Path: "obj-a", Section: 0, StreamID: 1, NgramBloomFilter: bloomFilter("")},
+	}
+
+	sec := buildSection(t, input, 64) // Many pages
+
+	// Rows are sorted by path, section and stream ID.
+	expect := []StreamNgrams{input[3], input[2], input[1], input[0]}
+
+	t.Run("RowReader", func(t *testing.T) {
+		r := NewRowReader(sec)
+		defer r.Close()
+
+		actual, err := readAll(t.Context(), r)
+		require.NoError(t, err)
+		require.Equal(t, expect, actual)
+	})
+
+	t.Run("IterSection", func(t *testing.T) {
+		var actual []StreamNgrams
+		for result := range IterSection(t.Context(), sec) {
+			stream, err := result.Value()
+			require.NoError(t, err)
+			actual = append(actual, stream)
+		}
+		require.Equal(t, expect, actual)
+	})
+
+	t.Run("Verify", func(t *testing.T) {
+		require.NoError(t, Verify(t.Context(), sec))
+	})
+}
+
+func buildSection(t *testing.T, streams []StreamNgrams, pageSize int) *Section {
+	t.Helper()
+
+	b := NewBuilder(nil, pageSize)
+	for _, s := range streams {
+		b.Append(s.Path, s.Section, s.StreamID, s.NgramBloomFilter)
+	}
+
+	var buf bytes.Buffer
+	builder := dataobj.NewBuilder()
+	require.NoError(t, builder.Append(b))
+
+	_, err := builder.Flush(&buf)
+	require.NoError(t, err)
+
+	obj, err := dataobj.FromReaderAt(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	sec, err := Open(t.Context(), obj.Sections()[0])
+	require.NoError(t, err)
+	return sec
+}
+
+func readAll(ctx context.Context, r *RowReader) ([]StreamNgrams, error) {
+	var (
+		res []StreamNgrams
+		buf = make([]StreamNgrams, 128)
+	)
+
+	for {
+		n, err := r.Read(ctx, buf)
+		if n > 0 {
+			res = append(res, buf[:n]...)
+		}
+		if errors.Is(err, io.EOF) {
+			return res, nil
+		} else if err != nil {
+			return res, err
+		}
+
+		clear(buf)
+	}
+}
@@ -0,0 +1,34 @@
+package ngrams
+
+import (
+	"fmt"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/metadata/ngramsmd"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/streamio"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/util/protocodec"
+)
+
+// decodeNgramsMetadata decodes ngrams section metadata from r.
+func decodeNgramsMetadata(r streamio.Reader) (*ngramsmd.Metadata, error) {
+	gotVersion, err := streamio.ReadUvarint(r)
+	if err != nil {
+		return nil, fmt.Errorf("read ngrams section format version: %w", err)
+	} else if gotVersion != ngramsFormatVersion {
+		return nil, fmt.Errorf("unexpected ngrams section format version: got=%d want=%d", gotVersion, ngramsFormatVersion)
+	}
+
+	var md ngramsmd.Metadata
+	if err := protocodec.Decode(r, &md); err != nil {
+		return nil, fmt.Errorf("ngrams section metadata: %w", err)
+	}
+	return &md, nil
+}
+
+// decodeNgramsColumnMetadata decodes ngrams column metadata from r.
+func decodeNgramsColumnMetadata(r streamio.Reader) (*ngramsmd.ColumnMetadata, error) {
+	var metadata ngramsmd.ColumnMetadata
+	if err := protocodec.Decode(r, &metadata); err != nil {
+		return nil, fmt.Errorf("ngrams column metadata: %w", err)
+	}
+	return &metadata, nil
+}
@@ -0,0 +1,160 @@
+package ngrams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"unsafe"
+
+	"github.com/grafana/loki/v3/pkg/dataobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/dataset"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/metadata/datasetmd"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/metadata/ngramsmd"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/result"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/util/symbolizer"
+)
+
+// Iter iterates over the rows of all ngrams sections in the provided data
+// object. All ngrams sections are iterated over in order.
+func Iter(ctx context.Context, obj *dataobj.Object) result.Seq[StreamNgrams] {
+	return result.Iter(func(yield func(StreamNgrams) bool) error {
+		for i, section := range obj.Sections().Filter(CheckSection) {
+			ngramsSection, err := Open(ctx, section)
+			if err != nil {
+				return fmt.Errorf("opening section %d: %w", i, err)
+			}
+
+			for result := range IterSection(ctx, ngramsSection) {
+				if result.Err() != nil || !yield(result.MustValue()) {
+					return result.Err()
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// IterSection iterates over the rows of the provided ngrams section.
+func IterSection(ctx context.Context, section *Section) result.Seq[StreamNgrams] {
+	return result.Iter(func(yield func(StreamNgrams) bool) error {
+		dec := newDecoder(section.reader)
+
+		// We need to pull the columns twice: once from the dataset implementation
+		// and once for the metadata to retrieve column type.
+		//
+		// TODO(rfratto): find a way to expose this information from
+		// encoding.StreamsDataset to avoid the double call.
+		metadata, err := dec.Metadata(ctx)
+		if err != nil {
+			return err
+		}
+
+		dset, err := newColumnsDataset(section.Columns())
+		if err != nil {
+			return fmt.Errorf("creating section dataset: %w", err)
+		}
+
+		columns, err := result.Collect(dset.ListColumns(ctx))
+		if err != nil {
+			return err
+		}
+
+		r := dataset.NewReader(dataset.ReaderOptions{
+			Dataset: dset,
+			Columns: columns,
+		})
+		defer r.Close()
+
+		sym := symbolizer.New(128, 1024)
+
+		var rows [1]dataset.Row
+		for {
+			n, err := r.Read(ctx, rows[:])
+			if err != nil && !errors.Is(err, io.EOF) {
+				return err
+			} else if n == 0 && errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			var stream StreamNgrams
+			for _, row := range rows[:n] {
+				if err := decodeRow(metadata.GetColumns(), row, &stream, sym); err != nil {
+					return err
+				}
+
+				if !yield(stream) {
+					return nil
+				}
+			}
+		}
+	})
+}
+
+// decodeRow decodes a [StreamNgrams] from a [dataset.Row], using the provided
+// columns to determine the column type. The list of columns must match the
+// columns used to create the row.
+//
+// The sym argument is used for reusing path strings between calls to
+// decodeRow. If sym is nil, path strings are always allocated.
+func decodeRow(columns []*ngramsmd.ColumnDesc, row dataset.Row, stream *StreamNgrams, sym *symbolizer.Symbolizer) error {
+	for columnIndex, columnValue := range row.Values {
+		column := columns[columnIndex]
+		switch column.Type {
+		case ngramsmd.COLUMN_TYPE_PATH:
+			if ty := columnValue.Type(); ty != datasetmd.VALUE_TYPE_BYTE_ARRAY {
+				return fmt.Errorf("invalid type %s for %s", ty, column.Type)
+			}
+
+			if columnValue.IsNil() || columnValue.IsZero() {
+				return fmt.Errorf("nil or zero value for %s", column.Type)
+			}
+
+			if sym != nil {
+				stream.Path = sym.Get(unsafeString(columnValue.ByteArray()))
+			} else {
+				stream.Path = string(columnValue.ByteArray())
+			}
+
+		case ngramsmd.COLUMN_TYPE_SECTION:
+			if columnValue.IsNil() {
+				stream.Section = 0
+				continue
+			} else if ty := columnValue.Type(); ty != datasetmd.VALUE_TYPE_INT64 {
+				return fmt.Errorf("invalid type %s for %s", ty, column.Type)
+			}
+			stream.Section = columnValue.Int64()
+
+		case ngramsmd.COLUMN_TYPE_STREAM_ID:
+			if columnValue.IsNil() {
+				stream.StreamID = 0
+				continue
+			} else if ty := columnValue.Type(); ty != datasetmd.VALUE_TYPE_INT64 {
+				return fmt.Errorf("invalid type %s for %s", ty, column.Type)
+			}
+			stream.StreamID = columnValue.Int64()
+
+		case ngramsmd.COLUMN_TYPE_NGRAM_BLOOM_FILTER:
+			if columnValue.IsNil() {
+				stream.NgramBloomFilter = nil
+				continue
+			} else if ty := columnValue.Type(); ty != datasetmd.VALUE_TYPE_BYTE_ARRAY {
+				return fmt.Errorf("invalid type %s for %s", ty, column.Type)
+			}
+			stream.NgramBloomFilter = slices.Clone(columnValue.ByteArray())
+
+		default:
+			// TODO(rfratto): We probably don't want to return an error on unexpected
+			// columns because it breaks forward compatibility. Should we log
+			// something here?
+		}
+	}
+
+	return nil
+}
+
+func unsafeString(data []byte) string {
+	return unsafe.String(unsafe.SliceData(data), len(data))
+}
@@ -0,0 +1,88 @@
+package ngrams
+
+import (
+	"fmt"
+
+	"github.com/bits-and-blooms/bloom/v3"
+)
+
+// NgramSize is the length in bytes of the n-grams stored in the bloom filters
+// of the ngrams section. Strings shorter than NgramSize can't be looked up.
+const NgramSize = 3
+
+// ngramFalsePositiveRate is the target false positive rate of the n-gram
+// bloom filters. A lookup of a string tests all of its n-grams, so the false
+// positive rate of a lookup is usually much lower.
+const ngramFalsePositiveRate = 0.01
+
+// An NgramSet accumulates the distinct n-grams of a set of log lines, to build
+// a bloom filter from. The zero value of NgramSet is ready for use.
+type NgramSet struct {
+	ngrams map[uint32]struct{}
+}
+
+// AddLine adds all n-grams of line to s.
+func (s *NgramSet) AddLine(line []byte) {
+	if s.ngrams == nil {
+		s.ngrams = make(map[uint32]struct{})
+	}
+	for i := 0; i+NgramSize <= len(line); i++ {
+		s.ngrams[packNgram(line[i:i+NgramSize])] = struct{}{}
+	}
+}
+
+// Len returns the number of distinct n-grams in s.
+func (s *NgramSet) Len() int { return len(s.ngrams) }
+
+// BloomFilter returns the encoded bloom filter of the n-grams in s, to be
+// passed to [Builder.Append].
+func (s *NgramSet) BloomFilter() ([]byte, error) {
+	bf := bloom.NewWithEstimates(uint(max(len(s.ngrams), 1)), ngramFalsePositiveRate)
+
+	var buf [NgramSize]byte
+	for ngram := range s.ngrams {
+		bf.Add(unpackNgram(ngram, buf[:]))
+	}
+	return bf.MarshalBinary()
+}
+
+// Reset removes all n-grams from s.
+func (s *NgramSet) Reset() { clear(s.ngrams) }
+
+func packNgram(ngram []byte) uint32 {
+	return uint32(ngram[0])<<16 | uint32(ngram[1])<<8 | uint32(ngram[2])
+}
+
+func unpackNgram(ngram uint32, buf []byte) []byte {
+	buf[0], buf[1], buf[2] = byte(ngram>>16), byte(ngram>>8), byte(ngram)
+	return buf[:NgramSize]
+}
+
+// An NgramFilter is a decoded n-gram bloom filter, which tests whether any of
+// the log lines it was built from may contain a string.
+type NgramFilter struct {
+	bf bloom.BloomFilter
+}
+
+// NewNgramFilter decodes an n-gram bloom filter as returned by
+// [NgramSet.BloomFilter].
+func NewNgramFilter(data []byte) (*NgramFilter, error) {
+	var f NgramFilter
+	if err := f.bf.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("decoding n-gram bloom filter: %w", err)
+	}
+	return &f, nil
+}
+
+// MayContain returns false if none of the log lines f was built from contains
+// s. MayContain always returns true for strings shorter than [NgramSize].
+func (f *NgramFilter) MayContain(s string) bool {
+	var buf [NgramSize]byte
+	for i := 0; i+NgramSize <= len(s); i++ {
+		copy(buf[:], s[i:i+NgramSize])
+		if !f.bf.Test(buf[:]) {
+			return false
+		}
+	}
+	return true
+}
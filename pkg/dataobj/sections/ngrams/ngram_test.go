@@ -0,0 +1,45 @@
+package ngrams
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNgramFilter(t *testing.T) {
+	var set NgramSet
+	set.AddLine([]byte("level=error msg=\"connection refused\""))
+	set.AddLine([]byte("level=info msg=done"))
+	set.AddLine([]byte("ab")) // Too short to contain any n-gram.
+
+	data, err := set.BloomFilter()
+	require.NoError(t, err)
+
+	f, err := NewNgramFilter(data)
+	require.NoError(t, err)
+
+	tt := []struct {
+		needle string
+		expect bool
+	}{
+		{needle: "connection refused", expect: true},
+		{needle: "level=info", expect: true},
+		{needle: "msg=done", expect: true},
+		{needle: "ab", expect: true}, // Too short to look up.
+		{needle: "zz", expect: true}, // Too short to look up.
+		{needle: "", expect: true},   // Too short to look up.
+		{needle: "timeout", expect: false},
+		{needle: "LEVEL=ERROR", expect: false}, // Lookups are case sensitive.
+	}
+	for _, tc := range tt {
+		t.Run(tc.needle, func(t *testing.T) {
+			require.Equal(t, tc.expect, f.MayContain(tc.needle))
+		})
+	}
+
+	t.Run("Reset", func(t *testing.T) {
+		require.Positive(t, set.Len())
+		set.Reset()
+		require.Zero(t, set.Len())
+	})
+}
@@ -0,0 +1,131 @@
+// Package ngrams defines types used for the data object ngrams section. The
+// ngrams section holds a bloom filter of the n-grams of the log lines of each
+// stream in a logs section, which allows skipping sections that can't match a
+// line filter.
+package ngrams
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/loki/v3/pkg/dataobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/metadata/ngramsmd"
+)
+
+var sectionType = dataobj.SectionType{
+	Namespace: "github.com/grafana/loki",
+	Kind:      "ngrams",
+}
+
+// CheckSection returns true if section is an ngrams section.
+func CheckSection(section *dataobj.Section) bool { return section.Type == sectionType }
+
+// Section represents an opened ngrams section.
+type Section struct {
+	reader  dataobj.SectionReader
+	columns []*Column
+}
+
+// Open opens a Section from an underlying [dataobj.Section]. Open returns an
+// error if the section metadata could not be read or if the provided ctx is
+// canceled.
+func Open(ctx context.Context, section *dataobj.Section) (*Section, error) {
+	if !CheckSection(section) {
+		return nil, fmt.Errorf("section is not an ngrams section")
+	}
+
+	sec := &Section{reader: section.Reader}
+	if err := sec.init(ctx); err != nil {
+		return nil, fmt.Errorf("intializing section: %w", err)
+	}
+	return sec, nil
+}
+
+func (s *Section) init(ctx context.Context) error {
+	dec := newDecoder(s.reader)
+	metadata, err := dec.Metadata(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to decode metadata: %w", err)
+	}
+
+	for _, col := range metadata.GetColumns() {
+		colType, ok := convertColumnType(col.Type)
+		if !ok {
+			// Skip over unrecognized columns.
+			continue
+		}
+
+		s.columns = append(s.columns, &Column{
+			Section: s,
+			Name:    col.Info.Name,
+			Type:    colType,
+
+			desc: col,
+		})
+	}
+
+	return nil
+}
+
+// Columns returns the set of Columns in the section. The slice of returned
+// sections must not be mutated.
+//
+// Unrecognized columns (e.g., when running older code against newer ngrams
+// sections) are skipped.
+func (s *Section) Columns() []*Column { return s.columns }
+
+// ColumnType represents the kind of information stored in a [Column].
+type ColumnType int
+
+const (
+	ColumnTypeInvalid          ColumnType = iota // ColumnTypeInvalid is an invalid column.
+	ColumnTypePath                               // ColumnTypePath is a column containing the path to the data object.
+	ColumnTypeSection                            // ColumnTypeSection is a column containing the index of the logs section in the data object.
+	ColumnTypeStreamID                           // ColumnTypeStreamID is a column containing the ID of the stream in the data object.
+	ColumnTypeNgramBloomFilter                   // ColumnTypeNgramBloomFilter is a column containing the n-gram bloom filter of the stream.
+)
+
+var columnTypeNames = map[ColumnType]string{
+	ColumnTypeInvalid:          "invalid",
+	ColumnTypePath:             "path",
+	ColumnTypeSection:          "section",
+	ColumnTypeStreamID:         "stream_id",
+	ColumnTypeNgramBloomFilter: "ngram_bloom_filter",
+}
+
+// String returns the human-readable name of ct.
+func (ct ColumnType) String() string {
+	text, ok := columnTypeNames[ct]
+	if !ok {
+		return fmt.Sprintf("ColumnType(%d)", ct)
+	}
+	return text
+}
+
+// A Column represents one of the columns in the ngrams section. Valid columns
+// can only be retrieved by calling [Section.Columns].
+//
+// Data in columns can be read by using a [RowReader].
+type Column struct {
+	Section *Section
+	Name    string
+	Type    ColumnType
+
+	desc *ngramsmd.ColumnDesc // Column description used for further decoding and reading.
+}
+
+func convertColumnType(protoType ngramsmd.ColumnType) (ColumnType, bool) {
+	switch protoType {
+	case ngramsmd.COLUMN_TYPE_UNSPECIFIED:
+		return ColumnTypeInvalid, true
+	case ngramsmd.COLUMN_TYPE_PATH:
+		return ColumnTypePath, true
+	case ngramsmd.COLUMN_TYPE_SECTION:
+		return ColumnTypeSection, true
+	case ngramsmd.COLUMN_TYPE_STREAM_ID:
+		return ColumnTypeStreamID, true
+	case ngramsmd.COLUMN_TYPE_NGRAM_BLOOM_FILTER:
+		return ColumnTypeNgramBloomFilter, true
+	}
+	return ColumnTypeInvalid, false
+}
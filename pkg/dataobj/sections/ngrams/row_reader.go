@@ -0,0 +1,131 @@
+package ngrams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/dataset"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/metadata/ngramsmd"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/util/slicegrow"
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/util/symbolizer"
+)
+
+// RowReader reads the rows of an ngrams section.
+type RowReader struct {
+	sec   *Section
+	ready bool
+
+	buf []dataset.Row
+
+	reader     *dataset.Reader
+	columns    []dataset.Column
+	columnDesc []*ngramsmd.ColumnDesc
+
+	symbols *symbolizer.Symbolizer
+}
+
+// NewRowReader creates a new RowReader for the given section.
+func NewRowReader(sec *Section) *RowReader {
+	var r RowReader
+	r.Reset(sec)
+	return &r
+}
+
+// Read reads up to the next len(s) rows from the reader and stores them into
+// s. It returns the number of rows read and any error encountered. At the end
+// of the ngrams section, Read returns 0, io.EOF.
+func (r *RowReader) Read(ctx context.Context, s []StreamNgrams) (int, error) {
+	if r.sec == nil {
+		return 0, io.EOF
+	}
+
+	if !r.ready {
+		err := r.initReader(ctx)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	r.buf = slicegrow.GrowToCap(r.buf, len(s))
+	r.buf = r.buf[:len(s)]
+	n, err := r.reader.Read(ctx, r.buf)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, fmt.Errorf("reading rows: %w", err)
+	} else if n == 0 && errors.Is(err, io.EOF) {
+		return 0, io.EOF
+	}
+
+	for i := range r.buf[:n] {
+		if err := decodeRow(r.columnDesc, r.buf[i], &s[i], r.symbols); err != nil {
+			return i, fmt.Errorf("decoding row: %w", err)
+		}
+	}
+
+	return n, nil
+}
+
+func (r *RowReader) initReader(ctx context.Context) error {
+	dec := newDecoder(r.sec.reader)
+
+	metadata, err := dec.Metadata(ctx)
+	if err != nil {
+		return fmt.Errorf("reading metadata: %w", err)
+	}
+
+	dset, err := newColumnsDataset(r.sec.Columns())
+	if err != nil {
+		return fmt.Errorf("creating section dataset: %w", err)
+	}
+	columns := dset.Columns()
+
+	readerOpts := dataset.ReaderOptions{
+		Dataset: dset,
+		Columns: columns,
+
+		TargetCacheSize: 16_000_000, // Permit up to 16MB of cache pages.
+	}
+
+	if r.reader == nil {
+		r.reader = dataset.NewReader(readerOpts)
+	} else {
+		r.reader.Reset(readerOpts)
+	}
+
+	if r.symbols == nil {
+		r.symbols = symbolizer.New(128, 100_000)
+	} else {
+		r.symbols.Reset()
+	}
+
+	r.columnDesc = metadata.GetColumns()
+	r.columns = columns
+	r.ready = true
+	return nil
+}
+
+// Reset resets the RowReader with a new decoder to read from. Reset allows
+// reusing a RowReader without allocating a new one.
+//
+// Reset may be called with a nil section to clear the RowReader without
+// needing a new section.
+func (r *RowReader) Reset(sec *Section) {
+	r.sec = sec
+	r.ready = false
+	r.columns = nil
+	r.columnDesc = nil
+
+	if r.symbols != nil {
+		r.symbols.Reset()
+	}
+}
+
+// Close closes the RowReader and releases any resources it holds. Closed
+// RowReaders can be reused by calling [RowReader.Reset].
+func (r *RowReader) Close() error {
+	if r.reader != nil {
+		return r.reader.Close()
+	}
+	return nil
+}
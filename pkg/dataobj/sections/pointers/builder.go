@@ -1,6 +1,7 @@
 package pointers
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"sort"
@@ -141,6 +142,18 @@ func (b *Builder) ObserveStream(path string, section int64, idInObject int64, id
 	b.streamLookup[b.key] = newPointer
 }
 
+// Append appends a copy of pointer, such as a pointer read from another
+// index object.
+func (b *Builder) Append(pointer SectionPointer) {
+	pointer.ValuesBloomFilter = bytes.Clone(pointer.ValuesBloomFilter)
+	newPointer := &pointer
+	b.pointers = append(b.pointers, newPointer)
+
+	if pointer.PointerKind == PointerKindStreamIndex {
+		b.streamLookup[streamKey{objectPath: pointer.Path, section: pointer.Section, streamID: pointer.StreamIDRef}] = newPointer
+	}
+}
+
 func (b *Builder) RecordColumnIndex(path string, section int64, columnName string, columnIndex int64, valuesBloomFilter []byte) {
 	newPointer := &SectionPointer{
 		Path:              path,
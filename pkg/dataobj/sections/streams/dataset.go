@@ -185,8 +185,10 @@ func newDatasetPage(dec *decoder, desc *streamsmd.PageDesc) *datasetPage {
 			RowCount:         int(info.RowsCount),
 			ValuesCount:      int(info.ValuesCount),
 
-			Encoding: info.Encoding,
-			Stats:    info.Statistics,
+			Encoding:    info.Encoding,
+			Stats:       info.Statistics,
+			BloomFilter: info.BloomFilter,
+			Dictionary:  info.Dictionary,
 		},
 	}
 }
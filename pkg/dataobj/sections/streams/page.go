@@ -0,0 +1,27 @@
+package streams
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/dataset"
+)
+
+// DecodePage decodes the rows of the page at index page of the column at
+// index column in the streams section. Rows are returned as int64, uint64 or
+// []byte values depending on the value type of the column; NULL rows are
+// returned as nil.
+//
+// Columns are indexed in the order returned by [Section.Columns].
+func DecodePage(ctx context.Context, section *Section, column, page int) ([]any, error) {
+	columns := section.Columns()
+	if column < 0 || column >= len(columns) {
+		return nil, fmt.Errorf("column %d out of range: section has %d columns", column, len(columns))
+	}
+
+	dset, err := newColumnsDataset(columns[column : column+1])
+	if err != nil {
+		return nil, err
+	}
+	return dataset.DecodePage(ctx, dset.Columns()[0], page)
+}
@@ -205,6 +205,10 @@ func translateStreamsPredicate(p RowPredicate, columns []dataset.Column, columnD
 			return desc.Type == streamsmd.COLUMN_TYPE_LABEL && desc.Info.Name == p.Name
 		})
 		if metadataColumn == nil {
+			// Streams without the label column have an empty value for it.
+			if p.Value == "" {
+				return dataset.TruePredicate{}
+			}
 			return dataset.FalsePredicate{}
 		}
 		return dataset.EqualPredicate{
@@ -217,6 +221,10 @@ func translateStreamsPredicate(p RowPredicate, columns []dataset.Column, columnD
 			return desc.Type == streamsmd.COLUMN_TYPE_LABEL && desc.Info.Name == p.Name
 		})
 		if metadataColumn == nil {
+			// Streams without the label column have an empty value for it.
+			if p.Keep(p.Name, "") {
+				return dataset.TruePredicate{}
+			}
 			return dataset.FalsePredicate{}
 		}
 		return dataset.FuncPredicate{
@@ -0,0 +1,34 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/internal/dataset"
+)
+
+// Verify decodes every page of the streams section and checks it against the
+// section metadata, including page checksums, row and value counts, and
+// range statistics. Verify returns an error describing every problem found,
+// or nil if the section is valid.
+func Verify(ctx context.Context, section *Section) error {
+	columns := section.Columns()
+	dset, err := newColumnsDataset(columns)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, col := range dset.Columns() {
+		if err := dataset.VerifyColumn(ctx, col); err != nil {
+			desc := columns[i].Type.String()
+			if columns[i].Name != "" {
+				desc += " " + strconv.Quote(columns[i].Name)
+			}
+			errs = append(errs, fmt.Errorf("column %d (%s): %w", i, desc, err))
+		}
+	}
+	return errors.Join(errs...)
+}
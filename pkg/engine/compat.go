@@ -27,6 +27,7 @@ type ResultBuilder interface {
 
 var _ ResultBuilder = &streamsResultBuilder{}
 var _ ResultBuilder = &vectorResultBuilder{}
+var _ ResultBuilder = &matrixResultBuilder{}
 
 func newStreamsResultBuilder() *streamsResultBuilder {
 	return &streamsResultBuilder{
@@ -66,6 +67,7 @@ func (b *streamsResultBuilder) collectRow(rec arrow.Record, i int) (labels.Label
 	var entry logproto.Entry
 	lbs := labels.NewBuilder(labels.EmptyLabels())
 	metadata := labels.NewBuilder(labels.EmptyLabels())
+	parsed := labels.NewBuilder(labels.EmptyLabels())
 
 	for colIdx := range int(rec.NumCols()) {
 		col := rec.Column(colIdx)
@@ -111,10 +113,21 @@ func (b *streamsResultBuilder) collectRow(rec arrow.Record, i int) (labels.Label
 			}
 			continue
 		}
+
+		// Extract parsed
+		if colType == types.ColumnTypeParsed.String() {
+			switch arr := col.(type) {
+			case *array.String:
+				parsed.Set(colName, arr.Value(i))
+				// include parsed labels in stream labels
+				lbs.Set(colName, arr.Value(i))
+			}
+			continue
+		}
 	}
 	entry.StructuredMetadata = logproto.FromLabelsToLabelAdapters(metadata.Labels())
 	// set to a non-nil value to match with existing engine.
-	entry.Parsed = logproto.FromLabelsToLabelAdapters(labels.Labels{})
+	entry.Parsed = logproto.FromLabelsToLabelAdapters(parsed.Labels())
 
 	return lbs.Labels(), entry
 }
@@ -136,6 +149,10 @@ func (b *streamsResultBuilder) Len() int {
 type vectorResultBuilder struct {
 	data        promql.Vector
 	lblsBuilder *labels.Builder
+
+	// preserveOrder keeps the samples in the order they were collected
+	// instead of sorting them by labels, which is required for sort and sort_desc.
+	preserveOrder bool
 }
 
 func newVectorResultBuilder() *vectorResultBuilder {
@@ -157,8 +174,14 @@ func (b *vectorResultBuilder) CollectRecord(rec arrow.Record) {
 }
 
 func (b *vectorResultBuilder) collectRow(rec arrow.Record, i int) (promql.Sample, bool) {
+	return collectSample(rec, i, b.lblsBuilder)
+}
+
+// collectSample converts row i of the record into a [promql.Sample].
+// It returns false if the row does not contain a valid timestamp and value.
+func collectSample(rec arrow.Record, i int, lblsBuilder *labels.Builder) (promql.Sample, bool) {
 	var sample promql.Sample
-	b.lblsBuilder.Reset(labels.EmptyLabels())
+	lblsBuilder.Reset(labels.EmptyLabels())
 
 	// TODO: we add a lot of overhead by reading row by row. Switch to vectorized conversion.
 	for colIdx := range int(rec.NumCols()) {
@@ -184,27 +207,29 @@ func (b *vectorResultBuilder) collectRow(rec arrow.Record, i int) (promql.Sample
 				return promql.Sample{}, false
 			}
 
-			col, ok := col.(*array.Int64)
+			col, ok := col.(*array.Float64)
 			if !ok {
 				return promql.Sample{}, false
 			}
-			sample.F = float64(col.Value(i))
+			sample.F = col.Value(i)
 		default:
 			// allow any string columns
 			if colDataType == datatype.Loki.String.String() {
-				b.lblsBuilder.Set(colName, col.(*array.String).Value(i))
+				lblsBuilder.Set(colName, col.(*array.String).Value(i))
 			}
 		}
 	}
 
-	sample.Metric = b.lblsBuilder.Labels()
+	sample.Metric = lblsBuilder.Labels()
 	return sample, true
 }
 
 func (b *vectorResultBuilder) Build(s stats.Result, md *metadata.Context) logqlmodel.Result {
-	sort.Slice(b.data, func(i, j int) bool {
-		return labels.Compare(b.data[i].Metric, b.data[j].Metric) < 0
-	})
+	if !b.preserveOrder {
+		sort.Slice(b.data, func(i, j int) bool {
+			return labels.Compare(b.data[i].Metric, b.data[j].Metric) < 0
+		})
+	}
 	return logqlmodel.Result{
 		Data:       b.data,
 		Statistics: s,
@@ -216,3 +241,60 @@ func (b *vectorResultBuilder) Build(s stats.Result, md *metadata.Context) logqlm
 func (b *vectorResultBuilder) Len() int {
 	return len(b.data)
 }
+
+type matrixResultBuilder struct {
+	series      map[uint64]int // maps the hash of the series labels to its index in data
+	data        promql.Matrix
+	count       int
+	lblsBuilder *labels.Builder
+}
+
+func newMatrixResultBuilder() *matrixResultBuilder {
+	return &matrixResultBuilder{
+		series:      make(map[uint64]int),
+		data:        promql.Matrix{},
+		lblsBuilder: labels.NewBuilder(labels.EmptyLabels()),
+	}
+}
+
+func (b *matrixResultBuilder) CollectRecord(rec arrow.Record) {
+	for row := range int(rec.NumRows()) {
+		sample, ok := collectSample(rec, row, b.lblsBuilder)
+		if !ok {
+			continue
+		}
+
+		// TODO: handle hash collisions
+		key := sample.Metric.Hash()
+		idx, ok := b.series[key]
+		if !ok {
+			idx = len(b.data)
+			b.series[key] = idx
+			b.data = append(b.data, promql.Series{Metric: sample.Metric})
+		}
+		b.data[idx].Floats = append(b.data[idx].Floats, promql.FPoint{T: sample.T, F: sample.F})
+		b.count++
+	}
+}
+
+func (b *matrixResultBuilder) Build(s stats.Result, md *metadata.Context) logqlmodel.Result {
+	// Points of a series are not guaranteed to be collected in order, as
+	// results may be produced by multiple pipelines.
+	for _, series := range b.data {
+		sort.Slice(series.Floats, func(i, j int) bool {
+			return series.Floats[i].T < series.Floats[j].T
+		})
+	}
+	sort.Sort(b.data)
+
+	return logqlmodel.Result{
+		Data:       b.data,
+		Statistics: s,
+		Headers:    md.Headers(),
+		Warnings:   md.Warnings(),
+	}
+}
+
+func (b *matrixResultBuilder) Len() int {
+	return b.count
+}
@@ -190,6 +190,55 @@ func TestStreamsResultBuilder(t *testing.T) {
 		}
 		require.Equal(t, expected, result.Data.(logqlmodel.Streams))
 	})
+
+	t.Run("parsed columns are added to stream labels and parsed labels of the entry", func(t *testing.T) {
+		mdTypeParsed := datatype.ColumnMetadata(types.ColumnTypeParsed, datatype.Loki.String)
+		schema := arrow.NewSchema(
+			[]arrow.Field{
+				{Name: types.ColumnNameBuiltinTimestamp, Type: arrow.FixedWidthTypes.Timestamp_ns, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
+				{Name: types.ColumnNameBuiltinMessage, Type: arrow.BinaryTypes.String, Metadata: datatype.ColumnMetadataBuiltinMessage},
+				{Name: "env", Type: arrow.BinaryTypes.String, Metadata: mdTypeLabel},
+				{Name: "level", Type: arrow.BinaryTypes.String, Metadata: mdTypeParsed},
+			},
+			nil,
+		)
+
+		data := [][]interface{}{
+			{arrow.Timestamp(1620000000000000001), "level=error", "prod", "error"},
+			{arrow.Timestamp(1620000000000000002), "msg=timeout", "prod", nil},
+		}
+
+		record := createRecord(t, schema, data)
+		defer record.Release()
+
+		pipeline := executor.NewBufferedPipeline(record)
+		defer pipeline.Close()
+
+		builder := newStreamsResultBuilder()
+		err := collectResult(context.Background(), pipeline, builder)
+
+		require.NoError(t, err)
+		require.Equal(t, 2, builder.Len())
+
+		md, _ := metadata.NewContext(t.Context())
+		result := builder.Build(stats.Result{}, md)
+
+		expected := logqlmodel.Streams{
+			push.Stream{
+				Labels: labels.FromStrings("env", "prod", "level", "error").String(),
+				Entries: []logproto.Entry{
+					{Line: "level=error", Timestamp: time.Unix(0, 1620000000000000001), StructuredMetadata: logproto.FromLabelsToLabelAdapters(labels.Labels{}), Parsed: logproto.FromLabelsToLabelAdapters(labels.FromStrings("level", "error"))},
+				},
+			},
+			push.Stream{
+				Labels: labels.FromStrings("env", "prod").String(),
+				Entries: []logproto.Entry{
+					{Line: "msg=timeout", Timestamp: time.Unix(0, 1620000000000000002), StructuredMetadata: logproto.FromLabelsToLabelAdapters(labels.Labels{}), Parsed: logproto.FromLabelsToLabelAdapters(labels.Labels{})},
+				},
+			},
+		}
+		require.Equal(t, expected, result.Data.(logqlmodel.Streams))
+	})
 }
 
 func TestVectorResultBuilder(t *testing.T) {
@@ -199,7 +248,7 @@ func TestVectorResultBuilder(t *testing.T) {
 		schema := arrow.NewSchema(
 			[]arrow.Field{
 				{Name: types.ColumnNameBuiltinTimestamp, Type: arrow.FixedWidthTypes.Timestamp_ns, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
-				{Name: types.ColumnNameGeneratedValue, Type: arrow.PrimitiveTypes.Int64, Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Integer)},
+				{Name: types.ColumnNameGeneratedValue, Type: arrow.PrimitiveTypes.Float64, Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Float)},
 				{Name: "instance", Type: arrow.BinaryTypes.String, Metadata: mdTypeString},
 				{Name: "job", Type: arrow.BinaryTypes.String, Metadata: mdTypeString},
 			},
@@ -207,9 +256,9 @@ func TestVectorResultBuilder(t *testing.T) {
 		)
 
 		data := [][]any{
-			{arrow.Timestamp(1620000000000000000), int64(42), "localhost:9090", "prometheus"},
-			{arrow.Timestamp(1620000000000000000), int64(23), "localhost:9100", "node-exporter"},
-			{arrow.Timestamp(1620000000000000000), int64(15), "localhost:9100", "prometheus"},
+			{arrow.Timestamp(1620000000000000000), float64(42), "localhost:9090", "prometheus"},
+			{arrow.Timestamp(1620000000000000000), float64(23), "localhost:9100", "node-exporter"},
+			{arrow.Timestamp(1620000000000000000), float64(15), "localhost:9100", "prometheus"},
 		}
 
 		record := createRecord(t, schema, data)
@@ -250,14 +299,14 @@ func TestVectorResultBuilder(t *testing.T) {
 		schema := arrow.NewSchema(
 			[]arrow.Field{
 				{Name: types.ColumnNameBuiltinTimestamp, Type: arrow.FixedWidthTypes.Timestamp_ns, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
-				{Name: types.ColumnNameGeneratedValue, Type: arrow.PrimitiveTypes.Int64, Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Integer)},
+				{Name: types.ColumnNameGeneratedValue, Type: arrow.PrimitiveTypes.Float64, Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Float)},
 				{Name: "instance", Type: arrow.BinaryTypes.String, Metadata: mdTypeString},
 			},
 			nil,
 		)
 
 		data := [][]interface{}{
-			{nil, int64(42), "localhost:9090"},
+			{nil, float64(42), "localhost:9090"},
 			{arrow.Timestamp(1620000000000000000), nil, "localhost:9100"},
 		}
 
@@ -274,3 +323,51 @@ func TestVectorResultBuilder(t *testing.T) {
 		require.Equal(t, 0, builder.Len(), "expected no samples to be collected")
 	})
 }
+
+func TestMatrixResultBuilder(t *testing.T) {
+	mdTypeString := datatype.ColumnMetadata(types.ColumnTypeAmbiguous, datatype.Loki.String)
+
+	t.Run("successful conversion of matrix data", func(t *testing.T) {
+		schema := arrow.NewSchema(
+			[]arrow.Field{
+				{Name: types.ColumnNameBuiltinTimestamp, Type: arrow.FixedWidthTypes.Timestamp_ns, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
+				{Name: types.ColumnNameGeneratedValue, Type: arrow.PrimitiveTypes.Float64, Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Float)},
+				{Name: "instance", Type: arrow.BinaryTypes.String, Metadata: mdTypeString},
+				{Name: "job", Type: arrow.BinaryTypes.String, Metadata: mdTypeString},
+			},
+			nil,
+		)
+
+		data := [][]any{
+			{arrow.Timestamp(1620000060000000000), float64(43), "localhost:9090", "prometheus"},
+			{arrow.Timestamp(1620000000000000000), float64(42), "localhost:9090", "prometheus"},
+			{arrow.Timestamp(1620000000000000000), float64(23), "localhost:9100", "node-exporter"},
+			{arrow.Timestamp(1620000060000000000), float64(24), "localhost:9100", "node-exporter"},
+			{nil, float64(15), "localhost:9100", "prometheus"},
+		}
+
+		record := createRecord(t, schema, data)
+		defer record.Release()
+
+		pipeline := executor.NewBufferedPipeline(record)
+		defer pipeline.Close()
+
+		builder := newMatrixResultBuilder()
+		err := collectResult(context.Background(), pipeline, builder)
+
+		require.NoError(t, err)
+		require.Equal(t, 4, builder.Len())
+
+		md, _ := metadata.NewContext(t.Context())
+		result := builder.Build(stats.Result{}, md)
+		matrix := result.Data.(promql.Matrix)
+		require.Equal(t, 2, len(matrix))
+
+		// Series are sorted by labels, points are sorted by timestamp
+		require.Equal(t, labels.FromStrings("instance", "localhost:9090", "job", "prometheus"), matrix[0].Metric)
+		require.Equal(t, []promql.FPoint{{T: 1620000000000, F: 42}, {T: 1620000060000, F: 43}}, matrix[0].Floats)
+
+		require.Equal(t, labels.FromStrings("instance", "localhost:9100", "job", "node-exporter"), matrix[1].Metric)
+		require.Equal(t, []promql.FPoint{{T: 1620000000000, F: 23}, {T: 1620000060000, F: 24}}, matrix[1].Floats)
+	})
+}
@@ -34,11 +34,11 @@ var tracer = otel.Tracer("pkg/engine")
 var ErrNotSupported = errors.New("feature not supported in new query engine")
 
 // New creates a new instance of the query engine that implements the [logql.Engine] interface.
-func New(opts logql.EngineOpts, bucket objstore.Bucket, limits logql.Limits, reg prometheus.Registerer, logger log.Logger) *QueryEngine {
+func New(opts logql.EngineOpts, metastoreCfg metastore.Config, bucket objstore.Bucket, limits logql.Limits, reg prometheus.Registerer, logger log.Logger) *QueryEngine {
 	var ms metastore.Metastore
 	if bucket != nil {
 		metastoreBucket := objstore.NewPrefixedBucket(bucket, opts.CataloguePath)
-		ms = metastore.NewObjectMetastore(metastoreBucket, logger, reg)
+		ms = metastore.NewObjectMetastore(metastoreCfg, metastoreBucket, logger, reg)
 	}
 
 	if opts.BatchSize <= 0 {
@@ -91,11 +91,8 @@ func (e *QueryEngine) Execute(ctx context.Context, params logql.Params) (logqlmo
 	defer span.End()
 
 	var (
-		startTime = time.Now()
-
-		durLogicalPlanning  time.Duration
-		durPhysicalPlanning time.Duration
-		durExecution        time.Duration
+		startTime    = time.Now()
+		durExecution time.Duration
 	)
 
 	statsCtx, ctx := stats.NewContext(ctx)
@@ -104,74 +101,14 @@ func (e *QueryEngine) Execute(ctx context.Context, params logql.Params) (logqlmo
 	logger := utillog.WithContext(ctx, e.logger)
 	logger = log.With(logger, "query", params.QueryString(), "shard", strings.Join(params.Shards(), ","), "engine", "v2")
 
-	logicalPlan, err := func() (*logical.Plan, error) {
-		_, span := tracer.Start(ctx, "QueryEngine.Execute.logicalPlan")
-		defer span.End()
-
-		timer := prometheus.NewTimer(e.metrics.logicalPlanning)
-		logicalPlan, err := logical.BuildPlan(params)
-		if err != nil {
-			level.Warn(logger).Log("msg", "failed to create logical plan", "err", err)
-			e.metrics.subqueries.WithLabelValues(statusNotImplemented).Inc()
-			span.RecordError(err)
-			span.SetStatus(codes.Error, "failed to create logical plan")
-			return nil, ErrNotSupported
-		}
-
-		durLogicalPlanning = timer.ObserveDuration()
-		level.Info(logger).Log(
-			"msg", "finished logical planning",
-			"plan", logicalPlan.String(),
-			"duration", durLogicalPlanning.Seconds(),
-		)
-		span.SetStatus(codes.Ok, "")
-		return logicalPlan, nil
-	}()
+	logicalPlan, durLogicalPlanning, err := e.buildLogicalPlan(ctx, logger, params)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "failed to create logical plan")
 		return logqlmodel.Result{}, err
 	}
 
-	physicalPlan, err := func() (*physical.Plan, error) {
-		ctx, span := tracer.Start(ctx, "QueryEngine.Execute.physicalPlan")
-		defer span.End()
-
-		timer := prometheus.NewTimer(e.metrics.physicalPlanning)
-
-		catalogueType := physical.CatalogueTypeDirect
-		if e.opts.CataloguePath != "" {
-			catalogueType = physical.CatalogueTypeIndex
-		}
-		catalog := physical.NewMetastoreCatalog(ctx, e.metastore, catalogueType)
-		planner := physical.NewPlanner(physical.NewContext(params.Start(), params.End()), catalog)
-		plan, err := planner.Build(logicalPlan)
-		if err != nil {
-			level.Warn(logger).Log("msg", "failed to create physical plan", "err", err)
-			e.metrics.subqueries.WithLabelValues(statusFailure).Inc()
-			span.RecordError(err)
-			span.SetStatus(codes.Error, "failed to create physical plan")
-			return nil, ErrNotSupported
-		}
-
-		plan, err = planner.Optimize(plan)
-		if err != nil {
-			level.Warn(logger).Log("msg", "failed to optimize physical plan", "err", err)
-			e.metrics.subqueries.WithLabelValues(statusFailure).Inc()
-			span.RecordError(err)
-			span.SetStatus(codes.Error, "failed to optimize physical plan")
-			return nil, ErrNotSupported
-		}
-
-		durPhysicalPlanning = timer.ObserveDuration()
-		level.Info(logger).Log(
-			"msg", "finished physical planning",
-			"plan", physical.PrintAsTree(plan),
-			"duration", durPhysicalPlanning.Seconds(),
-		)
-		span.SetStatus(codes.Ok, "")
-		return plan, nil
-	}()
+	physicalPlan, durPhysicalPlanning, err := e.buildPhysicalPlan(ctx, logger, params, logicalPlan)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "failed to create physical plan")
@@ -186,12 +123,7 @@ func (e *QueryEngine) Execute(ctx context.Context, params logql.Params) (logqlmo
 
 		timer := prometheus.NewTimer(e.metrics.execution)
 
-		cfg := executor.Config{
-			BatchSize:                int64(e.opts.BatchSize),
-			Bucket:                   e.bucket,
-			DataobjScanPageCacheSize: int64(e.opts.DataobjScanPageCacheSize),
-		}
-		pipeline := executor.Run(ctx, cfg, physicalPlan, logger)
+		pipeline := executor.Run(ctx, e.executorConfig(), physicalPlan, logger)
 		defer pipeline.Close()
 
 		var builder ResultBuilder
@@ -199,8 +131,14 @@ func (e *QueryEngine) Execute(ctx context.Context, params logql.Params) (logqlmo
 		case syntax.LogSelectorExpr:
 			builder = newStreamsResultBuilder()
 		case syntax.SampleExpr:
-			// assume instant query since logical planning would fail for range queries.
-			builder = newVectorResultBuilder()
+			if logql.GetRangeType(params) == logql.InstantType {
+				vectorBuilder := newVectorResultBuilder()
+				// the order of samples is defined by the query if it contains sort or sort_desc.
+				vectorBuilder.preserveOrder, _ = logql.Sortable(params)
+				builder = vectorBuilder
+			} else {
+				builder = newMatrixResultBuilder()
+			}
 		default:
 			// should never happen as we already check the expression type in the logical planner
 			panic(fmt.Sprintf("failed to execute. Invalid exprression type (%T)", params.GetExpression()))
@@ -241,6 +179,82 @@ func (e *QueryEngine) Execute(ctx context.Context, params logql.Params) (logqlmo
 	return builder.Build(stats, metadataCtx), nil
 }
 
+// buildLogicalPlan creates the logical plan of a query. Queries that cannot be
+// planned return [ErrNotSupported].
+func (e *QueryEngine) buildLogicalPlan(ctx context.Context, logger log.Logger, params logql.Params) (*logical.Plan, time.Duration, error) {
+	_, span := tracer.Start(ctx, "QueryEngine.Execute.logicalPlan")
+	defer span.End()
+
+	timer := prometheus.NewTimer(e.metrics.logicalPlanning)
+	logicalPlan, err := logical.BuildPlan(params)
+	if err != nil {
+		level.Warn(logger).Log("msg", "failed to create logical plan", "err", err)
+		e.metrics.subqueries.WithLabelValues(statusNotImplemented).Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to create logical plan")
+		return nil, 0, ErrNotSupported
+	}
+
+	duration := timer.ObserveDuration()
+	level.Info(logger).Log(
+		"msg", "finished logical planning",
+		"plan", logicalPlan.String(),
+		"duration", duration.Seconds(),
+	)
+	span.SetStatus(codes.Ok, "")
+	return logicalPlan, duration, nil
+}
+
+// buildPhysicalPlan creates the optimized physical plan of a query from its
+// logical plan.
+func (e *QueryEngine) buildPhysicalPlan(ctx context.Context, logger log.Logger, params logql.Params, logicalPlan *logical.Plan) (*physical.Plan, time.Duration, error) {
+	ctx, span := tracer.Start(ctx, "QueryEngine.Execute.physicalPlan")
+	defer span.End()
+
+	timer := prometheus.NewTimer(e.metrics.physicalPlanning)
+
+	catalogueType := physical.CatalogueTypeDirect
+	if e.opts.CataloguePath != "" {
+		catalogueType = physical.CatalogueTypeIndex
+	}
+	catalog := physical.NewMetastoreCatalog(ctx, e.metastore, catalogueType)
+	planner := physical.NewPlanner(physical.NewContext(params.Start(), params.End()), catalog)
+	plan, err := planner.Build(logicalPlan)
+	if err != nil {
+		level.Warn(logger).Log("msg", "failed to create physical plan", "err", err)
+		e.metrics.subqueries.WithLabelValues(statusFailure).Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to create physical plan")
+		return nil, 0, ErrNotSupported
+	}
+
+	plan, err = planner.Optimize(plan)
+	if err != nil {
+		level.Warn(logger).Log("msg", "failed to optimize physical plan", "err", err)
+		e.metrics.subqueries.WithLabelValues(statusFailure).Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to optimize physical plan")
+		return nil, 0, ErrNotSupported
+	}
+
+	duration := timer.ObserveDuration()
+	level.Info(logger).Log(
+		"msg", "finished physical planning",
+		"plan", physical.PrintAsTree(plan),
+		"duration", duration.Seconds(),
+	)
+	span.SetStatus(codes.Ok, "")
+	return plan, duration, nil
+}
+
+func (e *QueryEngine) executorConfig() executor.Config {
+	return executor.Config{
+		BatchSize:                int64(e.opts.BatchSize),
+		Bucket:                   e.bucket,
+		DataobjScanPageCacheSize: int64(e.opts.DataobjScanPageCacheSize),
+	}
+}
+
 func collectResult(ctx context.Context, pipeline executor.Pipeline, builder ResultBuilder) error {
 	for {
 		if err := pipeline.Read(ctx); err != nil {
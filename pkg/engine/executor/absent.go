@@ -0,0 +1,93 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/engine/planner/physical"
+)
+
+// NewAbsentPipeline returns a pipeline that reads all records of its inputs
+// and returns a single record with one sample for each step of node at which
+// the inputs have no samples. Each sample has the value 1 and the labels of
+// node. The pipeline is exhausted without a record if the inputs have samples
+// at all steps.
+func NewAbsentPipeline(node *physical.Absent, inputs ...Pipeline) *GenericPipeline {
+	done := false
+	return newGenericPipeline(Local, func(ctx context.Context, inputs []Pipeline) state {
+		if done {
+			return Exhausted
+		}
+		done = true
+
+		present := make(map[arrow.Timestamp]struct{})
+		for _, input := range inputs {
+			if err := readTimestamps(ctx, input, present); err != nil {
+				return failureState(err)
+			}
+		}
+
+		start, step := node.Start, node.Step
+		if step <= 0 {
+			// instant query
+			start, step = node.End, time.Nanosecond
+		}
+
+		var (
+			timestamps []arrow.Timestamp
+			results    [][]binOpSample
+		)
+		for t := start; !t.After(node.End); t = t.Add(step) {
+			ts, _ := arrow.TimestampFromTime(t, arrow.Nanosecond)
+			if _, ok := present[ts]; ok {
+				continue
+			}
+			timestamps = append(timestamps, ts)
+			results = append(results, []binOpSample{{labels: node.Labels, value: 1}})
+		}
+
+		if len(timestamps) == 0 {
+			return Exhausted
+		}
+		return successState(buildSamplesRecord(timestamps, results))
+	}, inputs...)
+}
+
+// readTimestamps reads all records of input and adds the values of their
+// timestamp column to timestamps.
+func readTimestamps(ctx context.Context, input Pipeline, timestamps map[arrow.Timestamp]struct{}) error {
+	for {
+		if err := input.Read(ctx); err != nil {
+			if errors.Is(err, EOF) {
+				return nil
+			}
+			return err
+		}
+
+		record, err := input.Value()
+		if err != nil {
+			return err
+		}
+
+		indices := record.Schema().FieldIndices(types.ColumnNameBuiltinTimestamp)
+		if len(indices) == 0 {
+			return fmt.Errorf("missing column %s", types.ColumnNameBuiltinTimestamp)
+		}
+		tsCol, ok := record.Column(indices[0]).(*array.Timestamp)
+		if !ok {
+			return fmt.Errorf("invalid type %s of column %s", record.Column(indices[0]).DataType(), types.ColumnNameBuiltinTimestamp)
+		}
+
+		for i := range tsCol.Len() {
+			if tsCol.IsValid(i) {
+				timestamps[tsCol.Value(i)] = struct{}{}
+			}
+		}
+	}
+}
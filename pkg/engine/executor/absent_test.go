@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/engine/planner/physical"
+	"github.com/grafana/loki/v3/pkg/util/arrowtest"
+)
+
+func TestAbsentPipeline(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: types.ColumnNameBuiltinTimestamp, Type: datatype.Arrow.Timestamp, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
+		{Name: types.ColumnNameGeneratedValue, Type: datatype.Arrow.Float, Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Float)},
+		{Name: "app", Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)},
+	}, nil)
+
+	var (
+		start = time.Unix(10, 0).UTC()
+		end   = time.Unix(40, 0).UTC()
+		step  = 10 * time.Second
+	)
+
+	for _, tt := range []struct {
+		name     string
+		node     *physical.Absent
+		inputs   []arrowtest.Rows
+		expected arrowtest.Rows
+	}{
+		{
+			name: "steps without samples",
+			node: &physical.Absent{Labels: labels.FromStrings("app", "foo"), Start: start, End: end, Step: step},
+			inputs: []arrowtest.Rows{
+				{
+					{types.ColumnNameBuiltinTimestamp: start, types.ColumnNameGeneratedValue: 1.0, "app": "foo"},
+				},
+				{
+					{types.ColumnNameBuiltinTimestamp: start.Add(2 * step), types.ColumnNameGeneratedValue: 3.0, "app": "foo"},
+				},
+			},
+			expected: arrowtest.Rows{
+				{types.ColumnNameBuiltinTimestamp: start.Add(step), types.ColumnNameGeneratedValue: 1.0, "app": "foo"},
+				{types.ColumnNameBuiltinTimestamp: end, types.ColumnNameGeneratedValue: 1.0, "app": "foo"},
+			},
+		},
+		{
+			name: "no inputs",
+			node: &physical.Absent{Start: start, End: end},
+			expected: arrowtest.Rows{
+				{types.ColumnNameBuiltinTimestamp: end, types.ColumnNameGeneratedValue: 1.0},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			inputs := make([]Pipeline, 0, len(tt.inputs))
+			for _, rows := range tt.inputs {
+				inputs = append(inputs, NewArrowtestPipeline(nil, schema, rows))
+			}
+
+			pipeline := NewAbsentPipeline(tt.node, inputs...)
+			defer pipeline.Close()
+
+			require.NoError(t, pipeline.Read(t.Context()))
+			record, err := pipeline.Value()
+			require.NoError(t, err)
+
+			actual, err := arrowtest.RecordRows(record)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+
+			require.ErrorIs(t, pipeline.Read(t.Context()), EOF)
+		})
+	}
+
+	t.Run("samples at all steps", func(t *testing.T) {
+		input := NewArrowtestPipeline(nil, schema, arrowtest.Rows{
+			{types.ColumnNameBuiltinTimestamp: end, types.ColumnNameGeneratedValue: 1.0, "app": "foo"},
+		})
+
+		pipeline := NewAbsentPipeline(&physical.Absent{Start: end, End: end}, input)
+		defer pipeline.Close()
+
+		require.ErrorIs(t, pipeline.Read(t.Context()), EOF)
+	})
+}
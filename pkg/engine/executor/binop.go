@@ -0,0 +1,643 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/engine/planner/physical"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// BinOpPipeline is a pipeline that performs a binary operation between the
+// samples of two vectors, or between the samples of a vector and a scalar.
+//
+// It reads all samples of its inputs and computes the result for each
+// timestamp like the legacy engine: samples of two vectors are matched by the
+// signature of their labels, which is computed from the labels of the on or
+// ignoring modifier. Arithmetic operations and comparisons with the bool
+// modifier return the result of the operation, comparisons without the bool
+// modifier filter the samples of the left operand, and the set operations
+// and, or and unless return the samples of the operands unchanged.
+//
+// Like in the vector aggregation, the labels of a sample are the union of
+// all string columns of the row, where columns with the same name are
+// resolved by the precedence of their column type.
+type BinOpPipeline struct {
+	state  state
+	inputs []Pipeline
+
+	left, right Pipeline // nil if the operand is the scalar
+	scalar      float64
+
+	op         types.BinaryOp
+	returnBool bool
+	matching   *syntax.VectorMatching
+
+	done bool
+}
+
+// binOpSample is a sample of an operand or the result of a binary operation.
+type binOpSample struct {
+	labels labels.Labels
+	value  float64
+}
+
+// NewBinOpPipeline returns a pipeline that performs the binary operation of
+// node between the samples of left and right. If the operation has a scalar
+// operand, the input of that operand must be nil.
+func NewBinOpPipeline(node *physical.BinOpNode, left, right Pipeline) (*BinOpPipeline, error) {
+	p := &BinOpPipeline{
+		left:       left,
+		right:      right,
+		op:         node.Op,
+		returnBool: node.ReturnBool,
+		matching:   node.VectorMatching,
+	}
+
+	switch {
+	case left != nil && right != nil:
+		p.inputs = []Pipeline{left, right}
+		if p.matching == nil {
+			p.matching = &syntax.VectorMatching{Card: syntax.CardOneToOne}
+		}
+	case left != nil || right != nil:
+		if node.Scalar == nil {
+			return nil, errors.New("binary operation is missing an operand")
+		}
+		if isSetOperation(node.Op) {
+			return nil, fmt.Errorf("set operation %s requires two vector operands", node.Op)
+		}
+		scalar, ok := node.Scalar.Literal.(datatype.FloatLiteral)
+		if !ok {
+			return nil, fmt.Errorf("unsupported scalar operand of type %s", node.Scalar.ValueType())
+		}
+		p.scalar = scalar.Value()
+		p.inputs = []Pipeline{left}
+		if left == nil {
+			p.inputs = []Pipeline{right}
+		}
+	default:
+		return nil, errors.New("binary operation requires at least one vector operand")
+	}
+
+	return p, nil
+}
+
+// Read reads the next value into its state.
+func (p *BinOpPipeline) Read(ctx context.Context) error {
+	if p.state.err != nil {
+		return p.state.err
+	}
+
+	// release previous batch before creating a new one
+	if p.state.batch != nil {
+		p.state.batch.Release()
+	}
+
+	record, err := p.read(ctx)
+	p.state = newState(record, err)
+
+	if err != nil {
+		return fmt.Errorf("run binary operation: %w", err)
+	}
+	return nil
+}
+
+func (p *BinOpPipeline) read(ctx context.Context) (arrow.Record, error) {
+	// All samples are returned with the first record.
+	if p.done {
+		return nil, EOF
+	}
+	p.done = true
+
+	var (
+		lhs, rhs map[arrow.Timestamp][]binOpSample
+		err      error
+	)
+	if p.left != nil {
+		if lhs, err = readSamples(ctx, p.left); err != nil {
+			return nil, err
+		}
+	}
+	if p.right != nil {
+		if rhs, err = readSamples(ctx, p.right); err != nil {
+			return nil, err
+		}
+	}
+
+	timestamps := make([]arrow.Timestamp, 0, max(len(lhs), len(rhs)))
+	for ts := range lhs {
+		timestamps = append(timestamps, ts)
+	}
+	for ts := range rhs {
+		if _, ok := lhs[ts]; !ok {
+			timestamps = append(timestamps, ts)
+		}
+	}
+	slices.Sort(timestamps)
+
+	results := make([][]binOpSample, len(timestamps))
+	numSamples := 0
+	for i, ts := range timestamps {
+		switch {
+		case p.left == nil:
+			results[i] = p.scalarBinop(rhs[ts], true)
+		case p.right == nil:
+			results[i] = p.scalarBinop(lhs[ts], false)
+		default:
+			if results[i], err = p.vectorBinop(lhs[ts], rhs[ts]); err != nil {
+				return nil, err
+			}
+		}
+		numSamples += len(results[i])
+	}
+
+	if numSamples == 0 {
+		return nil, EOF
+	}
+	return buildSamplesRecord(timestamps, results), nil
+}
+
+// scalarBinop applies the operation between each sample of vec and the
+// scalar. The result has the labels of the samples of vec.
+func (p *BinOpPipeline) scalarBinop(vec []binOpSample, scalarLeft bool) []binOpSample {
+	results := make([]binOpSample, 0, len(vec))
+	for _, s := range vec {
+		left, right := s.value, p.scalar
+		if scalarLeft {
+			left, right = right, left
+		}
+
+		value, ok := applyBinaryOp(p.op, left, right, !p.returnBool)
+		if !ok {
+			continue
+		}
+		if isComparisonOperation(p.op) && !p.returnBool {
+			// filtering comparisons return the value of the vector
+			value = s.value
+		}
+		results = append(results, binOpSample{labels: s.labels, value: value})
+	}
+	return results
+}
+
+// vectorBinop applies the operation between the matching samples of lhs and
+// rhs.
+func (p *BinOpPipeline) vectorBinop(lhs, rhs []binOpSample) ([]binOpSample, error) {
+	lsigs := make([]uint64, len(lhs))
+	for i, s := range lhs {
+		lsigs[i] = matchingSignature(s.labels, p.matching)
+	}
+	rsigs := make([]uint64, len(rhs))
+	for i, s := range rhs {
+		rsigs[i] = matchingSignature(s.labels, p.matching)
+	}
+
+	switch p.op {
+	case types.BinaryOpAnd:
+		return vectorAnd(lhs, rhs, lsigs, rsigs), nil
+	case types.BinaryOpOr:
+		return vectorOr(lhs, rhs, lsigs, rsigs), nil
+	case types.BinaryOpUnless:
+		return vectorUnless(lhs, rhs, lsigs, rsigs), nil
+	}
+
+	// One-to-many matching is handled like many-to-one matching with swapped
+	// operands.
+	card := p.matching.Card
+	if card == syntax.CardOneToMany {
+		lhs, rhs = rhs, lhs
+		lsigs, rsigs = rsigs, lsigs
+	}
+
+	// Add all rhs samples to a map, so we can easily find matches later.
+	rightSigs := make(map[uint64]*binOpSample, len(rhs))
+	for i := range rhs {
+		sig := rsigs[i]
+		if rightSigs[sig] != nil {
+			side := "right"
+			if card == syntax.CardOneToMany {
+				side = "left"
+			}
+			return nil, fmt.Errorf("found duplicate series on the %s hand-side"+
+				";many-to-many matching not allowed: matching labels must be unique on one side", side)
+		}
+		rightSigs[sig] = &rhs[i]
+	}
+
+	var (
+		results     = make([]binOpSample, 0, len(lhs))
+		matchedSigs = make(map[uint64]map[uint64]struct{})
+	)
+	for i := range lhs {
+		ls, sig := &lhs[i], lsigs[i]
+		rs, found := rightSigs[sig]
+		if !found {
+			continue
+		}
+
+		metric := resultLabels(ls.labels, rs.labels, p.matching)
+		insertedSigs, exists := matchedSigs[sig]
+		if card == syntax.CardOneToOne {
+			if exists {
+				return nil, errors.New("multiple matches for labels: many-to-one matching must be explicit (group_left/group_right)")
+			}
+			matchedSigs[sig] = nil
+		} else {
+			insertSig := labels.StableHash(metric)
+			if !exists {
+				insertedSigs = map[uint64]struct{}{}
+				matchedSigs[sig] = insertedSigs
+			} else if _, duplicate := insertedSigs[insertSig]; duplicate {
+				return nil, errors.New("multiple matches for labels: grouping labels must ensure unique matches")
+			}
+			insertedSigs[insertSig] = struct{}{}
+		}
+
+		// swap back before applying the operation
+		if card == syntax.CardOneToMany {
+			ls, rs = rs, ls
+		}
+
+		value, ok := applyBinaryOp(p.op, ls.value, rs.value, !p.returnBool)
+		if !ok {
+			continue
+		}
+		if isComparisonOperation(p.op) && !p.returnBool {
+			// filtering comparisons return the value of the left operand
+			value = ls.value
+		}
+		results = append(results, binOpSample{labels: metric, value: value})
+	}
+	return results, nil
+}
+
+// matchingSignature returns the signature of lbls that is used to match
+// samples of two vectors.
+func matchingSignature(lbls labels.Labels, matching *syntax.VectorMatching) uint64 {
+	if matching.On {
+		return labels.StableHash(labels.NewBuilder(lbls).Keep(matching.MatchingLabels...).Labels())
+	}
+	return labels.StableHash(labels.NewBuilder(lbls).Del(matching.MatchingLabels...).Labels())
+}
+
+// resultLabels returns the labels of the result of an operation between two
+// matching samples with the labels lhs and rhs.
+func resultLabels(lhs, rhs labels.Labels, matching *syntax.VectorMatching) labels.Labels {
+	lb := labels.NewBuilder(lhs)
+
+	if matching.Card == syntax.CardOneToOne {
+		if matching.On {
+			lhs.Range(func(l labels.Label) {
+				if !slices.Contains(matching.MatchingLabels, l.Name) {
+					lb.Del(l.Name)
+				}
+			})
+		} else {
+			lb.Del(matching.MatchingLabels...)
+		}
+	}
+	for _, ln := range matching.Include {
+		// Included labels from the group_x modifier are taken from the "one"-side.
+		if v := rhs.Get(ln); v != "" {
+			lb.Set(ln, v)
+		} else {
+			lb.Del(ln)
+		}
+	}
+
+	return lb.Labels()
+}
+
+func vectorAnd(lhs, rhs []binOpSample, lsigs, rsigs []uint64) []binOpSample {
+	if len(lhs) == 0 || len(rhs) == 0 {
+		return nil // Short-circuit: AND with nothing is nothing.
+	}
+
+	rightSigs := make(map[uint64]struct{}, len(rsigs))
+	for _, sig := range rsigs {
+		rightSigs[sig] = struct{}{}
+	}
+
+	results := make([]binOpSample, 0, len(lhs))
+	for i, ls := range lhs {
+		if _, ok := rightSigs[lsigs[i]]; ok {
+			results = append(results, ls)
+		}
+	}
+	return results
+}
+
+func vectorOr(lhs, rhs []binOpSample, lsigs, rsigs []uint64) []binOpSample {
+	if len(lhs) == 0 {
+		return rhs
+	} else if len(rhs) == 0 {
+		return lhs
+	}
+
+	leftSigs := make(map[uint64]struct{}, len(lsigs))
+	results := make([]binOpSample, 0, len(lhs)+len(rhs))
+	for i, ls := range lhs {
+		leftSigs[lsigs[i]] = struct{}{}
+		results = append(results, ls)
+	}
+	for i, rs := range rhs {
+		if _, ok := leftSigs[rsigs[i]]; !ok {
+			results = append(results, rs)
+		}
+	}
+	return results
+}
+
+func vectorUnless(lhs, rhs []binOpSample, lsigs, rsigs []uint64) []binOpSample {
+	if len(lhs) == 0 || len(rhs) == 0 {
+		return lhs
+	}
+
+	rightSigs := make(map[uint64]struct{}, len(rsigs))
+	for _, sig := range rsigs {
+		rightSigs[sig] = struct{}{}
+	}
+
+	results := make([]binOpSample, 0, len(lhs))
+	for i, ls := range lhs {
+		if _, ok := rightSigs[lsigs[i]]; !ok {
+			results = append(results, ls)
+		}
+	}
+	return results
+}
+
+// applyBinaryOp applies the arithmetic or comparison operation op to left and
+// right. Comparisons return 1 if the comparison is true and 0 otherwise. If
+// filter is set, false comparisons return false instead.
+//
+// Like in the legacy engine, division and modulo by zero return NaN.
+func applyBinaryOp(op types.BinaryOp, left, right float64, filter bool) (float64, bool) {
+	switch op {
+	case types.BinaryOpAdd:
+		return left + right, true
+	case types.BinaryOpSub:
+		return left - right, true
+	case types.BinaryOpMul:
+		return left * right, true
+	case types.BinaryOpDiv:
+		if right == 0 {
+			return math.NaN(), true
+		}
+		return left / right, true
+	case types.BinaryOpMod:
+		if right == 0 {
+			return math.NaN(), true
+		}
+		return math.Mod(left, right), true
+	case types.BinaryOpPow:
+		return math.Pow(left, right), true
+	}
+
+	var cmp bool
+	switch op {
+	case types.BinaryOpEq:
+		cmp = left == right
+	case types.BinaryOpNeq:
+		cmp = left != right
+	case types.BinaryOpGt:
+		cmp = left > right
+	case types.BinaryOpGte:
+		cmp = left >= right
+	case types.BinaryOpLt:
+		cmp = left < right
+	case types.BinaryOpLte:
+		cmp = left <= right
+	default:
+		return 0, false
+	}
+
+	switch {
+	case cmp:
+		return 1, true
+	case filter:
+		return 0, false
+	default:
+		return 0, true
+	}
+}
+
+func isComparisonOperation(op types.BinaryOp) bool {
+	switch op {
+	case types.BinaryOpEq, types.BinaryOpNeq, types.BinaryOpGt, types.BinaryOpGte, types.BinaryOpLt, types.BinaryOpLte:
+		return true
+	default:
+		return false
+	}
+}
+
+func isSetOperation(op types.BinaryOp) bool {
+	switch op {
+	case types.BinaryOpAnd, types.BinaryOpOr, types.BinaryOpUnless:
+		return true
+	default:
+		return false
+	}
+}
+
+// readSamples reads all records of input and returns their samples grouped
+// by timestamp.
+func readSamples(ctx context.Context, input Pipeline) (map[arrow.Timestamp][]binOpSample, error) {
+	samples := make(map[arrow.Timestamp][]binOpSample)
+	for {
+		if err := input.Read(ctx); err != nil {
+			if errors.Is(err, EOF) {
+				return samples, nil
+			}
+			return nil, err
+		}
+
+		record, _ := input.Value()
+		if err := appendSamples(samples, record); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// appendSamples appends the samples of the rows of record to samples. The
+// labels of a sample are all string columns of the row except builtin and
+// generated columns. Empty label values are ignored.
+func appendSamples(samples map[arrow.Timestamp][]binOpSample, record arrow.Record) error {
+	var (
+		schema     = record.Schema()
+		timestamps *array.Timestamp
+		values     *array.Float64
+		names      []string
+		columns    []labelColumn
+		byName     = make(map[string]int)
+	)
+
+	for i, field := range schema.Fields() {
+		ct := types.ColumnTypeAmbiguous
+		if value, ok := field.Metadata.GetValue(types.MetadataKeyColumnType); ok {
+			ct = types.ColumnTypeFromString(value)
+		}
+
+		switch {
+		case ct == types.ColumnTypeBuiltin && field.Name == types.ColumnNameBuiltinTimestamp:
+			timestamps, _ = record.Column(i).(*array.Timestamp)
+			continue
+		case ct == types.ColumnTypeGenerated && field.Name == types.ColumnNameGeneratedValue:
+			values, _ = record.Column(i).(*array.Float64)
+			continue
+		case ct == types.ColumnTypeBuiltin || ct == types.ColumnTypeGenerated:
+			continue
+		}
+
+		arr, ok := record.Column(i).(*array.String)
+		if !ok {
+			continue
+		}
+
+		idx, ok := byName[field.Name]
+		if !ok {
+			idx = len(columns)
+			byName[field.Name] = idx
+			names = append(names, field.Name)
+			columns = append(columns, labelColumn{field: idx})
+		}
+
+		// keep arrays of columns with the same name ordered by precedence
+		col := &columns[idx]
+		precedence := types.ColumnTypePrecedence(ct)
+		pos, _ := slices.BinarySearch(col.precedences, precedence)
+		col.arrays = slices.Insert(col.arrays, pos, arr)
+		col.precedences = slices.Insert(col.precedences, pos, precedence)
+	}
+
+	if timestamps == nil {
+		return fmt.Errorf("column %s not found", types.ColumnNameBuiltinTimestamp)
+	}
+	if values == nil {
+		return fmt.Errorf("column %s not found", types.ColumnNameGeneratedValue)
+	}
+
+	builder := labels.NewScratchBuilder(len(columns))
+	for row := range int(record.NumRows()) {
+		// skip rows without a value
+		if timestamps.IsNull(row) || values.IsNull(row) {
+			continue
+		}
+
+		builder.Reset()
+		for _, col := range columns {
+			if value := col.value(row); value != "" {
+				builder.Add(names[col.field], value)
+			}
+		}
+		builder.Sort()
+
+		ts := timestamps.Value(row)
+		samples[ts] = append(samples[ts], binOpSample{
+			labels: builder.Labels(),
+			value:  values.Value(row),
+		})
+	}
+	return nil
+}
+
+// buildSamplesRecord returns a record with the samples of results, where
+// results[i] are the samples at timestamps[i]. The labels of the samples are
+// returned as columns of type [types.ColumnTypeLabel].
+func buildSamplesRecord(timestamps []arrow.Timestamp, results [][]binOpSample) arrow.Record {
+	var names []string
+	seen := make(map[string]struct{})
+	for _, samples := range results {
+		for _, s := range samples {
+			s.labels.Range(func(l labels.Label) {
+				if _, ok := seen[l.Name]; !ok {
+					seen[l.Name] = struct{}{}
+					names = append(names, l.Name)
+				}
+			})
+		}
+	}
+	slices.Sort(names)
+
+	fields := make([]arrow.Field, 0, len(names)+2)
+	fields = append(fields,
+		arrow.Field{
+			Name:     types.ColumnNameBuiltinTimestamp,
+			Type:     datatype.Arrow.Timestamp,
+			Nullable: false,
+			Metadata: datatype.ColumnMetadataBuiltinTimestamp,
+		},
+		arrow.Field{
+			Name:     types.ColumnNameGeneratedValue,
+			Type:     datatype.Arrow.Float,
+			Nullable: false,
+			Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Float),
+		},
+	)
+	for _, name := range names {
+		fields = append(fields, arrow.Field{
+			Name:     name,
+			Type:     datatype.Arrow.String,
+			Nullable: true,
+			Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String),
+		})
+	}
+
+	rb := array.NewRecordBuilder(memory.NewGoAllocator(), arrow.NewSchema(fields, nil))
+	defer rb.Release()
+
+	for i, samples := range results {
+		for _, s := range samples {
+			rb.Field(0).(*array.TimestampBuilder).Append(timestamps[i])
+			rb.Field(1).(*array.Float64Builder).Append(s.value)
+
+			for j, name := range names {
+				builder := rb.Field(j + 2).(*array.StringBuilder) // offset by 2 as the first 2 fields are timestamp and value
+				if value := s.labels.Get(name); value != "" {
+					builder.Append(value)
+				} else {
+					builder.AppendNull()
+				}
+			}
+		}
+	}
+
+	return rb.NewRecord()
+}
+
+// Value returns the current value in state.
+func (p *BinOpPipeline) Value() (arrow.Record, error) {
+	return p.state.Value()
+}
+
+// Close closes the resources of the pipeline.
+func (p *BinOpPipeline) Close() {
+	if p.state.batch != nil {
+		p.state.batch.Release()
+	}
+
+	for _, input := range p.inputs {
+		input.Close()
+	}
+}
+
+// Inputs returns the inputs of the pipeline.
+func (p *BinOpPipeline) Inputs() []Pipeline {
+	return p.inputs
+}
+
+// Transport returns the transport type of the pipeline.
+func (p *BinOpPipeline) Transport() Transport {
+	return Local
+}
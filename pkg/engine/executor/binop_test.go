@@ -0,0 +1,393 @@
+package executor
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/engine/planner/physical"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/util/arrowtest"
+)
+
+func TestBinOpPipeline(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: types.ColumnNameBuiltinTimestamp, Type: datatype.Arrow.Timestamp, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
+		{Name: types.ColumnNameGeneratedValue, Type: datatype.Arrow.Float, Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Float)},
+		{Name: "app", Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)},
+		{Name: "level", Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeParsed, datatype.Loki.String)},
+	}, nil)
+
+	var (
+		t1 = time.Unix(10, 0).UTC()
+		t2 = time.Unix(20, 0).UTC()
+	)
+
+	row := func(ts time.Time, value float64, lbls ...string) arrowtest.Row {
+		r := arrowtest.Row{types.ColumnNameBuiltinTimestamp: ts, types.ColumnNameGeneratedValue: value, "app": nil, "level": nil}
+		for i := 0; i < len(lbls); i += 2 {
+			r[lbls[i]] = lbls[i+1]
+		}
+		return r
+	}
+
+	scalar := func(v float64) *physical.LiteralExpr { return physical.NewLiteral(v) }
+
+	for _, tt := range []struct {
+		name        string
+		node        *physical.BinOpNode
+		left, right arrowtest.Rows // nil for the scalar operand
+		expected    arrowtest.Rows
+	}{
+		{
+			name: "vector divided by vector",
+			node: &physical.BinOpNode{Op: types.BinaryOpDiv, VectorMatching: &syntax.VectorMatching{Card: syntax.CardOneToOne}},
+			left: arrowtest.Rows{
+				row(t1, 10, "app", "a"),
+				row(t1, 20, "app", "b"),
+				row(t2, 30, "app", "a"),
+			},
+			right: arrowtest.Rows{
+				row(t1, 2, "app", "a"),
+				row(t1, 5, "app", "c"),
+				row(t2, 3, "app", "a"),
+			},
+			expected: arrowtest.Rows{
+				row(t1, 5, "app", "a"),
+				row(t2, 10, "app", "a"),
+			},
+		},
+		{
+			name: "vector compared to scalar",
+			node: &physical.BinOpNode{Op: types.BinaryOpGt, Scalar: scalar(15)},
+			left: arrowtest.Rows{
+				row(t1, 10, "app", "a"),
+				row(t1, 20, "app", "b"),
+			},
+			expected: arrowtest.Rows{
+				row(t1, 20, "app", "b"),
+			},
+		},
+		{
+			name: "vector compared to scalar with bool",
+			node: &physical.BinOpNode{Op: types.BinaryOpGt, Scalar: scalar(15), ReturnBool: true},
+			left: arrowtest.Rows{
+				row(t1, 10, "app", "a"),
+				row(t1, 20, "app", "b"),
+			},
+			expected: arrowtest.Rows{
+				row(t1, 0, "app", "a"),
+				row(t1, 1, "app", "b"),
+			},
+		},
+		{
+			name: "scalar minus vector",
+			node: &physical.BinOpNode{Op: types.BinaryOpSub, Scalar: scalar(100)},
+			right: arrowtest.Rows{
+				row(t1, 10, "app", "a"),
+				row(t2, 2, "app", "a"),
+			},
+			expected: arrowtest.Rows{
+				row(t1, 90, "app", "a"),
+				row(t2, 98, "app", "a"),
+			},
+		},
+		{
+			name: "scalar compared to vector returns value of vector",
+			node: &physical.BinOpNode{Op: types.BinaryOpLt, Scalar: scalar(15)},
+			right: arrowtest.Rows{
+				row(t1, 10, "app", "a"),
+				row(t1, 20, "app", "b"),
+			},
+			expected: arrowtest.Rows{
+				row(t1, 20, "app", "b"),
+			},
+		},
+		{
+			name: "vector compared to vector",
+			node: &physical.BinOpNode{Op: types.BinaryOpGte, VectorMatching: &syntax.VectorMatching{Card: syntax.CardOneToOne}},
+			left: arrowtest.Rows{
+				row(t1, 10, "app", "a"),
+				row(t1, 20, "app", "b"),
+			},
+			right: arrowtest.Rows{
+				row(t1, 15, "app", "a"),
+				row(t1, 15, "app", "b"),
+			},
+			expected: arrowtest.Rows{
+				row(t1, 20, "app", "b"),
+			},
+		},
+		{
+			name: "ignoring labels drops them from the result",
+			node: &physical.BinOpNode{Op: types.BinaryOpMul, VectorMatching: &syntax.VectorMatching{Card: syntax.CardOneToOne, MatchingLabels: []string{"level"}}},
+			left: arrowtest.Rows{
+				row(t1, 2, "app", "a", "level", "error"),
+			},
+			right: arrowtest.Rows{
+				row(t1, 3, "app", "a", "level", "info"),
+			},
+			expected: arrowtest.Rows{
+				row(t1, 6, "app", "a"),
+			},
+		},
+		{
+			name: "group_left includes labels of the one side",
+			node: &physical.BinOpNode{Op: types.BinaryOpDiv, VectorMatching: &syntax.VectorMatching{
+				Card:           syntax.CardManyToOne,
+				On:             true,
+				MatchingLabels: []string{"app"},
+				Include:        []string{"level"},
+			}},
+			left: arrowtest.Rows{
+				row(t1, 1, "app", "a"),
+				row(t1, 3, "app", "b"),
+			},
+			right: arrowtest.Rows{
+				row(t1, 4, "app", "a", "level", "total"),
+			},
+			expected: arrowtest.Rows{
+				row(t1, 0.25, "app", "a", "level", "total"),
+			},
+		},
+		{
+			name: "group_right keeps labels of the many side",
+			node: &physical.BinOpNode{Op: types.BinaryOpSub, VectorMatching: &syntax.VectorMatching{
+				Card:           syntax.CardOneToMany,
+				On:             true,
+				MatchingLabels: []string{"app"},
+			}},
+			left: arrowtest.Rows{
+				row(t1, 10, "app", "a"),
+			},
+			right: arrowtest.Rows{
+				row(t1, 1, "app", "a", "level", "error"),
+				row(t1, 2, "app", "a", "level", "info"),
+			},
+			expected: arrowtest.Rows{
+				row(t1, 9, "app", "a", "level", "error"),
+				row(t1, 8, "app", "a", "level", "info"),
+			},
+		},
+		{
+			name: "and",
+			node: &physical.BinOpNode{Op: types.BinaryOpAnd, VectorMatching: &syntax.VectorMatching{Card: syntax.CardOneToOne}},
+			left: arrowtest.Rows{
+				row(t1, 1, "app", "a"),
+				row(t1, 2, "app", "b"),
+				row(t2, 3, "app", "a"),
+			},
+			right: arrowtest.Rows{
+				row(t1, 10, "app", "a"),
+			},
+			expected: arrowtest.Rows{
+				row(t1, 1, "app", "a"),
+			},
+		},
+		{
+			name: "or",
+			node: &physical.BinOpNode{Op: types.BinaryOpOr, VectorMatching: &syntax.VectorMatching{Card: syntax.CardOneToOne}},
+			left: arrowtest.Rows{
+				row(t1, 1, "app", "a"),
+			},
+			right: arrowtest.Rows{
+				row(t1, 10, "app", "a"),
+				row(t1, 20, "app", "b"),
+				row(t2, 30, "app", "a"),
+			},
+			expected: arrowtest.Rows{
+				row(t1, 1, "app", "a"),
+				row(t1, 20, "app", "b"),
+				row(t2, 30, "app", "a"),
+			},
+		},
+		{
+			name: "unless",
+			node: &physical.BinOpNode{Op: types.BinaryOpUnless, VectorMatching: &syntax.VectorMatching{Card: syntax.CardOneToOne}},
+			left: arrowtest.Rows{
+				row(t1, 1, "app", "a"),
+				row(t1, 2, "app", "b"),
+				row(t2, 3, "app", "a"),
+			},
+			right: arrowtest.Rows{
+				row(t1, 10, "app", "a"),
+			},
+			expected: arrowtest.Rows{
+				row(t1, 2, "app", "b"),
+				row(t2, 3, "app", "a"),
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var left, right Pipeline
+			if tt.left != nil {
+				left = NewArrowtestPipeline(nil, schema, tt.left)
+			}
+			if tt.right != nil {
+				right = NewArrowtestPipeline(nil, schema, tt.right)
+			}
+
+			pipeline, err := NewBinOpPipeline(tt.node, left, right)
+			require.NoError(t, err)
+			defer pipeline.Close()
+
+			require.NoError(t, pipeline.Read(t.Context()))
+			record, err := pipeline.Value()
+			require.NoError(t, err)
+
+			actual, err := arrowtest.RecordRows(record)
+			require.NoError(t, err)
+			require.Equal(t, withoutNilValues(tt.expected), withoutNilValues(actual))
+
+			require.ErrorIs(t, pipeline.Read(t.Context()), EOF)
+		})
+	}
+}
+
+func TestBinOpPipeline_DivisionByZero(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: types.ColumnNameBuiltinTimestamp, Type: datatype.Arrow.Timestamp, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
+		{Name: types.ColumnNameGeneratedValue, Type: datatype.Arrow.Float, Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Float)},
+	}, nil)
+
+	input := NewArrowtestPipeline(nil, schema, arrowtest.Rows{
+		{types.ColumnNameBuiltinTimestamp: time.Unix(10, 0).UTC(), types.ColumnNameGeneratedValue: 10.0},
+	})
+
+	pipeline, err := NewBinOpPipeline(&physical.BinOpNode{Op: types.BinaryOpDiv, Scalar: physical.NewLiteral(0.0)}, input, nil)
+	require.NoError(t, err)
+	defer pipeline.Close()
+
+	require.NoError(t, pipeline.Read(t.Context()))
+	record, err := pipeline.Value()
+	require.NoError(t, err)
+
+	require.Equal(t, int64(1), record.NumRows())
+	values := record.Column(1).(*array.Float64)
+	require.True(t, math.IsNaN(values.Value(0)))
+}
+
+func TestBinOpPipeline_Errors(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: types.ColumnNameBuiltinTimestamp, Type: datatype.Arrow.Timestamp, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
+		{Name: types.ColumnNameGeneratedValue, Type: datatype.Arrow.Float, Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Float)},
+		{Name: "app", Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)},
+		{Name: "level", Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)},
+	}, nil)
+
+	ts := time.Unix(10, 0).UTC()
+	many := arrowtest.Rows{
+		{types.ColumnNameBuiltinTimestamp: ts, types.ColumnNameGeneratedValue: 1.0, "app": "a", "level": "error"},
+		{types.ColumnNameBuiltinTimestamp: ts, types.ColumnNameGeneratedValue: 2.0, "app": "a", "level": "info"},
+	}
+	one := arrowtest.Rows{
+		{types.ColumnNameBuiltinTimestamp: ts, types.ColumnNameGeneratedValue: 3.0, "app": "a", "level": nil},
+	}
+	onApp := []string{"app"}
+
+	for _, tt := range []struct {
+		name        string
+		matching    *syntax.VectorMatching
+		left, right arrowtest.Rows
+		expected    string
+	}{
+		{
+			name:     "many-to-one without group_left",
+			matching: &syntax.VectorMatching{Card: syntax.CardOneToOne, On: true, MatchingLabels: onApp},
+			left:     many,
+			right:    one,
+			expected: "many-to-one matching must be explicit (group_left/group_right)",
+		},
+		{
+			name:     "duplicate series on the one side",
+			matching: &syntax.VectorMatching{Card: syntax.CardManyToOne, On: true, MatchingLabels: onApp},
+			left:     one,
+			right:    many,
+			expected: "found duplicate series on the right hand-side",
+		},
+		{
+			name:     "duplicate series on the one side of group_right",
+			matching: &syntax.VectorMatching{Card: syntax.CardOneToMany, On: true, MatchingLabels: onApp},
+			left:     many,
+			right:    one,
+			expected: "found duplicate series on the left hand-side",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := NewBinOpPipeline(
+				&physical.BinOpNode{Op: types.BinaryOpAdd, VectorMatching: tt.matching},
+				NewArrowtestPipeline(nil, schema, tt.left),
+				NewArrowtestPipeline(nil, schema, tt.right),
+			)
+			require.NoError(t, err)
+			defer pipeline.Close()
+
+			err = pipeline.Read(t.Context())
+			require.ErrorContains(t, err, tt.expected)
+			require.False(t, errors.Is(err, EOF))
+		})
+	}
+}
+
+func TestVectorLiteralPipeline(t *testing.T) {
+	var (
+		start = time.Unix(10, 0).UTC()
+		end   = time.Unix(30, 0).UTC()
+	)
+
+	for _, tt := range []struct {
+		name     string
+		step     time.Duration
+		expected []time.Time
+	}{
+		{
+			name:     "range query",
+			step:     10 * time.Second,
+			expected: []time.Time{start, start.Add(10 * time.Second), end},
+		},
+		{
+			name:     "instant query",
+			expected: []time.Time{end},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := NewVectorLiteralPipeline(&physical.VectorLiteral{Value: 1, Start: start, End: end, Step: tt.step})
+			defer pipeline.Close()
+
+			require.NoError(t, pipeline.Read(t.Context()))
+			record, err := pipeline.Value()
+			require.NoError(t, err)
+
+			actual, err := arrowtest.RecordRows(record)
+			require.NoError(t, err)
+
+			expected := make(arrowtest.Rows, len(tt.expected))
+			for i, ts := range tt.expected {
+				expected[i] = arrowtest.Row{types.ColumnNameBuiltinTimestamp: ts, types.ColumnNameGeneratedValue: 1.0}
+			}
+			require.Equal(t, expected, actual)
+
+			require.ErrorIs(t, pipeline.Read(t.Context()), EOF)
+		})
+	}
+}
+
+// withoutNilValues removes the columns of rows that are null, so that rows
+// can be compared independently of the columns of other rows.
+func withoutNilValues(rows arrowtest.Rows) arrowtest.Rows {
+	for _, row := range rows {
+		for k, v := range row {
+			if v == nil {
+				delete(row, k)
+			}
+		}
+	}
+	return rows
+}
@@ -81,6 +81,14 @@ func (c *Context) execute(ctx context.Context, node physical.Node) Pipeline {
 		return tracePipeline("physical.RangeAggregation", c.executeRangeAggregation(ctx, n, inputs))
 	case *physical.VectorAggregation:
 		return tracePipeline("physical.VectorAggregation", c.executeVectorAggregation(ctx, n, inputs))
+	case *physical.ParseNode:
+		return tracePipeline("physical.ParseNode", c.executeParse(ctx, n, inputs))
+	case *physical.BinOpNode:
+		return tracePipeline("physical.BinOpNode", c.executeBinOp(ctx, n, inputs))
+	case *physical.VectorLiteral:
+		return tracePipeline("physical.VectorLiteral", NewVectorLiteralPipeline(n))
+	case *physical.Absent:
+		return tracePipeline("physical.Absent", NewAbsentPipeline(n, inputs...))
 	default:
 		return errorPipeline(ctx, fmt.Errorf("invalid node type: %T", node))
 	}
@@ -296,6 +304,7 @@ func (c *Context) executeFilter(ctx context.Context, filter *physical.Filter, in
 
 func (c *Context) executeProjection(ctx context.Context, proj *physical.Projection, inputs []Pipeline) Pipeline {
 	ctx, span := tracer.Start(ctx, "Context.executeProjection", trace.WithAttributes(
+		attribute.String("mode", proj.Mode.String()),
 		attribute.Int("num_columns", len(proj.Columns)),
 		attribute.Int("num_inputs", len(inputs)),
 	))
@@ -314,19 +323,59 @@ func (c *Context) executeProjection(ctx context.Context, proj *physical.Projecti
 		return errorPipeline(ctx, fmt.Errorf("projection expects at least one column, got 0"))
 	}
 
-	p, err := NewProjectPipeline(inputs[0], proj.Columns, &c.evaluator)
+	var (
+		p   Pipeline
+		err error
+	)
+	switch proj.Mode {
+	case physical.ProjectionModeSelect:
+		p, err = NewProjectPipeline(inputs[0], proj.Columns, &c.evaluator)
+	case physical.ProjectionModeExpand:
+		p, err = NewExpandPipeline(inputs[0], proj.Columns, &c.evaluator)
+	case physical.ProjectionModeKeep:
+		p, err = NewKeepPipeline(inputs[0], proj.Columns, &c.evaluator)
+	case physical.ProjectionModeDrop:
+		p, err = NewDropPipeline(inputs[0], proj.Columns, &c.evaluator)
+	default:
+		err = fmt.Errorf("unsupported projection mode %s", proj.Mode)
+	}
 	if err != nil {
 		return errorPipeline(ctx, err)
 	}
 	return p
 }
 
+func (c *Context) executeParse(ctx context.Context, parse *physical.ParseNode, inputs []Pipeline) Pipeline {
+	ctx, span := tracer.Start(ctx, "Context.executeParse", trace.WithAttributes(
+		attribute.Stringer("kind", parse.Kind),
+		attribute.Int("num_expressions", len(parse.Expressions)),
+		attribute.Int("num_inputs", len(inputs)),
+	))
+	defer span.End()
+
+	if len(inputs) == 0 {
+		return emptyPipeline()
+	}
+
+	if len(inputs) > 1 {
+		return errorPipeline(ctx, fmt.Errorf("parse expects exactly one input, got %d", len(inputs)))
+	}
+
+	pipeline, err := NewParsePipeline(parse, inputs[0])
+	if err != nil {
+		return errorPipeline(ctx, err)
+	}
+	return pipeline
+}
+
 func (c *Context) executeRangeAggregation(ctx context.Context, plan *physical.RangeAggregation, inputs []Pipeline) Pipeline {
 	ctx, span := tracer.Start(ctx, "Context.executeRangeAggregation", trace.WithAttributes(
+		attribute.Stringer("operation", plan.Operation),
 		attribute.Int("num_partition_by", len(plan.PartitionBy)),
 		attribute.Int64("start_ts", plan.Start.UnixNano()),
 		attribute.Int64("end_ts", plan.End.UnixNano()),
 		attribute.Int64("range_interval", int64(plan.Range)),
+		attribute.Int64("offset", int64(plan.Offset)),
 		attribute.Int64("step", int64(plan.Step)),
 		attribute.Int("num_inputs", len(inputs)),
 	))
@@ -338,9 +387,13 @@ func (c *Context) executeRangeAggregation(ctx context.Context, plan *physical.Ra
 
 	pipeline, err := NewRangeAggregationPipeline(inputs, c.evaluator, rangeAggregationOptions{
 		partitionBy:   plan.PartitionBy,
+		operation:     plan.Operation,
+		value:         plan.Value,
+		parameter:     plan.Parameter,
 		startTs:       plan.Start,
 		endTs:         plan.End,
 		rangeInterval: plan.Range,
+		offset:        plan.Offset,
 		step:          plan.Step,
 	})
 	if err != nil {
@@ -350,9 +403,39 @@ func (c *Context) executeRangeAggregation(ctx context.Context, plan *physical.Ra
 	return pipeline
 }
 
+func (c *Context) executeBinOp(ctx context.Context, node *physical.BinOpNode, inputs []Pipeline) Pipeline {
+	ctx, span := tracer.Start(ctx, "Context.executeBinOp", trace.WithAttributes(
+		attribute.Stringer("operation", node.Op),
+		attribute.Bool("return_bool", node.ReturnBool),
+		attribute.Int("num_inputs", len(inputs)),
+	))
+	defer span.End()
+
+	// The children of a node are not ordered, so the operands are looked up
+	// by the nodes they were built from.
+	var left, right Pipeline
+	for i, child := range c.plan.Children(node) {
+		switch child {
+		case node.Left:
+			left = inputs[i]
+		case node.Right:
+			right = inputs[i]
+		}
+	}
+
+	pipeline, err := NewBinOpPipeline(node, left, right)
+	if err != nil {
+		return errorPipeline(ctx, err)
+	}
+
+	return pipeline
+}
+
 func (c *Context) executeVectorAggregation(ctx context.Context, plan *physical.VectorAggregation, inputs []Pipeline) Pipeline {
 	ctx, span := tracer.Start(ctx, "Context.executeVectorAggregation", trace.WithAttributes(
+		attribute.Stringer("operation", plan.Operation),
 		attribute.Int("num_group_by", len(plan.GroupBy)),
+		attribute.Bool("without", plan.Without),
 		attribute.Int("num_inputs", len(inputs)),
 	))
 	defer span.End()
@@ -361,7 +444,12 @@ func (c *Context) executeVectorAggregation(ctx context.Context, plan *physical.V
 		return emptyPipeline()
 	}
 
-	pipeline, err := NewVectorAggregationPipeline(inputs, plan.GroupBy, c.evaluator)
+	pipeline, err := NewVectorAggregationPipeline(inputs, c.evaluator, vectorAggregationOptions{
+		groupBy:   plan.GroupBy,
+		without:   plan.Without,
+		operation: plan.Operation,
+		parameter: plan.Parameter,
+	})
 	if err != nil {
 		return errorPipeline(ctx, err)
 	}
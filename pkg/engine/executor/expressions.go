@@ -2,15 +2,19 @@ package executor
 
 import (
 	"fmt"
+	"regexp"
 	"slices"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/prometheus/prometheus/model/labels"
 
 	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
 	"github.com/grafana/loki/v3/pkg/engine/internal/types"
 	"github.com/grafana/loki/v3/pkg/engine/planner/physical"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
 )
 
 type expressionEvaluator struct{}
@@ -135,11 +139,289 @@ func (e expressionEvaluator) eval(expr physical.Expression, input arrow.Record)
 			return nil, fmt.Errorf("failed to lookup binary function for signature %v(%v,%v): %w", expr.Op, lhs.Type().ArrowType(), rhs.Type().ArrowType(), err)
 		}
 		return fn.Evaluate(lhs, rhs)
+
+	case *physical.TemplateExpr:
+		res, err := e.evalTemplate(expr, input)
+		if err != nil {
+			return nil, err
+		}
+		res.Errors.Release()
+		res.ErrorDetails.Release()
+		return &Array{
+			array: res.Values,
+			dt:    datatype.Loki.String,
+			ct:    types.ColumnTypeGenerated,
+			rows:  input.NumRows(),
+		}, nil
+
+	case *physical.RegexpReplaceExpr:
+		return e.evalRegexpReplace(expr, input)
 	}
 
 	return nil, fmt.Errorf("unknown expression: %v", expr)
 }
 
+// evalRegexpReplace matches the value of expr for each row of input against
+// the anchored regular expression of expr. Rows that match are replaced with
+// the replacement of expr, where capture groups are expanded like in
+// [regexp.Regexp.Expand], rows that do not match are null. Null values are
+// matched as empty strings, like labels that are not set in the legacy engine.
+func (e expressionEvaluator) evalRegexpReplace(expr *physical.RegexpReplaceExpr, input arrow.Record) (ColumnVector, error) {
+	re, err := regexp.Compile("^(?:" + expr.Regex + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression %q: %w", expr.Regex, err)
+	}
+
+	vec, err := e.eval(expr.Value, input)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := array.NewStringBuilder(memory.NewGoAllocator())
+	defer builder.Release()
+
+	var buf []byte
+	for i := range int(input.NumRows()) {
+		value, _ := vec.Value(i).(string)
+
+		indexes := re.FindStringSubmatchIndex(value)
+		if indexes == nil {
+			builder.AppendNull()
+			continue
+		}
+		buf = re.ExpandString(buf[:0], expr.Replacement, value, indexes)
+		builder.Append(string(buf))
+	}
+
+	return &Array{
+		array: builder.NewArray(),
+		dt:    datatype.Loki.String,
+		ct:    types.ColumnTypeGenerated,
+		rows:  input.NumRows(),
+	}, nil
+}
+
+// evalCast converts the values of the column of expr, which must be a cast
+// operation, and returns the converted values and the conversion errors.
+func (e expressionEvaluator) evalCast(expr *physical.UnaryExpr, input arrow.Record) (castResult, error) {
+	lhr, err := e.eval(expr.Left, input)
+	if err != nil {
+		return castResult{}, err
+	}
+
+	fn, err := unaryFunctions.GetForSignature(expr.Op, lhr.Type().ArrowType())
+	if err != nil {
+		return castResult{}, fmt.Errorf("failed to lookup unary function: %w", err)
+	}
+	cast, ok := fn.(*castFunction)
+	if !ok {
+		return castResult{}, fmt.Errorf("unary function %s is not a cast", expr.Op)
+	}
+	return cast.cast(lhr)
+}
+
+// templateResult is the result of evaluating a [physical.TemplateExpr].
+type templateResult struct {
+	// Values are the formatted values. A row is null if the template could
+	// not be executed for the row and the formatted label should keep its
+	// value.
+	Values arrow.Array
+	// Errors and ErrorDetails contain the error and its details of rows for
+	// which the template could not be executed, and null otherwise.
+	Errors, ErrorDetails arrow.Array
+}
+
+// evalTemplate executes the text template of expr for each row of input.
+//
+// The template is executed with the formatters of the [log] package and the
+// values of the label, metadata and parsed columns of the row, so the result
+// is the same as with the line_format and label_format stages of the LogQL
+// engine. This includes that metadata columns which conflict with a label
+// column are available with the suffix "_extracted".
+//
+// If the template cannot be executed for a row, the row keeps its log line,
+// or its label is not formatted, and the error is reported in Errors and
+// ErrorDetails.
+func (e expressionEvaluator) evalTemplate(expr *physical.TemplateExpr, input arrow.Record) (templateResult, error) {
+	var stage log.Stage
+	if expr.Label == "" {
+		formatter, err := log.NewFormatter(expr.Template)
+		if err != nil {
+			return templateResult{}, err
+		}
+		stage = formatter
+	} else {
+		formatter, err := log.NewLabelsFormatter([]log.LabelFmt{log.NewTemplateLabelFmt(expr.Label, expr.Template)})
+		if err != nil {
+			return templateResult{}, err
+		}
+		stage = formatter
+	}
+
+	mem := memory.NewGoAllocator()
+	var (
+		values  = array.NewStringBuilder(mem)
+		errs    = array.NewStringBuilder(mem)
+		details = array.NewStringBuilder(mem)
+	)
+	defer values.Release()
+	defer errs.Release()
+	defer details.Release()
+
+	rows := newRowLabels(input)
+	for i := range int(input.NumRows()) {
+		line, ok := rows.Line(i)
+		if !ok {
+			values.AppendNull()
+			errs.AppendNull()
+			details.AppendNull()
+			continue
+		}
+
+		lbs := rows.LabelsBuilder(i)
+		prevErr, prevDetails := lbs.GetErr(), lbs.GetErrorDetails()
+
+		formatted, _ := stage.Process(rows.Timestamp(i), []byte(line), lbs)
+
+		if lbs.GetErr() != prevErr || lbs.GetErrorDetails() != prevDetails {
+			if expr.Label == "" {
+				values.Append(line)
+			} else {
+				values.AppendNull()
+			}
+			errs.Append(lbs.GetErr())
+			details.Append(lbs.GetErrorDetails())
+			continue
+		}
+
+		if expr.Label == "" {
+			values.Append(string(formatted))
+		} else {
+			value, _ := lbs.Get(expr.Label)
+			values.Append(value)
+		}
+		errs.AppendNull()
+		details.AppendNull()
+	}
+
+	return templateResult{
+		Values:       values.NewArray(),
+		Errors:       errs.NewArray(),
+		ErrorDetails: details.NewArray(),
+	}, nil
+}
+
+// rowLabels provides the log line, timestamp and labels of the rows of a
+// record in the representation of the [log] package.
+type rowLabels struct {
+	builder *log.BaseLabelsBuilder
+
+	messages   *array.String
+	timestamps *array.Timestamp
+
+	labels, metadata, parsed []int // Indices of the string columns per column type.
+	names                    []string
+	columns                  []*array.String
+
+	scratch labels.ScratchBuilder
+}
+
+func newRowLabels(rec arrow.Record) *rowLabels {
+	r := &rowLabels{
+		builder: log.NewBaseLabelsBuilder(),
+		names:   make([]string, rec.NumCols()),
+		columns: make([]*array.String, rec.NumCols()),
+	}
+
+	for i, field := range rec.Schema().Fields() {
+		ct, ok := field.Metadata.GetValue(types.MetadataKeyColumnType)
+		if !ok {
+			continue
+		}
+
+		switch col := rec.Column(i).(type) {
+		case *array.String:
+			r.names[i] = field.Name
+			r.columns[i] = col
+
+			switch ct {
+			case types.ColumnTypeBuiltin.String():
+				if field.Name == types.ColumnNameBuiltinMessage {
+					r.messages = col
+				}
+			case types.ColumnTypeLabel.String():
+				r.labels = append(r.labels, i)
+			case types.ColumnTypeMetadata.String():
+				r.metadata = append(r.metadata, i)
+			case types.ColumnTypeParsed.String():
+				r.parsed = append(r.parsed, i)
+			}
+		case *array.Timestamp:
+			if ct == types.ColumnTypeBuiltin.String() && field.Name == types.ColumnNameBuiltinTimestamp {
+				r.timestamps = col
+			}
+		}
+	}
+	return r
+}
+
+// Line returns the log line of row i and whether it exists.
+func (r *rowLabels) Line(i int) (string, bool) {
+	if r.messages == nil || r.messages.IsNull(i) {
+		return "", false
+	}
+	return r.messages.Value(i), true
+}
+
+// Timestamp returns the timestamp of row i, or zero if it does not exist.
+func (r *rowLabels) Timestamp(i int) int64 {
+	if r.timestamps == nil || r.timestamps.IsNull(i) {
+		return 0
+	}
+	return int64(r.timestamps.Value(i))
+}
+
+// LabelsBuilder returns a [log.LabelsBuilder] with the labels of row i. Label
+// columns are the stream labels of the builder, metadata columns its
+// structured metadata and parsed columns its parsed labels.
+func (r *rowLabels) LabelsBuilder(i int) *log.LabelsBuilder {
+	base := r.collect(r.labels, i)
+	lbs := r.builder.ForLabels(base, base.Hash())
+	lbs.Reset()
+	lbs.Add(log.StructuredMetadataLabel, r.collect(r.metadata, i))
+
+	for _, idx := range r.parsed {
+		col := r.columns[idx]
+		if col.IsNull(i) {
+			continue
+		}
+
+		switch name := r.names[idx]; name {
+		case logqlmodel.ErrorLabel:
+			lbs.SetErr(col.Value(i))
+		case logqlmodel.ErrorDetailsLabel:
+			lbs.SetErrorDetails(col.Value(i))
+		default:
+			lbs.Set(log.ParsedLabel, name, col.Value(i))
+		}
+	}
+	return lbs
+}
+
+// collect returns the non-empty values of the columns idxs of row i as
+// labels.
+func (r *rowLabels) collect(idxs []int, i int) labels.Labels {
+	r.scratch.Reset()
+	for _, idx := range idxs {
+		col := r.columns[idx]
+		if col.IsValid(i) && col.Value(i) != "" {
+			r.scratch.Add(r.names[idx], col.Value(i))
+		}
+	}
+	r.scratch.Sort()
+	return r.scratch.Labels()
+}
+
 // newFunc returns a new function that can evaluate an input against a binded expression.
 func (e expressionEvaluator) newFunc(expr physical.Expression) evalFunc {
 	return func(input arrow.Record) (ColumnVector, error) {
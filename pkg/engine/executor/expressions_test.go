@@ -384,3 +384,59 @@ label_2
 		require.IsType(t, &Scalar{}, colVec)
 	})
 }
+
+func TestEvaluateRegexpReplaceExpression(t *testing.T) {
+	fields := []arrow.Field{
+		{Name: "pod", Type: arrow.BinaryTypes.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)},
+	}
+	data := `api-0
+frontend
+null`
+
+	record, err := CSVToArrow(fields, data)
+	require.NoError(t, err)
+	defer record.Release()
+
+	e := expressionEvaluator{}
+
+	t.Run("replacement of matching values", func(t *testing.T) {
+		expr := &physical.RegexpReplaceExpr{
+			Value:       &physical.ColumnExpr{Ref: types.ColumnRef{Column: "pod", Type: types.ColumnTypeAmbiguous}},
+			Regex:       `(\w+)-\d+`,
+			Replacement: "$1",
+		}
+
+		colVec, err := e.eval(expr, record)
+		require.NoError(t, err)
+		require.Equal(t, types.ColumnTypeGenerated, colVec.ColumnType())
+		require.Equal(t, arrow.STRING, colVec.Type().ArrowType().ID())
+
+		require.Equal(t, "api", colVec.Value(0))
+		require.Equal(t, nil, colVec.Value(1)) // Regex is anchored and does not match
+		require.Equal(t, nil, colVec.Value(2))
+	})
+
+	t.Run("null values are matched as empty string", func(t *testing.T) {
+		expr := &physical.RegexpReplaceExpr{
+			Value:       &physical.ColumnExpr{Ref: types.ColumnRef{Column: "does_not_exist", Type: types.ColumnTypeAmbiguous}},
+			Regex:       `(.*)`,
+			Replacement: "unknown$1",
+		}
+
+		colVec, err := e.eval(expr, record)
+		require.NoError(t, err)
+		for i := range int(record.NumRows()) {
+			require.Equal(t, "unknown", colVec.Value(i))
+		}
+	})
+
+	t.Run("invalid regex", func(t *testing.T) {
+		expr := &physical.RegexpReplaceExpr{
+			Value: &physical.ColumnExpr{Ref: types.ColumnRef{Column: "pod", Type: types.ColumnTypeAmbiguous}},
+			Regex: `(`,
+		}
+
+		_, err := e.eval(expr, record)
+		require.Error(t, err)
+	})
+}
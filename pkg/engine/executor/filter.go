@@ -94,6 +94,10 @@ func filterBatch(batch arrow.Record, include func(int) bool) arrow.Record {
 			builders[i] = builder
 			additions[i] = func(offset int) {
 				src := batch.Column(i).(*array.Boolean)
+				if src.IsNull(offset) {
+					builder.AppendNull()
+					return
+				}
 				builder.Append(src.Value(offset))
 			}
 
@@ -102,6 +106,10 @@ func filterBatch(batch arrow.Record, include func(int) bool) arrow.Record {
 			builders[i] = builder
 			additions[i] = func(offset int) {
 				src := batch.Column(i).(*array.String)
+				if src.IsNull(offset) {
+					builder.AppendNull()
+					return
+				}
 				builder.Append(src.Value(offset))
 			}
 
@@ -110,6 +118,10 @@ func filterBatch(batch arrow.Record, include func(int) bool) arrow.Record {
 			builders[i] = builder
 			additions[i] = func(offset int) {
 				src := batch.Column(i).(*array.Uint64)
+				if src.IsNull(offset) {
+					builder.AppendNull()
+					return
+				}
 				builder.Append(src.Value(offset))
 			}
 
@@ -118,6 +130,10 @@ func filterBatch(batch arrow.Record, include func(int) bool) arrow.Record {
 			builders[i] = builder
 			additions[i] = func(offset int) {
 				src := batch.Column(i).(*array.Int64)
+				if src.IsNull(offset) {
+					builder.AppendNull()
+					return
+				}
 				builder.Append(src.Value(offset))
 			}
 
@@ -126,6 +142,10 @@ func filterBatch(batch arrow.Record, include func(int) bool) arrow.Record {
 			builders[i] = builder
 			additions[i] = func(offset int) {
 				src := batch.Column(i).(*array.Float64)
+				if src.IsNull(offset) {
+					builder.AppendNull()
+					return
+				}
 				builder.Append(src.Value(offset))
 			}
 
@@ -134,6 +154,10 @@ func filterBatch(batch arrow.Record, include func(int) bool) arrow.Record {
 			builders[i] = builder
 			additions[i] = func(offset int) {
 				src := batch.Column(i).(*array.Timestamp)
+				if src.IsNull(offset) {
+					builder.AppendNull()
+					return
+				}
 				builder.Append(src.Value(offset))
 			}
 
@@ -271,4 +271,31 @@ Charlie,true
 		// Assert that the pipelines produce equal results
 		AssertPipelinesEqual(t, filterPipeline, expectedPipeline)
 	})
+
+	t.Run("filter preserves null values", func(t *testing.T) {
+		// Empty values are read as null
+		inputCSV := "Alice,true\n,true\nBob,false"
+		inputRecord, err := CSVToArrow(fields, inputCSV)
+		require.NoError(t, err)
+		defer inputRecord.Release()
+
+		inputPipeline := NewBufferedPipeline(inputRecord)
+
+		filter := &physical.Filter{
+			Predicates: []physical.Expression{
+				&physical.ColumnExpr{Ref: createColumnRef("valid")},
+			},
+		}
+
+		filterPipeline := NewFilterPipeline(filter, inputPipeline, expressionEvaluator{})
+		defer filterPipeline.Close()
+
+		require.NoError(t, filterPipeline.Read(t.Context()))
+		record, err := filterPipeline.Value()
+		require.NoError(t, err)
+
+		require.Equal(t, int64(2), record.NumRows())
+		require.False(t, record.Column(0).IsNull(0))
+		require.True(t, record.Column(0).IsNull(1))
+	})
 }
@@ -2,12 +2,16 @@ package executor
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/dustin/go-humanize"
 
+	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
 	"github.com/grafana/loki/v3/pkg/engine/internal/errors"
 	"github.com/grafana/loki/v3/pkg/engine/internal/types"
 )
@@ -18,6 +22,21 @@ var (
 )
 
 func init() {
+	// Functions for [types.UnaryOpLength]
+	unaryFunctions.register(types.UnaryOpLength, arrow.BinaryTypes.String, &stringLengthFunction{})
+	// Functions for [types.UnaryOpCastFloat]
+	unaryFunctions.register(types.UnaryOpCastFloat, arrow.BinaryTypes.String, &castFunction{conv: func(v string) (float64, error) { return strconv.ParseFloat(v, 64) }})
+	// Functions for [types.UnaryOpCastBytes]
+	unaryFunctions.register(types.UnaryOpCastBytes, arrow.BinaryTypes.String, &castFunction{conv: func(v string) (float64, error) {
+		b, err := humanize.ParseBytes(v)
+		return float64(b), err
+	}})
+	// Functions for [types.UnaryOpCastDuration]
+	unaryFunctions.register(types.UnaryOpCastDuration, arrow.BinaryTypes.String, &castFunction{conv: func(v string) (float64, error) {
+		d, err := time.ParseDuration(v)
+		return d.Seconds(), err
+	}})
+
 	// Functions for [types.BinaryOpEq]
 	binaryFunctions.register(types.BinaryOpEq, arrow.FixedWidthTypes.Boolean, &genericFunction[*array.Boolean, bool]{eval: func(a, b bool) (bool, error) { return a == b, nil }})
 	binaryFunctions.register(types.BinaryOpEq, arrow.BinaryTypes.String, &genericFunction[*array.String, string]{eval: func(a, b string) (bool, error) { return a == b, nil }})
@@ -106,8 +125,136 @@ func (u *unaryFuncReg) register(op types.UnaryOp, ltype arrow.DataType, f UnaryF
 }
 
 // GetForSignature implements UnaryFunctionRegistry.
-func (u *unaryFuncReg) GetForSignature(types.UnaryOp, arrow.DataType) (UnaryFunction, error) {
-	return nil, errors.ErrNotImplemented
+func (u *unaryFuncReg) GetForSignature(op types.UnaryOp, ltype arrow.DataType) (UnaryFunction, error) {
+	// Get registered functions for the specific operation
+	reg, ok := u.reg[op]
+	if !ok {
+		return nil, errors.ErrNotImplemented
+	}
+	// Get registered function for the specific data type
+	fn, ok := reg[ltype]
+	if !ok {
+		return nil, errors.ErrNotImplemented
+	}
+	return fn, nil
+}
+
+// stringLengthFunction is a [UnaryFunction] that returns the length in bytes
+// of each value of a string column.
+type stringLengthFunction struct{}
+
+// Evaluate implements UnaryFunction.
+func (f *stringLengthFunction) Evaluate(lhs ColumnVector) (ColumnVector, error) {
+	arr, ok := lhs.ToArray().(*array.String)
+	if !ok {
+		return nil, arrow.ErrType
+	}
+
+	mem := memory.NewGoAllocator()
+	builder := array.NewInt64Builder(mem)
+	defer builder.Release()
+
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			builder.AppendNull()
+			continue
+		}
+		builder.Append(int64(len(arr.Value(i))))
+	}
+
+	return &Array{
+		array: builder.NewArray(),
+		dt:    datatype.Loki.Integer,
+		ct:    types.ColumnTypeGenerated,
+		rows:  int64(arr.Len()),
+	}, nil
+}
+
+// castFunction is a [UnaryFunction] that converts the values of a string
+// column into floats using conv.
+//
+// Empty values and values that cannot be converted result in NULL, so that
+// they are ignored by aggregations. This mirrors the behaviour of unwrap in
+// the legacy engine, which skips samples with an empty value. The errors of
+// values that cannot be converted are only available via cast.
+type castFunction struct {
+	conv func(string) (float64, error)
+}
+
+// Evaluate implements UnaryFunction.
+func (f *castFunction) Evaluate(lhs ColumnVector) (ColumnVector, error) {
+	res, err := f.cast(lhs)
+	if err != nil {
+		return nil, err
+	}
+	res.Errors.Release()
+	res.ErrorDetails.Release()
+
+	return &Array{
+		array: res.Values,
+		dt:    datatype.Loki.Float,
+		ct:    types.ColumnTypeGenerated,
+		rows:  int64(res.Values.Len()),
+	}, nil
+}
+
+// errSampleExtraction is the error of values that cannot be converted. It
+// matches the error of unwrap in the [log] package.
+const errSampleExtraction = "SampleExtractionErr"
+
+// castResult is the result of converting a column with a [castFunction].
+type castResult struct {
+	// Values are the converted values. A row is null if the value is empty
+	// or cannot be converted.
+	Values arrow.Array
+	// Errors and ErrorDetails contain the error and its details of rows whose
+	// value cannot be converted, and null otherwise.
+	Errors, ErrorDetails arrow.Array
+}
+
+// cast converts the values of lhs. Errors are reported as
+// SampleExtractionErr, like the errors of unwrap in the legacy engine.
+func (f *castFunction) cast(lhs ColumnVector) (castResult, error) {
+	arr, ok := lhs.ToArray().(*array.String)
+	if !ok {
+		return castResult{}, arrow.ErrType
+	}
+
+	mem := memory.NewGoAllocator()
+	var (
+		values  = array.NewFloat64Builder(mem)
+		errs    = array.NewStringBuilder(mem)
+		details = array.NewStringBuilder(mem)
+	)
+	defer values.Release()
+	defer errs.Release()
+	defer details.Release()
+
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) || arr.Value(i) == "" {
+			values.AppendNull()
+			errs.AppendNull()
+			details.AppendNull()
+			continue
+		}
+
+		v, err := f.conv(arr.Value(i))
+		if err != nil {
+			values.AppendNull()
+			errs.Append(errSampleExtraction)
+			details.Append(err.Error())
+			continue
+		}
+		values.Append(v)
+		errs.AppendNull()
+		details.AppendNull()
+	}
+
+	return castResult{
+		Values:       values.NewArray(),
+		Errors:       errs.NewArray(),
+		ErrorDetails: details.NewArray(),
+	}, nil
 }
 
 type BinaryFunctionRegistry interface {
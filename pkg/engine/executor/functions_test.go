@@ -837,3 +837,127 @@ func TestEmptyArrays(t *testing.T) {
 
 	assert.Equal(t, int64(0), result.Len())
 }
+
+func TestUnaryFunctionRegistry_GetForSignature(t *testing.T) {
+	tests := []struct {
+		name     string
+		op       types.UnaryOp
+		dataType arrow.DataType
+		wantErr  bool
+	}{
+		{
+			name:     "valid length operation",
+			op:       types.UnaryOpLength,
+			dataType: arrow.BinaryTypes.String,
+			wantErr:  false,
+		},
+		{
+			name:     "valid cast operation",
+			op:       types.UnaryOpCastDuration,
+			dataType: arrow.BinaryTypes.String,
+			wantErr:  false,
+		},
+		{
+			name:     "invalid operation",
+			op:       types.UnaryOpNot,
+			dataType: arrow.BinaryTypes.String,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid data type for operation",
+			op:       types.UnaryOpCastFloat,
+			dataType: arrow.PrimitiveTypes.Int64,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn, err := unaryFunctions.GetForSignature(tt.op, tt.dataType)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Nil(t, fn)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, fn)
+			}
+		})
+	}
+}
+
+func TestCastFunctions(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	tests := []struct {
+		name     string
+		op       types.UnaryOp
+		input    []string
+		expected []float64
+		nulls    []bool
+	}{
+		{
+			name:     "float",
+			op:       types.UnaryOpCastFloat,
+			input:    []string{"1.5", "", "foo", "-2"},
+			expected: []float64{1.5, 0, 0, -2},
+			nulls:    []bool{false, true, true, false},
+		},
+		{
+			name:     "bytes",
+			op:       types.UnaryOpCastBytes,
+			input:    []string{"1KB", "1KiB", "42"},
+			expected: []float64{1000, 1024, 42},
+			nulls:    []bool{false, false, false},
+		},
+		{
+			name:     "duration",
+			op:       types.UnaryOpCastDuration,
+			input:    []string{"1m", "250ms", "1"},
+			expected: []float64{60, 0.25, 0},
+			nulls:    []bool{false, false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := createStringArray(mem, tt.input, nil)
+			defer input.array.Release()
+
+			fn, err := unaryFunctions.GetForSignature(tt.op, arrow.BinaryTypes.String)
+			require.NoError(t, err)
+
+			result, err := fn.Evaluate(input)
+			require.NoError(t, err)
+
+			arr := result.ToArray().(*array.Float64)
+			defer arr.Release()
+			for i := range tt.expected {
+				assert.Equal(t, tt.nulls[i], arr.IsNull(i), "null at index %d", i)
+				if !tt.nulls[i] {
+					assert.Equal(t, tt.expected[i], arr.Value(i), "value at index %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestStringLengthFunction(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	input := createStringArray(mem, []string{"foo", "", "bar baz"}, []bool{false, false, true})
+	defer input.array.Release()
+
+	fn, err := unaryFunctions.GetForSignature(types.UnaryOpLength, arrow.BinaryTypes.String)
+	require.NoError(t, err)
+
+	result, err := fn.Evaluate(input)
+	require.NoError(t, err)
+
+	arr := result.ToArray().(*array.Int64)
+	defer arr.Release()
+	assert.Equal(t, int64(3), arr.Value(0))
+	assert.Equal(t, int64(0), arr.Value(1))
+	assert.True(t, arr.IsNull(2))
+}
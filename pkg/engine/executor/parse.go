@@ -0,0 +1,322 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/engine/planner/physical"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+)
+
+// NewParsePipeline returns a pipeline that parses the log line of each row of
+// its input and appends the extracted values as columns of type
+// [types.ColumnTypeParsed].
+//
+// Values are extracted with the parsers of the [log] package, so the names of
+// the extracted columns are the same as the names of the labels extracted by
+// the LogQL engine: values that conflict with a stream label of the row get the
+// suffix "_extracted", and parser errors are reported in the __error__ and
+// __error_details__ columns.
+//
+// Rows without a value for an extracted column are null. If the input already
+// contains a parsed column with the same name, for example from a previous
+// parser, the columns are merged and the new value takes precedence.
+func NewParsePipeline(node *physical.ParseNode, input Pipeline) (*GenericPipeline, error) {
+	stage, err := newParserStage(node)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &lineParser{
+		stage:        stage,
+		modifiesLine: node.Kind.ModifiesLine(),
+		builder:      log.NewBaseLabelsBuilder(),
+	}
+
+	return newGenericPipeline(Local, func(ctx context.Context, inputs []Pipeline) state {
+		// Pull the next item from the input pipeline
+		input := inputs[0]
+		err := input.Read(ctx)
+		if err != nil {
+			return failureState(err)
+		}
+
+		batch, err := input.Value()
+		if err != nil {
+			return failureState(err)
+		}
+
+		parsed, err := p.parse(batch)
+		if err != nil {
+			return failureState(err)
+		}
+		batch.Release()
+		return successState(parsed)
+	}, input), nil
+}
+
+// newParserStage creates the [log.Stage] that extracts the values of the
+// parser described by node.
+func newParserStage(node *physical.ParseNode) (log.Stage, error) {
+	switch node.Kind {
+	case types.ParserKindJSON:
+		if len(node.Expressions) > 0 {
+			return log.NewJSONExpressionParser(node.Expressions)
+		}
+		return log.NewJSONParser(false), nil
+	case types.ParserKindLogfmt:
+		if len(node.Expressions) > 0 {
+			return log.NewLogfmtExpressionParser(node.Expressions, node.Strict)
+		}
+		return log.NewLogfmtParser(node.Strict, node.KeepEmpty), nil
+	case types.ParserKindRegexp:
+		return log.NewRegexpParser(node.Pattern)
+	case types.ParserKindPattern:
+		return log.NewPatternParser(node.Pattern)
+	case types.ParserKindUnpack:
+		return log.NewUnpackParser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported parser kind %s", node.Kind)
+	}
+}
+
+type lineParser struct {
+	stage        log.Stage
+	modifiesLine bool
+	builder      *log.BaseLabelsBuilder
+}
+
+// parse parses the message column of batch and returns a new record with the
+// extracted columns.
+func (p *lineParser) parse(batch arrow.Record) (arrow.Record, error) {
+	var (
+		schema = batch.Schema()
+
+		messageIdx   = -1
+		timestampIdx = -1
+		labelIdxs    []int
+		parsedIdxs   = make(map[string]int)
+	)
+
+	for i, field := range schema.Fields() {
+		ct, ok := field.Metadata.GetValue(types.MetadataKeyColumnType)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case ct == types.ColumnTypeBuiltin.String() && field.Name == types.ColumnNameBuiltinMessage:
+			messageIdx = i
+		case ct == types.ColumnTypeBuiltin.String() && field.Name == types.ColumnNameBuiltinTimestamp:
+			timestampIdx = i
+		case ct == types.ColumnTypeLabel.String() && field.Type.ID() == arrow.STRING:
+			labelIdxs = append(labelIdxs, i)
+		case ct == types.ColumnTypeParsed.String() && field.Type.ID() == arrow.STRING:
+			parsedIdxs[field.Name] = i
+		}
+	}
+
+	if messageIdx < 0 {
+		return nil, fmt.Errorf("column %s not found in input", types.ColumnNameBuiltinMessage)
+	}
+	messages, ok := batch.Column(messageIdx).(*array.String)
+	if !ok {
+		return nil, fmt.Errorf("column %s has unexpected type %s", types.ColumnNameBuiltinMessage, batch.Column(messageIdx).DataType())
+	}
+
+	var timestamps *array.Timestamp
+	if timestampIdx >= 0 {
+		timestamps, _ = batch.Column(timestampIdx).(*array.Timestamp)
+	}
+
+	mem := memory.NewGoAllocator()
+
+	var (
+		columns    = newParsedColumns(mem)
+		lines      *array.StringBuilder
+		lblBuilder = labels.NewScratchBuilder(len(labelIdxs))
+		buf        []labels.Label
+	)
+	defer columns.Release()
+
+	if p.modifiesLine {
+		lines = array.NewStringBuilder(mem)
+		defer lines.Release()
+	}
+
+	for row := range int(batch.NumRows()) {
+		if messages.IsNull(row) {
+			if lines != nil {
+				lines.AppendNull()
+			}
+			continue
+		}
+
+		// Stream labels are the base labels of the parser, which is used to
+		// detect conflicts between extracted values and stream labels.
+		lblBuilder.Reset()
+		for _, idx := range labelIdxs {
+			col := batch.Column(idx).(*array.String)
+			if col.IsValid(row) && col.Value(row) != "" {
+				lblBuilder.Add(schema.Field(idx).Name, col.Value(row))
+			}
+		}
+		lblBuilder.Sort()
+		base := lblBuilder.Labels()
+
+		var ts int64
+		if timestamps != nil && timestamps.IsValid(row) {
+			ts = int64(timestamps.Value(row))
+		}
+
+		lbs := p.builder.ForLabels(base, base.Hash())
+		lbs.Reset()
+
+		line, _ := p.stage.Process(ts, []byte(messages.Value(row)), lbs)
+		if lines != nil {
+			lines.Append(string(line))
+		}
+
+		buf = lbs.UnsortedLabels(buf, log.ParsedLabel)
+		for _, lbl := range buf {
+			columns.Set(row, lbl.Name, lbl.Value)
+		}
+	}
+
+	numRows := int(batch.NumRows())
+	names, values := columns.Arrays(numRows)
+	defer func() {
+		for _, arr := range values {
+			arr.Release()
+		}
+	}()
+
+	// Build the output schema: all input columns except parsed columns that are
+	// replaced by a newly extracted column, followed by the extracted columns.
+	replaced := make(map[int]struct{})
+	for _, name := range names {
+		if idx, ok := parsedIdxs[name]; ok {
+			replaced[idx] = struct{}{}
+		}
+	}
+
+	fields := make([]arrow.Field, 0, int(batch.NumCols())+len(names))
+	arrays := make([]arrow.Array, 0, int(batch.NumCols())+len(names))
+
+	for i, field := range schema.Fields() {
+		if _, ok := replaced[i]; ok {
+			continue
+		}
+
+		col := batch.Column(i)
+		if i == messageIdx && lines != nil {
+			col = lines.NewArray()
+			defer col.Release()
+		}
+
+		fields = append(fields, field)
+		arrays = append(arrays, col)
+	}
+
+	for i, name := range names {
+		col := values[i]
+		if idx, ok := parsedIdxs[name]; ok {
+			col = mergeStringArrays(mem, col.(*array.String), batch.Column(idx).(*array.String))
+			defer col.Release()
+		}
+
+		fields = append(fields, arrow.Field{
+			Name:     name,
+			Type:     datatype.Arrow.String,
+			Nullable: true,
+			Metadata: datatype.ColumnMetadata(types.ColumnTypeParsed, datatype.Loki.String),
+		})
+		arrays = append(arrays, col)
+	}
+
+	return array.NewRecord(arrow.NewSchema(fields, nil), arrays, int64(numRows)), nil
+}
+
+// mergeStringArrays returns an array that contains the values of primary and
+// the values of fallback for rows where primary is null.
+func mergeStringArrays(mem memory.Allocator, primary, fallback *array.String) arrow.Array {
+	builder := array.NewStringBuilder(mem)
+	defer builder.Release()
+
+	for i := range primary.Len() {
+		switch {
+		case primary.IsValid(i):
+			builder.Append(primary.Value(i))
+		case fallback.IsValid(i):
+			builder.Append(fallback.Value(i))
+		default:
+			builder.AppendNull()
+		}
+	}
+	return builder.NewArray()
+}
+
+// parsedColumns collects the values of columns that are discovered while
+// parsing rows.
+type parsedColumns struct {
+	mem      memory.Allocator
+	names    []string
+	builders map[string]*array.StringBuilder
+}
+
+func newParsedColumns(mem memory.Allocator) *parsedColumns {
+	return &parsedColumns{
+		mem:      mem,
+		builders: make(map[string]*array.StringBuilder),
+	}
+}
+
+// Set sets the value of the named column for the given row. Rows must be set
+// in ascending order.
+func (c *parsedColumns) Set(row int, name, value string) {
+	builder, ok := c.builders[name]
+	if !ok {
+		builder = array.NewStringBuilder(c.mem)
+		c.builders[name] = builder
+		c.names = append(c.names, name)
+	}
+
+	// Pad the rows without value for this column.
+	if n := row - builder.Len(); n > 0 {
+		builder.AppendNulls(n)
+	}
+	if builder.Len() > row {
+		// The value has already been set for this row.
+		return
+	}
+	builder.Append(value)
+}
+
+// Arrays returns the names of the columns in order of appearance and the
+// arrays of numRows values. The returned arrays must be released by the
+// caller.
+func (c *parsedColumns) Arrays(numRows int) ([]string, []arrow.Array) {
+	arrays := make([]arrow.Array, len(c.names))
+	for i, name := range c.names {
+		builder := c.builders[name]
+		if n := numRows - builder.Len(); n > 0 {
+			builder.AppendNulls(n)
+		}
+		arrays[i] = builder.NewArray()
+	}
+	return c.names, arrays
+}
+
+// Release releases the builders of the columns.
+func (c *parsedColumns) Release() {
+	for _, builder := range c.builders {
+		builder.Release()
+	}
+}
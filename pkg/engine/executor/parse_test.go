@@ -0,0 +1,170 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/engine/planner/physical"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/util/arrowtest"
+)
+
+func TestParsePipeline(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: types.ColumnNameBuiltinMessage, Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadataBuiltinMessage},
+		{Name: "app", Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)},
+	}, nil)
+
+	for _, tt := range []struct {
+		name     string
+		node     *physical.ParseNode
+		input    arrowtest.Rows
+		expected arrowtest.Rows
+	}{
+		{
+			name: "logfmt",
+			node: &physical.ParseNode{Kind: types.ParserKindLogfmt},
+			input: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: `level=error msg="request failed"`, "app": "api"},
+				{types.ColumnNameBuiltinMessage: `level=info`, "app": "api"},
+				{types.ColumnNameBuiltinMessage: nil, "app": "api"},
+			},
+			expected: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: `level=error msg="request failed"`, "app": "api", "level": "error", "msg": "request failed"},
+				{types.ColumnNameBuiltinMessage: `level=info`, "app": "api", "level": "info", "msg": nil},
+				{types.ColumnNameBuiltinMessage: nil, "app": "api", "level": nil, "msg": nil},
+			},
+		},
+		{
+			name: "logfmt with expressions",
+			node: &physical.ParseNode{
+				Kind:        types.ParserKindLogfmt,
+				Expressions: []log.LabelExtractionExpr{log.NewLabelExtractionExpr("severity", "level")},
+			},
+			input: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: `level=error msg="request failed"`, "app": "api"},
+			},
+			expected: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: `level=error msg="request failed"`, "app": "api", "severity": "error"},
+			},
+		},
+		{
+			name: "json with conflicting stream label",
+			node: &physical.ParseNode{Kind: types.ParserKindJSON},
+			input: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: `{"app":"frontend","status":500}`, "app": "api"},
+			},
+			expected: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: `{"app":"frontend","status":500}`, "app": "api", "app_extracted": "frontend", "status": "500"},
+			},
+		},
+		{
+			name: "json with invalid line",
+			node: &physical.ParseNode{Kind: types.ParserKindJSON},
+			input: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: `{"status":500}`, "app": "api"},
+				{types.ColumnNameBuiltinMessage: `not json`, "app": "api"},
+			},
+			expected: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: `{"status":500}`, "app": "api", "status": "500", "__error__": nil, "__error_details__": nil},
+				{types.ColumnNameBuiltinMessage: `not json`, "app": "api", "status": nil, "__error__": "JSONParserErr", "__error_details__": "Value looks like object, but can't find closing '}' symbol"},
+			},
+		},
+		{
+			name: "regexp",
+			node: &physical.ParseNode{Kind: types.ParserKindRegexp, Pattern: `took (?P<duration>\S+)`},
+			input: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: `request took 10ms`, "app": "api"},
+				{types.ColumnNameBuiltinMessage: `request failed`, "app": "api"},
+			},
+			expected: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: `request took 10ms`, "app": "api", "duration": "10ms"},
+				{types.ColumnNameBuiltinMessage: `request failed`, "app": "api", "duration": nil},
+			},
+		},
+		{
+			name: "pattern",
+			node: &physical.ParseNode{Kind: types.ParserKindPattern, Pattern: `<method> <path> <_>`},
+			input: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: `GET /api/v1/push 200`, "app": "api"},
+			},
+			expected: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: `GET /api/v1/push 200`, "app": "api", "method": "GET", "path": "/api/v1/push"},
+			},
+		},
+		{
+			name: "unpack replaces log line",
+			node: &physical.ParseNode{Kind: types.ParserKindUnpack},
+			input: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: `{"_entry":"original line","pod":"api-0"}`, "app": "api"},
+			},
+			expected: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: `original line`, "app": "api", "pod": "api-0"},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			alloc := memory.NewGoAllocator()
+
+			input := tt.input.Record(alloc, schema)
+			defer input.Release()
+
+			pipeline, err := NewParsePipeline(tt.node, NewBufferedPipeline(input))
+			require.NoError(t, err)
+			defer pipeline.Close()
+
+			require.NoError(t, pipeline.Read(t.Context()))
+			record, err := pipeline.Value()
+			require.NoError(t, err)
+			defer record.Release()
+
+			for _, field := range record.Schema().Fields()[schema.NumFields():] {
+				ct, ok := field.Metadata.GetValue(types.MetadataKeyColumnType)
+				require.True(t, ok)
+				require.Equal(t, types.ColumnTypeParsed.String(), ct)
+			}
+
+			actual, err := arrowtest.RecordRows(record)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestParsePipeline_MergesParsedColumns(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: types.ColumnNameBuiltinMessage, Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadataBuiltinMessage},
+		{Name: "level", Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeParsed, datatype.Loki.String)},
+	}, nil)
+
+	alloc := memory.NewGoAllocator()
+	input := arrowtest.Rows{
+		{types.ColumnNameBuiltinMessage: `level=error`, "level": "info"},
+		{types.ColumnNameBuiltinMessage: `msg=timeout`, "level": "warn"},
+	}.Record(alloc, schema)
+	defer input.Release()
+
+	pipeline, err := NewParsePipeline(&physical.ParseNode{Kind: types.ParserKindLogfmt}, NewBufferedPipeline(input))
+	require.NoError(t, err)
+	defer pipeline.Close()
+
+	require.NoError(t, pipeline.Read(t.Context()))
+	record, err := pipeline.Value()
+	require.NoError(t, err)
+	defer record.Release()
+
+	// The previously parsed level column is replaced by the merged column.
+	require.Equal(t, 1, len(record.Schema().FieldIndices("level")))
+
+	actual, err := arrowtest.RecordRows(record)
+	require.NoError(t, err)
+	require.Equal(t, arrowtest.Rows{
+		{types.ColumnNameBuiltinMessage: `level=error`, "level": "error", "msg": nil},
+		{types.ColumnNameBuiltinMessage: `msg=timeout`, "level": "warn", "msg": "timeout"},
+	}, actual)
+}
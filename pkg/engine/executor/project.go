@@ -6,9 +6,12 @@ import (
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
 
 	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
 	"github.com/grafana/loki/v3/pkg/engine/planner/physical"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
 )
 
 func NewProjectPipeline(input Pipeline, columns []physical.ColumnExpression, evaluator *expressionEvaluator) (*GenericPipeline, error) {
@@ -56,3 +59,462 @@ func NewProjectPipeline(input Pipeline, columns []physical.ColumnExpression, eva
 		return successState(projectedRecord)
 	}, input), nil
 }
+
+// NewExpandPipeline returns a pipeline that evaluates the expressions of
+// columns and adds their results as columns to its input. Each column must be
+// a [physical.AssignExpr].
+//
+// All expressions are evaluated against the columns of the input. Assigning
+// to a builtin column replaces that column. Assigning to any other column type
+// hides the label, metadata and parsed columns of the same name for the rows
+// where the assigned value is not null, so that rows without a value keep
+// their previous value and column type.
+//
+// Assigning to a generated column replaces the generated column of the same
+// name, or adds it to the input. Unlike other columns, generated columns may
+// hold values of any type, such as the floats of a cast.
+//
+// Errors of template expressions and casts are reported in the parsed columns
+// __error__ and __error_details__.
+func NewExpandPipeline(input Pipeline, columns []physical.ColumnExpression, evaluator *expressionEvaluator) (*GenericPipeline, error) {
+	assignments := make([]*physical.AssignExpr, len(columns))
+	for i, col := range columns {
+		assign, ok := col.(*physical.AssignExpr)
+		if !ok {
+			return nil, fmt.Errorf("projection column %d is not an assign expression", i)
+		}
+		assignments[i] = assign
+	}
+
+	return newGenericPipeline(Local, func(ctx context.Context, inputs []Pipeline) state {
+		// Pull the next item from the input pipeline
+		input := inputs[0]
+		err := input.Read(ctx)
+		if err != nil {
+			return failureState(err)
+		}
+
+		batch, err := input.Value()
+		if err != nil {
+			return failureState(err)
+		}
+		defer batch.Release()
+
+		expanded, err := expandRecord(batch, assignments, evaluator)
+		if err != nil {
+			return failureState(err)
+		}
+		return successState(expanded)
+	}, input), nil
+}
+
+// assignedColumn is the evaluated value of an [physical.AssignExpr].
+type assignedColumn struct {
+	ref    types.ColumnRef
+	values *array.String
+}
+
+// generatedColumn is the evaluated value of an [physical.AssignExpr] to a
+// generated column.
+type generatedColumn struct {
+	name   string
+	dt     datatype.DataType
+	values arrow.Array
+}
+
+func expandRecord(batch arrow.Record, assignments []*physical.AssignExpr, evaluator *expressionEvaluator) (arrow.Record, error) {
+	mem := memory.NewGoAllocator()
+
+	var (
+		assigned      []assignedColumn
+		generated     []generatedColumn
+		errs, details []*array.String
+	)
+	defer func() {
+		for _, col := range assigned {
+			col.values.Release()
+		}
+		for _, col := range generated {
+			col.values.Release()
+		}
+		for i := range errs {
+			errs[i].Release()
+			details[i].Release()
+		}
+	}()
+
+	for _, assign := range assignments {
+		// Assigning a column that does not exist leaves the target column
+		// untouched, like renaming a non-existing label.
+		if col, ok := assign.Value.(*physical.ColumnExpr); ok && len(batch.Schema().FieldIndices(col.Ref.Column)) == 0 {
+			continue
+		}
+
+		var (
+			values arrow.Array
+			dt     datatype.DataType
+		)
+		switch value := assign.Value.(type) {
+		case *physical.TemplateExpr:
+			res, err := evaluator.evalTemplate(value, batch)
+			if err != nil {
+				return nil, err
+			}
+			values, dt = res.Values, datatype.Loki.String
+			errs = append(errs, res.Errors.(*array.String))
+			details = append(details, res.ErrorDetails.(*array.String))
+		default:
+			if cast, ok := value.(*physical.UnaryExpr); ok && isCastOp(cast.Op) {
+				res, err := evaluator.evalCast(cast, batch)
+				if err != nil {
+					return nil, err
+				}
+				values, dt = res.Values, datatype.Loki.Float
+				errs = append(errs, res.Errors.(*array.String))
+				details = append(details, res.ErrorDetails.(*array.String))
+				break
+			}
+
+			vec, err := evaluator.eval(value, batch)
+			if err != nil {
+				return nil, err
+			}
+			values, dt = vec.ToArray(), vec.Type()
+			if _, ok := vec.(*Array); ok {
+				// The array is owned by the batch.
+				values.Retain()
+			}
+		}
+
+		if assign.Ref.Type == types.ColumnTypeGenerated {
+			generated = append(generated, generatedColumn{name: assign.Ref.Column, dt: dt, values: values})
+			continue
+		}
+
+		str, ok := values.(*array.String)
+		if !ok {
+			values.Release()
+			return nil, fmt.Errorf("cannot assign value of type %s to column %s", values.DataType(), assign.Ref.String())
+		}
+		assigned = append(assigned, assignedColumn{ref: assign.Ref, values: str})
+	}
+
+	// Template and cast errors are treated like parsed errors, so they can be
+	// filtered and dropped like errors of parsers.
+	for i := range errs {
+		assigned = append(assigned,
+			assignedColumn{ref: types.ColumnRef{Column: logqlmodel.ErrorLabel, Type: types.ColumnTypeParsed}, values: errs[i]},
+			assignedColumn{ref: types.ColumnRef{Column: logqlmodel.ErrorDetailsLabel, Type: types.ColumnTypeParsed}, values: details[i]},
+		)
+		errs[i].Retain()
+		details[i].Retain()
+	}
+	assigned = mergeAssignedColumns(mem, assigned)
+
+	schema := batch.Schema()
+	fields := make([]arrow.Field, 0, int(batch.NumCols())+len(assigned))
+	arrays := make([]arrow.Array, 0, int(batch.NumCols())+len(assigned))
+	defer func() {
+		for _, arr := range arrays {
+			arr.Release()
+		}
+	}()
+
+	// merged holds the index of the output column of an assigned parsed
+	// column that already existed in the input.
+	merged := make(map[string]int)
+	// replaced holds the names of the generated columns that already existed
+	// in the input.
+	replaced := make(map[string]struct{})
+
+	for i, field := range schema.Fields() {
+		col := batch.Column(i)
+		col.Retain()
+
+		ct, _ := field.Metadata.GetValue(types.MetadataKeyColumnType)
+		if ct == types.ColumnTypeGenerated.String() {
+			for _, g := range generated {
+				if g.name != field.Name {
+					continue
+				}
+				col.Release()
+				col = g.values
+				col.Retain()
+				field = generatedField(g)
+				replaced[field.Name] = struct{}{}
+			}
+		}
+		for _, a := range assigned {
+			if a.ref.Column != field.Name {
+				continue
+			}
+
+			var replaced arrow.Array
+			switch {
+			case a.ref.Type == types.ColumnTypeBuiltin && ct == types.ColumnTypeBuiltin.String():
+				replaced = a.values
+				replaced.Retain()
+			case a.ref.Type != types.ColumnTypeBuiltin && ct != types.ColumnTypeBuiltin.String():
+				str, ok := col.(*array.String)
+				if !ok {
+					col.Release()
+					return nil, fmt.Errorf("cannot assign value to column %s of type %s", field.Name, col.DataType())
+				}
+				if a.ref.Type.String() == ct {
+					replaced = mergeStringArrays(mem, a.values, str)
+					merged[field.Name] = len(fields)
+				} else {
+					replaced = nullWhere(mem, str, a.values.IsValid)
+				}
+			default:
+				continue
+			}
+			col.Release()
+			col = replaced
+		}
+
+		fields = append(fields, field)
+		arrays = append(arrays, col)
+	}
+
+	for _, g := range generated {
+		if _, ok := replaced[g.name]; ok {
+			continue
+		}
+		replaced[g.name] = struct{}{}
+
+		g.values.Retain()
+		fields = append(fields, generatedField(g))
+		arrays = append(arrays, g.values)
+	}
+
+	for _, a := range assigned {
+		if a.ref.Type == types.ColumnTypeBuiltin {
+			continue
+		}
+		if _, ok := merged[a.ref.Column]; ok {
+			continue
+		}
+		merged[a.ref.Column] = len(fields)
+
+		a.values.Retain()
+		fields = append(fields, arrow.Field{
+			Name:     a.ref.Column,
+			Type:     datatype.Arrow.String,
+			Nullable: true,
+			Metadata: datatype.ColumnMetadata(a.ref.Type, datatype.Loki.String),
+		})
+		arrays = append(arrays, a.values)
+	}
+
+	return array.NewRecord(arrow.NewSchema(fields, nil), arrays, batch.NumRows()), nil
+}
+
+func generatedField(g generatedColumn) arrow.Field {
+	return arrow.Field{
+		Name:     g.name,
+		Type:     g.values.DataType(),
+		Nullable: true,
+		Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, g.dt),
+	}
+}
+
+// isCastOp returns whether op converts a string column into a numeric value.
+func isCastOp(op types.UnaryOp) bool {
+	switch op {
+	case types.UnaryOpCastFloat, types.UnaryOpCastBytes, types.UnaryOpCastDuration:
+		return true
+	default:
+		return false
+	}
+}
+
+// mergeAssignedColumns merges the values of columns that are assigned more
+// than once, with later assignments taking precedence.
+func mergeAssignedColumns(mem memory.Allocator, assigned []assignedColumn) []assignedColumn {
+	res := assigned[:0]
+	indices := make(map[types.ColumnRef]int, len(assigned))
+	for _, a := range assigned {
+		idx, ok := indices[a.ref]
+		if !ok {
+			indices[a.ref] = len(res)
+			res = append(res, a)
+			continue
+		}
+
+		prev := res[idx].values
+		res[idx].values = mergeStringArrays(mem, a.values, prev).(*array.String)
+		prev.Release()
+		a.values.Release()
+	}
+	return res
+}
+
+// NewKeepPipeline returns a pipeline that removes all label, metadata and
+// parsed columns of its input, except the columns. Columns that are
+// [physical.ConditionalColumnExpr] are only kept for the rows where their
+// condition is true. The __error__ and __error_details__ columns are always
+// kept.
+func NewKeepPipeline(input Pipeline, columns []physical.ColumnExpression, evaluator *expressionEvaluator) (*GenericPipeline, error) {
+	return newLabelsProjectPipeline(input, columns, evaluator, true)
+}
+
+// NewDropPipeline returns a pipeline that removes the label, metadata and
+// parsed columns of its input that match the columns. Columns that are
+// [physical.ConditionalColumnExpr] are only removed for the rows where their
+// condition is true.
+func NewDropPipeline(input Pipeline, columns []physical.ColumnExpression, evaluator *expressionEvaluator) (*GenericPipeline, error) {
+	return newLabelsProjectPipeline(input, columns, evaluator, false)
+}
+
+func newLabelsProjectPipeline(input Pipeline, columns []physical.ColumnExpression, evaluator *expressionEvaluator, keep bool) (*GenericPipeline, error) {
+	for i, col := range columns {
+		switch col.(type) {
+		case *physical.ColumnExpr, *physical.ConditionalColumnExpr:
+		default:
+			return nil, fmt.Errorf("projection column %d is not a column or conditional column expression", i)
+		}
+	}
+
+	return newGenericPipeline(Local, func(ctx context.Context, inputs []Pipeline) state {
+		// Pull the next item from the input pipeline
+		input := inputs[0]
+		err := input.Read(ctx)
+		if err != nil {
+			return failureState(err)
+		}
+
+		batch, err := input.Value()
+		if err != nil {
+			return failureState(err)
+		}
+		defer batch.Release()
+
+		projected, err := projectLabels(batch, columns, evaluator, keep)
+		if err != nil {
+			return failureState(err)
+		}
+		return successState(projected)
+	}, input), nil
+}
+
+// projectLabels keeps or removes the label, metadata and parsed columns of
+// batch that match columns.
+func projectLabels(batch arrow.Record, columns []physical.ColumnExpression, evaluator *expressionEvaluator, keep bool) (arrow.Record, error) {
+	mem := memory.NewGoAllocator()
+
+	// unconditional contains the names of the columns that are matched for
+	// all rows, conditions the conditions of the columns that are only matched
+	// for some rows.
+	var (
+		unconditional = make(map[string]struct{})
+		conditions    = make(map[string][]*array.Boolean)
+	)
+	for _, col := range columns {
+		switch col := col.(type) {
+		case *physical.ColumnExpr:
+			unconditional[col.Ref.Column] = struct{}{}
+		case *physical.ConditionalColumnExpr:
+			vec, err := evaluator.eval(col.Condition, batch)
+			if err != nil {
+				return nil, err
+			}
+			arr, ok := vec.ToArray().(*array.Boolean)
+			if !ok {
+				return nil, fmt.Errorf("condition of column %s must be boolean, got %s", col.Ref.String(), vec.ToArray().DataType())
+			}
+			conditions[col.Ref.Column] = append(conditions[col.Ref.Column], arr)
+		}
+	}
+
+	matches := func(name string) func(int) bool {
+		return func(i int) bool {
+			for _, arr := range conditions[name] {
+				if arr.IsValid(i) && arr.Value(i) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	schema := batch.Schema()
+	fields := make([]arrow.Field, 0, int(batch.NumCols()))
+	arrays := make([]arrow.Array, 0, int(batch.NumCols()))
+	defer func() {
+		for _, arr := range arrays {
+			arr.Release()
+		}
+	}()
+
+	for i, field := range schema.Fields() {
+		col := batch.Column(i)
+
+		ct, ok := field.Metadata.GetValue(types.MetadataKeyColumnType)
+		isLabel := ok && (ct == types.ColumnTypeLabel.String() || ct == types.ColumnTypeMetadata.String() || ct == types.ColumnTypeParsed.String())
+		if keep && isErrorLabel(field.Name) {
+			isLabel = false
+		}
+		if !isLabel {
+			col.Retain()
+			fields = append(fields, field)
+			arrays = append(arrays, col)
+			continue
+		}
+
+		_, matchesAll := unconditional[field.Name]
+		_, matchesSome := conditions[field.Name]
+
+		switch {
+		case keep && matchesAll, !keep && !matchesAll && !matchesSome:
+			col.Retain()
+		case keep && matchesSome:
+			str, ok := col.(*array.String)
+			if !ok {
+				return nil, fmt.Errorf("cannot project column %s of type %s", field.Name, col.DataType())
+			}
+			match := matches(field.Name)
+			col = nullWhere(mem, str, func(i int) bool { return !match(i) })
+		case !keep && !matchesAll && matchesSome:
+			str, ok := col.(*array.String)
+			if !ok {
+				return nil, fmt.Errorf("cannot project column %s of type %s", field.Name, col.DataType())
+			}
+			col = nullWhere(mem, str, matches(field.Name))
+		default:
+			// The column is removed.
+			continue
+		}
+
+		fields = append(fields, field)
+		arrays = append(arrays, col)
+	}
+
+	return array.NewRecord(arrow.NewSchema(fields, nil), arrays, batch.NumRows()), nil
+}
+
+// isErrorLabel returns whether name is the name of one of the labels that
+// are used to report errors of the pipeline stages.
+func isErrorLabel(name string) bool {
+	switch name {
+	case logqlmodel.ErrorLabel, logqlmodel.ErrorDetailsLabel, logqlmodel.PreserveErrorLabel:
+		return true
+	}
+	return false
+}
+
+// nullWhere returns a copy of arr in which the rows for which cond returns
+// true are null.
+func nullWhere(mem memory.Allocator, arr *array.String, cond func(int) bool) arrow.Array {
+	builder := array.NewStringBuilder(mem)
+	defer builder.Release()
+
+	for i := range arr.Len() {
+		if arr.IsNull(i) || cond(i) {
+			builder.AppendNull()
+			continue
+		}
+		builder.Append(arr.Value(i))
+	}
+	return builder.NewArray()
+}
@@ -2,13 +2,18 @@ package executor
 
 import (
 	"testing"
+	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
 	"github.com/grafana/loki/v3/pkg/engine/internal/types"
 	"github.com/grafana/loki/v3/pkg/engine/planner/physical"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+	"github.com/grafana/loki/v3/pkg/util/arrowtest"
 )
 
 func TestNewProjectPipeline(t *testing.T) {
@@ -200,3 +205,272 @@ func createColumnRef(name string) types.ColumnRef {
 		Type:   types.ColumnTypeBuiltin,
 	}
 }
+
+func TestNewExpandPipeline(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: types.ColumnNameBuiltinTimestamp, Type: datatype.Arrow.Timestamp, Nullable: true, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
+		{Name: types.ColumnNameBuiltinMessage, Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadataBuiltinMessage},
+		{Name: "app", Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)},
+		{Name: "dst", Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)},
+		{Name: "app", Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeMetadata, datatype.Loki.String)},
+		{Name: "level", Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeParsed, datatype.Loki.String)},
+	}, nil)
+
+	input := arrowtest.Rows{
+		{types.ColumnNameBuiltinTimestamp: time.Unix(0, 1).UTC(), types.ColumnNameBuiltinMessage: "request failed", "app": "api", "dst": "x", "level": "error"},
+		{types.ColumnNameBuiltinTimestamp: time.Unix(0, 2).UTC(), types.ColumnNameBuiltinMessage: "request done", "app": "api", "dst": nil, "level": nil},
+	}
+
+	messageRef := types.ColumnRef{Column: types.ColumnNameBuiltinMessage, Type: types.ColumnTypeBuiltin}
+
+	for _, tt := range []struct {
+		name    string
+		columns []physical.ColumnExpression
+		check   func(t *testing.T, rows arrowtest.Rows)
+	}{
+		{
+			name: "line_format replaces log line",
+			columns: []physical.ColumnExpression{
+				&physical.AssignExpr{Ref: messageRef, Value: &physical.TemplateExpr{Template: `{{.level}}: {{__line__}} ({{.app}})`}},
+			},
+			check: func(t *testing.T, rows arrowtest.Rows) {
+				require.Equal(t, "error: request failed (api)", rows[0][types.ColumnNameBuiltinMessage])
+				require.Equal(t, ": request done (api)", rows[1][types.ColumnNameBuiltinMessage])
+			},
+		},
+		{
+			name: "line_format with conflicting metadata column",
+			columns: []physical.ColumnExpression{
+				&physical.AssignExpr{Ref: messageRef, Value: &physical.TemplateExpr{Template: `{{.app_extracted}}`}},
+			},
+			check: func(t *testing.T, rows arrowtest.Rows) {
+				require.Equal(t, "api", rows[0][types.ColumnNameBuiltinMessage])
+				require.Equal(t, "api", rows[1][types.ColumnNameBuiltinMessage])
+			},
+		},
+		{
+			name: "label_format template",
+			columns: []physical.ColumnExpression{
+				&physical.AssignExpr{
+					Ref:   types.ColumnRef{Column: "level", Type: types.ColumnTypeParsed},
+					Value: &physical.TemplateExpr{Template: `{{ .level | upper }}`, Label: "level"},
+				},
+			},
+			check: func(t *testing.T, rows arrowtest.Rows) {
+				require.Equal(t, "ERROR", rows[0]["level"])
+				require.Equal(t, "", rows[1]["level"])
+			},
+		},
+		{
+			name: "label_format rename",
+			columns: []physical.ColumnExpression{
+				&physical.AssignExpr{
+					Ref:   types.ColumnRef{Column: "dst", Type: types.ColumnTypeParsed},
+					Value: &physical.ColumnExpr{Ref: types.ColumnRef{Column: "level", Type: types.ColumnTypeAmbiguous}},
+				},
+			},
+			check: func(t *testing.T, rows arrowtest.Rows) {
+				// The label column is hidden for rows with a formatted value.
+				require.Equal(t, nil, rows[0]["dst"])
+				require.Equal(t, "error", rows[0]["dst.parsed"])
+				require.Equal(t, nil, rows[1]["dst"])
+				require.Equal(t, nil, rows[1]["dst.parsed"])
+			},
+		},
+		{
+			name: "rename of missing column",
+			columns: []physical.ColumnExpression{
+				&physical.AssignExpr{
+					Ref:   types.ColumnRef{Column: "dst", Type: types.ColumnTypeParsed},
+					Value: &physical.ColumnExpr{Ref: types.ColumnRef{Column: "missing", Type: types.ColumnTypeAmbiguous}},
+				},
+			},
+			check: func(t *testing.T, rows arrowtest.Rows) {
+				require.Equal(t, "x", rows[0]["dst"])
+				require.NotContains(t, rows[0], "dst.parsed")
+			},
+		},
+		{
+			name: "template error",
+			columns: []physical.ColumnExpression{
+				&physical.AssignExpr{Ref: messageRef, Value: &physical.TemplateExpr{Template: `{{ regexReplaceAll "(" .level "" }}`}},
+			},
+			check: func(t *testing.T, rows arrowtest.Rows) {
+				// The log line is kept and the error is reported.
+				require.Equal(t, "request failed", rows[0][types.ColumnNameBuiltinMessage])
+				require.Equal(t, "TemplateFormatErr", rows[0][logqlmodel.ErrorLabel])
+				require.Contains(t, rows[0][logqlmodel.ErrorDetailsLabel], "error parsing regexp")
+			},
+		},
+		{
+			name: "unwrap cast error",
+			columns: []physical.ColumnExpression{
+				&physical.AssignExpr{
+					Ref: types.ColumnRef{Column: types.ColumnNameGeneratedUnwrapped, Type: types.ColumnTypeGenerated},
+					Value: &physical.UnaryExpr{
+						Op:   types.UnaryOpCastFloat,
+						Left: &physical.ColumnExpr{Ref: types.ColumnRef{Column: "dst", Type: types.ColumnTypeAmbiguous}},
+					},
+				},
+			},
+			check: func(t *testing.T, rows arrowtest.Rows) {
+				// Values that cannot be converted have no value and report
+				// the error, empty values are skipped without an error.
+				require.Equal(t, nil, rows[0][types.ColumnNameGeneratedUnwrapped])
+				require.Equal(t, "SampleExtractionErr", rows[0][logqlmodel.ErrorLabel])
+				require.Contains(t, rows[0][logqlmodel.ErrorDetailsLabel], `parsing "x"`)
+				require.Equal(t, nil, rows[1][types.ColumnNameGeneratedUnwrapped])
+				require.Equal(t, nil, rows[1][logqlmodel.ErrorLabel])
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			alloc := memory.NewGoAllocator()
+
+			record := input.Record(alloc, schema)
+			defer record.Release()
+
+			pipeline, err := NewExpandPipeline(NewBufferedPipeline(record), tt.columns, &expressionEvaluator{})
+			require.NoError(t, err)
+			defer pipeline.Close()
+
+			require.NoError(t, pipeline.Read(t.Context()))
+			res, err := pipeline.Value()
+			require.NoError(t, err)
+			defer res.Release()
+
+			tt.check(t, recordRowsByType(t, res))
+		})
+	}
+}
+
+func TestNewKeepAndDropPipeline(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: types.ColumnNameBuiltinMessage, Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadataBuiltinMessage},
+		{Name: "app", Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)},
+		{Name: "trace", Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeMetadata, datatype.Loki.String)},
+		{Name: "level", Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeParsed, datatype.Loki.String)},
+		{Name: logqlmodel.ErrorLabel, Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeParsed, datatype.Loki.String)},
+	}, nil)
+
+	input := arrowtest.Rows{
+		{types.ColumnNameBuiltinMessage: "line 1", "app": "api", "trace": "t1", "level": "debug", logqlmodel.ErrorLabel: nil},
+		{types.ColumnNameBuiltinMessage: "line 2", "app": "api", "trace": "t2", "level": "error", logqlmodel.ErrorLabel: "JSONParserErr"},
+	}
+
+	levelDebug := &physical.ConditionalColumnExpr{
+		Ref: types.ColumnRef{Column: "level", Type: types.ColumnTypeAmbiguous},
+		Condition: &physical.BinaryExpr{
+			Left:  &physical.ColumnExpr{Ref: types.ColumnRef{Column: "level", Type: types.ColumnTypeAmbiguous}},
+			Right: physical.NewLiteral("debug"),
+			Op:    types.BinaryOpEq,
+		},
+	}
+
+	for _, tt := range []struct {
+		name     string
+		keep     bool
+		columns  []physical.ColumnExpression
+		expected arrowtest.Rows
+	}{
+		{
+			name:    "keep",
+			keep:    true,
+			columns: []physical.ColumnExpression{&physical.ColumnExpr{Ref: types.ColumnRef{Column: "app", Type: types.ColumnTypeAmbiguous}}},
+			expected: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: "line 1", "app": "api", logqlmodel.ErrorLabel: nil},
+				{types.ColumnNameBuiltinMessage: "line 2", "app": "api", logqlmodel.ErrorLabel: "JSONParserErr"},
+			},
+		},
+		{
+			name:    "keep with matcher",
+			keep:    true,
+			columns: []physical.ColumnExpression{levelDebug},
+			expected: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: "line 1", "level": "debug", logqlmodel.ErrorLabel: nil},
+				{types.ColumnNameBuiltinMessage: "line 2", "level": nil, logqlmodel.ErrorLabel: "JSONParserErr"},
+			},
+		},
+		{
+			name: "drop",
+			columns: []physical.ColumnExpression{
+				&physical.ColumnExpr{Ref: types.ColumnRef{Column: "trace", Type: types.ColumnTypeAmbiguous}},
+				&physical.ColumnExpr{Ref: types.ColumnRef{Column: logqlmodel.ErrorLabel, Type: types.ColumnTypeAmbiguous}},
+			},
+			expected: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: "line 1", "app": "api", "level": "debug"},
+				{types.ColumnNameBuiltinMessage: "line 2", "app": "api", "level": "error"},
+			},
+		},
+		{
+			name:    "drop with matcher",
+			columns: []physical.ColumnExpression{levelDebug},
+			expected: arrowtest.Rows{
+				{types.ColumnNameBuiltinMessage: "line 1", "app": "api", "trace": "t1", "level": nil, logqlmodel.ErrorLabel: nil},
+				{types.ColumnNameBuiltinMessage: "line 2", "app": "api", "trace": "t2", "level": "error", logqlmodel.ErrorLabel: "JSONParserErr"},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			alloc := memory.NewGoAllocator()
+
+			record := input.Record(alloc, schema)
+			defer record.Release()
+
+			newPipeline := NewDropPipeline
+			if tt.keep {
+				newPipeline = NewKeepPipeline
+			}
+			pipeline, err := newPipeline(NewBufferedPipeline(record), tt.columns, &expressionEvaluator{})
+			require.NoError(t, err)
+			defer pipeline.Close()
+
+			require.NoError(t, pipeline.Read(t.Context()))
+			res, err := pipeline.Value()
+			require.NoError(t, err)
+			defer res.Release()
+
+			actual, err := arrowtest.RecordRows(res)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+// recordRowsByType returns the rows of rec. Columns that are not label or
+// builtin columns are keyed by their name and column type, e.g. "dst.parsed",
+// if the record contains another column with the same name.
+func recordRowsByType(t *testing.T, rec arrow.Record) arrowtest.Rows {
+	t.Helper()
+
+	counts := make(map[string]int)
+	for _, field := range rec.Schema().Fields() {
+		counts[field.Name]++
+	}
+
+	rows := make(arrowtest.Rows, rec.NumRows())
+	for i := range rows {
+		rows[i] = make(map[string]any)
+	}
+	for colIdx, field := range rec.Schema().Fields() {
+		name := field.Name
+		if ct, _ := field.Metadata.GetValue(types.MetadataKeyColumnType); counts[name] > 1 && ct != types.ColumnTypeLabel.String() && ct != types.ColumnTypeBuiltin.String() {
+			name = name + "." + ct
+		}
+
+		col := rec.Column(colIdx)
+		for i := range rows {
+			if col.IsNull(i) {
+				rows[i][name] = nil
+				continue
+			}
+			switch col := col.(type) {
+			case *array.String:
+				rows[i][name] = col.Value(i)
+			default:
+				rows[i][name] = col.GetOneForMarshal(i)
+			}
+		}
+	}
+	return rows
+}
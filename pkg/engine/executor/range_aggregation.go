@@ -2,8 +2,12 @@ package executor
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"maps"
+	"math"
+	"slices"
 	"strings"
 	"time"
 
@@ -11,19 +15,26 @@ import (
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/prometheus/model/labels"
 
 	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
 	"github.com/grafana/loki/v3/pkg/engine/internal/types"
 	"github.com/grafana/loki/v3/pkg/engine/planner/physical"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
 )
 
 type rangeAggregationOptions struct {
 	partitionBy []physical.ColumnExpression
 
+	operation types.RangeAggregationType
+	value     physical.Expression // expression to compute the aggregated value of each row. Nil if rows are counted.
+	parameter float64             // parameter of the aggregation, such as the quantile
+
 	// start and end timestamps are equal for instant queries.
 	startTs       time.Time     // start timestamp of the query
 	endTs         time.Time     // end timestamp of the query
 	rangeInterval time.Duration // range interval
+	offset        time.Duration // offset of the range interval
 	step          time.Duration // step used for range queries
 }
 
@@ -33,7 +44,12 @@ type rangeAggregationOptions struct {
 // 2. Partitions the data by the specified columns
 // 3. Applies the aggregation function on each partition
 //
-// Current version only supports counting for instant queries.
+// If no partition columns are specified, the data is partitioned by all label,
+// metadata and parsed columns, which results in one partition per series.
+//
+// The aggregation is evaluated at each step between the start and end
+// timestamp of the query. Like in the [logql.RangeVectorIterator], the window
+// of a step at time t contains all entries within (t-offset-range, t-offset].
 type RangeAggregationPipeline struct {
 	state  state
 	inputs []Pipeline
@@ -41,14 +57,44 @@ type RangeAggregationPipeline struct {
 	aggregator *partitionAggregator
 	evaluator  expressionEvaluator // used to evaluate column expressions
 	opts       rangeAggregationOptions
+
+	valueColumns map[string]struct{} // names of the columns referenced by the value expression
 }
 
 func NewRangeAggregationPipeline(inputs []Pipeline, evaluator expressionEvaluator, opts rangeAggregationOptions) (*RangeAggregationPipeline, error) {
+	if opts.operation == types.RangeAggregationTypeInvalid {
+		opts.operation = types.RangeAggregationTypeCount
+	}
+
+	switch opts.operation {
+	case types.RangeAggregationTypeCount, types.RangeAggregationTypeRate:
+		// value is optional
+	default:
+		if opts.value == nil {
+			return nil, fmt.Errorf("range aggregation %s requires a value expression", opts.operation)
+		}
+	}
+
+	valueColumns := make(map[string]struct{})
+	if opts.value != nil {
+		collectColumnNames(opts.value, valueColumns)
+	}
+
+	aggregator := newPartitionAggregator(opts.operation)
+	for _, column := range opts.partitionBy {
+		columnExpr, ok := column.(*physical.ColumnExpr)
+		if !ok {
+			return nil, fmt.Errorf("invalid column expression type %T", column)
+		}
+		aggregator.addField(columnExpr.Ref.Column, columnExpr.Ref.Type)
+	}
+
 	return &RangeAggregationPipeline{
-		inputs:     inputs,
-		evaluator:  evaluator,
-		aggregator: newPartitionAggregator(),
-		opts:       opts,
+		inputs:       inputs,
+		evaluator:    evaluator,
+		aggregator:   aggregator,
+		opts:         opts,
+		valueColumns: valueColumns,
 	}, nil
 }
 
@@ -75,12 +121,10 @@ func (r *RangeAggregationPipeline) Read(ctx context.Context) error {
 }
 
 // TODOs:
-// - Support implicit partitioning by all labels when partitionBy is empty
 // - Use columnar access pattern. Current approach is row-based which does not benefit from the storage format.
 // - Add toggle to return partial results on Read() call instead of returning only after exhausing all inputs.
 func (r *RangeAggregationPipeline) read(ctx context.Context) (arrow.Record, error) {
 	var (
-		isTSInRange  func(t time.Time) bool
 		tsColumnExpr = &physical.ColumnExpr{
 			Ref: types.ColumnRef{
 				Column: types.ColumnNameBuiltinTimestamp,
@@ -89,18 +133,10 @@ func (r *RangeAggregationPipeline) read(ctx context.Context) (arrow.Record, erro
 		} // timestamp column expression
 
 		// reused on each row read
-		labelValues = make([]string, len(r.opts.partitionBy))
+		labelValues []string
+		windows     []time.Time
 	)
 
-	{
-		evalTs := r.opts.endTs
-		earliestTs := r.opts.endTs.Add(-r.opts.rangeInterval)
-		isTSInRange = func(t time.Time) bool {
-			// Aggregate entries that belong in [earliestTs, evalTs)
-			return t.Compare(earliestTs) >= 0 && t.Compare(evalTs) < 0
-		}
-	}
-
 	r.aggregator.Reset() // reset before reading new inputs
 	inputsExhausted := false
 	for !inputsExhausted {
@@ -119,19 +155,17 @@ func (r *RangeAggregationPipeline) read(ctx context.Context) (arrow.Record, erro
 			record, _ := input.Value()
 
 			// extract all the columns that are used for partitioning
-			arrays := make([]*array.String, 0, len(r.opts.partitionBy))
-			for _, columnExpr := range r.opts.partitionBy {
-				vec, err := r.evaluator.eval(columnExpr, record)
-				if err != nil {
-					return nil, err
-				}
-
-				if vec.Type() != datatype.Loki.String {
-					return nil, fmt.Errorf("unsupported datatype for partitioning %s", vec.Type())
-				}
+			arrays, indexes, err := r.partitionArrays(record)
+			if err != nil {
+				return nil, err
+			}
 
-				arrays = append(arrays, vec.ToArray().(*array.String))
+			// extract the values to aggregate
+			valueAt, err := r.valueAccessor(record)
+			if err != nil {
+				return nil, err
 			}
+			errorAt := errorAccessor(record)
 
 			// extract timestamp column to check if the entry is in range
 			vec, err := r.evaluator.eval(tsColumnExpr, record)
@@ -140,27 +174,44 @@ func (r *RangeAggregationPipeline) read(ctx context.Context) (arrow.Record, erro
 			}
 			tsCol := vec.ToArray().(*array.Timestamp)
 
+			labelValues = slices.Grow(labelValues[:0], r.aggregator.NumOfFields())[:r.aggregator.NumOfFields()]
 			for row := range int(record.NumRows()) {
-				if !isTSInRange(tsCol.Value(row).ToTime(arrow.Nanosecond)) {
+				ts := tsCol.Value(row).ToTime(arrow.Nanosecond)
+				windows = r.windowsForTimestamp(windows[:0], ts)
+				if len(windows) == 0 {
+					continue
+				}
+
+				value, ok := valueAt(row)
+				if !ok {
+					// Rows whose value could not be converted fail the query,
+					// unless the error is filtered, like in the LogQL engine.
+					if errType, errDetails := errorAt(row); errType == errSampleExtraction {
+						return nil, logqlmodel.NewPipelineErr(r.rowLabels(arrays, indexes, row, errType, errDetails))
+					}
+					// rows without a value are not aggregated
 					continue
 				}
 
-				// reset label values and hash for each row
+				// reset label values for each row
 				clear(labelValues)
 				for col, arr := range arrays {
-					labelValues[col] = arr.Value(row)
+					if arr.IsNull(row) {
+						continue
+					}
+					labelValues[indexes[col]] = arr.Value(row)
 				}
-				r.aggregator.Add(labelValues)
+				r.aggregator.Add(windows, labelValues, ts, value)
 			}
 		}
 	}
 
-	if r.aggregator.NumOfPartitions() == 0 {
+	if r.aggregator.NumOfPoints() == 0 {
 		return nil, EOF // no values to aggregate & reached EOF
 	}
 
 	// TODO: schema is same for each read call when partitionBy is defined, we can create it once and reuse.
-	fields := make([]arrow.Field, 0, len(r.opts.partitionBy)+2)
+	fields := make([]arrow.Field, 0, r.aggregator.NumOfFields()+2)
 	fields = append(fields,
 		arrow.Field{
 			Name:     types.ColumnNameBuiltinTimestamp,
@@ -170,23 +221,18 @@ func (r *RangeAggregationPipeline) read(ctx context.Context) (arrow.Record, erro
 		},
 		arrow.Field{
 			Name:     types.ColumnNameGeneratedValue,
-			Type:     datatype.Arrow.Integer,
+			Type:     datatype.Arrow.Float,
 			Nullable: false,
-			Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Integer),
+			Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Float),
 		},
 	)
 
-	for _, column := range r.opts.partitionBy {
-		columnExpr, ok := column.(*physical.ColumnExpr)
-		if !ok {
-			panic(fmt.Sprintf("invalid column expression type %T", column))
-		}
-
+	for _, field := range r.aggregator.fields {
 		fields = append(fields, arrow.Field{
-			Name:     columnExpr.Ref.Column,
+			Name:     field.name,
 			Type:     datatype.Arrow.String,
 			Nullable: true,
-			Metadata: datatype.ColumnMetadata(columnExpr.Ref.Type, datatype.Loki.String),
+			Metadata: datatype.ColumnMetadata(field.ct, datatype.Loki.String),
 		})
 	}
 
@@ -194,17 +240,21 @@ func (r *RangeAggregationPipeline) read(ctx context.Context) (arrow.Record, erro
 	rb := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
 	defer rb.Release()
 
-	ts, _ := arrow.TimestampFromTime(r.opts.endTs, arrow.Nanosecond)
-	for _, entry := range r.aggregator.entries {
-		rb.Field(0).(*array.TimestampBuilder).Append(ts)
-		rb.Field(1).(*array.Int64Builder).Append(entry.count)
-
-		for col, val := range entry.labelValues {
-			builder := rb.Field(col + 2) // offset by 2 as the first 2 fields are timestamp and value
-			if val == "" {
-				builder.(*array.StringBuilder).AppendNull()
-			} else {
-				builder.(*array.StringBuilder).Append(val)
+	// emit aggregated results in sorted order of timestamp
+	for _, window := range r.aggregator.GetSortedTimestamps() {
+		ts, _ := arrow.TimestampFromTime(window, arrow.Nanosecond)
+		for _, entry := range r.aggregator.points[window] {
+			rb.Field(0).(*array.TimestampBuilder).Append(ts)
+			rb.Field(1).(*array.Float64Builder).Append(r.aggregator.result(entry, r.opts))
+
+			for col := range r.aggregator.fields {
+				builder := rb.Field(col + 2) // offset by 2 as the first 2 fields are timestamp and value
+				// entries created before a field was discovered have fewer label values
+				if col >= len(entry.labelValues) || entry.labelValues[col] == "" {
+					builder.(*array.StringBuilder).AppendNull()
+				} else {
+					builder.(*array.StringBuilder).Append(entry.labelValues[col])
+				}
 			}
 		}
 	}
@@ -212,6 +262,197 @@ func (r *RangeAggregationPipeline) read(ctx context.Context) (arrow.Record, erro
 	return rb.NewRecord(), nil
 }
 
+// windowsForTimestamp appends the evaluation timestamps of all steps whose
+// window (t-offset-range, t-offset] contains ts to windows and returns the
+// result.
+//
+// Steps are aligned to the start timestamp of the query. Instant queries
+// have a single step at the end timestamp.
+func (r *RangeAggregationPipeline) windowsForTimestamp(windows []time.Time, ts time.Time) []time.Time {
+	start, end, step := r.opts.startTs, r.opts.endTs, r.opts.step
+	if step <= 0 {
+		// instant query
+		start = end
+		step = 1
+	}
+
+	// Shifting ts by the offset allows to find the steps as if there was
+	// no offset.
+	ts = ts.Add(r.opts.offset)
+
+	// The first step that contains ts is the first step at or after ts.
+	// The last step that contains ts is the last step before ts+range.
+	first := ts
+	if first.Before(start) {
+		first = start
+	}
+	last := ts.Add(r.opts.rangeInterval - 1)
+	if last.After(end) {
+		last = end
+	}
+	if first.After(last) {
+		return windows
+	}
+
+	// align the first step
+	offset := first.Sub(start)
+	if rem := offset % step; rem != 0 {
+		offset += step - rem
+	}
+
+	for t := start.Add(offset); !t.After(last); t = t.Add(step) {
+		windows = append(windows, t)
+	}
+	return windows
+}
+
+// partitionArrays returns the string arrays of the record that are used for
+// partitioning, along with the index of the partition field of each array.
+//
+// If no partition columns are specified, all label, metadata and parsed
+// columns of the record are used, except the columns the aggregated value is
+// computed from. New columns are registered with the aggregator.
+func (r *RangeAggregationPipeline) partitionArrays(record arrow.Record) ([]*array.String, []int, error) {
+	if len(r.opts.partitionBy) > 0 {
+		arrays := make([]*array.String, 0, len(r.opts.partitionBy))
+		indexes := make([]int, 0, len(r.opts.partitionBy))
+		for i, columnExpr := range r.opts.partitionBy {
+			vec, err := r.evaluator.eval(columnExpr, record)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if vec.Type() != datatype.Loki.String {
+				return nil, nil, fmt.Errorf("unsupported datatype for partitioning %s", vec.Type())
+			}
+
+			arrays = append(arrays, vec.ToArray().(*array.String))
+			indexes = append(indexes, i)
+		}
+		return arrays, indexes, nil
+	}
+
+	var (
+		schema  = record.Schema()
+		arrays  = make([]*array.String, 0, schema.NumFields())
+		indexes = make([]int, 0, schema.NumFields())
+	)
+	for i, field := range schema.Fields() {
+		ct, ok := field.Metadata.GetValue(types.MetadataKeyColumnType)
+		if !ok {
+			continue
+		}
+
+		switch columnType := types.ColumnTypeFromString(ct); columnType {
+		case types.ColumnTypeLabel, types.ColumnTypeMetadata, types.ColumnTypeParsed:
+			if _, ok := r.valueColumns[field.Name]; ok {
+				continue
+			}
+
+			arr, ok := record.Column(i).(*array.String)
+			if !ok {
+				continue
+			}
+
+			arrays = append(arrays, arr)
+			indexes = append(indexes, r.aggregator.addField(field.Name, columnType))
+		}
+	}
+	return arrays, indexes, nil
+}
+
+// valueAccessor returns a function to access the value of a row of the
+// record. The function returns false if the row has no value.
+func (r *RangeAggregationPipeline) valueAccessor(record arrow.Record) (func(int) (float64, bool), error) {
+	if r.opts.value == nil {
+		return func(int) (float64, bool) { return 1, true }, nil
+	}
+
+	vec, err := r.evaluator.eval(r.opts.value, record)
+	if err != nil {
+		return nil, err
+	}
+
+	switch arr := vec.ToArray().(type) {
+	case *array.Float64:
+		return func(row int) (float64, bool) {
+			if arr.IsNull(row) {
+				return 0, false
+			}
+			return arr.Value(row), true
+		}, nil
+	case *array.Int64:
+		return func(row int) (float64, bool) {
+			if arr.IsNull(row) {
+				return 0, false
+			}
+			return float64(arr.Value(row)), true
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported datatype for range aggregation value %s", vec.Type())
+	}
+}
+
+// errorAccessor returns a function to access the error and error details of a
+// row of the record, which are empty if the row has no error.
+func errorAccessor(record arrow.Record) func(int) (string, string) {
+	var errs, details *array.String
+	for i, field := range record.Schema().Fields() {
+		ct, ok := field.Metadata.GetValue(types.MetadataKeyColumnType)
+		if !ok || ct != types.ColumnTypeParsed.String() {
+			continue
+		}
+		switch field.Name {
+		case logqlmodel.ErrorLabel:
+			errs, _ = record.Column(i).(*array.String)
+		case logqlmodel.ErrorDetailsLabel:
+			details, _ = record.Column(i).(*array.String)
+		}
+	}
+
+	return func(row int) (string, string) {
+		if errs == nil || errs.IsNull(row) {
+			return "", ""
+		}
+		if details == nil || details.IsNull(row) {
+			return errs.Value(row), ""
+		}
+		return errs.Value(row), details.Value(row)
+	}
+}
+
+// rowLabels returns the labels of the series of a row with an error, which
+// are reported by the pipeline error.
+func (r *RangeAggregationPipeline) rowLabels(arrays []*array.String, indexes []int, row int, errType, errDetails string) labels.Labels {
+	builder := labels.NewScratchBuilder(len(arrays) + 2)
+	for col, arr := range arrays {
+		name := r.aggregator.fields[indexes[col]].name
+		if arr.IsNull(row) || name == logqlmodel.ErrorLabel || name == logqlmodel.ErrorDetailsLabel {
+			continue
+		}
+		builder.Add(name, arr.Value(row))
+	}
+	builder.Add(logqlmodel.ErrorLabel, errType)
+	if errDetails != "" {
+		builder.Add(logqlmodel.ErrorDetailsLabel, errDetails)
+	}
+	builder.Sort()
+	return builder.Labels()
+}
+
+// collectColumnNames adds the names of all columns referenced by expr to names.
+func collectColumnNames(expr physical.Expression, names map[string]struct{}) {
+	switch expr := expr.(type) {
+	case *physical.ColumnExpr:
+		names[expr.Ref.Column] = struct{}{}
+	case *physical.UnaryExpr:
+		collectColumnNames(expr.Left, names)
+	case *physical.BinaryExpr:
+		collectColumnNames(expr.Left, names)
+		collectColumnNames(expr.Right, names)
+	}
+}
+
 // Value returns the current value in state.
 func (r *RangeAggregationPipeline) Value() (arrow.Record, error) {
 	return r.state.Value()
@@ -240,62 +481,226 @@ func (r *RangeAggregationPipeline) Transport() Transport {
 	return Local
 }
 
+type partitionField struct {
+	name string
+	ct   types.ColumnType
+}
+
 type partitionAggregator struct {
-	digest  *xxhash.Digest // used to compute key for each partition
-	entries map[uint64]*partitionEntry
+	operation types.RangeAggregationType
+	digest    *xxhash.Digest                           // used to compute key for each partition
+	points    map[time.Time]map[uint64]*partitionEntry // holds the partition entries for each step
+
+	fields  []partitionField // fields the data is partitioned by
+	indexes map[partitionField]int
 }
 
-func newPartitionAggregator() *partitionAggregator {
+func newPartitionAggregator(operation types.RangeAggregationType) *partitionAggregator {
 	return &partitionAggregator{
-		digest: xxhash.New(),
+		operation: operation,
+		digest:    xxhash.New(),
 		// TODO: estimate size during planning
-		entries: make(map[uint64]*partitionEntry),
+		points:  make(map[time.Time]map[uint64]*partitionEntry),
+		indexes: make(map[partitionField]int),
 	}
 }
 
 type partitionEntry struct {
-	count       int64
 	labelValues []string
+	rangeState
+}
+
+// addField registers a partition field and returns its index.
+// Registering an already known field returns the existing index.
+func (a *partitionAggregator) addField(name string, ct types.ColumnType) int {
+	field := partitionField{name: name, ct: ct}
+	if idx, ok := a.indexes[field]; ok {
+		return idx
+	}
+
+	idx := len(a.fields)
+	a.fields = append(a.fields, field)
+	a.indexes[field] = idx
+	return idx
 }
 
-func (a *partitionAggregator) Add(partitionLabelValues []string) {
+// Add adds the value of an entry at ts to the partition of each of the given
+// step windows.
+func (a *partitionAggregator) Add(windows []time.Time, partitionLabelValues []string, ts time.Time, value float64) {
 	a.digest.Reset()
 
 	for i, val := range partitionLabelValues {
-		if i > 0 {
-			_, _ = a.digest.Write([]byte{0}) // separator for label values
+		// Empty values are skipped, so that the key of a partition does not
+		// change when new fields are discovered.
+		if val == "" {
+			continue
 		}
 
+		_, _ = a.digest.Write(binary.LittleEndian.AppendUint32(nil, uint32(i)))
 		_, _ = a.digest.WriteString(val)
+		_, _ = a.digest.Write([]byte{0}) // separator for label values
 	}
 
 	key := a.digest.Sum64()
-	if entry, ok := a.entries[key]; ok {
-		// TODO: handle hash collisions
-		entry.count++
-	} else {
-		// create a new slice since partitionLabelValues is reused by the calling code
-		labelValues := make([]string, len(partitionLabelValues))
-		for i, v := range partitionLabelValues {
-			// copy the value as this is backed by the arrow array data buffer.
-			// We could retain the record to avoid this copy, but that would hold
-			// all other columns in memory for as long as the query is evaluated.
-			labelValues[i] = strings.Clone(v)
+
+	var labelValues []string // shared by the entries of all windows
+	for _, window := range windows {
+		point, ok := a.points[window]
+		if !ok {
+			point = make(map[uint64]*partitionEntry)
+			a.points[window] = point
+		}
+
+		entry, ok := point[key]
+		if !ok {
+			if labelValues == nil {
+				// create a new slice since partitionLabelValues is reused by the calling code
+				labelValues = make([]string, len(partitionLabelValues))
+				for i, v := range partitionLabelValues {
+					// copy the value as this is backed by the arrow array data buffer.
+					// We could retain the record to avoid this copy, but that would hold
+					// all other columns in memory for as long as the query is evaluated.
+					labelValues[i] = strings.Clone(v)
+				}
+			}
+
+			// TODO: add limits on number of partitions
+			entry = &partitionEntry{labelValues: labelValues}
+			point[key] = entry
 		}
 
-		// TODO: add limits on number of partitions
-		a.entries[key] = &partitionEntry{
-			labelValues: labelValues,
-			count:       1,
+		// TODO: handle hash collisions
+		entry.add(ts, value, a.operation == types.RangeAggregationTypeQuantile)
+	}
+}
+
+// result returns the aggregated value of the partition entry.
+func (a *partitionAggregator) result(entry *partitionEntry, opts rangeAggregationOptions) float64 {
+	seconds := opts.rangeInterval.Seconds()
+
+	switch a.operation {
+	case types.RangeAggregationTypeCount:
+		return float64(entry.count)
+	case types.RangeAggregationTypeRate:
+		if opts.value != nil {
+			return entry.sum / seconds
 		}
+		return float64(entry.count) / seconds
+	case types.RangeAggregationTypeBytes, types.RangeAggregationTypeSum:
+		return entry.sum
+	case types.RangeAggregationTypeBytesRate:
+		return entry.sum / seconds
+	case types.RangeAggregationTypeAvg:
+		return entry.mean
+	case types.RangeAggregationTypeMin:
+		return entry.min
+	case types.RangeAggregationTypeMax:
+		return entry.max
+	case types.RangeAggregationTypeFirst:
+		return entry.first
+	case types.RangeAggregationTypeLast:
+		return entry.last
+	case types.RangeAggregationTypeStdDev:
+		return math.Sqrt(entry.m2 / float64(entry.count))
+	case types.RangeAggregationTypeStdVar:
+		return entry.m2 / float64(entry.count)
+	case types.RangeAggregationTypeQuantile:
+		return quantile(opts.parameter, entry.values)
+	default:
+		panic(fmt.Sprintf("unsupported range aggregation type %s", a.operation))
 	}
 }
 
 func (a *partitionAggregator) Reset() {
 	a.digest.Reset()
-	clear(a.entries)
+	clear(a.points)
+}
+
+func (a *partitionAggregator) NumOfPoints() int {
+	return len(a.points)
+}
+
+// GetSortedTimestamps returns the timestamps of all steps in sorted order.
+func (a *partitionAggregator) GetSortedTimestamps() []time.Time {
+	return slices.SortedFunc(maps.Keys(a.points), func(a, b time.Time) int {
+		return a.Compare(b)
+	})
 }
 
-func (a *partitionAggregator) NumOfPartitions() int {
-	return len(a.entries)
+func (a *partitionAggregator) NumOfFields() int {
+	return len(a.fields)
+}
+
+// rangeState holds the running state of all supported range aggregations
+// over the samples of a single partition.
+type rangeState struct {
+	count int64
+	sum   float64
+
+	min, max float64
+
+	first, last     float64
+	firstTs, lastTs time.Time
+
+	// mean and m2 are computed using Welford's online algorithm.
+	mean, m2 float64
+
+	values []float64 // only collected for quantiles
+}
+
+func (s *rangeState) add(ts time.Time, value float64, keepValues bool) {
+	s.count++
+	s.sum += value
+
+	if s.count == 1 {
+		s.min, s.max = value, value
+		s.first, s.firstTs = value, ts
+		s.last, s.lastTs = value, ts
+	} else {
+		if value < s.min || math.IsNaN(s.min) {
+			s.min = value
+		}
+		if value > s.max || math.IsNaN(s.max) {
+			s.max = value
+		}
+		if ts.Before(s.firstTs) {
+			s.first, s.firstTs = value, ts
+		}
+		if !ts.Before(s.lastTs) {
+			s.last, s.lastTs = value, ts
+		}
+	}
+
+	delta := value - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (value - s.mean)
+
+	if keepValues {
+		s.values = append(s.values, value)
+	}
+}
+
+// quantile calculates the q-quantile of the given values using linear
+// interpolation between the closest ranks, like [logql.Quantile].
+func quantile(q float64, values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	if q < 0 {
+		return math.Inf(-1)
+	}
+	if q > 1 {
+		return math.Inf(+1)
+	}
+
+	slices.Sort(values)
+
+	n := float64(len(values))
+	rank := q * (n - 1)
+
+	lowerIndex := math.Max(0, math.Floor(rank))
+	upperIndex := math.Min(n-1, lowerIndex+1)
+
+	weight := rank - math.Floor(rank)
+	return values[int(lowerIndex)]*(1-weight) + values[int(upperIndex)]*weight
 }
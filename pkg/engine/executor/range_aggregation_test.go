@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
 	"github.com/grafana/loki/v3/pkg/engine/internal/types"
 	"github.com/grafana/loki/v3/pkg/engine/planner/physical"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
 )
 
 const arrowTimestampFormat = "2006-01-02T15:04:05.000000000Z"
@@ -29,11 +30,11 @@ func TestRangeAggregationPipeline(t *testing.T) {
 	// test data for first input
 	now := time.Now().UTC()
 	input1CSV := strings.Join([]string{
-		fmt.Sprintf("%s,prod,app1,error", now.Format(arrowTimestampFormat)), // excluded, falls on the open interval
+		fmt.Sprintf("%s,prod,app1,error", now.Format(arrowTimestampFormat)), // included, falls on the closed interval
 		fmt.Sprintf("%s,prod,app1,info", now.Add(-5*time.Minute).Format(arrowTimestampFormat)),
 		fmt.Sprintf("%s,prod,app1,error", now.Add(-5*time.Minute).Format(arrowTimestampFormat)),
-		fmt.Sprintf("%s,prod,app2,error", now.Add(-10*time.Minute).Format(arrowTimestampFormat)), // included, falls on closed interval
-		fmt.Sprintf("%s,dev,,error", now.Add(-10*time.Minute).Format(arrowTimestampFormat)),
+		fmt.Sprintf("%s,prod,app2,error", now.Add(-10*time.Minute).Format(arrowTimestampFormat)), // excluded, falls on the open interval
+		fmt.Sprintf("%s,dev,,error", now.Add(-9*time.Minute).Format(arrowTimestampFormat)),
 	}, "\n")
 
 	// test data for second input
@@ -95,20 +96,20 @@ func TestRangeAggregationPipeline(t *testing.T) {
 	defer record.Release()
 
 	// Define expected results
-	expected := map[string]int64{
-		"prod,app1": 2,
-		"prod,app2": 3,
-		"prod,app3": 2,
+	expected := map[string]float64{
+		"prod,app1": 3,
+		"prod,app2": 1,
+		"prod,app3": 1,
 		"dev,":      1,
 	}
 
 	require.Equal(t, int64(len(expected)), record.NumRows(), "number of records should match")
 
-	actual := make(map[string]int64)
+	actual := make(map[string]float64)
 	for i := range int(record.NumRows()) {
 		require.Equal(t, record.Column(0).(*array.Timestamp).Value(i).ToTime(arrow.Nanosecond), now)
 
-		value := record.Column(1).(*array.Int64).Value(i)
+		value := record.Column(1).(*array.Float64).Value(i)
 		env := record.Column(2).(*array.String).Value(i)
 		service := record.Column(3).(*array.String).Value(i)
 		key := fmt.Sprintf("%s,%s", env, service)
@@ -117,3 +118,247 @@ func TestRangeAggregationPipeline(t *testing.T) {
 
 	require.EqualValues(t, expected, actual, "aggregation results should match")
 }
+
+func TestRangeAggregationPipeline_Operations(t *testing.T) {
+	fields := []arrow.Field{
+		{Name: types.ColumnNameBuiltinTimestamp, Type: datatype.Arrow.Timestamp, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
+		{Name: "service", Type: datatype.Arrow.String, Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)},
+		{Name: "latency", Type: datatype.Arrow.String, Metadata: datatype.ColumnMetadata(types.ColumnTypeParsed, datatype.Loki.String)},
+	}
+
+	now := time.Now().UTC()
+	inputCSV := strings.Join([]string{
+		fmt.Sprintf("%s,app1,4", now.Add(-4*time.Second).Format(arrowTimestampFormat)),
+		fmt.Sprintf("%s,app1,1", now.Add(-3*time.Second).Format(arrowTimestampFormat)),
+		fmt.Sprintf("%s,app1,", now.Add(-2*time.Second).Format(arrowTimestampFormat)), // skipped, no value
+		fmt.Sprintf("%s,app1,3", now.Add(-1*time.Second).Format(arrowTimestampFormat)),
+		fmt.Sprintf("%s,app2,2", now.Add(-3*time.Second).Format(arrowTimestampFormat)),
+	}, "\n")
+
+	unwrap := &physical.UnaryExpr{
+		Op:   types.UnaryOpCastFloat,
+		Left: &physical.ColumnExpr{Ref: types.ColumnRef{Column: "latency", Type: types.ColumnTypeAmbiguous}},
+	}
+
+	// partition by service only for aggregations without unwrap, as the
+	// latency column would otherwise be part of the series labels.
+	byService := []physical.ColumnExpression{
+		&physical.ColumnExpr{Ref: types.ColumnRef{Column: "service", Type: types.ColumnTypeAmbiguous}},
+	}
+
+	for _, tt := range []struct {
+		operation   types.RangeAggregationType
+		partitionBy []physical.ColumnExpression
+		value       physical.Expression
+		parameter   float64
+		expected    map[string]float64
+	}{
+		{operation: types.RangeAggregationTypeCount, partitionBy: byService, expected: map[string]float64{"app1": 4, "app2": 1}},
+		{operation: types.RangeAggregationTypeRate, partitionBy: byService, expected: map[string]float64{"app1": 0.4, "app2": 0.1}},
+		{operation: types.RangeAggregationTypeRate, value: unwrap, expected: map[string]float64{"app1": 0.8, "app2": 0.2}},
+		{operation: types.RangeAggregationTypeSum, value: unwrap, expected: map[string]float64{"app1": 8, "app2": 2}},
+		{operation: types.RangeAggregationTypeAvg, value: unwrap, expected: map[string]float64{"app1": 8.0 / 3, "app2": 2}},
+		{operation: types.RangeAggregationTypeMin, value: unwrap, expected: map[string]float64{"app1": 1, "app2": 2}},
+		{operation: types.RangeAggregationTypeMax, value: unwrap, expected: map[string]float64{"app1": 4, "app2": 2}},
+		{operation: types.RangeAggregationTypeFirst, value: unwrap, expected: map[string]float64{"app1": 4, "app2": 2}},
+		{operation: types.RangeAggregationTypeLast, value: unwrap, expected: map[string]float64{"app1": 3, "app2": 2}},
+		{operation: types.RangeAggregationTypeStdVar, value: unwrap, expected: map[string]float64{"app1": 14.0 / 9, "app2": 0}},
+		{operation: types.RangeAggregationTypeQuantile, value: unwrap, parameter: 0.5, expected: map[string]float64{"app1": 3, "app2": 2}},
+		{
+			operation: types.RangeAggregationTypeBytes,
+			value:     &physical.UnaryExpr{Op: types.UnaryOpLength, Left: &physical.ColumnExpr{Ref: types.ColumnRef{Column: "latency", Type: types.ColumnTypeAmbiguous}}},
+			expected:  map[string]float64{"app1": 3, "app2": 1},
+		},
+	} {
+		t.Run(tt.operation.String(), func(t *testing.T) {
+			record, err := CSVToArrow(fields, inputCSV)
+			require.NoError(t, err)
+			defer record.Release()
+
+			// without partitionBy columns the data is partitioned by all
+			// labels except the unwrapped column.
+			pipeline, err := NewRangeAggregationPipeline([]Pipeline{NewBufferedPipeline(record)}, expressionEvaluator{}, rangeAggregationOptions{
+				partitionBy:   tt.partitionBy,
+				operation:     tt.operation,
+				value:         tt.value,
+				parameter:     tt.parameter,
+				startTs:       now,
+				endTs:         now,
+				rangeInterval: 10 * time.Second,
+			})
+			require.NoError(t, err)
+			defer pipeline.Close()
+
+			require.NoError(t, pipeline.Read(t.Context()))
+			result, err := pipeline.Value()
+			require.NoError(t, err)
+			defer result.Release()
+
+			actual := make(map[string]float64)
+			for i := range int(result.NumRows()) {
+				value := result.Column(1).(*array.Float64).Value(i)
+				idx := result.Schema().FieldIndices("service")
+				require.Len(t, idx, 1)
+				actual[result.Column(idx[0]).(*array.String).Value(i)] = value
+			}
+
+			require.Len(t, actual, len(tt.expected))
+			for k, v := range tt.expected {
+				require.InDelta(t, v, actual[k], 1e-9, k)
+			}
+		})
+	}
+}
+
+func TestRangeAggregationPipeline_UnwrapErrors(t *testing.T) {
+	fields := []arrow.Field{
+		{Name: types.ColumnNameBuiltinTimestamp, Type: datatype.Arrow.Timestamp, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
+		{Name: "service", Type: datatype.Arrow.String, Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)},
+		{Name: types.ColumnNameGeneratedUnwrapped, Type: datatype.Arrow.Float, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Float)},
+		{Name: logqlmodel.ErrorLabel, Type: datatype.Arrow.String, Nullable: true, Metadata: datatype.ColumnMetadata(types.ColumnTypeParsed, datatype.Loki.String)},
+	}
+
+	now := time.Now().UTC()
+	unwrapped := &physical.ColumnExpr{Ref: types.ColumnRef{Column: types.ColumnNameGeneratedUnwrapped, Type: types.ColumnTypeGenerated}}
+
+	for _, tt := range []struct {
+		name      string
+		inputCSV  string
+		expectErr bool
+	}{
+		{
+			name: "rows without value are skipped",
+			inputCSV: strings.Join([]string{
+				fmt.Sprintf("%s,app1,4,", now.Add(-2*time.Second).Format(arrowTimestampFormat)),
+				fmt.Sprintf("%s,app1,,", now.Add(-1*time.Second).Format(arrowTimestampFormat)),
+			}, "\n"),
+		},
+		{
+			name: "rows with conversion errors fail the query",
+			inputCSV: strings.Join([]string{
+				fmt.Sprintf("%s,app1,4,", now.Add(-2*time.Second).Format(arrowTimestampFormat)),
+				fmt.Sprintf("%s,app1,,SampleExtractionErr", now.Add(-1*time.Second).Format(arrowTimestampFormat)),
+			}, "\n"),
+			expectErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := CSVToArrow(fields, tt.inputCSV)
+			require.NoError(t, err)
+			defer record.Release()
+
+			pipeline, err := NewRangeAggregationPipeline([]Pipeline{NewBufferedPipeline(record)}, expressionEvaluator{}, rangeAggregationOptions{
+				operation:     types.RangeAggregationTypeSum,
+				value:         unwrapped,
+				startTs:       now,
+				endTs:         now,
+				rangeInterval: 10 * time.Second,
+			})
+			require.NoError(t, err)
+			defer pipeline.Close()
+
+			err = pipeline.Read(t.Context())
+			if tt.expectErr {
+				require.ErrorIs(t, err, logqlmodel.ErrPipeline)
+				require.ErrorContains(t, err, "SampleExtractionErr")
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRangeAggregationPipeline_Steps(t *testing.T) {
+	fields := []arrow.Field{
+		{Name: types.ColumnNameBuiltinTimestamp, Type: datatype.Arrow.Timestamp, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
+		{Name: "env", Type: datatype.Arrow.String, Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)},
+	}
+
+	start := time.Unix(1000, 0).UTC()
+	inputCSV := strings.Join([]string{
+		fmt.Sprintf("%s,prod", start.Add(-30*time.Second).Format(arrowTimestampFormat)),
+		fmt.Sprintf("%s,prod", start.Format(arrowTimestampFormat)),
+		fmt.Sprintf("%s,prod", start.Add(30*time.Second).Format(arrowTimestampFormat)),
+		fmt.Sprintf("%s,prod", start.Add(45*time.Second).Format(arrowTimestampFormat)),
+		fmt.Sprintf("%s,prod", start.Add(2*time.Minute).Format(arrowTimestampFormat)),
+		fmt.Sprintf("%s,dev", start.Add(90*time.Second).Format(arrowTimestampFormat)),
+	}, "\n")
+
+	for _, tt := range []struct {
+		name          string
+		step          time.Duration
+		rangeInterval time.Duration
+		offset        time.Duration
+		expected      map[time.Time]map[string]float64
+	}{
+		{
+			// windows overlap, entries are counted in multiple steps
+			name:          "step smaller than range",
+			step:          30 * time.Second,
+			rangeInterval: time.Minute,
+			expected: map[time.Time]map[string]float64{
+				start:                       {"prod": 2},           // (-60s, 0s]
+				start.Add(30 * time.Second): {"prod": 2},           // (-30s, 30s]
+				start.Add(60 * time.Second): {"prod": 2},           // (0s, 60s]
+				start.Add(90 * time.Second): {"prod": 1, "dev": 1}, // (30s, 90s]
+				start.Add(2 * time.Minute):  {"prod": 1, "dev": 1}, // (60s, 120s]
+			},
+		},
+		{
+			// windows have gaps, entries in gaps are not counted
+			name:          "step larger than range",
+			step:          time.Minute,
+			rangeInterval: 20 * time.Second,
+			expected: map[time.Time]map[string]float64{
+				start:                      {"prod": 1}, // (-20s, 0s]
+				start.Add(time.Minute):     {"prod": 1}, // (40s, 60s]
+				start.Add(2 * time.Minute): {"prod": 1}, // (100s, 120s]
+			},
+		},
+		{
+			// windows are shifted back by the offset
+			name:          "offset",
+			step:          time.Minute,
+			rangeInterval: time.Minute,
+			offset:        30 * time.Second,
+			expected: map[time.Time]map[string]float64{
+				start:                      {"prod": 1},           // (-90s, -30s]
+				start.Add(time.Minute):     {"prod": 2},           // (-30s, 30s]
+				start.Add(2 * time.Minute): {"prod": 1, "dev": 1}, // (30s, 90s]
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := CSVToArrow(fields, inputCSV)
+			require.NoError(t, err)
+			defer record.Release()
+
+			pipeline, err := NewRangeAggregationPipeline([]Pipeline{NewBufferedPipeline(record)}, expressionEvaluator{}, rangeAggregationOptions{
+				operation:     types.RangeAggregationTypeCount,
+				startTs:       start,
+				endTs:         start.Add(2 * time.Minute),
+				step:          tt.step,
+				rangeInterval: tt.rangeInterval,
+				offset:        tt.offset,
+			})
+			require.NoError(t, err)
+			defer pipeline.Close()
+
+			require.NoError(t, pipeline.Read(t.Context()))
+			result, err := pipeline.Value()
+			require.NoError(t, err)
+			defer result.Release()
+
+			actual := make(map[time.Time]map[string]float64)
+			for i := range int(result.NumRows()) {
+				ts := result.Column(0).(*array.Timestamp).Value(i).ToTime(arrow.Nanosecond)
+				if _, ok := actual[ts]; !ok {
+					actual[ts] = make(map[string]float64)
+				}
+				actual[ts][result.Column(2).(*array.String).Value(i)] = result.Column(1).(*array.Float64).Value(i)
+			}
+
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
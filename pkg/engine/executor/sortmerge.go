@@ -246,9 +246,14 @@ func sortIndexesByTimestamps(indexes []int, timestamps []int64, lessFn compareFu
 		pairs[i] = inputTimestampPair{indexes[i], timestamps[i]}
 	}
 
-	// Sort pairs by timestamp
+	// Sort pairs by timestamp. lessFn also returns true for equal timestamps,
+	// so it needs to be turned into a strict ordering, otherwise the sort is not
+	// stable and inputs with equal timestamps are not ordered by their index.
+	// A consistent order of equal timestamps makes sure that the limit always
+	// returns the same entries if it cuts through entries with the same
+	// timestamp.
 	sort.SliceStable(pairs, func(i, j int) bool {
-		return lessFn(pairs[i].timestamp, pairs[j].timestamp)
+		return lessFn(pairs[i].timestamp, pairs[j].timestamp) && !lessFn(pairs[j].timestamp, pairs[i].timestamp)
 	})
 
 	// Unpack the sorted pairs back into the original slices
@@ -1,6 +1,8 @@
 package executor
 
 import (
+	"errors"
+	"fmt"
 	"slices"
 	"testing"
 	"time"
@@ -9,8 +11,10 @@ import (
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
 	"github.com/grafana/loki/v3/pkg/engine/internal/types"
 	"github.com/grafana/loki/v3/pkg/engine/planner/physical"
+	"github.com/grafana/loki/v3/pkg/util/arrowtest"
 )
 
 func TestSortMerge(t *testing.T) {
@@ -142,3 +146,84 @@ func TestSortMerge(t *testing.T) {
 			"timestamps are not sorted in DESC order: %v", timestamps)
 	})
 }
+
+func TestSortMerge_AscendingWithLimit(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: types.ColumnNameBuiltinTimestamp, Type: datatype.Arrow.Timestamp, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
+		{Name: types.ColumnNameBuiltinMessage, Type: datatype.Arrow.String, Metadata: datatype.ColumnMetadataBuiltinMessage},
+	}, nil)
+
+	var (
+		t1 = time.Unix(10, 0).UTC()
+		t2 = time.Unix(20, 0).UTC()
+		t3 = time.Unix(30, 0).UTC()
+	)
+
+	column := &physical.ColumnExpr{
+		Ref: types.ColumnRef{Column: types.ColumnNameBuiltinTimestamp, Type: types.ColumnTypeBuiltin},
+	}
+
+	// Entries with the same timestamp are spread across batches and inputs,
+	// so the limit cuts through a group of entries with equal timestamps.
+	newInputs := func() []Pipeline {
+		return []Pipeline{
+			NewArrowtestPipeline(nil, schema,
+				arrowtest.Rows{
+					{types.ColumnNameBuiltinTimestamp: t1, types.ColumnNameBuiltinMessage: "a1"},
+					{types.ColumnNameBuiltinTimestamp: t2, types.ColumnNameBuiltinMessage: "a2"},
+				},
+				arrowtest.Rows{
+					{types.ColumnNameBuiltinTimestamp: t2, types.ColumnNameBuiltinMessage: "a3"},
+					{types.ColumnNameBuiltinTimestamp: t3, types.ColumnNameBuiltinMessage: "a4"},
+				},
+			),
+			NewArrowtestPipeline(nil, schema,
+				arrowtest.Rows{
+					{types.ColumnNameBuiltinTimestamp: t2, types.ColumnNameBuiltinMessage: "b1"},
+					{types.ColumnNameBuiltinTimestamp: t3, types.ColumnNameBuiltinMessage: "b2"},
+				},
+			),
+		}
+	}
+
+	for _, tt := range []struct {
+		limit    uint32
+		expected []string
+	}{
+		{limit: 1, expected: []string{"a1"}},
+		{limit: 3, expected: []string{"a1", "a2", "a3"}},
+		{limit: 4, expected: []string{"a1", "a2", "a3", "b1"}},
+		{limit: 10, expected: []string{"a1", "a2", "a3", "b1", "b2", "a4"}},
+	} {
+		t.Run(fmt.Sprintf("limit=%d", tt.limit), func(t *testing.T) {
+			merge, err := NewSortMergePipeline(newInputs(), physical.ASC, column, expressionEvaluator{})
+			require.NoError(t, err)
+
+			pipeline := NewLimitPipeline(merge, 0, tt.limit)
+			defer pipeline.Close()
+
+			var (
+				messages   []string
+				timestamps []time.Time
+			)
+			for {
+				err := pipeline.Read(t.Context())
+				if errors.Is(err, EOF) {
+					break
+				}
+				require.NoError(t, err)
+
+				batch, _ := pipeline.Value()
+				rows, err := arrowtest.RecordRows(batch)
+				require.NoError(t, err)
+				for _, row := range rows {
+					messages = append(messages, row[types.ColumnNameBuiltinMessage].(string))
+					timestamps = append(timestamps, row[types.ColumnNameBuiltinTimestamp].(time.Time))
+				}
+			}
+
+			require.Equal(t, tt.expected, messages)
+			require.True(t, slices.IsSortedFunc(timestamps, func(a, b time.Time) int { return a.Compare(b) }))
+		})
+	}
+}
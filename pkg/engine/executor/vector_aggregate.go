@@ -1,10 +1,13 @@
 package executor
 
 import (
+	"container/heap"
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"maps"
+	"math"
 	"slices"
 	"strings"
 	"time"
@@ -19,32 +22,51 @@ import (
 	"github.com/grafana/loki/v3/pkg/engine/planner/physical"
 )
 
+type vectorAggregationOptions struct {
+	groupBy []physical.ColumnExpression
+	without bool // group by all columns except the groupBy columns
+
+	operation types.VectorAggregationType
+	parameter int // parameter of the aggregation, such as k for topk
+}
+
 // VectorAggregationPipeline is a pipeline that performs vector aggregations.
 //
 // It reads from the input pipeline, groups the data by specified columns,
 // and applies the aggregation function on each group.
+//
+// Like in the legacy engine, the labels of a row are the union of all its
+// string columns, where columns with the same name are resolved by the
+// precedence of their column type.
 type VectorAggregationPipeline struct {
 	state  state
 	inputs []Pipeline
 
 	aggregator *vectorAggregator
 	evaluator  expressionEvaluator
-	groupBy    []physical.ColumnExpression
 
 	tsEval    evalFunc // used to evaluate the timestamp column
 	valueEval evalFunc // used to evaluate the value column
 }
 
-func NewVectorAggregationPipeline(inputs []Pipeline, groupBy []physical.ColumnExpression, evaluator expressionEvaluator) (*VectorAggregationPipeline, error) {
+func NewVectorAggregationPipeline(inputs []Pipeline, evaluator expressionEvaluator, opts vectorAggregationOptions) (*VectorAggregationPipeline, error) {
 	if len(inputs) == 0 {
 		return nil, fmt.Errorf("vector aggregation expects at least one input")
 	}
 
+	if opts.operation == types.VectorAggregationTypeInvalid {
+		opts.operation = types.VectorAggregationTypeSum
+	}
+
+	aggregator, err := newVectorAggregator(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	return &VectorAggregationPipeline{
 		inputs:     inputs,
 		evaluator:  evaluator,
-		groupBy:    groupBy,
-		aggregator: newVectorAggregator(groupBy),
+		aggregator: aggregator,
 		tsEval: evaluator.newFunc(&physical.ColumnExpr{
 			Ref: types.ColumnRef{
 				Column: types.ColumnNameBuiltinTimestamp,
@@ -82,7 +104,7 @@ func (v *VectorAggregationPipeline) Read(ctx context.Context) error {
 
 func (v *VectorAggregationPipeline) read(ctx context.Context) (arrow.Record, error) {
 	var (
-		labelValues = make([]string, len(v.groupBy))
+		labelValues []string
 	)
 
 	v.aggregator.Reset() // reset before reading new inputs
@@ -114,28 +136,25 @@ func (v *VectorAggregationPipeline) read(ctx context.Context) (arrow.Record, err
 			if err != nil {
 				return nil, err
 			}
-			valueArr := valueVec.ToArray().(*array.Int64)
-
-			// extract all the columns that are used for grouping
-			arrays := make([]*array.String, 0, len(v.groupBy))
-			for _, columnExpr := range v.groupBy {
-				vec, err := v.evaluator.eval(columnExpr, record)
-				if err != nil {
-					return nil, err
-				}
-
-				if vec.Type() != datatype.Loki.String {
-					return nil, fmt.Errorf("unsupported datatype for grouping %s", vec.Type())
-				}
-
-				arrays = append(arrays, vec.ToArray().(*array.String))
+			valueArr, ok := valueVec.ToArray().(*array.Float64)
+			if !ok {
+				return nil, fmt.Errorf("unsupported datatype for aggregation value %s", valueVec.Type())
 			}
 
+			// extract all the columns that make up the labels of a row
+			columns := v.aggregator.labelColumns(record)
+
+			labelValues = slices.Grow(labelValues[:0], v.aggregator.NumOfFields())[:v.aggregator.NumOfFields()]
 			for row := range int(record.NumRows()) {
+				// skip rows without a value
+				if valueArr.IsNull(row) {
+					continue
+				}
+
 				// reset for each row
 				clear(labelValues)
-				for col, arr := range arrays {
-					labelValues[col] = arr.Value(row)
+				for _, col := range columns {
+					labelValues[col.field] = col.value(row)
 				}
 
 				v.aggregator.Add(tsCol.Value(row).ToTime(arrow.Nanosecond), valueArr.Value(row), labelValues)
@@ -176,26 +195,158 @@ func (v *VectorAggregationPipeline) Transport() Transport {
 	return Local
 }
 
+// labelColumn holds all string columns of a record with the same name,
+// ordered by the precedence of their column type.
+type labelColumn struct {
+	field       int // index of the label field in the aggregator
+	arrays      []*array.String
+	precedences []int // precedence of the column type of each array
+}
+
+// value returns the value of the column with the highest precedence that is
+// not null.
+func (c labelColumn) value(row int) string {
+	for _, arr := range c.arrays {
+		if !arr.IsNull(row) {
+			return arr.Value(row)
+		}
+	}
+	return ""
+}
+
+type labelField struct {
+	name    string
+	ct      types.ColumnType
+	grouped bool // whether the field is part of the grouping key
+}
+
+type sample struct {
+	value       float64
+	labelValues []string
+}
+
 type groupState struct {
-	sum         int64
+	count int64
+	value float64 // sum, min or max, depending on the operation
+
+	// mean and m2 are computed using Welford's online algorithm.
+	mean, m2 float64
+
+	samples     *sampleHeap // only used for topk, bottomk, sort and sort_desc
 	labelValues []string
 }
 
 type vectorAggregator struct {
-	groupBy []physical.ColumnExpression          // columns to group by
-	digest  *xxhash.Digest                       // used to compute key for each group
-	points  map[time.Time]map[uint64]*groupState // holds the groupState for each point in time series
+	opts vectorAggregationOptions
+
+	fields   []labelField                         // all label fields seen in the input
+	indexes  map[string]int                       // maps the name of a label field to its index in fields
+	excluded map[string]struct{}                  // labels excluded from the grouping key when grouping without labels
+	digest   *xxhash.Digest                       // used to compute key for each group
+	points   map[time.Time]map[uint64]*groupState // holds the groupState for each point in time series
+}
+
+func newVectorAggregator(opts vectorAggregationOptions) (*vectorAggregator, error) {
+	a := &vectorAggregator{
+		opts:     opts,
+		indexes:  make(map[string]int),
+		excluded: make(map[string]struct{}),
+		digest:   xxhash.New(),
+		points:   make(map[time.Time]map[uint64]*groupState),
+	}
+
+	for _, column := range opts.groupBy {
+		colExpr, ok := column.(*physical.ColumnExpr)
+		if !ok {
+			return nil, fmt.Errorf("invalid column expression type %T", column)
+		}
+
+		if opts.without {
+			a.excluded[colExpr.Ref.Column] = struct{}{}
+			continue
+		}
+		// Fields of the grouping labels are registered first, so their
+		// order in the output matches the order of the grouping labels.
+		a.addField(colExpr.Ref.Column, colExpr.Ref.Type)
+	}
+
+	return a, nil
 }
 
-func newVectorAggregator(groupBy []physical.ColumnExpression) *vectorAggregator {
-	return &vectorAggregator{
-		groupBy: groupBy,
-		digest:  xxhash.New(),
-		points:  make(map[time.Time]map[uint64]*groupState),
+// addField registers a label field and returns its index.
+// Registering an already known field returns the existing index.
+func (a *vectorAggregator) addField(name string, ct types.ColumnType) int {
+	if idx, ok := a.indexes[name]; ok {
+		return idx
+	}
+
+	grouped := false
+	if a.opts.without {
+		_, excluded := a.excluded[name]
+		grouped = !excluded
+	} else {
+		grouped = slices.ContainsFunc(a.opts.groupBy, func(column physical.ColumnExpression) bool {
+			return column.(*physical.ColumnExpr).Ref.Column == name
+		})
+	}
+
+	idx := len(a.fields)
+	a.fields = append(a.fields, labelField{name: name, ct: ct, grouped: grouped})
+	a.indexes[name] = idx
+	return idx
+}
+
+// labelColumns returns the label columns of the record. All string columns
+// except builtin and generated columns are considered labels.
+//
+// If the result of the aggregation only contains the grouping labels,
+// columns that are not part of the grouping key are ignored.
+func (a *vectorAggregator) labelColumns(record arrow.Record) []labelColumn {
+	var (
+		schema  = record.Schema()
+		columns = make([]labelColumn, 0, schema.NumFields())
+		byName  = make(map[string]int, schema.NumFields())
+	)
+
+	for i, field := range schema.Fields() {
+		arr, ok := record.Column(i).(*array.String)
+		if !ok {
+			continue
+		}
+
+		ct := types.ColumnTypeAmbiguous
+		if value, ok := field.Metadata.GetValue(types.MetadataKeyColumnType); ok {
+			ct = types.ColumnTypeFromString(value)
+		}
+		if ct == types.ColumnTypeBuiltin || ct == types.ColumnTypeGenerated {
+			continue
+		}
+
+		if a.opts.operation.IsGrouping() && !a.opts.without {
+			if _, ok := a.indexes[field.Name]; !ok {
+				continue
+			}
+		}
+
+		idx, ok := byName[field.Name]
+		if !ok {
+			idx = len(columns)
+			byName[field.Name] = idx
+			columns = append(columns, labelColumn{field: a.addField(field.Name, ct)})
+		}
+
+		// keep arrays of columns with the same name ordered by precedence
+		col := &columns[idx]
+		precedence := types.ColumnTypePrecedence(ct)
+		pos, _ := slices.BinarySearch(col.precedences, precedence)
+		col.arrays = slices.Insert(col.arrays, pos, arr)
+		col.precedences = slices.Insert(col.precedences, pos, precedence)
 	}
+
+	return columns
 }
 
-func (a *vectorAggregator) Add(ts time.Time, value int64, labelValues []string) {
+func (a *vectorAggregator) Add(ts time.Time, value float64, labelValues []string) {
 	point, ok := a.points[ts]
 	if !ok {
 		point = make(map[uint64]*groupState)
@@ -204,37 +355,112 @@ func (a *vectorAggregator) Add(ts time.Time, value int64, labelValues []string)
 
 	a.digest.Reset()
 	for i, val := range labelValues {
-		if i > 0 {
-			_, _ = a.digest.Write([]byte{0}) // separator
+		// Empty values are skipped, so that the key of a group does not
+		// change when new fields are discovered.
+		if val == "" || !a.fields[i].grouped {
+			continue
 		}
 
+		_, _ = a.digest.Write(binary.LittleEndian.AppendUint32(nil, uint32(i)))
 		_, _ = a.digest.WriteString(val)
+		_, _ = a.digest.Write([]byte{0}) // separator
 	}
 	key := a.digest.Sum64()
 
-	if state, ok := point[key]; ok {
-		// TODO: handle hash collisions
-		state.sum += value
-	} else {
-		// create a new slice since labelValues is reused by the calling code
-		labelValuesCopy := make([]string, len(labelValues))
-		for i, v := range labelValues {
-			// copy the value as this is backed by the arrow array data buffer.
-			// We could retain the record to avoid this copy, but that would hold
-			// all other columns in memory for as long as the query is evaluated.
-			labelValuesCopy[i] = strings.Clone(v)
+	state, ok := point[key]
+	if !ok {
+		// TODO: add limits on number of groups
+		state = &groupState{}
+		if a.opts.operation.IsGrouping() {
+			state.labelValues = a.copyLabelValues(labelValues, true)
+		} else {
+			state.samples = newSampleHeap(a.opts.operation)
 		}
+		point[key] = state
+	}
 
-		// TODO: add limits on number of groups
-		point[key] = &groupState{
-			labelValues: labelValuesCopy,
-			sum:         value,
+	// TODO: handle hash collisions
+	a.aggregate(state, value, labelValues)
+}
+
+func (a *vectorAggregator) aggregate(state *groupState, value float64, labelValues []string) {
+	state.count++
+
+	switch a.opts.operation {
+	case types.VectorAggregationTypeSum:
+		state.value += value
+	case types.VectorAggregationTypeAvg:
+		state.mean += (value - state.mean) / float64(state.count)
+	case types.VectorAggregationTypeMin:
+		if state.count == 1 || state.value > value || math.IsNaN(state.value) {
+			state.value = value
+		}
+	case types.VectorAggregationTypeMax:
+		if state.count == 1 || state.value < value || math.IsNaN(state.value) {
+			state.value = value
+		}
+	case types.VectorAggregationTypeStdDev, types.VectorAggregationTypeStdVar:
+		delta := value - state.mean
+		state.mean += delta / float64(state.count)
+		state.m2 += delta * (value - state.mean)
+	case types.VectorAggregationTypeTopK, types.VectorAggregationTypeBottomK:
+		// keep the k samples with the highest (topk) or lowest (bottomk) values,
+		// the heap keeps the sample that is evicted first on top.
+		if state.samples.Len() < a.opts.parameter || state.samples.less(state.samples.samples[0].value, value) {
+			if state.samples.Len() == a.opts.parameter {
+				heap.Pop(state.samples)
+			}
+			heap.Push(state.samples, sample{value: value, labelValues: a.copyLabelValues(labelValues, false)})
 		}
+	case types.VectorAggregationTypeSort, types.VectorAggregationTypeSortDesc:
+		heap.Push(state.samples, sample{value: value, labelValues: a.copyLabelValues(labelValues, false)})
+	}
+}
+
+// copyLabelValues creates a copy of labelValues. If onlyGrouped is true, only
+// the values of the grouping labels are retained.
+func (a *vectorAggregator) copyLabelValues(labelValues []string, onlyGrouped bool) []string {
+	// create a new slice since labelValues is reused by the calling code
+	labelValuesCopy := make([]string, len(labelValues))
+	for i, v := range labelValues {
+		if onlyGrouped && !a.fields[i].grouped {
+			continue
+		}
+		// copy the value as this is backed by the arrow array data buffer.
+		// We could retain the record to avoid this copy, but that would hold
+		// all other columns in memory for as long as the query is evaluated.
+		labelValuesCopy[i] = strings.Clone(v)
+	}
+	return labelValuesCopy
+}
+
+// result returns the aggregated value of the group.
+func (a *vectorAggregator) result(state *groupState) float64 {
+	switch a.opts.operation {
+	case types.VectorAggregationTypeAvg:
+		return state.mean
+	case types.VectorAggregationTypeCount:
+		return float64(state.count)
+	case types.VectorAggregationTypeStdDev:
+		return math.Sqrt(state.m2 / float64(state.count))
+	case types.VectorAggregationTypeStdVar:
+		return state.m2 / float64(state.count)
+	default:
+		return state.value
 	}
 }
 
 func (a *vectorAggregator) buildRecord() (arrow.Record, error) {
-	fields := make([]arrow.Field, 0, len(a.groupBy)+2)
+	// Aggregations that return a result per group only return the grouping
+	// labels, all others return the labels of the input rows.
+	outputFields := make([]int, 0, len(a.fields))
+	for i, field := range a.fields {
+		if field.grouped || !a.opts.operation.IsGrouping() {
+			outputFields = append(outputFields, i)
+		}
+	}
+
+	fields := make([]arrow.Field, 0, len(outputFields)+2)
 	fields = append(fields,
 		arrow.Field{
 			Name:     types.ColumnNameBuiltinTimestamp,
@@ -244,23 +470,18 @@ func (a *vectorAggregator) buildRecord() (arrow.Record, error) {
 		},
 		arrow.Field{
 			Name:     types.ColumnNameGeneratedValue,
-			Type:     datatype.Arrow.Integer,
+			Type:     datatype.Arrow.Float,
 			Nullable: false,
-			Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Integer),
+			Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Float),
 		},
 	)
 
-	for _, column := range a.groupBy {
-		colExpr, ok := column.(*physical.ColumnExpr)
-		if !ok {
-			panic(fmt.Sprintf("invalid column expression type %T", column))
-		}
-
+	for _, idx := range outputFields {
 		fields = append(fields, arrow.Field{
-			Name:     colExpr.Ref.Column,
+			Name:     a.fields[idx].name,
 			Type:     datatype.Arrow.String,
 			Nullable: true,
-			Metadata: datatype.ColumnMetadata(colExpr.Ref.Type, datatype.Loki.String),
+			Metadata: datatype.ColumnMetadata(a.fields[idx].ct, datatype.Loki.String),
 		})
 	}
 
@@ -268,22 +489,35 @@ func (a *vectorAggregator) buildRecord() (arrow.Record, error) {
 	rb := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
 	defer rb.Release()
 
+	appendRow := func(ts arrow.Timestamp, value float64, labelValues []string) {
+		rb.Field(0).(*array.TimestampBuilder).Append(ts)
+		rb.Field(1).(*array.Float64Builder).Append(value)
+
+		for col, idx := range outputFields {
+			builder := rb.Field(col + 2) // offset by 2 as the first 2 fields are timestamp and value
+			// entries created before a field was discovered have fewer label values
+			if idx >= len(labelValues) || labelValues[idx] == "" {
+				builder.(*array.StringBuilder).AppendNull()
+			} else {
+				builder.(*array.StringBuilder).Append(labelValues[idx])
+			}
+		}
+	}
+
 	// emit aggregated results in sorted order of timestamp
 	for _, ts := range a.GetSortedTimestamps() {
 		entries := a.GetEntriesForTimestamp(ts)
 		tsValue, _ := arrow.TimestampFromTime(ts, arrow.Nanosecond)
 
 		for _, entry := range entries {
-			rb.Field(0).(*array.TimestampBuilder).Append(tsValue)
-			rb.Field(1).(*array.Int64Builder).Append(entry.sum)
-
-			for col, val := range entry.labelValues {
-				builder := rb.Field(col + 2) // offset by 2 as the first 2 fields are timestamp and value
-				if val == "" {
-					builder.(*array.StringBuilder).AppendNull()
-				} else {
-					builder.(*array.StringBuilder).Append(val)
-				}
+			if entry.samples == nil {
+				appendRow(tsValue, a.result(entry), entry.labelValues)
+				continue
+			}
+
+			// emit the samples of the group in order of the aggregation
+			for _, s := range entry.samples.sorted() {
+				appendRow(tsValue, s.value, s.labelValues)
 			}
 		}
 	}
@@ -299,6 +533,10 @@ func (a *vectorAggregator) NumOfPoints() int {
 	return len(a.points)
 }
 
+func (a *vectorAggregator) NumOfFields() int {
+	return len(a.fields)
+}
+
 // GetSortedTimestamps returns all timestamps in sorted order
 func (a *vectorAggregator) GetSortedTimestamps() []time.Time {
 	return slices.SortedFunc(maps.Keys(a.points), func(a, b time.Time) int {
@@ -310,3 +548,53 @@ func (a *vectorAggregator) GetSortedTimestamps() []time.Time {
 func (a *vectorAggregator) GetEntriesForTimestamp(ts time.Time) map[uint64]*groupState {
 	return a.points[ts]
 }
+
+// sampleHeap is a heap of samples that keeps the sample that comes last in
+// the order of the aggregation on top. For topk and sort_desc these are the
+// samples with the lowest value, for bottomk and sort the samples with the
+// highest value.
+type sampleHeap struct {
+	samples []sample
+	less    func(a, b float64) bool
+}
+
+var _ heap.Interface = (*sampleHeap)(nil)
+
+func newSampleHeap(op types.VectorAggregationType) *sampleHeap {
+	h := &sampleHeap{}
+	switch op {
+	case types.VectorAggregationTypeTopK, types.VectorAggregationTypeSortDesc:
+		h.less = func(a, b float64) bool { return math.IsNaN(a) || a < b }
+	default:
+		h.less = func(a, b float64) bool { return math.IsNaN(a) || a > b }
+	}
+	return h
+}
+
+func (h *sampleHeap) Len() int           { return len(h.samples) }
+func (h *sampleHeap) Less(i, j int) bool { return h.less(h.samples[i].value, h.samples[j].value) }
+func (h *sampleHeap) Swap(i, j int)      { h.samples[i], h.samples[j] = h.samples[j], h.samples[i] }
+func (h *sampleHeap) Push(x any)         { h.samples = append(h.samples, x.(sample)) }
+
+func (h *sampleHeap) Pop() any {
+	old := h.samples
+	n := len(old)
+	s := old[n-1]
+	h.samples = old[:n-1]
+	return s
+}
+
+// sorted returns the samples in the order of the aggregation.
+func (h *sampleHeap) sorted() []sample {
+	slices.SortStableFunc(h.samples, func(a, b sample) int {
+		switch {
+		case h.less(b.value, a.value):
+			return -1
+		case h.less(a.value, b.value):
+			return 1
+		default:
+			return 0
+		}
+	})
+	return h.samples
+}
@@ -19,7 +19,7 @@ func TestVectorAggregationPipeline(t *testing.T) {
 	// input schema with timestamp, value and group by columns
 	fields := []arrow.Field{
 		{Name: types.ColumnNameBuiltinTimestamp, Type: datatype.Arrow.Timestamp, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
-		{Name: types.ColumnNameGeneratedValue, Type: datatype.Arrow.Integer, Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Integer)},
+		{Name: types.ColumnNameGeneratedValue, Type: datatype.Arrow.Float, Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Float)},
 		{Name: "env", Type: datatype.Arrow.String, Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)},
 		{Name: "service", Type: datatype.Arrow.String, Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)},
 	}
@@ -84,7 +84,10 @@ func TestVectorAggregationPipeline(t *testing.T) {
 		},
 	}
 
-	pipeline, err := NewVectorAggregationPipeline([]Pipeline{input1, input2}, groupBy, expressionEvaluator{})
+	pipeline, err := NewVectorAggregationPipeline([]Pipeline{input1, input2}, expressionEvaluator{}, vectorAggregationOptions{
+		groupBy:   groupBy,
+		operation: types.VectorAggregationTypeSum,
+	})
 	require.NoError(t, err)
 	defer pipeline.Close()
 
@@ -96,7 +99,7 @@ func TestVectorAggregationPipeline(t *testing.T) {
 	defer record.Release()
 
 	// Define expected results - sum of values for each group at each timestamp
-	expected := map[time.Time]map[string]int64{
+	expected := map[time.Time]map[string]float64{
 		t1: {
 			"prod,app1": 15, // 10 + 5
 			"prod,app2": 20, // 20
@@ -118,16 +121,16 @@ func TestVectorAggregationPipeline(t *testing.T) {
 	}
 
 	// Verify results
-	actual := make(map[time.Time]map[string]int64)
+	actual := make(map[time.Time]map[string]float64)
 	for i := range int(record.NumRows()) {
 		ts := record.Column(0).(*array.Timestamp).Value(i).ToTime(arrow.Nanosecond)
-		value := record.Column(1).(*array.Int64).Value(i)
+		value := record.Column(1).(*array.Float64).Value(i)
 		env := record.Column(2).(*array.String).Value(i)
 		service := record.Column(3).(*array.String).Value(i)
 		key := fmt.Sprintf("%s,%s", env, service)
 
 		if _, ok := actual[ts]; !ok {
-			actual[ts] = make(map[string]int64)
+			actual[ts] = make(map[string]float64)
 		}
 		actual[ts][key] = value
 	}
@@ -142,3 +145,125 @@ func TestVectorAggregationPipeline(t *testing.T) {
 		}
 	}
 }
+
+func TestVectorAggregationPipeline_Operations(t *testing.T) {
+	fields := []arrow.Field{
+		{Name: types.ColumnNameBuiltinTimestamp, Type: datatype.Arrow.Timestamp, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
+		{Name: types.ColumnNameGeneratedValue, Type: datatype.Arrow.Float, Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Float)},
+		{Name: "env", Type: datatype.Arrow.String, Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)},
+		{Name: "service", Type: datatype.Arrow.String, Metadata: datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)},
+	}
+
+	now := time.Now().UTC()
+	inputCSV := strings.Join([]string{
+		fmt.Sprintf("%s,10,prod,app1", now.Format(arrowTimestampFormat)),
+		fmt.Sprintf("%s,20,prod,app2", now.Format(arrowTimestampFormat)),
+		fmt.Sprintf("%s,60,prod,app3", now.Format(arrowTimestampFormat)),
+		fmt.Sprintf("%s,30,dev,app1", now.Format(arrowTimestampFormat)),
+	}, "\n")
+
+	byEnv := []physical.ColumnExpression{
+		&physical.ColumnExpr{Ref: types.ColumnRef{Column: "env", Type: types.ColumnTypeAmbiguous}},
+	}
+	byService := []physical.ColumnExpression{
+		&physical.ColumnExpr{Ref: types.ColumnRef{Column: "service", Type: types.ColumnTypeAmbiguous}},
+	}
+
+	for _, tt := range []struct {
+		name     string
+		opts     vectorAggregationOptions
+		expected []string // rows formatted as value,env,service
+	}{
+		{
+			name:     "sum without grouping",
+			opts:     vectorAggregationOptions{operation: types.VectorAggregationTypeSum},
+			expected: []string{"120"},
+		},
+		{
+			name:     "sum without service",
+			opts:     vectorAggregationOptions{operation: types.VectorAggregationTypeSum, groupBy: byService, without: true},
+			expected: []string{"90,prod", "30,dev"},
+		},
+		{
+			name:     "avg by env",
+			opts:     vectorAggregationOptions{operation: types.VectorAggregationTypeAvg, groupBy: byEnv},
+			expected: []string{"30,prod", "30,dev"},
+		},
+		{
+			name:     "min by env",
+			opts:     vectorAggregationOptions{operation: types.VectorAggregationTypeMin, groupBy: byEnv},
+			expected: []string{"10,prod", "30,dev"},
+		},
+		{
+			name:     "max by env",
+			opts:     vectorAggregationOptions{operation: types.VectorAggregationTypeMax, groupBy: byEnv},
+			expected: []string{"60,prod", "30,dev"},
+		},
+		{
+			name:     "count by env",
+			opts:     vectorAggregationOptions{operation: types.VectorAggregationTypeCount, groupBy: byEnv},
+			expected: []string{"3,prod", "1,dev"},
+		},
+		{
+			name:     "stdvar by env",
+			opts:     vectorAggregationOptions{operation: types.VectorAggregationTypeStdVar, groupBy: byEnv},
+			expected: []string{"466.6666666666667,prod", "0,dev"},
+		},
+		{
+			name:     "stddev",
+			opts:     vectorAggregationOptions{operation: types.VectorAggregationTypeStdDev},
+			expected: []string{"18.708286933869708"},
+		},
+		{
+			name:     "topk by env",
+			opts:     vectorAggregationOptions{operation: types.VectorAggregationTypeTopK, groupBy: byEnv, parameter: 2},
+			expected: []string{"60,prod,app3", "20,prod,app2", "30,dev,app1"},
+		},
+		{
+			name:     "bottomk",
+			opts:     vectorAggregationOptions{operation: types.VectorAggregationTypeBottomK, parameter: 1},
+			expected: []string{"10,prod,app1"},
+		},
+		{
+			name:     "sort",
+			opts:     vectorAggregationOptions{operation: types.VectorAggregationTypeSort},
+			expected: []string{"10,prod,app1", "20,prod,app2", "30,dev,app1", "60,prod,app3"},
+		},
+		{
+			name:     "sort_desc",
+			opts:     vectorAggregationOptions{operation: types.VectorAggregationTypeSortDesc},
+			expected: []string{"60,prod,app3", "30,dev,app1", "20,prod,app2", "10,prod,app1"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := CSVToArrow(fields, inputCSV)
+			require.NoError(t, err)
+			defer record.Release()
+
+			pipeline, err := NewVectorAggregationPipeline([]Pipeline{NewBufferedPipeline(record)}, expressionEvaluator{}, tt.opts)
+			require.NoError(t, err)
+			defer pipeline.Close()
+
+			require.NoError(t, pipeline.Read(t.Context()))
+			result, err := pipeline.Value()
+			require.NoError(t, err)
+			defer result.Release()
+
+			actual := make([]string, 0, result.NumRows())
+			for i := range int(result.NumRows()) {
+				row := []string{fmt.Sprint(result.Column(1).(*array.Float64).Value(i))}
+				for col := 2; col < int(result.NumCols()); col++ {
+					row = append(row, result.Column(col).(*array.String).Value(i))
+				}
+				actual = append(actual, strings.Join(row, ","))
+			}
+
+			if len(tt.opts.groupBy) > 0 {
+				// groups are returned in no particular order
+				require.ElementsMatch(t, tt.expected, actual)
+			} else {
+				require.Equal(t, tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/engine/planner/physical"
+)
+
+// NewVectorLiteralPipeline returns a pipeline that returns a single record
+// with one sample without labels for each step of node. Steps start at the
+// start timestamp and include the end timestamp. Instant queries have a
+// single step at the end timestamp.
+func NewVectorLiteralPipeline(node *physical.VectorLiteral) *GenericPipeline {
+	done := false
+	return newGenericPipeline(Local, func(_ context.Context, _ []Pipeline) state {
+		if done {
+			return Exhausted
+		}
+		done = true
+
+		schema := arrow.NewSchema([]arrow.Field{
+			{
+				Name:     types.ColumnNameBuiltinTimestamp,
+				Type:     datatype.Arrow.Timestamp,
+				Nullable: false,
+				Metadata: datatype.ColumnMetadataBuiltinTimestamp,
+			},
+			{
+				Name:     types.ColumnNameGeneratedValue,
+				Type:     datatype.Arrow.Float,
+				Nullable: false,
+				Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Float),
+			},
+		}, nil)
+
+		rb := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+		defer rb.Release()
+
+		start, step := node.Start, node.Step
+		if step <= 0 {
+			// instant query
+			start, step = node.End, time.Nanosecond
+		}
+		for t := start; !t.After(node.End); t = t.Add(step) {
+			ts, _ := arrow.TimestampFromTime(t, arrow.Nanosecond)
+			rb.Field(0).(*array.TimestampBuilder).Append(ts)
+			rb.Field(1).(*array.Float64Builder).Append(node.Value)
+		}
+
+		return successState(rb.NewRecord())
+	})
+}
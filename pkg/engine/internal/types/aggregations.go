@@ -6,13 +6,49 @@ type RangeAggregationType int
 const (
 	RangeAggregationTypeInvalid RangeAggregationType = iota
 
-	RangeAggregationTypeCount // Represents count_over_time range aggregation
+	RangeAggregationTypeCount     // Represents count_over_time range aggregation
+	RangeAggregationTypeRate      // Represents rate range aggregation
+	RangeAggregationTypeBytes     // Represents bytes_over_time range aggregation
+	RangeAggregationTypeBytesRate // Represents bytes_rate range aggregation
+	RangeAggregationTypeSum       // Represents sum_over_time range aggregation
+	RangeAggregationTypeAvg       // Represents avg_over_time range aggregation
+	RangeAggregationTypeMin       // Represents min_over_time range aggregation
+	RangeAggregationTypeMax       // Represents max_over_time range aggregation
+	RangeAggregationTypeFirst     // Represents first_over_time range aggregation
+	RangeAggregationTypeLast      // Represents last_over_time range aggregation
+	RangeAggregationTypeStdDev    // Represents stddev_over_time range aggregation
+	RangeAggregationTypeStdVar    // Represents stdvar_over_time range aggregation
+	RangeAggregationTypeQuantile  // Represents quantile_over_time range aggregation
 )
 
 func (op RangeAggregationType) String() string {
 	switch op {
 	case RangeAggregationTypeCount:
 		return "count"
+	case RangeAggregationTypeRate:
+		return "rate"
+	case RangeAggregationTypeBytes:
+		return "bytes"
+	case RangeAggregationTypeBytesRate:
+		return "bytes_rate"
+	case RangeAggregationTypeSum:
+		return "sum"
+	case RangeAggregationTypeAvg:
+		return "avg"
+	case RangeAggregationTypeMin:
+		return "min"
+	case RangeAggregationTypeMax:
+		return "max"
+	case RangeAggregationTypeFirst:
+		return "first"
+	case RangeAggregationTypeLast:
+		return "last"
+	case RangeAggregationTypeStdDev:
+		return "stddev"
+	case RangeAggregationTypeStdVar:
+		return "stdvar"
+	case RangeAggregationTypeQuantile:
+		return "quantile"
 	default:
 		return "invalid"
 	}
@@ -24,14 +60,56 @@ type VectorAggregationType int
 const (
 	VectorAggregationTypeInvalid VectorAggregationType = iota
 
-	VectorAggregationTypeSum // Represents sum vector aggregation
+	VectorAggregationTypeSum      // Represents sum vector aggregation
+	VectorAggregationTypeAvg      // Represents avg vector aggregation
+	VectorAggregationTypeMin      // Represents min vector aggregation
+	VectorAggregationTypeMax      // Represents max vector aggregation
+	VectorAggregationTypeCount    // Represents count vector aggregation
+	VectorAggregationTypeStdDev   // Represents stddev vector aggregation
+	VectorAggregationTypeStdVar   // Represents stdvar vector aggregation
+	VectorAggregationTypeTopK     // Represents topk vector aggregation
+	VectorAggregationTypeBottomK  // Represents bottomk vector aggregation
+	VectorAggregationTypeSort     // Represents sort vector aggregation
+	VectorAggregationTypeSortDesc // Represents sort_desc vector aggregation
 )
 
 func (op VectorAggregationType) String() string {
 	switch op {
 	case VectorAggregationTypeSum:
 		return "sum"
+	case VectorAggregationTypeAvg:
+		return "avg"
+	case VectorAggregationTypeMin:
+		return "min"
+	case VectorAggregationTypeMax:
+		return "max"
+	case VectorAggregationTypeCount:
+		return "count"
+	case VectorAggregationTypeStdDev:
+		return "stddev"
+	case VectorAggregationTypeStdVar:
+		return "stdvar"
+	case VectorAggregationTypeTopK:
+		return "topk"
+	case VectorAggregationTypeBottomK:
+		return "bottomk"
+	case VectorAggregationTypeSort:
+		return "sort"
+	case VectorAggregationTypeSortDesc:
+		return "sort_desc"
 	default:
 		return "invalid"
 	}
 }
+
+// IsGrouping returns whether the aggregation produces one result per group.
+// Aggregations that select or order rows, such as topk or sort, return the
+// input rows instead.
+func (op VectorAggregationType) IsGrouping() bool {
+	switch op {
+	case VectorAggregationTypeTopK, VectorAggregationTypeBottomK, VectorAggregationTypeSort, VectorAggregationTypeSortDesc:
+		return false
+	default:
+		return true
+	}
+}
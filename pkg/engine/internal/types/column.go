@@ -37,6 +37,10 @@ const (
 	ColumnNameBuiltinMessage   = "message"
 	ColumnNameGeneratedValue   = "value"
 
+	// ColumnNameGeneratedUnwrapped is the name of the column that holds the
+	// converted values of an unwrapped column.
+	ColumnNameGeneratedUnwrapped = "__unwrapped__"
+
 	MetadataKeyColumnType     = "column_type"
 	MetadataKeyColumnDataType = "column_datatype"
 )
@@ -12,6 +12,11 @@ const (
 
 	UnaryOpNot // Logical NOT operation (!).
 	UnaryOpAbs // Mathematical absolute operation (abs).
+
+	UnaryOpLength       // Length in bytes of a string (len). Used for bytes_over_time and bytes_rate.
+	UnaryOpCastFloat    // Conversion of a string into a float (unwrap).
+	UnaryOpCastBytes    // Conversion of a humanized bytes string into a float (unwrap bytes()).
+	UnaryOpCastDuration // Conversion of a duration string into a float of seconds (unwrap duration()).
 )
 
 // String returns the string representation of the UnaryOp.
@@ -21,6 +26,14 @@ func (t UnaryOp) String() string {
 		return "NOT"
 	case UnaryOpAbs:
 		return "ABS"
+	case UnaryOpLength:
+		return "LENGTH"
+	case UnaryOpCastFloat:
+		return "CAST_FLOAT"
+	case UnaryOpCastBytes:
+		return "CAST_BYTES"
+	case UnaryOpCastDuration:
+		return "CAST_DURATION"
 	default:
 		panic(fmt.Sprintf("unknown unary operator %d", t))
 	}
@@ -46,11 +59,14 @@ const (
 	BinaryOpXor // Logical XOR operation (^).
 	BinaryOpNot // Logical NOT operation (!).
 
+	BinaryOpUnless // Set difference operation (unless). Used for vector matching.
+
 	BinaryOpAdd // Addition operation (+).
 	BinaryOpSub // Subtraction operation (-).
 	BinaryOpMul // Multiplication operation (*).
 	BinaryOpDiv // Division operation (/).
 	BinaryOpMod // Modulo operation (%).
+	BinaryOpPow // Exponentiation operation (^).
 
 	BinaryOpMatchSubstr     // Substring matching operation (|=). Used for string match filter.
 	BinaryOpNotMatchSubstr  // Substring non-matching operation (!=). Used for string match filter.
@@ -83,6 +99,8 @@ func (t BinaryOp) String() string {
 		return "XOR"
 	case BinaryOpNot:
 		return "NOT"
+	case BinaryOpUnless:
+		return "UNLESS"
 	case BinaryOpAdd:
 		return "ADD"
 	case BinaryOpSub:
@@ -93,6 +111,8 @@ func (t BinaryOp) String() string {
 		return "DIV"
 	case BinaryOpMod:
 		return "MOD"
+	case BinaryOpPow:
+		return "POW"
 	case BinaryOpMatchSubstr:
 		return "MATCH_STR"
 	case BinaryOpNotMatchSubstr:
@@ -0,0 +1,38 @@
+package types
+
+// ParserKind represents the kind of parser that extracts columns from log lines
+type ParserKind int
+
+const (
+	ParserKindInvalid ParserKind = iota
+
+	ParserKindJSON    // Represents the json parser
+	ParserKindLogfmt  // Represents the logfmt parser
+	ParserKindRegexp  // Represents the regexp parser
+	ParserKindPattern // Represents the pattern parser
+	ParserKindUnpack  // Represents the unpack parser
+)
+
+func (k ParserKind) String() string {
+	switch k {
+	case ParserKindJSON:
+		return "json"
+	case ParserKindLogfmt:
+		return "logfmt"
+	case ParserKindRegexp:
+		return "regexp"
+	case ParserKindPattern:
+		return "pattern"
+	case ParserKindUnpack:
+		return "unpack"
+	default:
+		return "invalid"
+	}
+}
+
+// ModifiesLine returns whether the parser replaces the log line of the rows it
+// parses. This is the case for the unpack parser, which replaces the log line
+// with the packed original log line.
+func (k ParserKind) ModifiesLine() bool {
+	return k == ParserKindUnpack
+}
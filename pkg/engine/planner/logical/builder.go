@@ -3,8 +3,12 @@ package logical
 import (
 	"time"
 
+	"github.com/prometheus/prometheus/model/labels"
+
 	"github.com/grafana/loki/v3/pkg/engine/internal/types"
 	"github.com/grafana/loki/v3/pkg/engine/planner/schema"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 )
 
 // Builder provides an ergonomic interface for constructing a [Plan].
@@ -28,6 +32,78 @@ func (b *Builder) Select(predicate Value) *Builder {
 	}
 }
 
+// Parse applies a [Parse] operation to the Builder.
+// pattern is only used by the regexp and pattern parsers, expressions, strict
+// and keepEmpty only by the json and logfmt parsers.
+func (b *Builder) Parse(
+	kind types.ParserKind,
+	pattern string,
+	expressions []log.LabelExtractionExpr,
+	strict, keepEmpty bool,
+) *Builder {
+	return &Builder{
+		val: &Parse{
+			Table: b.val,
+
+			Kind:        kind,
+			Pattern:     pattern,
+			Expressions: expressions,
+			Strict:      strict,
+			KeepEmpty:   keepEmpty,
+		},
+	}
+}
+
+// LineFormat applies a [LineFormat] operation to the Builder.
+func (b *Builder) LineFormat(template string) *Builder {
+	return &Builder{
+		val: &LineFormat{
+			Table:    b.val,
+			Template: template,
+		},
+	}
+}
+
+// LabelFormat applies a [LabelFormat] operation to the Builder.
+func (b *Builder) LabelFormat(formats []log.LabelFmt) *Builder {
+	return &Builder{
+		val: &LabelFormat{
+			Table:   b.val,
+			Formats: formats,
+		},
+	}
+}
+
+// Unwrap applies an [Unwrap] operation to the Builder.
+func (b *Builder) Unwrap(value *UnaryOp) *Builder {
+	return &Builder{
+		val: &Unwrap{
+			Table: b.val,
+			Value: value,
+		},
+	}
+}
+
+// KeepLabels applies a [KeepLabels] operation to the Builder.
+func (b *Builder) KeepLabels(lbls []log.NamedLabelMatcher) *Builder {
+	return &Builder{
+		val: &KeepLabels{
+			Table:  b.val,
+			Labels: lbls,
+		},
+	}
+}
+
+// DropLabels applies a [DropLabels] operation to the Builder.
+func (b *Builder) DropLabels(lbls []log.NamedLabelMatcher) *Builder {
+	return &Builder{
+		val: &DropLabels{
+			Table:  b.val,
+			Labels: lbls,
+		},
+	}
+}
+
 // Limit applies a [Limit] operation to the Builder.
 func (b *Builder) Limit(skip uint32, fetch uint32) *Builder {
 	return &Builder{
@@ -53,23 +129,30 @@ func (b *Builder) Sort(column ColumnRef, ascending, nullsFirst bool) *Builder {
 }
 
 // RangeAggregation applies a [RangeAggregation] operation to the Builder.
+// value may be nil for operations that count rows, such as count_over_time.
 func (b *Builder) RangeAggregation(
 	partitionBy []ColumnRef,
 	operation types.RangeAggregationType,
+	value Value,
+	parameter float64,
 	startTS, endTS time.Time,
 	step time.Duration,
 	rangeInterval time.Duration,
+	offset time.Duration,
 ) *Builder {
 	return &Builder{
 		val: &RangeAggregation{
 			Table: b.val,
 
 			Operation:     operation,
+			Value:         value,
+			Parameter:     parameter,
 			PartitionBy:   partitionBy,
 			Start:         startTS,
 			End:           endTS,
 			Step:          step,
 			RangeInterval: rangeInterval,
+			Offset:        offset,
 		},
 	}
 }
@@ -77,13 +160,60 @@ func (b *Builder) RangeAggregation(
 // VectorAggregation applies a [VectorAggregation] operation to the Builder.
 func (b *Builder) VectorAggregation(
 	groupBy []ColumnRef,
+	without bool,
 	operation types.VectorAggregationType,
+	parameter int,
 ) *Builder {
 	return &Builder{
 		val: &VectorAggregation{
 			Table:     b.val,
 			GroupBy:   groupBy,
+			Without:   without,
 			Operation: operation,
+			Parameter: parameter,
+		},
+	}
+}
+
+// LabelReplace applies a [LabelReplace] operation to the Builder.
+func (b *Builder) LabelReplace(dst, replacement, src, regex string) *Builder {
+	return &Builder{
+		val: &LabelReplace{
+			Table: b.val,
+
+			Dst:         dst,
+			Replacement: replacement,
+			Src:         src,
+			Regex:       regex,
+		},
+	}
+}
+
+// Absent applies an [Absent] operation to the Builder.
+func (b *Builder) Absent(lbls labels.Labels, startTS, endTS time.Time, step time.Duration) *Builder {
+	return &Builder{
+		val: &Absent{
+			Table: b.val,
+
+			Labels: lbls,
+			Start:  startTS,
+			End:    endTS,
+			Step:   step,
+		},
+	}
+}
+
+// BinOp applies a [BinOp] operation between the Builder as the left operand
+// and right. right is either a table relation or a [Literal] scalar.
+func (b *Builder) BinOp(op types.BinaryOp, right Value, returnBool bool, matching *syntax.VectorMatching) *Builder {
+	return &Builder{
+		val: &BinOp{
+			Left:  b.val,
+			Right: right,
+			Op:    op,
+
+			ReturnBool:     returnBool,
+			VectorMatching: matching,
 		},
 	}
 }
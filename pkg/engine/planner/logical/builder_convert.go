@@ -41,10 +41,28 @@ func (b *ssaBuilder) process(value Value) (Value, error) {
 		return b.processLimitPlan(value)
 	case *Sort:
 		return b.processSortPlan(value)
+	case *Parse:
+		return b.processParsePlan(value)
+	case *LineFormat:
+		return b.processLineFormatPlan(value)
+	case *LabelFormat:
+		return b.processLabelFormatPlan(value)
+	case *KeepLabels:
+		return b.processKeepLabelsPlan(value)
+	case *Unwrap:
+		return b.processUnwrapPlan(value)
+	case *DropLabels:
+		return b.processDropLabelsPlan(value)
 	case *RangeAggregation:
 		return b.processRangeAggregate(value)
 	case *VectorAggregation:
 		return b.processVectorAggregation(value)
+	case *VectorLiteral:
+		return b.processVectorLiteral(value)
+	case *LabelReplace:
+		return b.processLabelReplace(value)
+	case *Absent:
+		return b.processAbsent(value)
 
 	case *UnaryOp:
 		return b.processUnaryOp(value)
@@ -104,6 +122,69 @@ func (b *ssaBuilder) processSortPlan(plan *Sort) (Value, error) {
 	return plan, nil
 }
 
+func (b *ssaBuilder) processParsePlan(plan *Parse) (Value, error) {
+	if _, err := b.process(plan.Table); err != nil {
+		return nil, err
+	}
+
+	plan.id = fmt.Sprintf("%%%d", b.getID())
+	b.instructions = append(b.instructions, plan)
+	return plan, nil
+}
+
+func (b *ssaBuilder) processLineFormatPlan(plan *LineFormat) (Value, error) {
+	if _, err := b.process(plan.Table); err != nil {
+		return nil, err
+	}
+
+	plan.id = fmt.Sprintf("%%%d", b.getID())
+	b.instructions = append(b.instructions, plan)
+	return plan, nil
+}
+
+func (b *ssaBuilder) processLabelFormatPlan(plan *LabelFormat) (Value, error) {
+	if _, err := b.process(plan.Table); err != nil {
+		return nil, err
+	}
+
+	plan.id = fmt.Sprintf("%%%d", b.getID())
+	b.instructions = append(b.instructions, plan)
+	return plan, nil
+}
+
+func (b *ssaBuilder) processKeepLabelsPlan(plan *KeepLabels) (Value, error) {
+	if _, err := b.process(plan.Table); err != nil {
+		return nil, err
+	}
+
+	plan.id = fmt.Sprintf("%%%d", b.getID())
+	b.instructions = append(b.instructions, plan)
+	return plan, nil
+}
+
+func (b *ssaBuilder) processDropLabelsPlan(plan *DropLabels) (Value, error) {
+	if _, err := b.process(plan.Table); err != nil {
+		return nil, err
+	}
+
+	plan.id = fmt.Sprintf("%%%d", b.getID())
+	b.instructions = append(b.instructions, plan)
+	return plan, nil
+}
+
+func (b *ssaBuilder) processUnwrapPlan(plan *Unwrap) (Value, error) {
+	if _, err := b.process(plan.Table); err != nil {
+		return nil, err
+	}
+	if _, err := b.process(plan.Value); err != nil {
+		return nil, err
+	}
+
+	plan.id = fmt.Sprintf("%%%d", b.getID())
+	b.instructions = append(b.instructions, plan)
+	return plan, nil
+}
+
 func (b *ssaBuilder) processUnaryOp(value *UnaryOp) (Value, error) {
 	if _, err := b.process(value.Value); err != nil {
 		return nil, err
@@ -119,6 +200,11 @@ func (b *ssaBuilder) processRangeAggregate(plan *RangeAggregation) (Value, error
 	if _, err := b.process(plan.Table); err != nil {
 		return nil, err
 	}
+	if plan.Value != nil {
+		if _, err := b.process(plan.Value); err != nil {
+			return nil, err
+		}
+	}
 
 	plan.id = fmt.Sprintf("%%%d", b.getID())
 	b.instructions = append(b.instructions, plan)
@@ -135,6 +221,32 @@ func (b *ssaBuilder) processVectorAggregation(plan *VectorAggregation) (Value, e
 	return plan, nil
 }
 
+func (b *ssaBuilder) processVectorLiteral(plan *VectorLiteral) (Value, error) {
+	plan.id = fmt.Sprintf("%%%d", b.getID())
+	b.instructions = append(b.instructions, plan)
+	return plan, nil
+}
+
+func (b *ssaBuilder) processLabelReplace(plan *LabelReplace) (Value, error) {
+	if _, err := b.process(plan.Table); err != nil {
+		return nil, err
+	}
+
+	plan.id = fmt.Sprintf("%%%d", b.getID())
+	b.instructions = append(b.instructions, plan)
+	return plan, nil
+}
+
+func (b *ssaBuilder) processAbsent(plan *Absent) (Value, error) {
+	if _, err := b.process(plan.Table); err != nil {
+		return nil, err
+	}
+
+	plan.id = fmt.Sprintf("%%%d", b.getID())
+	b.instructions = append(b.instructions, plan)
+	return plan, nil
+}
+
 func (b *ssaBuilder) processBinOp(expr *BinOp) (Value, error) {
 	if _, err := b.process(expr.Left); err != nil {
 		return nil, err
@@ -3,9 +3,12 @@ package logical
 import (
 	"fmt"
 	"io"
+	"strconv"
 
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
 	"github.com/grafana/loki/v3/pkg/engine/internal/util"
 	"github.com/grafana/loki/v3/pkg/engine/planner/internal/tree"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 )
 
 // PrintTree prints the given value and its dependencies as a tree structure to
@@ -29,10 +32,28 @@ func (t *treeFormatter) convert(value Value) *tree.Node {
 		return t.convertLimit(value)
 	case *Sort:
 		return t.convertSort(value)
+	case *Parse:
+		return t.convertParse(value)
+	case *LineFormat:
+		return t.convertLineFormat(value)
+	case *LabelFormat:
+		return t.convertLabelFormat(value)
+	case *KeepLabels:
+		return t.convertKeepLabels(value)
+	case *Unwrap:
+		return t.convertUnwrap(value)
+	case *DropLabels:
+		return t.convertDropLabels(value)
 	case *RangeAggregation:
 		return t.convertRangeAggregation(value)
 	case *VectorAggregation:
 		return t.convertVectorAggregation(value)
+	case *VectorLiteral:
+		return t.convertVectorLiteral(value)
+	case *LabelReplace:
+		return t.convertLabelReplace(value)
+	case *Absent:
+		return t.convertAbsent(value)
 
 	case *UnaryOp:
 		return t.convertUnaryOp(value)
@@ -98,6 +119,97 @@ func (t *treeFormatter) convertSort(ast *Sort) *tree.Node {
 	return node
 }
 
+func (t *treeFormatter) convertParse(ast *Parse) *tree.Node {
+	properties := []tree.Property{
+		tree.NewProperty("table", false, ast.Table.Name()),
+		tree.NewProperty("kind", false, ast.Kind),
+	}
+	if ast.Pattern != "" {
+		properties = append(properties, tree.NewProperty("pattern", false, strconv.Quote(ast.Pattern)))
+	}
+	if len(ast.Expressions) > 0 {
+		expressions := make([]any, len(ast.Expressions))
+		for i, expr := range ast.Expressions {
+			expressions[i] = fmt.Sprintf("%s=%s", expr.Identifier, strconv.Quote(expr.Expression))
+		}
+		properties = append(properties, tree.NewProperty("expressions", true, expressions...))
+	}
+	if ast.Strict {
+		properties = append(properties, tree.NewProperty("strict", false, ast.Strict))
+	}
+	if ast.KeepEmpty {
+		properties = append(properties, tree.NewProperty("keep_empty", false, ast.KeepEmpty))
+	}
+
+	node := tree.NewNode("PARSE", ast.Name(), properties...)
+	node.Children = append(node.Children, t.convert(ast.Table))
+	return node
+}
+
+func (t *treeFormatter) convertLineFormat(ast *LineFormat) *tree.Node {
+	node := tree.NewNode("LINE_FORMAT", ast.Name(),
+		tree.NewProperty("table", false, ast.Table.Name()),
+		tree.NewProperty("template", false, strconv.Quote(ast.Template)),
+	)
+	node.Children = append(node.Children, t.convert(ast.Table))
+	return node
+}
+
+func (t *treeFormatter) convertLabelFormat(ast *LabelFormat) *tree.Node {
+	formats := formatLabelFmts(ast.Formats)
+	values := make([]any, len(formats))
+	for i := range formats {
+		values[i] = formats[i]
+	}
+
+	node := tree.NewNode("LABEL_FORMAT", ast.Name(),
+		tree.NewProperty("table", false, ast.Table.Name()),
+		tree.NewProperty("formats", true, values...),
+	)
+	node.Children = append(node.Children, t.convert(ast.Table))
+	return node
+}
+
+func (t *treeFormatter) convertUnwrap(ast *Unwrap) *tree.Node {
+	node := tree.NewNode("UNWRAP", ast.Name(),
+		tree.NewProperty("table", false, ast.Table.Name()),
+		tree.NewProperty("value", false, ast.Value.Name()),
+	)
+	node.Comments = append(node.Comments, t.convert(ast.Value))
+	node.Children = append(node.Children, t.convert(ast.Table))
+	return node
+}
+
+func (t *treeFormatter) convertKeepLabels(ast *KeepLabels) *tree.Node {
+	lbls := formatNamedLabelMatchers(ast.Labels)
+	values := make([]any, len(lbls))
+	for i := range lbls {
+		values[i] = lbls[i]
+	}
+
+	node := tree.NewNode("KEEP", ast.Name(),
+		tree.NewProperty("table", false, ast.Table.Name()),
+		tree.NewProperty("labels", true, values...),
+	)
+	node.Children = append(node.Children, t.convert(ast.Table))
+	return node
+}
+
+func (t *treeFormatter) convertDropLabels(ast *DropLabels) *tree.Node {
+	lbls := formatNamedLabelMatchers(ast.Labels)
+	values := make([]any, len(lbls))
+	for i := range lbls {
+		values[i] = lbls[i]
+	}
+
+	node := tree.NewNode("DROP", ast.Name(),
+		tree.NewProperty("table", false, ast.Table.Name()),
+		tree.NewProperty("labels", true, values...),
+	)
+	node.Children = append(node.Children, t.convert(ast.Table))
+	return node
+}
+
 func (t *treeFormatter) convertUnaryOp(expr *UnaryOp) *tree.Node {
 	node := tree.NewNode("UnaryOp", expr.Name(),
 		tree.NewProperty("op", false, expr.Op.String()),
@@ -113,6 +225,22 @@ func (t *treeFormatter) convertBinOp(expr *BinOp) *tree.Node {
 		tree.NewProperty("left", false, expr.Left.Name()),
 		tree.NewProperty("right", false, expr.Right.Name()),
 	)
+	if expr.ReturnBool {
+		node.Properties = append(node.Properties, tree.NewProperty("bool", false, true))
+	}
+	if m := expr.VectorMatching; m != nil {
+		if m.On {
+			node.Properties = append(node.Properties, tree.NewProperty("on", true, toAnySlice(m.MatchingLabels)...))
+		} else if len(m.MatchingLabels) > 0 {
+			node.Properties = append(node.Properties, tree.NewProperty("ignoring", true, toAnySlice(m.MatchingLabels)...))
+		}
+		switch m.Card {
+		case syntax.CardManyToOne:
+			node.Properties = append(node.Properties, tree.NewProperty("group_left", true, toAnySlice(m.Include)...))
+		case syntax.CardOneToMany:
+			node.Properties = append(node.Properties, tree.NewProperty("group_right", true, toAnySlice(m.Include)...))
+		}
+	}
 	node.Children = append(node.Children, t.convert(expr.Left))
 	node.Children = append(node.Children, t.convert(expr.Right))
 	return node
@@ -136,10 +264,21 @@ func (t *treeFormatter) convertRangeAggregation(r *RangeAggregation) *tree.Node
 	properties := []tree.Property{
 		tree.NewProperty("table", false, r.Table.Name()),
 		tree.NewProperty("operation", false, r.Operation),
+	}
+	if r.Value != nil {
+		properties = append(properties, tree.NewProperty("value", false, r.Value.Name()))
+	}
+	if r.Operation == types.RangeAggregationTypeQuantile {
+		properties = append(properties, tree.NewProperty("parameter", false, r.Parameter))
+	}
+	properties = append(properties,
 		tree.NewProperty("start_ts", false, util.FormatTimeRFC3339Nano(r.Start)),
 		tree.NewProperty("end_ts", false, util.FormatTimeRFC3339Nano(r.End)),
 		tree.NewProperty("step", false, r.Step),
 		tree.NewProperty("range", false, r.RangeInterval),
+	)
+	if r.Offset != 0 {
+		properties = append(properties, tree.NewProperty("offset", false, r.Offset))
 	}
 
 	if len(r.PartitionBy) > 0 {
@@ -152,6 +291,9 @@ func (t *treeFormatter) convertRangeAggregation(r *RangeAggregation) *tree.Node
 	}
 
 	node := tree.NewNode("RangeAggregation", r.Name(), properties...)
+	if r.Value != nil {
+		node.Comments = append(node.Comments, t.convert(r.Value))
+	}
 	for _, columnRef := range r.PartitionBy {
 		node.Comments = append(node.Comments, t.convert(&columnRef))
 	}
@@ -165,8 +307,18 @@ func (t *treeFormatter) convertVectorAggregation(v *VectorAggregation) *tree.Nod
 		tree.NewProperty("table", false, v.Table.Name()),
 		tree.NewProperty("operation", false, v.Operation),
 	}
+	if v.Operation == types.VectorAggregationTypeTopK || v.Operation == types.VectorAggregationTypeBottomK {
+		properties = append(properties, tree.NewProperty("parameter", false, v.Parameter))
+	}
+
+	if v.Without {
+		without := make([]any, len(v.GroupBy))
+		for i := range v.GroupBy {
+			without[i] = v.GroupBy[i].Name()
+		}
 
-	if len(v.GroupBy) > 0 {
+		properties = append(properties, tree.NewProperty("without", true, without...))
+	} else if len(v.GroupBy) > 0 {
 		groupBy := make([]any, len(v.GroupBy))
 		for i := range v.GroupBy {
 			groupBy[i] = v.GroupBy[i].Name()
@@ -183,3 +335,44 @@ func (t *treeFormatter) convertVectorAggregation(v *VectorAggregation) *tree.Nod
 
 	return node
 }
+
+func (t *treeFormatter) convertVectorLiteral(v *VectorLiteral) *tree.Node {
+	return tree.NewNode("VectorLiteral", v.Name(),
+		tree.NewProperty("value", false, v.Value),
+		tree.NewProperty("start_ts", false, util.FormatTimeRFC3339Nano(v.Start)),
+		tree.NewProperty("end_ts", false, util.FormatTimeRFC3339Nano(v.End)),
+		tree.NewProperty("step", false, v.Step),
+	)
+}
+
+func (t *treeFormatter) convertLabelReplace(l *LabelReplace) *tree.Node {
+	node := tree.NewNode("LabelReplace", l.Name(),
+		tree.NewProperty("table", false, l.Table.Name()),
+		tree.NewProperty("dst", false, l.Dst),
+		tree.NewProperty("replacement", false, strconv.Quote(l.Replacement)),
+		tree.NewProperty("src", false, l.Src),
+		tree.NewProperty("regex", false, strconv.Quote(l.Regex)),
+	)
+	node.Children = append(node.Children, t.convert(l.Table))
+	return node
+}
+
+func (t *treeFormatter) convertAbsent(a *Absent) *tree.Node {
+	node := tree.NewNode("Absent", a.Name(),
+		tree.NewProperty("table", false, a.Table.Name()),
+		tree.NewProperty("labels", false, a.Labels),
+		tree.NewProperty("start_ts", false, util.FormatTimeRFC3339Nano(a.Start)),
+		tree.NewProperty("end_ts", false, util.FormatTimeRFC3339Nano(a.End)),
+		tree.NewProperty("step", false, a.Step),
+	)
+	node.Children = append(node.Children, t.convert(a.Table))
+	return node
+}
+
+func toAnySlice(values []string) []any {
+	result := make([]any, len(values))
+	for i := range values {
+		result[i] = values[i]
+	}
+	return result
+}
@@ -126,10 +126,13 @@ func TestFormatRangeAggregationQuery(t *testing.T) {
 	).RangeAggregation(
 		[]ColumnRef{*NewColumnRef("label1", types.ColumnTypeAmbiguous), *NewColumnRef("label2", types.ColumnTypeAmbiguous)},
 		types.RangeAggregationTypeCount,
+		nil, // Value
+		0,   // Parameter
 		time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), // Start Time
 		time.Date(1970, 1, 1, 1, 0, 0, 0, time.UTC), // End Time
 		time.Minute,
 		time.Minute*5, // Range
+		0,             // Offset
 	)
 
 	// Convert to plan so that node IDs get populated
@@ -176,7 +179,9 @@ func TestFormatVectorAggregationQuery(t *testing.T) {
 			*NewColumnRef("app", types.ColumnTypeLabel),
 			*NewColumnRef("env", types.ColumnTypeLabel),
 		},
+		false, // Without
 		types.VectorAggregationTypeSum,
+		0, // Parameter
 	)
 
 	// Convert to plan so that node IDs get populated
@@ -0,0 +1,64 @@
+package logical
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/engine/internal/util"
+	"github.com/grafana/loki/v3/pkg/engine/planner/schema"
+)
+
+// The Absent instruction yields a table relation with a single series, that
+// has the value 1 at each step of the query for which Table has no samples.
+// It is the result of the absent_over_time range aggregation. Absent
+// implements both [Instruction] and [Value].
+type Absent struct {
+	id string
+
+	Table  Value         // The table relation of samples to check for absence.
+	Labels labels.Labels // The labels of the resulting series.
+
+	Start time.Time     // The timestamp of the first step.
+	End   time.Time     // The timestamp of the last step.
+	Step  time.Duration // The step between samples. Zero for instant queries.
+}
+
+var (
+	_ Value       = (*Absent)(nil)
+	_ Instruction = (*Absent)(nil)
+)
+
+// Name returns an identifier for the Absent operation.
+func (a *Absent) Name() string {
+	if a.id != "" {
+		return a.id
+	}
+	return fmt.Sprintf("%p", a)
+}
+
+// String returns the disassembled SSA form of the Absent instruction.
+func (a *Absent) String() string {
+	return fmt.Sprintf(
+		"ABSENT %s [labels=%s, start_ts=%s, end_ts=%s, step=%s]",
+		a.Table.Name(), a.Labels, util.FormatTimeRFC3339Nano(a.Start), util.FormatTimeRFC3339Nano(a.End), a.Step,
+	)
+}
+
+// Schema returns the schema of the Absent plan.
+func (a *Absent) Schema() *schema.Schema {
+	columns := make([]schema.ColumnSchema, 0, a.Labels.Len()+2)
+	columns = append(columns,
+		schema.ColumnSchema{Name: types.ColumnNameBuiltinTimestamp, Type: schema.ValueTypeTimestamp},
+		schema.ColumnSchema{Name: types.ColumnNameGeneratedValue, Type: schema.ValueTypeFloat64},
+	)
+	a.Labels.Range(func(l labels.Label) {
+		columns = append(columns, schema.ColumnSchema{Name: l.Name, Type: schema.ValueTypeString})
+	})
+	return &schema.Schema{Columns: columns}
+}
+
+func (a *Absent) isInstruction() {}
+func (a *Absent) isValue()       {}
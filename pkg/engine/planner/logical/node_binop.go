@@ -2,18 +2,32 @@ package logical
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/grafana/loki/v3/pkg/engine/internal/types"
 	"github.com/grafana/loki/v3/pkg/engine/planner/schema"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 )
 
 // The BinOp instruction yields the result of binary operation Left Op Right.
 // BinOp implements both [Instruction] and [Value].
+//
+// BinOp is used both for expressions that are evaluated per row, such as
+// predicates, and for operations between vectors of a metric query. In the
+// latter case, at least one of Left and Right is a table relation and the
+// other one is either a table relation or a [Literal] scalar.
 type BinOp struct {
 	id string
 
 	Left, Right Value
 	Op          types.BinaryOp
+
+	// ReturnBool is set for comparison operations between vectors that
+	// return 0 or 1 instead of filtering samples.
+	ReturnBool bool
+	// VectorMatching describes how samples of vector operands are matched.
+	// It is nil for operations that are not between two vectors.
+	VectorMatching *syntax.VectorMatching
 }
 
 var (
@@ -31,7 +45,32 @@ func (b *BinOp) Name() string {
 
 // String returns the disassembled SSA form of the BinOp instruction.
 func (b *BinOp) String() string {
-	return fmt.Sprintf("%s %s %s", b.Op, b.Left.Name(), b.Right.Name())
+	props := b.properties()
+	if len(props) == 0 {
+		return fmt.Sprintf("%s %s %s", b.Op, b.Left.Name(), b.Right.Name())
+	}
+	return fmt.Sprintf("%s %s %s [%s]", b.Op, b.Left.Name(), b.Right.Name(), strings.Join(props, ", "))
+}
+
+func (b *BinOp) properties() []string {
+	var props []string
+	if b.ReturnBool {
+		props = append(props, "bool=true")
+	}
+	if m := b.VectorMatching; m != nil {
+		if m.On {
+			props = append(props, fmt.Sprintf("on=(%s)", strings.Join(m.MatchingLabels, ", ")))
+		} else if len(m.MatchingLabels) > 0 {
+			props = append(props, fmt.Sprintf("ignoring=(%s)", strings.Join(m.MatchingLabels, ", ")))
+		}
+		switch m.Card {
+		case syntax.CardManyToOne:
+			props = append(props, fmt.Sprintf("group_left=(%s)", strings.Join(m.Include, ", ")))
+		case syntax.CardOneToMany:
+			props = append(props, fmt.Sprintf("group_right=(%s)", strings.Join(m.Include, ", ")))
+		}
+	}
+	return props
 }
 
 // Schema returns the schema of the BinOp operation.
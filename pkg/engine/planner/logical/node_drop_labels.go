@@ -0,0 +1,50 @@
+package logical
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/loki/v3/pkg/engine/planner/schema"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+)
+
+// The DropLabels instruction removes the listed label, metadata and parsed
+// columns of a table relation. DropLabels implements both [Instruction] and
+// [Value].
+//
+// Labels with a matcher are only removed for rows where the matcher matches
+// the value of the label.
+type DropLabels struct {
+	id string
+
+	Table Value // The table relation to remove labels from.
+
+	// Labels are the names and matchers of the labels to remove.
+	Labels []log.NamedLabelMatcher
+}
+
+var (
+	_ Value       = (*DropLabels)(nil)
+	_ Instruction = (*DropLabels)(nil)
+)
+
+// Name returns an identifier for the DropLabels operation.
+func (d *DropLabels) Name() string {
+	if d.id != "" {
+		return d.id
+	}
+	return fmt.Sprintf("%p", d)
+}
+
+// String returns the disassembled SSA form of the DropLabels instruction.
+func (d *DropLabels) String() string {
+	return fmt.Sprintf("DROP %s [labels=(%s)]", d.Table.Name(), strings.Join(formatNamedLabelMatchers(d.Labels), ", "))
+}
+
+// Schema returns the schema of the DropLabels plan.
+func (d *DropLabels) Schema() *schema.Schema {
+	return d.Table.Schema()
+}
+
+func (d *DropLabels) isInstruction() {}
+func (d *DropLabels) isValue()       {}
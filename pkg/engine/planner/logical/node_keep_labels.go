@@ -0,0 +1,64 @@
+package logical
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/loki/v3/pkg/engine/planner/schema"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+)
+
+// The KeepLabels instruction removes all label, metadata and parsed columns
+// of a table relation except the listed ones. KeepLabels implements both
+// [Instruction] and [Value].
+//
+// Labels with a matcher are only kept for rows where the matcher matches the
+// value of the label.
+type KeepLabels struct {
+	id string
+
+	Table Value // The table relation to remove labels from.
+
+	// Labels are the names and matchers of the labels to keep.
+	Labels []log.NamedLabelMatcher
+}
+
+var (
+	_ Value       = (*KeepLabels)(nil)
+	_ Instruction = (*KeepLabels)(nil)
+)
+
+// Name returns an identifier for the KeepLabels operation.
+func (k *KeepLabels) Name() string {
+	if k.id != "" {
+		return k.id
+	}
+	return fmt.Sprintf("%p", k)
+}
+
+// String returns the disassembled SSA form of the KeepLabels instruction.
+func (k *KeepLabels) String() string {
+	return fmt.Sprintf("KEEP %s [labels=(%s)]", k.Table.Name(), strings.Join(formatNamedLabelMatchers(k.Labels), ", "))
+}
+
+// Schema returns the schema of the KeepLabels plan.
+func (k *KeepLabels) Schema() *schema.Schema {
+	return k.Table.Schema()
+}
+
+func (k *KeepLabels) isInstruction() {}
+func (k *KeepLabels) isValue()       {}
+
+// formatNamedLabelMatchers returns the string representation of each label,
+// which is either the name of the label or its matcher.
+func formatNamedLabelMatchers(lbls []log.NamedLabelMatcher) []string {
+	res := make([]string, len(lbls))
+	for i, lbl := range lbls {
+		if lbl.Matcher != nil {
+			res[i] = lbl.Matcher.String()
+			continue
+		}
+		res[i] = lbl.Name
+	}
+	return res
+}
@@ -0,0 +1,67 @@
+package logical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/loki/v3/pkg/engine/planner/schema"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+)
+
+// The LabelFormat instruction renames labels or sets labels to the result of
+// a text template for each row of a table relation. LabelFormat implements
+// both [Instruction] and [Value].
+//
+// The formatted labels are added as columns of type [types.ColumnTypeParsed],
+// like the labels of the LabelFormat stage of the LogQL engine.
+type LabelFormat struct {
+	id string
+
+	Table Value // The table relation to format.
+
+	// Formats are the label renames and label templates of the stage.
+	Formats []log.LabelFmt
+}
+
+var (
+	_ Value       = (*LabelFormat)(nil)
+	_ Instruction = (*LabelFormat)(nil)
+)
+
+// Name returns an identifier for the LabelFormat operation.
+func (f *LabelFormat) Name() string {
+	if f.id != "" {
+		return f.id
+	}
+	return fmt.Sprintf("%p", f)
+}
+
+// String returns the disassembled SSA form of the LabelFormat instruction.
+func (f *LabelFormat) String() string {
+	return fmt.Sprintf("LABEL_FORMAT %s [formats=(%s)]", f.Table.Name(), strings.Join(formatLabelFmts(f.Formats), ", "))
+}
+
+// Schema returns the schema of the LabelFormat plan.
+func (f *LabelFormat) Schema() *schema.Schema {
+	// The formatted labels are only known for renames, but not whether the
+	// source label exists. Therefore the schema is left unchanged.
+	return f.Table.Schema()
+}
+
+func (f *LabelFormat) isInstruction() {}
+func (f *LabelFormat) isValue()       {}
+
+// formatLabelFmts returns the string representation of each label format,
+// which is dst=src for renames and dst="template" for templates.
+func formatLabelFmts(fmts []log.LabelFmt) []string {
+	res := make([]string, len(fmts))
+	for i, f := range fmts {
+		if f.Rename {
+			res[i] = fmt.Sprintf("%s=%s", f.Name, f.Value)
+			continue
+		}
+		res[i] = fmt.Sprintf("%s=%s", f.Name, strconv.Quote(f.Value))
+	}
+	return res
+}
@@ -0,0 +1,54 @@
+package logical
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/grafana/loki/v3/pkg/engine/planner/schema"
+)
+
+// The LabelReplace instruction sets the label Dst of each sample of a table
+// relation to Replacement, if the value of the label Src matches Regex. The
+// Replacement may reference capture groups of Regex with $1, $2, etc.
+// LabelReplace implements both [Instruction] and [Value].
+type LabelReplace struct {
+	id string
+
+	Table Value // The table relation to modify.
+
+	Dst         string // The label to set.
+	Replacement string // The template of the new value.
+	Src         string // The label to match Regex against.
+	Regex       string // The regular expression, anchored at both ends.
+}
+
+var (
+	_ Value       = (*LabelReplace)(nil)
+	_ Instruction = (*LabelReplace)(nil)
+)
+
+// Name returns an identifier for the LabelReplace operation.
+func (l *LabelReplace) Name() string {
+	if l.id != "" {
+		return l.id
+	}
+	return fmt.Sprintf("%p", l)
+}
+
+// String returns the disassembled SSA form of the LabelReplace instruction.
+func (l *LabelReplace) String() string {
+	return fmt.Sprintf(
+		"LABEL_REPLACE %s [dst=%s, replacement=%s, src=%s, regex=%s]",
+		l.Table.Name(), l.Dst, strconv.Quote(l.Replacement), l.Src, strconv.Quote(l.Regex),
+	)
+}
+
+// Schema returns the schema of the LabelReplace plan.
+func (l *LabelReplace) Schema() *schema.Schema {
+	// The destination label may be a new column, which is only known to
+	// exist once the regular expression matched at execution time.
+	return l.Table.Schema()
+}
+
+func (l *LabelReplace) isInstruction() {}
+func (l *LabelReplace) isValue()       {}
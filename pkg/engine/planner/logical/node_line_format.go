@@ -0,0 +1,47 @@
+package logical
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/grafana/loki/v3/pkg/engine/planner/schema"
+)
+
+// The LineFormat instruction replaces the log line of each row of a table
+// relation with the result of a text template. LineFormat implements both
+// [Instruction] and [Value].
+type LineFormat struct {
+	id string
+
+	Table Value // The table relation to format.
+
+	// Template is the text template that is executed with the columns of a
+	// row to produce the new log line.
+	Template string
+}
+
+var (
+	_ Value       = (*LineFormat)(nil)
+	_ Instruction = (*LineFormat)(nil)
+)
+
+// Name returns an identifier for the LineFormat operation.
+func (f *LineFormat) Name() string {
+	if f.id != "" {
+		return f.id
+	}
+	return fmt.Sprintf("%p", f)
+}
+
+// String returns the disassembled SSA form of the LineFormat instruction.
+func (f *LineFormat) String() string {
+	return fmt.Sprintf("LINE_FORMAT %s [template=%s]", f.Table.Name(), strconv.Quote(f.Template))
+}
+
+// Schema returns the schema of the LineFormat plan.
+func (f *LineFormat) Schema() *schema.Schema {
+	return f.Table.Schema()
+}
+
+func (f *LineFormat) isInstruction() {}
+func (f *LineFormat) isValue()       {}
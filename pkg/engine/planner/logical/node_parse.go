@@ -0,0 +1,87 @@
+package logical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/engine/planner/schema"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+)
+
+// The Parse instruction parses the log line of each row of a table relation
+// and adds the extracted values as new columns. Parse implements both
+// [Instruction] and [Value].
+//
+// The names of the extracted columns are only known at execution time,
+// therefore they are referenced with [types.ColumnTypeAmbiguous] by
+// subsequent instructions.
+type Parse struct {
+	id string
+
+	Table Value // The table relation to parse.
+
+	// Kind is the kind of parser to apply to the log line.
+	Kind types.ParserKind
+
+	// Pattern is the expression of the regexp and pattern parsers.
+	Pattern string
+
+	// Expressions are the extraction expressions of the json and logfmt
+	// parsers. If empty, all values are extracted.
+	Expressions []log.LabelExtractionExpr
+
+	// Strict and KeepEmpty are the flags of the logfmt parser.
+	Strict    bool
+	KeepEmpty bool
+}
+
+var (
+	_ Value       = (*Parse)(nil)
+	_ Instruction = (*Parse)(nil)
+)
+
+// Name returns an identifier for the Parse operation.
+func (p *Parse) Name() string {
+	if p.id != "" {
+		return p.id
+	}
+	return fmt.Sprintf("%p", p)
+}
+
+// String returns the disassembled SSA form of the Parse instruction.
+func (p *Parse) String() string {
+	return fmt.Sprintf("PARSE %s [%s]", p.Table.Name(), strings.Join(p.properties(), ", "))
+}
+
+func (p *Parse) properties() []string {
+	props := []string{fmt.Sprintf("kind=%s", p.Kind)}
+	if p.Pattern != "" {
+		props = append(props, fmt.Sprintf("pattern=%s", strconv.Quote(p.Pattern)))
+	}
+	if len(p.Expressions) > 0 {
+		expressions := make([]string, len(p.Expressions))
+		for i, expr := range p.Expressions {
+			expressions[i] = fmt.Sprintf("%s=%s", expr.Identifier, strconv.Quote(expr.Expression))
+		}
+		props = append(props, fmt.Sprintf("expressions=(%s)", strings.Join(expressions, ", ")))
+	}
+	if p.Strict {
+		props = append(props, "strict=true")
+	}
+	if p.KeepEmpty {
+		props = append(props, "keep_empty=true")
+	}
+	return props
+}
+
+// Schema returns the schema of the Parse plan.
+func (p *Parse) Schema() *schema.Schema {
+	// The columns added by the parser depend on the log lines and are not
+	// known at planning time.
+	return p.Table.Schema()
+}
+
+func (p *Parse) isInstruction() {}
+func (p *Parse) isValue()       {}
@@ -21,10 +21,13 @@ type RangeAggregation struct {
 	PartitionBy []ColumnRef // The columns to partition by.
 
 	Operation     types.RangeAggregationType // The type of aggregation operation to perform.
+	Value         Value                      // The value to aggregate for each row. Nil if rows are counted.
+	Parameter     float64                    // The parameter of the operation, such as the φ-quantile of quantile_over_time.
 	Start         time.Time
 	End           time.Time
 	Step          time.Duration
 	RangeInterval time.Duration
+	Offset        time.Duration // The offset modifier of the range, windows are evaluated at t-Offset.
 }
 
 var (
@@ -42,7 +45,17 @@ func (r *RangeAggregation) Name() string {
 
 // String returns the disassembled SSA form of the RangeAggregation instruction.
 func (r *RangeAggregation) String() string {
-	props := fmt.Sprintf("operation=%s, start_ts=%s, end_ts=%s, step=%s, range=%s", r.Operation, util.FormatTimeRFC3339Nano(r.Start), util.FormatTimeRFC3339Nano(r.End), r.Step, r.RangeInterval)
+	props := fmt.Sprintf("operation=%s", r.Operation)
+	if r.Value != nil {
+		props += fmt.Sprintf(", value=%s", r.Value.Name())
+	}
+	if r.Operation == types.RangeAggregationTypeQuantile {
+		props += fmt.Sprintf(", parameter=%v", r.Parameter)
+	}
+	props += fmt.Sprintf(", start_ts=%s, end_ts=%s, step=%s, range=%s", util.FormatTimeRFC3339Nano(r.Start), util.FormatTimeRFC3339Nano(r.End), r.Step, r.RangeInterval)
+	if r.Offset != 0 {
+		props += fmt.Sprintf(", offset=%s", r.Offset)
+	}
 
 	if len(r.PartitionBy) > 0 {
 		partitionBy := ""
@@ -77,11 +90,11 @@ func (r *RangeAggregation) Schema() *schema.Schema {
 			Name: types.ColumnNameBuiltinTimestamp,
 			Type: schema.ValueTypeTimestamp,
 		})
-		// Using int64 since only count_over_time is supported.
 		outputSchema.Columns = append(outputSchema.Columns, schema.ColumnSchema{
 			Name: types.ColumnNameGeneratedValue,
-			Type: schema.ValueTypeInt64,
+			Type: schema.ValueTypeFloat64,
 		})
+		return &outputSchema
 	}
 
 	// If partition by is empty, we aggregate by query-time series.
@@ -98,7 +111,7 @@ func (r *RangeAggregation) Schema() *schema.Schema {
 	}
 	outputSchema.Columns = append(outputSchema.Columns, schema.ColumnSchema{
 		Name: types.ColumnNameGeneratedValue,
-		Type: schema.ValueTypeInt64,
+		Type: schema.ValueTypeFloat64,
 	})
 	return &outputSchema
 }
@@ -0,0 +1,55 @@
+package logical
+
+import (
+	"fmt"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/engine/planner/schema"
+)
+
+// The Unwrap instruction converts the values of a column of a table relation
+// into the sample values of a range aggregation. Unwrap implements both
+// [Instruction] and [Value].
+//
+// The converted values are added as the generated column
+// [types.ColumnNameGeneratedUnwrapped]. Rows whose value cannot be converted
+// have no value, and the conversion error is added as parsed __error__ and
+// __error_details__ columns, like with the unwrap stage of the LogQL engine.
+// This allows filtering these rows with label filters after the Unwrap.
+type Unwrap struct {
+	id string
+
+	Table Value    // The table relation to unwrap.
+	Value *UnaryOp // The conversion of the unwrapped column.
+}
+
+var (
+	_ Value       = (*Unwrap)(nil)
+	_ Instruction = (*Unwrap)(nil)
+)
+
+// Name returns an identifier for the Unwrap operation.
+func (u *Unwrap) Name() string {
+	if u.id != "" {
+		return u.id
+	}
+	return fmt.Sprintf("%p", u)
+}
+
+// String returns the disassembled SSA form of the Unwrap instruction.
+func (u *Unwrap) String() string {
+	return fmt.Sprintf("UNWRAP %s [value=%s]", u.Table.Name(), u.Value.Name())
+}
+
+// Schema returns the schema of the Unwrap plan.
+func (u *Unwrap) Schema() *schema.Schema {
+	var columns []schema.ColumnSchema
+	if s := u.Table.Schema(); s != nil {
+		columns = append(columns, s.Columns...)
+	}
+	columns = append(columns, schema.ColumnSchema{Name: types.ColumnNameGeneratedUnwrapped, Type: schema.ValueTypeFloat64})
+	return &schema.Schema{Columns: columns}
+}
+
+func (u *Unwrap) isInstruction() {}
+func (u *Unwrap) isValue()       {}
@@ -17,9 +17,13 @@ type VectorAggregation struct {
 
 	// The columns to group by. If empty, all rows are aggregated into a single result.
 	GroupBy []ColumnRef
+	// Without inverts the grouping: rows are grouped by all columns except the GroupBy columns.
+	Without bool
 
 	// The type of aggregation operation to perform (e.g., sum, min, max)
 	Operation types.VectorAggregationType
+	// The parameter of the aggregation, such as k for topk and bottomk.
+	Parameter int
 }
 
 var (
@@ -38,8 +42,20 @@ func (v *VectorAggregation) Name() string {
 // String returns the disassembled SSA form of the VectorAggregation instruction.
 func (v *VectorAggregation) String() string {
 	props := fmt.Sprintf("operation=%s", v.Operation)
+	if v.Operation == types.VectorAggregationTypeTopK || v.Operation == types.VectorAggregationTypeBottomK {
+		props += fmt.Sprintf(", parameter=%d", v.Parameter)
+	}
 
-	if len(v.GroupBy) > 0 {
+	if v.Without {
+		without := ""
+		for i, columnRef := range v.GroupBy {
+			if i > 0 {
+				without += ", "
+			}
+			without += columnRef.String()
+		}
+		props += fmt.Sprintf(", without=(%s)", without)
+	} else if len(v.GroupBy) > 0 {
 		groupBy := ""
 		for i, columnRef := range v.GroupBy {
 			if i > 0 {
@@ -70,10 +86,16 @@ func (v *VectorAggregation) Schema() *schema.Schema {
 		},
 		schema.ColumnSchema{
 			Name: types.ColumnNameGeneratedValue,
-			Type: schema.ValueTypeInt64,
+			Type: schema.ValueTypeFloat64,
 		},
 	)
 
+	// The output columns are only known at execution time when grouping
+	// without columns, or when the input rows are returned as is.
+	if v.Without || !v.Operation.IsGrouping() {
+		return &outputSchema
+	}
+
 	// Add group by columns
 	for _, columnRef := range v.GroupBy {
 		outputSchema.Columns = append(outputSchema.Columns,
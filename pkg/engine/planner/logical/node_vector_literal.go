@@ -0,0 +1,58 @@
+package logical
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/engine/internal/util"
+	"github.com/grafana/loki/v3/pkg/engine/planner/schema"
+)
+
+// The VectorLiteral instruction yields a table relation with a single series
+// without labels, that has the same value at each step of the query. It is
+// the result of the vector(x) function. VectorLiteral implements both
+// [Instruction] and [Value].
+type VectorLiteral struct {
+	id string
+
+	Value float64 // The value of each sample.
+
+	Start time.Time     // The timestamp of the first step.
+	End   time.Time     // The timestamp of the last step.
+	Step  time.Duration // The step between samples. Zero for instant queries.
+}
+
+var (
+	_ Value       = (*VectorLiteral)(nil)
+	_ Instruction = (*VectorLiteral)(nil)
+)
+
+// Name returns an identifier for the VectorLiteral operation.
+func (v *VectorLiteral) Name() string {
+	if v.id != "" {
+		return v.id
+	}
+	return fmt.Sprintf("%p", v)
+}
+
+// String returns the disassembled SSA form of the VectorLiteral instruction.
+func (v *VectorLiteral) String() string {
+	return fmt.Sprintf(
+		"VECTOR_LITERAL [value=%v, start_ts=%s, end_ts=%s, step=%s]",
+		v.Value, util.FormatTimeRFC3339Nano(v.Start), util.FormatTimeRFC3339Nano(v.End), v.Step,
+	)
+}
+
+// Schema returns the schema of the VectorLiteral plan.
+func (v *VectorLiteral) Schema() *schema.Schema {
+	return &schema.Schema{
+		Columns: []schema.ColumnSchema{
+			{Name: types.ColumnNameBuiltinTimestamp, Type: schema.ValueTypeTimestamp},
+			{Name: types.ColumnNameGeneratedValue, Type: schema.ValueTypeFloat64},
+		},
+	}
+}
+
+func (v *VectorLiteral) isInstruction() {}
+func (v *VectorLiteral) isValue()       {}
@@ -27,7 +27,7 @@ func BuildPlan(params logql.Params) (*Plan, error) {
 
 	switch e := params.GetExpression().(type) {
 	case syntax.LogSelectorExpr:
-		builder, err = buildPlanForLogQuery(e, params, false, 0)
+		builder, err = buildPlanForLogQuery(e, params, false, 0, 0)
 	case syntax.SampleExpr:
 		builder, err = buildPlanForSampleQuery(e, params)
 	default:
@@ -44,13 +44,29 @@ func BuildPlan(params logql.Params) (*Plan, error) {
 // buildPlanForLogQuery builds logical plan operations by traversing [syntax.LogSelectorExpr]
 // isMetricQuery should be set to true if this expr is encountered when processing a [syntax.SampleExpr].
 // rangeInterval should be set to a non-zero value if the query contains [$range].
-func buildPlanForLogQuery(expr syntax.LogSelectorExpr, params logql.Params, isMetricQuery bool, rangeInterval time.Duration) (*Builder, error) {
+// offset should be set to the offset modifier of the [$range], if any.
+func buildPlanForLogQuery(expr syntax.LogSelectorExpr, params logql.Params, isMetricQuery bool, rangeInterval, offset time.Duration) (*Builder, error) {
 	var (
 		err        error
 		selector   Value
 		predicates []Value
+
+		// stages holds the parse and format stages and all predicates that
+		// follow the first of these stages in the order of the pipeline.
+		// These predicates can reference parsed or formatted columns and can
+		// therefore not be used to resolve the data objects of the
+		// [MakeTable] instruction.
+		stages []Value
 	)
 
+	addPredicate := func(predicate Value) {
+		if len(stages) > 0 {
+			stages = append(stages, predicate)
+			return
+		}
+		predicates = append(predicates, predicate)
+	}
+
 	// TODO(chaudum): Implement a Walk function that can return an error
 	expr.Walk(func(e syntax.Expr) bool {
 		switch e := e.(type) {
@@ -61,7 +77,7 @@ func buildPlanForLogQuery(expr syntax.LogSelectorExpr, params logql.Params, isMe
 			selector = convertLabelMatchers(e.Matchers())
 			return true
 		case *syntax.LineFilterExpr:
-			predicates = append(predicates, convertLineFilterExpr(e))
+			addPredicate(convertLineFilterExpr(e))
 			// We do not want to traverse the AST further down, because line filter expressions can be nested,
 			// which would lead to multiple predicates of the same expression.
 			return false // do not traverse children
@@ -69,13 +85,27 @@ func buildPlanForLogQuery(expr syntax.LogSelectorExpr, params logql.Params, isMe
 			if val, innerErr := convertLabelFilter(e.LabelFilterer); innerErr != nil {
 				err = innerErr
 			} else {
-				predicates = append(predicates, val)
+				addPredicate(val)
 			}
 			return true
-		case *syntax.LineParserExpr, *syntax.LogfmtParserExpr, *syntax.LogfmtExpressionParserExpr, *syntax.JSONExpressionParserExpr,
-			*syntax.LineFmtExpr, *syntax.LabelFmtExpr,
-			*syntax.KeepLabelsExpr, *syntax.DropLabelsExpr:
-			err = errUnimplemented
+		case *syntax.LineParserExpr, *syntax.LogfmtParserExpr, *syntax.LogfmtExpressionParserExpr, *syntax.JSONExpressionParserExpr:
+			if val, innerErr := convertParserExpr(e); innerErr != nil {
+				err = innerErr
+			} else {
+				stages = append(stages, val)
+			}
+			return false // do not traverse children
+		case *syntax.LineFmtExpr:
+			stages = append(stages, &LineFormat{Template: e.Value})
+			return false // do not traverse children
+		case *syntax.LabelFmtExpr:
+			stages = append(stages, &LabelFormat{Formats: e.Formats})
+			return false // do not traverse children
+		case *syntax.KeepLabelsExpr:
+			stages = append(stages, &KeepLabels{Labels: e.Labels()})
+			return false // do not traverse children
+		case *syntax.DropLabelsExpr:
+			stages = append(stages, &DropLabels{Labels: e.Labels()})
 			return false // do not traverse children
 		default:
 			err = errUnimplemented
@@ -100,23 +130,26 @@ func buildPlanForLogQuery(expr syntax.LogSelectorExpr, params logql.Params, isMe
 		},
 	)
 
-	direction := params.Direction()
-	if !isMetricQuery && direction == logproto.FORWARD {
-		return nil, fmt.Errorf("forward search log queries are not supported: %w", errUnimplemented)
-	}
-
 	if !isMetricQuery {
 		// SORT -> SortMerge
-		// We always sort DESC. ASC timestamp sorting is not supported for logs
-		// queries, and metric queries do not need sorting.
-		builder = builder.Sort(*timestampColumnRef(), false, false)
+		// Log queries are sorted by timestamp in the direction of the query, so
+		// that the limit returns the oldest entries for forward queries and the
+		// newest entries for backward queries. Metric queries do not need
+		// sorting.
+		ascending := params.Direction() == logproto.FORWARD
+		builder = builder.Sort(*timestampColumnRef(), ascending, false)
 	}
 
 	// SELECT -> Filter
 	start := params.Start()
 	end := params.End()
-	// extend search by rangeInterval to be able to include entries belonging to the [$range] interval.
-	for _, value := range convertQueryRangeToPredicates(start.Add(-rangeInterval), end) {
+	timeRange := convertQueryRangeToPredicates(start, end)
+	if isMetricQuery {
+		// extend search by rangeInterval to be able to include entries belonging to the [$range] interval,
+		// and shift it by the offset of the [$range].
+		timeRange = convertWindowRangeToPredicates(start.Add(-rangeInterval-offset), end.Add(-offset))
+	}
+	for _, value := range timeRange {
 		builder = builder.Select(value)
 	}
 
@@ -124,6 +157,25 @@ func buildPlanForLogQuery(expr syntax.LogSelectorExpr, params logql.Params, isMe
 		builder = builder.Select(value)
 	}
 
+	// PARSE -> Parse
+	// LINE_FORMAT, LABEL_FORMAT, KEEP, DROP -> Projection
+	for _, value := range stages {
+		switch value := value.(type) {
+		case *Parse:
+			builder = builder.Parse(value.Kind, value.Pattern, value.Expressions, value.Strict, value.KeepEmpty)
+		case *LineFormat:
+			builder = builder.LineFormat(value.Template)
+		case *LabelFormat:
+			builder = builder.LabelFormat(value.Formats)
+		case *KeepLabels:
+			builder = builder.KeepLabels(value.Labels)
+		case *DropLabels:
+			builder = builder.DropLabels(value.Labels)
+		default:
+			builder = builder.Select(value)
+		}
+	}
+
 	// Metric queries do not apply a limit.
 	if !isMetricQuery {
 		// LIMIT -> Limit
@@ -134,50 +186,252 @@ func buildPlanForLogQuery(expr syntax.LogSelectorExpr, params logql.Params, isMe
 	return builder, nil
 }
 
+// buildPlanForSampleQuery builds logical plan operations by traversing
+// [syntax.SampleExpr]. Binary operations, vector literals and label_replace
+// are converted recursively, other sample expressions must be either
+// absent_over_time or a vector aggregation of a range aggregation.
 func buildPlanForSampleQuery(e syntax.SampleExpr, params logql.Params) (*Builder, error) {
-	if params.Step() > 0 {
-		return nil, fmt.Errorf("only instant metric queries are supported: %w", errUnimplemented)
+	switch e := e.(type) {
+	case *syntax.BinOpExpr:
+		return buildPlanForBinOp(e, params)
+	case *syntax.VectorExpr:
+		val, err := e.Value()
+		if err != nil {
+			return nil, err
+		}
+		return NewBuilder(&VectorLiteral{
+			Value: val,
+			Start: params.Start(),
+			End:   params.End(),
+			Step:  params.Step(),
+		}), nil
+	case *syntax.LabelReplaceExpr:
+		builder, err := buildPlanForSampleQuery(e.Left, params)
+		if err != nil {
+			return nil, err
+		}
+		return builder.LabelReplace(e.Dst, e.Replacement, e.Src, e.Regex), nil
+	case *syntax.LiteralExpr:
+		// scalar results are not yet supported, literals can only be used as
+		// operands of binary operations.
+		return nil, errUnimplemented
+	case *syntax.RangeAggregationExpr:
+		if e.Operation == syntax.OpRangeTypeAbsent {
+			return buildPlanForAbsent(e, params)
+		}
+		// range aggregations without vector aggregation are not yet supported.
+		return nil, errUnimplemented
+	default:
+		return buildPlanForVectorAggregation(e, params)
+	}
+}
+
+// buildPlanForBinOp builds the logical plan of a binary operation between two
+// vectors, or between a vector and a scalar literal.
+func buildPlanForBinOp(e *syntax.BinOpExpr, params logql.Params) (*Builder, error) {
+	op := convertBinaryOp(e.Op)
+	if op == types.BinaryOpInvalid {
+		return nil, errUnimplemented
+	}
+
+	var opts syntax.BinOpOptions
+	if e.Opts != nil {
+		opts = *e.Opts
+	}
+
+	leftLit, leftIsLiteral := e.SampleExpr.(*syntax.LiteralExpr)
+	rightLit, rightIsLiteral := e.RHS.(*syntax.LiteralExpr)
+
+	switch {
+	case leftIsLiteral && rightIsLiteral:
+		// scalar results are not yet supported.
+		return nil, errUnimplemented
+
+	case leftIsLiteral:
+		right, err := buildPlanForSampleQuery(e.RHS, params)
+		if err != nil {
+			return nil, err
+		}
+		return NewBuilder(&BinOp{
+			Left:       NewLiteral(leftLit.Val),
+			Right:      right.Value(),
+			Op:         op,
+			ReturnBool: opts.ReturnBool,
+		}), nil
+
+	case rightIsLiteral:
+		left, err := buildPlanForSampleQuery(e.SampleExpr, params)
+		if err != nil {
+			return nil, err
+		}
+		return left.BinOp(op, NewLiteral(rightLit.Val), opts.ReturnBool, nil), nil
 	}
 
+	left, err := buildPlanForSampleQuery(e.SampleExpr, params)
+	if err != nil {
+		return nil, err
+	}
+	right, err := buildPlanForSampleQuery(e.RHS, params)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := opts.VectorMatching
+	if matching == nil {
+		matching = &syntax.VectorMatching{Card: syntax.CardOneToOne}
+	}
+	return left.BinOp(op, right.Value(), opts.ReturnBool, matching), nil
+}
+
+func convertBinaryOp(op string) types.BinaryOp {
+	switch op {
+	case syntax.OpTypeAdd:
+		return types.BinaryOpAdd
+	case syntax.OpTypeSub:
+		return types.BinaryOpSub
+	case syntax.OpTypeMul:
+		return types.BinaryOpMul
+	case syntax.OpTypeDiv:
+		return types.BinaryOpDiv
+	case syntax.OpTypeMod:
+		return types.BinaryOpMod
+	case syntax.OpTypePow:
+		return types.BinaryOpPow
+	case syntax.OpTypeCmpEQ:
+		return types.BinaryOpEq
+	case syntax.OpTypeNEQ:
+		return types.BinaryOpNeq
+	case syntax.OpTypeGT:
+		return types.BinaryOpGt
+	case syntax.OpTypeGTE:
+		return types.BinaryOpGte
+	case syntax.OpTypeLT:
+		return types.BinaryOpLt
+	case syntax.OpTypeLTE:
+		return types.BinaryOpLte
+	case syntax.OpTypeAnd:
+		return types.BinaryOpAnd
+	case syntax.OpTypeOr:
+		return types.BinaryOpOr
+	case syntax.OpTypeUnless:
+		return types.BinaryOpUnless
+	default:
+		return types.BinaryOpInvalid
+	}
+}
+
+// buildPlanForVectorAggregation builds the logical plan of a vector
+// aggregation of a range aggregation. label_replace may be applied to the
+// result of the range aggregation before it is aggregated.
+func buildPlanForVectorAggregation(e syntax.SampleExpr, params logql.Params) (*Builder, error) {
 	var (
 		err error
 
-		rangeAggType  types.RangeAggregationType
-		rangeInterval time.Duration
-
-		vecAggType types.VectorAggregationType
-		groupBy    []ColumnRef
+		rangeAggType   types.RangeAggregationType
+		rangeInterval  time.Duration
+		rangeOffset    time.Duration
+		rangeValue     Value
+		rangeParameter float64
+		unwrap         *UnaryOp
+		unwrapLabel    string
+		partitionBy    []ColumnRef
+		postFilters    []Value
+
+		vecAggType      types.VectorAggregationType
+		vecAggParameter int
+		groupBy         []ColumnRef
+		without         bool
+
+		// labelReplaces holds the label_replace expressions between the
+		// vector aggregation and the range aggregation, outermost first.
+		labelReplaces []*syntax.LabelReplaceExpr
 	)
 
 	e.Walk(func(e syntax.Expr) bool {
 		switch e := e.(type) {
 		case *syntax.RangeAggregationExpr:
-			// only count operation is supported for range aggregation.
-			// offsets are not yet supported.
-			if e.Operation != syntax.OpRangeTypeCount || e.Left.Offset != 0 {
+			rangeAggType = convertRangeAggregationType(e.Operation)
+			if rangeAggType == types.RangeAggregationTypeInvalid {
+				err = errUnimplemented
+				return false
+			}
+			// the @ modifier is not yet supported.
+			if e.Left.At != nil {
 				err = errUnimplemented
 				return false
 			}
-
-			rangeAggType = types.RangeAggregationTypeCount
 			rangeInterval = e.Left.Interval
+			rangeOffset = e.Left.Offset
+			if e.Params != nil {
+				rangeParameter = *e.Params
+			}
+
+			if e.Grouping != nil {
+				// grouping by an empty label set and grouping without labels are not yet supported.
+				if len(e.Grouping.Groups) == 0 || e.Grouping.Without {
+					err = errUnimplemented
+					return false
+				}
+
+				partitionBy = make([]ColumnRef, 0, len(e.Grouping.Groups))
+				for _, group := range e.Grouping.Groups {
+					partitionBy = append(partitionBy, *NewColumnRef(group, types.ColumnTypeAmbiguous))
+				}
+			}
+
+			switch {
+			case e.Left.Unwrap != nil:
+				unwrap, err = convertUnwrap(e.Left.Unwrap)
+				if err != nil {
+					return false
+				}
+				unwrapLabel = e.Left.Unwrap.Identifier
+				rangeValue = NewColumnRef(types.ColumnNameGeneratedUnwrapped, types.ColumnTypeGenerated)
+
+				for _, filter := range e.Left.Unwrap.PostFilters {
+					val, innerErr := convertLabelFilter(filter)
+					if innerErr != nil {
+						err = innerErr
+						return false
+					}
+					postFilters = append(postFilters, val)
+				}
+			case rangeAggType == types.RangeAggregationTypeBytes || rangeAggType == types.RangeAggregationTypeBytesRate:
+				rangeValue = &UnaryOp{Op: types.UnaryOpLength, Value: lineColumnRef()}
+			}
+
 			return false // do not traverse log range query
 
 		case *syntax.VectorAggregationExpr:
-			// only sum operation is supported for vector aggregation
-			// grouping by atleast one label is required.
-			if e.Operation != syntax.OpTypeSum ||
-				e.Grouping == nil || len(e.Grouping.Groups) == 0 || e.Grouping.Without {
+			// nested vector aggregations are not yet supported.
+			if vecAggType != types.VectorAggregationTypeInvalid {
 				err = errUnimplemented
 				return false
 			}
 
-			vecAggType = types.VectorAggregationTypeSum
-			groupBy = make([]ColumnRef, 0, len(e.Grouping.Groups))
-			for _, group := range e.Grouping.Groups {
-				groupBy = append(groupBy, *NewColumnRef(group, types.ColumnTypeAmbiguous))
+			vecAggType = convertVectorAggregationType(e.Operation)
+			if vecAggType == types.VectorAggregationTypeInvalid {
+				err = errUnimplemented
+				return false
+			}
+			vecAggParameter = e.Params
+
+			if e.Grouping != nil {
+				without = e.Grouping.Without
+				groupBy = make([]ColumnRef, 0, len(e.Grouping.Groups))
+				for _, group := range e.Grouping.Groups {
+					groupBy = append(groupBy, *NewColumnRef(group, types.ColumnTypeAmbiguous))
+				}
 			}
 
+			return true
+		case *syntax.LabelReplaceExpr:
+			// label_replace is only supported within a vector aggregation.
+			if vecAggType == types.VectorAggregationTypeInvalid {
+				err = errUnimplemented
+				return false
+			}
+			labelReplaces = append(labelReplaces, e)
 			return true
 		default:
 			err = errUnimplemented
@@ -197,18 +451,201 @@ func buildPlanForSampleQuery(e syntax.SampleExpr, params logql.Params) (*Builder
 		return nil, err
 	}
 
-	builder, err := buildPlanForLogQuery(logSelectorExpr, params, true, rangeInterval)
+	builder, err := buildPlanForLogQuery(logSelectorExpr, params, true, rangeInterval, rangeOffset)
 	if err != nil {
 		return nil, err
 	}
 
+	// UNWRAP -> Projection
+	// The conversion errors of unwrap are added as labels before the post
+	// filters, so that they can be filtered like in the LogQL engine.
+	if unwrap != nil {
+		builder = builder.Unwrap(unwrap)
+	}
+	for _, value := range postFilters {
+		builder = builder.Select(value)
+	}
+	if unwrap != nil && len(partitionBy) == 0 {
+		// The unwrapped label is not part of the resulting series.
+		builder = builder.DropLabels([]log.NamedLabelMatcher{log.NewNamedLabelMatcher(nil, unwrapLabel)})
+	}
+
 	builder = builder.RangeAggregation(
-		nil, rangeAggType, params.Start(), params.End(), params.Step(), rangeInterval,
-	).VectorAggregation(groupBy, vecAggType)
+		partitionBy, rangeAggType, rangeValue, rangeParameter, params.Start(), params.End(), params.Step(), rangeInterval, rangeOffset,
+	)
+	for i := len(labelReplaces) - 1; i >= 0; i-- {
+		lr := labelReplaces[i]
+		builder = builder.LabelReplace(lr.Dst, lr.Replacement, lr.Src, lr.Regex)
+	}
+	builder = builder.VectorAggregation(groupBy, without, vecAggType, vecAggParameter)
 
 	return builder, nil
 }
 
+// buildPlanForAbsent builds the logical plan of absent_over_time. The entries
+// of the log range are counted, and the [Absent] operation yields a sample for
+// each step without entries.
+func buildPlanForAbsent(e *syntax.RangeAggregationExpr, params logql.Params) (*Builder, error) {
+	// absent_over_time of unwrapped ranges and the @ modifier are not yet supported.
+	if e.Left.Unwrap != nil || e.Left.At != nil {
+		return nil, errUnimplemented
+	}
+
+	logSelectorExpr, err := e.Selector()
+	if err != nil {
+		return nil, err
+	}
+
+	builder, err := buildPlanForLogQuery(logSelectorExpr, params, true, e.Left.Interval, e.Left.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	// The absent series is labelled with the equality matchers of the
+	// selector, which are the same for all entries. Partitioning by them
+	// results in a single partition instead of one partition per series.
+	lbls := convertAbsentLabels(logSelectorExpr.Matchers())
+
+	partitionBy := make([]ColumnRef, 0, lbls.Len())
+	lbls.Range(func(l labels.Label) {
+		partitionBy = append(partitionBy, *NewColumnRef(l.Name, types.ColumnTypeAmbiguous))
+	})
+
+	builder = builder.RangeAggregation(
+		partitionBy, types.RangeAggregationTypeCount, nil, 0, params.Start(), params.End(), params.Step(), e.Left.Interval, e.Left.Offset,
+	)
+	return builder.Absent(lbls, params.Start(), params.End(), params.Step()), nil
+}
+
+// convertAbsentLabels returns the labels of the series returned by
+// absent_over_time, like [logql.absentLabels]. These are the labels of the
+// equality matchers, except for labels with multiple matchers.
+func convertAbsentLabels(matchers []*labels.Matcher) labels.Labels {
+	var (
+		builder = labels.NewScratchBuilder(len(matchers))
+		seen    = make(map[string]int, len(matchers))
+	)
+	for _, m := range matchers {
+		seen[m.Name]++
+	}
+	for _, m := range matchers {
+		if m.Name == labels.MetricName || m.Type != labels.MatchEqual || seen[m.Name] > 1 {
+			continue
+		}
+		builder.Add(m.Name, m.Value)
+	}
+	builder.Sort()
+	return builder.Labels()
+}
+
+func convertVectorAggregationType(op string) types.VectorAggregationType {
+	switch op {
+	case syntax.OpTypeSum:
+		return types.VectorAggregationTypeSum
+	case syntax.OpTypeAvg:
+		return types.VectorAggregationTypeAvg
+	case syntax.OpTypeMin:
+		return types.VectorAggregationTypeMin
+	case syntax.OpTypeMax:
+		return types.VectorAggregationTypeMax
+	case syntax.OpTypeCount:
+		return types.VectorAggregationTypeCount
+	case syntax.OpTypeStddev:
+		return types.VectorAggregationTypeStdDev
+	case syntax.OpTypeStdvar:
+		return types.VectorAggregationTypeStdVar
+	case syntax.OpTypeTopK:
+		return types.VectorAggregationTypeTopK
+	case syntax.OpTypeBottomK:
+		return types.VectorAggregationTypeBottomK
+	case syntax.OpTypeSort:
+		return types.VectorAggregationTypeSort
+	case syntax.OpTypeSortDesc:
+		return types.VectorAggregationTypeSortDesc
+	default:
+		return types.VectorAggregationTypeInvalid
+	}
+}
+
+func convertRangeAggregationType(op string) types.RangeAggregationType {
+	switch op {
+	case syntax.OpRangeTypeCount:
+		return types.RangeAggregationTypeCount
+	case syntax.OpRangeTypeRate:
+		return types.RangeAggregationTypeRate
+	case syntax.OpRangeTypeBytes:
+		return types.RangeAggregationTypeBytes
+	case syntax.OpRangeTypeBytesRate:
+		return types.RangeAggregationTypeBytesRate
+	case syntax.OpRangeTypeSum:
+		return types.RangeAggregationTypeSum
+	case syntax.OpRangeTypeAvg:
+		return types.RangeAggregationTypeAvg
+	case syntax.OpRangeTypeMin:
+		return types.RangeAggregationTypeMin
+	case syntax.OpRangeTypeMax:
+		return types.RangeAggregationTypeMax
+	case syntax.OpRangeTypeFirst:
+		return types.RangeAggregationTypeFirst
+	case syntax.OpRangeTypeLast:
+		return types.RangeAggregationTypeLast
+	case syntax.OpRangeTypeStddev:
+		return types.RangeAggregationTypeStdDev
+	case syntax.OpRangeTypeStdvar:
+		return types.RangeAggregationTypeStdVar
+	case syntax.OpRangeTypeQuantile:
+		return types.RangeAggregationTypeQuantile
+	default:
+		return types.RangeAggregationTypeInvalid
+	}
+}
+
+// convertUnwrap converts an [syntax.UnwrapExpr] into a unary operation that
+// casts the unwrapped column into a numeric value.
+func convertUnwrap(expr *syntax.UnwrapExpr) (*UnaryOp, error) {
+	var op types.UnaryOp
+	switch expr.Operation {
+	case "":
+		op = types.UnaryOpCastFloat
+	case syntax.OpConvBytes:
+		op = types.UnaryOpCastBytes
+	case syntax.OpConvDuration, syntax.OpConvDurationSeconds:
+		op = types.UnaryOpCastDuration
+	default:
+		return nil, fmt.Errorf("unsupported unwrap conversion %q: %w", expr.Operation, errUnimplemented)
+	}
+
+	return &UnaryOp{
+		Op:    op,
+		Value: NewColumnRef(expr.Identifier, types.ColumnTypeAmbiguous),
+	}, nil
+}
+
+// convertParserExpr converts a parser stage expression into a [Parse]
+// instruction without an input table.
+func convertParserExpr(expr syntax.Expr) (*Parse, error) {
+	switch e := expr.(type) {
+	case *syntax.LineParserExpr:
+		switch e.Op {
+		case syntax.OpParserTypeJSON:
+			return &Parse{Kind: types.ParserKindJSON}, nil
+		case syntax.OpParserTypeRegexp:
+			return &Parse{Kind: types.ParserKindRegexp, Pattern: e.Param}, nil
+		case syntax.OpParserTypePattern:
+			return &Parse{Kind: types.ParserKindPattern, Pattern: e.Param}, nil
+		case syntax.OpParserTypeUnpack:
+			return &Parse{Kind: types.ParserKindUnpack}, nil
+		}
+	case *syntax.LogfmtParserExpr:
+		return &Parse{Kind: types.ParserKindLogfmt, Strict: e.Strict, KeepEmpty: e.KeepEmpty}, nil
+	case *syntax.LogfmtExpressionParserExpr:
+		return &Parse{Kind: types.ParserKindLogfmt, Expressions: e.Expressions, Strict: e.Strict, KeepEmpty: e.KeepEmpty}, nil
+	case *syntax.JSONExpressionParserExpr:
+		return &Parse{Kind: types.ParserKindJSON, Expressions: e.Expressions}, nil
+	}
+	return nil, fmt.Errorf("unsupported parser %s: %w", expr, errUnimplemented)
+}
+
 func convertLabelMatchers(matchers []*labels.Matcher) Value {
 	var value *BinOp
 
@@ -368,6 +805,23 @@ func convertQueryRangeToPredicates(start, end time.Time) []*BinOp {
 	}
 }
 
+// convertWindowRangeToPredicates returns the predicates for the time range
+// (start, end], which is the range covered by the windows of metric queries.
+func convertWindowRangeToPredicates(start, end time.Time) []*BinOp {
+	return []*BinOp{
+		{
+			Left:  timestampColumnRef(),
+			Right: NewLiteral(datatype.Timestamp(start.UTC().UnixNano())),
+			Op:    types.BinaryOpGt,
+		},
+		{
+			Left:  timestampColumnRef(),
+			Right: NewLiteral(datatype.Timestamp(end.UTC().UnixNano())),
+			Op:    types.BinaryOpLte,
+		},
+	}
+}
+
 func parseShards(shards []string) (*ShardInfo, error) {
 	if len(shards) == 0 {
 		return noShard, nil
@@ -83,7 +83,7 @@ func TestConvertAST_Success(t *testing.T) {
 		statement: `{cluster="prod", namespace=~"loki-.*"} | foo="bar" or bar="baz" |= "metric.go" |= "foo" or "bar" !~ "(a|b|c)" `,
 		start:     3600,
 		end:       7200,
-		direction: logproto.BACKWARD, // ASC is not supported
+		direction: logproto.BACKWARD,
 		limit:     1000,
 	}
 	logicalPlan, err := BuildPlan(q)
@@ -121,6 +121,117 @@ RETURN %20
 	t.Logf("\n%s\n", sb.String())
 }
 
+func TestConvertAST_Parse_Success(t *testing.T) {
+	q := &query{
+		statement: `{cluster="prod"} |= "metric.go" | logfmt --strict | level="error" | regexp "took (?P<duration>.+)" | duration != ""`,
+		start:     3600,
+		end:       7200,
+		direction: logproto.BACKWARD,
+		limit:     1000,
+	}
+	logicalPlan, err := BuildPlan(q)
+	require.NoError(t, err)
+	t.Logf("\n%s\n", logicalPlan.String())
+
+	// Only the line filter before the first parser is passed as predicate to
+	// MAKETABLE, because later predicates can reference parsed columns.
+	expected := `%1 = EQ label.cluster "prod"
+%2 = MAKETABLE [selector=%1, predicates=[%8], shard=0_of_1]
+%3 = SORT %2 [column=builtin.timestamp, asc=false, nulls_first=false]
+%4 = GTE builtin.timestamp 1970-01-01T01:00:00Z
+%5 = SELECT %3 [predicate=%4]
+%6 = LT builtin.timestamp 1970-01-01T02:00:00Z
+%7 = SELECT %5 [predicate=%6]
+%8 = MATCH_STR builtin.message "metric.go"
+%9 = SELECT %7 [predicate=%8]
+%10 = PARSE %9 [kind=logfmt, strict=true]
+%11 = EQ ambiguous.level "error"
+%12 = SELECT %10 [predicate=%11]
+%13 = PARSE %12 [kind=regexp, pattern="took (?P<duration>.+)"]
+%14 = NEQ ambiguous.duration ""
+%15 = SELECT %13 [predicate=%14]
+%16 = LIMIT %15 [skip=0, fetch=1000]
+RETURN %16
+`
+
+	require.Equal(t, expected, logicalPlan.String())
+
+	var sb strings.Builder
+	PrintTree(&sb, logicalPlan.Value())
+
+	t.Logf("\n%s\n", sb.String())
+}
+
+func TestConvertAST_Forward_Success(t *testing.T) {
+	q := &query{
+		statement: `{cluster="prod"} |= "metric.go"`,
+		start:     3600,
+		end:       7200,
+		direction: logproto.FORWARD,
+		limit:     100,
+	}
+	logicalPlan, err := BuildPlan(q)
+	require.NoError(t, err)
+	t.Logf("\n%s\n", logicalPlan.String())
+
+	// Forward queries sort ascending by timestamp, so the limit returns the
+	// oldest entries of the range [start, end).
+	expected := `%1 = EQ label.cluster "prod"
+%2 = MAKETABLE [selector=%1, predicates=[%8], shard=0_of_1]
+%3 = SORT %2 [column=builtin.timestamp, asc=true, nulls_first=false]
+%4 = GTE builtin.timestamp 1970-01-01T01:00:00Z
+%5 = SELECT %3 [predicate=%4]
+%6 = LT builtin.timestamp 1970-01-01T02:00:00Z
+%7 = SELECT %5 [predicate=%6]
+%8 = MATCH_STR builtin.message "metric.go"
+%9 = SELECT %7 [predicate=%8]
+%10 = LIMIT %9 [skip=0, fetch=100]
+RETURN %10
+`
+
+	require.Equal(t, expected, logicalPlan.String())
+}
+
+func TestConvertAST_Format_Success(t *testing.T) {
+	q := &query{
+		statement: `{cluster="prod"} | json | label_format svc=app, msg="{{.level}}: {{__line__}}" | drop level="debug" | keep svc, msg | line_format "{{.msg}}" |= "error"`,
+		start:     3600,
+		end:       7200,
+		direction: logproto.BACKWARD,
+		limit:     1000,
+	}
+	logicalPlan, err := BuildPlan(q)
+	require.NoError(t, err)
+	t.Logf("\n%s\n", logicalPlan.String())
+
+	// The line filter after line_format references the formatted log line
+	// and is therefore not passed as predicate to MAKETABLE.
+	expected := `%1 = EQ label.cluster "prod"
+%2 = MAKETABLE [selector=%1, predicates=[], shard=0_of_1]
+%3 = SORT %2 [column=builtin.timestamp, asc=false, nulls_first=false]
+%4 = GTE builtin.timestamp 1970-01-01T01:00:00Z
+%5 = SELECT %3 [predicate=%4]
+%6 = LT builtin.timestamp 1970-01-01T02:00:00Z
+%7 = SELECT %5 [predicate=%6]
+%8 = PARSE %7 [kind=json]
+%9 = LABEL_FORMAT %8 [formats=(svc=app, msg="{{.level}}: {{__line__}}")]
+%10 = DROP %9 [labels=(level="debug")]
+%11 = KEEP %10 [labels=(svc, msg)]
+%12 = LINE_FORMAT %11 [template="{{.msg}}"]
+%13 = MATCH_STR builtin.message "error"
+%14 = SELECT %12 [predicate=%13]
+%15 = LIMIT %14 [skip=0, fetch=1000]
+RETURN %15
+`
+
+	require.Equal(t, expected, logicalPlan.String())
+
+	var sb strings.Builder
+	PrintTree(&sb, logicalPlan.Value())
+
+	t.Logf("\n%s\n", sb.String())
+}
+
 func TestConvertAST_MetricQuery_Success(t *testing.T) {
 	q := &query{
 		statement: `sum by (level) (count_over_time({cluster="prod", namespace=~"loki-.*"} |= "metric.go"[5m]))`,
@@ -137,9 +248,9 @@ func TestConvertAST_MetricQuery_Success(t *testing.T) {
 %2 = MATCH_RE label.namespace "loki-.*"
 %3 = AND %1 %2
 %4 = MAKETABLE [selector=%3, predicates=[%9], shard=0_of_1]
-%5 = GTE builtin.timestamp 1970-01-01T00:55:00Z
+%5 = GT builtin.timestamp 1970-01-01T00:55:00Z
 %6 = SELECT %4 [predicate=%5]
-%7 = LT builtin.timestamp 1970-01-01T02:00:00Z
+%7 = LTE builtin.timestamp 1970-01-01T02:00:00Z
 %8 = SELECT %6 [predicate=%7]
 %9 = MATCH_STR builtin.message "metric.go"
 %10 = SELECT %8 [predicate=%9]
@@ -156,9 +267,120 @@ RETURN %12
 	t.Logf("\n%s\n", sb.String())
 }
 
+func TestConvertAST_Unwrap_Success(t *testing.T) {
+	q := &query{
+		statement: `sum(max_over_time({cluster="prod"} | logfmt | unwrap bytes(size) | __error__="" [5m]))`,
+		start:     3600,
+		end:       7200,
+		interval:  5 * time.Minute,
+	}
+
+	logicalPlan, err := BuildPlan(q)
+	require.NoError(t, err)
+	t.Logf("\n%s\n", logicalPlan.String())
+
+	expected := `%1 = EQ label.cluster "prod"
+%2 = MAKETABLE [selector=%1, predicates=[], shard=0_of_1]
+%3 = GT builtin.timestamp 1970-01-01T00:55:00Z
+%4 = SELECT %2 [predicate=%3]
+%5 = LTE builtin.timestamp 1970-01-01T02:00:00Z
+%6 = SELECT %4 [predicate=%5]
+%7 = PARSE %6 [kind=logfmt]
+%8 = CAST_BYTES ambiguous.size
+%9 = UNWRAP %7 [value=%8]
+%10 = EQ ambiguous.__error__ ""
+%11 = SELECT %9 [predicate=%10]
+%12 = DROP %11 [labels=(size)]
+%13 = RANGE_AGGREGATION %12 [operation=max, value=generated.__unwrapped__, start_ts=1970-01-01T01:00:00Z, end_ts=1970-01-01T02:00:00Z, step=0s, range=5m0s]
+%14 = VECTOR_AGGREGATION %13 [operation=sum]
+RETURN %14
+`
+
+	require.Equal(t, expected, logicalPlan.String())
+}
+
+func TestConvertAST_BinOp_Success(t *testing.T) {
+	q := &query{
+		statement: `sum by (level) (count_over_time({app="foo"}[5m])) / ignoring (level) group_left sum(count_over_time({app="bar"}[5m])) > bool 0.5`,
+		start:     3600,
+		end:       7200,
+		interval:  5 * time.Minute,
+	}
+
+	logicalPlan, err := BuildPlan(q)
+	require.NoError(t, err)
+	t.Logf("\n%s\n", logicalPlan.String())
+
+	expected := `%1 = EQ label.app "foo"
+%2 = MAKETABLE [selector=%1, predicates=[], shard=0_of_1]
+%3 = GT builtin.timestamp 1970-01-01T00:55:00Z
+%4 = SELECT %2 [predicate=%3]
+%5 = LTE builtin.timestamp 1970-01-01T02:00:00Z
+%6 = SELECT %4 [predicate=%5]
+%7 = RANGE_AGGREGATION %6 [operation=count, start_ts=1970-01-01T01:00:00Z, end_ts=1970-01-01T02:00:00Z, step=0s, range=5m0s]
+%8 = VECTOR_AGGREGATION %7 [operation=sum, group_by=(ambiguous.level)]
+%9 = EQ label.app "bar"
+%10 = MAKETABLE [selector=%9, predicates=[], shard=0_of_1]
+%11 = GT builtin.timestamp 1970-01-01T00:55:00Z
+%12 = SELECT %10 [predicate=%11]
+%13 = LTE builtin.timestamp 1970-01-01T02:00:00Z
+%14 = SELECT %12 [predicate=%13]
+%15 = RANGE_AGGREGATION %14 [operation=count, start_ts=1970-01-01T01:00:00Z, end_ts=1970-01-01T02:00:00Z, step=0s, range=5m0s]
+%16 = VECTOR_AGGREGATION %15 [operation=sum]
+%17 = DIV %8 %16 [ignoring=(level), group_left=()]
+%18 = GT %17 0.5 [bool=true]
+RETURN %18
+`
+
+	require.Equal(t, expected, logicalPlan.String())
+
+	var sb strings.Builder
+	PrintTree(&sb, logicalPlan.Value())
+
+	t.Logf("\n%s\n", sb.String())
+}
+
+func TestConvertAST_Absent_Success(t *testing.T) {
+	q := &query{
+		statement: `absent_over_time({app="foo", env="prod", env!="dev", cluster=~"eu-.*"}[5m] offset 1h)`,
+		start:     7200,
+		end:       7200,
+		interval:  5 * time.Minute,
+	}
+
+	logicalPlan, err := BuildPlan(q)
+	require.NoError(t, err)
+	t.Logf("\n%s\n", logicalPlan.String())
+
+	expected := `%1 = EQ label.app "foo"
+%2 = EQ label.env "prod"
+%3 = AND %1 %2
+%4 = NEQ label.env "dev"
+%5 = AND %3 %4
+%6 = MATCH_RE label.cluster "eu-.*"
+%7 = AND %5 %6
+%8 = MAKETABLE [selector=%7, predicates=[], shard=0_of_1]
+%9 = GT builtin.timestamp 1970-01-01T00:55:00Z
+%10 = SELECT %8 [predicate=%9]
+%11 = LTE builtin.timestamp 1970-01-01T01:00:00Z
+%12 = SELECT %10 [predicate=%11]
+%13 = RANGE_AGGREGATION %12 [partition_by=(ambiguous.app), operation=count, start_ts=1970-01-01T02:00:00Z, end_ts=1970-01-01T02:00:00Z, step=0s, range=5m0s, offset=1h0m0s]
+%14 = ABSENT %13 [labels={app="foo"}, start_ts=1970-01-01T02:00:00Z, end_ts=1970-01-01T02:00:00Z, step=0s]
+RETURN %14
+`
+
+	require.Equal(t, expected, logicalPlan.String())
+
+	var sb strings.Builder
+	PrintTree(&sb, logicalPlan.Value())
+
+	t.Logf("\n%s\n", sb.String())
+}
+
 func TestCanExecuteQuery(t *testing.T) {
 	for _, tt := range []struct {
 		statement string
+		step      time.Duration
 		expected  bool
 	}{
 		{
@@ -183,33 +405,59 @@ func TestCanExecuteQuery(t *testing.T) {
 		},
 		{
 			statement: `{env="prod"} | json`,
+			expected:  true,
 		},
 		{
 			statement: `{env="prod"} | json foo="bar"`,
+			expected:  true,
 		},
 		{
 			statement: `{env="prod"} | logfmt`,
+			expected:  true,
 		},
 		{
 			statement: `{env="prod"} | logfmt foo="bar"`,
+			expected:  true,
 		},
 		{
 			statement: `{env="prod"} | pattern "<_> foo=<foo> <_>"`,
+			expected:  true,
 		},
 		{
 			statement: `{env="prod"} | regexp ".* foo=(?P<foo>.+) .*"`,
+			expected:  true,
 		},
 		{
 			statement: `{env="prod"} | unpack`,
+			expected:  true,
 		},
 		{
 			statement: `{env="prod"} |= "metrics.go" | logfmt`,
+			expected:  true,
 		},
 		{
 			statement: `{env="prod"} | line_format "{.cluster}"`,
+			expected:  true,
 		},
 		{
 			statement: `{env="prod"} | label_format cluster="us"`,
+			expected:  true,
+		},
+		{
+			statement: `{env="prod"} | logfmt | label_format dst=src, msg="{{.level}}: {{__line__}}" | line_format "{{.msg}}"`,
+			expected:  true,
+		},
+		{
+			statement: `{env="prod"} | logfmt | keep level, status="500"`,
+			expected:  true,
+		},
+		{
+			statement: `{env="prod"} | logfmt | drop __error__, __error_details__, level="debug"`,
+			expected:  true,
+		},
+		{
+			statement: `sum by (dst) (count_over_time({env="prod"} | label_format dst=src [1m]))`,
+			expected:  true,
 		},
 		{
 			statement: `{env="prod"} |= "metric.go" | retry > 2`,
@@ -218,24 +466,128 @@ func TestCanExecuteQuery(t *testing.T) {
 			statement: `sum by (level) (count_over_time({env="prod"}[1m]))`,
 			expected:  true,
 		},
+		{
+			// range query
+			statement: `sum by (level) (count_over_time({env="prod"}[1m]))`,
+			step:      30 * time.Second,
+			expected:  true,
+		},
 		{
 			// both vector and range aggregation are required
 			statement: `count_over_time({env="prod"}[1m])`,
 		},
 		{
-			// group by labels are required
 			statement: `sum(count_over_time({env="prod"}[1m]))`,
+			expected:  true,
+		},
+		{
+			statement: `sum without (level) (count_over_time({env="prod"}[1m]))`,
+			expected:  true,
+		},
+		{
+			statement: `topk(10, count_over_time({env="prod"}[1m]))`,
+			expected:  true,
+		},
+		{
+			statement: `sort_desc(count_over_time({env="prod"}[1m]))`,
+			expected:  true,
+		},
+		{
+			// nested vector aggregations are not supported
+			statement: `sum(max by (level) (count_over_time({env="prod"}[1m])))`,
 		},
 		{
-			// rate is not supported
 			statement: `sum by (level) (rate({env="prod"}[1m]))`,
+			expected:  true,
+		},
+		{
+			statement: `sum by (level) (bytes_rate({env="prod"}[1m]))`,
+			expected:  true,
+		},
+		{
+			statement: `sum by (level) (avg_over_time({env="prod"} | unwrap latency [1m]))`,
+			expected:  true,
+		},
+		{
+			statement: `sum by (level) (quantile_over_time(0.99, {env="prod"} | unwrap duration(latency) [1m]))`,
+			expected:  true,
+		},
+		{
+			statement: `sum by (level) (max_over_time({env="prod"} | unwrap bytes(size) | __error__="" [1m]))`,
+			expected:  true,
 		},
 		{
-			// max is not supported
 			statement: `max by (level) (count_over_time({env="prod"}[1m]))`,
+			expected:  true,
 		},
 		{
 			statement: `sum by (level) (count_over_time({env="prod"}[1m] offset 5m))`,
+			expected:  true,
+		},
+		{
+			statement: `absent_over_time({env="prod", app="api"}[5m])`,
+			expected:  true,
+		},
+		{
+			statement: `absent_over_time({env="prod"} |= "error" [5m] offset 1h)`,
+			expected:  true,
+		},
+		{
+			statement: `sum by (level) (count_over_time({env="prod"}[1m] @ 1609746000))`,
+		},
+		{
+			statement: `absent_over_time({env="prod"}[5m] @ end())`,
+		},
+		{
+			statement: `count_over_time({env="prod"}[1m])`,
+		},
+		{
+			statement: `{env="prod"} | logfmt | level="error" |= "timeout"`,
+			expected:  true,
+		},
+		{
+			statement: `sum by (level) (count_over_time({env="prod"} | json | level="error" [1m]))`,
+			expected:  true,
+		},
+		{
+			statement: `sum by (level) (avg_over_time({env="prod"} | logfmt | unwrap latency [1m]))`,
+			expected:  true,
+		},
+		{
+			statement: `sum(rate({env="prod", level="error"}[1m])) / sum(rate({env="prod"}[1m]))`,
+			expected:  true,
+		},
+		{
+			statement: `sum by (cluster) (rate({env="prod"}[1m])) > bool on (cluster) group_left () sum(rate({env="dev"}[1m]))`,
+			expected:  true,
+		},
+		{
+			statement: `sum by (level) (count_over_time({env="prod"}[1m])) unless sum by (level) (count_over_time({env="dev"}[1m]))`,
+			expected:  true,
+		},
+		{
+			statement: `100 * sum(count_over_time({env="prod"}[1m])) > 10`,
+			expected:  true,
+		},
+		{
+			statement: `sum(count_over_time({env="prod"}[1m])) or vector(0)`,
+			expected:  true,
+		},
+		{
+			statement: `label_replace(sum by (pod) (count_over_time({env="prod"}[1m])), "app", "$1", "pod", "(.*)-.*")`,
+			expected:  true,
+		},
+		{
+			statement: `sum by (app) (label_replace(count_over_time({env="prod"}[1m]), "app", "$1", "pod", "(.*)-.*"))`,
+			expected:  true,
+		},
+		{
+			// scalar results are not supported
+			statement: `1 + 1`,
+		},
+		{
+			// binary operations within vector aggregations are not supported
+			statement: `sum(count_over_time({env="prod"}[1m]) * 2)`,
 		},
 	} {
 		t.Run(tt.statement, func(t *testing.T) {
@@ -243,6 +595,7 @@ func TestCanExecuteQuery(t *testing.T) {
 				statement: tt.statement,
 				start:     1000,
 				end:       2000,
+				step:      tt.step,
 				direction: logproto.BACKWARD,
 				limit:     1000,
 			}
@@ -0,0 +1,41 @@
+package physical
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// Absent represents a node in the physical plan that produces a single series
+// with the value 1 at each step of the query for which its input has no
+// samples. The series is labelled with Labels.
+type Absent struct {
+	id string
+
+	Labels labels.Labels
+	Start  time.Time
+	End    time.Time
+	Step   time.Duration // optional for instant queries
+}
+
+// ID implements the [Node] interface.
+// Returns a string that uniquely identifies the node in the plan.
+func (a *Absent) ID() string {
+	if a.id == "" {
+		return fmt.Sprintf("%p", a)
+	}
+	return a.id
+}
+
+// Type implements the [Node] interface.
+// Returns the type of the node.
+func (*Absent) Type() NodeType {
+	return NodeTypeAbsent
+}
+
+// Accept implements the [Node] interface.
+// Dispatches itself to the provided [Visitor] v
+func (a *Absent) Accept(visitor Visitor) error {
+	return visitor.VisitAbsent(a)
+}
@@ -0,0 +1,56 @@
+package physical
+
+import (
+	"fmt"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// BinOpNode represents a binary operation between the samples of two vectors,
+// or between the samples of a vector and a scalar, in the physical plan.
+//
+// The vector operands are children of the node. Because the children of a
+// node in the [Plan] are not ordered, Left and Right reference the child that
+// is the left and right operand. If one of the operands is a scalar, the
+// respective field is nil and Scalar holds its value.
+type BinOpNode struct {
+	id string
+
+	Op types.BinaryOp
+
+	// Left and Right are the children of the node that produce the vector
+	// operands. One of them is nil if the operation has a scalar operand.
+	Left, Right Node
+	// Scalar is the value of the scalar operand. It is nil if both operands
+	// are vectors.
+	Scalar *LiteralExpr
+
+	// ReturnBool is set for comparison operations that return 0 or 1 instead
+	// of filtering samples.
+	ReturnBool bool
+	// VectorMatching describes how samples of the vector operands are
+	// matched. It is nil if the operation has a scalar operand.
+	VectorMatching *syntax.VectorMatching
+}
+
+// ID implements the [Node] interface.
+// Returns a string that uniquely identifies the node in the plan.
+func (b *BinOpNode) ID() string {
+	if b.id == "" {
+		return fmt.Sprintf("%p", b)
+	}
+	return b.id
+}
+
+// Type implements the [Node] interface.
+// Returns the type of the node.
+func (*BinOpNode) Type() NodeType {
+	return NodeTypeBinOp
+}
+
+// Accept implements the [Node] interface.
+// Dispatches itself to the provided [Visitor] v
+func (b *BinOpNode) Accept(v Visitor) error {
+	return v.VisitBinOp(b)
+}
@@ -144,7 +144,10 @@ func (c *MetastoreCatalog) resolveDataObjWithIndex(selector Expression, predicat
 	}
 
 	predicateMatchers := make([]*labels.Matcher, 0, len(predicates))
+	var lineFilters []string
 	for _, predicate := range predicates {
+		lineFilters = append(lineFilters, expressionToLineFilters(predicate)...)
+
 		matchers, err := expressionToMatchers(predicate, true)
 		if err != nil {
 			// Not all predicates are supported by the metastore, so some will be skipped
@@ -153,7 +156,7 @@ func (c *MetastoreCatalog) resolveDataObjWithIndex(selector Expression, predicat
 		predicateMatchers = append(predicateMatchers, matchers...)
 	}
 
-	sectionDescriptors, err := c.metastore.Sections(c.ctx, from, through, matchers, predicateMatchers)
+	sectionDescriptors, err := c.metastore.Sections(c.ctx, from, through, matchers, predicateMatchers, lineFilters)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to resolve data object sections: %w", err)
 	}
@@ -246,6 +249,33 @@ func expressionToMatchers(selector Expression, allowAmbiguousColumnRefs bool) ([
 	}
 }
 
+// expressionToLineFilters returns the strings which log lines must contain for
+// the predicate to match, which are the values of |= line filters. Line
+// filters which are combined with other expressions using anything but AND
+// are ignored.
+func expressionToLineFilters(predicate Expression) []string {
+	expr, ok := predicate.(*BinaryExpr)
+	if !ok {
+		return nil
+	}
+
+	switch expr.Op {
+	case types.BinaryOpAnd:
+		return append(expressionToLineFilters(expr.Left), expressionToLineFilters(expr.Right)...)
+	case types.BinaryOpMatchSubstr:
+		column, ok := expr.Left.(*ColumnExpr)
+		if !ok || column.Ref.Column != types.ColumnNameBuiltinMessage || column.Ref.Type != types.ColumnTypeBuiltin {
+			return nil
+		}
+		value, err := convertLiteralToString(expr.Right)
+		if err != nil {
+			return nil
+		}
+		return []string{value}
+	}
+	return nil
+}
+
 func convertLiteralToString(expr Expression) (string, error) {
 	l, ok := expr.(*LiteralExpr)
 	if !ok {
@@ -166,3 +166,60 @@ func TestCatalog_ExpressionToMatchers(t *testing.T) {
 		})
 	}
 }
+
+func TestCatalog_ExpressionToLineFilters(t *testing.T) {
+	lineFilter := func(op types.BinaryOp, value string) *BinaryExpr {
+		return &BinaryExpr{
+			Left:  newColumnExpr(types.ColumnNameBuiltinMessage, types.ColumnTypeBuiltin),
+			Right: NewLiteral(value),
+			Op:    op,
+		}
+	}
+
+	tests := []struct {
+		expr Expression
+		want []string
+	}{
+		{
+			expr: lineFilter(types.BinaryOpMatchSubstr, "foo"),
+			want: []string{"foo"},
+		},
+		{
+			expr: lineFilter(types.BinaryOpNotMatchSubstr, "foo"),
+			want: nil,
+		},
+		{
+			expr: lineFilter(types.BinaryOpMatchRe, "foo.*"),
+			want: nil,
+		},
+		{
+			expr: &BinaryExpr{
+				Left:  newColumnExpr("foo", types.ColumnTypeLabel),
+				Right: NewLiteral("bar"),
+				Op:    types.BinaryOpMatchSubstr,
+			},
+			want: nil,
+		},
+		{
+			expr: &BinaryExpr{
+				Left:  lineFilter(types.BinaryOpMatchSubstr, "foo"),
+				Right: lineFilter(types.BinaryOpMatchSubstr, "bar"),
+				Op:    types.BinaryOpAnd,
+			},
+			want: []string{"foo", "bar"},
+		},
+		{
+			expr: &BinaryExpr{
+				Left:  lineFilter(types.BinaryOpMatchSubstr, "foo"),
+				Right: lineFilter(types.BinaryOpMatchSubstr, "bar"),
+				Op:    types.BinaryOpOr,
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr.String(), func(t *testing.T) {
+			require.Equal(t, tt.want, expressionToLineFilters(tt.expr))
+		})
+	}
+}
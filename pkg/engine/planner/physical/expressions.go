@@ -2,6 +2,7 @@ package physical
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
 	"github.com/grafana/loki/v3/pkg/engine/internal/types"
@@ -17,6 +18,10 @@ const (
 	ExprTypeBinary
 	ExprTypeLiteral
 	ExprTypeColumn
+	ExprTypeTemplate
+	ExprTypeAssign
+	ExprTypeConditionalColumn
+	ExprTypeRegexpReplace
 )
 
 // String returns the string representation of the [ExpressionType].
@@ -30,6 +35,14 @@ func (t ExpressionType) String() string {
 		return "LiteralExpression"
 	case ExprTypeColumn:
 		return "ColumnExpression"
+	case ExprTypeTemplate:
+		return "TemplateExpression"
+	case ExprTypeAssign:
+		return "AssignExpression"
+	case ExprTypeConditionalColumn:
+		return "ConditionalColumnExpression"
+	case ExprTypeRegexpReplace:
+		return "RegexpReplaceExpression"
 	default:
 		panic(fmt.Sprintf("unknown expression type %d", t))
 	}
@@ -166,3 +179,94 @@ func (e *ColumnExpr) String() string {
 func (e *ColumnExpr) Type() ExpressionType {
 	return ExprTypeColumn
 }
+
+// TemplateExpr is an expression that formats a string for each row by
+// executing a text template with the columns of the row, the same way as the
+// line_format and label_format stages of LogQL do.
+type TemplateExpr struct {
+	// Template is the text template to execute.
+	Template string
+	// Label is the name of the label that is formatted by the template. If
+	// empty, the template formats the log line.
+	Label string
+}
+
+func (*TemplateExpr) isExpr() {}
+
+// String returns the string representation of the template expression.
+func (e *TemplateExpr) String() string {
+	if e.Label == "" {
+		return fmt.Sprintf("TEMPLATE(%s)", strconv.Quote(e.Template))
+	}
+	return fmt.Sprintf("TEMPLATE(%s, %s)", e.Label, strconv.Quote(e.Template))
+}
+
+// Type returns the type of the [TemplateExpr].
+func (*TemplateExpr) Type() ExpressionType {
+	return ExprTypeTemplate
+}
+
+// AssignExpr is an expression that implements the [ColumnExpression]
+// interface. It projects the result of the expression Value into the column
+// Ref.
+type AssignExpr struct {
+	Ref   types.ColumnRef
+	Value Expression
+}
+
+func (*AssignExpr) isExpr()       {}
+func (*AssignExpr) isColumnExpr() {}
+
+// String returns the string representation of the assign expression.
+func (e *AssignExpr) String() string {
+	return fmt.Sprintf("%s=%s", e.Ref.String(), e.Value)
+}
+
+// Type returns the type of the [AssignExpr].
+func (*AssignExpr) Type() ExpressionType {
+	return ExprTypeAssign
+}
+
+// ConditionalColumnExpr is an expression that implements the
+// [ColumnExpression] interface. It refers to the column Ref only for rows for
+// which the expression Condition evaluates to true.
+type ConditionalColumnExpr struct {
+	Ref       types.ColumnRef
+	Condition Expression
+}
+
+func (*ConditionalColumnExpr) isExpr()       {}
+func (*ConditionalColumnExpr) isColumnExpr() {}
+
+// String returns the string representation of the conditional column
+// expression.
+func (e *ConditionalColumnExpr) String() string {
+	return fmt.Sprintf("%s IF %s", e.Ref.String(), e.Condition)
+}
+
+// Type returns the type of the [ConditionalColumnExpr].
+func (*ConditionalColumnExpr) Type() ExpressionType {
+	return ExprTypeConditionalColumn
+}
+
+// RegexpReplaceExpr is an expression that matches the value of the expression
+// Value against Regex for each row. If the value matches, the result is
+// Replacement with the capture groups of Regex expanded, otherwise the result
+// is null. Regex is anchored at both ends.
+type RegexpReplaceExpr struct {
+	Value       Expression
+	Regex       string
+	Replacement string
+}
+
+func (*RegexpReplaceExpr) isExpr() {}
+
+// String returns the string representation of the regexp replace expression.
+func (e *RegexpReplaceExpr) String() string {
+	return fmt.Sprintf("REGEXP_REPLACE(%s, %s, %s)", e.Value, strconv.Quote(e.Regex), strconv.Quote(e.Replacement))
+}
+
+// Type returns the type of the [RegexpReplaceExpr].
+func (*RegexpReplaceExpr) Type() ExpressionType {
+	return ExprTypeRegexpReplace
+}
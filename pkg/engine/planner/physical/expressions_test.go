@@ -42,6 +42,31 @@ func TestExpressionTypes(t *testing.T) {
 			expr:     &ColumnExpr{Ref: types.ColumnRef{Column: "col", Type: types.ColumnTypeBuiltin}},
 			expected: ExprTypeColumn,
 		},
+		{
+			name:     "TemplateExpression",
+			expr:     &TemplateExpr{Template: "{{.foo}}"},
+			expected: ExprTypeTemplate,
+		},
+		{
+			name: "AssignExpression",
+			expr: &AssignExpr{
+				Ref:   types.ColumnRef{Column: "col", Type: types.ColumnTypeParsed},
+				Value: &TemplateExpr{Template: "{{.foo}}", Label: "col"},
+			},
+			expected: ExprTypeAssign,
+		},
+		{
+			name: "ConditionalColumnExpression",
+			expr: &ConditionalColumnExpr{
+				Ref: types.ColumnRef{Column: "col", Type: types.ColumnTypeAmbiguous},
+				Condition: &BinaryExpr{
+					Op:    types.BinaryOpEq,
+					Left:  &ColumnExpr{Ref: types.ColumnRef{Column: "col", Type: types.ColumnTypeAmbiguous}},
+					Right: NewLiteral("foo"),
+				},
+			},
+			expected: ExprTypeConditionalColumn,
+		},
 	}
 
 	for _, tt := range tests {
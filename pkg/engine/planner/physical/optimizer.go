@@ -63,6 +63,18 @@ func (r *predicatePushdown) applyPredicatePushdown(node Node, predicate Expressi
 			return true
 		}
 		return false
+	case *ParseNode:
+		// Predicates on the log line must not be pushed down below parsers
+		// that replace the log line.
+		if node.Kind.ModifiesLine() && referencesColumn(predicate, types.ColumnNameBuiltinMessage, types.ColumnTypeBuiltin) {
+			return false
+		}
+	case *Projection:
+		// Predicates must not be pushed down below projections that modify
+		// the columns they reference.
+		if projectionModifiesColumns(node, predicate) {
+			return false
+		}
 	}
 	for _, child := range r.plan.Children(node) {
 		if ok := r.applyPredicatePushdown(child, predicate); !ok {
@@ -85,6 +97,62 @@ func canApplyPredicate(predicate Expression) bool {
 	}
 }
 
+// referencesColumn returns whether the expression references the column with
+// the given name and type.
+func referencesColumn(expr Expression, name string, ty types.ColumnType) bool {
+	var columns []ColumnExpression
+	extractColumnsFromExpression(expr, &columns)
+	for _, col := range columns {
+		if col, ok := col.(*ColumnExpr); ok && col.Ref.Column == name && col.Ref.Type == ty {
+			return true
+		}
+	}
+	return false
+}
+
+// projectionModifiesColumns returns whether the projection modifies any of
+// the columns referenced by expr.
+func projectionModifiesColumns(node *Projection, expr Expression) bool {
+	var columns []ColumnExpression
+	extractColumnsFromExpression(expr, &columns)
+
+	for _, col := range columns {
+		col, ok := col.(*ColumnExpr)
+		if !ok {
+			continue
+		}
+
+		switch node.Mode {
+		case ProjectionModeKeep:
+			if col.Ref.Type != types.ColumnTypeBuiltin {
+				return true
+			}
+		case ProjectionModeExpand, ProjectionModeDrop:
+			for _, projected := range node.Columns {
+				var ref types.ColumnRef
+				switch projected := projected.(type) {
+				case *ColumnExpr:
+					ref = projected.Ref
+				case *AssignExpr:
+					ref = projected.Ref
+				case *ConditionalColumnExpr:
+					ref = projected.Ref
+				}
+				if ref.Column != col.Ref.Column {
+					continue
+				}
+				// Builtin columns are only modified by assigning to them,
+				// all other column types are modified regardless of the type
+				// of the projected column.
+				if (ref.Type == types.ColumnTypeBuiltin) == (col.Ref.Type == types.ColumnTypeBuiltin) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 var _ rule = (*predicatePushdown)(nil)
 
 // limitPushdown is a rule that moves down the limit to the scan nodes.
@@ -107,6 +175,10 @@ func (r *limitPushdown) applyLimitPushdown(node Node, limit uint32) bool {
 		// In case the scan node is reachable from multiple different limit nodes, we need to take the largest limit.
 		node.Limit = max(node.Limit, limit)
 		return true
+	case *Filter:
+		// The limit cannot be pushed down below a filter, because the filter
+		// may remove rows after the limit has been applied.
+		return false
 	}
 	for _, child := range r.plan.Children(node) {
 		if ok := r.applyLimitPushdown(child, limit); !ok {
@@ -127,11 +199,19 @@ type groupByPushdown struct {
 func (r *groupByPushdown) apply(node Node) bool {
 	switch node := node.(type) {
 	case *VectorAggregation:
-		if node.Operation != types.VectorAggregationTypeSum {
+		// Grouping keys can only be pushed down for sum aggregations with
+		// explicit grouping keys.
+		if node.Operation != types.VectorAggregationTypeSum || node.Without || len(node.GroupBy) == 0 {
 			return false
 		}
 
-		return r.applyGroupByPushdown(node, node.GroupBy)
+		anyChanged := false
+		for _, child := range r.plan.Children(node) {
+			if changed := r.applyGroupByPushdown(child, node.GroupBy); changed {
+				anyChanged = true
+			}
+		}
+		return anyChanged
 	}
 
 	return false
@@ -140,7 +220,7 @@ func (r *groupByPushdown) apply(node Node) bool {
 func (r *groupByPushdown) applyGroupByPushdown(node Node, groupBy []ColumnExpression) bool {
 	switch node := node.(type) {
 	case *RangeAggregation:
-		if node.Operation != types.RangeAggregationTypeCount {
+		if !canPushDownGroupBy(node.Operation) {
 			return false
 		}
 
@@ -166,6 +246,11 @@ func (r *groupByPushdown) applyGroupByPushdown(node Node, groupBy []ColumnExpres
 			}
 		}
 		return changed
+	case *Projection, *BinOpNode, *VectorAggregation:
+		// Nodes between the vector aggregation and the range aggregation may
+		// depend on labels that are not grouping keys, such as the source
+		// label of label_replace.
+		return false
 	}
 
 	anyChanged := false
@@ -177,6 +262,23 @@ func (r *groupByPushdown) applyGroupByPushdown(node Node, groupBy []ColumnExpres
 	return anyChanged
 }
 
+// canPushDownGroupBy returns whether a range aggregation of type op can be
+// partitioned by the grouping keys of a parent sum aggregation instead of by
+// series. This is only true for operations where the sum of the per-series
+// results equals the result of the aggregation over all samples of the group.
+func canPushDownGroupBy(op types.RangeAggregationType) bool {
+	switch op {
+	case types.RangeAggregationTypeCount,
+		types.RangeAggregationTypeRate,
+		types.RangeAggregationTypeBytes,
+		types.RangeAggregationTypeBytesRate,
+		types.RangeAggregationTypeSum:
+		return true
+	default:
+		return false
+	}
+}
+
 var _ rule = (*groupByPushdown)(nil)
 
 // projectionPushdown is a rule that pushes down column projections.
@@ -189,7 +291,7 @@ type projectionPushdown struct {
 func (r *projectionPushdown) apply(node Node) bool {
 	switch node := node.(type) {
 	case *RangeAggregation:
-		if len(node.PartitionBy) == 0 || node.Operation != types.RangeAggregationTypeCount {
+		if len(node.PartitionBy) == 0 {
 			return false
 		}
 
@@ -197,6 +299,11 @@ func (r *projectionPushdown) apply(node Node) bool {
 		copy(projections, node.PartitionBy)
 		// Always project timestamp column
 		projections[len(node.PartitionBy)] = &ColumnExpr{Ref: types.ColumnRef{Column: types.ColumnNameBuiltinTimestamp, Type: types.ColumnTypeBuiltin}}
+		// Project the columns the aggregated value is computed from, such as
+		// the unwrapped column.
+		if node.Value != nil {
+			extractColumnsFromExpression(node.Value, &projections)
+		}
 
 		return r.applyProjectionPushdown(node, projections, false)
 	case *Filter:
@@ -248,6 +355,33 @@ func (r *projectionPushdown) applyProjectionPushdown(node Node, projections []Co
 			}
 		}
 		return changed
+	case *ParseNode:
+		// Parsers require the log line to extract the parsed columns.
+		projections = append(slices.Clone(projections), &ColumnExpr{Ref: types.ColumnRef{Column: types.ColumnNameBuiltinMessage, Type: types.ColumnTypeBuiltin}})
+	case *Projection:
+		if node.Mode == ProjectionModeExpand {
+			projections = slices.Clone(projections)
+			for _, col := range node.Columns {
+				assign, ok := col.(*AssignExpr)
+				if !ok {
+					continue
+				}
+				// Generated columns are created by the projection and
+				// cannot be read by the scan.
+				if assign.Ref.Type == types.ColumnTypeGenerated {
+					projections = slices.DeleteFunc(projections, func(c ColumnExpression) bool {
+						colExpr, ok := c.(*ColumnExpr)
+						return ok && colExpr.Ref == assign.Ref
+					})
+				}
+				// Templates can reference any column of a row, therefore the
+				// columns read by the scan cannot be limited.
+				if _, ok := assign.Value.(*TemplateExpr); ok {
+					return false
+				}
+				extractColumnsFromExpression(assign.Value, &projections)
+			}
+		}
 	}
 
 	anyChanged := false
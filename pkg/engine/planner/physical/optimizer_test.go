@@ -261,6 +261,71 @@ func TestOptimizer(t *testing.T) {
 		require.Equal(t, expected, actual)
 	})
 
+	t.Run("groupby pushdown is not applied to aggregations without grouping labels", func(t *testing.T) {
+		for _, vectorAgg := range []*VectorAggregation{
+			{id: "sum", Operation: types.VectorAggregationTypeSum},
+			{id: "sum_without", Operation: types.VectorAggregationTypeSum, Without: true, GroupBy: []ColumnExpression{
+				&ColumnExpr{Ref: types.ColumnRef{Column: "level", Type: types.ColumnTypeAmbiguous}},
+			}},
+			{id: "max_by", Operation: types.VectorAggregationTypeMax, GroupBy: []ColumnExpression{
+				&ColumnExpr{Ref: types.ColumnRef{Column: "level", Type: types.ColumnTypeAmbiguous}},
+			}},
+		} {
+			plan := &Plan{}
+			scan1 := plan.addNode(&DataObjScan{id: "scan1"})
+			rangeAgg := plan.addNode(&RangeAggregation{
+				id:        "count_over_time",
+				Operation: types.RangeAggregationTypeCount,
+			})
+			plan.addNode(vectorAgg)
+
+			_ = plan.addEdge(Edge{Parent: vectorAgg, Child: rangeAgg})
+			_ = plan.addEdge(Edge{Parent: rangeAgg, Child: scan1})
+
+			rule := &groupByPushdown{plan: plan}
+			require.False(t, rule.apply(vectorAgg), vectorAgg.id)
+			require.Empty(t, rangeAgg.(*RangeAggregation).PartitionBy, vectorAgg.id)
+		}
+	})
+
+	t.Run("groupby pushdown is not applied through label_replace", func(t *testing.T) {
+		plan := &Plan{}
+		scan1 := plan.addNode(&DataObjScan{id: "scan1"})
+		rangeAgg := plan.addNode(&RangeAggregation{
+			id:        "count_over_time",
+			Operation: types.RangeAggregationTypeCount,
+		})
+		labelReplace := plan.addNode(&Projection{
+			id:   "label_replace",
+			Mode: ProjectionModeExpand,
+			Columns: []ColumnExpression{
+				&AssignExpr{
+					Ref: types.ColumnRef{Column: "app", Type: types.ColumnTypeParsed},
+					Value: &RegexpReplaceExpr{
+						Value:       &ColumnExpr{Ref: types.ColumnRef{Column: "pod", Type: types.ColumnTypeAmbiguous}},
+						Regex:       "(.*)-.*",
+						Replacement: "$1",
+					},
+				},
+			},
+		})
+		vectorAgg := plan.addNode(&VectorAggregation{
+			id:        "sum_by_app",
+			Operation: types.VectorAggregationTypeSum,
+			GroupBy: []ColumnExpression{
+				&ColumnExpr{Ref: types.ColumnRef{Column: "app", Type: types.ColumnTypeAmbiguous}},
+			},
+		})
+
+		_ = plan.addEdge(Edge{Parent: vectorAgg, Child: labelReplace})
+		_ = plan.addEdge(Edge{Parent: labelReplace, Child: rangeAgg})
+		_ = plan.addEdge(Edge{Parent: rangeAgg, Child: scan1})
+
+		rule := &groupByPushdown{plan: plan}
+		require.False(t, rule.apply(vectorAgg))
+		require.Empty(t, rangeAgg.(*RangeAggregation).PartitionBy)
+	})
+
 	t.Run("projection pushdown", func(t *testing.T) {
 		partitionBy := []ColumnExpression{
 			&ColumnExpr{Ref: types.ColumnRef{Column: "service", Type: types.ColumnTypeLabel}},
@@ -463,4 +528,308 @@ func TestOptimizer(t *testing.T) {
 		expected := PrintAsTree(expectedPlan)
 		require.Equal(t, expected, actual)
 	})
+
+	t.Run("predicate pushdown through parse node", func(t *testing.T) {
+		linePredicate := &BinaryExpr{
+			Left:  newColumnExpr(types.ColumnNameBuiltinMessage, types.ColumnTypeBuiltin),
+			Right: NewLiteral("error"),
+			Op:    types.BinaryOpMatchSubstr,
+		}
+
+		for _, tt := range []struct {
+			kind       types.ParserKind
+			pushedDown bool
+		}{
+			{kind: types.ParserKindLogfmt, pushedDown: true},
+			{kind: types.ParserKindUnpack, pushedDown: false},
+		} {
+			t.Run(tt.kind.String(), func(t *testing.T) {
+				plan := &Plan{}
+				{
+					scan1 := plan.addNode(&DataObjScan{id: "scan1"})
+					parse := plan.addNode(&ParseNode{id: "parse1", Kind: tt.kind})
+					filter := plan.addNode(&Filter{id: "filter1", Predicates: []Expression{linePredicate}})
+
+					_ = plan.addEdge(Edge{Parent: filter, Child: parse})
+					_ = plan.addEdge(Edge{Parent: parse, Child: scan1})
+				}
+
+				optimizations := []*optimization{
+					newOptimization("predicate pushdown", plan).withRules(
+						&predicatePushdown{plan: plan},
+					),
+				}
+				o := newOptimizer(plan, optimizations)
+				o.optimize(plan.Roots()[0])
+
+				expectedPlan := &Plan{}
+				{
+					scan1 := &DataObjScan{id: "scan1"}
+					filter := &Filter{id: "filter1", Predicates: []Expression{}}
+					if tt.pushedDown {
+						scan1.Predicates = []Expression{linePredicate}
+					} else {
+						filter.Predicates = []Expression{linePredicate}
+					}
+					expectedPlan.addNode(scan1)
+					parse := expectedPlan.addNode(&ParseNode{id: "parse1", Kind: tt.kind})
+					expectedPlan.addNode(filter)
+
+					_ = expectedPlan.addEdge(Edge{Parent: filter, Child: parse})
+					_ = expectedPlan.addEdge(Edge{Parent: parse, Child: scan1})
+				}
+
+				actual := PrintAsTree(plan)
+				expected := PrintAsTree(expectedPlan)
+				require.Equal(t, expected, actual)
+			})
+		}
+	})
+
+	t.Run("limit is not pushed down below filters", func(t *testing.T) {
+		plan := &Plan{}
+		{
+			scan1 := plan.addNode(&DataObjScan{id: "scan1"})
+			parse := plan.addNode(&ParseNode{id: "parse1", Kind: types.ParserKindJSON})
+			filter := plan.addNode(&Filter{id: "filter1", Predicates: []Expression{
+				&BinaryExpr{
+					Left:  newColumnExpr("level", types.ColumnTypeAmbiguous),
+					Right: NewLiteral("error"),
+					Op:    types.BinaryOpEq,
+				},
+			}})
+			limit := plan.addNode(&Limit{id: "limit1", Fetch: 100})
+
+			_ = plan.addEdge(Edge{Parent: limit, Child: filter})
+			_ = plan.addEdge(Edge{Parent: filter, Child: parse})
+			_ = plan.addEdge(Edge{Parent: parse, Child: scan1})
+		}
+
+		expected := PrintAsTree(plan)
+
+		optimizations := []*optimization{
+			newOptimization("limit pushdown", plan).withRules(
+				&limitPushdown{plan: plan},
+			),
+		}
+		o := newOptimizer(plan, optimizations)
+		o.optimize(plan.Roots()[0])
+
+		actual := PrintAsTree(plan)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("projection pushdown through parse node", func(t *testing.T) {
+		partitionBy := []ColumnExpression{
+			&ColumnExpr{Ref: types.ColumnRef{Column: "level", Type: types.ColumnTypeAmbiguous}},
+		}
+
+		plan := &Plan{}
+		{
+			scan1 := plan.addNode(&DataObjScan{id: "scan1"})
+			parse := plan.addNode(&ParseNode{id: "parse1", Kind: types.ParserKindLogfmt})
+			rangeAgg := plan.addNode(&RangeAggregation{
+				id:          "count_over_time",
+				Operation:   types.RangeAggregationTypeCount,
+				PartitionBy: partitionBy,
+			})
+
+			_ = plan.addEdge(Edge{Parent: rangeAgg, Child: parse})
+			_ = plan.addEdge(Edge{Parent: parse, Child: scan1})
+		}
+
+		optimizations := []*optimization{
+			newOptimization("projection pushdown", plan).withRules(
+				&projectionPushdown{plan: plan},
+			),
+		}
+		o := newOptimizer(plan, optimizations)
+		o.optimize(plan.Roots()[0])
+
+		expectedPlan := &Plan{}
+		{
+			scan1 := expectedPlan.addNode(&DataObjScan{id: "scan1", Projections: []ColumnExpression{
+				&ColumnExpr{Ref: types.ColumnRef{Column: "level", Type: types.ColumnTypeAmbiguous}},
+				&ColumnExpr{Ref: types.ColumnRef{Column: types.ColumnNameBuiltinTimestamp, Type: types.ColumnTypeBuiltin}},
+				&ColumnExpr{Ref: types.ColumnRef{Column: types.ColumnNameBuiltinMessage, Type: types.ColumnTypeBuiltin}},
+			}})
+			parse := expectedPlan.addNode(&ParseNode{id: "parse1", Kind: types.ParserKindLogfmt})
+			rangeAgg := expectedPlan.addNode(&RangeAggregation{
+				id:          "count_over_time",
+				Operation:   types.RangeAggregationTypeCount,
+				PartitionBy: partitionBy,
+			})
+
+			_ = expectedPlan.addEdge(Edge{Parent: rangeAgg, Child: parse})
+			_ = expectedPlan.addEdge(Edge{Parent: parse, Child: scan1})
+		}
+
+		actual := PrintAsTree(plan)
+		expected := PrintAsTree(expectedPlan)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("predicate pushdown through projection", func(t *testing.T) {
+		linePredicate := &BinaryExpr{
+			Left:  newColumnExpr(types.ColumnNameBuiltinMessage, types.ColumnTypeBuiltin),
+			Right: NewLiteral("error"),
+			Op:    types.BinaryOpMatchSubstr,
+		}
+
+		for _, tt := range []struct {
+			name       string
+			projection func() *Projection
+			pushedDown bool
+		}{
+			{
+				name: "line_format",
+				projection: func() *Projection {
+					return &Projection{id: "projection1", Mode: ProjectionModeExpand, Columns: []ColumnExpression{
+						&AssignExpr{
+							Ref:   types.ColumnRef{Column: types.ColumnNameBuiltinMessage, Type: types.ColumnTypeBuiltin},
+							Value: &TemplateExpr{Template: "{{.msg}}"},
+						},
+					}}
+				},
+				pushedDown: false,
+			},
+			{
+				name: "label_format",
+				projection: func() *Projection {
+					return &Projection{id: "projection1", Mode: ProjectionModeExpand, Columns: []ColumnExpression{
+						&AssignExpr{
+							Ref:   types.ColumnRef{Column: "msg", Type: types.ColumnTypeParsed},
+							Value: &TemplateExpr{Template: "{{.level}}", Label: "msg"},
+						},
+					}}
+				},
+				pushedDown: true,
+			},
+			{
+				name: "drop",
+				projection: func() *Projection {
+					return &Projection{id: "projection1", Mode: ProjectionModeDrop, Columns: []ColumnExpression{
+						newColumnExpr("level", types.ColumnTypeAmbiguous),
+					}}
+				},
+				pushedDown: true,
+			},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				plan := &Plan{}
+				{
+					scan1 := plan.addNode(&DataObjScan{id: "scan1"})
+					projection := plan.addNode(tt.projection())
+					filter := plan.addNode(&Filter{id: "filter1", Predicates: []Expression{linePredicate}})
+
+					_ = plan.addEdge(Edge{Parent: filter, Child: projection})
+					_ = plan.addEdge(Edge{Parent: projection, Child: scan1})
+				}
+
+				optimizations := []*optimization{
+					newOptimization("predicate pushdown", plan).withRules(
+						&predicatePushdown{plan: plan},
+					),
+				}
+				o := newOptimizer(plan, optimizations)
+				o.optimize(plan.Roots()[0])
+
+				expectedPlan := &Plan{}
+				{
+					scan1 := &DataObjScan{id: "scan1"}
+					filter := &Filter{id: "filter1", Predicates: []Expression{}}
+					if tt.pushedDown {
+						scan1.Predicates = []Expression{linePredicate}
+					} else {
+						filter.Predicates = []Expression{linePredicate}
+					}
+					expectedPlan.addNode(scan1)
+					projection := expectedPlan.addNode(tt.projection())
+					expectedPlan.addNode(filter)
+
+					_ = expectedPlan.addEdge(Edge{Parent: filter, Child: projection})
+					_ = expectedPlan.addEdge(Edge{Parent: projection, Child: scan1})
+				}
+
+				actual := PrintAsTree(plan)
+				expected := PrintAsTree(expectedPlan)
+				require.Equal(t, expected, actual)
+			})
+		}
+	})
+
+	t.Run("projection pushdown through projection", func(t *testing.T) {
+		partitionBy := []ColumnExpression{
+			&ColumnExpr{Ref: types.ColumnRef{Column: "dst", Type: types.ColumnTypeAmbiguous}},
+		}
+
+		for _, tt := range []struct {
+			name        string
+			value       Expression
+			projections []ColumnExpression
+		}{
+			{
+				name:  "rename",
+				value: newColumnExpr("src", types.ColumnTypeAmbiguous),
+				projections: []ColumnExpression{
+					&ColumnExpr{Ref: types.ColumnRef{Column: "dst", Type: types.ColumnTypeAmbiguous}},
+					&ColumnExpr{Ref: types.ColumnRef{Column: types.ColumnNameBuiltinTimestamp, Type: types.ColumnTypeBuiltin}},
+					&ColumnExpr{Ref: types.ColumnRef{Column: "src", Type: types.ColumnTypeAmbiguous}},
+				},
+			},
+			{
+				// Templates can reference any column, so all columns are read.
+				name:        "template",
+				value:       &TemplateExpr{Template: "{{.src}}", Label: "dst"},
+				projections: nil,
+			},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				newProjection := func() *Projection {
+					return &Projection{id: "projection1", Mode: ProjectionModeExpand, Columns: []ColumnExpression{
+						&AssignExpr{Ref: types.ColumnRef{Column: "dst", Type: types.ColumnTypeParsed}, Value: tt.value},
+					}}
+				}
+				newRangeAggregation := func() *RangeAggregation {
+					return &RangeAggregation{
+						id:          "count_over_time",
+						Operation:   types.RangeAggregationTypeCount,
+						PartitionBy: partitionBy,
+					}
+				}
+
+				plan := &Plan{}
+				{
+					scan1 := plan.addNode(&DataObjScan{id: "scan1"})
+					projection := plan.addNode(newProjection())
+					rangeAgg := plan.addNode(newRangeAggregation())
+
+					_ = plan.addEdge(Edge{Parent: rangeAgg, Child: projection})
+					_ = plan.addEdge(Edge{Parent: projection, Child: scan1})
+				}
+
+				optimizations := []*optimization{
+					newOptimization("projection pushdown", plan).withRules(
+						&projectionPushdown{plan: plan},
+					),
+				}
+				o := newOptimizer(plan, optimizations)
+				o.optimize(plan.Roots()[0])
+
+				expectedPlan := &Plan{}
+				{
+					scan1 := expectedPlan.addNode(&DataObjScan{id: "scan1", Projections: tt.projections})
+					projection := expectedPlan.addNode(newProjection())
+					rangeAgg := expectedPlan.addNode(newRangeAggregation())
+
+					_ = expectedPlan.addEdge(Edge{Parent: rangeAgg, Child: projection})
+					_ = expectedPlan.addEdge(Edge{Parent: projection, Child: scan1})
+				}
+
+				actual := PrintAsTree(plan)
+				expected := PrintAsTree(expectedPlan)
+				require.Equal(t, expected, actual)
+			})
+		}
+	})
 }
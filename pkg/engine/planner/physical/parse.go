@@ -0,0 +1,50 @@
+package physical
+
+import (
+	"fmt"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+)
+
+// ParseNode represents a parsing operation in the physical plan.
+// It parses the log line of each input row and adds the extracted values as
+// new columns of type [types.ColumnTypeParsed] to the result.
+type ParseNode struct {
+	id string
+
+	// Kind is the kind of parser to apply to the log line.
+	Kind types.ParserKind
+
+	// Pattern is the expression of the regexp and pattern parsers.
+	Pattern string
+
+	// Expressions are the extraction expressions of the json and logfmt
+	// parsers. If empty, all values are extracted.
+	Expressions []log.LabelExtractionExpr
+
+	// Strict and KeepEmpty are the flags of the logfmt parser.
+	Strict    bool
+	KeepEmpty bool
+}
+
+// ID implements the [Node] interface.
+// Returns a string that uniquely identifies the node in the plan.
+func (p *ParseNode) ID() string {
+	if p.id == "" {
+		return fmt.Sprintf("%p", p)
+	}
+	return p.id
+}
+
+// Type implements the [Node] interface.
+// Returns the type of the node.
+func (*ParseNode) Type() NodeType {
+	return NodeTypeParse
+}
+
+// Accept implements the [Node] interface.
+// Dispatches itself to the provided [Visitor] v
+func (p *ParseNode) Accept(v Visitor) error {
+	return v.VisitParse(p)
+}
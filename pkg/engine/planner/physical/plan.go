@@ -16,6 +16,10 @@ const (
 	NodeTypeLimit
 	NodeTypeRangeAggreation
 	NodeTypeVectorAggregation
+	NodeTypeParse
+	NodeTypeBinOp
+	NodeTypeVectorLiteral
+	NodeTypeAbsent
 )
 
 func (t NodeType) String() string {
@@ -34,6 +38,14 @@ func (t NodeType) String() string {
 		return "RangeAggregation"
 	case NodeTypeVectorAggregation:
 		return "VectorAggregation"
+	case NodeTypeParse:
+		return "Parse"
+	case NodeTypeBinOp:
+		return "BinOp"
+	case NodeTypeVectorLiteral:
+		return "VectorLiteral"
+	case NodeTypeAbsent:
+		return "Absent"
 	default:
 		return "Undefined"
 	}
@@ -65,6 +77,10 @@ var _ Node = (*Projection)(nil)
 var _ Node = (*Limit)(nil)
 var _ Node = (*Filter)(nil)
 var _ Node = (*RangeAggregation)(nil)
+var _ Node = (*ParseNode)(nil)
+var _ Node = (*BinOpNode)(nil)
+var _ Node = (*VectorLiteral)(nil)
+var _ Node = (*Absent)(nil)
 
 func (*DataObjScan) isNode()       {}
 func (*SortMerge) isNode()         {}
@@ -73,6 +89,10 @@ func (*Limit) isNode()             {}
 func (*Filter) isNode()            {}
 func (*RangeAggregation) isNode()  {}
 func (*VectorAggregation) isNode() {}
+func (*ParseNode) isNode()         {}
+func (*BinOpNode) isNode()         {}
+func (*VectorLiteral) isNode()     {}
+func (*Absent) isNode()            {}
 
 // Edge is a directed connection (parent-child relation) between a two nodes.
 type Edge struct {
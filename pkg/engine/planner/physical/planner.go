@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/prometheus/prometheus/model/labels"
+
 	"github.com/grafana/loki/v3/pkg/engine/internal/types"
 	"github.com/grafana/loki/v3/pkg/engine/planner/logical"
+	"github.com/grafana/loki/v3/pkg/logql/log"
 )
 
 // Context carries planning state that needs to be propagated down the plan tree.
@@ -143,10 +146,30 @@ func (p *Planner) process(inst logical.Value, ctx *Context) ([]Node, error) {
 		return p.processSort(inst, ctx)
 	case *logical.Limit:
 		return p.processLimit(inst, ctx)
+	case *logical.Parse:
+		return p.processParse(inst, ctx)
+	case *logical.LineFormat:
+		return p.processLineFormat(inst, ctx)
+	case *logical.LabelFormat:
+		return p.processLabelFormat(inst, ctx)
+	case *logical.KeepLabels:
+		return p.processKeepLabels(inst, ctx)
+	case *logical.DropLabels:
+		return p.processDropLabels(inst, ctx)
+	case *logical.Unwrap:
+		return p.processUnwrap(inst, ctx)
 	case *logical.RangeAggregation:
 		return p.processRangeAggregation(inst, ctx)
 	case *logical.VectorAggregation:
 		return p.processVectorAggregation(inst, ctx)
+	case *logical.LabelReplace:
+		return p.processLabelReplace(inst, ctx)
+	case *logical.BinOp:
+		return p.processBinOp(inst, ctx)
+	case *logical.VectorLiteral:
+		return p.processVectorLiteral(inst)
+	case *logical.Absent:
+		return p.processAbsent(inst, ctx)
 	}
 	return nil, nil
 }
@@ -270,22 +293,195 @@ func (p *Planner) processLimit(lp *logical.Limit, ctx *Context) ([]Node, error)
 	return []Node{node}, nil
 }
 
+// Convert [logical.Parse] into one [ParseNode] node.
+func (p *Planner) processParse(lp *logical.Parse, ctx *Context) ([]Node, error) {
+	node := &ParseNode{
+		Kind:        lp.Kind,
+		Pattern:     lp.Pattern,
+		Expressions: lp.Expressions,
+		Strict:      lp.Strict,
+		KeepEmpty:   lp.KeepEmpty,
+	}
+	p.plan.addNode(node)
+	children, err := p.process(lp.Table, ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range children {
+		if err := p.plan.addEdge(Edge{Parent: node, Child: children[i]}); err != nil {
+			return nil, err
+		}
+	}
+	return []Node{node}, nil
+}
+
+// Convert [logical.LineFormat] into one [Projection] node that assigns the
+// formatted log line to the message column.
+func (p *Planner) processLineFormat(lp *logical.LineFormat, ctx *Context) ([]Node, error) {
+	node := &Projection{
+		Mode: ProjectionModeExpand,
+		Columns: []ColumnExpression{
+			&AssignExpr{
+				Ref:   types.ColumnRef{Column: types.ColumnNameBuiltinMessage, Type: types.ColumnTypeBuiltin},
+				Value: &TemplateExpr{Template: lp.Template},
+			},
+		},
+	}
+	return p.processProjection(node, lp.Table, ctx)
+}
+
+// Convert [logical.LabelFormat] into one [Projection] node that assigns the
+// formatted labels to parsed columns. If the stage renames labels, an
+// additional [Projection] node drops the renamed labels.
+func (p *Planner) processLabelFormat(lp *logical.LabelFormat, ctx *Context) ([]Node, error) {
+	var (
+		assignments = make([]ColumnExpression, 0, len(lp.Formats))
+		renamed     []ColumnExpression
+		formatted   = make(map[string]struct{}, len(lp.Formats))
+	)
+	for _, f := range lp.Formats {
+		formatted[f.Name] = struct{}{}
+	}
+
+	for _, f := range lp.Formats {
+		ref := types.ColumnRef{Column: f.Name, Type: types.ColumnTypeParsed}
+		if f.Rename {
+			assignments = append(assignments, &AssignExpr{Ref: ref, Value: newColumnExpr(f.Value, types.ColumnTypeAmbiguous)})
+			if _, ok := formatted[f.Value]; !ok {
+				renamed = append(renamed, newColumnExpr(f.Value, types.ColumnTypeAmbiguous))
+			}
+			continue
+		}
+		assignments = append(assignments, &AssignExpr{Ref: ref, Value: &TemplateExpr{Template: f.Value, Label: f.Name}})
+	}
+
+	node := &Projection{Mode: ProjectionModeExpand, Columns: assignments}
+	if len(renamed) == 0 {
+		return p.processProjection(node, lp.Table, ctx)
+	}
+
+	drop := &Projection{Mode: ProjectionModeDrop, Columns: renamed}
+	p.plan.addNode(drop)
+	children, err := p.processProjection(node, lp.Table, ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range children {
+		if err := p.plan.addEdge(Edge{Parent: drop, Child: children[i]}); err != nil {
+			return nil, err
+		}
+	}
+	return []Node{drop}, nil
+}
+
+// Convert [logical.Unwrap] into one [Projection] node that assigns the
+// converted values to the generated unwrapped column.
+func (p *Planner) processUnwrap(lp *logical.Unwrap, ctx *Context) ([]Node, error) {
+	node := &Projection{
+		Mode: ProjectionModeExpand,
+		Columns: []ColumnExpression{
+			&AssignExpr{
+				Ref:   types.ColumnRef{Column: types.ColumnNameGeneratedUnwrapped, Type: types.ColumnTypeGenerated},
+				Value: p.convertPredicate(lp.Value),
+			},
+		},
+	}
+	return p.processProjection(node, lp.Table, ctx)
+}
+
+// Convert [logical.KeepLabels] into one [Projection] node.
+func (p *Planner) processKeepLabels(lp *logical.KeepLabels, ctx *Context) ([]Node, error) {
+	node := &Projection{Mode: ProjectionModeKeep, Columns: convertNamedLabelMatchers(lp.Labels)}
+	return p.processProjection(node, lp.Table, ctx)
+}
+
+// Convert [logical.DropLabels] into one [Projection] node.
+func (p *Planner) processDropLabels(lp *logical.DropLabels, ctx *Context) ([]Node, error) {
+	node := &Projection{Mode: ProjectionModeDrop, Columns: convertNamedLabelMatchers(lp.Labels)}
+	return p.processProjection(node, lp.Table, ctx)
+}
+
+// processProjection adds the projection node to the plan with the nodes of
+// the table as children.
+func (p *Planner) processProjection(node *Projection, table logical.Value, ctx *Context) ([]Node, error) {
+	p.plan.addNode(node)
+	children, err := p.process(table, ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range children {
+		if err := p.plan.addEdge(Edge{Parent: node, Child: children[i]}); err != nil {
+			return nil, err
+		}
+	}
+	return []Node{node}, nil
+}
+
+// convertNamedLabelMatchers converts the labels of the keep and drop stages
+// into column expressions. Labels with a matcher are converted into
+// [ConditionalColumnExpr] expressions with the matcher as condition.
+func convertNamedLabelMatchers(lbls []log.NamedLabelMatcher) []ColumnExpression {
+	columns := make([]ColumnExpression, len(lbls))
+	for i, lbl := range lbls {
+		if lbl.Matcher == nil {
+			columns[i] = newColumnExpr(lbl.Name, types.ColumnTypeAmbiguous)
+			continue
+		}
+		columns[i] = &ConditionalColumnExpr{
+			Ref: types.ColumnRef{Column: lbl.Matcher.Name, Type: types.ColumnTypeAmbiguous},
+			Condition: &BinaryExpr{
+				Left:  newColumnExpr(lbl.Matcher.Name, types.ColumnTypeAmbiguous),
+				Right: NewLiteral(lbl.Matcher.Value),
+				Op:    convertMatchType(lbl.Matcher.Type),
+			},
+		}
+	}
+	return columns
+}
+
+// convertMatchType converts a label matcher type into a binary operator.
+func convertMatchType(t labels.MatchType) types.BinaryOp {
+	switch t {
+	case labels.MatchEqual:
+		return types.BinaryOpEq
+	case labels.MatchNotEqual:
+		return types.BinaryOpNeq
+	case labels.MatchRegexp:
+		return types.BinaryOpMatchRe
+	case labels.MatchNotRegexp:
+		return types.BinaryOpNotMatchRe
+	default:
+		return types.BinaryOpInvalid
+	}
+}
+
 func (p *Planner) processRangeAggregation(r *logical.RangeAggregation, ctx *Context) ([]Node, error) {
 	partitionBy := make([]ColumnExpression, len(r.PartitionBy))
 	for i, col := range r.PartitionBy {
 		partitionBy[i] = &ColumnExpr{Ref: col.Ref}
 	}
 
+	var value Expression
+	if r.Value != nil {
+		value = p.convertPredicate(r.Value)
+	}
+
 	node := &RangeAggregation{
 		PartitionBy: partitionBy,
 		Operation:   r.Operation,
+		Value:       value,
+		Parameter:   r.Parameter,
 		Start:       r.Start,
 		End:         r.End,
 		Range:       r.RangeInterval,
+		Offset:      r.Offset,
 		Step:        r.Step,
 	}
 	p.plan.addNode(node)
 
+	// The data objects are resolved for the time range shifted by the offset.
+	ctx = ctx.WithTimeRange(ctx.from.Add(-r.Offset), ctx.through.Add(-r.Offset))
+
 	children, err := p.process(r.Table, ctx.WithRangeInterval(r.RangeInterval))
 	if err != nil {
 		return nil, err
@@ -308,9 +504,109 @@ func (p *Planner) processVectorAggregation(lp *logical.VectorAggregation, ctx *C
 
 	node := &VectorAggregation{
 		GroupBy:   groupBy,
+		Without:   lp.Without,
 		Operation: lp.Operation,
+		Parameter: lp.Parameter,
+	}
+	p.plan.addNode(node)
+	children, err := p.process(lp.Table, ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range children {
+		if err := p.plan.addEdge(Edge{Parent: node, Child: children[i]}); err != nil {
+			return nil, err
+		}
+	}
+	return []Node{node}, nil
+}
+
+// Convert [logical.LabelReplace] into one [Projection] node that assigns the
+// replaced value to the destination label. Rows for which the regular
+// expression does not match keep the value of the destination label.
+func (p *Planner) processLabelReplace(lp *logical.LabelReplace, ctx *Context) ([]Node, error) {
+	node := &Projection{
+		Mode: ProjectionModeExpand,
+		Columns: []ColumnExpression{
+			&AssignExpr{
+				Ref: types.ColumnRef{Column: lp.Dst, Type: types.ColumnTypeParsed},
+				Value: &RegexpReplaceExpr{
+					Value:       newColumnExpr(lp.Src, types.ColumnTypeAmbiguous),
+					Regex:       lp.Regex,
+					Replacement: lp.Replacement,
+				},
+			},
+		},
+	}
+	return p.processProjection(node, lp.Table, ctx)
+}
+
+// Convert [logical.BinOp] into one [BinOpNode] node. Operands that are
+// literals are stored in the node, the other operands become its children.
+func (p *Planner) processBinOp(lp *logical.BinOp, ctx *Context) ([]Node, error) {
+	node := &BinOpNode{
+		Op:             lp.Op,
+		ReturnBool:     lp.ReturnBool,
+		VectorMatching: lp.VectorMatching,
 	}
 	p.plan.addNode(node)
+
+	var err error
+	if node.Left, err = p.processBinOpOperand(node, lp.Left, ctx); err != nil {
+		return nil, err
+	}
+	if node.Right, err = p.processBinOpOperand(node, lp.Right, ctx); err != nil {
+		return nil, err
+	}
+	return []Node{node}, nil
+}
+
+// processBinOpOperand converts an operand of a binary operation. It returns
+// the child node of the operand, or nil if the operand is a literal.
+func (p *Planner) processBinOpOperand(node *BinOpNode, operand logical.Value, ctx *Context) (Node, error) {
+	if lit, ok := operand.(*logical.Literal); ok {
+		if node.Scalar != nil {
+			return nil, errors.New("binary operation between two literals")
+		}
+		node.Scalar = NewLiteral(lit.Value())
+		return nil, nil
+	}
+
+	children, err := p.process(operand, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(children) != 1 {
+		return nil, fmt.Errorf("operand of binary operation must produce exactly one node, got %d", len(children))
+	}
+	if err := p.plan.addEdge(Edge{Parent: node, Child: children[0]}); err != nil {
+		return nil, err
+	}
+	return children[0], nil
+}
+
+// Convert [logical.VectorLiteral] into one [VectorLiteral] node.
+func (p *Planner) processVectorLiteral(lp *logical.VectorLiteral) ([]Node, error) {
+	node := &VectorLiteral{
+		Value: lp.Value,
+		Start: lp.Start,
+		End:   lp.End,
+		Step:  lp.Step,
+	}
+	p.plan.addNode(node)
+	return []Node{node}, nil
+}
+
+// Convert [logical.Absent] into one [Absent] node.
+func (p *Planner) processAbsent(lp *logical.Absent, ctx *Context) ([]Node, error) {
+	node := &Absent{
+		Labels: lp.Labels,
+		Start:  lp.Start,
+		End:    lp.End,
+		Step:   lp.Step,
+	}
+	p.plan.addNode(node)
+
 	children, err := p.process(lp.Table, ctx)
 	if err != nil {
 		return nil, err
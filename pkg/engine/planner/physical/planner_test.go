@@ -5,11 +5,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
 	"github.com/grafana/loki/v3/pkg/engine/internal/types"
 	"github.com/grafana/loki/v3/pkg/engine/planner/logical"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 )
 
 type objectMeta struct {
@@ -267,6 +270,65 @@ func TestPlanner_Convert(t *testing.T) {
 	t.Logf("Optimized plan\n%s\n", PrintAsTree(physicalPlan))
 }
 
+func TestPlanner_Convert_Forward(t *testing.T) {
+	// Build a forward query plan:
+	// { app="users" } with limit 100
+	b := logical.NewBuilder(
+		&logical.MakeTable{
+			Selector: &logical.BinOp{
+				Left:  logical.NewColumnRef("app", types.ColumnTypeLabel),
+				Right: logical.NewLiteral("users"),
+				Op:    types.BinaryOpEq,
+			},
+			Shard: logical.NewShard(0, 1), // no sharding
+		},
+	).Sort(
+		*logical.NewColumnRef(types.ColumnNameBuiltinTimestamp, types.ColumnTypeBuiltin),
+		true,
+		false,
+	).Limit(0, 100)
+
+	logicalPlan, err := b.ToPlan()
+	require.NoError(t, err)
+
+	catalog := &catalog{
+		streamsByObject: map[string]objectMeta{
+			"obj1": {streamIDs: []int64{1, 2}, sections: 2},
+			"obj2": {streamIDs: []int64{3, 4}, sections: 1},
+		},
+	}
+	planner := NewPlanner(NewContext(time.Now(), time.Now()), catalog)
+
+	physicalPlan, err := planner.Build(logicalPlan)
+	require.NoError(t, err)
+	physicalPlan, err = planner.Optimize(physicalPlan)
+	require.NoError(t, err)
+	t.Logf("Optimized plan\n%s\n", PrintAsTree(physicalPlan))
+
+	root, err := physicalPlan.Root()
+	require.NoError(t, err)
+
+	var merges, scans int
+	visitor := &nodeCollectVisitor{
+		onVisitSortMerge: func(n *SortMerge) error {
+			require.Equal(t, ASC, n.Order)
+			merges++
+			return nil
+		},
+		onVisitDataObjScan: func(n *DataObjScan) error {
+			// Each scan must return the oldest entries of its section, so
+			// that the merged result contains the oldest entries overall.
+			require.Equal(t, ASC, n.Direction)
+			require.Equal(t, uint32(100), n.Limit)
+			scans++
+			return nil
+		},
+	}
+	require.NoError(t, physicalPlan.DFSWalk(root, visitor, PreOrderWalk))
+	require.Positive(t, merges)
+	require.Equal(t, 3, scans)
+}
+
 func TestPlanner_Convert_RangeAggregations(t *testing.T) {
 	// logical plan for count_over_time({ app="users" } | age > 21[5m])
 	b := logical.NewBuilder(
@@ -293,10 +355,13 @@ func TestPlanner_Convert_RangeAggregations(t *testing.T) {
 	).RangeAggregation(
 		[]logical.ColumnRef{*logical.NewColumnRef("label1", types.ColumnTypeAmbiguous), *logical.NewColumnRef("label2", types.ColumnTypeMetadata)},
 		types.RangeAggregationTypeCount,
+		nil, // Value
+		0,   // Parameter
 		time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), // Start Time
 		time.Date(2023, 10, 1, 1, 0, 0, 0, time.UTC), // End Time
 		0,             // Step
 		time.Minute*5, // Range
+		0,             // Offset
 	)
 
 	logicalPlan, err := b.ToPlan()
@@ -318,3 +383,333 @@ func TestPlanner_Convert_RangeAggregations(t *testing.T) {
 	require.NoError(t, err)
 	t.Logf("Optimized plan\n%s\n", PrintAsTree(physicalPlan))
 }
+
+func TestPlanner_Convert_BinOp(t *testing.T) {
+	start := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 10, 1, 1, 0, 0, 0, time.UTC)
+
+	newMakeTable := func(app string) *logical.MakeTable {
+		return &logical.MakeTable{
+			Selector: &logical.BinOp{
+				Left:  logical.NewColumnRef("app", types.ColumnTypeLabel),
+				Right: logical.NewLiteral(app),
+				Op:    types.BinaryOpEq,
+			},
+			Shard: logical.NewShard(0, 1), // no sharding
+		}
+	}
+
+	// logical plan for
+	// sum(count_over_time({app="users"}[5m])) / sum by (dst) (label_replace(count_over_time({app="orders"}[5m]), "dst", "$1", "src", "(.*)")) > bool 0.5
+	left := logical.NewBuilder(newMakeTable("users")).
+		RangeAggregation(nil, types.RangeAggregationTypeCount, nil, 0, start, end, 0, 5*time.Minute, 0).
+		VectorAggregation(nil, false, types.VectorAggregationTypeSum, 0)
+	right := logical.NewBuilder(newMakeTable("orders")).
+		RangeAggregation(nil, types.RangeAggregationTypeCount, nil, 0, start, end, 0, 5*time.Minute, 0).
+		LabelReplace("dst", "$1", "src", "(.*)").
+		VectorAggregation([]logical.ColumnRef{*logical.NewColumnRef("dst", types.ColumnTypeAmbiguous)}, false, types.VectorAggregationTypeSum, 0)
+	b := left.
+		BinOp(types.BinaryOpDiv, right.Value(), false, &syntax.VectorMatching{Card: syntax.CardOneToOne}).
+		BinOp(types.BinaryOpGt, logical.NewLiteral(0.5), true, nil)
+
+	logicalPlan, err := b.ToPlan()
+	require.NoError(t, err)
+
+	catalog := &catalog{
+		streamsByObject: map[string]objectMeta{
+			"obj1": {streamIDs: []int64{1, 2}, sections: 1},
+		},
+	}
+	planner := NewPlanner(NewContext(start, end), catalog)
+
+	physicalPlan, err := planner.Build(logicalPlan)
+	require.NoError(t, err)
+	physicalPlan, err = planner.Optimize(physicalPlan)
+	require.NoError(t, err)
+	t.Logf("Optimized plan\n%s\n", PrintAsTree(physicalPlan))
+
+	roots := physicalPlan.Roots()
+	require.Len(t, roots, 1)
+
+	// The comparison with the literal has a single child, the division.
+	cmp, ok := roots[0].(*BinOpNode)
+	require.True(t, ok)
+	require.Equal(t, types.BinaryOpGt, cmp.Op)
+	require.True(t, cmp.ReturnBool)
+	require.Equal(t, NewLiteral(0.5), cmp.Scalar)
+	require.Nil(t, cmp.Right)
+	require.Equal(t, []Node{cmp.Left}, physicalPlan.Children(cmp))
+
+	// The division references both vector aggregations as its operands.
+	div, ok := cmp.Left.(*BinOpNode)
+	require.True(t, ok)
+	require.Equal(t, types.BinaryOpDiv, div.Op)
+	require.Nil(t, div.Scalar)
+	require.ElementsMatch(t, []Node{div.Left, div.Right}, physicalPlan.Children(div))
+
+	leftAgg, ok := div.Left.(*VectorAggregation)
+	require.True(t, ok)
+	require.Empty(t, leftAgg.GroupBy)
+
+	rightAgg, ok := div.Right.(*VectorAggregation)
+	require.True(t, ok)
+	require.Len(t, rightAgg.GroupBy, 1)
+
+	// label_replace is converted into a projection between the vector
+	// aggregation and the range aggregation.
+	children := physicalPlan.Children(rightAgg)
+	require.Len(t, children, 1)
+	projection, ok := children[0].(*Projection)
+	require.True(t, ok)
+	require.Equal(t, ProjectionModeExpand, projection.Mode)
+	require.Equal(t, `parsed.dst=REGEXP_REPLACE(ambiguous.src, "(.*)", "$1")`, projection.Columns[0].String())
+
+	// The grouping key of the vector aggregation is not pushed down through
+	// label_replace.
+	children = physicalPlan.Children(projection)
+	require.Len(t, children, 1)
+	rangeAgg, ok := children[0].(*RangeAggregation)
+	require.True(t, ok)
+	require.Empty(t, rangeAgg.PartitionBy)
+}
+
+func TestPlanner_Convert_LabelFormat(t *testing.T) {
+	// logical plan for { app="users" } | label_format dst=src, msg="{{.level}}" | drop level="debug"
+	b := logical.NewBuilder(
+		&logical.MakeTable{
+			Selector: &logical.BinOp{
+				Left:  logical.NewColumnRef("app", types.ColumnTypeLabel),
+				Right: logical.NewLiteral("users"),
+				Op:    types.BinaryOpEq,
+			},
+			Shard: logical.NewShard(0, 1), // no sharding
+		},
+	).LabelFormat(
+		[]log.LabelFmt{
+			log.NewRenameLabelFmt("dst", "src"),
+			log.NewTemplateLabelFmt("msg", "{{.level}}"),
+		},
+	).DropLabels(
+		[]log.NamedLabelMatcher{
+			log.NewNamedLabelMatcher(labels.MustNewMatcher(labels.MatchEqual, "level", "debug"), ""),
+		},
+	)
+
+	logicalPlan, err := b.ToPlan()
+	require.NoError(t, err)
+
+	catalog := &catalog{
+		streamsByObject: map[string]objectMeta{
+			"obj1": {streamIDs: []int64{1, 2}, sections: 1},
+		},
+	}
+	planner := NewPlanner(NewContext(time.Now(), time.Now()), catalog)
+
+	physicalPlan, err := planner.Build(logicalPlan)
+	require.NoError(t, err)
+	t.Logf("Physical plan\n%s\n", PrintAsTree(physicalPlan))
+
+	// The drop stage is the root, followed by the projection that drops the
+	// renamed label and the projection that assigns the formatted labels.
+	root, err := physicalPlan.Root()
+	require.NoError(t, err)
+
+	drop, ok := root.(*Projection)
+	require.True(t, ok)
+	require.Equal(t, ProjectionModeDrop, drop.Mode)
+	require.Equal(t, []ColumnExpression{
+		&ConditionalColumnExpr{
+			Ref: types.ColumnRef{Column: "level", Type: types.ColumnTypeAmbiguous},
+			Condition: &BinaryExpr{
+				Left:  newColumnExpr("level", types.ColumnTypeAmbiguous),
+				Right: NewLiteral("debug"),
+				Op:    types.BinaryOpEq,
+			},
+		},
+	}, drop.Columns)
+
+	children := physicalPlan.Children(drop)
+	require.Len(t, children, 1)
+	renamed, ok := children[0].(*Projection)
+	require.True(t, ok)
+	require.Equal(t, ProjectionModeDrop, renamed.Mode)
+	require.Equal(t, []ColumnExpression{newColumnExpr("src", types.ColumnTypeAmbiguous)}, renamed.Columns)
+
+	children = physicalPlan.Children(renamed)
+	require.Len(t, children, 1)
+	formatted, ok := children[0].(*Projection)
+	require.True(t, ok)
+	require.Equal(t, ProjectionModeExpand, formatted.Mode)
+	require.Equal(t, []ColumnExpression{
+		&AssignExpr{
+			Ref:   types.ColumnRef{Column: "dst", Type: types.ColumnTypeParsed},
+			Value: newColumnExpr("src", types.ColumnTypeAmbiguous),
+		},
+		&AssignExpr{
+			Ref:   types.ColumnRef{Column: "msg", Type: types.ColumnTypeParsed},
+			Value: &TemplateExpr{Template: "{{.level}}", Label: "msg"},
+		},
+	}, formatted.Columns)
+}
+
+func TestPlanner_Convert_Unwrap(t *testing.T) {
+	// logical plan for sum by (level) (max_over_time({ app="users" } | unwrap bytes(size) | __error__="" [5m]))
+	unwrapped := logical.NewColumnRef(types.ColumnNameGeneratedUnwrapped, types.ColumnTypeGenerated)
+	b := logical.NewBuilder(
+		&logical.MakeTable{
+			Selector: &logical.BinOp{
+				Left:  logical.NewColumnRef("app", types.ColumnTypeLabel),
+				Right: logical.NewLiteral("users"),
+				Op:    types.BinaryOpEq,
+			},
+			Shard: logical.NewShard(0, 1), // no sharding
+		},
+	).Unwrap(
+		&logical.UnaryOp{Op: types.UnaryOpCastBytes, Value: logical.NewColumnRef("size", types.ColumnTypeAmbiguous)},
+	).Select(
+		&logical.BinOp{
+			Left:  logical.NewColumnRef("__error__", types.ColumnTypeAmbiguous),
+			Right: logical.NewLiteral(""),
+			Op:    types.BinaryOpEq,
+		},
+	).RangeAggregation(
+		[]logical.ColumnRef{*logical.NewColumnRef("level", types.ColumnTypeAmbiguous)},
+		types.RangeAggregationTypeMax,
+		unwrapped,
+		0, // Parameter
+		time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), // Start Time
+		time.Date(2023, 10, 1, 1, 0, 0, 0, time.UTC), // End Time
+		0,             // Step
+		time.Minute*5, // Range
+		0,             // Offset
+	)
+
+	logicalPlan, err := b.ToPlan()
+	require.NoError(t, err)
+
+	catalog := &catalog{
+		streamsByObject: map[string]objectMeta{
+			"obj1": {streamIDs: []int64{1, 2}, sections: 1},
+		},
+	}
+	planner := NewPlanner(NewContext(time.Now(), time.Now()), catalog)
+
+	physicalPlan, err := planner.Build(logicalPlan)
+	require.NoError(t, err)
+	physicalPlan, err = planner.Optimize(physicalPlan)
+	require.NoError(t, err)
+	t.Logf("Optimized plan\n%s\n", PrintAsTree(physicalPlan))
+
+	root, err := physicalPlan.Root()
+	require.NoError(t, err)
+	rangeAgg, ok := root.(*RangeAggregation)
+	require.True(t, ok)
+	require.Equal(t, &ColumnExpr{Ref: unwrapped.Ref}, rangeAgg.Value)
+
+	// The unwrapped column is converted by a projection below the post filter.
+	children := physicalPlan.Children(rangeAgg)
+	require.Len(t, children, 1)
+	filter, ok := children[0].(*Filter)
+	require.True(t, ok)
+
+	children = physicalPlan.Children(filter)
+	require.Len(t, children, 1)
+	projection, ok := children[0].(*Projection)
+	require.True(t, ok)
+	require.Equal(t, ProjectionModeExpand, projection.Mode)
+	require.Equal(t, []ColumnExpression{
+		&AssignExpr{
+			Ref:   unwrapped.Ref,
+			Value: &UnaryExpr{Left: newColumnExpr("size", types.ColumnTypeAmbiguous), Op: types.UnaryOpCastBytes},
+		},
+	}, projection.Columns)
+
+	// The scan reads the column to unwrap, but not the generated column.
+	var projected []string
+	for _, node := range physicalPlan.Leaves() {
+		scan, ok := node.(*DataObjScan)
+		require.True(t, ok)
+		for _, col := range scan.Projections {
+			projected = append(projected, col.(*ColumnExpr).Ref.Column)
+		}
+	}
+	require.ElementsMatch(t, []string{"level", types.ColumnNameBuiltinTimestamp, "size", "__error__"}, projected)
+}
+
+// recordingCatalog is a [Catalog] that records the time range of the last
+// resolved data objects.
+type recordingCatalog struct {
+	*catalog
+	from, through time.Time
+}
+
+func (c *recordingCatalog) ResolveDataObjWithShard(e Expression, p []Expression, shard ShardInfo, from, through time.Time) ([]DataObjLocation, [][]int64, [][]int, error) {
+	c.from, c.through = from, through
+	return c.catalog.ResolveDataObjWithShard(e, p, shard, from, through)
+}
+
+func TestPlanner_Convert_Absent(t *testing.T) {
+	start := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 10, 1, 1, 0, 0, 0, time.UTC)
+	lbls := labels.FromStrings("app", "users")
+
+	// logical plan for
+	// absent_over_time({app="users"}[5m] offset 1h)
+	b := logical.NewBuilder(
+		&logical.MakeTable{
+			Selector: &logical.BinOp{
+				Left:  logical.NewColumnRef("app", types.ColumnTypeLabel),
+				Right: logical.NewLiteral("users"),
+				Op:    types.BinaryOpEq,
+			},
+			Shard: logical.NewShard(0, 1), // no sharding
+		},
+	).RangeAggregation(
+		[]logical.ColumnRef{*logical.NewColumnRef("app", types.ColumnTypeAmbiguous)},
+		types.RangeAggregationTypeCount,
+		nil, // Value
+		0,   // Parameter
+		start,
+		end,
+		time.Minute,   // Step
+		time.Minute*5, // Range
+		time.Hour,     // Offset
+	).Absent(lbls, start, end, time.Minute)
+
+	logicalPlan, err := b.ToPlan()
+	require.NoError(t, err)
+
+	catalog := &recordingCatalog{
+		catalog: &catalog{
+			streamsByObject: map[string]objectMeta{
+				"obj1": {streamIDs: []int64{1, 2}, sections: 1},
+			},
+		},
+	}
+	planner := NewPlanner(NewContext(start, end), catalog)
+
+	physicalPlan, err := planner.Build(logicalPlan)
+	require.NoError(t, err)
+	physicalPlan, err = planner.Optimize(physicalPlan)
+	require.NoError(t, err)
+	t.Logf("Optimized plan\n%s\n", PrintAsTree(physicalPlan))
+
+	// The data objects are resolved for the time range shifted by the offset.
+	require.Equal(t, start.Add(-time.Hour-5*time.Minute), catalog.from)
+	require.Equal(t, end.Add(-time.Hour), catalog.through)
+
+	roots := physicalPlan.Roots()
+	require.Len(t, roots, 1)
+
+	absent, ok := roots[0].(*Absent)
+	require.True(t, ok)
+	require.Equal(t, lbls, absent.Labels)
+	require.Equal(t, time.Minute, absent.Step)
+
+	children := physicalPlan.Children(absent)
+	require.Len(t, children, 1)
+	rangeAgg, ok := children[0].(*RangeAggregation)
+	require.True(t, ok)
+	require.Equal(t, time.Hour, rangeAgg.Offset)
+	require.Equal(t, 5*time.Minute, rangeAgg.Range)
+}
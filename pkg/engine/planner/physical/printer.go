@@ -3,10 +3,13 @@ package physical
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
 	"github.com/grafana/loki/v3/pkg/engine/planner/internal/tree"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 )
 
 // BuildTree converts a physical plan node and its children into a tree structure
@@ -49,6 +52,9 @@ func toTreeNode(n Node) *tree.Node {
 		treeNode.Properties = []tree.Property{
 			tree.NewProperty("columns", true, toAnySlice(node.Columns)...),
 		}
+		if node.Mode != ProjectionModeSelect {
+			treeNode.Properties = append(treeNode.Properties, tree.NewProperty("mode", false, node.Mode))
+		}
 	case *Filter:
 		for i := range node.Predicates {
 			treeNode.Properties = append(treeNode.Properties, tree.NewProperty(fmt.Sprintf("predicate[%d]", i), false, node.Predicates[i].String()))
@@ -61,10 +67,21 @@ func toTreeNode(n Node) *tree.Node {
 	case *RangeAggregation:
 		properties := []tree.Property{
 			tree.NewProperty("operation", false, node.Operation),
+		}
+		if node.Value != nil {
+			properties = append(properties, tree.NewProperty("value", false, node.Value.String()))
+		}
+		if node.Operation == types.RangeAggregationTypeQuantile {
+			properties = append(properties, tree.NewProperty("parameter", false, node.Parameter))
+		}
+		properties = append(properties,
 			tree.NewProperty("start", false, node.Start.Format(time.RFC3339Nano)),
 			tree.NewProperty("end", false, node.End.Format(time.RFC3339Nano)),
 			tree.NewProperty("step", false, node.Step),
 			tree.NewProperty("range", false, node.Range),
+		)
+		if node.Offset != 0 {
+			properties = append(properties, tree.NewProperty("offset", false, node.Offset))
 		}
 
 		if len(node.PartitionBy) > 0 {
@@ -72,6 +89,86 @@ func toTreeNode(n Node) *tree.Node {
 		}
 
 		treeNode.Properties = properties
+	case *VectorAggregation:
+		properties := []tree.Property{
+			tree.NewProperty("operation", false, node.Operation),
+		}
+		if node.Operation == types.VectorAggregationTypeTopK || node.Operation == types.VectorAggregationTypeBottomK {
+			properties = append(properties, tree.NewProperty("parameter", false, node.Parameter))
+		}
+
+		if node.Without {
+			properties = append(properties, tree.NewProperty("without", true, toAnySlice(node.GroupBy)...))
+		} else if len(node.GroupBy) > 0 {
+			properties = append(properties, tree.NewProperty("group_by", true, toAnySlice(node.GroupBy)...))
+		}
+
+		treeNode.Properties = properties
+	case *ParseNode:
+		properties := []tree.Property{
+			tree.NewProperty("kind", false, node.Kind),
+		}
+		if node.Pattern != "" {
+			properties = append(properties, tree.NewProperty("pattern", false, strconv.Quote(node.Pattern)))
+		}
+		if len(node.Expressions) > 0 {
+			expressions := make([]any, len(node.Expressions))
+			for i, expr := range node.Expressions {
+				expressions[i] = fmt.Sprintf("%s=%s", expr.Identifier, strconv.Quote(expr.Expression))
+			}
+			properties = append(properties, tree.NewProperty("expressions", true, expressions...))
+		}
+		if node.Strict {
+			properties = append(properties, tree.NewProperty("strict", false, node.Strict))
+		}
+		if node.KeepEmpty {
+			properties = append(properties, tree.NewProperty("keep_empty", false, node.KeepEmpty))
+		}
+
+		treeNode.Properties = properties
+	case *BinOpNode:
+		properties := []tree.Property{
+			tree.NewProperty("operation", false, node.Op),
+		}
+		if node.Scalar != nil {
+			side := "right"
+			if node.Left == nil {
+				side = "left"
+			}
+			properties = append(properties, tree.NewProperty(side, false, node.Scalar.String()))
+		}
+		if node.ReturnBool {
+			properties = append(properties, tree.NewProperty("bool", false, node.ReturnBool))
+		}
+		if m := node.VectorMatching; m != nil {
+			if m.On {
+				properties = append(properties, tree.NewProperty("on", true, toAnySlice(m.MatchingLabels)...))
+			} else if len(m.MatchingLabels) > 0 {
+				properties = append(properties, tree.NewProperty("ignoring", true, toAnySlice(m.MatchingLabels)...))
+			}
+			switch m.Card {
+			case syntax.CardManyToOne:
+				properties = append(properties, tree.NewProperty("group_left", true, toAnySlice(m.Include)...))
+			case syntax.CardOneToMany:
+				properties = append(properties, tree.NewProperty("group_right", true, toAnySlice(m.Include)...))
+			}
+		}
+
+		treeNode.Properties = properties
+	case *VectorLiteral:
+		treeNode.Properties = []tree.Property{
+			tree.NewProperty("value", false, node.Value),
+			tree.NewProperty("start", false, node.Start.Format(time.RFC3339Nano)),
+			tree.NewProperty("end", false, node.End.Format(time.RFC3339Nano)),
+			tree.NewProperty("step", false, node.Step),
+		}
+	case *Absent:
+		treeNode.Properties = []tree.Property{
+			tree.NewProperty("labels", false, node.Labels),
+			tree.NewProperty("start", false, node.Start.Format(time.RFC3339Nano)),
+			tree.NewProperty("end", false, node.End.Format(time.RFC3339Nano)),
+			tree.NewProperty("step", false, node.Step),
+		}
 	}
 	return treeNode
 }
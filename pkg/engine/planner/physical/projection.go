@@ -2,6 +2,47 @@ package physical
 
 import "fmt"
 
+// ProjectionMode defines how the columns of a [Projection] are applied to the
+// columns of its input.
+type ProjectionMode int
+
+const (
+	// ProjectionModeSelect keeps only the projected columns of the input.
+	ProjectionModeSelect ProjectionMode = iota
+
+	// ProjectionModeExpand adds the projected columns to the columns of the
+	// input. Input columns with the same name as a projected column are
+	// replaced. Columns are projected with [AssignExpr] expressions.
+	ProjectionModeExpand
+
+	// ProjectionModeKeep removes all label, metadata and parsed columns of the
+	// input, except the projected columns. Columns projected with a
+	// [ConditionalColumnExpr] are only kept for rows where the condition is
+	// true.
+	ProjectionModeKeep
+
+	// ProjectionModeDrop removes the projected columns from the input. Columns
+	// projected with a [ConditionalColumnExpr] are only removed for rows where
+	// the condition is true.
+	ProjectionModeDrop
+)
+
+// String returns the string representation of the [ProjectionMode].
+func (m ProjectionMode) String() string {
+	switch m {
+	case ProjectionModeSelect:
+		return "select"
+	case ProjectionModeExpand:
+		return "expand"
+	case ProjectionModeKeep:
+		return "keep"
+	case ProjectionModeDrop:
+		return "drop"
+	default:
+		return fmt.Sprintf("ProjectionMode(%d)", m)
+	}
+}
+
 // Projection represents a column selection operation in the physical plan.
 // It contains a list of columns (column expressions) that are later
 // evaluated against the input columns to remove unnecessary colums from the
@@ -12,6 +53,9 @@ type Projection struct {
 	// Columns is a set of column expressions that are used to drop not needed
 	// columns that do not match the expression evaluation.
 	Columns []ColumnExpression
+
+	// Mode defines how Columns are applied to the input columns.
+	Mode ProjectionMode
 }
 
 // ID implements the [Node] interface.
@@ -14,10 +14,13 @@ type RangeAggregation struct {
 	PartitionBy []ColumnExpression // Columns to partition the data by.
 
 	Operation types.RangeAggregationType
+	Value     Expression // Value to aggregate for each row. Nil if rows are counted.
+	Parameter float64    // Parameter of the operation, such as the φ-quantile of quantile_over_time.
 	Start     time.Time
 	End       time.Time
 	Step      time.Duration // optional for instant queries
 	Range     time.Duration
+	Offset    time.Duration // offset of the range, windows are evaluated at t-Offset
 }
 
 func (r *RangeAggregation) ID() string {
@@ -14,9 +14,13 @@ type VectorAggregation struct {
 
 	// GroupBy defines the columns to group by. If empty, all rows are aggregated into a single result.
 	GroupBy []ColumnExpression
+	// Without inverts the grouping: rows are grouped by all columns except the GroupBy columns.
+	Without bool
 
 	// Operation defines the type of aggregation operation to perform (e.g., sum, min, max)
 	Operation types.VectorAggregationType
+	// Parameter defines the parameter of the aggregation, such as k for topk and bottomk.
+	Parameter int
 }
 
 // ID implements the [Node] interface.
@@ -0,0 +1,39 @@
+package physical
+
+import (
+	"fmt"
+	"time"
+)
+
+// VectorLiteral represents a node in the physical plan that produces a single
+// series without labels, that has the same value at each step of the query.
+// It has no children.
+type VectorLiteral struct {
+	id string
+
+	Value float64
+	Start time.Time
+	End   time.Time
+	Step  time.Duration // optional for instant queries
+}
+
+// ID implements the [Node] interface.
+// Returns a string that uniquely identifies the node in the plan.
+func (v *VectorLiteral) ID() string {
+	if v.id == "" {
+		return fmt.Sprintf("%p", v)
+	}
+	return v.id
+}
+
+// Type implements the [Node] interface.
+// Returns the type of the node.
+func (*VectorLiteral) Type() NodeType {
+	return NodeTypeVectorLiteral
+}
+
+// Accept implements the [Node] interface.
+// Dispatches itself to the provided [Visitor] v
+func (v *VectorLiteral) Accept(visitor Visitor) error {
+	return visitor.VisitVectorLiteral(v)
+}
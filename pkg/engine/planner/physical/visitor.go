@@ -12,4 +12,8 @@ type Visitor interface {
 	VisitFilter(*Filter) error
 	VisitLimit(*Limit) error
 	VisitVectorAggregation(*VectorAggregation) error
+	VisitParse(*ParseNode) error
+	VisitBinOp(*BinOpNode) error
+	VisitVectorLiteral(*VectorLiteral) error
+	VisitAbsent(*Absent) error
 }
@@ -16,6 +16,10 @@ type nodeCollectVisitor struct {
 	onVisitProjection        func(*Projection) error
 	onVisitRangeAggregation  func(*RangeAggregation) error
 	onVisitVectorAggregation func(*VectorAggregation) error
+	onVisitParse             func(*ParseNode) error
+	onVisitBinOp             func(*BinOpNode) error
+	onVisitVectorLiteral     func(*VectorLiteral) error
+	onVisitAbsent            func(*Absent) error
 }
 
 func (v *nodeCollectVisitor) VisitDataObjScan(n *DataObjScan) error {
@@ -74,3 +78,35 @@ func (v *nodeCollectVisitor) VisitVectorAggregation(n *VectorAggregation) error
 	v.visited = append(v.visited, fmt.Sprintf("%s.%s", n.Type().String(), n.ID()))
 	return nil
 }
+
+func (v *nodeCollectVisitor) VisitParse(n *ParseNode) error {
+	if v.onVisitParse != nil {
+		return v.onVisitParse(n)
+	}
+	v.visited = append(v.visited, fmt.Sprintf("%s.%s", n.Type().String(), n.ID()))
+	return nil
+}
+
+func (v *nodeCollectVisitor) VisitBinOp(n *BinOpNode) error {
+	if v.onVisitBinOp != nil {
+		return v.onVisitBinOp(n)
+	}
+	v.visited = append(v.visited, fmt.Sprintf("%s.%s", n.Type().String(), n.ID()))
+	return nil
+}
+
+func (v *nodeCollectVisitor) VisitVectorLiteral(n *VectorLiteral) error {
+	if v.onVisitVectorLiteral != nil {
+		return v.onVisitVectorLiteral(n)
+	}
+	v.visited = append(v.visited, fmt.Sprintf("%s.%s", n.Type().String(), n.ID()))
+	return nil
+}
+
+func (v *nodeCollectVisitor) VisitAbsent(n *Absent) error {
+	if v.onVisitAbsent != nil {
+		return v.onVisitAbsent(n)
+	}
+	v.visited = append(v.visited, fmt.Sprintf("%s.%s", n.Type().String(), n.ID()))
+	return nil
+}
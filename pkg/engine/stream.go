@@ -0,0 +1,359 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/arrio"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/loki/v3/pkg/engine/executor"
+	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	utillog "github.com/grafana/loki/v3/pkg/util/log"
+	util_validation "github.com/grafana/loki/v3/pkg/util/validation"
+)
+
+// Names of the columns of streamed results.
+const (
+	ResultColumnTimestamp          = "timestamp"
+	ResultColumnLine               = "line"
+	ResultColumnValue              = "value"
+	ResultColumnLabels             = "labels"
+	ResultColumnStructuredMetadata = "structured_metadata"
+	ResultColumnParsed             = "parsed"
+)
+
+var (
+	labelsType = func() *arrow.MapType {
+		typ := arrow.MapOf(arrow.BinaryTypes.String, arrow.BinaryTypes.String)
+		typ.KeysSorted = true
+		return typ
+	}()
+
+	logsResultSchema = arrow.NewSchema([]arrow.Field{
+		{Name: ResultColumnTimestamp, Type: arrow.FixedWidthTypes.Timestamp_ns},
+		{Name: ResultColumnLine, Type: arrow.BinaryTypes.String},
+		{Name: ResultColumnLabels, Type: labelsType},
+		{Name: ResultColumnStructuredMetadata, Type: labelsType},
+		{Name: ResultColumnParsed, Type: labelsType},
+	}, nil)
+
+	samplesResultSchema = arrow.NewSchema([]arrow.Field{
+		{Name: ResultColumnTimestamp, Type: arrow.FixedWidthTypes.Timestamp_ns},
+		{Name: ResultColumnValue, Type: arrow.PrimitiveTypes.Float64},
+		{Name: ResultColumnLabels, Type: labelsType},
+	}, nil)
+)
+
+// ResultSchema returns the schema of the records that [QueryEngine.Stream]
+// writes for a query.
+//
+// The results of log queries have the columns timestamp, line, labels,
+// structured_metadata and parsed. The results of metric queries have the
+// columns timestamp, value and labels. Labels, structured metadata and parsed
+// labels are maps from names to values.
+//
+// Unlike the records produced by the executor, whose columns depend on the
+// data that was read, all records of a query share this schema.
+func ResultSchema(expr syntax.Expr) (*arrow.Schema, error) {
+	// Some sample expressions, such as vector(), also implement
+	// [syntax.LogSelectorExpr], so they are checked first.
+	switch expr.(type) {
+	case syntax.SampleExpr:
+		return samplesResultSchema, nil
+	case syntax.LogSelectorExpr:
+		return logsResultSchema, nil
+	default:
+		return nil, fmt.Errorf("unsupported expression type %T", expr)
+	}
+}
+
+// withQueryTimeout applies the smallest query timeout of the tenants of ctx to
+// ctx. Streamed queries can be served without the HTTP middleware that
+// applies the timeout to other queries, such as over Arrow Flight.
+func (e *QueryEngine) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	tenants, err := tenant.TenantIDs(ctx)
+	if e.limits == nil || err != nil {
+		return context.WithCancel(ctx)
+	}
+	timeoutCapture := func(id string) time.Duration { return e.limits.QueryTimeout(ctx, id) }
+	timeout := util_validation.SmallestPositiveNonZeroDurationPerTenant(tenants, timeoutCapture)
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, timeout, errors.New("query timeout reached"))
+}
+
+// Stream executes a LogQL query like [QueryEngine.Execute], but writes its
+// results to w as Arrow records of [ResultSchema] while they are produced,
+// instead of collecting them into a [logqlmodel.Result].
+//
+// Entries and samples are written in the order the executor produces them,
+// which is not necessarily sorted by stream or series. Streaming stops at the
+// first error returned by w.
+func (e *QueryEngine) Stream(ctx context.Context, params logql.Params, w arrio.Writer) error {
+	ctx, span := tracer.Start(ctx, "QueryEngine.Stream", trace.WithAttributes(
+		attribute.String("type", string(logql.GetRangeType(params))),
+		attribute.String("query", params.QueryString()),
+		attribute.Stringer("start", params.Start()),
+		attribute.Stringer("end", params.End()),
+		attribute.Stringer("step", params.Step()),
+		attribute.Stringer("length", params.End().Sub(params.Start())),
+		attribute.StringSlice("shards", params.Shards()),
+	))
+	defer span.End()
+
+	startTime := time.Now()
+
+	ctx, cancel := e.withQueryTimeout(ctx)
+	defer cancel()
+
+	logger := utillog.WithContext(ctx, e.logger)
+	logger = log.With(logger, "query", params.QueryString(), "shard", strings.Join(params.Shards(), ","), "engine", "v2")
+
+	schema, err := ResultSchema(params.GetExpression())
+	if err != nil {
+		return ErrNotSupported
+	}
+
+	logicalPlan, durLogicalPlanning, err := e.buildLogicalPlan(ctx, logger, params)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to create logical plan")
+		return err
+	}
+
+	physicalPlan, durPhysicalPlanning, err := e.buildPhysicalPlan(ctx, logger, params, logicalPlan)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to create physical plan")
+		return err
+	}
+
+	level.Info(logger).Log("msg", "start streaming query with new engine")
+	timer := prometheus.NewTimer(e.metrics.execution)
+
+	pipeline := executor.Run(ctx, e.executorConfig(), physicalPlan, logger)
+	defer pipeline.Close()
+
+	converter := newResultConverter(memory.DefaultAllocator, schema)
+	defer converter.Release()
+
+	if err := streamResult(ctx, pipeline, converter, w); err != nil {
+		e.metrics.subqueries.WithLabelValues(statusFailure).Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to stream results")
+		return err
+	}
+
+	durExecution := timer.ObserveDuration()
+	e.metrics.subqueries.WithLabelValues(statusSuccess).Inc()
+
+	level.Debug(logger).Log(
+		"msg", "finished streaming with new engine",
+		"duration_logical_planning", durLogicalPlanning,
+		"duration_physical_planning", durPhysicalPlanning,
+		"duration_execution", durExecution,
+		"duration_full", time.Since(startTime),
+	)
+
+	span.SetStatus(codes.Ok, "")
+	return nil
+}
+
+func streamResult(ctx context.Context, pipeline executor.Pipeline, converter *resultConverter, w arrio.Writer) error {
+	for {
+		if err := pipeline.Read(ctx); err != nil {
+			if errors.Is(err, executor.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		rec, err := pipeline.Value()
+		if err != nil {
+			return err
+		}
+
+		result := converter.Convert(rec)
+		rec.Release()
+
+		if result.NumRows() > 0 {
+			err = w.Write(result)
+		}
+		result.Release()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// resultConverter converts records produced by the executor into records of
+// a result schema. Rows are skipped by the same rules as the [ResultBuilder]
+// implementations: log lines need stream labels, a line, and a timestamp;
+// samples need a timestamp and a value.
+type resultConverter struct {
+	samples bool
+	builder *array.RecordBuilder
+}
+
+func newResultConverter(alloc memory.Allocator, schema *arrow.Schema) *resultConverter {
+	return &resultConverter{
+		samples: schema.Equal(samplesResultSchema),
+		builder: array.NewRecordBuilder(alloc, schema),
+	}
+}
+
+// resultColumns holds the indexes of the columns of a record that are
+// converted into result columns. Indexes of labels are sorted by column name.
+type resultColumns struct {
+	timestamp, line, value int
+
+	labels, metadata, parsed []int
+}
+
+func (c *resultConverter) Convert(rec arrow.Record) arrow.Record {
+	cols := c.resultColumns(rec)
+
+	if c.samples {
+		c.convertSamples(rec, cols)
+	} else {
+		c.convertLogs(rec, cols)
+	}
+	return c.builder.NewRecord()
+}
+
+// Release releases the memory of the converter.
+func (c *resultConverter) Release() {
+	c.builder.Release()
+}
+
+func (c *resultConverter) resultColumns(rec arrow.Record) resultColumns {
+	cols := resultColumns{timestamp: -1, line: -1, value: -1}
+	schema := rec.Schema()
+
+	for i, field := range schema.Fields() {
+		colType, ok := field.Metadata.GetValue(types.MetadataKeyColumnType)
+		if !ok {
+			continue
+		}
+		colDataType, _ := field.Metadata.GetValue(types.MetadataKeyColumnDataType)
+
+		switch {
+		case field.Name == types.ColumnNameBuiltinTimestamp && field.Type.ID() == arrow.TIMESTAMP:
+			cols.timestamp = i
+		case field.Name == types.ColumnNameBuiltinMessage && colType == types.ColumnTypeBuiltin.String():
+			cols.line = i
+		case field.Name == types.ColumnNameGeneratedValue && field.Type.ID() == arrow.FLOAT64:
+			cols.value = i
+		case field.Type.ID() != arrow.STRING:
+			// Only string columns can be labels.
+		case c.samples:
+			// Series labels are made of all string columns.
+			if colDataType == datatype.Loki.String.String() {
+				cols.labels = append(cols.labels, i)
+			}
+		case colType == types.ColumnTypeLabel.String():
+			cols.labels = append(cols.labels, i)
+		case colType == types.ColumnTypeMetadata.String():
+			cols.metadata = append(cols.metadata, i)
+		case colType == types.ColumnTypeParsed.String():
+			cols.parsed = append(cols.parsed, i)
+		}
+	}
+
+	for _, indexes := range [][]int{cols.labels, cols.metadata, cols.parsed} {
+		sort.Slice(indexes, func(i, j int) bool {
+			return schema.Field(indexes[i]).Name < schema.Field(indexes[j]).Name
+		})
+	}
+	return cols
+}
+
+func (c *resultConverter) convertLogs(rec arrow.Record, cols resultColumns) {
+	if cols.timestamp < 0 || cols.line < 0 {
+		return
+	}
+	timestamps := rec.Column(cols.timestamp).(*array.Timestamp)
+	lines := rec.Column(cols.line).(*array.String)
+
+	for row := range int(rec.NumRows()) {
+		if timestamps.IsNull(row) || lines.IsNull(row) || lines.Value(row) == "" {
+			continue
+		}
+		// Structured metadata and parsed labels are part of the stream labels
+		// of entries in a [logqlmodel.Streams] result.
+		if !hasLabels(rec, cols.labels, row) && !hasLabels(rec, cols.metadata, row) && !hasLabels(rec, cols.parsed, row) {
+			continue
+		}
+
+		c.builder.Field(0).(*array.TimestampBuilder).Append(timestamps.Value(row))
+		c.builder.Field(1).(*array.StringBuilder).Append(lines.Value(row))
+		appendLabels(c.builder.Field(2).(*array.MapBuilder), rec, cols.labels, row)
+		appendLabels(c.builder.Field(3).(*array.MapBuilder), rec, cols.metadata, row)
+		appendLabels(c.builder.Field(4).(*array.MapBuilder), rec, cols.parsed, row)
+	}
+}
+
+func (c *resultConverter) convertSamples(rec arrow.Record, cols resultColumns) {
+	if cols.timestamp < 0 || cols.value < 0 {
+		return
+	}
+	timestamps := rec.Column(cols.timestamp).(*array.Timestamp)
+	values := rec.Column(cols.value).(*array.Float64)
+
+	for row := range int(rec.NumRows()) {
+		if timestamps.IsNull(row) || values.IsNull(row) {
+			continue
+		}
+
+		c.builder.Field(0).(*array.TimestampBuilder).Append(timestamps.Value(row))
+		c.builder.Field(1).(*array.Float64Builder).Append(values.Value(row))
+		appendLabels(c.builder.Field(2).(*array.MapBuilder), rec, cols.labels, row)
+	}
+}
+
+// hasLabels returns true if any of the string columns has a non-empty value
+// in row.
+func hasLabels(rec arrow.Record, columns []int, row int) bool {
+	for _, i := range columns {
+		if col := rec.Column(i).(*array.String); col.IsValid(row) && col.Value(row) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// appendLabels appends a map of the values of the string columns in row to b.
+// The map is keyed by column name. Like in [labels.Labels], empty values are
+// treated as missing.
+func appendLabels(b *array.MapBuilder, rec arrow.Record, columns []int, row int) {
+	b.Append(true)
+	keys := b.KeyBuilder().(*array.StringBuilder)
+	items := b.ItemBuilder().(*array.StringBuilder)
+
+	for _, i := range columns {
+		col := rec.Column(i).(*array.String)
+		if col.IsNull(row) || col.Value(row) == "" {
+			continue
+		}
+		keys.Append(rec.ColumnName(i))
+		items.Append(col.Value(row))
+	}
+}
@@ -0,0 +1,241 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/httpgrpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/logql"
+	utillog "github.com/grafana/loki/v3/pkg/util/log"
+	serverutil "github.com/grafana/loki/v3/pkg/util/server"
+)
+
+const (
+	// QueryArrowPath is the HTTP path on which queriers stream the results of
+	// queries as Arrow IPC. The endpoint is experimental.
+	QueryArrowPath = "/loki/api/v1/query_arrow"
+
+	// ContentTypeArrowStream is the content type of responses that contain
+	// an Arrow IPC stream.
+	ContentTypeArrowStream = "application/vnd.apache.arrow.stream"
+
+	// ErrorTrailer is the HTTP trailer that holds the error of a query that
+	// failed after its results started streaming.
+	ErrorTrailer = "X-Loki-Error"
+)
+
+// ValidateFunc validates the parameters of a query before it is executed. It
+// returns the parameters to execute, which may be adjusted to the limits of
+// the tenant, such as a start time clamped to the max query lookback.
+//
+// Errors returned by a ValidateFunc are reported to clients as invalid
+// requests.
+type ValidateFunc func(ctx context.Context, params logql.Params) (logql.Params, error)
+
+// NewStreamHandler returns an HTTP handler that executes queries with e and
+// streams their results as a single Arrow IPC stream of [ResultSchema]. It
+// accepts the same parameters as the query_range endpoint.
+//
+// Errors that happen before the first record is written are reported with the
+// status code of the response. Later errors end the response early and are
+// reported in the [ErrorTrailer] trailer, so clients must check the trailer
+// to know whether they have received all results.
+//
+// The parameters of every query are checked with validate before the query is
+// executed.
+func NewStreamHandler(e *QueryEngine, validate ValidateFunc, logger log.Logger) http.Handler {
+	return &streamHandler{engine: e, validate: validate, logger: logger}
+}
+
+type streamHandler struct {
+	engine   *QueryEngine
+	validate ValidateFunc
+	logger   log.Logger
+}
+
+// ServeHTTP implements [http.Handler].
+func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := utillog.WithContext(ctx, h.logger)
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params, err := streamParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params, err = h.validate(ctx, params)
+	if err != nil {
+		serverutil.WriteError(invalidRequestError(err), w)
+		return
+	}
+	schema, err := ResultSchema(params.GetExpression())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := &streamResponseWriter{ResponseWriter: w}
+	writer := &flushingRecordWriter{
+		Writer:     ipc.NewWriter(resp, ipc.WithSchema(schema)),
+		controller: http.NewResponseController(w),
+	}
+
+	err = h.engine.Stream(ctx, params, writer)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err == nil {
+		return
+	}
+
+	level.Warn(logger).Log("msg", "failed to stream query results", "err", err)
+	if resp.started {
+		w.Header().Set(ErrorTrailer, err.Error())
+		return
+	}
+	if errors.Is(err, ErrNotSupported) {
+		err = httpgrpc.Errorf(http.StatusNotImplemented, "%s", err.Error())
+	}
+	serverutil.WriteError(err, w)
+}
+
+// streamParams returns the parameters of the range query in the form of r.
+func streamParams(r *http.Request) (logql.Params, error) {
+	req, err := loghttp.ParseRangeQuery(r)
+	if err != nil {
+		return nil, err
+	}
+	return logql.NewLiteralParams(
+		req.Query,
+		req.Start,
+		req.End,
+		req.Step,
+		req.Interval,
+		req.Direction,
+		req.Limit,
+		req.Shards,
+		nil,
+	)
+}
+
+// invalidRequestError returns err as an HTTP 400 error, unless it already
+// carries a status code.
+func invalidRequestError(err error) error {
+	if _, ok := httpgrpc.HTTPResponseFromError(err); ok {
+		return err
+	}
+	return httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
+}
+
+// streamResponseWriter commits the headers of a successful response once the
+// first bytes of the stream are written.
+type streamResponseWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (w *streamResponseWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.Header().Set("Content-Type", ContentTypeArrowStream)
+		w.Header().Set("Trailer", ErrorTrailer)
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// flushingRecordWriter flushes the response after every record, so that
+// clients can process records while the query is still running.
+type flushingRecordWriter struct {
+	*ipc.Writer
+	controller *http.ResponseController
+}
+
+func (w *flushingRecordWriter) Write(rec arrow.Record) error {
+	if err := w.Writer.Write(rec); err != nil {
+		return err
+	}
+	if err := w.controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// NewFlightServer returns an Arrow Flight service that executes queries with
+// e. Queries are executed with DoGet requests, whose ticket is the
+// URL-encoded form of the parameters of the query_range endpoint, such as
+// "query=%7Bapp%3D%22foo%22%7D&start=...&end=...&limit=...". The results are
+// returned as records of [ResultSchema].
+//
+// The parameters of every query are checked with validate before the query is
+// executed. Register the service with [flight.RegisterFlightServiceServer].
+func NewFlightServer(e *QueryEngine, validate ValidateFunc, logger log.Logger) flight.FlightServer {
+	return &flightServer{engine: e, validate: validate, logger: logger}
+}
+
+type flightServer struct {
+	flight.BaseFlightServer
+
+	engine   *QueryEngine
+	validate ValidateFunc
+	logger   log.Logger
+}
+
+// DoGet implements [flight.FlightServer].
+func (s *flightServer) DoGet(ticket *flight.Ticket, stream flight.FlightService_DoGetServer) error {
+	ctx := stream.Context()
+	logger := utillog.WithContext(ctx, s.logger)
+
+	params, err := ticketParams(ticket)
+	if err != nil {
+		return grpcstatus.Error(codes.InvalidArgument, err.Error())
+	}
+	params, err = s.validate(ctx, params)
+	if err != nil {
+		return grpcstatus.Error(codes.InvalidArgument, grpcstatus.Convert(err).Message())
+	}
+	schema, err := ResultSchema(params.GetExpression())
+	if err != nil {
+		return grpcstatus.Error(codes.InvalidArgument, err.Error())
+	}
+
+	writer := flight.NewRecordWriter(stream, ipc.WithSchema(schema))
+	if err := s.engine.Stream(ctx, params, writer); err != nil {
+		level.Warn(logger).Log("msg", "failed to stream query results", "err", err)
+		if errors.Is(err, ErrNotSupported) {
+			return grpcstatus.Error(codes.Unimplemented, err.Error())
+		}
+		return serverutil.ClientGrpcStatusAndError(err)
+	}
+	return writer.Close()
+}
+
+// ticketParams returns the parameters of the query in ticket.
+func ticketParams(ticket *flight.Ticket) (logql.Params, error) {
+	form, err := url.ParseQuery(string(ticket.GetTicket()))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ticket: %w", err)
+	}
+	r, err := http.NewRequestWithContext(context.Background(), http.MethodGet, QueryArrowPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.Form = form
+	return streamParams(r)
+}
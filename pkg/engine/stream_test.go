@@ -0,0 +1,417 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
+	"github.com/grafana/loki/v3/pkg/engine/internal/datatype"
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// resultRow is a row of a record of [ResultSchema].
+type resultRow struct {
+	Timestamp          time.Time
+	Line               string
+	Value              float64
+	Labels             map[string]string
+	StructuredMetadata map[string]string
+	Parsed             map[string]string
+}
+
+func resultRows(t *testing.T, rec arrow.Record) []resultRow {
+	t.Helper()
+
+	rows := make([]resultRow, rec.NumRows())
+	for i, field := range rec.Schema().Fields() {
+		for row := range rows {
+			switch col := rec.Column(i).(type) {
+			case *array.Timestamp:
+				rows[row].Timestamp = col.Value(row).ToTime(arrow.Nanosecond).UTC()
+			case *array.String:
+				rows[row].Line = col.Value(row)
+			case *array.Float64:
+				rows[row].Value = col.Value(row)
+			case *array.Map:
+				values := make(map[string]string)
+				keys := col.Keys().(*array.String)
+				items := col.Items().(*array.String)
+				start, end := col.ValueOffsets(row)
+				for j := start; j < end; j++ {
+					values[keys.Value(int(j))] = items.Value(int(j))
+				}
+
+				switch field.Name {
+				case ResultColumnLabels:
+					rows[row].Labels = values
+				case ResultColumnStructuredMetadata:
+					rows[row].StructuredMetadata = values
+				case ResultColumnParsed:
+					rows[row].Parsed = values
+				}
+			default:
+				t.Fatalf("unexpected column type %T", col)
+			}
+		}
+	}
+	return rows
+}
+
+func TestResultSchema(t *testing.T) {
+	for query, expected := range map[string]*arrow.Schema{
+		`{app="foo"}`:                          logsResultSchema,
+		`{app="foo"} | json`:                   logsResultSchema,
+		`count_over_time({app="foo"}[1m])`:     samplesResultSchema,
+		`sum(rate({app="foo"}[1m])) > 1`:       samplesResultSchema,
+		`vector(1)`:                            samplesResultSchema,
+		`sum by (app) (rate({app="foo"}[1m]))`: samplesResultSchema,
+	} {
+		expr, err := syntax.ParseExpr(query)
+		require.NoError(t, err)
+
+		schema, err := ResultSchema(expr)
+		require.NoError(t, err)
+		require.True(t, expected.Equal(schema), query)
+	}
+}
+
+func TestResultConverter(t *testing.T) {
+	alloc := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer alloc.AssertSize(t, 0)
+
+	mdTypeLabel := datatype.ColumnMetadata(types.ColumnTypeLabel, datatype.Loki.String)
+	mdTypeMetadata := datatype.ColumnMetadata(types.ColumnTypeMetadata, datatype.Loki.String)
+	mdTypeParsed := datatype.ColumnMetadata(types.ColumnTypeParsed, datatype.Loki.String)
+
+	t.Run("logs", func(t *testing.T) {
+		schema := arrow.NewSchema([]arrow.Field{
+			{Name: types.ColumnNameBuiltinTimestamp, Type: arrow.FixedWidthTypes.Timestamp_ns, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
+			{Name: types.ColumnNameBuiltinMessage, Type: arrow.BinaryTypes.String, Metadata: datatype.ColumnMetadataBuiltinMessage},
+			{Name: "env", Type: arrow.BinaryTypes.String, Metadata: mdTypeLabel},
+			{Name: "app", Type: arrow.BinaryTypes.String, Metadata: mdTypeLabel},
+			{Name: "trace_id", Type: arrow.BinaryTypes.String, Metadata: mdTypeMetadata},
+			{Name: "level", Type: arrow.BinaryTypes.String, Metadata: mdTypeParsed},
+		}, nil)
+		rec := createRecord(t, schema, [][]interface{}{
+			{arrow.Timestamp(1), "first", "prod", "foo", "abc", "info"},
+			{arrow.Timestamp(2), "second", "dev", nil, nil, ""},
+			{arrow.Timestamp(3), nil, "prod", "foo", nil, nil},     // no line
+			{nil, "fourth", "prod", "foo", nil, nil},               // no timestamp
+			{arrow.Timestamp(5), "fifth", nil, nil, nil, nil},      // no labels
+			{arrow.Timestamp(6), "sixth", nil, nil, "def", "warn"}, // no stream labels
+		})
+		defer rec.Release()
+
+		converter := newResultConverter(alloc, logsResultSchema)
+		defer converter.Release()
+
+		result := converter.Convert(rec)
+		defer result.Release()
+		require.True(t, logsResultSchema.Equal(result.Schema()))
+
+		require.Equal(t, []resultRow{
+			{
+				Timestamp:          time.Unix(0, 1).UTC(),
+				Line:               "first",
+				Labels:             map[string]string{"app": "foo", "env": "prod"},
+				StructuredMetadata: map[string]string{"trace_id": "abc"},
+				Parsed:             map[string]string{"level": "info"},
+			},
+			{
+				Timestamp:          time.Unix(0, 2).UTC(),
+				Line:               "second",
+				Labels:             map[string]string{"env": "dev"},
+				StructuredMetadata: map[string]string{},
+				Parsed:             map[string]string{},
+			},
+			{
+				Timestamp:          time.Unix(0, 6).UTC(),
+				Line:               "sixth",
+				Labels:             map[string]string{},
+				StructuredMetadata: map[string]string{"trace_id": "def"},
+				Parsed:             map[string]string{"level": "warn"},
+			},
+		}, resultRows(t, result))
+
+		// Map keys are sorted by name.
+		keys := result.Column(2).(*array.Map).Keys().(*array.String)
+		require.Equal(t, "app", keys.Value(0))
+		require.Equal(t, "env", keys.Value(1))
+	})
+
+	t.Run("samples", func(t *testing.T) {
+		schema := arrow.NewSchema([]arrow.Field{
+			{Name: types.ColumnNameBuiltinTimestamp, Type: arrow.FixedWidthTypes.Timestamp_ns, Metadata: datatype.ColumnMetadataBuiltinTimestamp},
+			{Name: types.ColumnNameGeneratedValue, Type: arrow.PrimitiveTypes.Float64, Metadata: datatype.ColumnMetadata(types.ColumnTypeGenerated, datatype.Loki.Float)},
+			{Name: "instance", Type: arrow.BinaryTypes.String, Metadata: mdTypeLabel},
+			{Name: "env", Type: arrow.BinaryTypes.String, Metadata: mdTypeParsed},
+		}, nil)
+		rec := createRecord(t, schema, [][]interface{}{
+			{arrow.Timestamp(1e9), 42.0, "localhost:9090", "prod"},
+			{arrow.Timestamp(2e9), nil, "localhost:9090", "prod"}, // no value
+			{nil, 42.0, "localhost:9090", "prod"},                 // no timestamp
+			{arrow.Timestamp(3e9), 1.5, nil, nil},
+		})
+		defer rec.Release()
+
+		converter := newResultConverter(alloc, samplesResultSchema)
+		defer converter.Release()
+
+		result := converter.Convert(rec)
+		defer result.Release()
+		require.True(t, samplesResultSchema.Equal(result.Schema()))
+
+		require.Equal(t, []resultRow{
+			{Timestamp: time.Unix(1, 0).UTC(), Value: 42, Labels: map[string]string{"env": "prod", "instance": "localhost:9090"}},
+			{Timestamp: time.Unix(3, 0).UTC(), Value: 1.5, Labels: map[string]string{}},
+		}, resultRows(t, result))
+	})
+
+	t.Run("missing columns", func(t *testing.T) {
+		schema := arrow.NewSchema([]arrow.Field{
+			{Name: "env", Type: arrow.BinaryTypes.String, Metadata: mdTypeLabel},
+		}, nil)
+		rec := createRecord(t, schema, [][]interface{}{{"prod"}})
+		defer rec.Release()
+
+		for _, resultSchema := range []*arrow.Schema{logsResultSchema, samplesResultSchema} {
+			converter := newResultConverter(alloc, resultSchema)
+			result := converter.Convert(rec)
+			require.EqualValues(t, 0, result.NumRows())
+			result.Release()
+			converter.Release()
+		}
+	})
+}
+
+func newTestEngine() *QueryEngine {
+	return New(logql.EngineOpts{BatchSize: 100}, metastore.Config{}, nil, nil, prometheus.NewRegistry(), log.NewNopLogger())
+}
+
+// validateTestParams rejects queries that end after 100s and clamps the start
+// of queries to 10s.
+func validateTestParams(_ context.Context, params logql.Params) (logql.Params, error) {
+	if params.End().After(time.Unix(100, 0)) {
+		return nil, errors.New("query too long")
+	}
+	start := params.Start()
+	if start.Before(time.Unix(10, 0)) {
+		start = time.Unix(10, 0)
+	}
+	return logql.NewLiteralParams(params.QueryString(), start, params.End(), params.Step(), params.Interval(),
+		params.Direction(), params.Limit(), params.Shards(), nil)
+}
+
+func TestStreamHandler(t *testing.T) {
+	handler := NewStreamHandler(newTestEngine(), validateTestParams, log.NewNopLogger())
+
+	t.Run("streams results", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, QueryArrowPath+"?"+url.Values{
+			"query": {"vector(1) + 1.5"},
+			"start": {"10"},
+			"end":   {"20"},
+			"step":  {"10"},
+		}.Encode(), nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		resp := rec.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, ContentTypeArrowStream, resp.Header.Get("Content-Type"))
+		require.Empty(t, resp.Trailer.Get(ErrorTrailer))
+
+		records, err := readRecords(rec.Body, memory.DefaultAllocator)
+		require.NoError(t, err)
+
+		var rows []resultRow
+		for _, rec := range records {
+			require.True(t, samplesResultSchema.Equal(rec.Schema()))
+			rows = append(rows, resultRows(t, rec)...)
+			rec.Release()
+		}
+		require.Equal(t, []resultRow{
+			{Timestamp: time.Unix(10, 0).UTC(), Value: 2.5, Labels: map[string]string{}},
+			{Timestamp: time.Unix(20, 0).UTC(), Value: 2.5, Labels: map[string]string{}},
+		}, rows)
+	})
+
+	t.Run("writes schema of empty result", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, QueryArrowPath, bytes.NewBufferString(url.Values{
+			"query": {"vector(1) > 2"},
+			"start": {"10"},
+			"end":   {"20"},
+			"step":  {"10"},
+		}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NotZero(t, rec.Body.Len())
+
+		records, err := readRecords(rec.Body, memory.DefaultAllocator)
+		require.NoError(t, err)
+		require.Empty(t, records)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, QueryArrowPath+"?query=%7B&start=10&end=20", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("validates request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, QueryArrowPath+"?query=vector(1)&start=10&end=200&step=10", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Contains(t, rec.Body.String(), "query too long")
+	})
+
+	t.Run("executes validated params", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, QueryArrowPath+"?query=vector(1)%2B0&start=0&end=10&step=10", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		records, err := readRecords(rec.Body, memory.DefaultAllocator)
+		require.NoError(t, err)
+
+		var rows []resultRow
+		for _, rec := range records {
+			rows = append(rows, resultRows(t, rec)...)
+			rec.Release()
+		}
+		require.Equal(t, []resultRow{
+			{Timestamp: time.Unix(10, 0).UTC(), Value: 1, Labels: map[string]string{}},
+		}, rows)
+	})
+
+	t.Run("query not supported", func(t *testing.T) {
+		// Log queries cannot be planned without a metastore.
+		req := httptest.NewRequest(http.MethodGet, QueryArrowPath+"?"+url.Values{
+			"query": {`{app="foo"}`},
+			"start": {"10"},
+			"end":   {"20"},
+		}.Encode(), nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusNotImplemented, rec.Code)
+		require.NotEqual(t, ContentTypeArrowStream, rec.Header().Get("Content-Type"))
+	})
+}
+
+func TestFlightServer(t *testing.T) {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	flight.RegisterFlightServiceServer(server, NewFlightServer(newTestEngine(), validateTestParams, log.NewNopLogger()))
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+	client := flight.NewFlightServiceClient(conn)
+
+	t.Run("streams results", func(t *testing.T) {
+		stream, err := client.DoGet(t.Context(), &flight.Ticket{Ticket: []byte(url.Values{
+			"query": {"vector(1) + 1.5"},
+			"start": {"10"},
+			"end":   {"20"},
+			"step":  {"10"},
+		}.Encode())})
+		require.NoError(t, err)
+
+		reader, err := flight.NewRecordReader(stream)
+		require.NoError(t, err)
+		defer reader.Release()
+		require.True(t, samplesResultSchema.Equal(reader.Schema()))
+
+		var rows []resultRow
+		for reader.Next() {
+			rows = append(rows, resultRows(t, reader.Record())...)
+		}
+		require.NoError(t, reader.Err())
+		require.Equal(t, []resultRow{
+			{Timestamp: time.Unix(10, 0).UTC(), Value: 2.5, Labels: map[string]string{}},
+			{Timestamp: time.Unix(20, 0).UTC(), Value: 2.5, Labels: map[string]string{}},
+		}, rows)
+	})
+
+	t.Run("invalid ticket", func(t *testing.T) {
+		stream, err := client.DoGet(t.Context(), &flight.Ticket{Ticket: []byte("query=%7B")})
+		require.NoError(t, err)
+
+		_, err = stream.Recv()
+		require.Equal(t, codes.InvalidArgument, grpcstatus.Code(err))
+	})
+
+	t.Run("validates ticket", func(t *testing.T) {
+		stream, err := client.DoGet(t.Context(), &flight.Ticket{Ticket: []byte("query=vector(1)&start=10&end=200&step=10")})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.Equal(t, codes.InvalidArgument, grpcstatus.Code(err))
+		require.Equal(t, "query too long", grpcstatus.Convert(err).Message())
+	})
+}
+
+// readRecords reads all records from a sequence of Arrow IPC streams. The
+// caller must release the returned records.
+func readRecords(r io.Reader, alloc memory.Allocator) ([]arrow.Record, error) {
+	var records []arrow.Record
+	release := func() {
+		for _, rec := range records {
+			rec.Release()
+		}
+	}
+
+	for {
+		stream, err := ipc.NewReader(r, ipc.WithAllocator(alloc))
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		} else if err != nil {
+			release()
+			return nil, fmt.Errorf("reading arrow stream: %w", err)
+		}
+
+		for stream.Next() {
+			rec := stream.Record()
+			rec.Retain()
+			records = append(records, rec)
+		}
+		err = stream.Err()
+		stream.Release()
+		if err != nil {
+			release()
+			return nil, fmt.Errorf("reading arrow record: %w", err)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package logql
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/axiomhq/hyperloglog"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	promql_parser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+)
+
+const (
+	CountDistinctSketchMatrixType = "CountDistinctSketchMatrix"
+)
+
+type (
+	CountDistinctSketchVector []CountDistinctSketchSample
+	CountDistinctSketchMatrix []CountDistinctSketchVector
+)
+
+// CountDistinctSketchSample holds a HyperLogLog sketch of the distinct values
+// of a label of a series at a given step.
+type CountDistinctSketchSample struct {
+	T int64
+	F *hyperloglog.Sketch
+
+	Metric labels.Labels
+}
+
+func (s CountDistinctSketchSample) ToProto() (*logproto.CountDistinctSketchSample, error) {
+	metric := make([]*logproto.LabelPair, 0, s.Metric.Len())
+	s.Metric.Range(func(l labels.Label) {
+		metric = append(metric, &logproto.LabelPair{Name: l.Name, Value: l.Value})
+	})
+
+	hll, err := s.F.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &logproto.CountDistinctSketchSample{
+		Hyperloglog: hll,
+		TimestampMs: s.T,
+		Metric:      metric,
+	}, nil
+}
+
+func countDistinctSketchSampleFromProto(proto *logproto.CountDistinctSketchSample) (CountDistinctSketchSample, error) {
+	hll := hyperloglog.New()
+	if err := hll.UnmarshalBinary(proto.Hyperloglog); err != nil {
+		return CountDistinctSketchSample{}, err
+	}
+	out := CountDistinctSketchSample{
+		T: proto.TimestampMs,
+		F: hll,
+	}
+
+	b := labels.NewScratchBuilder(len(proto.Metric))
+	for _, p := range proto.Metric {
+		b.Add(p.Name, p.Value)
+	}
+	out.Metric = b.Labels()
+
+	return out, nil
+}
+
+func (v CountDistinctSketchVector) Merge(right CountDistinctSketchVector) (CountDistinctSketchVector, error) {
+	// labels hash to vector index map
+	groups := streamHashPool.Get().(map[uint64]int)
+	defer func() {
+		clear(groups)
+		streamHashPool.Put(groups)
+	}()
+	for i, sample := range v {
+		groups[labels.StableHash(sample.Metric)] = i
+	}
+
+	for _, sample := range right {
+		i, ok := groups[labels.StableHash(sample.Metric)]
+		if !ok {
+			v = append(v, sample)
+			continue
+		}
+
+		if err := v[i].F.Merge(sample.F); err != nil {
+			return v, err
+		}
+	}
+
+	return v, nil
+}
+
+func (CountDistinctSketchVector) SampleVector() promql.Vector {
+	return promql.Vector{}
+}
+
+func (CountDistinctSketchVector) QuantileSketchVec() ProbabilisticQuantileVector {
+	return ProbabilisticQuantileVector{}
+}
+
+func (CountDistinctSketchVector) CountMinSketchVec() CountMinSketchVector {
+	return CountMinSketchVector{}
+}
+
+func (v CountDistinctSketchVector) CountDistinctSketchVec() CountDistinctSketchVector {
+	return v
+}
+
+func (v CountDistinctSketchVector) ToProto() (*logproto.CountDistinctSketchVector, error) {
+	samples := make([]*logproto.CountDistinctSketchSample, len(v))
+	for i, sample := range v {
+		s, err := sample.ToProto()
+		if err != nil {
+			return nil, err
+		}
+		samples[i] = s
+	}
+	return &logproto.CountDistinctSketchVector{Samples: samples}, nil
+}
+
+func CountDistinctSketchVectorFromProto(proto *logproto.CountDistinctSketchVector) (CountDistinctSketchVector, error) {
+	out := make([]CountDistinctSketchSample, len(proto.Samples))
+	for i, sample := range proto.Samples {
+		s, err := countDistinctSketchSampleFromProto(sample)
+		if err != nil {
+			return CountDistinctSketchVector{}, err
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+func (CountDistinctSketchMatrix) String() string {
+	return "CountDistinctSketchMatrix()"
+}
+
+func (m CountDistinctSketchMatrix) Merge(right CountDistinctSketchMatrix) (CountDistinctSketchMatrix, error) {
+	if len(m) != len(right) {
+		return nil, fmt.Errorf("failed to merge count distinct sketch matrix: lengths differ %d!=%d", len(m), len(right))
+	}
+	var err error
+	for i, vec := range m {
+		m[i], err = vec.Merge(right[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge count distinct sketch matrix: %w", err)
+		}
+	}
+
+	return m, nil
+}
+
+func (CountDistinctSketchMatrix) Type() promql_parser.ValueType { return CountDistinctSketchMatrixType }
+
+func (m CountDistinctSketchMatrix) ToProto() (*logproto.CountDistinctSketchMatrix, error) {
+	values := make([]*logproto.CountDistinctSketchVector, len(m))
+	for i, vec := range m {
+		v, err := vec.ToProto()
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return &logproto.CountDistinctSketchMatrix{Values: values}, nil
+}
+
+func CountDistinctSketchMatrixFromProto(proto *logproto.CountDistinctSketchMatrix) (CountDistinctSketchMatrix, error) {
+	out := make([]CountDistinctSketchVector, len(proto.Values))
+	for i, v := range proto.Values {
+		s, err := CountDistinctSketchVectorFromProto(v)
+		if err != nil {
+			return CountDistinctSketchMatrix{}, err
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+// newCountDistinctSketch returns a sketch of the label value hashes of
+// samples extracted with unwrap_label.
+func newCountDistinctSketch(samples []promql.FPoint) *hyperloglog.Sketch {
+	s := hyperloglog.New()
+	for _, v := range samples {
+		s.InsertHash(log.LabelHashFromValue(v.F))
+	}
+	return s
+}
+
+type CountDistinctSketchStepEvaluator struct {
+	iter RangeVectorIterator
+
+	err error
+}
+
+func (e *CountDistinctSketchStepEvaluator) Next() (bool, int64, StepResult) {
+	next := e.iter.Next()
+	if !next {
+		return false, 0, CountDistinctSketchVector{}
+	}
+	ts, r := e.iter.At()
+	vec := r.CountDistinctSketchVec()
+	for _, s := range vec {
+		// Errors are not allowed in metrics unless they've been specifically requested.
+		if s.Metric.Has(logqlmodel.ErrorLabel) && s.Metric.Get(logqlmodel.PreserveErrorLabel) != "true" {
+			e.err = logqlmodel.NewPipelineErr(s.Metric)
+			return false, 0, CountDistinctSketchVector{}
+		}
+	}
+	return true, ts, vec
+}
+
+func (e *CountDistinctSketchStepEvaluator) Close() error { return e.iter.Close() }
+
+func (e *CountDistinctSketchStepEvaluator) Error() error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.iter.Error()
+}
+
+func (e *CountDistinctSketchStepEvaluator) Explain(parent Node) {
+	parent.Child("CountDistinctSketch")
+}
+
+func newCountDistinctSketchIterator(
+	it iter.PeekingSampleIterator,
+	selRange, step, start, end, offset int64,
+) RangeVectorIterator {
+	// forces at least one step.
+	if step == 0 {
+		step = 1
+	}
+	if offset != 0 {
+		start = start - offset
+		end = end - offset
+	}
+
+	inner := &batchRangeVectorIterator{
+		iter:     it,
+		step:     step,
+		end:      end,
+		selRange: selRange,
+		metrics:  map[string]labels.Labels{},
+		window:   map[string]*promql.Series{},
+		agg:      nil,
+		current:  start - step, // first loop iteration will set it to start
+		offset:   offset,
+	}
+	return &countDistinctSketchBatchRangeVectorIterator{
+		batchRangeVectorIterator: inner,
+	}
+}
+
+type countDistinctSketchBatchRangeVectorIterator struct {
+	*batchRangeVectorIterator
+}
+
+func (r *countDistinctSketchBatchRangeVectorIterator) At() (int64, StepResult) {
+	at := make([]CountDistinctSketchSample, 0, len(r.window))
+	// convert ts from nano to milli seconds as the iterator work with nanoseconds
+	ts := r.current/1e+6 + r.offset/1e+6
+	for _, series := range r.window {
+		at = append(at, CountDistinctSketchSample{
+			F:      newCountDistinctSketch(series.Floats),
+			T:      ts,
+			Metric: series.Metric,
+		})
+	}
+	return ts, CountDistinctSketchVector(at)
+}
+
+// JoinCountDistinctSketchVector joins the results from stepEvaluator into a CountDistinctSketchMatrix.
+func JoinCountDistinctSketchVector(next bool, r StepResult, stepEvaluator StepEvaluator, params Params) (promql_parser.Value, error) {
+	vec := r.CountDistinctSketchVec()
+	if stepEvaluator.Error() != nil {
+		return nil, stepEvaluator.Error()
+	}
+
+	if GetRangeType(params) == InstantType {
+		return CountDistinctSketchMatrix{vec}, nil
+	}
+
+	stepCount := int(math.Ceil(float64(params.End().Sub(params.Start()).Nanoseconds()) / float64(params.Step().Nanoseconds())))
+	if stepCount <= 0 {
+		stepCount = 1
+	}
+
+	result := make(CountDistinctSketchMatrix, 0, stepCount)
+
+	for next {
+		result = append(result, vec)
+		next, _, r = stepEvaluator.Next()
+		vec = r.CountDistinctSketchVec()
+		if stepEvaluator.Error() != nil {
+			return nil, stepEvaluator.Error()
+		}
+	}
+
+	return result, stepEvaluator.Error()
+}
+
+// CountDistinctSketchMatrixStepEvaluator steps through a matrix of count
+// distinct sketch vectors, ie HyperLogLog sketches per time step.
+type CountDistinctSketchMatrixStepEvaluator struct {
+	end, ts time.Time
+	step    time.Duration
+	m       CountDistinctSketchMatrix
+}
+
+func NewCountDistinctSketchMatrixStepEvaluator(m CountDistinctSketchMatrix, params Params) *CountDistinctSketchMatrixStepEvaluator {
+	var (
+		step = params.Step()
+	)
+	return &CountDistinctSketchMatrixStepEvaluator{
+		end:  params.End(),
+		ts:   params.Start().Add(-step), // will be corrected on first Next() call
+		step: step,
+		m:    m,
+	}
+}
+
+func (m *CountDistinctSketchMatrixStepEvaluator) Next() (bool, int64, StepResult) {
+	m.ts = m.ts.Add(m.step)
+	if m.ts.After(m.end) {
+		return false, 0, nil
+	}
+
+	ts := m.ts.UnixNano() / int64(time.Millisecond)
+
+	if len(m.m) == 0 {
+		return false, 0, nil
+	}
+
+	vec := m.m[0]
+
+	// Reset for next step
+	m.m = m.m[1:]
+
+	return true, ts, vec
+}
+
+func (*CountDistinctSketchMatrixStepEvaluator) Close() error { return nil }
+
+func (*CountDistinctSketchMatrixStepEvaluator) Error() error { return nil }
+
+func (*CountDistinctSketchMatrixStepEvaluator) Explain(parent Node) {
+	parent.Child("CountDistinctSketchMatrix")
+}
+
+// CountDistinctSketchVectorStepEvaluator evaluates a count distinct sketch
+// into a promql.Vector of estimated cardinalities.
+type CountDistinctSketchVectorStepEvaluator struct {
+	inner StepEvaluator
+}
+
+var _ StepEvaluator = NewCountDistinctSketchVectorStepEvaluator(nil)
+
+func NewCountDistinctSketchVectorStepEvaluator(inner StepEvaluator) *CountDistinctSketchVectorStepEvaluator {
+	return &CountDistinctSketchVectorStepEvaluator{
+		inner: inner,
+	}
+}
+
+func (e *CountDistinctSketchVectorStepEvaluator) Next() (bool, int64, StepResult) {
+	ok, ts, r := e.inner.Next()
+	if !ok {
+		return false, 0, SampleVector{}
+	}
+	countDistinctSketchVec := r.CountDistinctSketchVec()
+
+	vec := make(promql.Vector, len(countDistinctSketchVec))
+
+	for i, s := range countDistinctSketchVec {
+		vec[i] = promql.Sample{
+			T:      s.T,
+			F:      float64(s.F.Estimate()),
+			Metric: s.Metric,
+		}
+	}
+
+	return ok, ts, SampleVector(vec)
+}
+
+func (*CountDistinctSketchVectorStepEvaluator) Close() error { return nil }
+
+func (*CountDistinctSketchVectorStepEvaluator) Error() error { return nil }
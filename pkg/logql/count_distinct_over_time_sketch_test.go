@@ -0,0 +1,87 @@
+package logql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+)
+
+func TestCountDistinctSketchMatrixSerialization(t *testing.T) {
+	sketch := newCountDistinctSketch(labelHashes("foo", "bar", "foo"))
+
+	matrix := CountDistinctSketchMatrix([]CountDistinctSketchVector{
+		[]CountDistinctSketchSample{
+			{T: 0, F: sketch, Metric: labels.FromStrings("foo", "bar")},
+		},
+	})
+
+	proto, err := matrix.ToProto()
+	require.NoError(t, err)
+
+	actual, err := CountDistinctSketchMatrixFromProto(proto)
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Len(t, actual[0], 1)
+	require.Equal(t, labels.FromStrings("foo", "bar"), actual[0][0].Metric)
+	require.Equal(t, uint64(2), actual[0][0].F.Estimate())
+}
+
+func TestCountDistinctSketchVectorMerge(t *testing.T) {
+	left := CountDistinctSketchVector{
+		{T: 10, F: newCountDistinctSketch(labelHashes("a", "b")), Metric: labels.FromStrings("app", "foo")},
+	}
+	right := CountDistinctSketchVector{
+		{T: 10, F: newCountDistinctSketch(labelHashes("b", "c")), Metric: labels.FromStrings("app", "foo")},
+		{T: 10, F: newCountDistinctSketch(labelHashes("d")), Metric: labels.FromStrings("app", "bar")},
+	}
+
+	merged, err := left.Merge(right)
+	require.NoError(t, err)
+	require.Len(t, merged, 2)
+
+	estimates := map[string]uint64{}
+	for _, s := range merged {
+		estimates[s.Metric.Get("app")] = s.F.Estimate()
+	}
+	require.Equal(t, map[string]uint64{"foo": 3, "bar": 1}, estimates)
+}
+
+func TestCountDistinctSketchStepEvaluatorError(t *testing.T) {
+	iter := errorRangeVectorIterator{
+		result: CountDistinctSketchVector([]CountDistinctSketchSample{
+			{T: 43, F: nil, Metric: labels.FromStrings(logqlmodel.ErrorLabel, "my error")},
+		}),
+	}
+	ev := CountDistinctSketchStepEvaluator{
+		iter: iter,
+	}
+	ok, _, _ := ev.Next()
+	require.False(t, ok)
+
+	err := ev.Error()
+	require.ErrorContains(t, err, "my error")
+}
+
+func TestJoinCountDistinctSketchVectorError(t *testing.T) {
+	result := CountDistinctSketchVector{}
+	ev := errorStepEvaluator{
+		err: errors.New("could not evaluate"),
+	}
+	_, err := JoinCountDistinctSketchVector(true, result, ev, LiteralParams{})
+	require.ErrorContains(t, err, "could not evaluate")
+}
+
+// labelHashes returns the samples extracted by unwrap_label for values.
+func labelHashes(values ...string) []promql.FPoint {
+	points := make([]promql.FPoint, 0, len(values))
+	for i, v := range values {
+		points = append(points, promql.FPoint{T: int64(i), F: float64(xxhash.Sum64String(v) >> 11)})
+	}
+	return points
+}
@@ -328,6 +328,62 @@ func (e *QuantileSketchMergeExpr) Walk(f syntax.WalkFn) {
 	}
 }
 
+// CountDistinctSketchEvalExpr evaluates a count distinct sketch to the
+// estimated number of distinct values.
+type CountDistinctSketchEvalExpr struct {
+	syntax.SampleExpr
+	mergeExpr *CountDistinctSketchMergeExpr
+}
+
+func (e CountDistinctSketchEvalExpr) String() string {
+	return fmt.Sprintf("countDistinctSketchEval<%s>", e.mergeExpr.String())
+}
+
+func (e *CountDistinctSketchEvalExpr) Walk(f syntax.WalkFn) {
+	if !f(e) {
+		return
+	}
+	if e.SampleExpr != nil {
+		e.SampleExpr.Walk(f)
+	}
+	if e.mergeExpr != nil {
+		e.mergeExpr.Walk(f)
+	}
+}
+
+type CountDistinctSketchMergeExpr struct {
+	syntax.SampleExpr
+	downstreams []DownstreamSampleExpr
+}
+
+func (e CountDistinctSketchMergeExpr) String() string {
+	var sb strings.Builder
+	for i, d := range e.downstreams {
+		if i >= defaultMaxDepth {
+			break
+		}
+
+		if i > 0 {
+			sb.WriteString(" ++ ")
+		}
+
+		sb.WriteString(d.String())
+	}
+	return fmt.Sprintf("countDistinctSketchMerge<%s>", sb.String())
+}
+
+func (e *CountDistinctSketchMergeExpr) Walk(f syntax.WalkFn) {
+	if !f(e) {
+		return
+	}
+	if e.SampleExpr != nil {
+		e.SampleExpr.Walk(f)
+	}
+	for _, d := range e.downstreams {
+		d.Walk(f)
+	}
+}
+
 type MergeFirstOverTimeExpr struct {
 	syntax.SampleExpr
 	downstreams []DownstreamSampleExpr
@@ -602,6 +658,36 @@ func (ev *DownstreamEvaluator) NewStepEvaluator(
 		}
 		inner := NewQuantileSketchMatrixStepEvaluator(matrix, params)
 		return NewQuantileSketchVectorStepEvaluator(inner, *e.quantile), nil
+	case *CountDistinctSketchEvalExpr:
+		var queries []DownstreamQuery
+		if e.mergeExpr != nil {
+			for _, d := range e.mergeExpr.downstreams {
+				qry := DownstreamQuery{
+					Params: ParamsWithExpressionOverride{
+						Params:             ParamOverridesFromShard(params, d.shard),
+						ExpressionOverride: d.SampleExpr,
+					},
+				}
+				queries = append(queries, qry)
+			}
+		}
+
+		acc := newCountDistinctSketchAccumulator()
+		results, err := ev.Downstream(ctx, queries, acc)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(results) != 1 {
+			return nil, fmt.Errorf("unexpected results length for sharded count distinct: got (%d), want (1)", len(results))
+		}
+
+		matrix, ok := results[0].Data.(CountDistinctSketchMatrix)
+		if !ok {
+			return nil, fmt.Errorf("unexpected matrix type: got (%T), want (CountDistinctSketchMatrix)", results[0].Data)
+		}
+		inner := NewCountDistinctSketchMatrixStepEvaluator(matrix, params)
+		return NewCountDistinctSketchVectorStepEvaluator(inner), nil
 	case *MergeFirstOverTimeExpr:
 		queries := make([]DownstreamQuery, len(e.downstreams))
 
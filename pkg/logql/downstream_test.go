@@ -63,6 +63,11 @@ func TestMappingEquivalence(t *testing.T) {
 		{`avg_over_time({a=~".+"} | logfmt | unwrap value [1s]) without (stream)`, true, nil},
 		{`avg_over_time({a=~".+"} | logfmt | drop level | unwrap value [1s])`, true, nil},
 		{`avg_over_time({a=~".+"} | logfmt | drop level | unwrap value [1s]) without (stream)`, true, nil},
+		{`max_over_time(sum by (a) (rate({a=~".+"}[1s]))[5s:1s])`, false, nil},
+		{`avg_over_time(sum(rate({a=~".+"}[1s]))[5s:2s] offset 1s)`, false, nil},
+		{`sum by (a) (rate({a=~".+"}[2s] @ 10))`, false, nil},
+		{`count_over_time({a=~".+"}[3s] @ end() offset 2s)`, false, nil},
+		{`max_over_time(sum by (a) (rate({a=~".+"}[1s]))[5s:1s] @ start())`, false, nil},
 		{`quantile_over_time(0.99, {a=~".+"} | logfmt | unwrap value [1s])`, true, []string{ShardQuantileOverTime}},
 		{`quantile_over_time(0.99, {a=~".+"} | logfmt | unwrap value [1s] offset 2s)`, true, []string{ShardQuantileOverTime}},
 		{
@@ -196,6 +201,7 @@ func TestMappingEquivalenceSketches(t *testing.T) {
 		{`quantile_over_time(0.70, {a=~".+"} | logfmt | unwrap value [1s]) by (a)`, 0.05},
 		{`quantile_over_time(0.99, {a=~".+"} | logfmt | unwrap value [1s]) by (a)`, 0.02},
 		{`quantile_over_time(0.99, {a=~".+"} | logfmt | unwrap value [1s] offset 2s) by (a)`, 0.02},
+		{`approx_count_distinct_over_time({a=~".+"} | logfmt | unwrap_label value [1s]) by (a)`, 0.02},
 	} {
 		q := NewMockQuerier(
 			shards,
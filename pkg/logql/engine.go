@@ -259,6 +259,8 @@ func (q *query) resultLength(res promql_parser.Value) int {
 		return int(r.Lines())
 	case ProbabilisticQuantileMatrix:
 		return len(r)
+	case CountDistinctSketchMatrix:
+		return len(r)
 	default:
 		// for `scalar` or `string` or any other return type, we just return `0` as result length.
 		return 0
@@ -421,6 +423,16 @@ func (q *query) evalSample(ctx context.Context, expr syntax.SampleExpr) (promql_
 		return nil, err
 	}
 
+	// @ start() and @ end() refer to the time range of the query, including
+	// within subqueries which are evaluated over other time ranges. Sharded
+	// queries are resolved by the frontend before they are sharded.
+	if !isShardingAST(expr) {
+		expr, err = syntax.ResolveAtModifiers(expr, q.params.Start(), q.params.End())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	stepEvaluator, err := q.evaluator.NewStepEvaluator(ctx, q.evaluator, expr, q.params)
 	if err != nil {
 		return nil, err
@@ -444,6 +456,8 @@ func (q *query) evalSample(ctx context.Context, expr syntax.SampleExpr) (promql_
 			return q.JoinSampleVector(ctx, next, vec, stepEvaluator, maxSeries, mfl)
 		case ProbabilisticQuantileVector:
 			return JoinQuantileSketchVector(next, vec, stepEvaluator, q.params)
+		case CountDistinctSketchVector:
+			return JoinCountDistinctSketchVector(next, vec, stepEvaluator, q.params)
 		case CountMinSketchVector:
 			return JoinCountMinSketchVector(next, vec, stepEvaluator, q.params)
 		case HeapCountMinSketchVector:
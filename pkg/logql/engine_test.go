@@ -1447,6 +1447,96 @@ func TestEngine_RangeQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			`max_over_time(count_over_time({app="foo"}[10s])[30s:10s])`, time.Unix(300, 0), time.Unix(320, 0), 10 * time.Second, 0, logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{newSeries(testSize, identity, `{app="foo"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(260, 0), End: time.Unix(320, 0), Selector: `count_over_time({app="foo"}[10s])`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.FromStrings("app", "foo"),
+					Floats: []promql.FPoint{{T: 300000, F: 10}, {T: 310000, F: 10}, {T: 320000, F: 9}},
+				},
+			},
+		},
+		{
+			`max_over_time(sum(count_over_time({app=~"foo|bar"}[10s]))[30s:10s])`, time.Unix(300, 0), time.Unix(320, 0), 10 * time.Second, 0, logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{newSeries(testSize, identity, `{app="foo"}`), newSeries(testSize, identity, `{app="bar"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(260, 0), End: time.Unix(320, 0), Selector: `sum(count_over_time({app=~"foo|bar"}[10s]))`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.EmptyLabels(),
+					Floats: []promql.FPoint{{T: 300000, F: 20}, {T: 310000, F: 20}, {T: 320000, F: 18}},
+				},
+			},
+		},
+		{
+			`count_over_time({app="foo"}[10s] @ start())`, time.Unix(300, 0), time.Unix(320, 0), 10 * time.Second, 0, logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{newSeries(305, identity, `{app="foo"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(290, 0), End: time.Unix(300, 0), Selector: `count_over_time({app="foo"}[10s] @ 300)`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.FromStrings("app", "foo"),
+					Floats: []promql.FPoint{{T: 300000, F: 10}, {T: 310000, F: 10}, {T: 320000, F: 10}},
+				},
+			},
+		},
+		{
+			`count_over_time({app="foo"}[10s] @ 305)`, time.Unix(300, 0), time.Unix(320, 0), 10 * time.Second, 0, logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{newSeries(300, identity, `{app="foo"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(295, 0), End: time.Unix(305, 0), Selector: `count_over_time({app="foo"}[10s] @ 305)`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.FromStrings("app", "foo"),
+					Floats: []promql.FPoint{{T: 300000, F: 4}, {T: 310000, F: 4}, {T: 320000, F: 4}},
+				},
+			},
+		},
+		{
+			`count_over_time({app="foo"}[10s] @ 305 offset 5s)`, time.Unix(300, 0), time.Unix(320, 0), 10 * time.Second, 0, logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{newSeries(300, identity, `{app="foo"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(290, 0), End: time.Unix(300, 0), Selector: `count_over_time({app="foo"}[10s] @ 305 offset 5s)`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.FromStrings("app", "foo"),
+					Floats: []promql.FPoint{{T: 300000, F: 9}, {T: 310000, F: 9}, {T: 320000, F: 9}},
+				},
+			},
+		},
+		{
+			`min_over_time(count_over_time({app="foo"}[10s])[30s:10s] @ end())`, time.Unix(300, 0), time.Unix(320, 0), 10 * time.Second, 0, logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{newSeries(305, identity, `{app="foo"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(280, 0), End: time.Unix(320, 0), Selector: `count_over_time({app="foo"}[10s])`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.FromStrings("app", "foo"),
+					Floats: []promql.FPoint{{T: 300000, F: 4}, {T: 310000, F: 4}, {T: 320000, F: 4}},
+				},
+			},
+		},
 		{
 			`rate(({app=~"foo|bar"} |~".+bar" | unwrap bar)[1m])`, time.Unix(60, 0), time.Unix(180, 0), 30 * time.Second, 0, logproto.FORWARD, 100,
 			[][]logproto.Series{
@@ -2745,6 +2835,10 @@ func (s *storeSampleResult) CountMinSketchVec() CountMinSketchVector {
 	return CountMinSketchVector{}
 }
 
+func (s *storeSampleResult) CountDistinctSketchVec() CountDistinctSketchVector {
+	return CountDistinctSketchVector{}
+}
+
 func TestEngine_Variants_RangeQuery(t *testing.T) {
 	t.Parallel()
 
@@ -3193,6 +3287,39 @@ func TestStepEvaluator_Error(t *testing.T) {
 	}
 }
 
+func TestEngine_SubqueryDefaultStep(t *testing.T) {
+	// samples every 7s, so that the result depends on the resolution of the
+	// subquery.
+	q := &querierRecorder{
+		series: map[string][]logproto.Series{
+			"": {newSeries(1000, factor(7, identity), `{app="foo"}`)},
+		},
+	}
+	eng := NewEngine(EngineOpts{}, q, NoLimits, log.NewNopLogger())
+	ctx := user.InjectOrgID(context.Background(), "fake")
+	qs := `sum_over_time(count_over_time({app="foo"}[10s])[2m:])`
+
+	params, err := NewLiteralParams(qs, time.Unix(600, 0), time.Unix(900, 0), 30*time.Second, 0, logproto.FORWARD, 0, nil, nil)
+	require.NoError(t, err)
+	res, err := eng.Query(params).Exec(ctx)
+	require.NoError(t, err)
+	matrix := res.Data.(promql.Matrix)
+	require.Len(t, matrix, 1)
+	require.Len(t, matrix[0].Floats, 11)
+
+	// the range query must agree with instant queries at each of its steps.
+	for _, p := range matrix[0].Floats {
+		ts := time.UnixMilli(p.T)
+		params, err := NewLiteralParams(qs, ts, ts, 0, 0, logproto.FORWARD, 0, nil, nil)
+		require.NoError(t, err)
+		res, err := eng.Query(params).Exec(ctx)
+		require.NoError(t, err)
+		vector := res.Data.(promql.Vector)
+		require.Len(t, vector, 1)
+		require.Equal(t, p.F, vector[0].F, "instant query at %s", ts)
+	}
+}
+
 func TestEngine_MaxSeries(t *testing.T) {
 	eng := NewEngine(EngineOpts{}, getLocalQuerier(100000), &fakeLimits{maxSeries: 1}, log.NewNopLogger())
 
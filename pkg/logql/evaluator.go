@@ -325,6 +325,22 @@ func (ev *DefaultEvaluator) NewIterator(ctx context.Context, expr syntax.LogSele
 	return ev.querier.SelectLogs(ctx, params)
 }
 
+// sampleRange returns the time range of the samples selected by a log range
+// for the query q. A range with an @ modifier selects the same samples for
+// every step of the query.
+func sampleRange(r *syntax.LogRangeExpr, q Params) (time.Time, time.Time) {
+	start, end := q.Start(), q.End()
+	if r.At != nil {
+		start = r.At.Time(start, end)
+		end = start
+	}
+	// extend startTs backwards by step
+	start = start.Add(-r.Interval).Add(-r.Offset)
+	// add leap nanosecond to endTs to include lines exactly at endTs. range iterators work on start exclusive, end inclusive ranges
+	end = end.Add(-r.Offset).Add(time.Nanosecond)
+	return start, end
+}
+
 func (ev *DefaultEvaluator) NewStepEvaluator(
 	ctx context.Context,
 	nextEvFactory SampleEvaluatorFactory,
@@ -337,12 +353,11 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 			// if range expression is wrapped with a vector expression
 			// we should send the vector expression for allowing reducing labels at the source.
 			nextEvFactory = SampleEvaluatorFunc(func(ctx context.Context, _ SampleEvaluatorFactory, _ syntax.SampleExpr, _ Params) (StepEvaluator, error) {
+				start, end := sampleRange(rangExpr.Left, q)
 				it, err := ev.querier.SelectSamples(ctx, SelectSampleParams{
 					&logproto.SampleQueryRequest{
-						// extend startTs backwards by step
-						Start: q.Start().Add(-rangExpr.Left.Interval).Add(-rangExpr.Left.Offset),
-						// add leap nanosecond to endTs to include lines exactly at endTs. range iterators work on start exclusive, end inclusive ranges
-						End: q.End().Add(-rangExpr.Left.Offset).Add(time.Nanosecond),
+						Start: start,
+						End:   end,
 						// intentionally send the vector for reducing labels.
 						Selector: e.String(),
 						Shards:   q.Shards(),
@@ -362,12 +377,11 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 	case *CountMinSketchEvalExpr:
 		return NewCountMinSketchEvalStepEvaluator(ctx, nextEvFactory, e, q)
 	case *syntax.RangeAggregationExpr:
+		start, end := sampleRange(e.Left, q)
 		it, err := ev.querier.SelectSamples(ctx, SelectSampleParams{
 			&logproto.SampleQueryRequest{
-				// extend startTs backwards by step
-				Start: q.Start().Add(-e.Left.Interval).Add(-e.Left.Offset),
-				// add leap nanosecond to endTs to include lines exactly at endTs. range iterators work on start exclusive, end inclusive ranges
-				End: q.End().Add(-e.Left.Offset).Add(time.Nanosecond),
+				Start: start,
+				End:   end,
 				// intentionally send the vector for reducing labels.
 				Selector: e.String(),
 				Shards:   q.Shards(),
@@ -381,6 +395,8 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 			return nil, err
 		}
 		return newRangeAggEvaluator(iter.NewPeekingSampleIterator(it), e, q, e.Left.Offset)
+	case *syntax.SubqueryExpr:
+		return newSubqueryEvaluator(ctx, nextEvFactory, e, q)
 	case *syntax.BinOpExpr:
 		return newBinOpStepEvaluator(ctx, nextEvFactory, e, q)
 	case *syntax.LabelReplaceExpr:
@@ -672,6 +688,17 @@ func newRangeAggEvaluator(
 		return &QuantileSketchStepEvaluator{
 			iter: iter,
 		}, nil
+	case syntax.OpRangeTypeApproxCountDistinctSketch:
+		iter := newCountDistinctSketchIterator(
+			it,
+			expr.Left.Interval.Nanoseconds(),
+			q.Step().Nanoseconds(),
+			q.Start().UnixNano(), q.End().UnixNano(), o.Nanoseconds(),
+		)
+
+		return &CountDistinctSketchStepEvaluator{
+			iter: iter,
+		}, nil
 	case syntax.OpRangeTypeFirstWithTimestamp:
 		iter := newFirstWithTimestampIterator(
 			it,
@@ -784,6 +811,144 @@ func (r AbsentRangeVectorEvaluator) Error() error {
 	return r.iter.Error()
 }
 
+// defaultSubqueryStep is the resolution of subqueries which don't specify
+// one, e.g. [1h:]. Like the evaluation interval in Prometheus, it doesn't
+// depend on the step of the query, so that the results of a subquery don't
+// change with the step.
+const defaultSubqueryStep = time.Minute
+
+// subqueryParams overrides the time range and the step of the query for the
+// evaluation of the inner expression of a subquery.
+type subqueryParams struct {
+	Params
+	start, end time.Time
+	step       time.Duration
+}
+
+func (p subqueryParams) Start() time.Time    { return p.start }
+func (p subqueryParams) End() time.Time      { return p.end }
+func (p subqueryParams) Step() time.Duration { return p.step }
+
+// newSubqueryEvaluator evaluates the inner expression of a subquery as a range
+// query at the resolution of the subquery, and aggregates the resulting
+// samples over the range of the subquery at each step of the query.
+//
+// Like in Prometheus, the steps of the inner expression are aligned to
+// multiples of the resolution rather than to the start of the query, so that
+// the same samples are aggregated regardless of the query range.
+func newSubqueryEvaluator(
+	ctx context.Context,
+	nextEvFactory SampleEvaluatorFactory,
+	expr *syntax.SubqueryExpr,
+	q Params,
+) (StepEvaluator, error) {
+	step := expr.Step
+	if step == 0 {
+		step = defaultSubqueryStep
+	}
+
+	// with an @ modifier, the subquery is evaluated once at the same time for
+	// every step of the query.
+	evalStart, evalEnd := q.Start(), q.End()
+	if expr.At != nil {
+		evalStart = expr.At.Time(evalStart, evalEnd)
+		evalEnd = evalStart
+	}
+
+	start := evalStart.Add(-expr.Offset).Add(-expr.Range).UnixMilli()
+	stepMs := step.Milliseconds()
+	if rem := start % stepMs; rem != 0 {
+		start -= rem
+		if rem > 0 {
+			start += stepMs
+		}
+	}
+
+	inner, err := nextEvFactory.NewStepEvaluator(ctx, nextEvFactory, expr.Left, subqueryParams{
+		Params: q,
+		start:  time.UnixMilli(start),
+		end:    evalEnd.Add(-expr.Offset),
+		step:   step,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	it, err := newRangeVectorIterator(
+		iter.NewPeekingSampleIterator(&stepEvaluatorSampleIterator{ev: inner}),
+		&syntax.RangeAggregationExpr{
+			Left:      &syntax.LogRangeExpr{Interval: expr.Range, Offset: expr.Offset, At: expr.At},
+			Operation: expr.Operation,
+			Params:    expr.Params,
+		},
+		expr.Range.Nanoseconds(),
+		q.Step().Nanoseconds(),
+		q.Start().UnixNano(), q.End().UnixNano(), expr.Offset.Nanoseconds(),
+	)
+	if err != nil {
+		_ = inner.Close()
+		return nil, err
+	}
+
+	return &SubqueryEvaluator{
+		RangeVectorEvaluator: RangeVectorEvaluator{iter: it},
+		expr:                 expr,
+		inner:                inner,
+	}, nil
+}
+
+// SubqueryEvaluator aggregates the samples of the inner expression of a
+// subquery over the range of the subquery.
+type SubqueryEvaluator struct {
+	RangeVectorEvaluator
+
+	expr  *syntax.SubqueryExpr
+	inner StepEvaluator
+}
+
+// stepEvaluatorSampleIterator turns the vectors of a step evaluator into a
+// sample iterator ordered by timestamp, as expected by range vector iterators.
+type stepEvaluatorSampleIterator struct {
+	ev StepEvaluator
+
+	vec    promql.Vector
+	ts     int64
+	idx    int
+	labels string
+	hash   uint64
+	err    error
+}
+
+func (it *stepEvaluatorSampleIterator) Next() bool {
+	for it.idx >= len(it.vec)-1 {
+		ok, ts, r := it.ev.Next()
+		if !ok {
+			it.err = it.ev.Error()
+			return false
+		}
+		it.vec, it.ts, it.idx = promql.Vector(r.SampleVector()), ts, -1
+	}
+	it.idx++
+	it.labels = it.vec[it.idx].Metric.String()
+	it.hash = it.vec[it.idx].Metric.Hash()
+	return true
+}
+
+func (it *stepEvaluatorSampleIterator) At() logproto.Sample {
+	return logproto.Sample{
+		Timestamp: it.ts * int64(time.Millisecond),
+		Value:     it.vec[it.idx].F,
+	}
+}
+
+func (it *stepEvaluatorSampleIterator) Labels() string { return it.labels }
+
+func (it *stepEvaluatorSampleIterator) StreamHash() uint64 { return it.hash }
+
+func (it *stepEvaluatorSampleIterator) Err() error { return it.err }
+
+func (it *stepEvaluatorSampleIterator) Close() error { return it.ev.Close() }
+
 // newBinOpStepEvaluator explicitly does not handle when both legs are literals as
 // it makes the type system simpler and these are reduced in mustNewBinOpExpr
 func newBinOpStepEvaluator(
@@ -1374,12 +1539,11 @@ func (ev *DefaultEvaluator) NewVariantsStepEvaluator(
 
 		// We don't have the benefit of sending the vector expression to the source for reducing labels
 		// Since multiple samples are allowed, and they may not share the same labels to reduce by
+		start, end := sampleRange(logRange, q)
 		it, err := ev.querier.SelectSamples(ctx, SelectSampleParams{
 			&logproto.SampleQueryRequest{
-				// extend startTs backwards by step
-				Start: q.Start().Add(-logRange.Interval).Add(-logRange.Offset),
-				// add leap nanosecond to endTs to include lines exactly at endTs. range iterators work on start exclusive, end inclusive ranges
-				End:      q.End().Add(-logRange.Offset).Add(time.Nanosecond),
+				Start:    start,
+				End:      end,
 				Selector: expr.String(),
 				Shards:   q.Shards(),
 				Plan: &plan.QueryPlan{
@@ -44,6 +44,11 @@ func (r *RangeVectorEvaluator) Explain(parent Node) {
 	parent.Child("RangeVectorAgg")
 }
 
+func (e *SubqueryEvaluator) Explain(parent Node) {
+	b := parent.Childf("%s Subquery", e.expr.Operation)
+	e.inner.Explain(b)
+}
+
 func (e *AbsentRangeVectorEvaluator) Explain(parent Node) {
 	parent.Child("Absent RangeVectorAgg")
 }
@@ -63,6 +68,11 @@ func (e *QuantileSketchVectorStepEvaluator) Explain(parent Node) {
 	e.inner.Explain(b)
 }
 
+func (e *CountDistinctSketchVectorStepEvaluator) Explain(parent Node) {
+	b := parent.Child("CountDistinctSketchVector")
+	e.inner.Explain(b)
+}
+
 func (e *mergeOverTimeStepEvaluator) Explain(parent Node) {
 	parent.Child("MergeFirstOverTime")
 }
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/pkg/errors"
 	"github.com/prometheus/prometheus/model/labels"
 
@@ -16,6 +17,13 @@ const (
 	ConvertBytes    = "bytes"
 	ConvertDuration = "duration"
 	ConvertFloat    = "float"
+	// ConvertLabelHash converts a label value into a hash of the value, see
+	// LabelHashFromValue.
+	ConvertLabelHash = "label_hash"
+
+	// labelHashShift is the number of low bits of a label value hash which
+	// are dropped so that the hash is exactly representable by a float64.
+	labelHashShift = 11
 )
 
 // LineExtractor extracts a float64 from a log line.
@@ -156,6 +164,8 @@ func LabelExtractorWithStages(
 		convFn = convertDuration
 	case ConvertFloat:
 		convFn = convertFloat
+	case ConvertLabelHash:
+		convFn = convertLabelHash
 	default:
 		return nil, errors.Errorf("unsupported conversion operation %s", conversion)
 	}
@@ -325,6 +335,18 @@ func convertDuration(v string) (float64, error) {
 	return d.Seconds(), nil
 }
 
+// convertLabelHash converts a label value into the upper 53 bits of its hash,
+// so that distinct label values can be counted from the extracted samples.
+func convertLabelHash(v string) (float64, error) {
+	return float64(xxhash.Sum64String(v) >> labelHashShift), nil
+}
+
+// LabelHashFromValue returns the label value hash of a sample value extracted
+// with ConvertLabelHash. The dropped low bits of the hash are zero.
+func LabelHashFromValue(v float64) uint64 {
+	return uint64(v) << labelHashShift
+}
+
 func convertBytes(v string) (float64, error) {
 	b, err := humanize.ParseBytes(v)
 	if err != nil {
@@ -2,9 +2,13 @@ package log
 
 import (
 	"bytes"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 	"unsafe"
 
@@ -34,6 +38,9 @@ var (
 	_ Stage = &JSONParser{}
 	_ Stage = &RegexpParser{}
 	_ Stage = &LogfmtParser{}
+	_ Stage = &XMLParser{}
+	_ Stage = &XMLExpressionParser{}
+	_ Stage = &DelimitedParser{}
 
 	trueBytes = []byte("true")
 
@@ -41,6 +48,9 @@ var (
 	errMissingCapture       = errors.New("at least one named capture must be supplied")
 	errFoundAllLabels       = errors.New("found all required labels")
 	errLabelDoesNotMatch    = errors.New("found a label with a matcher that didn't match")
+	errMissingXMLRoot       = errors.New("expecting an xml document, but found no root element")
+	errUnterminatedQuote    = errors.New("unterminated quoted field")
+	errQuotedFieldEnd       = errors.New("unexpected character after quoted field")
 
 	// the rune error replacement is rejected by Prometheus hence replacing them with space.
 	removeInvalidUtf = func(r rune) rune {
@@ -721,6 +731,565 @@ func isValidJSONStart(data []byte) bool {
 
 func (j *JSONExpressionParser) RequiredLabelNames() []string { return []string{} }
 
+// XMLParser is a log stage that extracts the elements and attributes of an
+// XML document as labels.
+//
+// Label names are built from the path of element names starting at the root
+// element, joined by underscores. Attributes are appended to the path of their
+// element. For example, the line
+//
+//	<response code="200"><user>bob</user></response>
+//
+// results in the labels response_code="200" and response_user="bob".
+// Elements only produce a label if they contain non-whitespace character data,
+// which is trimmed. Namespaces are ignored, and if several elements or
+// attributes result in the same label name, the first one wins.
+type XMLParser struct {
+	reader bytes.Reader
+	keys   internedStringSet
+
+	prefix     []byte   // Sanitized label name prefix of the current element.
+	prefixLens []int    // Length of prefix before each element on the current path.
+	texts      [][]byte // Character data of each element on the current path.
+}
+
+// NewXMLParser creates a log stage that can parse an XML log line and add its
+// elements and attributes as labels.
+func NewXMLParser() *XMLParser {
+	return &XMLParser{
+		keys: internedStringSet{},
+	}
+}
+
+func (x *XMLParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	parserHints := lbs.ParserLabelHints()
+	if parserHints.NoLabels() {
+		return line, true
+	}
+
+	x.prefix = x.prefix[:0]
+	x.prefixLens = x.prefixLens[:0]
+	x.reader.Reset(line)
+	dec := xml.NewDecoder(&x.reader)
+
+	var sawRoot bool
+	for !parserHints.AllRequiredExtracted() {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			addErrLabel(errXML, err, lbs)
+			return line, true
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			sawRoot = true
+			x.pushElement(tok.Name.Local)
+			if !parserHints.ShouldExtractPrefix(string(x.prefix)) {
+				if err := dec.Skip(); err != nil {
+					addErrLabel(errXML, err, lbs)
+					return line, true
+				}
+				x.popElement()
+				continue
+			}
+
+			for _, attr := range tok.Attr {
+				if isXMLNamespaceAttr(attr) {
+					continue
+				}
+				prefixLen := len(x.prefix)
+				x.prefix = append(x.prefix, jsonSpacer)
+				x.prefix = appendSanitized(x.prefix, unsafeGetBytes(attr.Name.Local))
+				ok := x.setLabel(x.prefix, attr.Value, lbs)
+				x.prefix = x.prefix[:prefixLen]
+				if !ok {
+					return line, false
+				}
+			}
+
+		case xml.CharData:
+			if depth := len(x.prefixLens); depth > 0 {
+				x.texts[depth-1] = append(x.texts[depth-1], tok...)
+			}
+
+		case xml.EndElement:
+			if text := bytes.TrimSpace(x.texts[len(x.prefixLens)-1]); len(text) > 0 {
+				if !x.setLabel(x.prefix, string(text), lbs) {
+					return line, false
+				}
+			}
+			x.popElement()
+		}
+	}
+
+	if !sawRoot {
+		addErrLabel(errXML, errMissingXMLRoot, lbs)
+	}
+	return line, true
+}
+
+func (x *XMLParser) pushElement(name string) {
+	depth := len(x.prefixLens)
+	x.prefixLens = append(x.prefixLens, len(x.prefix))
+	if depth > 0 {
+		x.prefix = append(x.prefix, jsonSpacer)
+	}
+	x.prefix = appendSanitized(x.prefix, unsafeGetBytes(name))
+
+	if depth == len(x.texts) {
+		x.texts = append(x.texts, nil)
+	}
+	x.texts[depth] = x.texts[depth][:0]
+}
+
+func (x *XMLParser) popElement() {
+	last := len(x.prefixLens) - 1
+	x.prefix = x.prefix[:x.prefixLens[last]]
+	x.prefixLens = x.prefixLens[:last]
+}
+
+// setLabel sets the label with the sanitized name key to value, unless the
+// label isn't required or was already extracted. It returns false if the line
+// doesn't match the label filters of the query and can be dropped.
+func (x *XMLParser) setLabel(key []byte, value string, lbs *LabelsBuilder) bool {
+	parserHints := lbs.ParserLabelHints()
+	name, ok := x.keys.Get(key, func() (string, bool) {
+		field := string(key)
+		if lbs.BaseHas(field) {
+			field = field + duplicateSuffix
+		}
+		if !parserHints.ShouldExtract(field) {
+			return "", false
+		}
+		return field, true
+	})
+	if !ok || parserHints.Extracted(name) {
+		return true
+	}
+
+	lbs.Set(ParsedLabel, name, value)
+	return parserHints.ShouldContinueParsingLine(name, lbs)
+}
+
+func (x *XMLParser) RequiredLabelNames() []string { return []string{} }
+
+// isXMLNamespaceAttr returns true if attr declares an XML namespace.
+func isXMLNamespaceAttr(attr xml.Attr) bool {
+	return attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns")
+}
+
+// XMLExpressionParser is a log stage that extracts labels from an XML log line
+// using path expressions. See [parseXMLPath] for the supported syntax.
+type XMLExpressionParser struct {
+	ids   []string
+	paths []xmlPath
+	keys  internedStringSet
+
+	reader    bytes.Reader
+	stack     []string // Local names of the elements on the current path.
+	done      []bool   // Whether the value of each path was found.
+	capturing []bool   // Whether the character data of each path is being captured.
+	texts     [][]byte // Captured character data of each path.
+}
+
+func NewXMLExpressionParser(expressions []LabelExtractionExpr) (*XMLExpressionParser, error) {
+	var ids []string
+	var paths []xmlPath
+	for _, exp := range expressions {
+		path, err := parseXMLPath(exp.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse expression [%s]: %w", exp.Expression, err)
+		}
+
+		if !model.LabelName(exp.Identifier).IsValid() {
+			return nil, fmt.Errorf("invalid extracted label name '%s'", exp.Identifier)
+		}
+
+		ids = append(ids, exp.Identifier)
+		paths = append(paths, path)
+	}
+
+	return &XMLExpressionParser{
+		ids:       ids,
+		paths:     paths,
+		keys:      internedStringSet{},
+		done:      make([]bool, len(paths)),
+		capturing: make([]bool, len(paths)),
+		texts:     make([][]byte, len(paths)),
+	}, nil
+}
+
+func (x *XMLExpressionParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	if len(line) == 0 || lbs.ParserLabelHints().NoLabels() {
+		return line, true
+	}
+
+	x.stack = x.stack[:0]
+	clear(x.done)
+	clear(x.capturing)
+	x.reader.Reset(line)
+	dec := xml.NewDecoder(&x.reader)
+
+	var (
+		sawRoot   bool
+		found     int
+		capturing int
+	)
+	for found < len(x.paths) {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			addErrLabel(errXML, err, lbs)
+			return line, true
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			sawRoot = true
+			x.stack = append(x.stack, tok.Name.Local)
+
+			var onPath bool
+			for i, path := range x.paths {
+				if x.done[i] || !path.hasPrefix(x.stack) {
+					continue
+				}
+				onPath = true
+				if len(x.stack) < len(path.elements) {
+					continue
+				}
+
+				if path.attr == "" {
+					x.capturing[i] = true
+					x.texts[i] = x.texts[i][:0]
+					capturing++
+				} else if value, ok := xmlAttrValue(tok.Attr, path.attr); ok {
+					x.setLabel(i, value, lbs)
+					found++
+				}
+			}
+
+			// Skip elements which aren't on the path of any expression, unless
+			// they're part of the value of an element.
+			if !onPath && capturing == 0 {
+				if err := dec.Skip(); err != nil {
+					addErrLabel(errXML, err, lbs)
+					return line, true
+				}
+				x.stack = x.stack[:len(x.stack)-1]
+			}
+
+		case xml.CharData:
+			for i := range x.paths {
+				if x.capturing[i] {
+					x.texts[i] = append(x.texts[i], tok...)
+				}
+			}
+
+		case xml.EndElement:
+			for i, path := range x.paths {
+				if x.capturing[i] && len(x.stack) == len(path.elements) {
+					x.capturing[i] = false
+					x.done[i] = true
+					capturing--
+					x.setLabel(i, string(bytes.TrimSpace(x.texts[i])), lbs)
+					found++
+				}
+			}
+			x.stack = x.stack[:len(x.stack)-1]
+		}
+	}
+
+	if !sawRoot {
+		addErrLabel(errXML, errMissingXMLRoot, lbs)
+		return line, true
+	}
+
+	// Ensure there's a label for every value
+	if found < len(x.ids) {
+		for _, id := range x.ids {
+			if _, ok := lbs.Get(id); !ok {
+				lbs.Set(ParsedLabel, id, "")
+			}
+		}
+	}
+
+	return line, true
+}
+
+// setLabel sets the label of the expression at index i to value, and marks
+// the expression as done.
+func (x *XMLExpressionParser) setLabel(i int, value string, lbs *LabelsBuilder) {
+	x.done[i] = true
+
+	identifier := x.ids[i]
+	key, _ := x.keys.Get(unsafeGetBytes(identifier), func() (string, bool) {
+		if lbs.BaseHas(identifier) {
+			identifier = identifier + duplicateSuffix
+		}
+		return identifier, true
+	})
+	lbs.Set(ParsedLabel, key, value)
+}
+
+func (x *XMLExpressionParser) RequiredLabelNames() []string { return []string{} }
+
+func xmlAttrValue(attrs []xml.Attr, name string) (string, bool) {
+	for _, attr := range attrs {
+		if attr.Name.Local == name && !isXMLNamespaceAttr(attr) {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// xmlPath is a parsed path expression of an [XMLExpressionParser].
+type xmlPath struct {
+	elements []string // Local names of the elements from the root element.
+	attr     string   // Local name of the attribute to extract, if any.
+}
+
+// parseXMLPath parses a path expression for an [XMLExpressionParser]. Path
+// expressions are a small subset of XPath: an absolute path of element names
+// starting at the root element, such as /response/status, optionally followed
+// by an attribute, such as /response/@code.
+//
+// A path to an element selects the character data of the first matching
+// element and all of its descendants, with leading and trailing whitespace
+// trimmed. A path to an attribute selects the value of the attribute of the
+// first matching element which has the attribute. Names are matched against
+// local names; namespace prefixes in the expression, such as in
+// /soap:Envelope/soap:Body, are ignored.
+func parseXMLPath(expr string) (xmlPath, error) {
+	var path xmlPath
+
+	rest, ok := strings.CutPrefix(expr, "/")
+	if !ok {
+		return path, errors.New("path must start with /")
+	}
+
+	steps := strings.Split(rest, "/")
+	for i, step := range steps {
+		attr, isAttr := strings.CutPrefix(step, "@")
+		if isAttr {
+			step = attr
+			if i == 0 {
+				return path, errors.New("attribute must follow an element")
+			} else if i != len(steps)-1 {
+				return path, errors.New("attribute must be the last step of the path")
+			}
+		}
+
+		if colon := strings.LastIndexByte(step, ':'); colon >= 0 {
+			step = step[colon+1:]
+		}
+		if !isValidXMLName(step) {
+			return path, fmt.Errorf("invalid name in step %d", i+1)
+		}
+
+		if isAttr {
+			path.attr = step
+		} else {
+			path.elements = append(path.elements, step)
+		}
+	}
+	return path, nil
+}
+
+// hasPrefix returns true if stack is a prefix of the elements of p.
+func (p xmlPath) hasPrefix(stack []string) bool {
+	if len(stack) > len(p.elements) {
+		return false
+	}
+	for i, name := range stack {
+		if p.elements[i] != name {
+			return false
+		}
+	}
+	return true
+}
+
+func isValidXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+		case i > 0 && (r == '-' || r == '.' || unicode.IsDigit(r)):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// DelimitedParser is a log stage that splits a log line into fields separated
+// by a separator, such as comma-separated or tab-separated values.
+//
+// Fields can be enclosed in double quotes to contain the separator, and a
+// double quote inside a quoted field is escaped by another double quote, as
+// described in RFC 4180. Double quotes inside an unquoted field are kept as is.
+//
+// Each field is extracted into the label of its column. Without columns, the
+// fields are extracted into the labels field_1, field_2, and so on. Empty
+// fields are not extracted.
+type DelimitedParser struct {
+	errType string
+	sep     []byte
+	columns []string
+	keys    internedStringSet
+
+	buf     []byte // Unquoted value of the current field.
+	nameBuf []byte // Name of the current field, if columns are not set.
+}
+
+// NewCSVParser creates a log stage that extracts the comma-separated fields
+// of a log line into the labels of the given columns. An empty column skips
+// its field. If columns is empty, all fields are extracted by position.
+func NewCSVParser(columns []string) (*DelimitedParser, error) {
+	return newDelimitedParser(errCSV, ",", columns)
+}
+
+// NewDelimitedParser creates a log stage that extracts the fields of a log
+// line separated by sep into the labels of the given columns. An empty column
+// skips its field. If columns is empty, all fields are extracted by position.
+func NewDelimitedParser(sep string, columns []string) (*DelimitedParser, error) {
+	return newDelimitedParser(errDelimited, sep, columns)
+}
+
+func newDelimitedParser(errType, sep string, columns []string) (*DelimitedParser, error) {
+	if sep == "" {
+		return nil, errors.New("separator must not be empty")
+	}
+	if strings.ContainsAny(sep, "\"\r\n") {
+		return nil, errors.New("separator must not contain double quotes or line breaks")
+	}
+
+	seen := make(map[string]struct{}, len(columns))
+	for _, column := range columns {
+		if column == "" {
+			continue
+		}
+		if !model.LabelName(column).IsValid() {
+			return nil, fmt.Errorf("invalid column label name '%s'", column)
+		}
+		if _, ok := seen[column]; ok {
+			return nil, fmt.Errorf("duplicate column label name '%s'", column)
+		}
+		seen[column] = struct{}{}
+	}
+
+	return &DelimitedParser{
+		errType: errType,
+		sep:     []byte(sep),
+		columns: columns,
+		keys:    internedStringSet{},
+	}, nil
+}
+
+func (d *DelimitedParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	parserHints := lbs.ParserLabelHints()
+	if parserHints.NoLabels() {
+		return line, true
+	}
+
+	rest := line
+	for i := 0; len(d.columns) == 0 || i < len(d.columns); i++ {
+		field, next, more, err := d.nextField(rest)
+		if err != nil {
+			addErrLabel(d.errType, fmt.Errorf("field %d: %w", i+1, err), lbs)
+			return line, true
+		}
+
+		column := d.column(i)
+		key, ok := d.keys.Get(column, func() (string, bool) {
+			name := string(column)
+			if name == "" {
+				return "", false
+			}
+			if lbs.BaseHas(name) {
+				name = name + duplicateSuffix
+			}
+			if !parserHints.ShouldExtract(name) {
+				return "", false
+			}
+			return name, true
+		})
+		if ok && len(field) > 0 && !parserHints.Extracted(key) {
+			if bytes.ContainsRune(field, utf8.RuneError) {
+				field = bytes.Map(removeInvalidUtf, field)
+			}
+
+			lbs.Set(ParsedLabel, key, string(field))
+			if !parserHints.ShouldContinueParsingLine(key, lbs) {
+				return line, false
+			}
+			if parserHints.AllRequiredExtracted() {
+				break
+			}
+		}
+
+		if !more {
+			break
+		}
+		rest = next
+	}
+	return line, true
+}
+
+// column returns the name of the column of the field at index i, or an empty
+// string if the field is skipped.
+func (d *DelimitedParser) column(i int) []byte {
+	if len(d.columns) > 0 {
+		return unsafeGetBytes(d.columns[i])
+	}
+	d.nameBuf = append(d.nameBuf[:0], "field_"...)
+	return strconv.AppendInt(d.nameBuf, int64(i+1), 10)
+}
+
+// nextField returns the first field of line and the remainder of the line
+// after its separator. more is false if the field is the last one of the line.
+// The returned field is only valid until the next call to nextField.
+func (d *DelimitedParser) nextField(line []byte) (field, rest []byte, more bool, err error) {
+	if len(line) == 0 || line[0] != '"' {
+		i := bytes.Index(line, d.sep)
+		if i < 0 {
+			return line, nil, false, nil
+		}
+		return line[:i], line[i+len(d.sep):], true, nil
+	}
+
+	d.buf = d.buf[:0]
+	line = line[1:]
+	for {
+		i := bytes.IndexByte(line, '"')
+		if i < 0 {
+			return nil, nil, false, errUnterminatedQuote
+		}
+		d.buf = append(d.buf, line[:i]...)
+		line = line[i+1:]
+		if len(line) == 0 || line[0] != '"' {
+			break
+		}
+		// An escaped double quote.
+		d.buf = append(d.buf, '"')
+		line = line[1:]
+	}
+
+	switch {
+	case len(line) == 0:
+		return d.buf, nil, false, nil
+	case bytes.HasPrefix(line, d.sep):
+		return d.buf, line[len(d.sep):], true, nil
+	}
+	return nil, nil, false, errQuotedFieldEnd
+}
+
+func (d *DelimitedParser) RequiredLabelNames() []string { return []string{} }
+
 type UnpackParser struct {
 	lbsBuffer []string
 
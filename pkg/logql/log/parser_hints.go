@@ -29,7 +29,7 @@ type ParserHint interface {
 	ShouldExtract(key string) bool
 
 	// Tells if there's any hint that start with the given prefix.
-	// This allows to speed up key searching in nested structured like json.
+	// This allows to speed up key searching in nested structured like json or xml.
 	ShouldExtractPrefix(prefix string) bool
 
 	// Tells if we should not extract any labels.
@@ -43,6 +43,16 @@ var (
 	}`)
 
 	logfmtLine = []byte(`ts=2021-02-02T14:35:05.983992774Z caller=spanlogger.go:79 org_id=3677 traceID=2e5c7234b8640997 Ingester.TotalReached=15 Ingester.TotalChunksMatched=0 Ingester.TotalBatches=0`)
+
+	csvLine = []byte(`2021-02-02T14:35:05Z,POST,"foo.grafana.net",/rpc/v2/stage,204,30.001`)
+
+	xmlLine = []byte(`<request method="POST" host="foo.grafana.net">
+	<cluster>us-east-west</cluster>
+	<user>foo</user>
+	<response status="204">
+		<latency_seconds>30.001</latency_seconds>
+	</response>
+</request>`)
 )
 
 func Test_ParserHints(t *testing.T) {
@@ -224,6 +234,48 @@ func Test_ParserHints(t *testing.T) {
 			[]float64{1.0},
 			[]string{"{app=\"nginx\", message_message=\"foo\"}"},
 		},
+		{
+			`sum by (host) (rate({app="nginx"} | csv columns="ts,method,host,uri,status,latency" | status = 204 | unwrap latency [1m]))`,
+			csvLine,
+			true,
+			[]float64{30.001},
+			[]string{"{host=\"foo.grafana.net\"}"},
+		},
+		{
+			`sum(rate({app="nginx"} | csv | field_5 = 500 [1m]))`,
+			csvLine,
+			false,
+			[]float64{0},
+			[]string{""},
+		},
+		{
+			`rate({app="nginx"} | xml | request_response_status = 204 [1m])`,
+			xmlLine,
+			true,
+			[]float64{1.0},
+			[]string{"{app=\"nginx\", cluster=\"us-central-west\", request_cluster=\"us-east-west\", request_host=\"foo.grafana.net\", request_method=\"POST\", request_response_latency_seconds=\"30.001\", request_response_status=\"204\", request_user=\"foo\"}"},
+		},
+		{
+			`sum by (request_host) (rate({app="nginx"} | xml | request_response_status = 204 [1m]))`,
+			xmlLine,
+			true,
+			[]float64{1.0},
+			[]string{"{request_host=\"foo.grafana.net\"}"},
+		},
+		{
+			`sum(rate({app="nginx"} | xml | request_user="foo" | unwrap request_response_latency_seconds [1m]))`,
+			xmlLine,
+			true,
+			[]float64{30.001},
+			[]string{"{}"},
+		},
+		{
+			`sum(rate({app="nginx"} | xml | nonexistant_field="foo" [1m]))`,
+			xmlLine,
+			false,
+			[]float64{0},
+			[]string{""},
+		},
 	} {
 		t.Run(tt.expr, func(t *testing.T) {
 			t.Parallel()
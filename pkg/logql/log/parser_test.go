@@ -1575,6 +1575,399 @@ func TestXExpressionParserFailures(t *testing.T) {
 	}
 }
 
+func Test_xmlParser_Parse(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  []byte
+		lbs   labels.Labels
+		want  labels.Labels
+		hints ParserHint
+	}{
+		{
+			"elements and attributes",
+			[]byte(`<response code="200"><user id="7">bob</user><latency unit="ms"> 12 </latency></response>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("response_code", "200",
+				"response_user", "bob",
+				"response_user_id", "7",
+				"response_latency", "12",
+				"response_latency_unit", "ms",
+			),
+			NoParserHints(),
+		},
+		{
+			"namespaces and sanitized names",
+			[]byte(`<?xml version="1.0"?><soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"><soap:Body><get-user.response><name>alice &amp; bob</name></get-user.response></soap:Body></soap:Envelope>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("Envelope_Body_get_user_response_name", "alice & bob"),
+			NoParserHints(),
+		},
+		{
+			"first element wins",
+			[]byte(`<items><item>a</item><item>b</item></items>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("items_item", "a"),
+			NoParserHints(),
+		},
+		{
+			"cdata and mixed content",
+			[]byte(`<log>error <b>here</b> now<msg><![CDATA[<raw>]]></msg></log>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("log", "error  now",
+				"log_b", "here",
+				"log_msg", "<raw>",
+			),
+			NoParserHints(),
+		},
+		{
+			"duplicate extraction",
+			[]byte(`<app><name>foo</name></app>`),
+			labels.FromStrings("app_name", "bar"),
+			labels.FromStrings("app_name", "bar",
+				"app_name_extracted", "foo",
+			),
+			NoParserHints(),
+		},
+		{
+			"hints",
+			[]byte(`<response code="200"><user>bob</user><request><method>GET</method></request></response>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("response_request_method", "GET"),
+			NewParserHint([]string{"response_request_method"}, []string{"response_request_method"}, false, true, "", nil),
+		},
+		{
+			"invalid xml",
+			[]byte(`<response><code>200</response>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("__error__", "XMLParserErr",
+				"__error_details__", "XML syntax error on line 1: element <code> closed by </response>",
+			),
+			NoParserHints(),
+		},
+		{
+			"not xml",
+			[]byte(`level=info msg="hello"`),
+			labels.EmptyLabels(),
+			labels.FromStrings("__error__", "XMLParserErr",
+				"__error_details__", "expecting an xml document, but found no root element",
+			),
+			NoParserHints(),
+		},
+	}
+	for _, tt := range tests {
+		x := NewXMLParser()
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBaseLabelsBuilderWithGrouping(nil, tt.hints, false, false).ForLabels(tt.lbs, labels.StableHash(tt.lbs))
+			b.Reset()
+			_, _ = x.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestXMLExpressionParser(t *testing.T) {
+	testLine := []byte(`<?xml version="1.0"?><soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"><soap:Body><response code="200"><user id="1">alice</user><user id="2">bob</user><message>hello <b>world</b></message></response></soap:Body></soap:Envelope>`)
+
+	tests := []struct {
+		name        string
+		line        []byte
+		expressions []LabelExtractionExpr
+		lbs         labels.Labels
+		want        labels.Labels
+	}{
+		{
+			"attribute",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("status", "/Envelope/Body/response/@code"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("status", "200"),
+		},
+		{
+			"elements with namespace prefixes",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("user", "/soap:Envelope/soap:Body/response/user"),
+				NewLabelExtractionExpr("user_id", "/soap:Envelope/soap:Body/response/user/@id"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("user", "alice", "user_id", "1"),
+		},
+		{
+			"element with descendants",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("message", "/Envelope/Body/response/message"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("message", "hello world"),
+		},
+		{
+			"missing values",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("status", "/Envelope/Body/response/@status"),
+				NewLabelExtractionExpr("fault", "/Envelope/Body/Fault"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("fault", "", "status", ""),
+		},
+		{
+			"duplicate extraction",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("user", "/Envelope/Body/response/user"),
+			},
+			labels.FromStrings("user", "root"),
+			labels.FromStrings("user", "root", "user_extracted", "alice"),
+		},
+		{
+			"invalid xml",
+			[]byte(`<response code="200">`),
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("user", "/response/user"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("__error__", "XMLParserErr",
+				"__error_details__", "XML syntax error on line 1: unexpected EOF",
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, err := NewXMLExpressionParser(tt.expressions)
+			require.NoError(t, err, "cannot create XML expression parser")
+			b := NewBaseLabelsBuilderWithGrouping(nil, NoParserHints(), false, false).ForLabels(tt.lbs, labels.StableHash(tt.lbs))
+			b.Reset()
+			_, _ = x.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestXMLExpressionParserFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression LabelExtractionExpr
+		error      string
+	}{
+		{
+			"relative path",
+			NewLabelExtractionExpr("app", `response/code`),
+			"path must start with /",
+		},
+		{
+			"empty step",
+			NewLabelExtractionExpr("app", `/response//code`),
+			"invalid name in step 2",
+		},
+		{
+			"attribute before element",
+			NewLabelExtractionExpr("app", `/response/@code/value`),
+			"attribute must be the last step of the path",
+		},
+		{
+			"attribute of document",
+			NewLabelExtractionExpr("app", `/@code`),
+			"attribute must follow an element",
+		},
+		{
+			"unsupported predicate",
+			NewLabelExtractionExpr("app", `/response/user[1]`),
+			"invalid name in step 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewXMLExpressionParser([]LabelExtractionExpr{tt.expression})
+
+			require.NotNil(t, err)
+			require.Equal(t, err.Error(), fmt.Sprintf("cannot parse expression [%s]: %s", tt.expression.Expression, tt.error))
+		})
+	}
+}
+
+func Test_delimitedParser_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		sep     string
+		columns []string
+		line    []byte
+		lbs     labels.Labels
+		want    labels.Labels
+		hints   ParserHint
+	}{
+		{
+			"positional fields",
+			",",
+			nil,
+			[]byte(`GET,/api/v1/push,204`),
+			labels.EmptyLabels(),
+			labels.FromStrings("field_1", "GET",
+				"field_2", "/api/v1/push",
+				"field_3", "204",
+			),
+			NoParserHints(),
+		},
+		{
+			"columns",
+			",",
+			[]string{"method", "path", "status"},
+			[]byte(`GET,/api/v1/push,204,0.31`),
+			labels.EmptyLabels(),
+			labels.FromStrings("method", "GET",
+				"path", "/api/v1/push",
+				"status", "204",
+			),
+			NoParserHints(),
+		},
+		{
+			"skipped, empty and missing fields",
+			",",
+			[]string{"method", "", "status", "duration"},
+			[]byte(`GET,/api/v1/push,`),
+			labels.EmptyLabels(),
+			labels.FromStrings("method", "GET"),
+			NoParserHints(),
+		},
+		{
+			"quoted fields",
+			",",
+			[]string{"user", "msg", "status"},
+			[]byte(`"bob","said ""hi"", then left",""`),
+			labels.EmptyLabels(),
+			labels.FromStrings("msg", `said "hi", then left`,
+				"user", "bob",
+			),
+			NoParserHints(),
+		},
+		{
+			"quotes inside unquoted field",
+			",",
+			[]string{"a", "b"},
+			[]byte(`5'11",x`),
+			labels.EmptyLabels(),
+			labels.FromStrings("a", `5'11"`,
+				"b", "x",
+			),
+			NoParserHints(),
+		},
+		{
+			"tab separator",
+			"\t",
+			[]string{"ts", "level", "msg"},
+			[]byte("2024-01-01T00:00:00Z\terror\t\"quoted\ttab\""),
+			labels.EmptyLabels(),
+			labels.FromStrings("level", "error",
+				"msg", "quoted\ttab",
+				"ts", "2024-01-01T00:00:00Z",
+			),
+			NoParserHints(),
+		},
+		{
+			"multi-character separator",
+			" | ",
+			nil,
+			[]byte(`a | "b | c" | d`),
+			labels.EmptyLabels(),
+			labels.FromStrings("field_1", "a",
+				"field_2", "b | c",
+				"field_3", "d",
+			),
+			NoParserHints(),
+		},
+		{
+			"duplicate extraction",
+			",",
+			[]string{"app", "status"},
+			[]byte(`foo,200`),
+			labels.FromStrings("app", "bar"),
+			labels.FromStrings("app", "bar",
+				"app_extracted", "foo",
+				"status", "200",
+			),
+			NoParserHints(),
+		},
+		{
+			"hints",
+			",",
+			[]string{"method", "path", "status"},
+			[]byte(`GET,/api/v1/push,204`),
+			labels.EmptyLabels(),
+			labels.FromStrings("path", "/api/v1/push"),
+			NewParserHint([]string{"path"}, []string{"path"}, false, true, "", nil),
+		},
+		{
+			"unterminated quote",
+			",",
+			nil,
+			[]byte(`a,"b`),
+			labels.EmptyLabels(),
+			labels.FromStrings("__error__", "DelimitedParserErr",
+				"__error_details__", "field 2: unterminated quoted field",
+				"field_1", "a",
+			),
+			NoParserHints(),
+		},
+		{
+			"text after quoted field",
+			",",
+			nil,
+			[]byte(`"a"b,c`),
+			labels.EmptyLabels(),
+			labels.FromStrings("__error__", "DelimitedParserErr",
+				"__error_details__", "field 1: unexpected character after quoted field",
+			),
+			NoParserHints(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewDelimitedParser(tt.sep, tt.columns)
+			require.NoError(t, err)
+			b := NewBaseLabelsBuilderWithGrouping(nil, tt.hints, false, false).ForLabels(tt.lbs, labels.StableHash(tt.lbs))
+			b.Reset()
+			_, _ = d.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestDelimitedParserFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		sep     string
+		columns []string
+		error   string
+	}{
+		{"empty separator", "", nil, "separator must not be empty"},
+		{"quote separator", `"`, nil, "separator must not contain double quotes or line breaks"},
+		{"invalid column", ",", []string{"a", "b\xffc"}, "invalid column label name 'b\xffc'"},
+		{"duplicate column", ",", []string{"a", "b", "a"}, "duplicate column label name 'a'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDelimitedParser(tt.sep, tt.columns)
+			require.EqualError(t, err, tt.error)
+		})
+	}
+}
+
+func TestCSVParser(t *testing.T) {
+	d, err := NewCSVParser(nil)
+	require.NoError(t, err)
+
+	lbs := labels.EmptyLabels()
+	b := NewBaseLabelsBuilder().ForLabels(lbs, labels.StableHash(lbs))
+	b.Reset()
+	_, _ = d.Process(0, []byte(`a,"b`), b)
+	require.Equal(t, labels.FromStrings("__error__", "CSVParserErr",
+		"__error_details__", "field 2: unterminated quoted field",
+		"field_1", "a",
+	), b.LabelsResult().Labels())
+}
+
 func Test_unpackParser_Parse(t *testing.T) {
 	tests := []struct {
 		name string
@@ -4,16 +4,8 @@ import "github.com/grafana/loki/v3/pkg/logql/syntax"
 
 // optimizeSampleExpr Attempt to optimize the SampleExpr to another that will run faster but will produce the same result.
 func optimizeSampleExpr(expr syntax.SampleExpr) (syntax.SampleExpr, error) {
-	var skip bool
 	// we skip sharding AST for now, it's not easy to clone them since they are not part of the language.
-	expr.Walk(func(e syntax.Expr) bool {
-		switch e.(type) {
-		case *ConcatSampleExpr, DownstreamSampleExpr, *QuantileSketchEvalExpr, *QuantileSketchMergeExpr, *MergeFirstOverTimeExpr, *MergeLastOverTimeExpr:
-			skip = true
-		}
-		return true
-	})
-	if skip {
+	if isShardingAST(expr) {
 		return expr, nil
 	}
 	expr, err := syntax.Clone[syntax.SampleExpr](expr)
@@ -25,6 +17,19 @@ func optimizeSampleExpr(expr syntax.SampleExpr) (syntax.SampleExpr, error) {
 	return expr, nil
 }
 
+// isShardingAST returns true if expr contains expressions of the sharding AST.
+func isShardingAST(expr syntax.SampleExpr) bool {
+	var found bool
+	expr.Walk(func(e syntax.Expr) bool {
+		switch e.(type) {
+		case *ConcatSampleExpr, DownstreamSampleExpr, *QuantileSketchEvalExpr, *QuantileSketchMergeExpr, *CountDistinctSketchEvalExpr, *CountDistinctSketchMergeExpr, *MergeFirstOverTimeExpr, *MergeLastOverTimeExpr:
+			found = true
+		}
+		return true
+	})
+	return found
+}
+
 // replaceApproxTopKWithTopk replaces all ApproxTopKExpr with TopKExpr.
 // ApproxTopKExpr is not supported by the querier, so we replace it with the implementation if this function reaches the querier.
 func replaceApproxTopK(expr syntax.SampleExpr) {
@@ -97,6 +102,14 @@ func removeLineformat(expr syntax.SampleExpr) {
 					found = true
 					break
 				}
+				if _, ok := pipelineExpr.MultiStages[j].(*syntax.XMLExpressionParserExpr); ok {
+					found = true
+					break
+				}
+				if _, ok := pipelineExpr.MultiStages[j].(*syntax.DelimitedParserExpr); ok {
+					found = true
+					break
+				}
 			}
 			if found {
 				// we cannot remove safely the linefmtExpr.
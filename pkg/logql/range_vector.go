@@ -7,11 +7,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/axiomhq/hyperloglog"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	promql_parser "github.com/prometheus/prometheus/promql/parser"
 
 	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logql/log"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/logql/vector"
 )
@@ -47,6 +49,28 @@ func newRangeVectorIterator(
 	if step == 0 {
 		step = 1
 	}
+	if expr.Left != nil && expr.Left.At != nil {
+		at := expr.Left.At
+		// the range is evaluated once at the time of the @ modifier.
+		ts := at.Time(time.Unix(0, start), time.Unix(0, end)).UnixNano()
+		inner, err := newSlidingRangeVectorIterator(it, expr, selRange, step, ts, ts, offset)
+		if err != nil {
+			return nil, err
+		}
+		return &atModifierRangeVectorIterator{
+			iter:    inner,
+			step:    step,
+			end:     end,
+			current: start - step, // first loop iteration will set it to start
+		}, nil
+	}
+	return newSlidingRangeVectorIterator(it, expr, selRange, step, start, end, offset)
+}
+
+func newSlidingRangeVectorIterator(
+	it iter.PeekingSampleIterator,
+	expr *syntax.RangeAggregationExpr,
+	selRange, step, start, end, offset int64) (RangeVectorIterator, error) {
 	if offset != 0 {
 		start = start - offset
 		end = end - offset
@@ -250,6 +274,8 @@ func aggregator(r *syntax.RangeAggregationExpr) (BatchRangeVectorAggregator, err
 		return last, nil
 	case syntax.OpRangeTypeAbsent:
 		return one, nil
+	case syntax.OpRangeTypeApproxCountDistinct:
+		return approxCountDistinctOverTime, nil
 	default:
 		return nil, fmt.Errorf(syntax.UnsupportedErr, r.Operation)
 	}
@@ -504,6 +530,60 @@ func one(_ []promql.FPoint) float64 {
 	return 1.0
 }
 
+// approxCountDistinctOverTime estimates the number of distinct label values
+// unwrapped with unwrap_label.
+func approxCountDistinctOverTime(samples []promql.FPoint) float64 {
+	return float64(newCountDistinctSketch(samples).Estimate())
+}
+
+// atModifierRangeVectorIterator returns the same samples at every step of the
+// query: those of a range with an @ modifier, which is evaluated only once.
+type atModifierRangeVectorIterator struct {
+	iter               RangeVectorIterator
+	step, end, current int64
+	loaded             bool
+	samples, at        []promql.Sample
+}
+
+func (r *atModifierRangeVectorIterator) Next() bool {
+	r.current = r.current + r.step
+	if r.current > r.end {
+		return false
+	}
+	if !r.loaded {
+		r.loaded = true
+		if !r.iter.Next() {
+			return r.iter.Error() == nil
+		}
+		// copy the samples as iterators reuse their buffers.
+		_, vec := r.iter.At()
+		r.samples = append(r.samples, vec.SampleVector()...)
+	}
+	return true
+}
+
+func (r *atModifierRangeVectorIterator) At() (int64, StepResult) {
+	if r.at == nil {
+		r.at = make([]promql.Sample, 0, len(r.samples))
+	}
+	r.at = r.at[:0]
+	// convert ts from nano to milli seconds as the iterator work with nanoseconds
+	ts := r.current / 1e+6
+	for _, s := range r.samples {
+		s.T = ts
+		r.at = append(r.at, s)
+	}
+	return ts, SampleVector(r.at)
+}
+
+func (r *atModifierRangeVectorIterator) Close() error {
+	return r.iter.Close()
+}
+
+func (r *atModifierRangeVectorIterator) Error() error {
+	return r.iter.Error()
+}
+
 // streaming range agg
 type streamRangeVectorIterator struct {
 	iter                                 iter.PeekingSampleIterator
@@ -627,6 +707,8 @@ func streamingAggregator(r *syntax.RangeAggregationExpr) (RangeStreamingAgg, err
 		return &LastOverTime{}, nil
 	case syntax.OpRangeTypeAbsent:
 		return &OneOverTime{}, nil
+	case syntax.OpRangeTypeApproxCountDistinct:
+		return &ApproxCountDistinctOverTime{sketch: hyperloglog.New()}, nil
 	default:
 		return nil, fmt.Errorf(syntax.UnsupportedErr, r.Operation)
 	}
@@ -851,3 +933,15 @@ func (a *OneOverTime) agg(_ promql.FPoint) {
 func (a *OneOverTime) at() float64 {
 	return 1.0
 }
+
+type ApproxCountDistinctOverTime struct {
+	sketch *hyperloglog.Sketch
+}
+
+func (a *ApproxCountDistinctOverTime) agg(sample promql.FPoint) {
+	a.sketch.InsertHash(log.LabelHashFromValue(sample.F))
+}
+
+func (a *ApproxCountDistinctOverTime) at() float64 {
+	return float64(a.sketch.Estimate())
+}
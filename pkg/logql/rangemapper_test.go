@@ -1878,6 +1878,10 @@ func Test_SplitRangeVectorMapping_Noop(t *testing.T) {
 			`bytes_rate({app="foo"} | logfmt [3m])`,
 			`bytes_rate({app="foo"} | logfmt [3m])`,
 		},
+		{
+			`rate({app="foo"} | xml [3m])`,
+			`rate({app="foo"} | xml [3m])`,
+		},
 		// should be noop if inner range aggregation includes a stage for label extraction
 		// and the vector aggregator is count
 		{
@@ -1985,6 +1989,16 @@ func Test_SplitRangeVectorMapping_Noop(t *testing.T) {
 			`(sum(last_over_time({app="foo"} | logfmt | unwrap total_count [1d]) by (foo)) or vector(0.000000))`,
 		},
 
+		// should be noop if subquery
+		{
+			`max_over_time(sum(count_over_time({app="foo"}[3m]))[1h:1m])`,
+			`max_over_time(sum(count_over_time({app="foo"}[3m]))[1h:1m])`,
+		},
+		{
+			`sum(count_over_time({app="foo"}[3m])) / max_over_time(sum(count_over_time({app="foo"}[3m]))[1h:])`,
+			`(sum(count_over_time({app="foo"}[3m])) / max_over_time(sum(count_over_time({app="foo"}[3m]))[1h:]))`,
+		},
+
 		// should be noop if literal expression
 		{
 			`5`,
@@ -101,6 +101,8 @@ func (m ShardMapper) Map(expr syntax.Expr, r *downstreamRecorder, topLevel bool)
 		return m.mapLabelReplaceExpr(e, r, topLevel)
 	case *syntax.RangeAggregationExpr:
 		return m.mapRangeAggregationExpr(e, r, topLevel)
+	case *syntax.SubqueryExpr:
+		return m.mapSubqueryExpr(e, r)
 	case *syntax.BinOpExpr:
 		return m.mapBinOpExpr(e, r, topLevel)
 	default:
@@ -415,6 +417,19 @@ func (m ShardMapper) mapLabelReplaceExpr(expr *syntax.LabelReplaceExpr, r *downs
 	return &cpy, bytesPerShard, nil
 }
 
+// mapSubqueryExpr shards the inner expression of a subquery. The subquery
+// itself is never sharded: the same series may exist on several shards, so it
+// has to be evaluated over the merged results of its inner expression.
+func (m ShardMapper) mapSubqueryExpr(expr *syntax.SubqueryExpr, r *downstreamRecorder) (syntax.SampleExpr, uint64, error) {
+	subMapped, bytesPerShard, err := m.Map(expr.Left, r, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	cpy := *expr
+	cpy.Left = subMapped.(syntax.SampleExpr)
+	return &cpy, bytesPerShard, nil
+}
+
 // These functions require a different merge strategy than the default
 // concatenation.
 // This is because the same label sets may exist on multiple shards when label-reducing parsing is applied or when
@@ -580,6 +595,44 @@ func (m ShardMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr,
 			quantile: expr.Params,
 		}, bytesPerShard, nil
 
+	case syntax.OpRangeTypeApproxCountDistinct:
+		shards, bytesPerShard, err := m.shards.Resolver().Shards(expr)
+		if err != nil {
+			return nil, 0, err
+		}
+		if shards == 0 {
+			return noOp(expr, m.shards.Resolver())
+		}
+
+		// unwrap_label removes the counted label from the series, so the
+		// same series and label value may be seen by several shards even
+		// without grouping. Therefore the cardinalities can't be summed and
+		// HyperLogLog sketches are merged across shards instead:
+		// approx_count_distinct_over_time() by (foo) ->
+		// count_distinct_sketch_eval(count_distinct_sketch_merge by (foo)
+		// (__approx_count_distinct_sketch_over_time__() by (foo)))
+
+		downstreams := make([]DownstreamSampleExpr, 0, shards)
+		expr.Operation = syntax.OpRangeTypeApproxCountDistinctSketch
+		for shard := shards - 1; shard >= 0; shard-- {
+			s := NewPowerOfTwoShard(index.ShardAnnotation{
+				Shard: uint32(shard),
+				Of:    uint32(shards),
+			})
+			downstreams = append(downstreams, DownstreamSampleExpr{
+				shard: &ShardWithChunkRefs{
+					Shard: s,
+				},
+				SampleExpr: expr,
+			})
+		}
+
+		return &CountDistinctSketchEvalExpr{
+			mergeExpr: &CountDistinctSketchMergeExpr{
+				downstreams: downstreams,
+			},
+		}, bytesPerShard, nil
+
 	case syntax.OpRangeTypeFirst:
 		if !m.firstOverTimeSharding {
 			return noOp(expr, m.shards.Resolver())
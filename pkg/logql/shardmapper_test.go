@@ -473,6 +473,36 @@ func TestMappingStrings(t *testing.T) {
 			in:  `count by (foo) (sum by (foo, bar) (rate({job="bar"}[1m])))`,
 			out: `countby(foo)(sumby(foo,bar)(downstream<sumby(foo,bar)(rate({job="bar"}[1m])),shard=0_of_2>++downstream<sumby(foo,bar)(rate({job="bar"}[1m])),shard=1_of_2>))`,
 		},
+		{
+			// approx_count_distinct_over_time is always merged from sketches
+			in: `approx_count_distinct_over_time({a=~".+"} | logfmt | unwrap_label user [1m]) by (a)`,
+			out: `countDistinctSketchEval<countDistinctSketchMerge<
+					downstream<__approx_count_distinct_sketch_over_time__({a=~".+"}|logfmt|unwrap_labeluser[1m])by(a),shard=1_of_2>
+					++ downstream<__approx_count_distinct_sketch_over_time__({a=~".+"}|logfmt|unwrap_labeluser[1m])by(a),shard=0_of_2>>>`,
+		},
+		{
+			// approx_count_distinct_over_time is only sharded at the top level
+			in:  `max(approx_count_distinct_over_time({a=~".+"} | logfmt | unwrap_label user [1m]) by (a))`,
+			out: `max(approx_count_distinct_over_time({a=~".+"}|logfmt|unwrap_labeluser[1m])by(a))`,
+		},
+		{
+			// subqueries are evaluated over the merged shards of their inner expression
+			in:  `max_over_time(sum(rate({job="bar"}[1m]))[1h:1m])`,
+			out: `max_over_time(sum(downstream<sum(rate({job="bar"}[1m])),shard=0_of_2>++downstream<sum(rate({job="bar"}[1m])),shard=1_of_2>)[1h:1m])`,
+		},
+		{
+			in:  `sum by (foo) (max_over_time(rate({job="bar"}[1m])[30m:] offset 5m))`,
+			out: `sumby(foo)(max_over_time(downstream<rate({job="bar"}[1m]),shard=0_of_2>++downstream<rate({job="bar"}[1m]),shard=1_of_2>[30m:]offset5m0s))`,
+		},
+		{
+			in:  `sum(rate({job="bar"}[1m] @ 1609746000 offset 5m))`,
+			out: `sum(downstream<sum(rate({job="bar"}[1m]@1609746000offset5m0s)),shard=0_of_2>++downstream<sum(rate({job="bar"}[1m]@1609746000offset5m0s)),shard=1_of_2>)`,
+		},
+		{
+			// the sketches of sharded quantiles don't support the @ modifier
+			in:  `quantile_over_time(0.8, {foo="bar"} | unwrap bytes [5m] @ end()) by (cluster)`,
+			out: `quantile_over_time(0.8,{foo="bar"}|unwrapbytes[5m]@end())by(cluster)`,
+		},
 	} {
 		t.Run(tc.in, func(t *testing.T) {
 			ast, err := syntax.ParseExpr(tc.in)
@@ -48,6 +48,7 @@ type Expr interface {
 func (MatchersExpr) isExpr()               {}
 func (PipelineExpr) isExpr()               {}
 func (RangeAggregationExpr) isExpr()       {}
+func (SubqueryExpr) isExpr()               {}
 func (VectorAggregationExpr) isExpr()      {}
 func (LiteralExpr) isExpr()                {}
 func (VectorExpr) isExpr()                 {}
@@ -62,6 +63,8 @@ func (KeepLabelsExpr) isExpr()             {}
 func (LineFmtExpr) isExpr()                {}
 func (LabelFmtExpr) isExpr()               {}
 func (JSONExpressionParserExpr) isExpr()   {}
+func (XMLExpressionParserExpr) isExpr()    {}
+func (DelimitedParserExpr) isExpr()        {}
 func (LogfmtExpressionParserExpr) isExpr() {}
 func (LogRangeExpr) isExpr()               {}
 func (OffsetExpr) isExpr()                 {}
@@ -97,6 +100,7 @@ type SampleExpr interface {
 }
 
 func (RangeAggregationExpr) isSampleExpr()  {}
+func (SubqueryExpr) isSampleExpr()          {}
 func (VectorAggregationExpr) isSampleExpr() {}
 func (LiteralExpr) isSampleExpr()           {}
 func (VectorExpr) isSampleExpr()            {}
@@ -122,6 +126,8 @@ func (KeepLabelsExpr) isStageExpr()             {}
 func (LineFmtExpr) isStageExpr()                {}
 func (LabelFmtExpr) isStageExpr()               {}
 func (JSONExpressionParserExpr) isStageExpr()   {}
+func (XMLExpressionParserExpr) isStageExpr()    {}
+func (DelimitedParserExpr) isStageExpr()        {}
 func (LogfmtExpressionParserExpr) isStageExpr() {}
 
 func Clone[T Expr](e T) (T, error) {
@@ -186,6 +192,8 @@ func ExtractLabelFiltersBeforeParser(e Expr) []*LabelFilterExpr {
 		VisitLogfmtParserFn:           func(_ RootVisitor, _ *LogfmtParserExpr) { foundParseStage = true },
 		VisitLabelParserFn:            func(_ RootVisitor, _ *LineParserExpr) { foundParseStage = true },
 		VisitJSONExpressionParserFn:   func(_ RootVisitor, _ *JSONExpressionParserExpr) { foundParseStage = true },
+		VisitXMLExpressionParserFn:    func(_ RootVisitor, _ *XMLExpressionParserExpr) { foundParseStage = true },
+		VisitDelimitedParserFn:        func(_ RootVisitor, _ *DelimitedParserExpr) { foundParseStage = true },
 		VisitLogfmtExpressionParserFn: func(_ RootVisitor, _ *LogfmtExpressionParserExpr) { foundParseStage = true },
 		VisitLabelFmtFn:               func(_ RootVisitor, _ *LabelFmtExpr) { foundParseStage = true },
 		VisitKeepLabelFn:              func(_ RootVisitor, _ *KeepLabelsExpr) { foundParseStage = true },
@@ -762,6 +770,8 @@ func (e *LineParserExpr) Stage() (log.Stage, error) {
 		return log.NewUnpackParser(), nil
 	case OpParserTypePattern:
 		return log.NewPatternParser(e.Param)
+	case OpParserTypeXML:
+		return log.NewXMLParser(), nil
 	default:
 		return nil, fmt.Errorf("unknown parser operator: %s", e.Op)
 	}
@@ -878,6 +888,10 @@ func (e *DropLabelsExpr) String() string {
 	str := sb.String()
 	return str
 }
+
+// Labels returns the labels and label matchers of the drop stage.
+func (e *DropLabelsExpr) Labels() []log.NamedLabelMatcher { return e.dropLabels }
+
 func (e *DropLabelsExpr) Walk(f WalkFn) { f(e) }
 
 func (e *DropLabelsExpr) Accept(v RootVisitor) { v.VisitDropLabels(e) }
@@ -919,6 +933,9 @@ func (e *KeepLabelsExpr) String() string {
 	return str
 }
 
+// Labels returns the labels and label matchers of the keep stage.
+func (e *KeepLabelsExpr) Labels() []log.NamedLabelMatcher { return e.keepLabels }
+
 func (e *KeepLabelsExpr) Walk(f WalkFn) { f(e) }
 
 func (e *KeepLabelsExpr) Accept(v RootVisitor) { v.VisitKeepLabel(e) }
@@ -1016,6 +1033,117 @@ func (j *JSONExpressionParserExpr) String() string {
 	return sb.String()
 }
 
+type XMLExpressionParserExpr struct {
+	Expressions []log.LabelExtractionExpr
+}
+
+func newXMLExpressionParser(expressions []log.LabelExtractionExpr) *XMLExpressionParserExpr {
+	if _, err := log.NewXMLExpressionParser(expressions); err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid xml parser: %s", err.Error()), 0, 0))
+	}
+	return &XMLExpressionParserExpr{
+		Expressions: expressions,
+	}
+}
+
+func (x *XMLExpressionParserExpr) Shardable(_ bool) bool { return true }
+
+func (x *XMLExpressionParserExpr) Walk(f WalkFn) { f(x) }
+
+func (x *XMLExpressionParserExpr) Accept(v RootVisitor) { v.VisitXMLExpressionParser(x) }
+
+func (x *XMLExpressionParserExpr) Stage() (log.Stage, error) {
+	return log.NewXMLExpressionParser(x.Expressions)
+}
+
+func (x *XMLExpressionParserExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s ", OpPipe, OpParserTypeXML))
+	for i, exp := range x.Expressions {
+		sb.WriteString(exp.Identifier)
+		sb.WriteString("=")
+		sb.WriteString(strconv.Quote(exp.Expression))
+
+		if i+1 != len(x.Expressions) {
+			sb.WriteString(",")
+		}
+	}
+	return sb.String()
+}
+
+// DelimitedParserExpr is a `| csv` or `| delimited` parser stage.
+type DelimitedParserExpr struct {
+	Op        string
+	Separator string   // Only set for OpParserTypeDelimited.
+	Columns   []string // Empty if fields are extracted by position.
+}
+
+// newDelimitedParserExpr creates a parser stage for op from its parameters,
+// such as sep="\t" and columns="a,b,c".
+func newDelimitedParserExpr(op string, params []log.LabelExtractionExpr) *DelimitedParserExpr {
+	e := &DelimitedParserExpr{Op: op}
+	seen := make(map[string]struct{}, len(params))
+	for _, p := range params {
+		if _, ok := seen[p.Identifier]; ok {
+			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid %s parser: duplicate parameter %s", op, p.Identifier), 0, 0))
+		}
+		seen[p.Identifier] = struct{}{}
+
+		switch {
+		case p.Identifier == "columns":
+			if p.Expression != "" {
+				e.Columns = strings.Split(p.Expression, ",")
+				for i := range e.Columns {
+					e.Columns[i] = strings.TrimSpace(e.Columns[i])
+				}
+			}
+		case p.Identifier == "sep" && op == OpParserTypeDelimited:
+			e.Separator = p.Expression
+		default:
+			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid %s parser: unknown parameter %s", op, p.Identifier), 0, 0))
+		}
+	}
+	if _, ok := seen["sep"]; op == OpParserTypeDelimited && !ok {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid %s parser: sep parameter is required", op), 0, 0))
+	}
+
+	if _, err := e.Stage(); err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid %s parser: %s", op, err.Error()), 0, 0))
+	}
+	return e
+}
+
+func (e *DelimitedParserExpr) Shardable(_ bool) bool { return true }
+
+func (e *DelimitedParserExpr) Walk(f WalkFn) { f(e) }
+
+func (e *DelimitedParserExpr) Accept(v RootVisitor) { v.VisitDelimitedParser(e) }
+
+func (e *DelimitedParserExpr) Stage() (log.Stage, error) {
+	switch e.Op {
+	case OpParserTypeCSV:
+		return log.NewCSVParser(e.Columns)
+	case OpParserTypeDelimited:
+		return log.NewDelimitedParser(e.Separator, e.Columns)
+	default:
+		return nil, fmt.Errorf("unknown parser operator: %s", e.Op)
+	}
+}
+
+func (e *DelimitedParserExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s", OpPipe, e.Op))
+	if e.Op == OpParserTypeDelimited {
+		sb.WriteString(" sep=")
+		sb.WriteString(strconv.Quote(e.Separator))
+	}
+	if len(e.Columns) > 0 {
+		sb.WriteString(" columns=")
+		sb.WriteString(strconv.Quote(strings.Join(e.Columns, ",")))
+	}
+	return sb.String()
+}
+
 type internedStringSet map[string]struct {
 	s  string
 	ok bool
@@ -1112,17 +1240,22 @@ func mustNewFloat(s string) float64 {
 
 type UnwrapExpr struct {
 	Identifier string
-	Operation  string
+	// Operation is the conversion applied to the label value. OpUnwrapLabel
+	// unwraps the label value itself, for counting distinct label values.
+	Operation string
 
 	PostFilters []log.LabelFilterer
 }
 
 func (u UnwrapExpr) String() string {
 	var sb strings.Builder
-	if u.Operation != "" {
-		sb.WriteString(fmt.Sprintf(" %s %s %s(%s)", OpPipe, OpUnwrap, u.Operation, u.Identifier))
-	} else {
+	switch u.Operation {
+	case OpUnwrapLabel:
+		sb.WriteString(fmt.Sprintf(" %s %s %s", OpPipe, OpUnwrapLabel, u.Identifier))
+	case "":
 		sb.WriteString(fmt.Sprintf(" %s %s %s", OpPipe, OpUnwrap, u.Identifier))
+	default:
+		sb.WriteString(fmt.Sprintf(" %s %s %s(%s)", OpPipe, OpUnwrap, u.Operation, u.Identifier))
 	}
 	for _, f := range u.PostFilters {
 		sb.WriteString(fmt.Sprintf(" %s %s", OpPipe, f))
@@ -1143,6 +1276,7 @@ type LogRangeExpr struct {
 	Left     LogSelectorExpr
 	Interval time.Duration
 	Offset   time.Duration
+	At       *AtModifier
 	Unwrap   *UnwrapExpr
 }
 
@@ -1154,8 +1288,8 @@ func (r LogRangeExpr) String() string {
 		sb.WriteString(r.Unwrap.String())
 	}
 	sb.WriteString(fmt.Sprintf("[%v]", model.Duration(r.Interval)))
-	if r.Offset != 0 {
-		offsetExpr := OffsetExpr{Offset: r.Offset}
+	if r.Offset != 0 || r.At != nil {
+		offsetExpr := OffsetExpr{Offset: r.Offset, At: r.At}
 		sb.WriteString(offsetExpr.String())
 	}
 	return sb.String()
@@ -1186,36 +1320,142 @@ func (r *LogRangeExpr) WithoutUnwrap() (*LogRangeExpr, error) {
 		Left:     left,
 		Interval: r.Interval,
 		Offset:   r.Offset,
+		At:       r.At.clone(),
 	}, nil
 }
 
 func newLogRange(left LogSelectorExpr, interval time.Duration, u *UnwrapExpr, o *OffsetExpr) *LogRangeExpr {
 	var offset time.Duration
+	var at *AtModifier
 	if o != nil {
 		offset = o.Offset
+		at = o.At
 	}
 	return &LogRangeExpr{
 		Left:     left,
 		Interval: interval,
 		Unwrap:   u,
 		Offset:   offset,
+		At:       at,
 	}
 }
 
+// OffsetExpr holds the modifiers of a range: its offset and its optional @
+// modifier.
 type OffsetExpr struct {
 	Offset time.Duration
+	At     *AtModifier
 }
 
 func (o *OffsetExpr) String() string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf(" %s %s", OpOffset, o.Offset.String()))
+	if o.At != nil {
+		sb.WriteString(o.At.String())
+	}
+	if o.Offset != 0 {
+		sb.WriteString(fmt.Sprintf(" %s %s", OpOffset, o.Offset.String()))
+	}
 	return sb.String()
 }
 
-func newOffsetExpr(offset time.Duration) *OffsetExpr {
+func newOffsetExpr(offset time.Duration, at *AtModifier) *OffsetExpr {
 	return &OffsetExpr{
 		Offset: offset,
+		At:     at,
+	}
+}
+
+// AtModifier is the @ modifier of a range, e.g. [5m] @ 1609746000 or
+// [5m] @ end(). The range is then always evaluated at the same time instead of
+// at the time of each step of the query.
+type AtModifier struct {
+	// Timestamp is the evaluation time in milliseconds.
+	Timestamp int64
+	// StartOrEnd is OpAtStart or OpAtEnd for @ start() and @ end() until they
+	// are resolved against the time range of the query, see
+	// ResolveAtModifiers.
+	StartOrEnd string
+}
+
+func mustNewAtModifier(ts string) *AtModifier {
+	f, err := strconv.ParseFloat(ts, 64)
+	if err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid timestamp for %s modifier: %s", OpAt, err), 0, 0))
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) || f >= float64(math.MaxInt64)/1000 || f <= float64(math.MinInt64)/1000 {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("timestamp out of bounds for %s modifier: %s", OpAt, ts), 0, 0))
+	}
+	return &AtModifier{Timestamp: int64(math.Round(f * 1000))}
+}
+
+// Time returns the time at which the range is evaluated for a query from start
+// to end.
+func (a *AtModifier) Time(start, end time.Time) time.Time {
+	switch a.StartOrEnd {
+	case OpAtStart:
+		return start
+	case OpAtEnd:
+		return end
+	}
+	return time.UnixMilli(a.Timestamp)
+}
+
+func (a *AtModifier) String() string {
+	if a.StartOrEnd != "" {
+		return fmt.Sprintf(" %s %s()", OpAt, a.StartOrEnd)
 	}
+	return fmt.Sprintf(" %s %s", OpAt, strconv.FormatFloat(float64(a.Timestamp)/1000, 'f', -1, 64))
+}
+
+func (a *AtModifier) clone() *AtModifier {
+	if a == nil {
+		return nil
+	}
+	copied := *a
+	return &copied
+}
+
+// ResolveAtModifiers returns expr with @ start() and @ end() replaced by the
+// timestamps of start and end. expr is copied before it's modified, and
+// returned as is if it doesn't use them.
+func ResolveAtModifiers[T Expr](expr T, start, end time.Time) (T, error) {
+	if !hasAtStartOrEnd(expr) {
+		return expr, nil
+	}
+	expr, err := Clone(expr)
+	if err != nil {
+		return expr, err
+	}
+	resolve := func(at *AtModifier) {
+		if at != nil && at.StartOrEnd != "" {
+			at.Timestamp = at.Time(start, end).UnixMilli()
+			at.StartOrEnd = ""
+		}
+	}
+	expr.Walk(func(e Expr) bool {
+		switch e := e.(type) {
+		case *LogRangeExpr:
+			resolve(e.At)
+		case *SubqueryExpr:
+			resolve(e.At)
+		}
+		return true
+	})
+	return expr, nil
+}
+
+func hasAtStartOrEnd(expr Expr) bool {
+	var found bool
+	expr.Walk(func(e Expr) bool {
+		switch e := e.(type) {
+		case *LogRangeExpr:
+			found = found || (e.At != nil && e.At.StartOrEnd != "")
+		case *SubqueryExpr:
+			found = found || (e.At != nil && e.At.StartOrEnd != "")
+		}
+		return !found
+	})
+	return found
 }
 
 const (
@@ -1249,6 +1489,8 @@ const (
 	OpRangeTypeLast        = "last_over_time"
 	OpRangeTypeAbsent      = "absent_over_time"
 
+	OpRangeTypeApproxCountDistinct = "approx_count_distinct_over_time"
+
 	// vector
 	OpTypeVector = "vector"
 
@@ -1274,19 +1516,26 @@ const (
 	OpTypeLTE   = "<="
 
 	// parsers
-	OpParserTypeJSON    = "json"
-	OpParserTypeLogfmt  = "logfmt"
-	OpParserTypeRegexp  = "regexp"
-	OpParserTypeUnpack  = "unpack"
-	OpParserTypePattern = "pattern"
+	OpParserTypeJSON      = "json"
+	OpParserTypeLogfmt    = "logfmt"
+	OpParserTypeRegexp    = "regexp"
+	OpParserTypeUnpack    = "unpack"
+	OpParserTypePattern   = "pattern"
+	OpParserTypeXML       = "xml"
+	OpParserTypeCSV       = "csv"
+	OpParserTypeDelimited = "delimited"
 
 	OpFmtLine    = "line_format"
 	OpFmtLabel   = "label_format"
 	OpDecolorize = "decolorize"
 
-	OpPipe   = "|"
-	OpUnwrap = "unwrap"
-	OpOffset = "offset"
+	OpPipe        = "|"
+	OpUnwrap      = "unwrap"
+	OpUnwrapLabel = "unwrap_label"
+	OpOffset      = "offset"
+	OpAt          = "@"
+	OpAtStart     = "start"
+	OpAtEnd       = "end"
 
 	OpOn       = "on"
 	OpIgnoring = "ignoring"
@@ -1321,6 +1570,8 @@ const (
 	OpRangeTypeFirstWithTimestamp = "__first_over_time_ts__"
 	OpRangeTypeLastWithTimestamp  = "__last_over_time_ts__"
 
+	OpRangeTypeApproxCountDistinctSketch = "__approx_count_distinct_sketch_over_time__"
+
 	OpTypeCountMinSketch = "__count_min_sketch__"
 
 	// probabilistic aggregations
@@ -1409,6 +1660,7 @@ func (e *RangeAggregationExpr) MatcherGroups() ([]MatcherRange, error) {
 				Matchers: xs,
 				Interval: e.Left.Interval,
 				Offset:   e.Left.Offset,
+				At:       e.Left.At,
 			},
 		}, nil
 	}
@@ -1420,11 +1672,21 @@ func (e RangeAggregationExpr) validate() error {
 		switch e.Operation {
 		case OpRangeTypeAvg, OpRangeTypeStddev, OpRangeTypeStdvar, OpRangeTypeQuantile,
 			OpRangeTypeQuantileSketch, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeFirst,
-			OpRangeTypeLast, OpRangeTypeFirstWithTimestamp, OpRangeTypeLastWithTimestamp:
+			OpRangeTypeLast, OpRangeTypeFirstWithTimestamp, OpRangeTypeLastWithTimestamp,
+			OpRangeTypeApproxCountDistinct, OpRangeTypeApproxCountDistinctSketch:
 		default:
 			return fmt.Errorf("grouping not allowed for %s aggregation", e.Operation)
 		}
 	}
+	if e.isApproxCountDistinct() {
+		if e.Left.Unwrap == nil || e.Left.Unwrap.Operation != OpUnwrapLabel {
+			return fmt.Errorf("invalid aggregation %s without %s", e.Operation, OpUnwrapLabel)
+		}
+		return nil
+	}
+	if e.Left.Unwrap != nil && e.Left.Unwrap.Operation == OpUnwrapLabel {
+		return fmt.Errorf("invalid aggregation %s with %s", e.Operation, OpUnwrapLabel)
+	}
 	if e.Left.Unwrap != nil {
 		switch e.Operation {
 		case OpRangeTypeAvg, OpRangeTypeSum, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeStddev,
@@ -1448,6 +1710,12 @@ func (e RangeAggregationExpr) Validate() error {
 	return e.validate()
 }
 
+// isApproxCountDistinct returns true if the aggregation counts the distinct
+// values of a label, which is only allowed with unwrap_label.
+func (e RangeAggregationExpr) isApproxCountDistinct() bool {
+	return e.Operation == OpRangeTypeApproxCountDistinct || e.Operation == OpRangeTypeApproxCountDistinctSketch
+}
+
 // impls Stringer
 func (e *RangeAggregationExpr) String() string {
 	var sb strings.Builder
@@ -1467,13 +1735,22 @@ func (e *RangeAggregationExpr) String() string {
 
 // impl SampleExpr
 func (e *RangeAggregationExpr) Shardable(topLevel bool) bool {
-	// Here we are blocking sharding of quantile operations if they are not
-	// the top level aggregation in a query, such as max(quantile_over_time(...)).
+	// Here we are blocking sharding of quantile and approximate count distinct
+	// operations if they are not the top level aggregation in a query, such as
+	// max(quantile_over_time(...)), as their sketches can't be merged afterwards.
 	// The sharding here will be blocked even if the feature flag in the shardmapper
 	// to enable sharding of quantile queries is enabled.
-	if e.Operation == OpRangeTypeQuantile && !topLevel {
+	if (e.Operation == OpRangeTypeQuantile || e.Operation == OpRangeTypeApproxCountDistinct) && !topLevel {
 		return false
 	}
+	// The sharded variants of these operations are evaluated by iterators
+	// which don't support the @ modifier.
+	if e.Left.At != nil {
+		switch e.Operation {
+		case OpRangeTypeQuantile, OpRangeTypeApproxCountDistinct, OpRangeTypeFirst, OpRangeTypeLast:
+			return false
+		}
+	}
 	return shardableOps[e.Operation] && e.Left.Shardable(topLevel)
 }
 
@@ -1488,6 +1765,162 @@ func (e *RangeAggregationExpr) Walk(f WalkFn) {
 
 func (e *RangeAggregationExpr) Accept(v RootVisitor) { v.VisitRangeAggregation(e) }
 
+// subqueryRange is the range and the optional resolution of a subquery,
+// e.g. [1h:1m] or [1h:].
+type subqueryRange struct {
+	Range time.Duration
+	Step  time.Duration
+}
+
+// SubqueryExpr is a range aggregation over a subquery, such as
+// max_over_time(sum(rate({app="foo"}[1m]))[1h:1m]). The inner sample
+// expression is evaluated at each step of the subquery within its range and
+// the resulting samples are aggregated per series, like unwrapped samples.
+type SubqueryExpr struct {
+	Left      SampleExpr
+	Operation string
+	Params    *float64
+
+	// Range is the range of the subquery and Step its resolution. A zero Step
+	// uses the default resolution of the evaluator.
+	Range  time.Duration
+	Step   time.Duration
+	Offset time.Duration
+	At     *AtModifier
+
+	err error
+}
+
+func newSubqueryExpr(left SampleExpr, operation string, rng subqueryRange, o *OffsetExpr, stringParams *string) SampleExpr {
+	var params *float64
+	if stringParams != nil {
+		if operation != OpRangeTypeQuantile {
+			return &SubqueryExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter %s not supported for operation %s", *stringParams, operation), 0, 0)}
+		}
+		var err error
+		params = new(float64)
+		*params, err = strconv.ParseFloat(*stringParams, 64)
+		if err != nil {
+			return &SubqueryExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid parameter for operation %s: %s", operation, err), 0, 0)}
+		}
+	} else if operation == OpRangeTypeQuantile {
+		return &SubqueryExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
+	}
+
+	e := &SubqueryExpr{
+		Left:      left,
+		Operation: operation,
+		Params:    params,
+		Range:     rng.Range,
+		Step:      rng.Step,
+	}
+	if o != nil {
+		e.Offset = o.Offset
+		e.At = o.At
+	}
+	if err := e.validate(); err != nil {
+		return &SubqueryExpr{err: logqlmodel.NewParseError(err.Error(), 0, 0)}
+	}
+	return e
+}
+
+func (e *SubqueryExpr) validate() error {
+	if e.Range <= 0 {
+		return fmt.Errorf("subquery range must be positive")
+	}
+	switch e.Operation {
+	case OpRangeTypeCount, OpRangeTypeSum, OpRangeTypeAvg, OpRangeTypeMax, OpRangeTypeMin,
+		OpRangeTypeFirst, OpRangeTypeLast, OpRangeTypeStddev, OpRangeTypeStdvar, OpRangeTypeQuantile:
+		return nil
+	default:
+		return fmt.Errorf("invalid aggregation %s over subquery", e.Operation)
+	}
+}
+
+func (e *SubqueryExpr) Selector() (LogSelectorExpr, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Selector()
+}
+
+func (e *SubqueryExpr) Extractors() ([]SampleExtractor, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Extractors()
+}
+
+// MatcherGroups returns the matcher groups of the inner expression, with the
+// range, offset and @ modifier of the subquery added, since the inner
+// expression is evaluated over the whole range of the subquery. Groups with
+// their own @ modifier are evaluated at a fixed time and are left as is.
+func (e *SubqueryExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	groups, err := e.Left.MatcherGroups()
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		if groups[i].At != nil {
+			continue
+		}
+		groups[i].Interval += e.Range
+		groups[i].Offset += e.Offset
+		groups[i].At = e.At
+	}
+	return groups, nil
+}
+
+// rangeString returns the range, resolution and modifiers of the subquery,
+// e.g. [1h:1m] offset 5m.
+func (e *SubqueryExpr) rangeString() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("[%v:", model.Duration(e.Range)))
+	if e.Step != 0 {
+		sb.WriteString(model.Duration(e.Step).String())
+	}
+	sb.WriteString("]")
+	if e.Offset != 0 || e.At != nil {
+		offsetExpr := OffsetExpr{Offset: e.Offset, At: e.At}
+		sb.WriteString(offsetExpr.String())
+	}
+	return sb.String()
+}
+
+// impls Stringer
+func (e *SubqueryExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Operation)
+	sb.WriteString("(")
+	if e.Params != nil {
+		sb.WriteString(strconv.FormatFloat(*e.Params, 'f', -1, 64))
+		sb.WriteString(",")
+	}
+	sb.WriteString(e.Left.String())
+	sb.WriteString(e.rangeString())
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// Shardable returns false: the inner expression may aggregate series across
+// shards, so the subquery is evaluated after merging the shards of its inner
+// expression.
+func (e *SubqueryExpr) Shardable(_ bool) bool { return false }
+
+func (e *SubqueryExpr) Walk(f WalkFn) {
+	if !f(e) {
+		return
+	}
+	if e.Left != nil {
+		e.Left.Walk(f)
+	}
+}
+
+func (e *SubqueryExpr) Accept(v RootVisitor) { v.VisitSubquery(e) }
+
 // Grouping struct represents the grouping by/without label(s) for vector aggregators and range vector aggregators.
 // The representation is as follows:
 //   - No Grouping (labels dismissed): <operation> (<expr>) => Grouping{Without: false, Groups: nil}
@@ -2313,6 +2746,8 @@ var shardableOps = map[string]bool{
 	OpRangeTypeMin:       true,
 	OpRangeTypeQuantile:  true,
 
+	OpRangeTypeApproxCountDistinct: true,
+
 	// binops - arith
 	OpTypeAdd: true,
 	OpTypeMul: true,
@@ -2321,6 +2756,18 @@ var shardableOps = map[string]bool{
 type MatcherRange struct {
 	Matchers         []*labels.Matcher
 	Interval, Offset time.Duration
+	At               *AtModifier
+}
+
+// EvaluationRange returns the times at which the range of the matchers is
+// evaluated for a query from start to end. With an @ modifier, it is a single
+// point in time.
+func (m MatcherRange) EvaluationRange(start, end model.Time) (model.Time, model.Time) {
+	if m.At == nil {
+		return start, end
+	}
+	t := model.TimeFromUnixNano(m.At.Time(start.Time(), end.Time()).UnixNano())
+	return t, t
 }
 
 func MatcherGroups(expr Expr) ([]MatcherRange, error) {
@@ -2596,6 +3043,7 @@ func (m *MultiVariantExpr) MatcherGroups() ([]MatcherRange, error) {
 				Matchers: xs,
 				Interval: m.Interval(),
 				Offset:   m.Offset(),
+				At:       m.logRange.At,
 			},
 		}, nil
 	}
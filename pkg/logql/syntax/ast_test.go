@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/stretchr/testify/assert"
@@ -142,6 +143,12 @@ func Test_SampleExpr_String(t *testing.T) {
 		`last_over_time({namespace="tns"} |= "level=error" | json |foo>=5,bar<25ms | unwrap latency | __error__!~".*" | foo >5[5m])`,
 		`first_over_time({namespace="tns"} |= "level=error" | json |foo>=5,bar<25ms | unwrap latency | __error__!~".*" | foo >5[5m])`,
 		`absent_over_time({namespace="tns"} |= "level=error" | json |foo>=5,bar<25ms | unwrap latency | __error__!~".*" | foo >5[5m])`,
+		`approx_count_distinct_over_time({namespace="tns"} | json | unwrap_label user_id | __error__="" [5m]) by (service)`,
+		`max_over_time(sum by (app) (rate({namespace="tns"} |= "level=error" [1m]))[1h:1m])`,
+		`quantile_over_time(0.99, rate({namespace="tns"}[1m])[30m:] offset 5m)`,
+		`count_over_time({namespace="tns"} |= "level=error" [5m] @ 1609746000.123 offset 5m)`,
+		`sum by (app) (rate({namespace="tns"}[1m] @ start())) / sum by (app) (rate({namespace="tns"}[1m] @ end()))`,
+		`max_over_time(rate({namespace="tns"}[1m])[1h:1m] @ end() offset 1h)`,
 		`sum by (job) (
 			sum_over_time(
 				{namespace="tns"} |= "level=error" | json | avg=5 and bar<25ms | unwrap duration(latency)  | __error__!~".*" [5m]
@@ -188,6 +195,8 @@ func Test_SampleExpr_String(t *testing.T) {
 				or on ()
 				((sum by(typename,pool,commandname,colo) (sum_over_time({_namespace_="appspace", _schema_="appspace-1h", pool=~"r1testlvs", colo=~"slc|lvs|rno", env!~"(pre-production|sandbox)"} | logfmt | status!="0" | ( ( type=~"(?i)^(Error|Exception|Fatal|ERRPAGE|ValidationError)$" or typename=~"(?i)^(Error|Exception|Fatal|ERRPAGE|ValidationError)$" ) or status=~"(?i)^(Error|Exception|Fatal|ERRPAGE|ValidationError)$" ) | commandname=~"(?i).*|UNSET" | unwrap sumcount[5m])) / 60) / 60))`,
 		`{app="foo"} | logfmt code="response.code", IPAddress="host"`,
+		`sum by (status) (count_over_time({app="soap"} | xml | xml status="/Envelope/Body/response/@code" [5m]))`,
+		`{app="foo"} | csv | csv columns="method,,status" | delimited sep="\t" | delimited sep=" | " columns="a,b"`,
 	} {
 		t.Run(tc, func(t *testing.T) {
 			expr, err := ParseExpr(tc)
@@ -262,6 +271,25 @@ func TestMatcherGroups(t *testing.T) {
 				},
 			},
 		},
+		{
+			query: `max_over_time(count_over_time({job="foo"}[5m] @ 123)[1h:1m]) / max_over_time(count_over_time({job="bar"}[5m])[1h:1m] @ end())`,
+			exp: []MatcherRange{
+				{
+					Interval: 5 * time.Minute,
+					At:       &AtModifier{Timestamp: 123000},
+					Matchers: []*labels.Matcher{
+						labels.MustNewMatcher(labels.MatchEqual, "job", "foo"),
+					},
+				},
+				{
+					Interval: time.Hour + 5*time.Minute,
+					At:       &AtModifier{StartOrEnd: OpAtEnd},
+					Matchers: []*labels.Matcher{
+						labels.MustNewMatcher(labels.MatchEqual, "job", "bar"),
+					},
+				},
+			},
+		},
 	} {
 		t.Run(fmt.Sprint(i), func(t *testing.T) {
 			expr, err := ParseExpr(tc.query)
@@ -273,6 +301,59 @@ func TestMatcherGroups(t *testing.T) {
 	}
 }
 
+func TestMatcherRange_EvaluationRange(t *testing.T) {
+	start, end := model.TimeFromUnix(100), model.TimeFromUnix(200)
+
+	from, through := MatcherRange{}.EvaluationRange(start, end)
+	require.Equal(t, start, from)
+	require.Equal(t, end, through)
+
+	from, through = MatcherRange{At: &AtModifier{Timestamp: 50000}}.EvaluationRange(start, end)
+	require.Equal(t, model.TimeFromUnix(50), from)
+	require.Equal(t, model.TimeFromUnix(50), through)
+
+	from, through = MatcherRange{At: &AtModifier{StartOrEnd: OpAtEnd}}.EvaluationRange(start, end)
+	require.Equal(t, end, from)
+	require.Equal(t, end, through)
+}
+
+func TestResolveAtModifiers(t *testing.T) {
+	start, end := time.Unix(100, 0), time.Unix(200, 0)
+
+	for _, tc := range []struct {
+		query, exp string
+	}{
+		{
+			query: `count_over_time({job="foo"}[5m])`,
+			exp:   `count_over_time({job="foo"}[5m])`,
+		},
+		{
+			query: `count_over_time({job="foo"}[5m] @ 123.5)`,
+			exp:   `count_over_time({job="foo"}[5m] @ 123.5)`,
+		},
+		{
+			query: `count_over_time({job="foo"}[5m] @ start()) / count_over_time({job="foo"}[5m] @ end() offset 1m)`,
+			exp:   `(count_over_time({job="foo"}[5m] @ 100) / count_over_time({job="foo"}[5m] @ 200 offset 1m0s))`,
+		},
+		{
+			query: `max_over_time(count_over_time({job="foo"}[5m] @ end())[1h:1m] @ start())`,
+			exp:   `max_over_time(count_over_time({job="foo"}[5m] @ 200)[1h:1m] @ 100)`,
+		},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			expr, err := ParseExpr(tc.query)
+			require.NoError(t, err)
+			query := expr.String()
+
+			resolved, err := ResolveAtModifiers(expr, start, end)
+			require.NoError(t, err)
+			require.Equal(t, tc.exp, resolved.String())
+			// the original expression is left untouched
+			require.Equal(t, query, expr.String())
+		})
+	}
+}
+
 func Test_NilFilterDoesntPanic(t *testing.T) {
 	t.Parallel()
 	for _, tc := range []string{
@@ -880,6 +961,7 @@ func Test_parserExpr_Parser(t *testing.T) {
 	}{
 		{"json", OpParserTypeJSON, "", log.NewJSONParser(false), false, false},
 		{"unpack", OpParserTypeUnpack, "", log.NewUnpackParser(), false, false},
+		{"xml", OpParserTypeXML, "", log.NewXMLParser(), false, false},
 		{"pattern", OpParserTypePattern, "<foo> bar <buzz>", mustNewPatternParser("<foo> bar <buzz>"), false, false},
 		{"pattern err", OpParserTypePattern, "bar", nil, true, true},
 		{"regexp", OpParserTypeRegexp, "(?P<foo>foo)", mustNewRegexParser("(?P<foo>foo)"), false, false},
@@ -920,6 +1002,7 @@ func Test_parserExpr_String(t *testing.T) {
 		{"valid pattern", OpParserTypePattern, "buzz", `| pattern "buzz"`},
 		{"empty pattern", OpParserTypePattern, "", `| pattern ""`},
 		{"valid json", OpParserTypeJSON, "", `| json`},
+		{"valid xml", OpParserTypeXML, "", `| xml`},
 	}
 
 	for _, tt := range tests {
@@ -82,6 +82,24 @@ func (v *cloneVisitor) VisitRangeAggregation(e *RangeAggregationExpr) {
 	v.cloned = copied
 }
 
+func (v *cloneVisitor) VisitSubquery(e *SubqueryExpr) {
+	copied := &SubqueryExpr{
+		Left:      MustClone[SampleExpr](e.Left),
+		Operation: e.Operation,
+		Range:     e.Range,
+		Step:      e.Step,
+		Offset:    e.Offset,
+		At:        e.At.clone(),
+	}
+
+	if e.Params != nil {
+		tmp := *e.Params
+		copied.Params = &tmp
+	}
+
+	v.cloned = copied
+}
+
 func (v *cloneVisitor) VisitLabelReplace(e *LabelReplaceExpr) {
 	left := MustClone[SampleExpr](e.Left)
 	v.cloned = mustNewLabelReplaceExpr(left, e.Dst, e.Replacement, e.Src, e.Regex)
@@ -100,6 +118,7 @@ func (v *cloneVisitor) VisitLogRange(e *LogRangeExpr) {
 		Left:     MustClone[LogSelectorExpr](e.Left),
 		Interval: e.Interval,
 		Offset:   e.Offset,
+		At:       e.At.clone(),
 	}
 	if e.Unwrap != nil {
 		copied.Unwrap = &UnwrapExpr{
@@ -168,6 +187,28 @@ func (v *cloneVisitor) VisitJSONExpressionParser(e *JSONExpressionParserExpr) {
 	v.cloned = copied
 }
 
+func (v *cloneVisitor) VisitXMLExpressionParser(e *XMLExpressionParserExpr) {
+	copied := &XMLExpressionParserExpr{
+		Expressions: make([]log.LabelExtractionExpr, len(e.Expressions)),
+	}
+	copy(copied.Expressions, e.Expressions)
+
+	v.cloned = copied
+}
+
+func (v *cloneVisitor) VisitDelimitedParser(e *DelimitedParserExpr) {
+	copied := &DelimitedParserExpr{
+		Op:        e.Op,
+		Separator: e.Separator,
+	}
+	if e.Columns != nil {
+		copied.Columns = make([]string, len(e.Columns))
+		copy(copied.Columns, e.Columns)
+	}
+
+	v.cloned = copied
+}
+
 func (v *cloneVisitor) VisitKeepLabel(e *KeepLabelsExpr) {
 	copied := &KeepLabelsExpr{
 		keepLabels: make([]log.NamedLabelMatcher, len(e.keepLabels)),
@@ -41,6 +41,9 @@ func TestClone(t *testing.T) {
 		"regexp": {
 			query: `{env="prod", app=~"loki.*"} |~ ".*foo.*"`,
 		},
+		"delimited parser": {
+			query: `{app="foo"} | delimited sep="\t" columns="ts,,msg" | msg="bar"`,
+		},
 		"vector matching": {
 			query: `(sum by (cluster)(rate({foo="bar"}[5m])) / ignoring (cluster)  count(rate({foo="bar"}[5m])))`,
 		},
@@ -66,6 +66,8 @@ func (r RangeAggregationExpr) extractor(override *Grouping) (log.SampleExtractor
 			convOp = log.ConvertBytes
 		case OpConvDuration, OpConvDurationSeconds:
 			convOp = log.ConvertDuration
+		case OpUnwrapLabel:
+			convOp = log.ConvertLabelHash
 		default:
 			convOp = log.ConvertFloat
 		}
@@ -171,6 +173,8 @@ func variantRangeAggExprExtractor(rangeAgg *RangeAggregationExpr) (log.SampleExt
 			convOp = log.ConvertBytes
 		case OpConvDuration, OpConvDurationSeconds:
 			convOp = log.ConvertDuration
+		case OpUnwrapLabel:
+			convOp = log.ConvertLabelHash
 		default:
 			convOp = log.ConvertFloat
 		}
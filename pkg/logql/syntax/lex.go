@@ -29,6 +29,7 @@ var tokens = map[string]int{
 	"|>":           PIPE_PATTERN,
 	OpPipe:         PIPE,
 	OpUnwrap:       UNWRAP,
+	OpUnwrapLabel:  UNWRAP_LABEL,
 	"(":            OPEN_PARENTHESIS,
 	")":            CLOSE_PARENTHESIS,
 	"by":           BY,
@@ -38,6 +39,7 @@ var tokens = map[string]int{
 	"]":            CLOSE_BRACKET,
 	OpLabelReplace: LABEL_REPLACE,
 	OpOffset:       OFFSET,
+	OpAt:           AT,
 	OpOn:           ON,
 	OpIgnoring:     IGNORING,
 	OpGroupLeft:    GROUP_LEFT,
@@ -86,6 +88,14 @@ var tokens = map[string]int{
 	VariantsOf: OF,
 }
 
+// pipeTokens are tokens which are only keywords directly after a pipe, so
+// that they remain valid label names everywhere else.
+var pipeTokens = map[string]int{
+	OpParserTypeXML:       XML,
+	OpParserTypeCSV:       CSV,
+	OpParserTypeDelimited: DELIMITED,
+}
+
 var parserFlags = map[string]struct{}{
 	OpStrict:    {},
 	OpKeepEmpty: {},
@@ -111,6 +121,8 @@ var functionTokens = map[string]int{
 	OpRangeTypeAbsent:      ABSENT_OVER_TIME,
 	OpTypeVector:           VECTOR,
 
+	OpRangeTypeApproxCountDistinct: APPROX_COUNT_DISTINCT_OVER_TIME,
+
 	// vec ops
 	OpTypeSum:      SUM,
 	OpTypeAvg:      AVG,
@@ -132,6 +144,10 @@ var functionTokens = map[string]int{
 	OpConvDuration:        DURATION_CONV,
 	OpConvDurationSeconds: DURATION_SECONDS_CONV,
 
+	// @ modifier
+	OpAtStart: START,
+	OpAtEnd:   END,
+
 	// filterOp
 	OpFilterIP: IP,
 }
@@ -140,9 +156,17 @@ type lexer struct {
 	Scanner
 	errs    []logqlmodel.ParseError
 	builder strings.Builder
+
+	// prev is the previously lexed token.
+	prev int
 }
 
 func (l *lexer) Lex(lval *syntaxSymType) int {
+	l.prev = l.lex(lval)
+	return l.prev
+}
+
+func (l *lexer) lex(lval *syntaxSymType) int {
 	r := l.Scan()
 
 	switch r {
@@ -152,7 +176,7 @@ func (l *lexer) Lex(lval *syntaxSymType) int {
 		for next := l.Peek(); !(next == '\n' || next == scanner.EOF); next = l.Next() {
 		}
 
-		return l.Lex(lval)
+		return l.lex(lval)
 
 	case scanner.EOF:
 		return 0
@@ -208,6 +232,9 @@ func (l *lexer) Lex(lval *syntaxSymType) int {
 		l.builder.Reset()
 		for r := l.Next(); r != scanner.EOF; r = l.Next() {
 			if r == ']' {
+				if rng, step, ok := strings.Cut(l.builder.String(), ":"); ok {
+					return l.lexSubqueryRange(lval, rng, step)
+				}
 				i, err := model.ParseDuration(l.builder.String())
 				if err != nil {
 					l.Error(err.Error())
@@ -249,6 +276,11 @@ func (l *lexer) Lex(lval *syntaxSymType) int {
 		return tok
 	}
 
+	// A label filter directly after a pipe compares a label, e.g. | xml="x".
+	if tok, ok := pipeTokens[tokenTextLower]; ok && l.prev == PIPE && !isComparison(l.Scanner) {
+		return tok
+	}
+
 	if tok, ok := tokens[tokenTextLower]; ok {
 		return tok
 	}
@@ -257,6 +289,26 @@ func (l *lexer) Lex(lval *syntaxSymType) int {
 	return IDENTIFIER
 }
 
+// lexSubqueryRange lexes the range and the optional resolution of a
+// subquery, e.g. [1h:1m] or [1h:].
+func (l *lexer) lexSubqueryRange(lval *syntaxSymType, rng, step string) int {
+	r, err := model.ParseDuration(rng)
+	if err != nil {
+		l.Error(err.Error())
+		return 0
+	}
+	lval.subqueryRange = subqueryRange{Range: time.Duration(r)}
+	if step != "" {
+		s, err := model.ParseDuration(step)
+		if err != nil {
+			l.Error(err.Error())
+			return 0
+		}
+		lval.subqueryRange.Step = time.Duration(s)
+	}
+	return SUBQUERY_RANGE
+}
+
 func (l *lexer) Error(msg string) {
 	l.errs = append(l.errs, logqlmodel.NewParseError(msg, l.Line, l.Column))
 }
@@ -411,6 +463,16 @@ func isFunction(sc Scanner) bool {
 	return false
 }
 
+// isComparison check if the next runes start a comparison operator.
+func isComparison(sc Scanner) bool {
+	sc = trimSpace(sc)
+	switch sc.Peek() {
+	case '=', '!', '<', '>':
+		return true
+	}
+	return false
+}
+
 func trimSpace(l Scanner) Scanner {
 	for n := l.Peek(); n != scanner.EOF; n = l.Peek() {
 		if unicode.IsSpace(n) {
@@ -1660,6 +1660,119 @@ var ParseTestCases = []struct {
 		exp: nil,
 		err: logqlmodel.NewParseError("invalid aggregation count_over_time with unwrap", 0, 0),
 	},
+	{
+		in: `approx_count_distinct_over_time({app="foo"} | json | unwrap_label user_id [5m]) by (service)`,
+		exp: newRangeAggregationExpr(
+			newLogRange(&PipelineExpr{
+				Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+				MultiStages: MultiStageExpr{
+					newLabelParserExpr(OpParserTypeJSON, ""),
+				},
+			},
+				5*time.Minute,
+				newUnwrapExpr("user_id", OpUnwrapLabel),
+				nil),
+			OpRangeTypeApproxCountDistinct, &Grouping{Groups: []string{"service"}}, nil,
+		),
+	},
+	{
+		in:  `approx_count_distinct_over_time({app="foo"} | json | unwrap user_id [5m])`,
+		exp: nil,
+		err: logqlmodel.NewParseError("invalid aggregation approx_count_distinct_over_time without unwrap_label", 0, 0),
+	},
+	{
+		in:  `approx_count_distinct_over_time({app="foo"} [5m])`,
+		exp: nil,
+		err: logqlmodel.NewParseError("invalid aggregation approx_count_distinct_over_time without unwrap_label", 0, 0),
+	},
+	{
+		in:  `sum_over_time({app="foo"} | json | unwrap_label user_id [5m])`,
+		exp: nil,
+		err: logqlmodel.NewParseError("invalid aggregation sum_over_time with unwrap_label", 0, 0),
+	},
+	{
+		in: `max_over_time(sum(rate({app="foo"}[1m]))[1h:1m])`,
+		exp: newSubqueryExpr(
+			mustNewVectorAggregationExpr(
+				newRangeAggregationExpr(
+					newLogRange(newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}), time.Minute, nil, nil),
+					OpRangeTypeRate, nil, nil,
+				),
+				OpTypeSum, nil, nil,
+			),
+			OpRangeTypeMax, subqueryRange{Range: time.Hour, Step: time.Minute}, nil, nil,
+		),
+	},
+	{
+		in: `quantile_over_time(0.99, rate({app="foo"}[1m])[30m:] offset 5m)`,
+		exp: newSubqueryExpr(
+			newRangeAggregationExpr(
+				newLogRange(newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}), time.Minute, nil, nil),
+				OpRangeTypeRate, nil, nil,
+			),
+			OpRangeTypeQuantile, subqueryRange{Range: 30 * time.Minute}, &OffsetExpr{Offset: 5 * time.Minute}, NewStringLabelFilter("0.99"),
+		),
+	},
+	{
+		in: `count_over_time({app="foo"}[5m] @ 1609746000)`,
+		exp: newRangeAggregationExpr(
+			newLogRange(newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}), 5*time.Minute, nil,
+				newOffsetExpr(0, &AtModifier{Timestamp: 1609746000000})),
+			OpRangeTypeCount, nil, nil,
+		),
+	},
+	{
+		in: `count_over_time({app="foo"} |= "bar" [5m] @ 1609746000.5 offset 5m)`,
+		exp: newRangeAggregationExpr(
+			newLogRange(
+				newPipelineExpr(
+					newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+					MultiStageExpr{newLineFilterExpr(log.LineMatchEqual, "", "bar")},
+				),
+				5*time.Minute, nil,
+				newOffsetExpr(5*time.Minute, &AtModifier{Timestamp: 1609746000500})),
+			OpRangeTypeCount, nil, nil,
+		),
+	},
+	{
+		in: `sum_over_time({app="foo"} | unwrap bar [5m] offset 5m @ start())`,
+		exp: newRangeAggregationExpr(
+			newLogRange(newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}), 5*time.Minute,
+				newUnwrapExpr("bar", ""),
+				newOffsetExpr(5*time.Minute, &AtModifier{StartOrEnd: OpAtStart})),
+			OpRangeTypeSum, nil, nil,
+		),
+	},
+	{
+		in: `max_over_time(rate({app="foo"}[1m])[1h:1m] @ end())`,
+		exp: newSubqueryExpr(
+			newRangeAggregationExpr(
+				newLogRange(newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}), time.Minute, nil, nil),
+				OpRangeTypeRate, nil, nil,
+			),
+			OpRangeTypeMax, subqueryRange{Range: time.Hour, Step: time.Minute}, newOffsetExpr(0, &AtModifier{StartOrEnd: OpAtEnd}), nil,
+		),
+	},
+	{
+		in:  `count_over_time({app="foo"}[5m] @ 1e20)`,
+		exp: nil,
+		err: logqlmodel.NewParseError("timestamp out of bounds for @ modifier: 1e20", 0, 0),
+	},
+	{
+		in:  `count_over_time({app="foo"}[5m] @ now())`,
+		exp: nil,
+		err: logqlmodel.NewParseError("syntax error: unexpected IDENTIFIER, expecting NUMBER or START or END", 1, 35),
+	},
+	{
+		in:  `rate(sum(rate({app="foo"}[1m]))[1h:1m])`,
+		exp: nil,
+		err: logqlmodel.NewParseError("invalid aggregation rate over subquery", 0, 0),
+	},
+	{
+		in:  `quantile_over_time(sum(rate({app="foo"}[1m]))[1h:1m])`,
+		exp: nil,
+		err: logqlmodel.NewParseError("parameter required for operation quantile_over_time", 0, 0),
+	},
 	{
 		in: `{app="foo"} |= "bar" | json |  status_code < 500 or status_code > 200 and size >= 2.5KiB `,
 		exp: &PipelineExpr{
@@ -1781,7 +1894,7 @@ var ParseTestCases = []struct {
 			},
 				5*time.Minute,
 				newUnwrapExpr("foo", OpConvBytes),
-				newOffsetExpr(5*time.Minute)),
+				newOffsetExpr(5*time.Minute, nil)),
 			OpRangeTypeSum, nil, nil,
 		),
 	},
@@ -1895,7 +2008,7 @@ var ParseTestCases = []struct {
 				newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				5*time.Minute,
 				newUnwrapExpr("bar", ""),
-				newOffsetExpr(5*time.Minute)),
+				newOffsetExpr(5*time.Minute, nil)),
 			OpRangeTypeMax, &Grouping{Without: true, Groups: []string{"foo", "bar"}}, nil,
 		),
 	},
@@ -1906,7 +2019,7 @@ var ParseTestCases = []struct {
 				newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				5*time.Minute,
 				newUnwrapExpr("bar", ""),
-				newOffsetExpr(-5*time.Minute)),
+				newOffsetExpr(-5*time.Minute, nil)),
 			OpRangeTypeMax, &Grouping{Without: true, Groups: []string{"foo", "bar"}}, nil,
 		),
 	},
@@ -2104,7 +2217,7 @@ var ParseTestCases = []struct {
 				},
 					5*time.Minute,
 					newUnwrapExpr("foo", ""),
-					newOffsetExpr(5*time.Minute)),
+					newOffsetExpr(5*time.Minute, nil)),
 				OpRangeTypeQuantile, &Grouping{Without: false, Groups: []string{"namespace", "instance"}}, NewStringLabelFilter("0.99998"),
 			),
 			OpTypeSum,
@@ -2917,7 +3030,7 @@ var ParseTestCases = []struct {
 	},
 	{
 		in:  `quantile_over_time(foo,{namespace="tns"} |= "level=error" | json |foo>=5,bar<25ms| unwrap latency [5m])`,
-		err: logqlmodel.NewParseError("syntax error: unexpected IDENTIFIER, expecting NUMBER or { or (", 1, 20),
+		err: logqlmodel.NewParseError("syntax error: unexpected IDENTIFIER", 1, 20),
 	},
 	{
 		in:  `vector(abc)`,
@@ -3069,6 +3182,104 @@ var ParseTestCases = []struct {
 			Operation: "count_over_time",
 		},
 	},
+	{
+		in: `{app="foo"} | xml | status="200"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newLabelParserExpr(OpParserTypeXML, ""),
+				&LabelFilterExpr{
+					LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "status", "200")),
+				},
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | xml status="/response/@code", user="/soap:Envelope/soap:Body/user"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newXMLExpressionParser([]log.LabelExtractionExpr{
+					log.NewLabelExtractionExpr("status", `/response/@code`),
+					log.NewLabelExtractionExpr("user", `/soap:Envelope/soap:Body/user`),
+				}),
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | csv | csv columns="method,path,status" | delimited sep="\t" columns="ts, ,msg"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				&DelimitedParserExpr{Op: OpParserTypeCSV},
+				&DelimitedParserExpr{Op: OpParserTypeCSV, Columns: []string{"method", "path", "status"}},
+				&DelimitedParserExpr{Op: OpParserTypeDelimited, Separator: "\t", Columns: []string{"ts", "", "msg"}},
+			},
+		},
+	},
+	{
+		in:  `{app="foo"} | delimited columns="a,b"`,
+		exp: nil,
+		err: logqlmodel.NewParseError("invalid delimited parser: sep parameter is required", 0, 0),
+	},
+	{
+		in:  `{app="foo"} | csv sep=";"`,
+		exp: nil,
+		err: logqlmodel.NewParseError("invalid csv parser: unknown parameter sep", 0, 0),
+	},
+	{
+		in:  `{app="foo"} | csv columns="a,b,a"`,
+		exp: nil,
+		err: logqlmodel.NewParseError("invalid csv parser: duplicate column label name 'a'", 0, 0),
+	},
+	{
+		in:  `{app="foo"} | xml status="response/@code"`,
+		exp: nil,
+		err: logqlmodel.NewParseError("invalid xml parser: cannot parse expression [response/@code]: path must start with /", 0, 0),
+	},
+	{
+		in:  `{xml="a"}`,
+		exp: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "xml", "a")}),
+	},
+	{
+		in: `{app="foo"} | xml="x"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				&LabelFilterExpr{
+					LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "xml", "x")),
+				},
+			},
+		},
+	},
+	{
+		in:  `{csv="a"}`,
+		exp: newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "csv", "a")}),
+	},
+	{
+		in: `sum by (csv)(count_over_time({app="foo"}[1m]))`,
+		exp: mustNewVectorAggregationExpr(&RangeAggregationExpr{
+			Left: &LogRangeExpr{
+				Left:     &MatchersExpr{Mts: []*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "foo")}},
+				Interval: time.Minute,
+			},
+			Operation: "count_over_time",
+		}, "sum", &Grouping{
+			Without: false,
+			Groups:  []string{"csv"},
+		}, nil),
+	},
+	{
+		in: `{app="foo"} | delimited="x"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				&LabelFilterExpr{
+					LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "delimited", "x")),
+				},
+			},
+		},
+	},
 	{
 		// binop always includes vector matching. Default is `without ()`,
 		// the zero value.
@@ -146,6 +146,16 @@ func (e *JSONExpressionParserExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
 }
 
+// e.g: | xml label="expression", another="expression"
+func (e *XMLExpressionParserExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | delimited sep="\t" columns="a,b,c"
+func (e *DelimitedParserExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
 // e.g: | logfmt label="expression", another="expression"
 func (e *LogfmtExpressionParserExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
@@ -155,10 +165,13 @@ func (e *LogfmtExpressionParserExpr) Pretty(level int) string {
 func (e *UnwrapExpr) Pretty(level int) string {
 	s := Indent(level)
 
-	if e.Operation != "" {
-		s += fmt.Sprintf("%s %s %s(%s)", OpPipe, OpUnwrap, e.Operation, e.Identifier)
-	} else {
+	switch e.Operation {
+	case OpUnwrapLabel:
+		s += fmt.Sprintf("%s %s %s", OpPipe, OpUnwrapLabel, e.Identifier)
+	case "":
 		s += fmt.Sprintf("%s %s %s", OpPipe, OpUnwrap, e.Identifier)
+	default:
+		s += fmt.Sprintf("%s %s %s(%s)", OpPipe, OpUnwrap, e.Operation, e.Identifier)
 	}
 	for _, f := range e.PostFilters {
 		s += fmt.Sprintf("\n%s%s %s", Indent(level), OpPipe, f)
@@ -180,8 +193,8 @@ func (e *LogRangeExpr) Pretty(level int) string {
 	// TODO: this will put [1m] on the same line, not in new line as people used to now.
 	s = fmt.Sprintf("%s [%s]", s, model.Duration(e.Interval))
 
-	if e.Offset != 0 {
-		oe := OffsetExpr{Offset: e.Offset}
+	if e.Offset != 0 || e.At != nil {
+		oe := OffsetExpr{Offset: e.Offset, At: e.At}
 		s += oe.Pretty(level)
 	}
 
@@ -192,10 +205,17 @@ func (e *LogRangeExpr) Pretty(level int) string {
 // TODO(kavi): why does offset not work in log queries? e.g: `{foo="bar"} offset 1h`? is it bug? or anything else?
 // NOTE: Also offset expression never to be indented. It always goes with its parent expression (usually RangeExpr).
 func (e *OffsetExpr) Pretty(_ int) string {
-	// using `model.Duration` as it can format ignoring zero units.
-	// e.g: time.Duration(2 * Hour) -> "2h0m0s"
-	// but model.Duration(2 * Hour) -> "2h"
-	return fmt.Sprintf(" %s %s", OpOffset, model.Duration(e.Offset))
+	var s string
+	if e.At != nil {
+		s = e.At.String()
+	}
+	if e.Offset != 0 {
+		// using `model.Duration` as it can format ignoring zero units.
+		// e.g: time.Duration(2 * Hour) -> "2h0m0s"
+		// but model.Duration(2 * Hour) -> "2h"
+		s += fmt.Sprintf(" %s %s", OpOffset, model.Duration(e.Offset))
+	}
+	return s
 }
 
 // e.g: count_over_time({foo="bar"}[5m])
@@ -226,6 +246,36 @@ func (e *RangeAggregationExpr) Pretty(level int) string {
 	return s
 }
 
+// e.g: max_over_time(sum(rate({foo="bar"}[1m]))[1h:1m])
+func (e *SubqueryExpr) Pretty(level int) string {
+	s := Indent(level)
+	if !NeedSplit(e) {
+		return s + e.String()
+	}
+
+	s += e.Operation
+
+	s += "(\n"
+
+	if e.Params != nil {
+		s = fmt.Sprintf("%s%s%s,", s, Indent(level+1), fmt.Sprint(*e.Params))
+		s += "\n"
+	}
+
+	if _, ok := e.Left.(*BinOpExpr); ok {
+		// the range applies to the whole binary operation, which isn't
+		// wrapped in parentheses when split over multiple lines.
+		s += Indent(level+1) + "(\n" + e.Left.Pretty(level+2) + "\n" + Indent(level+1) + ")"
+	} else {
+		s += e.Left.Pretty(level + 1)
+	}
+	s += e.rangeString()
+
+	s += "\n" + Indent(level) + ")"
+
+	return s
+}
+
 // e.g:
 // sum(count_over_time({foo="bar"}[5m])) by (container)
 // topk(10, count_over_time({foo="bar"}[5m])) by (container)
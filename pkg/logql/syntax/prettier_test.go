@@ -95,6 +95,20 @@ func TestFormat(t *testing.T) {
   | json first_server="servers[0]",ua="request.headers[\"User-Agent\"]"
   | level="error"`,
 		},
+		{
+			name: "delimitedparserExpr",
+			in:   `{job="access"} | delimited sep="\t" columns="ts, method, path, status" | status>=500`,
+			exp: `{job="access"}
+  | delimited sep="\t" columns="ts,method,path,status"
+  | status>=500`,
+		},
+		{
+			name: "xmlparserExpr",
+			in:   `{job="billing"} | xml status="/Envelope/Body/response/@code", user="/Envelope/Body/response/user" | status!="200"`,
+			exp: `{job="billing"}
+  | xml status="/Envelope/Body/response/@code",user="/Envelope/Body/response/user"
+  | status!="200"`,
+		},
 	}
 
 	for _, c := range cases {
@@ -200,6 +214,70 @@ func TestFormat_LabelReplace(t *testing.T) {
 	}
 }
 
+func TestFormat_Subquery(t *testing.T) {
+	MaxCharsPerLine = 20
+
+	cases := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{
+			name: "subquery",
+			in:   `max_over_time(sum(rate({job="api-server",service="a:c"}|= "err" [5m]))[1h:1m] offset 5m)`,
+			exp: `max_over_time(
+  sum(
+    rate(
+      {job="api-server", service="a:c"}
+        |= "err" [5m]
+    )
+  )[1h:1m] offset 5m0s
+)`,
+		},
+		{
+			name: "subquery_at",
+			in:   `max_over_time(sum(rate({job="api-server"}[5m] @ 1609746000))[1h:1m] @ end() offset 5m)`,
+			exp: `max_over_time(
+  sum(
+    rate(
+      {job="api-server"} [5m] @ 1609746000
+    )
+  )[1h:1m] @ end() offset 5m0s
+)`,
+		},
+		{
+			name: "subquery_binop",
+			in:   `quantile_over_time(0.99, (sum(rate({job="api-server"}|= "err" [5m])) / sum(rate({job="api-server"}[5m])))[1h:])`,
+			exp: `quantile_over_time(
+  0.99,
+  (
+      sum(
+        rate(
+          {job="api-server"}
+            |= "err" [5m]
+        )
+      )
+    /
+      sum(
+        rate(
+          {job="api-server"} [5m]
+        )
+      )
+  )[1h:]
+)`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			expr, err := ParseExpr(c.in)
+			require.NoError(t, err)
+			got := Prettify(expr)
+			assert.Equal(t, c.exp, got)
+		})
+	}
+}
+
 func TestFormat_BinOp(t *testing.T) {
 	MaxCharsPerLine = 20
 
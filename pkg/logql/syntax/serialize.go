@@ -31,6 +31,7 @@ func EncodeJSON(e Expr, w io.Writer) error {
 
 // Field names
 const (
+	At                  = "at"
 	Bin                 = "bin"
 	Binary              = "binary"
 	Bytes               = "bytes"
@@ -68,7 +69,11 @@ const (
 	ReturnBool          = "return_bool"
 	RHS                 = "rhs"
 	Src                 = "src"
+	StartOrEnd          = "start_or_end"
+	StepNanos           = "step_nanos"
 	StringField         = "string"
+	Subquery            = "subquery"
+	TimestampMillis     = "timestamp_millis"
 	NoopField           = "noop"
 	Type                = "type"
 	Unwrap              = "unwrap"
@@ -92,6 +97,8 @@ func DecodeJSON(raw string) (Expr, error) {
 		return decodeVectorAgg(iter)
 	case RangeAgg:
 		return decodeRangeAgg(iter)
+	case Subquery:
+		return decodeSubquery(iter)
 	case Literal:
 		return decodeLiteral(iter)
 	case Vector:
@@ -208,6 +215,46 @@ func (v *JSONSerializer) VisitRangeAggregation(e *RangeAggregationExpr) {
 	v.Flush()
 }
 
+func (v *JSONSerializer) VisitSubquery(e *SubqueryExpr) {
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Subquery)
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Op)
+	v.WriteString(e.Operation)
+
+	if e.Params != nil {
+		v.WriteMore()
+		v.WriteObjectField(Params)
+		v.WriteFloat64(*e.Params)
+	}
+
+	v.WriteMore()
+	v.WriteObjectField(IntervalNanos)
+	v.WriteInt64(int64(e.Range))
+	v.WriteMore()
+	v.WriteObjectField(StepNanos)
+	v.WriteInt64(int64(e.Step))
+	v.WriteMore()
+	v.WriteObjectField(OffsetNanos)
+	v.WriteInt64(int64(e.Offset))
+
+	if e.At != nil {
+		v.WriteMore()
+		v.WriteObjectField(At)
+		encodeAtModifier(v.Stream, e.At)
+	}
+
+	v.WriteMore()
+	v.WriteObjectField(Inner)
+	e.Left.Accept(v)
+
+	v.WriteObjectEnd()
+	v.WriteObjectEnd()
+	v.Flush()
+}
+
 func (v *JSONSerializer) VisitLogRange(e *LogRangeExpr) {
 	v.WriteObjectStart()
 
@@ -217,6 +264,12 @@ func (v *JSONSerializer) VisitLogRange(e *LogRangeExpr) {
 	v.WriteObjectField(OffsetNanos)
 	v.WriteInt64(int64(e.Offset))
 
+	if e.At != nil {
+		v.WriteMore()
+		v.WriteObjectField(At)
+		encodeAtModifier(v.Stream, e.At)
+	}
+
 	// Serialize log selector pipeline as string.
 	v.WriteMore()
 	v.WriteObjectField(LogSelector)
@@ -338,6 +391,7 @@ func (v *JSONSerializer) VisitVariants(e *MultiVariantExpr) {
 // Below are StageExpr visitors that we are skipping since a pipeline is
 // serialized as a string.
 func (*JSONSerializer) VisitDecolorize(*DecolorizeExpr)                         {}
+func (*JSONSerializer) VisitDelimitedParser(*DelimitedParserExpr)               {}
 func (*JSONSerializer) VisitDropLabels(*DropLabelsExpr)                         {}
 func (*JSONSerializer) VisitJSONExpressionParser(*JSONExpressionParserExpr)     {}
 func (*JSONSerializer) VisitKeepLabel(*KeepLabelsExpr)                          {}
@@ -348,6 +402,7 @@ func (*JSONSerializer) VisitLineFilter(*LineFilterExpr)
 func (*JSONSerializer) VisitLineFmt(*LineFmtExpr)                               {}
 func (*JSONSerializer) VisitLogfmtExpressionParser(*LogfmtExpressionParserExpr) {}
 func (*JSONSerializer) VisitLogfmtParser(*LogfmtParserExpr)                     {}
+func (*JSONSerializer) VisitXMLExpressionParser(*XMLExpressionParserExpr)       {}
 
 func encodeGrouping(s *jsoniter.Stream, g *Grouping) {
 	s.WriteObjectStart()
@@ -426,6 +481,34 @@ func decodeUnwrap(iter *jsoniter.Iterator) *UnwrapExpr {
 	return e
 }
 
+func encodeAtModifier(s *jsoniter.Stream, at *AtModifier) {
+	s.WriteObjectStart()
+	s.WriteObjectField(TimestampMillis)
+	s.WriteInt64(at.Timestamp)
+
+	if at.StartOrEnd != "" {
+		s.WriteMore()
+		s.WriteObjectField(StartOrEnd)
+		s.WriteString(at.StartOrEnd)
+	}
+
+	s.WriteObjectEnd()
+}
+
+func decodeAtModifier(iter *jsoniter.Iterator) *AtModifier {
+	at := &AtModifier{}
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case TimestampMillis:
+			at.Timestamp = iter.ReadInt64()
+		case StartOrEnd:
+			at.StartOrEnd = iter.ReadString()
+		}
+	}
+
+	return at
+}
+
 func encodeLabelFilter(s *jsoniter.Stream, filter log.LabelFilterer) {
 	switch concrete := filter.(type) {
 	case *log.BinaryLabelFilter:
@@ -742,6 +825,8 @@ func decodeSample(iter *jsoniter.Iterator) (SampleExpr, error) {
 			expr, err = decodeVectorAgg(iter)
 		case RangeAgg:
 			expr, err = decodeRangeAgg(iter)
+		case Subquery:
+			expr, err = decodeSubquery(iter)
 		case Literal:
 			expr, err = decodeLiteral(iter)
 		case Vector:
@@ -901,6 +986,8 @@ func decodeLogRange(iter *jsoniter.Iterator) (*LogRangeExpr, error) {
 			expr.Interval = time.Duration(iter.ReadInt64())
 		case OffsetNanos:
 			expr.Offset = time.Duration(iter.ReadInt64())
+		case At:
+			expr.At = decodeAtModifier(iter)
 		case Unwrap:
 			expr.Unwrap = decodeUnwrap(iter)
 		}
@@ -909,6 +996,33 @@ func decodeLogRange(iter *jsoniter.Iterator) (*LogRangeExpr, error) {
 	return expr, err
 }
 
+func decodeSubquery(iter *jsoniter.Iterator) (*SubqueryExpr, error) {
+	expr := &SubqueryExpr{}
+	var err error
+
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case Op:
+			expr.Operation = iter.ReadString()
+		case Params:
+			tmp := iter.ReadFloat64()
+			expr.Params = &tmp
+		case IntervalNanos:
+			expr.Range = time.Duration(iter.ReadInt64())
+		case StepNanos:
+			expr.Step = time.Duration(iter.ReadInt64())
+		case OffsetNanos:
+			expr.Offset = time.Duration(iter.ReadInt64())
+		case At:
+			expr.At = decodeAtModifier(iter)
+		case Inner:
+			expr.Left, err = decodeSample(iter)
+		}
+	}
+
+	return expr, err
+}
+
 func decodeLabelReplace(iter *jsoniter.Iterator) (*LabelReplaceExpr, error) {
 	var err error
 	var left SampleExpr
@@ -56,6 +56,18 @@ func TestJSONSerializationRoundTrip(t *testing.T) {
 		"multiple post filters where one is a noop": {
 			query: `rate({app="foo"} | json | unwrap foo | latency >= 250ms or bytes=~".*" [1m])`,
 		},
+		"unwrap label": {
+			query: `approx_count_distinct_over_time({app="foo"} | json | unwrap_label user_id [5m]) by (service)`,
+		},
+		"subquery": {
+			query: `quantile_over_time(0.99, sum by (app) (rate({app="foo"}[1m]))[1h:1m] offset 5m)`,
+		},
+		"at modifier": {
+			query: `count_over_time({app="foo"}[5m] @ 1609746000.5 offset 5m) / count_over_time({app="foo"}[5m] @ end())`,
+		},
+		"subquery with at modifier": {
+			query: `max_over_time(rate({app="foo"}[1m])[1h:1m] @ start())`,
+		},
 		"empty label filter string": {
 			query: `rate({app="foo"} |= "bar" | json | unwrap latency | path!="" [5m])`,
 		},
@@ -43,6 +43,8 @@ import (
   labelExtractionExpressionList []log.LabelExtractionExpr
   unwrapExpr *UnwrapExpr
   offsetExpr *OffsetExpr
+  atModifier *AtModifier
+  subqueryRange subqueryRange
 }
 
 %start root
@@ -51,7 +53,7 @@ import (
 %type <logExpr> logExpr
 %type <metricExpr> metricExpr rangeAggregationExpr vectorAggregationExpr binOpExpr labelReplaceExpr vectorExpr
 %type <variantsExpr> variantsExpr
-%type <stage> pipelineStage logfmtParser labelParser jsonExpressionParser logfmtExpressionParser lineFormatExpr decolorizeExpr labelFormatExpr dropLabelsExpr keepLabelsExpr
+%type <stage> pipelineStage logfmtParser labelParser jsonExpressionParser xmlExpressionParser delimitedParser logfmtExpressionParser lineFormatExpr decolorizeExpr labelFormatExpr dropLabelsExpr keepLabelsExpr
 %type <stages> pipelineExpr
 %type <lineFilterExpr> lineFilter lineFilters orFilter
 %type <op> rangeOp convOp vectorOp filterOp
@@ -70,29 +72,40 @@ import (
 %type <logRangeExpr> logRangeExpr
 %type <literalExpr> literalExpr
 %type <labelExtractionExpression> labelExtractionExpression
-%type <labelExtractionExpressionList> labelExtractionExpressionList
+%type <labelExtractionExpressionList> labelExtractionExpressionList parserParams
 %type <unwrapExpr> unwrapExpr
 %type <offsetExpr> offsetExpr
+%type <atModifier> atModifier
 %type <metricExprs> metricExprs
 
 %token <bytes> BYTES
 %token <str> IDENTIFIER STRING NUMBER FUNCTION_FLAG
 %token <dur> DURATION RANGE
+%token <subqueryRange> SUBQUERY_RANGE
 %token <val> MATCHERS LABELS EQ RE NRE NPA OPEN_BRACE CLOSE_BRACE OPEN_BRACKET CLOSE_BRACKET COMMA DOT PIPE_MATCH PIPE_EXACT PIPE_PATTERN
              OPEN_PARENTHESIS CLOSE_PARENTHESIS BY WITHOUT COUNT_OVER_TIME RATE RATE_COUNTER SUM SORT SORT_DESC AVG
              MAX MIN COUNT STDDEV STDVAR BOTTOMK TOPK APPROX_TOPK
              BYTES_OVER_TIME BYTES_RATE BOOL JSON REGEXP LOGFMT PIPE LINE_FMT LABEL_FMT UNWRAP AVG_OVER_TIME SUM_OVER_TIME MIN_OVER_TIME
              MAX_OVER_TIME STDVAR_OVER_TIME STDDEV_OVER_TIME QUANTILE_OVER_TIME BYTES_CONV DURATION_CONV DURATION_SECONDS_CONV
              FIRST_OVER_TIME LAST_OVER_TIME ABSENT_OVER_TIME VECTOR LABEL_REPLACE UNPACK OFFSET PATTERN IP ON IGNORING GROUP_LEFT GROUP_RIGHT
-             DECOLORIZE DROP KEEP VARIANTS OF
+             DECOLORIZE DROP KEEP VARIANTS OF XML CSV DELIMITED UNWRAP_LABEL APPROX_COUNT_DISTINCT_OVER_TIME AT START END
 
 // Operators are listed with increasing precedence.
+//
+// Rules marked with PREFER_SHIFT are ambiguous with a longer match, which is
+// preferred: e.g. a selector followed by != starts a line filter rather than
+// a comparison, and (selector) followed by a range is a log range rather than
+// a parenthesized log query. CLOSE_PARENTHESIS and COMMA only have a
+// precedence to resolve these ambiguities.
+%nonassoc PREFER_SHIFT
+%right CLOSE_PARENTHESIS
 %left <binOp> OR
 %left <binOp> AND UNLESS
 %left <binOp> CMP_EQ NEQ LT LTE GT GTE
 %left <binOp> ADD SUB
 %left <binOp> MUL DIV MOD
 %right <binOp> POW
+%right COMMA
 
 %%
 
@@ -106,8 +119,8 @@ expr:
     ;
 
 logExpr:
-      selector { $$ = newMatcherExpr($1)}
-    | selector pipelineExpr { $$ = newPipelineExpr(newMatcherExpr($1), $2)}
+      selector %prec PREFER_SHIFT { $$ = newMatcherExpr($1)}
+    | selector pipelineExpr %prec PREFER_SHIFT { $$ = newPipelineExpr(newMatcherExpr($1), $2)}
     | OPEN_PARENTHESIS logExpr CLOSE_PARENTHESIS { $$ = $2 }
     ;
 
@@ -157,6 +170,7 @@ logRangeExpr:
 unwrapExpr:
     PIPE UNWRAP IDENTIFIER                                                   { $$ = newUnwrapExpr($3, "")}
   | PIPE UNWRAP convOp OPEN_PARENTHESIS IDENTIFIER CLOSE_PARENTHESIS         { $$ = newUnwrapExpr($5, $3)}
+  | PIPE UNWRAP_LABEL IDENTIFIER                                             { $$ = newUnwrapExpr($3, OpUnwrapLabel)}
   | unwrapExpr PIPE labelFilter                                              { $$ = $1.addPostFilter($3) }
   ;
 
@@ -171,6 +185,10 @@ rangeAggregationExpr:
     | rangeOp OPEN_PARENTHESIS NUMBER COMMA logRangeExpr CLOSE_PARENTHESIS           { $$ = newRangeAggregationExpr($5, $1, nil, &$3) }
     | rangeOp OPEN_PARENTHESIS logRangeExpr CLOSE_PARENTHESIS grouping               { $$ = newRangeAggregationExpr($3, $1, $5, nil) }
     | rangeOp OPEN_PARENTHESIS NUMBER COMMA logRangeExpr CLOSE_PARENTHESIS grouping  { $$ = newRangeAggregationExpr($5, $1, $7, &$3) }
+    | rangeOp OPEN_PARENTHESIS metricExpr SUBQUERY_RANGE CLOSE_PARENTHESIS                          { $$ = newSubqueryExpr($3, $1, $4, nil, nil) }
+    | rangeOp OPEN_PARENTHESIS metricExpr SUBQUERY_RANGE offsetExpr CLOSE_PARENTHESIS               { $$ = newSubqueryExpr($3, $1, $4, $5, nil) }
+    | rangeOp OPEN_PARENTHESIS NUMBER COMMA metricExpr SUBQUERY_RANGE CLOSE_PARENTHESIS             { $$ = newSubqueryExpr($5, $1, $6, nil, &$3) }
+    | rangeOp OPEN_PARENTHESIS NUMBER COMMA metricExpr SUBQUERY_RANGE offsetExpr CLOSE_PARENTHESIS  { $$ = newSubqueryExpr($5, $1, $6, $7, &$3) }
     ;
 
 vectorAggregationExpr:
@@ -217,6 +235,8 @@ pipelineStage:
   | PIPE logfmtParser            { $$ = $2 }
   | PIPE labelParser             { $$ = $2 }
   | PIPE jsonExpressionParser    { $$ = $2 }
+  | PIPE xmlExpressionParser     { $$ = $2 }
+  | PIPE delimitedParser         { $$ = $2 }
   | PIPE logfmtExpressionParser  { $$ = $2 }
   | PIPE labelFilter             { $$ = &LabelFilterExpr{LabelFilterer: $2 }}
   | PIPE lineFormatExpr          { $$ = $2 }
@@ -271,11 +291,27 @@ labelParser:
   | REGEXP STRING       { $$ = newLabelParserExpr(OpParserTypeRegexp, $2) }
   | UNPACK              { $$ = newLabelParserExpr(OpParserTypeUnpack, "") }
   | PATTERN STRING      { $$ = newLabelParserExpr(OpParserTypePattern, $2) }
+  | XML                 { $$ = newLabelParserExpr(OpParserTypeXML, "") }
   ;
 
 jsonExpressionParser:
     JSON labelExtractionExpressionList { $$ = newJSONExpressionParser($2) }
 
+xmlExpressionParser:
+    XML labelExtractionExpressionList %prec PREFER_SHIFT { $$ = newXMLExpressionParser($2) }
+
+delimitedParser:
+    CSV                         { $$ = newDelimitedParserExpr(OpParserTypeCSV, nil) }
+  | CSV parserParams            { $$ = newDelimitedParserExpr(OpParserTypeCSV, $2) }
+  | DELIMITED                   { $$ = newDelimitedParserExpr(OpParserTypeDelimited, nil) }
+  | DELIMITED parserParams      { $$ = newDelimitedParserExpr(OpParserTypeDelimited, $2) }
+  ;
+
+parserParams:
+    IDENTIFIER EQ STRING              { $$ = []log.LabelExtractionExpr{log.NewLabelExtractionExpr($1, $3)} }
+  | parserParams IDENTIFIER EQ STRING { $$ = append($1, log.NewLabelExtractionExpr($2, $4)) }
+  ;
+
 logfmtExpressionParser:
     LOGFMT parserFlags labelExtractionExpressionList  { $$ = newLogfmtExpressionParser($3, $2)}
   | LOGFMT labelExtractionExpressionList              { $$ = newLogfmtExpressionParser($2, nil)}
@@ -307,7 +343,7 @@ labelFilter:
     | OPEN_PARENTHESIS labelFilter CLOSE_PARENTHESIS { $$ = $2 }
     | labelFilter labelFilter                        { $$ = log.NewAndLabelFilter($1, $2 ) }
     | labelFilter AND labelFilter                    { $$ = log.NewAndLabelFilter($1, $3 ) }
-    | labelFilter COMMA labelFilter                  { $$ = log.NewAndLabelFilter($1, $3 ) }
+    | labelFilter COMMA labelFilter %prec PREFER_SHIFT { $$ = log.NewAndLabelFilter($1, $3 ) }
     | labelFilter OR labelFilter                     { $$ = log.NewOrLabelFilter($1, $3 ) }
     ;
 
@@ -505,10 +541,21 @@ rangeOp:
     | FIRST_OVER_TIME    { $$ = OpRangeTypeFirst }
     | LAST_OVER_TIME     { $$ = OpRangeTypeLast }
     | ABSENT_OVER_TIME   { $$ = OpRangeTypeAbsent }
+    | APPROX_COUNT_DISTINCT_OVER_TIME { $$ = OpRangeTypeApproxCountDistinct }
     ;
 
 offsetExpr:
-    OFFSET DURATION { $$ = newOffsetExpr( $2 ) }
+      OFFSET DURATION               { $$ = newOffsetExpr( $2, nil ) }
+    | atModifier                    { $$ = newOffsetExpr( 0, $1 ) }
+    | OFFSET DURATION atModifier    { $$ = newOffsetExpr( $2, $3 ) }
+    | atModifier OFFSET DURATION    { $$ = newOffsetExpr( $3, $1 ) }
+    ;
+
+atModifier:
+      AT NUMBER                                         { $$ = mustNewAtModifier( $2 ) }
+    | AT START OPEN_PARENTHESIS CLOSE_PARENTHESIS       { $$ = &AtModifier{ StartOrEnd: OpAtStart } }
+    | AT END OPEN_PARENTHESIS CLOSE_PARENTHESIS         { $$ = &AtModifier{ StartOrEnd: OpAtEnd } }
+    ;
 
 labels:
       IDENTIFIER                 { $$ = []string{ $1 } }
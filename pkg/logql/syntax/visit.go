@@ -17,6 +17,7 @@ type SampleExprVisitor interface {
 	VisitBinOp(*BinOpExpr)
 	VisitVectorAggregation(*VectorAggregationExpr)
 	VisitRangeAggregation(*RangeAggregationExpr)
+	VisitSubquery(*SubqueryExpr)
 	VisitLabelReplace(*LabelReplaceExpr)
 	VisitLiteral(*LiteralExpr)
 	VisitVector(*VectorExpr)
@@ -31,6 +32,7 @@ type LogSelectorExprVisitor interface {
 
 type StageExprVisitor interface {
 	VisitDecolorize(*DecolorizeExpr)
+	VisitDelimitedParser(*DelimitedParserExpr)
 	VisitDropLabels(*DropLabelsExpr)
 	VisitJSONExpressionParser(*JSONExpressionParserExpr)
 	VisitKeepLabel(*KeepLabelsExpr)
@@ -41,6 +43,7 @@ type StageExprVisitor interface {
 	VisitLineFmt(*LineFmtExpr)
 	VisitLogfmtExpressionParser(*LogfmtExpressionParserExpr)
 	VisitLogfmtParser(*LogfmtParserExpr)
+	VisitXMLExpressionParser(*XMLExpressionParserExpr)
 }
 
 type VariantsExprVisitor interface {
@@ -52,6 +55,7 @@ var _ RootVisitor = &DepthFirstTraversal{}
 type DepthFirstTraversal struct {
 	VisitBinOpFn                  func(v RootVisitor, e *BinOpExpr)
 	VisitDecolorizeFn             func(v RootVisitor, e *DecolorizeExpr)
+	VisitDelimitedParserFn        func(v RootVisitor, e *DelimitedParserExpr)
 	VisitDropLabelsFn             func(v RootVisitor, e *DropLabelsExpr)
 	VisitJSONExpressionParserFn   func(v RootVisitor, e *JSONExpressionParserExpr)
 	VisitKeepLabelFn              func(v RootVisitor, e *KeepLabelsExpr)
@@ -68,9 +72,11 @@ type DepthFirstTraversal struct {
 	VisitMatchersFn               func(v RootVisitor, e *MatchersExpr)
 	VisitPipelineFn               func(v RootVisitor, e *PipelineExpr)
 	VisitRangeAggregationFn       func(v RootVisitor, e *RangeAggregationExpr)
+	VisitSubqueryFn               func(v RootVisitor, e *SubqueryExpr)
 	VisitVectorFn                 func(v RootVisitor, e *VectorExpr)
 	VisitVectorAggregationFn      func(v RootVisitor, e *VectorAggregationExpr)
 	VisitVariantsFn               func(v RootVisitor, e *MultiVariantExpr)
+	VisitXMLExpressionParserFn    func(v RootVisitor, e *XMLExpressionParserExpr)
 }
 
 // VisitBinOp implements RootVisitor.
@@ -96,6 +102,16 @@ func (v *DepthFirstTraversal) VisitDecolorize(e *DecolorizeExpr) {
 	}
 }
 
+// VisitDelimitedParser implements RootVisitor.
+func (v *DepthFirstTraversal) VisitDelimitedParser(e *DelimitedParserExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitDelimitedParserFn != nil {
+		v.VisitDelimitedParserFn(v, e)
+	}
+}
+
 // VisitDropLabels implements RootVisitor.
 func (v *DepthFirstTraversal) VisitDropLabels(e *DropLabelsExpr) {
 	if e == nil {
@@ -272,6 +288,18 @@ func (v *DepthFirstTraversal) VisitRangeAggregation(e *RangeAggregationExpr) {
 	}
 }
 
+// VisitSubquery implements RootVisitor.
+func (v *DepthFirstTraversal) VisitSubquery(e *SubqueryExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitSubqueryFn != nil {
+		v.VisitSubqueryFn(v, e)
+	} else {
+		e.Left.Accept(v)
+	}
+}
+
 // VisitVector implements RootVisitor.
 func (v *DepthFirstTraversal) VisitVector(e *VectorExpr) {
 	if e == nil {
@@ -282,6 +310,16 @@ func (v *DepthFirstTraversal) VisitVector(e *VectorExpr) {
 	}
 }
 
+// VisitXMLExpressionParser implements RootVisitor.
+func (v *DepthFirstTraversal) VisitXMLExpressionParser(e *XMLExpressionParserExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitXMLExpressionParserFn != nil {
+		v.VisitXMLExpressionParserFn(v, e)
+	}
+}
+
 // VisitVectorAggregation implements RootVisitor.
 func (v *DepthFirstTraversal) VisitVectorAggregation(e *VectorAggregationExpr) {
 	if e == nil {
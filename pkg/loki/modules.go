@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/NYTimes/gziphandler"
+	"github.com/apache/arrow-go/v18/arrow/flight"
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/dns"
@@ -35,6 +36,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/collectors/version"
 	"github.com/prometheus/common/model"
 	"github.com/thanos-io/objstore"
+	"github.com/twmb/franz-go/pkg/kgo"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 
@@ -52,14 +54,17 @@ import (
 	"github.com/grafana/loki/v3/pkg/compactor/client/grpc"
 	"github.com/grafana/loki/v3/pkg/compactor/deletion"
 	"github.com/grafana/loki/v3/pkg/compactor/generationnumber"
+	dataobjcompactor "github.com/grafana/loki/v3/pkg/dataobj/compactor"
 	"github.com/grafana/loki/v3/pkg/dataobj/consumer"
 	"github.com/grafana/loki/v3/pkg/dataobj/explorer"
 	dataobjindex "github.com/grafana/loki/v3/pkg/dataobj/index"
 	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
 	dataobjquerier "github.com/grafana/loki/v3/pkg/dataobj/querier"
 	"github.com/grafana/loki/v3/pkg/distributor"
+	"github.com/grafana/loki/v3/pkg/engine"
 	"github.com/grafana/loki/v3/pkg/indexgateway"
 	"github.com/grafana/loki/v3/pkg/ingester"
+	kafkaclient "github.com/grafana/loki/v3/pkg/kafka/client"
 	"github.com/grafana/loki/v3/pkg/kafka/partition"
 	"github.com/grafana/loki/v3/pkg/limits"
 	limits_frontend "github.com/grafana/loki/v3/pkg/limits/frontend"
@@ -160,6 +165,7 @@ const (
 	DataObjExplorer          = "dataobj-explorer"
 	DataObjConsumer          = "dataobj-consumer"
 	DataObjIndexBuilder      = "dataobj-index-builder"
+	DataObjCompactor         = "dataobj-compactor"
 	UI                       = "ui"
 	All                      = "all"
 	Read                     = "read"
@@ -549,7 +555,7 @@ func (t *Loki) getQuerierStore() (querier.Store, error) {
 	logger := log.With(util_log.Logger, "component", "dataobj-querier")
 	storeCombiner := querier.NewStoreCombiner([]querier.StoreConfig{
 		{
-			Store: dataobjquerier.NewStore(store, logger, metastore.NewObjectMetastore(store, logger, prometheus.DefaultRegisterer)),
+			Store: dataobjquerier.NewStore(store, logger, metastore.NewObjectMetastore(t.Cfg.DataObj.Metastore, store, logger, prometheus.DefaultRegisterer)),
 			From:  t.Cfg.DataObj.Querier.From.Time,
 		},
 		{
@@ -627,7 +633,7 @@ func (t *Loki) initQuerier() (services.Service, error) {
 		}
 	}
 
-	t.querierAPI = querier.NewQuerierAPI(t.Cfg.Querier, t.Querier, t.Overrides, store, prometheus.DefaultRegisterer, logger)
+	t.querierAPI = querier.NewQuerierAPI(t.Cfg.Querier, t.Cfg.DataObj.Metastore, t.Querier, t.Overrides, store, prometheus.DefaultRegisterer, logger)
 
 	indexStatsHTTPMiddleware := querier.WrapQuerySpanAndTimeout("query.IndexStats", t.Overrides)
 	indexShardsHTTPMiddleware := querier.WrapQuerySpanAndTimeout("query.IndexShards", t.Overrides)
@@ -743,6 +749,18 @@ func (t *Loki) initQuerier() (services.Service, error) {
 	t.Server.HTTP.Path("/loki/api/v1/tail").Methods("GET", "POST").Handler(httpMiddleware.Wrap(http.HandlerFunc(tailQuerier.TailHandler)))
 	t.Server.HTTP.Path("/api/prom/tail").Methods("GET", "POST").Handler(httpMiddleware.Wrap(http.HandlerFunc(tailQuerier.TailHandler)))
 
+	// Like tail requests, streamed Arrow results are not proxied by the frontend, as they are written while the query
+	// is executed. Queriers serve them directly, both over HTTP and as an Arrow Flight service.
+	if t.Cfg.Querier.Engine.EnableV2Engine {
+		t.Server.HTTP.Path(engine.QueryArrowPath).Methods("GET", "POST").Handler(
+			middleware.Merge(
+				httpMiddleware,
+				querier.WrapQuerySpanAndTimeout("query.ArrowQuery", t.Overrides),
+			).Wrap(t.querierAPI.ArrowQueryHandler()),
+		)
+		flight.RegisterFlightServiceServer(t.Server.GRPC, t.querierAPI.FlightServer())
+	}
+
 	internalMiddlewares := []queryrangebase.Middleware{
 		serverutil.RecoveryMiddleware,
 		queryrange.Instrument{Metrics: t.Metrics},
@@ -2235,6 +2253,51 @@ func (t *Loki) initDataObjIndexBuilder() (services.Service, error) {
 	return t.dataObjIndexBuilder, err
 }
 
+func (t *Loki) initDataObjCompactor() (services.Service, error) {
+	store, err := t.createDataObjBucket("dataobj-compactor")
+	if err != nil {
+		return nil, err
+	}
+
+	// Delete requests are read from the delete requests store of the
+	// compactor, which therefore runs in the same process.
+	var deleteRequests dataobjcompactor.DeleteRequestsStore
+	if t.compactor != nil && t.Cfg.CompactorConfig.RetentionEnabled {
+		deleteRequests = t.compactor.DeleteRequestsStore()
+		t.Cfg.DataObj.Compactor.DeleteRequestCancelPeriod = t.Cfg.CompactorConfig.DeleteRequestCancelPeriod
+	} else if t.Cfg.DataObj.Compactor.RetentionEnabled {
+		level.Warn(util_log.Logger).Log("msg", "compactor retention is disabled, delete requests are not applied to data objects")
+	}
+
+	// Compacted objects which aren't indexed yet are announced to the index
+	// builder on the same topic as the objects written by the consumer.
+	var events *kgo.Client
+	if t.Cfg.Ingester.KafkaIngestion.Enabled {
+		eventsKafkaCfg := t.Cfg.KafkaConfig
+		eventsKafkaCfg.Topic = "loki.metastore-events"
+		eventsKafkaCfg.AutoCreateTopicDefaultPartitions = 1
+		events, err = kafkaclient.NewWriterClient("dataobj-compactor", eventsKafkaCfg, 50, util_log.Logger, prometheus.DefaultRegisterer)
+		if err != nil {
+			return nil, fmt.Errorf("creating events producer: %w", err)
+		}
+	}
+
+	level.Info(util_log.Logger).Log("msg", "initializing dataobj compactor")
+	t.dataObjCompactor, err = dataobjcompactor.New(
+		t.Cfg.DataObj.Compactor,
+		t.Cfg.DataObj.Metastore,
+		t.Cfg.DataObj.Index,
+		store,
+		events,
+		t.Overrides,
+		deleteRequests,
+		prometheus.DefaultRegisterer,
+		util_log.Logger,
+	)
+
+	return t.dataObjCompactor, err
+}
+
 func (t *Loki) createDataObjBucket(clientName string) (objstore.Bucket, error) {
 	schema, err := t.Cfg.SchemaConfig.SchemaForTime(model.Now())
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/apache/arrow-go/v18/arrow/flight"
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/httpgrpc"
@@ -25,6 +26,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
 	"github.com/grafana/loki/v3/pkg/engine"
 	"github.com/grafana/loki/v3/pkg/loghttp"
 	"github.com/grafana/loki/v3/pkg/logproto"
@@ -34,6 +36,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
 	querier_limits "github.com/grafana/loki/v3/pkg/querier/limits"
 	"github.com/grafana/loki/v3/pkg/querier/pattern"
+	"github.com/grafana/loki/v3/pkg/querier/plan"
 	"github.com/grafana/loki/v3/pkg/querier/queryrange"
 	index_stats "github.com/grafana/loki/v3/pkg/storage/stores/index/stats"
 	"github.com/grafana/loki/v3/pkg/tracing"
@@ -55,23 +58,89 @@ type QuerierAPI struct {
 	querier  Querier
 	cfg      Config
 	limits   querier_limits.Limits
-	engineV1 logql.Engine // Loki's current query engine
-	engineV2 logql.Engine // Loki's next generation query engine
+	engineV1 logql.Engine        // Loki's current query engine
+	engineV2 *engine.QueryEngine // Loki's next generation query engine
 	logger   log.Logger
 }
 
 // NewQuerierAPI returns an instance of the QuerierAPI.
-func NewQuerierAPI(cfg Config, querier Querier, limits querier_limits.Limits, store objstore.Bucket, reg prometheus.Registerer, logger log.Logger) *QuerierAPI {
+func NewQuerierAPI(cfg Config, metastoreCfg metastore.Config, querier Querier, limits querier_limits.Limits, store objstore.Bucket, reg prometheus.Registerer, logger log.Logger) *QuerierAPI {
 	return &QuerierAPI{
 		cfg:      cfg,
 		limits:   limits,
 		querier:  querier,
 		engineV1: logql.NewEngine(cfg.Engine, querier, limits, logger),
-		engineV2: engine.New(cfg.Engine, store, limits, reg, logger),
+		engineV2: engine.New(cfg.Engine, metastoreCfg, store, limits, reg, logger),
 		logger:   logger,
 	}
 }
 
+// ArrowQueryHandler returns a http.Handler that streams the results of range
+// queries as Arrow IPC. Queries are always executed with the next generation
+// query engine.
+func (q *QuerierAPI) ArrowQueryHandler() http.Handler {
+	return engine.NewStreamHandler(q.engineV2, q.validateStreamParams, q.logger)
+}
+
+// FlightServer returns an Arrow Flight service that streams the results of
+// queries. Queries are always executed with the next generation query engine.
+func (q *QuerierAPI) FlightServer() flight.FlightServer {
+	return engine.NewFlightServer(q.engineV2, q.validateStreamParams, q.logger)
+}
+
+// validateStreamParams applies the query limits of the tenant to queries that
+// are streamed by the querier. These queries do not pass through the query
+// frontend, so the querier checks the entries limit, the stream matchers
+// limit, the max query length and the access to internal streams itself, and
+// clamps the start of the query to the max query lookback.
+func (q *QuerierAPI) validateStreamParams(ctx context.Context, params logql.Params) (logql.Params, error) {
+	expr := params.GetExpression()
+	if err := q.validateMaxEntriesLimits(ctx, expr, params.Limit()); err != nil {
+		return nil, err
+	}
+
+	var req logql.QueryParams
+	queryPlan := &plan.QueryPlan{AST: expr}
+	switch expr.(type) {
+	case syntax.SampleExpr:
+		req = logql.SelectSampleParams{SampleQueryRequest: &logproto.SampleQueryRequest{
+			Start:  params.Start(),
+			End:    params.End(),
+			Shards: params.Shards(),
+			Plan:   queryPlan,
+		}}
+	case syntax.LogSelectorExpr:
+		req = logql.SelectLogParams{QueryRequest: &logproto.QueryRequest{
+			Start:  params.Start(),
+			End:    params.End(),
+			Shards: params.Shards(),
+			Plan:   queryPlan,
+		}}
+	default:
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, "unsupported query type %T", expr)
+	}
+
+	if err := querier_limits.ValidateAggregatedMetricQuery(ctx, req); err != nil {
+		return nil, err
+	}
+	start, end, err := querier_limits.ValidateQueryRequest(ctx, req, q.limits)
+	if err != nil {
+		return nil, err
+	}
+
+	return logql.NewLiteralParams(
+		params.QueryString(),
+		start,
+		end,
+		params.Step(),
+		params.Interval(),
+		params.Direction(),
+		params.Limit(),
+		params.Shards(),
+		params.GetStoreChunks(),
+	)
+}
+
 // RangeQueryHandler is a http.HandlerFunc for range queries and legacy log queries
 func (q *QuerierAPI) RangeQueryHandler(ctx context.Context, req *queryrange.LokiRequest) (logqlmodel.Result, error) {
 	var result logqlmodel.Result
@@ -12,8 +12,10 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
 	"github.com/grafana/loki/v3/pkg/loghttp"
 	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql"
 	"github.com/grafana/loki/v3/pkg/logqlmodel"
 	"github.com/grafana/loki/v3/pkg/util/constants"
 	"github.com/grafana/loki/v3/pkg/validation"
@@ -30,7 +32,7 @@ func TestInstantQueryHandler(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("log selector expression not allowed for instant queries", func(t *testing.T) {
-		api := NewQuerierAPI(mockQuerierConfig(), nil, limits, nil, nil, log.NewNopLogger())
+		api := NewQuerierAPI(mockQuerierConfig(), metastore.Config{}, nil, limits, nil, nil, log.NewNopLogger())
 
 		ctx := user.InjectOrgID(context.Background(), "user")
 		req, err := http.NewRequestWithContext(ctx, "GET", `/api/v1/query`, nil)
@@ -53,6 +55,98 @@ func TestInstantQueryHandler(t *testing.T) {
 	})
 }
 
+func TestValidateStreamParams(t *testing.T) {
+	defaultLimits := defaultLimitsTestConfig()
+	defaultLimits.MaxEntriesLimitPerQuery = 100
+	defaultLimits.MaxQueryLength = model.Duration(time.Hour)
+	defaultLimits.MaxQueryLookback = model.Duration(24 * time.Hour)
+	defaultLimits.MaxStreamsMatchersPerQuery = 2
+	limits, err := validation.NewOverrides(defaultLimits, nil)
+	require.NoError(t, err)
+
+	api := NewQuerierAPI(mockQuerierConfig(), metastore.Config{}, nil, limits, nil, nil, log.NewNopLogger())
+	ctx := user.InjectOrgID(context.Background(), "user")
+	now := time.Now()
+
+	for _, tc := range []struct {
+		name       string
+		query      string
+		start, end time.Time
+		limit      uint32
+		expStart   time.Time
+		expErr     string
+	}{
+		{
+			name:     "valid log query",
+			query:    `{app="foo"}`,
+			start:    now.Add(-time.Hour),
+			end:      now,
+			limit:    100,
+			expStart: now.Add(-time.Hour),
+		},
+		{
+			name:   "entries limit",
+			query:  `{app="foo"}`,
+			start:  now.Add(-time.Hour),
+			end:    now,
+			limit:  101,
+			expErr: "max entries limit per query exceeded",
+		},
+		{
+			name:     "entries limit does not apply to metric queries",
+			query:    `count_over_time({app="foo"}[1m])`,
+			start:    now.Add(-time.Hour),
+			end:      now,
+			limit:    101,
+			expStart: now.Add(-time.Hour),
+		},
+		{
+			name:   "query length",
+			query:  `count_over_time({app="foo"}[1m])`,
+			start:  now.Add(-2 * time.Hour),
+			end:    now,
+			expErr: "the query time range exceeds the limit",
+		},
+		{
+			name:   "stream matchers",
+			query:  `{app="foo", env="prod", cluster="eu"}`,
+			start:  now.Add(-time.Hour),
+			end:    now,
+			expErr: "max streams matchers per query exceeded",
+		},
+		{
+			name:     "start is clamped to max query lookback",
+			query:    `{app="foo"}`,
+			start:    now.Add(-25 * time.Hour),
+			end:      now.Add(-24*time.Hour + 30*time.Minute),
+			expStart: now.Add(-24 * time.Hour),
+		},
+		{
+			name:   "internal streams",
+			query:  fmt.Sprintf(`{%s="foo"}`, constants.AggregatedMetricLabel),
+			start:  now.Add(-time.Hour),
+			end:    now,
+			expErr: "internal streams can only be queried from Logs Drilldown",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			params, err := logql.NewLiteralParams(tc.query, tc.start, tc.end, time.Minute, 0, logproto.BACKWARD, tc.limit, nil, nil)
+			require.NoError(t, err)
+
+			validated, err := api.validateStreamParams(ctx, params)
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.WithinDuration(t, tc.expStart, validated.Start(), time.Second)
+			require.Equal(t, tc.end, validated.End())
+			require.Equal(t, tc.query, validated.QueryString())
+			require.Equal(t, tc.limit, validated.Limit())
+		})
+	}
+}
+
 type slowConnectionSimulator struct {
 	sleepFor   time.Duration
 	deadline   time.Duration
@@ -418,7 +512,7 @@ func setupAPI(t *testing.T, querier *querierMock, enableMetricAggregation bool)
 	limits, err := validation.NewOverrides(defaultLimits, nil)
 	require.NoError(t, err)
 
-	api := NewQuerierAPI(mockQuerierConfig(), querier, limits, nil, nil, log.NewNopLogger())
+	api := NewQuerierAPI(mockQuerierConfig(), metastore.Config{}, querier, limits, nil, nil, log.NewNopLogger())
 	return api
 }
 
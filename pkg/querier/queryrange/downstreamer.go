@@ -119,7 +119,9 @@ func withoutOffset(query logql.DownstreamQuery) (string, time.Time, time.Time) {
 		case *syntax.RangeAggregationExpr:
 			off := rng.Left.Offset
 
-			if off != 0 {
+			// the offset of a range with an @ modifier is relative to the
+			// time of the modifier, not to the query.
+			if off != 0 && rng.Left.At == nil {
 				rng.Left.Offset = 0 // remove offset
 
 				// adjust start and end time
@@ -127,6 +129,10 @@ func withoutOffset(query logql.DownstreamQuery) (string, time.Time, time.Time) {
 				newStart = newStart.Add(-off)
 
 			}
+		case *syntax.SubqueryExpr:
+			// offsets within a subquery are relative to the steps of the
+			// subquery, not to the query.
+			return false
 		}
 		return true
 	})
@@ -961,6 +961,59 @@ func (m *CountMinSketchResponse) GetStatistics() stats.Result {
 	return stats.Result{}
 }
 
+type CountDistinctSketchResponse struct {
+	Response   *github_com_grafana_loki_v3_pkg_logproto.CountDistinctSketchMatrix                                      `protobuf:"bytes,1,opt,name=response,proto3,customtype=github.com/grafana/loki/v3/pkg/logproto.CountDistinctSketchMatrix" json:"response,omitempty"`
+	Headers    []github_com_grafana_loki_v3_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader `protobuf:"bytes,2,rep,name=Headers,proto3,customtype=github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase/definitions.PrometheusResponseHeader" json:"-"`
+	Warnings   []string                                                                                                `protobuf:"bytes,3,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	Statistics stats.Result                                                                                            `protobuf:"bytes,4,opt,name=statistics,proto3" json:"statistics"`
+}
+
+func (m *CountDistinctSketchResponse) Reset()      { *m = CountDistinctSketchResponse{} }
+func (*CountDistinctSketchResponse) ProtoMessage() {}
+func (*CountDistinctSketchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_51b9d53b40d11902, []int{14}
+}
+func (m *CountDistinctSketchResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CountDistinctSketchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CountDistinctSketchResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CountDistinctSketchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CountDistinctSketchResponse.Merge(m, src)
+}
+func (m *CountDistinctSketchResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *CountDistinctSketchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CountDistinctSketchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CountDistinctSketchResponse proto.InternalMessageInfo
+
+func (m *CountDistinctSketchResponse) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
+func (m *CountDistinctSketchResponse) GetStatistics() stats.Result {
+	if m != nil {
+		return m.Statistics
+	}
+	return stats.Result{}
+}
+
 type ShardsResponse struct {
 	Response *github_com_grafana_loki_v3_pkg_logproto.ShardsResponse                                                 `protobuf:"bytes,1,opt,name=response,proto3,customtype=github.com/grafana/loki/v3/pkg/logproto.ShardsResponse" json:"response,omitempty"`
 	Headers  []github_com_grafana_loki_v3_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader `protobuf:"bytes,2,rep,name=Headers,proto3,customtype=github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase/definitions.PrometheusResponseHeader" json:"-"`
@@ -969,7 +1022,7 @@ type ShardsResponse struct {
 func (m *ShardsResponse) Reset()      { *m = ShardsResponse{} }
 func (*ShardsResponse) ProtoMessage() {}
 func (*ShardsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{14}
+	return fileDescriptor_51b9d53b40d11902, []int{15}
 }
 func (m *ShardsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1006,7 +1059,7 @@ type DetectedFieldsResponse struct {
 func (m *DetectedFieldsResponse) Reset()      { *m = DetectedFieldsResponse{} }
 func (*DetectedFieldsResponse) ProtoMessage() {}
 func (*DetectedFieldsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{15}
+	return fileDescriptor_51b9d53b40d11902, []int{16}
 }
 func (m *DetectedFieldsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1043,7 +1096,7 @@ type QueryPatternsResponse struct {
 func (m *QueryPatternsResponse) Reset()      { *m = QueryPatternsResponse{} }
 func (*QueryPatternsResponse) ProtoMessage() {}
 func (*QueryPatternsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{16}
+	return fileDescriptor_51b9d53b40d11902, []int{17}
 }
 func (m *QueryPatternsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1080,7 +1133,7 @@ type DetectedLabelsResponse struct {
 func (m *DetectedLabelsResponse) Reset()      { *m = DetectedLabelsResponse{} }
 func (*DetectedLabelsResponse) ProtoMessage() {}
 func (*DetectedLabelsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{17}
+	return fileDescriptor_51b9d53b40d11902, []int{18}
 }
 func (m *DetectedLabelsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1125,13 +1178,14 @@ type QueryResponse struct {
 	//	*QueryResponse_PatternsResponse
 	//	*QueryResponse_DetectedLabels
 	//	*QueryResponse_CountMinSketches
+	//	*QueryResponse_CountDistinctSketches
 	Response isQueryResponse_Response `protobuf_oneof:"response"`
 }
 
 func (m *QueryResponse) Reset()      { *m = QueryResponse{} }
 func (*QueryResponse) ProtoMessage() {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{18}
+	return fileDescriptor_51b9d53b40d11902, []int{19}
 }
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1206,20 +1260,24 @@ type QueryResponse_DetectedLabels struct {
 type QueryResponse_CountMinSketches struct {
 	CountMinSketches *CountMinSketchResponse `protobuf:"bytes,14,opt,name=countMinSketches,proto3,oneof"`
 }
-
-func (*QueryResponse_Series) isQueryResponse_Response()           {}
-func (*QueryResponse_Labels) isQueryResponse_Response()           {}
-func (*QueryResponse_Stats) isQueryResponse_Response()            {}
-func (*QueryResponse_Prom) isQueryResponse_Response()             {}
-func (*QueryResponse_Streams) isQueryResponse_Response()          {}
-func (*QueryResponse_Volume) isQueryResponse_Response()           {}
-func (*QueryResponse_TopkSketches) isQueryResponse_Response()     {}
-func (*QueryResponse_QuantileSketches) isQueryResponse_Response() {}
-func (*QueryResponse_ShardsResponse) isQueryResponse_Response()   {}
-func (*QueryResponse_DetectedFields) isQueryResponse_Response()   {}
-func (*QueryResponse_PatternsResponse) isQueryResponse_Response() {}
-func (*QueryResponse_DetectedLabels) isQueryResponse_Response()   {}
-func (*QueryResponse_CountMinSketches) isQueryResponse_Response() {}
+type QueryResponse_CountDistinctSketches struct {
+	CountDistinctSketches *CountDistinctSketchResponse `protobuf:"bytes,15,opt,name=countDistinctSketches,proto3,oneof"`
+}
+
+func (*QueryResponse_Series) isQueryResponse_Response()                {}
+func (*QueryResponse_Labels) isQueryResponse_Response()                {}
+func (*QueryResponse_Stats) isQueryResponse_Response()                 {}
+func (*QueryResponse_Prom) isQueryResponse_Response()                  {}
+func (*QueryResponse_Streams) isQueryResponse_Response()               {}
+func (*QueryResponse_Volume) isQueryResponse_Response()                {}
+func (*QueryResponse_TopkSketches) isQueryResponse_Response()          {}
+func (*QueryResponse_QuantileSketches) isQueryResponse_Response()      {}
+func (*QueryResponse_ShardsResponse) isQueryResponse_Response()        {}
+func (*QueryResponse_DetectedFields) isQueryResponse_Response()        {}
+func (*QueryResponse_PatternsResponse) isQueryResponse_Response()      {}
+func (*QueryResponse_DetectedLabels) isQueryResponse_Response()        {}
+func (*QueryResponse_CountMinSketches) isQueryResponse_Response()      {}
+func (*QueryResponse_CountDistinctSketches) isQueryResponse_Response() {}
 
 func (m *QueryResponse) GetResponse() isQueryResponse_Response {
 	if m != nil {
@@ -1326,6 +1384,13 @@ func (m *QueryResponse) GetCountMinSketches() *CountMinSketchResponse {
 	return nil
 }
 
+func (m *QueryResponse) GetCountDistinctSketches() *CountDistinctSketchResponse {
+	if x, ok := m.GetResponse().(*QueryResponse_CountDistinctSketches); ok {
+		return x.CountDistinctSketches
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*QueryResponse) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -1342,6 +1407,7 @@ func (*QueryResponse) XXX_OneofWrappers() []interface{} {
 		(*QueryResponse_PatternsResponse)(nil),
 		(*QueryResponse_DetectedLabels)(nil),
 		(*QueryResponse_CountMinSketches)(nil),
+		(*QueryResponse_CountDistinctSketches)(nil),
 	}
 }
 
@@ -1364,7 +1430,7 @@ type QueryRequest struct {
 func (m *QueryRequest) Reset()      { *m = QueryRequest{} }
 func (*QueryRequest) ProtoMessage() {}
 func (*QueryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_51b9d53b40d11902, []int{19}
+	return fileDescriptor_51b9d53b40d11902, []int{20}
 }
 func (m *QueryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1557,6 +1623,7 @@ func init() {
 	proto.RegisterType((*TopKSketchesResponse)(nil), "queryrange.TopKSketchesResponse")
 	proto.RegisterType((*QuantileSketchResponse)(nil), "queryrange.QuantileSketchResponse")
 	proto.RegisterType((*CountMinSketchResponse)(nil), "queryrange.CountMinSketchResponse")
+	proto.RegisterType((*CountDistinctSketchResponse)(nil), "queryrange.CountDistinctSketchResponse")
 	proto.RegisterType((*ShardsResponse)(nil), "queryrange.ShardsResponse")
 	proto.RegisterType((*DetectedFieldsResponse)(nil), "queryrange.DetectedFieldsResponse")
 	proto.RegisterType((*QueryPatternsResponse)(nil), "queryrange.QueryPatternsResponse")
@@ -1571,132 +1638,136 @@ func init() {
 }
 
 var fileDescriptor_51b9d53b40d11902 = []byte{
-	// 1991 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0xcf, 0x6f, 0x1b, 0xc7,
-	0x15, 0xe6, 0xf2, 0xa7, 0x38, 0x92, 0x68, 0x75, 0xac, 0x2a, 0x5b, 0xc5, 0xe1, 0xb2, 0x04, 0x9a,
-	0xa8, 0x45, 0x4b, 0xc6, 0x54, 0xe2, 0x26, 0xaa, 0x6b, 0xc4, 0x6b, 0xd9, 0x95, 0x5d, 0xbb, 0x71,
-	0x56, 0x42, 0x0e, 0xbd, 0x14, 0x23, 0x72, 0x44, 0x6e, 0x45, 0xee, 0xae, 0x77, 0x86, 0xb2, 0x05,
-	0x14, 0x45, 0xfe, 0x81, 0xa0, 0xb9, 0xf7, 0x5e, 0xf4, 0x56, 0x14, 0x28, 0x7a, 0xe8, 0xa9, 0xc7,
-	0xe4, 0x50, 0xc0, 0xc7, 0x80, 0x40, 0xd9, 0x9a, 0xbe, 0x14, 0x3a, 0x05, 0xe8, 0x3f, 0x50, 0xcc,
-	0x8f, 0x5d, 0xce, 0x70, 0x57, 0x35, 0xe9, 0x16, 0x05, 0x54, 0xf8, 0x42, 0xee, 0xcc, 0xbc, 0x6f,
-	0x76, 0xe6, 0x7b, 0xdf, 0x9b, 0x37, 0x33, 0x0b, 0xde, 0x0a, 0x8e, 0xbb, 0xcd, 0x47, 0x43, 0x1c,
-	0xba, 0x38, 0xe4, 0xff, 0xa7, 0x21, 0xf2, 0xba, 0x58, 0x79, 0x6c, 0x04, 0xa1, 0x4f, 0x7d, 0x08,
-	0xa6, 0x35, 0x9b, 0xad, 0xae, 0x4b, 0x7b, 0xc3, 0xc3, 0x46, 0xdb, 0x1f, 0x34, 0xbb, 0x7e, 0xd7,
-	0x6f, 0x76, 0x7d, 0xbf, 0xdb, 0xc7, 0x28, 0x70, 0x89, 0x7c, 0x6c, 0x86, 0x41, 0xbb, 0x49, 0x28,
-	0xa2, 0x43, 0x22, 0xf0, 0x9b, 0xeb, 0xcc, 0x90, 0x3f, 0x72, 0x88, 0xac, 0xb5, 0xa4, 0x39, 0x2f,
-	0x1d, 0x0e, 0x8f, 0x9a, 0xd4, 0x1d, 0x60, 0x42, 0xd1, 0x20, 0x88, 0x0c, 0xd8, 0xf8, 0xfa, 0x7e,
-	0x57, 0x20, 0x5d, 0xaf, 0x83, 0x9f, 0x74, 0x11, 0xc5, 0x8f, 0xd1, 0xa9, 0x34, 0x78, 0x5d, 0x33,
-	0x88, 0x1e, 0x64, 0xe3, 0xa6, 0xd6, 0x18, 0x20, 0x4a, 0x71, 0xe8, 0xc9, 0xb6, 0x6f, 0x68, 0x6d,
-	0xe4, 0x18, 0xd3, 0x76, 0x4f, 0x36, 0xd5, 0x64, 0xd3, 0xa3, 0xfe, 0xc0, 0xef, 0xe0, 0x3e, 0x9f,
-	0x08, 0x11, 0xbf, 0xd2, 0xe2, 0x32, 0xb3, 0x08, 0x86, 0xa4, 0xc7, 0x7f, 0x64, 0xe5, 0xad, 0x17,
-	0x72, 0x79, 0x88, 0x08, 0x6e, 0x76, 0xf0, 0x91, 0xeb, 0xb9, 0xd4, 0xf5, 0x3d, 0xa2, 0x3e, 0xcb,
-	0x4e, 0xae, 0xcd, 0xd7, 0xc9, 0xac, 0x7f, 0x36, 0xdf, 0x66, 0x38, 0x42, 0xfd, 0x10, 0x75, 0x71,
-	0xb3, 0xdd, 0x1b, 0x7a, 0xc7, 0xcd, 0x36, 0x6a, 0xf7, 0x70, 0x33, 0xc4, 0x64, 0xd8, 0xa7, 0x44,
-	0x14, 0xe8, 0x69, 0x80, 0xe5, 0x9b, 0xea, 0x5f, 0xe4, 0xc1, 0xf2, 0x7d, 0xff, 0xd8, 0x75, 0xf0,
-	0xa3, 0x21, 0x26, 0x14, 0xae, 0x83, 0x02, 0xef, 0xd5, 0x34, 0x6a, 0xc6, 0x56, 0xd9, 0x11, 0x05,
-	0x56, 0xdb, 0x77, 0x07, 0x2e, 0x35, 0xb3, 0x35, 0x63, 0x6b, 0xd5, 0x11, 0x05, 0x08, 0x41, 0x9e,
-	0x50, 0x1c, 0x98, 0xb9, 0x9a, 0xb1, 0x95, 0x73, 0xf8, 0x33, 0xdc, 0x04, 0x4b, 0xae, 0x47, 0x71,
-	0x78, 0x82, 0xfa, 0x66, 0x99, 0xd7, 0xc7, 0x65, 0x78, 0x03, 0x94, 0x08, 0x45, 0x21, 0x3d, 0x20,
-	0x66, 0xbe, 0x66, 0x6c, 0x2d, 0xb7, 0x36, 0x1b, 0xc2, 0xf3, 0x8d, 0xc8, 0xf3, 0x8d, 0x83, 0xc8,
-	0xf3, 0xf6, 0xd2, 0xe7, 0x63, 0x2b, 0xf3, 0xd9, 0xdf, 0x2c, 0xc3, 0x89, 0x40, 0x70, 0x07, 0x14,
-	0xb0, 0xd7, 0x39, 0x20, 0x66, 0x61, 0x01, 0xb4, 0x80, 0xc0, 0xab, 0xa0, 0xdc, 0x71, 0x43, 0xdc,
-	0x66, 0x2c, 0x9b, 0xc5, 0x9a, 0xb1, 0x55, 0x69, 0x5d, 0x6e, 0xc4, 0x42, 0xd9, 0x8d, 0x9a, 0x9c,
-	0xa9, 0x15, 0x9b, 0x5e, 0x80, 0x68, 0xcf, 0x2c, 0x71, 0x26, 0xf8, 0x33, 0xac, 0x83, 0x22, 0xe9,
-	0xa1, 0xb0, 0x43, 0xcc, 0xa5, 0x5a, 0x6e, 0xab, 0x6c, 0x83, 0xb3, 0xb1, 0x25, 0x6b, 0x1c, 0xf9,
-	0x0f, 0x7f, 0x06, 0xf2, 0x41, 0x1f, 0x79, 0x26, 0xe0, 0xa3, 0x5c, 0x6b, 0x28, 0x5e, 0x7a, 0xd8,
-	0x47, 0x9e, 0xfd, 0xfe, 0x68, 0x6c, 0xbd, 0xab, 0x06, 0x4f, 0x88, 0x8e, 0x90, 0x87, 0x9a, 0x7d,
-	0xff, 0xd8, 0x6d, 0x9e, 0x6c, 0x37, 0x55, 0xdf, 0xb3, 0x8e, 0x1a, 0x1f, 0xb1, 0x0e, 0x18, 0xd4,
-	0xe1, 0x1d, 0xc3, 0x7b, 0x60, 0x99, 0xf9, 0x18, 0xdf, 0x62, 0x0e, 0x26, 0xe6, 0x32, 0x7f, 0xcf,
-	0x6b, 0xd3, 0xd9, 0xf0, 0x7a, 0x07, 0x1f, 0xfd, 0x28, 0xf4, 0x87, 0x81, 0x7d, 0xe9, 0x6c, 0x6c,
-	0xa9, 0xf6, 0x8e, 0x5a, 0x80, 0xf7, 0x40, 0x85, 0x89, 0xc2, 0xf5, 0xba, 0x1f, 0x06, 0x5c, 0x81,
-	0xe6, 0x0a, 0xef, 0xee, 0x4a, 0x43, 0x95, 0x4c, 0xe3, 0x96, 0x66, 0x63, 0xe7, 0x19, 0xbd, 0xce,
-	0x0c, 0xb2, 0x3e, 0xc9, 0x01, 0xc8, 0xb4, 0x74, 0xd7, 0x23, 0x14, 0x79, 0xf4, 0x65, 0x24, 0x75,
-	0x1d, 0x14, 0x59, 0xf0, 0x1f, 0x10, 0x2e, 0xaa, 0x79, 0x7d, 0x2c, 0x31, 0xba, 0x93, 0xf3, 0x0b,
-	0x39, 0xb9, 0x90, 0xea, 0xe4, 0xe2, 0x0b, 0x9d, 0x5c, 0xfa, 0x1f, 0x39, 0x79, 0xe9, 0xbf, 0xeb,
-	0xe4, 0xf2, 0x4b, 0x3b, 0xd9, 0x04, 0x79, 0x36, 0x4a, 0xb8, 0x06, 0x72, 0x21, 0x7a, 0xcc, 0x7d,
-	0xba, 0xe2, 0xb0, 0xc7, 0xfa, 0x24, 0x0f, 0x56, 0xc4, 0x52, 0x42, 0x02, 0xdf, 0x23, 0x98, 0xf1,
-	0xb8, 0xcf, 0x57, 0x7f, 0xe1, 0x79, 0xc9, 0x23, 0xaf, 0x71, 0x64, 0x0b, 0xfc, 0x00, 0xe4, 0x77,
-	0x11, 0x45, 0x5c, 0x05, 0xcb, 0xad, 0x75, 0x95, 0x47, 0xd6, 0x17, 0x6b, 0xb3, 0x37, 0xd8, 0x40,
-	0xce, 0xc6, 0x56, 0xa5, 0x83, 0x28, 0xfa, 0xae, 0x3f, 0x70, 0x29, 0x1e, 0x04, 0xf4, 0xd4, 0xe1,
-	0x48, 0xf8, 0x2e, 0x28, 0xdf, 0x0e, 0x43, 0x3f, 0x3c, 0x38, 0x0d, 0x30, 0x57, 0x4d, 0xd9, 0x7e,
-	0xed, 0x6c, 0x6c, 0x5d, 0xc6, 0x51, 0xa5, 0x82, 0x98, 0x5a, 0xc2, 0x6f, 0x83, 0x02, 0x2f, 0x70,
-	0x9d, 0x94, 0xed, 0xcb, 0x67, 0x63, 0xeb, 0x12, 0x87, 0x28, 0xe6, 0xc2, 0x42, 0x97, 0x55, 0x61,
-	0x2e, 0x59, 0xc5, 0xea, 0x2e, 0xaa, 0xea, 0x36, 0x41, 0xe9, 0x04, 0x87, 0x84, 0x75, 0x53, 0xe2,
-	0xf5, 0x51, 0x11, 0xde, 0x04, 0x80, 0x11, 0xe3, 0x12, 0xea, 0xb6, 0x23, 0x67, 0xaf, 0x36, 0x44,
-	0xb2, 0x71, 0xb8, 0x8f, 0x6c, 0x28, 0x59, 0x50, 0x0c, 0x1d, 0xe5, 0x19, 0xfe, 0xce, 0x00, 0xa5,
-	0x3d, 0x8c, 0x3a, 0x38, 0x64, 0xee, 0xcd, 0x6d, 0x2d, 0xb7, 0xbe, 0xd5, 0x50, 0x33, 0xcb, 0xc3,
-	0xd0, 0x1f, 0x60, 0xda, 0xc3, 0x43, 0x12, 0x39, 0x48, 0x58, 0xdb, 0xde, 0x68, 0x6c, 0xe1, 0x39,
-	0xa5, 0x3a, 0x57, 0x42, 0x3b, 0xf7, 0x55, 0x67, 0x63, 0xcb, 0xf8, 0x9e, 0x13, 0x8d, 0x12, 0xb6,
-	0xc0, 0xd2, 0x63, 0x14, 0x7a, 0xae, 0xd7, 0x25, 0x26, 0xe0, 0x91, 0xb6, 0x71, 0x36, 0xb6, 0x60,
-	0x54, 0xa7, 0x38, 0x22, 0xb6, 0xab, 0xff, 0xd5, 0x00, 0x5f, 0x63, 0xc2, 0xd8, 0x67, 0xe3, 0x21,
-	0xca, 0x12, 0x33, 0x40, 0xb4, 0xdd, 0x33, 0x0d, 0xd6, 0x8d, 0x23, 0x0a, 0x6a, 0xbe, 0xc9, 0xfe,
-	0x47, 0xf9, 0x26, 0xb7, 0x78, 0xbe, 0x89, 0xd6, 0x95, 0x7c, 0xea, 0xba, 0x52, 0x38, 0x6f, 0x5d,
-	0xa9, 0xff, 0x4a, 0xae, 0xa1, 0xd1, 0xfc, 0x16, 0x08, 0xa5, 0x3b, 0x71, 0x28, 0xe5, 0xf8, 0x68,
-	0x63, 0x85, 0x8a, 0xbe, 0xee, 0x76, 0xb0, 0x47, 0xdd, 0x23, 0x17, 0x87, 0x2f, 0x08, 0x28, 0x45,
-	0xa5, 0x39, 0x5d, 0xa5, 0xaa, 0xc4, 0xf2, 0x17, 0x42, 0x62, 0x7a, 0x5c, 0x15, 0x5e, 0x22, 0xae,
-	0xea, 0xff, 0xcc, 0x82, 0x0d, 0xe6, 0x91, 0xfb, 0xe8, 0x10, 0xf7, 0x7f, 0x82, 0x06, 0x0b, 0x7a,
-	0xe5, 0x4d, 0xc5, 0x2b, 0x65, 0x1b, 0xbe, 0x62, 0x7d, 0x3e, 0xd6, 0x7f, 0x63, 0x80, 0xa5, 0x28,
-	0x01, 0xc0, 0x06, 0x00, 0x02, 0xc6, 0xd7, 0x78, 0xc1, 0x75, 0x85, 0x81, 0xc3, 0xb8, 0xd6, 0x51,
-	0x2c, 0xe0, 0xcf, 0x41, 0x51, 0x94, 0x64, 0x2c, 0x28, 0x69, 0x73, 0x9f, 0x86, 0x18, 0x0d, 0x6e,
-	0x76, 0x50, 0x40, 0x71, 0x68, 0xbf, 0xcf, 0x46, 0x31, 0x1a, 0x5b, 0x6f, 0x9d, 0xc7, 0x52, 0xb4,
-	0xc3, 0x97, 0x38, 0xe6, 0x5f, 0xf1, 0x4e, 0x47, 0xbe, 0xa1, 0xfe, 0xa9, 0x01, 0xd6, 0xd8, 0x40,
-	0x19, 0x35, 0xb1, 0x30, 0x76, 0xc1, 0x52, 0x28, 0x9f, 0xf9, 0x70, 0x97, 0x5b, 0xf5, 0x86, 0x4e,
-	0x6b, 0x0a, 0x95, 0x3c, 0xe1, 0x1a, 0x4e, 0x8c, 0x84, 0xdb, 0x1a, 0x8d, 0xd9, 0x34, 0x1a, 0x45,
-	0x8e, 0x56, 0x89, 0xfb, 0x73, 0x16, 0xc0, 0xbb, 0xec, 0x84, 0xc4, 0xf4, 0x37, 0x95, 0xea, 0x93,
-	0xc4, 0x88, 0xae, 0x4c, 0x49, 0x49, 0xda, 0xdb, 0x37, 0x46, 0x63, 0x6b, 0xe7, 0x05, 0xda, 0xf9,
-	0x37, 0x78, 0x65, 0x16, 0xaa, 0x7c, 0xb3, 0x17, 0x41, 0xbe, 0xf5, 0x3f, 0x64, 0x41, 0xe5, 0x63,
-	0xbf, 0x3f, 0x1c, 0xe0, 0x98, 0xbe, 0x20, 0x41, 0x9f, 0x39, 0xa5, 0x4f, 0xb7, 0xb5, 0x77, 0x46,
-	0x63, 0xeb, 0xda, 0xbc, 0xd4, 0xe9, 0xd8, 0x0b, 0x4d, 0xdb, 0xaf, 0x73, 0x60, 0xfd, 0xc0, 0x0f,
-	0x7e, 0xbc, 0xcf, 0x4f, 0xd1, 0xca, 0x32, 0xd9, 0x4b, 0x90, 0xb7, 0x3e, 0x25, 0x8f, 0x21, 0x1e,
-	0x20, 0x1a, 0xba, 0x4f, 0xec, 0x6b, 0xa3, 0xb1, 0xd5, 0x9a, 0x97, 0xb8, 0x29, 0xee, 0x22, 0x93,
-	0xa6, 0xed, 0x81, 0x72, 0xf3, 0xed, 0x81, 0x66, 0xd6, 0x85, 0xfc, 0x7c, 0xeb, 0xc2, 0xef, 0x73,
-	0x60, 0xe3, 0xa3, 0x21, 0xf2, 0xa8, 0xdb, 0xc7, 0xc2, 0x43, 0xb1, 0x7f, 0x7e, 0x91, 0xf0, 0x4f,
-	0x75, 0xea, 0x1f, 0x1d, 0x23, 0x3d, 0xf5, 0xc1, 0x68, 0x6c, 0x5d, 0x9f, 0xd7, 0x53, 0x69, 0x3d,
-	0xbc, 0xf2, 0xd9, 0xbc, 0x3e, 0xbb, 0xe5, 0x0f, 0x3d, 0xfa, 0xc0, 0xf5, 0x16, 0xf1, 0x99, 0x8e,
-	0xf9, 0x18, 0xb7, 0xa9, 0x1f, 0x2e, 0xe6, 0xb3, 0xb4, 0x1e, 0x5e, 0xf9, 0x6c, 0x1e, 0x9f, 0xfd,
-	0x29, 0x0b, 0x2a, 0xfb, 0x62, 0x4f, 0x1f, 0xb1, 0x75, 0x92, 0xe2, 0x2b, 0xf5, 0x12, 0x33, 0x38,
-	0x6c, 0xe8, 0x88, 0xc5, 0x52, 0x88, 0x8e, 0xbd, 0xd0, 0x29, 0xe4, 0x2f, 0x59, 0xb0, 0xb1, 0x8b,
-	0x29, 0x6e, 0x53, 0xdc, 0xb9, 0xe3, 0xe2, 0xbe, 0x42, 0xe2, 0x27, 0x46, 0x82, 0xc5, 0x9a, 0x72,
-	0x08, 0x4f, 0x05, 0xd9, 0xf6, 0x68, 0x6c, 0xdd, 0x98, 0x97, 0xc7, 0xf4, 0x3e, 0x2e, 0x34, 0x9f,
-	0x5f, 0x64, 0xc1, 0xd7, 0xc5, 0xc5, 0x92, 0xb8, 0xf5, 0x9e, 0xd2, 0xf9, 0xcb, 0x04, 0x9b, 0x96,
-	0xba, 0xe6, 0xa7, 0x40, 0xec, 0x9b, 0xa3, 0xb1, 0xf5, 0xc3, 0xf9, 0x17, 0xfd, 0x94, 0x2e, 0xfe,
-	0x6f, 0xb4, 0xc9, 0xcf, 0x82, 0x8b, 0x6a, 0x53, 0x07, 0xbd, 0x9c, 0x36, 0xf5, 0x3e, 0x2e, 0x34,
-	0x9f, 0x7f, 0x2c, 0x81, 0x55, 0xae, 0x92, 0x98, 0xc6, 0xef, 0x00, 0x79, 0x78, 0x96, 0x1c, 0xc2,
-	0xe8, 0xc2, 0x25, 0x0c, 0xda, 0x8d, 0x7d, 0x79, 0xac, 0x16, 0x16, 0xf0, 0x3d, 0x50, 0x24, 0xfc,
-	0x5a, 0x43, 0x9e, 0x8b, 0xaa, 0xb3, 0x37, 0x87, 0xfa, 0x05, 0xca, 0x5e, 0xc6, 0x91, 0xf6, 0xf0,
-	0x3a, 0x28, 0xf6, 0x39, 0x8b, 0xf2, 0x5a, 0xa7, 0x3e, 0x8b, 0x4c, 0x1e, 0xf4, 0x19, 0x5a, 0x60,
-	0xe0, 0x35, 0x50, 0xe0, 0x09, 0x40, 0xa6, 0x03, 0xed, 0xb5, 0xc9, 0x63, 0xd0, 0x5e, 0xc6, 0x11,
-	0xe6, 0xb0, 0x05, 0xf2, 0x41, 0xe8, 0x0f, 0xe4, 0x61, 0xf8, 0xca, 0xec, 0x3b, 0xd5, 0xd3, 0xe3,
-	0x5e, 0xc6, 0xe1, 0xb6, 0xf0, 0x1d, 0x50, 0x22, 0xfc, 0xd8, 0x49, 0xf8, 0x35, 0x22, 0x3b, 0x73,
-	0xcc, 0xc0, 0x14, 0x48, 0x64, 0x0a, 0xdf, 0x01, 0xc5, 0x13, 0x7e, 0xa8, 0x90, 0x77, 0xd3, 0x9b,
-	0x2a, 0x48, 0x3f, 0x6e, 0xb0, 0x79, 0x09, 0x5b, 0x78, 0x07, 0xac, 0x50, 0x3f, 0x38, 0x8e, 0xf6,
-	0xee, 0xf2, 0x0a, 0xb2, 0xa6, 0x62, 0xd3, 0xf6, 0xf6, 0x7b, 0x19, 0x47, 0xc3, 0xc1, 0x87, 0x60,
-	0xed, 0x91, 0xb6, 0xdf, 0xc3, 0xd1, 0x65, 0xb3, 0xc6, 0x73, 0xfa, 0x4e, 0x74, 0x2f, 0xe3, 0x24,
-	0xd0, 0x70, 0x17, 0x54, 0x88, 0x96, 0xe1, 0xe4, 0x87, 0x15, 0x6d, 0x5e, 0x7a, 0x0e, 0xdc, 0xcb,
-	0x38, 0x33, 0x18, 0x78, 0x1f, 0x54, 0x3a, 0xda, 0xfa, 0x2e, 0x3f, 0x9b, 0x68, 0xa3, 0x4a, 0xcf,
-	0x00, 0xac, 0x37, 0x1d, 0x0b, 0x3f, 0x04, 0x6b, 0xc1, 0xcc, 0xda, 0x26, 0xbf, 0x9b, 0x7c, 0x53,
-	0x9f, 0x65, 0xca, 0x22, 0xc8, 0x26, 0x39, 0x0b, 0x56, 0x87, 0x27, 0x42, 0xdc, 0x5c, 0x3d, 0x7f,
-	0x78, 0xfa, 0x22, 0xa0, 0x0e, 0x4f, 0xb4, 0x30, 0x27, 0xb4, 0xb5, 0x0d, 0x1c, 0x26, 0x66, 0x25,
-	0xd9, 0x5f, 0xfa, 0xd6, 0x92, 0x8d, 0x6f, 0x16, 0x6d, 0x83, 0xe9, 0x02, 0x57, 0xff, 0xb4, 0x08,
-	0x56, 0x64, 0xe0, 0x8a, 0xdb, 0xd7, 0xef, 0xc7, 0xb1, 0x28, 0xe2, 0xf6, 0x8d, 0xf3, 0x62, 0x91,
-	0x9b, 0x2b, 0xa1, 0xf8, 0x76, 0x1c, 0x8a, 0x22, 0x88, 0x37, 0xa6, 0x8b, 0x26, 0x9f, 0x89, 0x82,
-	0x90, 0xe1, 0xb7, 0x1d, 0x85, 0x9f, 0x88, 0xdd, 0xd7, 0xd3, 0xef, 0x30, 0x22, 0x94, 0x8c, 0xbd,
-	0x1d, 0x50, 0x72, 0xc5, 0x27, 0xa9, 0xb4, 0xa8, 0x4d, 0x7e, 0xb1, 0x62, 0xd1, 0x24, 0x01, 0x70,
-	0x7b, 0x1a, 0x83, 0x05, 0xf9, 0x09, 0x26, 0x11, 0x83, 0x31, 0x28, 0x0a, 0xc1, 0xab, 0x71, 0x08,
-	0x16, 0x67, 0x3f, 0xdb, 0x44, 0x01, 0x18, 0x4f, 0x4c, 0xc6, 0xdf, 0x6d, 0xb0, 0x1a, 0x29, 0x96,
-	0x37, 0xc9, 0x00, 0x7c, 0xe3, 0xbc, 0x8d, 0x62, 0x84, 0xd7, 0x51, 0xf0, 0x6e, 0x42, 0xe6, 0xe5,
-	0xd9, 0xe4, 0x3e, 0x2b, 0xf2, 0xa8, 0xa7, 0x59, 0x8d, 0xdf, 0x03, 0x97, 0xa6, 0x32, 0x15, 0x63,
-	0x02, 0xc9, 0xc3, 0xa1, 0x26, 0xf0, 0xa8, 0xab, 0x59, 0xa0, 0x3a, 0x2c, 0x29, 0xef, 0xe5, 0xf3,
-	0x86, 0x15, 0x89, 0x3b, 0x31, 0x2c, 0xa9, 0xed, 0x3d, 0xb0, 0x34, 0xc0, 0x14, 0x75, 0x10, 0x45,
-	0x66, 0x89, 0x27, 0xba, 0x37, 0x13, 0x21, 0x27, 0xd1, 0x8d, 0x07, 0xd2, 0xf0, 0xb6, 0x47, 0xc3,
-	0x53, 0xb9, 0x57, 0x8f, 0xd1, 0x9b, 0x3f, 0x00, 0xab, 0x9a, 0x01, 0x5c, 0x03, 0xb9, 0x63, 0x1c,
-	0x7d, 0xa6, 0x64, 0x8f, 0x70, 0x1d, 0x14, 0x4e, 0x50, 0x7f, 0x88, 0xb9, 0x3e, 0xcb, 0x8e, 0x28,
-	0xec, 0x64, 0xdf, 0x33, 0xec, 0x32, 0x28, 0x85, 0xe2, 0x2d, 0x76, 0xf7, 0xe9, 0xb3, 0x6a, 0xe6,
-	0xcb, 0x67, 0xd5, 0xcc, 0x57, 0xcf, 0xaa, 0xc6, 0x27, 0x93, 0xaa, 0xf1, 0xdb, 0x49, 0xd5, 0xf8,
-	0x7c, 0x52, 0x35, 0x9e, 0x4e, 0xaa, 0xc6, 0xdf, 0x27, 0x55, 0xe3, 0x1f, 0x93, 0x6a, 0xe6, 0xab,
-	0x49, 0xd5, 0xf8, 0xec, 0x79, 0x35, 0xf3, 0xf4, 0x79, 0x35, 0xf3, 0xe5, 0xf3, 0x6a, 0xe6, 0xa7,
-	0x57, 0x17, 0xce, 0xb9, 0x87, 0x45, 0xce, 0xd4, 0xf6, 0xbf, 0x02, 0x00, 0x00, 0xff, 0xff, 0xaa,
-	0x37, 0x05, 0xb8, 0xaf, 0x21, 0x00, 0x00,
+	// 2049 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0xcd, 0x8f, 0xdb, 0xc6,
+	0x15, 0x17, 0xf5, 0xb5, 0xab, 0xd9, 0x0f, 0x6f, 0xc7, 0x9b, 0x0d, 0xbb, 0x76, 0x44, 0x55, 0x45,
+	0xe3, 0x6d, 0xd1, 0x52, 0xb1, 0x36, 0x71, 0x93, 0x6d, 0x6a, 0xc4, 0xf4, 0xda, 0x95, 0x5d, 0xbb,
+	0x71, 0xb8, 0x8b, 0x1c, 0x7a, 0x09, 0x66, 0xa5, 0x59, 0x89, 0x5d, 0x89, 0xa4, 0xc9, 0xd1, 0xda,
+	0x0b, 0x14, 0x45, 0x4e, 0xbd, 0x05, 0xcd, 0xad, 0x87, 0xde, 0x8b, 0xde, 0x8a, 0x02, 0x3d, 0xf5,
+	0xd4, 0xde, 0x92, 0x43, 0x01, 0x1f, 0x03, 0x01, 0x65, 0x6b, 0xf9, 0x52, 0xec, 0x29, 0x40, 0xff,
+	0x81, 0x62, 0x3e, 0x48, 0xcd, 0x88, 0xdc, 0x5a, 0x72, 0x8b, 0x02, 0x1b, 0xf8, 0x22, 0x91, 0xc3,
+	0xf7, 0x7b, 0x1c, 0xfe, 0x7e, 0xef, 0xbd, 0xf9, 0x02, 0x57, 0xfc, 0xa3, 0x6e, 0xe3, 0xe1, 0x10,
+	0x07, 0x0e, 0x0e, 0xd8, 0xff, 0x49, 0x80, 0xdc, 0x2e, 0x96, 0x2e, 0x4d, 0x3f, 0xf0, 0x88, 0x07,
+	0xc1, 0xa4, 0x65, 0xb3, 0xd9, 0x75, 0x48, 0x6f, 0x78, 0x60, 0xb6, 0xbd, 0x41, 0xa3, 0xeb, 0x75,
+	0xbd, 0x46, 0xd7, 0xf3, 0xba, 0x7d, 0x8c, 0x7c, 0x27, 0x14, 0x97, 0x8d, 0xc0, 0x6f, 0x37, 0x42,
+	0x82, 0xc8, 0x30, 0xe4, 0xf8, 0xcd, 0x75, 0x6a, 0xc8, 0x2e, 0x19, 0x44, 0xb4, 0x1a, 0xc2, 0x9c,
+	0xdd, 0x1d, 0x0c, 0x0f, 0x1b, 0xc4, 0x19, 0xe0, 0x90, 0xa0, 0x81, 0x1f, 0x1b, 0xd0, 0xfe, 0xf5,
+	0xbd, 0x2e, 0x47, 0x3a, 0x6e, 0x07, 0x3f, 0xee, 0x22, 0x82, 0x1f, 0xa1, 0x13, 0x61, 0x70, 0x49,
+	0x31, 0x88, 0x2f, 0xc4, 0xc3, 0x4d, 0xe5, 0xa1, 0x8f, 0x08, 0xc1, 0x81, 0x2b, 0x9e, 0x7d, 0x5d,
+	0x79, 0x16, 0x1e, 0x61, 0xd2, 0xee, 0x89, 0x47, 0x35, 0xf1, 0xe8, 0x61, 0x7f, 0xe0, 0x75, 0x70,
+	0x9f, 0x7d, 0x48, 0xc8, 0x7f, 0x85, 0xc5, 0x45, 0x6a, 0xe1, 0x0f, 0xc3, 0x1e, 0xfb, 0x11, 0x8d,
+	0x37, 0x9f, 0xcb, 0xe5, 0x01, 0x0a, 0x71, 0xa3, 0x83, 0x0f, 0x1d, 0xd7, 0x21, 0x8e, 0xe7, 0x86,
+	0xf2, 0xb5, 0x70, 0x72, 0x6d, 0x36, 0x27, 0xd3, 0xfa, 0x6c, 0xbe, 0x41, 0x71, 0x21, 0xf1, 0x02,
+	0xd4, 0xc5, 0x8d, 0x76, 0x6f, 0xe8, 0x1e, 0x35, 0xda, 0xa8, 0xdd, 0xc3, 0x8d, 0x00, 0x87, 0xc3,
+	0x3e, 0x09, 0xf9, 0x0d, 0x39, 0xf1, 0xb1, 0x78, 0x53, 0xfd, 0xf3, 0x22, 0x58, 0xba, 0xe7, 0x1d,
+	0x39, 0x36, 0x7e, 0x38, 0xc4, 0x21, 0x81, 0xeb, 0xa0, 0xc4, 0xbc, 0xea, 0x5a, 0x4d, 0xdb, 0xaa,
+	0xd8, 0xfc, 0x86, 0xb6, 0xf6, 0x9d, 0x81, 0x43, 0xf4, 0x7c, 0x4d, 0xdb, 0x5a, 0xb1, 0xf9, 0x0d,
+	0x84, 0xa0, 0x18, 0x12, 0xec, 0xeb, 0x85, 0x9a, 0xb6, 0x55, 0xb0, 0xd9, 0x35, 0xdc, 0x04, 0x8b,
+	0x8e, 0x4b, 0x70, 0x70, 0x8c, 0xfa, 0x7a, 0x85, 0xb5, 0x27, 0xf7, 0xf0, 0x3a, 0x58, 0x08, 0x09,
+	0x0a, 0xc8, 0x7e, 0xa8, 0x17, 0x6b, 0xda, 0xd6, 0x52, 0x73, 0xd3, 0xe4, 0xca, 0x9b, 0xb1, 0xf2,
+	0xe6, 0x7e, 0xac, 0xbc, 0xb5, 0xf8, 0x59, 0x64, 0xe4, 0x3e, 0xfd, 0xbb, 0xa1, 0xd9, 0x31, 0x08,
+	0xee, 0x80, 0x12, 0x76, 0x3b, 0xfb, 0xa1, 0x5e, 0x9a, 0x03, 0xcd, 0x21, 0xf0, 0x2a, 0xa8, 0x74,
+	0x9c, 0x00, 0xb7, 0x29, 0xcb, 0x7a, 0xb9, 0xa6, 0x6d, 0xad, 0x36, 0x2f, 0x9a, 0x49, 0xa0, 0xec,
+	0xc6, 0x8f, 0xec, 0x89, 0x15, 0xfd, 0x3c, 0x1f, 0x91, 0x9e, 0xbe, 0xc0, 0x98, 0x60, 0xd7, 0xb0,
+	0x0e, 0xca, 0x61, 0x0f, 0x05, 0x9d, 0x50, 0x5f, 0xac, 0x15, 0xb6, 0x2a, 0x16, 0x38, 0x8d, 0x0c,
+	0xd1, 0x62, 0x8b, 0x7f, 0xf8, 0x11, 0x28, 0xfa, 0x7d, 0xe4, 0xea, 0x80, 0xf5, 0x72, 0xcd, 0x94,
+	0x54, 0x7a, 0xd0, 0x47, 0xae, 0xf5, 0xce, 0x28, 0x32, 0xde, 0x92, 0x93, 0x27, 0x40, 0x87, 0xc8,
+	0x45, 0x8d, 0xbe, 0x77, 0xe4, 0x34, 0x8e, 0xb7, 0x1b, 0xb2, 0xf6, 0xd4, 0x91, 0xf9, 0x01, 0x75,
+	0x40, 0xa1, 0x36, 0x73, 0x0c, 0xef, 0x82, 0x25, 0xaa, 0x31, 0xbe, 0x49, 0x05, 0x0e, 0xf5, 0x25,
+	0xf6, 0x9e, 0x57, 0x27, 0x5f, 0xc3, 0xda, 0x6d, 0x7c, 0xf8, 0xa3, 0xc0, 0x1b, 0xfa, 0xd6, 0x85,
+	0xd3, 0xc8, 0x90, 0xed, 0x6d, 0xf9, 0x06, 0xde, 0x05, 0xab, 0x34, 0x28, 0x1c, 0xb7, 0xfb, 0xbe,
+	0xcf, 0x22, 0x50, 0x5f, 0x66, 0xee, 0x2e, 0x9b, 0x72, 0xc8, 0x98, 0x37, 0x15, 0x1b, 0xab, 0x48,
+	0xe9, 0xb5, 0xa7, 0x90, 0xf5, 0x71, 0x01, 0x40, 0x1a, 0x4b, 0x77, 0xdc, 0x90, 0x20, 0x97, 0xbc,
+	0x48, 0x48, 0xbd, 0x0b, 0xca, 0x34, 0xf9, 0xf7, 0x43, 0xbd, 0x30, 0x87, 0xc6, 0x02, 0xa3, 0x8a,
+	0x5c, 0x9c, 0x4b, 0xe4, 0x52, 0xa6, 0xc8, 0xe5, 0xe7, 0x8a, 0xbc, 0xf0, 0x7f, 0x12, 0x79, 0xf1,
+	0x7f, 0x2b, 0x72, 0xe5, 0x85, 0x45, 0xd6, 0x41, 0x91, 0xf6, 0x12, 0xae, 0x81, 0x42, 0x80, 0x1e,
+	0x31, 0x4d, 0x97, 0x6d, 0x7a, 0x59, 0x1f, 0x17, 0xc1, 0x32, 0x2f, 0x25, 0xa1, 0xef, 0xb9, 0x21,
+	0xa6, 0x3c, 0xee, 0xb1, 0xea, 0xcf, 0x95, 0x17, 0x3c, 0xb2, 0x16, 0x5b, 0x3c, 0x81, 0xef, 0x81,
+	0xe2, 0x2e, 0x22, 0x88, 0x45, 0xc1, 0x52, 0x73, 0x5d, 0xe6, 0x91, 0xfa, 0xa2, 0xcf, 0xac, 0x0d,
+	0xda, 0x91, 0xd3, 0xc8, 0x58, 0xed, 0x20, 0x82, 0xbe, 0xeb, 0x0d, 0x1c, 0x82, 0x07, 0x3e, 0x39,
+	0xb1, 0x19, 0x12, 0xbe, 0x05, 0x2a, 0xb7, 0x82, 0xc0, 0x0b, 0xf6, 0x4f, 0x7c, 0xcc, 0xa2, 0xa6,
+	0x62, 0xbd, 0x7a, 0x1a, 0x19, 0x17, 0x71, 0xdc, 0x28, 0x21, 0x26, 0x96, 0xf0, 0xdb, 0xa0, 0xc4,
+	0x6e, 0x58, 0x9c, 0x54, 0xac, 0x8b, 0xa7, 0x91, 0x71, 0x81, 0x41, 0x24, 0x73, 0x6e, 0xa1, 0x86,
+	0x55, 0x69, 0xa6, 0xb0, 0x4a, 0xa2, 0xbb, 0x2c, 0x47, 0xb7, 0x0e, 0x16, 0x8e, 0x71, 0x10, 0x3a,
+	0x1e, 0x8f, 0x9b, 0x15, 0x3b, 0xbe, 0x85, 0x37, 0x00, 0xa0, 0xc4, 0x38, 0x21, 0x71, 0xda, 0xb1,
+	0xd8, 0x2b, 0x26, 0x1f, 0x6c, 0x6c, 0xa6, 0x91, 0x05, 0x05, 0x0b, 0x92, 0xa1, 0x2d, 0x5d, 0xc3,
+	0xdf, 0x6b, 0x60, 0xa1, 0x85, 0x51, 0x07, 0x07, 0x54, 0xde, 0xc2, 0xd6, 0x52, 0xf3, 0x5b, 0xa6,
+	0x3c, 0xb2, 0x3c, 0x08, 0xbc, 0x01, 0x26, 0x3d, 0x3c, 0x0c, 0x63, 0x81, 0xb8, 0xb5, 0xe5, 0x8e,
+	0x22, 0x03, 0xcf, 0x18, 0xaa, 0x33, 0x0d, 0x68, 0x67, 0xbe, 0xea, 0x34, 0x32, 0xb4, 0xef, 0xd9,
+	0x71, 0x2f, 0x61, 0x13, 0x2c, 0x3e, 0x42, 0x81, 0xeb, 0xb8, 0xdd, 0x50, 0x07, 0x2c, 0xd3, 0x36,
+	0x4e, 0x23, 0x03, 0xc6, 0x6d, 0x92, 0x10, 0x89, 0x5d, 0xfd, 0x6f, 0x1a, 0xf8, 0x1a, 0x0d, 0x8c,
+	0x3d, 0xda, 0x9f, 0x50, 0x2a, 0x31, 0x03, 0x44, 0xda, 0x3d, 0x5d, 0xa3, 0x6e, 0x6c, 0x7e, 0x23,
+	0x8f, 0x37, 0xf9, 0xff, 0x6a, 0xbc, 0x29, 0xcc, 0x3f, 0xde, 0xc4, 0x75, 0xa5, 0x98, 0x59, 0x57,
+	0x4a, 0x67, 0xd5, 0x95, 0xfa, 0xaf, 0x44, 0x0d, 0x8d, 0xbf, 0x6f, 0x8e, 0x54, 0xba, 0x9d, 0xa4,
+	0x52, 0x81, 0xf5, 0x36, 0x89, 0x50, 0xee, 0xeb, 0x4e, 0x07, 0xbb, 0xc4, 0x39, 0x74, 0x70, 0xf0,
+	0x9c, 0x84, 0x92, 0xa2, 0xb4, 0xa0, 0x46, 0xa9, 0x1c, 0x62, 0xc5, 0x73, 0x11, 0x62, 0x6a, 0x5e,
+	0x95, 0x5e, 0x20, 0xaf, 0xea, 0xff, 0xca, 0x83, 0x0d, 0xaa, 0xc8, 0x3d, 0x74, 0x80, 0xfb, 0x3f,
+	0x41, 0x83, 0x39, 0x55, 0x79, 0x5d, 0x52, 0xa5, 0x62, 0xc1, 0x97, 0xac, 0xcf, 0xc6, 0xfa, 0x6f,
+	0x35, 0xb0, 0x18, 0x0f, 0x00, 0xd0, 0x04, 0x80, 0xc3, 0x58, 0x8d, 0xe7, 0x5c, 0xaf, 0x52, 0x70,
+	0x90, 0xb4, 0xda, 0x92, 0x05, 0xfc, 0x19, 0x28, 0xf3, 0x3b, 0x91, 0x0b, 0xd2, 0xb0, 0xb9, 0x47,
+	0x02, 0x8c, 0x06, 0x37, 0x3a, 0xc8, 0x27, 0x38, 0xb0, 0xde, 0xa1, 0xbd, 0x18, 0x45, 0xc6, 0x95,
+	0xb3, 0x58, 0x8a, 0x67, 0xf8, 0x02, 0x47, 0xf5, 0xe5, 0xef, 0xb4, 0xc5, 0x1b, 0xea, 0x9f, 0x68,
+	0x60, 0x8d, 0x76, 0x94, 0x52, 0x93, 0x04, 0xc6, 0x2e, 0x58, 0x0c, 0xc4, 0x35, 0xeb, 0xee, 0x52,
+	0xb3, 0x6e, 0xaa, 0xb4, 0x66, 0x50, 0xc9, 0x06, 0x5c, 0xcd, 0x4e, 0x90, 0x70, 0x5b, 0xa1, 0x31,
+	0x9f, 0x45, 0x23, 0x1f, 0xa3, 0x65, 0xe2, 0xfe, 0x9c, 0x07, 0xf0, 0x0e, 0x5d, 0x21, 0xd1, 0xf8,
+	0x9b, 0x84, 0xea, 0xe3, 0x54, 0x8f, 0x2e, 0x4f, 0x48, 0x49, 0xdb, 0x5b, 0xd7, 0x47, 0x91, 0xb1,
+	0xf3, 0x9c, 0xd8, 0xf9, 0x0f, 0x78, 0xe9, 0x2b, 0xe4, 0xf0, 0xcd, 0x9f, 0x87, 0xf0, 0xad, 0xff,
+	0x31, 0x0f, 0x56, 0x3f, 0xf4, 0xfa, 0xc3, 0x01, 0x4e, 0xe8, 0xf3, 0x53, 0xf4, 0xe9, 0x13, 0xfa,
+	0x54, 0x5b, 0x6b, 0x67, 0x14, 0x19, 0xd7, 0x66, 0xa5, 0x4e, 0xc5, 0x9e, 0x6b, 0xda, 0x7e, 0x53,
+	0x00, 0xeb, 0xfb, 0x9e, 0xff, 0xe3, 0x3d, 0xb6, 0x8a, 0x96, 0xca, 0x64, 0x2f, 0x45, 0xde, 0xfa,
+	0x84, 0x3c, 0x8a, 0xb8, 0x8f, 0x48, 0xe0, 0x3c, 0xb6, 0xae, 0x8d, 0x22, 0xa3, 0x39, 0x2b, 0x71,
+	0x13, 0xdc, 0x79, 0x26, 0x4d, 0x99, 0x03, 0x15, 0x66, 0x9b, 0x03, 0x4d, 0xd5, 0x85, 0xe2, 0x6c,
+	0x75, 0xe1, 0x0f, 0x05, 0xb0, 0xf1, 0xc1, 0x10, 0xb9, 0xc4, 0xe9, 0x63, 0xae, 0x50, 0xa2, 0xcf,
+	0xcf, 0x53, 0xfa, 0x54, 0x27, 0xfa, 0xa8, 0x18, 0xa1, 0xd4, 0x7b, 0xa3, 0xc8, 0x78, 0x77, 0x56,
+	0xa5, 0xb2, 0x3c, 0xbc, 0xd4, 0x6c, 0x56, 0xcd, 0x6e, 0x7a, 0x43, 0x97, 0xdc, 0x77, 0xdc, 0x79,
+	0x34, 0x53, 0x31, 0x1f, 0xe2, 0x36, 0xf1, 0x82, 0xf9, 0x34, 0xcb, 0xf2, 0xf0, 0x52, 0xb3, 0x59,
+	0x34, 0xfb, 0x4b, 0x01, 0x5c, 0x62, 0xec, 0xed, 0xd2, 0x06, 0xb7, 0x4d, 0xa6, 0x84, 0xfb, 0xa5,
+	0x96, 0x52, 0xee, 0x9b, 0x53, 0xca, 0xa9, 0x48, 0x91, 0x72, 0xb7, 0x46, 0x91, 0x71, 0x63, 0x2e,
+	0xf9, 0xb2, 0xdc, 0xbc, 0xd4, 0x70, 0x16, 0x0d, 0xff, 0x94, 0x07, 0xab, 0x7b, 0x7c, 0x5d, 0x16,
+	0xb3, 0x75, 0x9c, 0x91, 0x6f, 0xf2, 0x46, 0xb4, 0x7f, 0x60, 0xaa, 0x88, 0xf9, 0xa6, 0x01, 0x2a,
+	0xf6, 0x5c, 0x4f, 0x03, 0xfe, 0x9a, 0x07, 0x1b, 0xbb, 0x98, 0xe0, 0x36, 0xc1, 0x9d, 0xdb, 0x0e,
+	0xee, 0x4b, 0x24, 0x7e, 0x9c, 0x8e, 0xfd, 0x9a, 0xb4, 0x91, 0x92, 0x09, 0xb2, 0xac, 0x51, 0x64,
+	0x5c, 0x9f, 0x95, 0xc7, 0x6c, 0x1f, 0xe7, 0x9a, 0xcf, 0xcf, 0xf3, 0xe0, 0x15, 0xbe, 0x39, 0xc8,
+	0x4f, 0x2e, 0x26, 0x74, 0xfe, 0x22, 0xc5, 0xa6, 0x21, 0x8f, 0xdb, 0x19, 0x10, 0xeb, 0xc6, 0x28,
+	0x32, 0x7e, 0x38, 0xfb, 0xc0, 0x9d, 0xe1, 0xe2, 0x2b, 0x13, 0x9b, 0x6c, 0x3d, 0x3f, 0x6f, 0x6c,
+	0xaa, 0xa0, 0x17, 0x8b, 0x4d, 0xd5, 0xc7, 0xb9, 0xe6, 0xf3, 0xd7, 0x8b, 0x60, 0x85, 0x45, 0x49,
+	0x42, 0xe3, 0x77, 0x80, 0xd8, 0x00, 0x11, 0x1c, 0xc2, 0x78, 0xd3, 0x2c, 0xf0, 0xdb, 0xe6, 0x9e,
+	0xd8, 0x1a, 0xe1, 0x16, 0xf0, 0x6d, 0x50, 0x0e, 0x69, 0xa7, 0xe2, 0xb5, 0x6d, 0x75, 0x7a, 0xf7,
+	0x57, 0xdd, 0x04, 0x6b, 0xe5, 0x6c, 0x61, 0x4f, 0x8f, 0x09, 0xfa, 0x8c, 0x45, 0xbd, 0x90, 0x5a,
+	0x5d, 0x9b, 0xd9, 0x9b, 0x35, 0x14, 0xcd, 0x31, 0xf0, 0x1a, 0x28, 0xb1, 0x01, 0x40, 0x2f, 0xa6,
+	0x5f, 0x9b, 0x5e, 0xca, 0xb6, 0x72, 0x36, 0x37, 0x87, 0x4d, 0x50, 0xf4, 0x03, 0x6f, 0x20, 0x36,
+	0x34, 0x2e, 0x4f, 0xbf, 0x53, 0xde, 0x01, 0x68, 0xe5, 0x6c, 0x66, 0x0b, 0xdf, 0xa4, 0x7b, 0x90,
+	0x01, 0x46, 0x83, 0x50, 0x2f, 0x8b, 0x75, 0xe3, 0x14, 0x4c, 0x82, 0xc4, 0xa6, 0xf0, 0x4d, 0x50,
+	0x3e, 0x66, 0x0b, 0x43, 0x71, 0xbe, 0xb0, 0x29, 0x83, 0xd4, 0x25, 0x23, 0xfd, 0x2e, 0x6e, 0x0b,
+	0x6f, 0x83, 0x65, 0xe2, 0xf9, 0x47, 0xf1, 0xfa, 0x4b, 0x6c, 0x23, 0xd7, 0x64, 0x6c, 0xd6, 0xfa,
+	0xac, 0x95, 0xb3, 0x15, 0x1c, 0x7c, 0x00, 0xd6, 0x1e, 0x2a, 0x73, 0x76, 0x1c, 0x1f, 0x18, 0x28,
+	0x3c, 0x67, 0xaf, 0x26, 0x5a, 0x39, 0x3b, 0x85, 0x86, 0xbb, 0x60, 0x35, 0x54, 0x46, 0x38, 0x1d,
+	0xa4, 0xbf, 0x4b, 0x1d, 0x03, 0x5b, 0x39, 0x7b, 0x0a, 0x03, 0xef, 0x81, 0xd5, 0x8e, 0x52, 0xdf,
+	0xf5, 0xa5, 0x74, 0xaf, 0xb2, 0x47, 0x00, 0xea, 0x4d, 0xc5, 0xc2, 0xf7, 0xc1, 0x9a, 0x3f, 0x55,
+	0xdb, 0xc4, 0xd9, 0xd7, 0x37, 0xd4, 0xaf, 0xcc, 0x28, 0x82, 0xf4, 0x23, 0xa7, 0xc1, 0x72, 0xf7,
+	0x78, 0x8a, 0xeb, 0x2b, 0x67, 0x77, 0x4f, 0x2d, 0x02, 0x72, 0xf7, 0xf8, 0x13, 0x2a, 0x42, 0x5b,
+	0x99, 0x84, 0xe3, 0x50, 0x5f, 0x4d, 0xfb, 0xcb, 0x5e, 0x1e, 0xd0, 0xfe, 0x4d, 0xa3, 0xe1, 0x47,
+	0xe0, 0x95, 0x76, 0x7a, 0x5e, 0x88, 0x43, 0xfd, 0x02, 0x73, 0x7b, 0x25, 0xe5, 0x36, 0x7b, 0x06,
+	0xdb, 0xca, 0xd9, 0xd9, 0x7e, 0x2c, 0x30, 0xa9, 0xa0, 0xf5, 0x4f, 0xca, 0x60, 0x59, 0x54, 0x06,
+	0xbe, 0x45, 0xff, 0xfd, 0x24, 0xd9, 0x79, 0x61, 0x78, 0xed, 0xac, 0x64, 0x67, 0xe6, 0x52, 0xae,
+	0xbf, 0x91, 0xe4, 0x3a, 0xaf, 0x12, 0x1b, 0x93, 0xaa, 0xcc, 0xa8, 0x92, 0x10, 0x22, 0xbf, 0xb7,
+	0xe3, 0xfc, 0xe6, 0xc5, 0xe1, 0x52, 0xf6, 0x46, 0x57, 0x8c, 0x12, 0xc9, 0xbd, 0x03, 0x16, 0x1c,
+	0x7e, 0x6e, 0x99, 0x55, 0x16, 0xd2, 0xc7, 0x9a, 0x34, 0x5d, 0x05, 0x00, 0x6e, 0x4f, 0x92, 0xbc,
+	0x24, 0xce, 0xe9, 0x52, 0x49, 0x9e, 0x80, 0xe2, 0x1c, 0xbf, 0x9a, 0xe4, 0x78, 0x79, 0xfa, 0x6c,
+	0x2f, 0xce, 0xf0, 0xe4, 0xc3, 0x44, 0x82, 0xdf, 0x02, 0x2b, 0x71, 0x4a, 0xb0, 0x47, 0x22, 0xc3,
+	0x5f, 0x3b, 0x6b, 0x26, 0x1a, 0xe3, 0x55, 0x14, 0xbc, 0x93, 0xca, 0xa3, 0xca, 0xf4, 0xec, 0x61,
+	0x3a, 0x8b, 0x62, 0x4f, 0xd3, 0x49, 0x74, 0x17, 0x5c, 0x98, 0xe4, 0x01, 0xef, 0x13, 0x48, 0xef,
+	0x20, 0x28, 0x19, 0x14, 0xbb, 0x9a, 0x06, 0xca, 0xdd, 0x12, 0xf9, 0xb3, 0x74, 0x56, 0xb7, 0xe2,
+	0xec, 0x49, 0x75, 0x4b, 0x24, 0x4f, 0x0b, 0x2c, 0x0e, 0x30, 0x41, 0x74, 0xa3, 0x5d, 0x5f, 0x60,
+	0x23, 0xe9, 0xeb, 0xa9, 0x9c, 0x16, 0x68, 0xf3, 0xbe, 0x30, 0xbc, 0xe5, 0x92, 0xe0, 0x44, 0x2c,
+	0x06, 0x12, 0xf4, 0xe6, 0x0f, 0xc0, 0x8a, 0x62, 0x40, 0xcf, 0x3d, 0x8f, 0x70, 0x7c, 0x96, 0x4d,
+	0x2f, 0xe9, 0xe1, 0xd3, 0x31, 0xea, 0x0f, 0x31, 0x8b, 0xcf, 0x8a, 0xcd, 0x6f, 0x76, 0xf2, 0x6f,
+	0x6b, 0x56, 0x05, 0x2c, 0x04, 0xfc, 0x2d, 0x56, 0xf7, 0xc9, 0xd3, 0x6a, 0xee, 0x8b, 0xa7, 0xd5,
+	0xdc, 0x97, 0x4f, 0xab, 0xda, 0xc7, 0xe3, 0xaa, 0xf6, 0xbb, 0x71, 0x55, 0xfb, 0x6c, 0x5c, 0xd5,
+	0x9e, 0x8c, 0xab, 0xda, 0x3f, 0xc6, 0x55, 0xed, 0x9f, 0xe3, 0x6a, 0xee, 0xcb, 0x71, 0x55, 0xfb,
+	0xf4, 0x59, 0x35, 0xf7, 0xe4, 0x59, 0x35, 0xf7, 0xc5, 0xb3, 0x6a, 0xee, 0xa7, 0x57, 0xe7, 0x1e,
+	0xd4, 0x0f, 0xca, 0x8c, 0xa9, 0xed, 0x7f, 0x0f, 0x00, 0x46, 0x39, 0x6c, 0x83, 0xd4, 0x23, 0x00,
+	0x00,
 }
 
 func (this *LokiRequest) Equal(that interface{}) bool {
@@ -2317,6 +2388,53 @@ func (this *CountMinSketchResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *CountDistinctSketchResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CountDistinctSketchResponse)
+	if !ok {
+		that2, ok := that.(CountDistinctSketchResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if that1.Response == nil {
+		if this.Response != nil {
+			return false
+		}
+	} else if !this.Response.Equal(*that1.Response) {
+		return false
+	}
+	if len(this.Headers) != len(that1.Headers) {
+		return false
+	}
+	for i := range this.Headers {
+		if !this.Headers[i].Equal(that1.Headers[i]) {
+			return false
+		}
+	}
+	if len(this.Warnings) != len(that1.Warnings) {
+		return false
+	}
+	for i := range this.Warnings {
+		if this.Warnings[i] != that1.Warnings[i] {
+			return false
+		}
+	}
+	if !this.Statistics.Equal(&that1.Statistics) {
+		return false
+	}
+	return true
+}
 func (this *ShardsResponse) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -2806,6 +2924,30 @@ func (this *QueryResponse_CountMinSketches) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *QueryResponse_CountDistinctSketches) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*QueryResponse_CountDistinctSketches)
+	if !ok {
+		that2, ok := that.(QueryResponse_CountDistinctSketches)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.CountDistinctSketches.Equal(that1.CountDistinctSketches) {
+		return false
+	}
+	return true
+}
 func (this *QueryRequest) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -3178,9 +3320,9 @@ func (this *LokiSeriesResponse) GoString() string {
 	s = append(s, "&queryrange.LokiSeriesResponse{")
 	s = append(s, "Status: "+fmt.Sprintf("%#v", this.Status)+",\n")
 	if this.Data != nil {
-		vs := make([]*logproto.SeriesIdentifier, len(this.Data))
+		vs := make([]logproto.SeriesIdentifier, len(this.Data))
 		for i := range vs {
-			vs[i] = &this.Data[i]
+			vs[i] = this.Data[i]
 		}
 		s = append(s, "Data: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
@@ -3289,6 +3431,19 @@ func (this *CountMinSketchResponse) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *CountDistinctSketchResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&queryrange.CountDistinctSketchResponse{")
+	s = append(s, "Response: "+fmt.Sprintf("%#v", this.Response)+",\n")
+	s = append(s, "Headers: "+fmt.Sprintf("%#v", this.Headers)+",\n")
+	s = append(s, "Warnings: "+fmt.Sprintf("%#v", this.Warnings)+",\n")
+	s = append(s, "Statistics: "+strings.Replace(this.Statistics.GoString(), `&`, ``, 1)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func (this *ShardsResponse) GoString() string {
 	if this == nil {
 		return "nil"
@@ -3337,7 +3492,7 @@ func (this *QueryResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 18)
+	s := make([]string, 0, 19)
 	s = append(s, "&queryrange.QueryResponse{")
 	if this.Status != nil {
 		s = append(s, "Status: "+fmt.Sprintf("%#v", this.Status)+",\n")
@@ -3452,6 +3607,14 @@ func (this *QueryResponse_CountMinSketches) GoString() string {
 		`CountMinSketches:` + fmt.Sprintf("%#v", this.CountMinSketches) + `}`}, ", ")
 	return s
 }
+func (this *QueryResponse_CountDistinctSketches) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&queryrange.QueryResponse_CountDistinctSketches{` +
+		`CountDistinctSketches:` + fmt.Sprintf("%#v", this.CountDistinctSketches) + `}`}, ", ")
+	return s
+}
 func (this *QueryRequest) GoString() string {
 	if this == nil {
 		return "nil"
@@ -4507,6 +4670,74 @@ func (m *CountMinSketchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	return len(dAtA) - i, nil
 }
 
+func (m *CountDistinctSketchResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CountDistinctSketchResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CountDistinctSketchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.Statistics.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintQueryrange(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x22
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Warnings[iNdEx])
+			copy(dAtA[i:], m.Warnings[iNdEx])
+			i = encodeVarintQueryrange(dAtA, i, uint64(len(m.Warnings[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Headers) > 0 {
+		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Headers[iNdEx].Size()
+				i -= size
+				if _, err := m.Headers[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintQueryrange(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Response != nil {
+		{
+			size := m.Response.Size()
+			i -= size
+			if _, err := m.Response.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+			i = encodeVarintQueryrange(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *ShardsResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -4748,7 +4979,8 @@ func (m *QueryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 }
 
 func (m *QueryResponse_Series) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryResponse_Series) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -4768,7 +5000,8 @@ func (m *QueryResponse_Series) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 func (m *QueryResponse_Labels) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryResponse_Labels) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -4788,7 +5021,8 @@ func (m *QueryResponse_Labels) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 func (m *QueryResponse_Stats) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryResponse_Stats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -4808,7 +5042,8 @@ func (m *QueryResponse_Stats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 func (m *QueryResponse_Prom) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryResponse_Prom) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -4828,7 +5063,8 @@ func (m *QueryResponse_Prom) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 func (m *QueryResponse_Streams) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryResponse_Streams) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -4848,7 +5084,8 @@ func (m *QueryResponse_Streams) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 func (m *QueryResponse_Volume) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryResponse_Volume) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -4868,7 +5105,8 @@ func (m *QueryResponse_Volume) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 func (m *QueryResponse_TopkSketches) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryResponse_TopkSketches) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -4888,7 +5126,8 @@ func (m *QueryResponse_TopkSketches) MarshalToSizedBuffer(dAtA []byte) (int, err
 	return len(dAtA) - i, nil
 }
 func (m *QueryResponse_QuantileSketches) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryResponse_QuantileSketches) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -4908,7 +5147,8 @@ func (m *QueryResponse_QuantileSketches) MarshalToSizedBuffer(dAtA []byte) (int,
 	return len(dAtA) - i, nil
 }
 func (m *QueryResponse_ShardsResponse) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryResponse_ShardsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -4928,7 +5168,8 @@ func (m *QueryResponse_ShardsResponse) MarshalToSizedBuffer(dAtA []byte) (int, e
 	return len(dAtA) - i, nil
 }
 func (m *QueryResponse_DetectedFields) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryResponse_DetectedFields) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -4948,7 +5189,8 @@ func (m *QueryResponse_DetectedFields) MarshalToSizedBuffer(dAtA []byte) (int, e
 	return len(dAtA) - i, nil
 }
 func (m *QueryResponse_PatternsResponse) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryResponse_PatternsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -4968,7 +5210,8 @@ func (m *QueryResponse_PatternsResponse) MarshalToSizedBuffer(dAtA []byte) (int,
 	return len(dAtA) - i, nil
 }
 func (m *QueryResponse_DetectedLabels) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryResponse_DetectedLabels) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -4988,7 +5231,8 @@ func (m *QueryResponse_DetectedLabels) MarshalToSizedBuffer(dAtA []byte) (int, e
 	return len(dAtA) - i, nil
 }
 func (m *QueryResponse_CountMinSketches) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryResponse_CountMinSketches) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -5007,6 +5251,27 @@ func (m *QueryResponse_CountMinSketches) MarshalToSizedBuffer(dAtA []byte) (int,
 	}
 	return len(dAtA) - i, nil
 }
+func (m *QueryResponse_CountDistinctSketches) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryResponse_CountDistinctSketches) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.CountDistinctSketches != nil {
+		{
+			size, err := m.CountDistinctSketches.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintQueryrange(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x7a
+	}
+	return len(dAtA) - i, nil
+}
 func (m *QueryRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -5059,7 +5324,8 @@ func (m *QueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 }
 
 func (m *QueryRequest_Series) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryRequest_Series) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -5079,7 +5345,8 @@ func (m *QueryRequest_Series) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 func (m *QueryRequest_Labels) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryRequest_Labels) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -5099,7 +5366,8 @@ func (m *QueryRequest_Labels) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 func (m *QueryRequest_Stats) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryRequest_Stats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -5119,7 +5387,8 @@ func (m *QueryRequest_Stats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 func (m *QueryRequest_Instant) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryRequest_Instant) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -5139,7 +5408,8 @@ func (m *QueryRequest_Instant) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 func (m *QueryRequest_Streams) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryRequest_Streams) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -5159,7 +5429,8 @@ func (m *QueryRequest_Streams) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 func (m *QueryRequest_Volume) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryRequest_Volume) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -5179,7 +5450,8 @@ func (m *QueryRequest_Volume) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 func (m *QueryRequest_ShardsRequest) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryRequest_ShardsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -5199,7 +5471,8 @@ func (m *QueryRequest_ShardsRequest) MarshalToSizedBuffer(dAtA []byte) (int, err
 	return len(dAtA) - i, nil
 }
 func (m *QueryRequest_DetectedFields) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryRequest_DetectedFields) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -5219,7 +5492,8 @@ func (m *QueryRequest_DetectedFields) MarshalToSizedBuffer(dAtA []byte) (int, er
 	return len(dAtA) - i, nil
 }
 func (m *QueryRequest_PatternsRequest) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryRequest_PatternsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -5239,7 +5513,8 @@ func (m *QueryRequest_PatternsRequest) MarshalToSizedBuffer(dAtA []byte) (int, e
 	return len(dAtA) - i, nil
 }
 func (m *QueryRequest_DetectedLabels) MarshalTo(dAtA []byte) (int, error) {
-	return m.MarshalToSizedBuffer(dAtA[:m.Size()])
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
 func (m *QueryRequest_DetectedLabels) MarshalToSizedBuffer(dAtA []byte) (int, error) {
@@ -5660,6 +5935,33 @@ func (m *CountMinSketchResponse) Size() (n int) {
 	return n
 }
 
+func (m *CountDistinctSketchResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Response != nil {
+		l = m.Response.Size()
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	if len(m.Headers) > 0 {
+		for _, e := range m.Headers {
+			l = e.Size()
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
+	if len(m.Warnings) > 0 {
+		for _, s := range m.Warnings {
+			l = len(s)
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
+	l = m.Statistics.Size()
+	n += 1 + l + sovQueryrange(uint64(l))
+	return n
+}
+
 func (m *ShardsResponse) Size() (n int) {
 	if m == nil {
 		return 0
@@ -5908,6 +6210,18 @@ func (m *QueryResponse_CountMinSketches) Size() (n int) {
 	}
 	return n
 }
+func (m *QueryResponse_CountDistinctSketches) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.CountDistinctSketches != nil {
+		l = m.CountDistinctSketches.Size()
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	return n
+}
 func (m *QueryRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -6253,6 +6567,19 @@ func (this *CountMinSketchResponse) String() string {
 	}, "")
 	return s
 }
+func (this *CountDistinctSketchResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CountDistinctSketchResponse{`,
+		`Response:` + fmt.Sprintf("%v", this.Response) + `,`,
+		`Headers:` + fmt.Sprintf("%v", this.Headers) + `,`,
+		`Warnings:` + fmt.Sprintf("%v", this.Warnings) + `,`,
+		`Statistics:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Statistics), "Result", "stats.Result", 1), `&`, ``, 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *ShardsResponse) String() string {
 	if this == nil {
 		return "nil"
@@ -6438,6 +6765,16 @@ func (this *QueryResponse_CountMinSketches) String() string {
 	}, "")
 	return s
 }
+func (this *QueryResponse_CountDistinctSketches) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&QueryResponse_CountDistinctSketches{`,
+		`CountDistinctSketches:` + strings.Replace(fmt.Sprintf("%v", this.CountDistinctSketches), "CountDistinctSketchResponse", "CountDistinctSketchResponse", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *QueryRequest) String() string {
 	if this == nil {
 		return "nil"
@@ -6945,10 +7282,7 @@ func (m *LokiRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -7270,10 +7604,7 @@ func (m *LokiInstantRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -7357,10 +7688,7 @@ func (m *Plan) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -7695,10 +8023,7 @@ func (m *LokiResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -7910,10 +8235,7 @@ func (m *LokiSeriesRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -8115,10 +8437,7 @@ func (m *LokiSeriesResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -8318,10 +8637,7 @@ func (m *LokiLabelNamesResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -8437,10 +8753,7 @@ func (m *LokiData) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -8559,10 +8872,7 @@ func (m *LokiPromResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -8682,10 +8992,7 @@ func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -8805,10 +9112,7 @@ func (m *VolumeResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -8993,10 +9297,7 @@ func (m *TopKSketchesResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -9181,10 +9482,7 @@ func (m *QuantileSketchResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -9369,10 +9667,192 @@ func (m *CountMinSketchResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CountDistinctSketchResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQueryrange
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CountDistinctSketchResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CountDistinctSketchResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Response", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Response == nil {
+				m.Response = &github_com_grafana_loki_v3_pkg_logproto.CountDistinctSketchMatrix{}
+			}
+			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Headers = append(m.Headers, github_com_grafana_loki_v3_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader{})
+			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Statistics", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
 				return ErrInvalidLengthQueryrange
 			}
-			if (iNdEx + skippy) < 0 {
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Statistics.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQueryrange(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -9492,10 +9972,7 @@ func (m *ShardsResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -9615,10 +10092,7 @@ func (m *DetectedFieldsResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -9738,10 +10212,7 @@ func (m *QueryPatternsResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -9861,10 +10332,7 @@ func (m *DetectedLabelsResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -10399,16 +10867,48 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 			}
 			m.Response = &QueryResponse_CountMinSketches{v}
 			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CountDistinctSketches", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &CountDistinctSketchResponse{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Response = &QueryResponse_CountDistinctSketches{v}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQueryrange(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -10778,7 +11278,7 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 					if err != nil {
 						return err
 					}
-					if skippy < 0 {
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
 						return ErrInvalidLengthQueryrange
 					}
 					if (iNdEx + skippy) > postIndex {
@@ -10935,10 +11435,7 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthQueryrange
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthQueryrange
 			}
 			if (iNdEx + skippy) > l {
@@ -10956,6 +11453,7 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 func skipQueryrange(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
+	depth := 0
 	for iNdEx < l {
 		var wire uint64
 		for shift := uint(0); ; shift += 7 {
@@ -10987,10 +11485,8 @@ func skipQueryrange(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			return iNdEx, nil
 		case 1:
 			iNdEx += 8
-			return iNdEx, nil
 		case 2:
 			var length int
 			for shift := uint(0); ; shift += 7 {
@@ -11011,55 +11507,30 @@ func skipQueryrange(dAtA []byte) (n int, err error) {
 				return 0, ErrInvalidLengthQueryrange
 			}
 			iNdEx += length
-			if iNdEx < 0 {
-				return 0, ErrInvalidLengthQueryrange
-			}
-			return iNdEx, nil
 		case 3:
-			for {
-				var innerWire uint64
-				var start int = iNdEx
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return 0, ErrIntOverflowQueryrange
-					}
-					if iNdEx >= l {
-						return 0, io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					innerWire |= (uint64(b) & 0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				innerWireType := int(innerWire & 0x7)
-				if innerWireType == 4 {
-					break
-				}
-				next, err := skipQueryrange(dAtA[start:])
-				if err != nil {
-					return 0, err
-				}
-				iNdEx = start + next
-				if iNdEx < 0 {
-					return 0, ErrInvalidLengthQueryrange
-				}
-			}
-			return iNdEx, nil
+			depth++
 		case 4:
-			return iNdEx, nil
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupQueryrange
+			}
+			depth--
 		case 5:
 			iNdEx += 4
-			return iNdEx, nil
 		default:
 			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
 		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthQueryrange
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
 	}
-	panic("unreachable")
+	return 0, io.ErrUnexpectedEOF
 }
 
 var (
-	ErrInvalidLengthQueryrange = fmt.Errorf("proto: negative length found during unmarshaling")
-	ErrIntOverflowQueryrange   = fmt.Errorf("proto: integer overflow")
+	ErrInvalidLengthQueryrange        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowQueryrange          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupQueryrange = fmt.Errorf("proto: unexpected end of group")
 )
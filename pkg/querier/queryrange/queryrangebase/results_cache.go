@@ -5,18 +5,15 @@ import (
 	"flag"
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/user"
-	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
-	"github.com/prometheus/prometheus/promql"
-	"github.com/prometheus/prometheus/promql/parser"
 
 	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/cache"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/cache/resultscache"
 	"github.com/grafana/loki/v3/pkg/util/constants"
@@ -245,8 +242,6 @@ func (s resultsCache) shouldCacheResponse(ctx context.Context, req Request, r Re
 	return true
 }
 
-var errAtModifierAfterEnd = errors.New("at modifier after end")
-
 // isAtModifierCachable returns true if the @ modifier result
 // is safe to cache.
 func (s resultsCache) isAtModifierCachable(r Request, maxCacheTime int64) bool {
@@ -260,7 +255,7 @@ func (s resultsCache) isAtModifierCachable(r Request, maxCacheTime int64) bool {
 	if !strings.Contains(query, "@") {
 		return true
 	}
-	expr, err := parser.ParseExpr(query)
+	expr, err := syntax.ParseExpr(query)
 	if err != nil {
 		// We are being pessimistic in such cases.
 		level.Warn(s.logger).Log("msg", "failed to parse query, considering @ modifier as not cachable", "query", query, "err", err)
@@ -268,34 +263,26 @@ func (s resultsCache) isAtModifierCachable(r Request, maxCacheTime int64) bool {
 	}
 
 	// This resolves the start() and end() used with the @ modifier.
-	expr, err = promql.PreprocessExpr(expr, r.GetStart(), r.GetEnd(), time.Duration(r.GetStep()))
+	expr, err = syntax.ResolveAtModifiers(expr, r.GetStart(), r.GetEnd())
 	if err != nil {
-		level.Warn(s.logger).Log("msg", "failed to preprocess query, considering @ modifier as not cachable", "query", query, "err", err)
+		level.Warn(s.logger).Log("msg", "failed to resolve @ modifiers, considering @ modifier as not cachable", "query", query, "err", err)
 		return false
 	}
 
 	end := r.GetEnd().UnixMilli()
 	atModCachable := true
-	parser.Inspect(expr, func(n parser.Node, _ []parser.Node) error {
-		switch e := n.(type) {
-		case *parser.VectorSelector:
-			if e.Timestamp != nil && (*e.Timestamp > end || *e.Timestamp > maxCacheTime) {
-				atModCachable = false
-				return errAtModifierAfterEnd
-			}
-		case *parser.MatrixSelector:
-			ts := e.VectorSelector.(*parser.VectorSelector).Timestamp
-			if ts != nil && (*ts > end || *ts > maxCacheTime) {
-				atModCachable = false
-				return errAtModifierAfterEnd
-			}
-		case *parser.SubqueryExpr:
-			if e.Timestamp != nil && (*e.Timestamp > end || *e.Timestamp > maxCacheTime) {
-				atModCachable = false
-				return errAtModifierAfterEnd
-			}
+	expr.Walk(func(e syntax.Expr) bool {
+		var at *syntax.AtModifier
+		switch e := e.(type) {
+		case *syntax.LogRangeExpr:
+			at = e.At
+		case *syntax.SubqueryExpr:
+			at = e.At
+		}
+		if at != nil && (at.Timestamp > end || at.Timestamp > maxCacheTime) {
+			atModCachable = false
 		}
-		return nil
+		return atModCachable
 	})
 
 	return atModCachable
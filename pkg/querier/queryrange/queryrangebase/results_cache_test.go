@@ -277,117 +277,123 @@ func TestShouldCache(t *testing.T) {
 			cacheGenNumberToInject: "1",
 			expected:               false,
 		},
-		// @ modifier on vector selectors.
+		// @ modifier on log ranges.
 		{
-			name:     "@ modifier on vector selector, before end, before maxCacheTime",
-			request:  &PrometheusRequest{Query: "metric @ 123", End: time.UnixMilli(125000)},
+			name:     "@ modifier on log range, before end, before maxCacheTime",
+			request:  &PrometheusRequest{Query: `rate({app="foo"}[5m] @ 123)`, End: time.UnixMilli(125000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: true,
 		},
 		{
-			name:     "@ modifier on vector selector, after end, before maxCacheTime",
-			request:  &PrometheusRequest{Query: "metric @ 127", End: time.UnixMilli(125000)},
+			name:     "@ modifier on log range, after end, before maxCacheTime",
+			request:  &PrometheusRequest{Query: `rate({app="foo"}[5m] @ 127)`, End: time.UnixMilli(125000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: false,
 		},
 		{
-			name:     "@ modifier on vector selector, before end, after maxCacheTime",
-			request:  &PrometheusRequest{Query: "metric @ 151", End: time.UnixMilli(200000)},
+			name:     "@ modifier on log range, before end, after maxCacheTime",
+			request:  &PrometheusRequest{Query: `rate({app="foo"}[5m] @ 151)`, End: time.UnixMilli(200000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: false,
 		},
 		{
-			name:     "@ modifier on vector selector, after end, after maxCacheTime",
-			request:  &PrometheusRequest{Query: "metric @ 151", End: time.UnixMilli(125000)},
+			name:     "@ modifier on log range, after end, after maxCacheTime",
+			request:  &PrometheusRequest{Query: `rate({app="foo"}[5m] @ 151)`, End: time.UnixMilli(125000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: false,
 		},
 		{
-			name:     "@ modifier on vector selector with start() before maxCacheTime",
-			request:  &PrometheusRequest{Query: "metric @ start()", Start: time.UnixMilli(100000), End: time.UnixMilli(200000)},
+			name:     "@ modifier on log range with start() before maxCacheTime",
+			request:  &PrometheusRequest{Query: `rate({app="foo"}[5m] @ start())`, Start: time.UnixMilli(100000), End: time.UnixMilli(200000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: true,
 		},
 		{
-			name:     "@ modifier on vector selector with end() after maxCacheTime",
-			request:  &PrometheusRequest{Query: "metric @ end()", Start: time.UnixMilli(100000), End: time.UnixMilli(200000)},
+			name:     "@ modifier on log range with end() after maxCacheTime",
+			request:  &PrometheusRequest{Query: `rate({app="foo"}[5m] @ end())`, Start: time.UnixMilli(100000), End: time.UnixMilli(200000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: false,
 		},
-		// @ modifier on matrix selectors.
+		// @ modifier within vector aggregations.
 		{
-			name:     "@ modifier on matrix selector, before end, before maxCacheTime",
-			request:  &PrometheusRequest{Query: "rate(metric[5m] @ 123)", End: time.UnixMilli(125000)},
+			name:     "@ modifier within vector aggregation, before end, before maxCacheTime",
+			request:  &PrometheusRequest{Query: `sum by (app) (count_over_time({app="foo"} |= "bar" [5m] @ 123))`, End: time.UnixMilli(125000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: true,
 		},
 		{
-			name:     "@ modifier on matrix selector, after end, before maxCacheTime",
-			request:  &PrometheusRequest{Query: "rate(metric[5m] @ 127)", End: time.UnixMilli(125000)},
+			name:     "@ modifier within vector aggregation, after end, before maxCacheTime",
+			request:  &PrometheusRequest{Query: `sum by (app) (count_over_time({app="foo"} |= "bar" [5m] @ 127))`, End: time.UnixMilli(125000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: false,
 		},
 		{
-			name:     "@ modifier on matrix selector, before end, after maxCacheTime",
-			request:  &PrometheusRequest{Query: "rate(metric[5m] @ 151)", End: time.UnixMilli(200000)},
+			name:     "@ modifier within vector aggregation, before end, after maxCacheTime",
+			request:  &PrometheusRequest{Query: `sum by (app) (count_over_time({app="foo"} |= "bar" [5m] @ 151))`, End: time.UnixMilli(200000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: false,
 		},
 		{
-			name:     "@ modifier on matrix selector, after end, after maxCacheTime",
-			request:  &PrometheusRequest{Query: "rate(metric[5m] @ 151)", End: time.UnixMilli(125000)},
+			name:     "@ modifier within vector aggregation, after end, after maxCacheTime",
+			request:  &PrometheusRequest{Query: `sum by (app) (count_over_time({app="foo"} |= "bar" [5m] @ 151))`, End: time.UnixMilli(125000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: false,
 		},
 		{
-			name:     "@ modifier on matrix selector with start() before maxCacheTime",
-			request:  &PrometheusRequest{Query: "rate(metric[5m] @ start())", Start: time.UnixMilli(100000), End: time.UnixMilli(200000)},
+			name:     "@ modifier within vector aggregation with start() before maxCacheTime",
+			request:  &PrometheusRequest{Query: `sum by (app) (count_over_time({app="foo"} |= "bar" [5m] @ start()))`, Start: time.UnixMilli(100000), End: time.UnixMilli(200000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: true,
 		},
 		{
-			name:     "@ modifier on matrix selector with end() after maxCacheTime",
-			request:  &PrometheusRequest{Query: "rate(metric[5m] @ end())", Start: time.UnixMilli(100000), End: time.UnixMilli(200000)},
+			name:     "@ modifier within vector aggregation with end() after maxCacheTime",
+			request:  &PrometheusRequest{Query: `sum by (app) (count_over_time({app="foo"} |= "bar" [5m] @ end()))`, Start: time.UnixMilli(100000), End: time.UnixMilli(200000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: false,
 		},
 		// @ modifier on subqueries.
 		{
 			name:     "@ modifier on subqueries, before end, before maxCacheTime",
-			request:  &PrometheusRequest{Query: "sum_over_time(rate(metric[1m])[10m:1m] @ 123)", End: time.UnixMilli(125000)},
+			request:  &PrometheusRequest{Query: `sum_over_time(rate({app="foo"}[1m])[10m:1m] @ 123)`, End: time.UnixMilli(125000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: true,
 		},
 		{
 			name:     "@ modifier on subqueries, after end, before maxCacheTime",
-			request:  &PrometheusRequest{Query: "sum_over_time(rate(metric[1m])[10m:1m] @ 127)", End: time.UnixMilli(125000)},
+			request:  &PrometheusRequest{Query: `sum_over_time(rate({app="foo"}[1m])[10m:1m] @ 127)`, End: time.UnixMilli(125000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: false,
 		},
 		{
 			name:     "@ modifier on subqueries, before end, after maxCacheTime",
-			request:  &PrometheusRequest{Query: "sum_over_time(rate(metric[1m])[10m:1m] @ 151)", End: time.UnixMilli(200000)},
+			request:  &PrometheusRequest{Query: `sum_over_time(rate({app="foo"}[1m])[10m:1m] @ 151)`, End: time.UnixMilli(200000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: false,
 		},
 		{
 			name:     "@ modifier on subqueries, after end, after maxCacheTime",
-			request:  &PrometheusRequest{Query: "sum_over_time(rate(metric[1m])[10m:1m] @ 151)", End: time.UnixMilli(125000)},
+			request:  &PrometheusRequest{Query: `sum_over_time(rate({app="foo"}[1m])[10m:1m] @ 151)`, End: time.UnixMilli(125000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: false,
 		},
 		{
 			name:     "@ modifier on subqueries with start() before maxCacheTime",
-			request:  &PrometheusRequest{Query: "sum_over_time(rate(metric[1m])[10m:1m] @ start())", Start: time.UnixMilli(100000), End: time.UnixMilli(200000)},
+			request:  &PrometheusRequest{Query: `sum_over_time(rate({app="foo"}[1m])[10m:1m] @ start())`, Start: time.UnixMilli(100000), End: time.UnixMilli(200000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: true,
 		},
 		{
 			name:     "@ modifier on subqueries with end() after maxCacheTime",
-			request:  &PrometheusRequest{Query: "sum_over_time(rate(metric[1m])[10m:1m] @ end())", Start: time.UnixMilli(100000), End: time.UnixMilli(200000)},
+			request:  &PrometheusRequest{Query: `sum_over_time(rate({app="foo"}[1m])[10m:1m] @ end())`, Start: time.UnixMilli(100000), End: time.UnixMilli(200000)},
 			input:    Response(&PrometheusResponse{}),
 			expected: false,
 		},
+		{
+			name:     "@ in a line filter",
+			request:  &PrometheusRequest{Query: `rate({app="foo"} |= "user@example.com" [5m])`, End: time.UnixMilli(125000)},
+			input:    Response(&PrometheusResponse{}),
+			expected: true,
+		},
 	} {
 		{
 			t.Run(tc.name, func(t *testing.T) {
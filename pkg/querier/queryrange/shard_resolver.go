@@ -91,8 +91,9 @@ func getStatsForMatchers(
 	results := make([]*stats.Stats, len(matcherGroups))
 	if err := concurrency.ForEachJob(ctx, len(matcherGroups), parallelism, func(ctx context.Context, i int) error {
 		matchers := syntax.MatchersString(matcherGroups[i].Matchers)
+		from, through := matcherGroups[i].EvaluationRange(start, end)
 		diff := matcherGroups[i].Interval + matcherGroups[i].Offset
-		adjustedFrom := start.Add(-diff)
+		adjustedFrom := from.Add(-diff)
 		if matcherGroups[i].Interval == 0 {
 			// For limited instant queries, when start == end, the queries would return
 			// zero results. Prometheus has a concept of "look back amount of time for instant queries"
@@ -101,7 +102,7 @@ func getStatsForMatchers(
 			adjustedFrom = adjustedFrom.Add(-defaultLookback)
 		}
 
-		adjustedThrough := end.Add(-matcherGroups[i].Offset)
+		adjustedThrough := through.Add(-matcherGroups[i].Offset)
 
 		resp, err := statsHandler.Do(ctx, &logproto.IndexStatsRequest{
 			From:     adjustedFrom,
@@ -239,6 +240,7 @@ func (r *dynamicShardResolver) ShardingRanges(expr syntax.Expr, targetBytesPerSh
 	}
 
 	for _, grp := range grps {
+		from, through := grp.EvaluationRange(r.from, r.through)
 		diff := grp.Interval
 
 		// For instant queries, when start == end,
@@ -250,13 +252,13 @@ func (r *dynamicShardResolver) ShardingRanges(expr syntax.Expr, targetBytesPerSh
 		diff += grp.Offset
 
 		// use the oldest adjustedFrom
-		if r.from.Add(-diff).Before(adjustedFrom) {
-			adjustedFrom = r.from.Add(-diff)
+		if from.Add(-diff).Before(adjustedFrom) {
+			adjustedFrom = from.Add(-diff)
 		}
 
 		// use the latest adjustedThrough
-		if r.through.Add(-grp.Offset).After(adjustedThrough) {
-			adjustedThrough = r.through.Add(-grp.Offset)
+		if through.Add(-grp.Offset).After(adjustedThrough) {
+			adjustedThrough = through.Add(-grp.Offset)
 		}
 	}
 
@@ -16,6 +16,7 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/querier/plan"
 	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
 	"github.com/grafana/loki/v3/pkg/storage/config"
 	"github.com/grafana/loki/v3/pkg/util/constants"
@@ -189,6 +190,13 @@ func (h *splitByInterval) Do(ctx context.Context, r queryrangebase.Request) (que
 		interval = validation.SmallestPositiveNonZeroDurationPerTenant(tenantIDs, h.limits.QuerySplitDuration)
 	}
 
+	if req, ok := r.(*LokiRequest); ok {
+		r, err = resolveAtModifiers(req)
+		if err != nil {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
+		}
+	}
+
 	// skip split by if unset
 	if interval == 0 {
 		return h.next.Do(ctx, r)
@@ -248,6 +256,27 @@ func (h *splitByInterval) Do(ctx context.Context, r queryrangebase.Request) (que
 	return h.merger.MergeResponse(resps...)
 }
 
+// resolveAtModifiers replaces @ start() and @ end() in the query of r by the
+// start and end of r, since they refer to the time range of the whole query and
+// not to the split the query is evaluated for. This also makes the cache key of
+// the results reflect the actual evaluation time of the query.
+func resolveAtModifiers(r *LokiRequest) (*LokiRequest, error) {
+	if r.Plan == nil {
+		return r, nil
+	}
+	expr, err := syntax.ResolveAtModifiers(r.Plan.AST, r.StartTs, r.EndTs)
+	if err != nil {
+		return nil, err
+	}
+	if expr == r.Plan.AST {
+		return r, nil
+	}
+	clone := *r
+	clone.Query = expr.String()
+	clone.Plan = &plan.QueryPlan{AST: expr}
+	return &clone, nil
+}
+
 // maxRangeVectorAndOffsetDurationFromQueryString
 func maxRangeVectorAndOffsetDurationFromQueryString(q string) (time.Duration, time.Duration, error) {
 	parsed, err := syntax.ParseExpr(q)
@@ -266,13 +295,25 @@ func maxRangeVectorAndOffsetDuration(expr syntax.Expr) (time.Duration, time.Dura
 
 	var maxRVDuration, maxOffset time.Duration
 	expr.Walk(func(e syntax.Expr) bool {
-		if r, ok := e.(*syntax.LogRangeExpr); ok {
+		switch r := e.(type) {
+		case *syntax.LogRangeExpr:
 			if r.Interval > maxRVDuration {
 				maxRVDuration = r.Interval
 			}
 			if r.Offset > maxOffset {
 				maxOffset = r.Offset
 			}
+		case *syntax.SubqueryExpr:
+			// the inner expression of a subquery looks back over the range and
+			// offset of the subquery in addition to its own.
+			dur, offset := maxRangeVectorAndOffsetDuration(r.Left)
+			if dur+r.Range > maxRVDuration {
+				maxRVDuration = dur + r.Range
+			}
+			if offset+r.Offset > maxOffset {
+				maxOffset = offset + r.Offset
+			}
+			return false
 		}
 		return true
 	})
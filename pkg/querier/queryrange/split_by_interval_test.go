@@ -1545,6 +1545,60 @@ func Test_splitByInterval_Do(t *testing.T) {
 	}
 }
 
+func Test_splitByInterval_ResolvesAtModifiers(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "1")
+
+	var queries []string
+	var mtx sync.Mutex
+
+	next := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		queries = append(queries, r.GetQuery())
+		require.Equal(t, r.GetQuery(), r.(*LokiRequest).Plan.AST.String())
+
+		return &LokiPromResponse{
+			Response: &queryrangebase.PrometheusResponse{
+				Status: loghttp.QueryStatusSuccess,
+				Data: queryrangebase.PrometheusData{
+					ResultType: loghttp.ResultTypeMatrix,
+				},
+			},
+		}, nil
+	})
+
+	l := WithSplitByLimits(fakeLimits{maxQueryParallelism: 1}, time.Hour)
+	split := SplitByIntervalMiddleware(
+		testSchemas,
+		l,
+		DefaultCodec,
+		newMetricQuerySplitter(l, nil),
+		nilMetrics,
+	).Wrap(next)
+
+	query := `count_over_time({app="foo"}[1m] @ start()) / count_over_time({app="foo"}[1m] @ end() offset 1h)`
+	req := &LokiRequest{
+		StartTs: time.Unix(0, 0),
+		EndTs:   time.Unix(0, (3 * time.Hour).Nanoseconds()),
+		Query:   query,
+		Step:    60000,
+		Path:    "/loki/api/v1/query_range",
+		Plan: &plan.QueryPlan{
+			AST: syntax.MustParseExpr(query),
+		},
+	}
+
+	_, err := split.Do(ctx, req)
+	require.NoError(t, err)
+
+	// all splits are evaluated at the start and the end of the whole query.
+	require.Len(t, queries, 3)
+	for _, q := range queries {
+		require.Equal(t, `(count_over_time({app="foo"}[1m] @ 0) / count_over_time({app="foo"}[1m] @ 10800 offset 1h0m0s))`, q)
+	}
+	require.Equal(t, query, req.Query)
+}
+
 func Test_series_splitByInterval_Do(t *testing.T) {
 	ctx := user.InjectOrgID(context.Background(), "1")
 	next := queryrangebase.HandlerFunc(func(_ context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {